@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/matthewjhunter/herald"
+	"github.com/matthewjhunter/herald/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// applyConfigFile overlays the database path, thresholds, keywords, avoid keywords, models,
+// fetch proxy, processing delay, grouping settings and prompt directory from a herald config.yaml onto cfg. As in the herald CLI, flags set
+// explicitly on the command line win over the file. Keyword weights aren't
+// carried over; EngineConfig takes bare terms.
+func applyConfigFile(cfg *herald.EngineConfig, path string, explicit map[string]bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+	// Start from zero values rather than storage.DefaultConfig so only the
+	// fields present in the file override the flag defaults.
+	var fc storage.Config
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return fmt.Errorf("parse config: %w", err)
+	}
+
+	if !explicit["db"] && fc.Database.Path != "" {
+		cfg.DBPath = fc.Database.Path
+	}
+	if !explicit["threshold"] && fc.Thresholds.InterestScore != 0 {
+		cfg.InterestThreshold = fc.Thresholds.InterestScore
+	}
+	if !explicit["security-threshold"] && fc.Thresholds.SecurityScore != 0 {
+		cfg.SecurityThreshold = fc.Thresholds.SecurityScore
+	}
+	if !explicit["security-model"] && fc.Ollama.SecurityModel != "" {
+		cfg.SecurityModel = fc.Ollama.SecurityModel
+	}
+	if !explicit["curation-model"] && fc.Ollama.CurationModel != "" {
+		cfg.CurationModel = fc.Ollama.CurationModel
+	}
+	if !explicit["curation-model"] && len(fc.Ollama.CurationModels) > 0 {
+		cfg.CurationModels = fc.Ollama.CurationModels
+	}
+	if !explicit["fetch-proxy"] && fc.Fetch.FetchProxy != "" {
+		cfg.FetchProxy = fc.Fetch.FetchProxy
+	}
+	if !explicit["process-delay"] && fc.Ollama.ProcessDelaySeconds > 0 {
+		cfg.ProcessDelay = time.Duration(fc.Ollama.ProcessDelaySeconds) * time.Second
+	}
+	if !explicit["min-group-size"] && fc.Grouping.MinGroupSize > 0 {
+		cfg.MinGroupSize = fc.Grouping.MinGroupSize
+	}
+	if !explicit["group-summary-min-change"] && fc.Grouping.SummaryMinChange > 0 {
+		cfg.SummaryMinChange = fc.Grouping.SummaryMinChange
+	}
+	if !explicit["prompt-dir"] && fc.Prompts.Dir != "" {
+		cfg.PromptDir = fc.Prompts.Dir
+	}
+	if !explicit["keywords"] && len(fc.Preferences.Keywords) > 0 {
+		cfg.Keywords = make([]string, len(fc.Preferences.Keywords))
+		for i, kw := range fc.Preferences.Keywords {
+			cfg.Keywords[i] = kw.Term
+		}
+	}
+	if len(fc.Preferences.AvoidKeywords) > 0 {
+		cfg.AvoidKeywords = fc.Preferences.AvoidKeywords.Terms()
+	}
+	return nil
+}
+
+// reloadOnSIGHUP re-reads the config file at path on every SIGHUP and applies
+// it over the flag-derived base config to the running engine.
+func reloadOnSIGHUP(engine *herald.Engine, base herald.EngineConfig, path string, explicit map[string]bool) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		cfg := base
+		if err := applyConfigFile(&cfg, path, explicit); err != nil {
+			slog.Error("config reload failed", "event", "config_reload_error", "path", path, "err", err)
+			continue
+		}
+		engine.ReloadConfig(cfg)
+	}
+}
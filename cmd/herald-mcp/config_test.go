@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matthewjhunter/herald"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "fetch:\n  fetch_proxy: http://proxy.internal:3128\n" +
+		"grouping:\n  min_group_size: 3\n  summary_min_change: 2\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := herald.EngineConfig{MinGroupSize: 1}
+	if err := applyConfigFile(&cfg, path, map[string]bool{}); err != nil {
+		t.Fatalf("applyConfigFile: %v", err)
+	}
+	if cfg.MinGroupSize != 3 || cfg.SummaryMinChange != 2 {
+		t.Errorf("grouping = %d, %d; want 3, 2", cfg.MinGroupSize, cfg.SummaryMinChange)
+	}
+	if cfg.FetchProxy != "http://proxy.internal:3128" {
+		t.Errorf("FetchProxy = %q", cfg.FetchProxy)
+	}
+
+	// Flags set on the command line win over the file.
+	cfg = herald.EngineConfig{MinGroupSize: 5}
+	if err := applyConfigFile(&cfg, path, map[string]bool{"min-group-size": true}); err != nil {
+		t.Fatalf("applyConfigFile: %v", err)
+	}
+	if cfg.MinGroupSize != 5 {
+		t.Errorf("MinGroupSize = %d, want the flag's 5", cfg.MinGroupSize)
+	}
+}
@@ -7,9 +7,34 @@ type articlesUnreadInput struct {
 	Limit    *int     `json:"limit,omitempty"     jsonschema:"Maximum number of articles to return (default 20)"`
 	Offset   *int     `json:"offset,omitempty"    jsonschema:"Number of articles to skip for pagination (default 0)"`
 	MinScore *float64 `json:"min_score,omitempty" jsonschema:"Minimum interest score filter (0-10). Only returns articles scored at or above this threshold."`
+	Order    *string  `json:"order,omitempty"     jsonschema:"Sort order when min_score is not set: newest (default), oldest, or score"`
 	Speaker  *string  `json:"speaker,omitempty"   jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
 }
 
+type articlesUngroupedInput struct {
+	Limit   *int    `json:"limit,omitempty"   jsonschema:"Maximum number of articles to return (default 20)"`
+	Offset  *int    `json:"offset,omitempty"  jsonschema:"Number of articles to skip for pagination (default 0)"`
+	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type articlesBlockedInput struct {
+	Limit   *int    `json:"limit,omitempty"   jsonschema:"Maximum number of articles to return (default 20)"`
+	Offset  *int    `json:"offset,omitempty"  jsonschema:"Number of articles to skip for pagination (default 0)"`
+	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type articlesReadHistoryInput struct {
+	Limit   *int    `json:"limit,omitempty"   jsonschema:"Maximum number of articles to return (default 20)"`
+	Offset  *int    `json:"offset,omitempty"  jsonschema:"Number of articles to skip for pagination (default 0)"`
+	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type articlesSinceInput struct {
+	Since   *string `json:"since,omitempty"   jsonschema:"RFC3339 timestamp; returns articles fetched after it. If omitted uses the user's last web visit."`
+	Limit   *int    `json:"limit,omitempty"   jsonschema:"Maximum number of articles to return (default 50)"`
+	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
 type articleIDInput struct {
 	ArticleID int64   `json:"article_id"           jsonschema:"The article ID"`
 	Speaker   *string `json:"speaker,omitempty"     jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
@@ -19,6 +44,10 @@ type speakerOnlyInput struct {
 	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
 }
 
+type combinedBriefingInput struct {
+	Speakers []string `json:"speakers" jsonschema:"Names of the users whose high-interest articles are combined"`
+}
+
 type feedSubscribeInput struct {
 	URL     string  `json:"url"                jsonschema:"The RSS/Atom feed URL"`
 	Title   *string `json:"title,omitempty"    jsonschema:"Optional display title for the feed. If omitted the feed's own title is used once fetched."`
@@ -35,24 +64,78 @@ type feedRenameInput struct {
 	Title  string `json:"title"   jsonschema:"The new display title"`
 }
 
+type feedDedupeInput struct {
+	FeedID  int64   `json:"feed_id"           jsonschema:"The feed ID to configure"`
+	ByURL   bool    `json:"by_url"            jsonschema:"true to deduplicate articles by URL instead of GUID; false to restore GUID matching"`
+	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type feedMaxArticlesInput struct {
+	FeedID      int64   `json:"feed_id"           jsonschema:"The feed ID to configure"`
+	MaxArticles int     `json:"max_articles"      jsonschema:"How many unstarred articles to keep for the feed; 0 for unlimited"`
+	Speaker     *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type feedContentSourceInput struct {
+	FeedID  int64   `json:"feed_id"           jsonschema:"The feed ID to configure"`
+	Source  string  `json:"source"            jsonschema:"Which article field to read: content (default), summary, or longest"`
+	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type feedStripPatternsInput struct {
+	FeedID   int64    `json:"feed_id"           jsonschema:"The feed ID to configure"`
+	Patterns []string `json:"patterns"          jsonschema:"Go regular expressions removed from the feed's article text before summarization and curation; replaces any existing set, [] clears them"`
+	Speaker  *string  `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type feedSetHeadersInput struct {
+	FeedID  int64             `json:"feed_id" jsonschema:"The feed ID to configure"`
+	Headers map[string]string `json:"headers" jsonschema:"HTTP headers to send when fetching the feed, e.g. {\"Authorization\": \"Bearer ...\"}; replaces any existing set, {} clears them"`
+	Speaker *string           `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type feedMuteInput struct {
+	FeedID  int64   `json:"feed_id"           jsonschema:"The feed ID to mute or unmute"`
+	Muted   *bool   `json:"muted,omitempty"   jsonschema:"false to unmute; defaults to true"`
+	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
 type articleGroupGetInput struct {
 	GroupID int64   `json:"group_id"           jsonschema:"The group ID to retrieve"`
 	Speaker *string `json:"speaker,omitempty"  jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
 }
 
+type groupRenameInput struct {
+	GroupID int64   `json:"group_id"           jsonschema:"The group ID to rename"`
+	Name    string  `json:"name"               jsonschema:"The new topic name for the group"`
+	Speaker *string `json:"speaker,omitempty"  jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type groupMergeInput struct {
+	SourceGroupID int64   `json:"source_group_id"   jsonschema:"The group to merge from. It is deleted after its articles are moved."`
+	TargetGroupID int64   `json:"target_group_id"   jsonschema:"The group to merge into. It keeps its ID and topic."`
+	Speaker       *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type articleMoveGroupInput struct {
+	ArticleID int64   `json:"article_id"         jsonschema:"The article ID to move"`
+	GroupID   *int64  `json:"group_id,omitempty" jsonschema:"The group to move the article into. Omit to place it in a new group of its own."`
+	Speaker   *string `json:"speaker,omitempty"  jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
 type preferenceSetInput struct {
-	Key     string  `json:"key"               jsonschema:"Preference key to set. Valid keys: keywords, interest_threshold, notify_when, notify_min_score"`
-	Value   string  `json:"value"             jsonschema:"Value to set (keywords as JSON array, thresholds as number strings, notify_when as enum)"`
+	Key     string  `json:"key"               jsonschema:"Preference key to set. Valid keys: keywords, avoid_keywords, interest_threshold, notify_when, notify_min_score, notify_webhook_url, autostar_min_score, summary_max_words, summary_style, languages, timezone, date_format, sanitize_policy"`
+	Value   string  `json:"value"             jsonschema:"Value to set (keywords and avoid_keywords as JSON array of strings or {term, weight} objects, thresholds as number strings, notify_when as enum)"`
 	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
 }
 
 type promptTypeInput struct {
-	PromptType string  `json:"prompt_type"        jsonschema:"The prompt type. Valid types: curation, summarization, group_summary, related_groups"`
+	PromptType string  `json:"prompt_type"        jsonschema:"The prompt type. Valid types: curation, summarization, group_summary, related_groups, briefing"`
 	Speaker    *string `json:"speaker,omitempty"   jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
 }
 
 type promptSetInput struct {
-	PromptType  string   `json:"prompt_type"            jsonschema:"The prompt type to customize. Valid types: curation, summarization, group_summary, related_groups"`
+	PromptType  string   `json:"prompt_type"            jsonschema:"The prompt type to customize. Valid types: curation, summarization, group_summary, related_groups, briefing"`
 	Template    *string  `json:"template,omitempty"     jsonschema:"New prompt template text"`
 	Temperature *float64 `json:"temperature,omitempty"  jsonschema:"Temperature setting (0.0-2.0)"`
 	Speaker     *string  `json:"speaker,omitempty"      jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
@@ -64,6 +147,12 @@ type articleStarInput struct {
 	Speaker   *string `json:"speaker,omitempty"       jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
 }
 
+type articleNoteSetInput struct {
+	ArticleID int64   `json:"article_id"              jsonschema:"The article ID to annotate"`
+	Note      string  `json:"note"                    jsonschema:"The note text. An empty note deletes the existing note."`
+	Speaker   *string `json:"speaker,omitempty"       jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
 type userRegisterInput struct {
 	Name string `json:"name" jsonschema:"Speaker name to register"`
 }
@@ -74,9 +163,10 @@ type filterRulesListInput struct {
 }
 
 type filterRuleAddInput struct {
-	Axis    string  `json:"axis"               jsonschema:"Filter axis: author, category, or tag"`
+	Axis    string  `json:"axis"               jsonschema:"Filter axis: author, category, tag, domain, title, or lang"`
 	Value   string  `json:"value"              jsonschema:"Value to match (e.g. author name, category name)"`
-	Score   int     `json:"score"              jsonschema:"Score to add when this rule matches (positive = boost, negative = penalize)"`
+	Score   int     `json:"score"              jsonschema:"Score to add when this rule matches (positive = boost, negative = penalize). Must be 0 for block rules."`
+	Block   bool    `json:"block,omitempty"    jsonschema:"Hide matching articles entirely regardless of score or threshold"`
 	FeedID  *int64  `json:"feed_id,omitempty"  jsonschema:"Optional feed ID to scope this rule to a single feed. If omitted the rule is global."`
 	Speaker *string `json:"speaker,omitempty"   jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
 }
@@ -92,6 +182,30 @@ type filterRuleDeleteInput struct {
 	Speaker *string `json:"speaker,omitempty"   jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
 }
 
+type filterRulesImportInput struct {
+	Rules   string  `json:"rules"              jsonschema:"JSON array of rules as returned by filter_rules_export: objects with axis, value, score, and optional block and feed_id"`
+	Speaker *string `json:"speaker,omitempty"   jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type filterRulesExportInput struct {
+	Speaker *string `json:"speaker,omitempty"   jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type articleUnblockInput struct {
+	ArticleID int64   `json:"article_id"         jsonschema:"The blocked article ID to release"`
+	Speaker   *string `json:"speaker,omitempty"  jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type articleResummarizeInput struct {
+	ArticleID int64   `json:"article_id"         jsonschema:"The article ID to summarize again"`
+	Speaker   *string `json:"speaker,omitempty"   jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type articleExplainInput struct {
+	ArticleID int64   `json:"article_id"         jsonschema:"The article ID to explain"`
+	Speaker   *string `json:"speaker,omitempty"   jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
 type feedMetadataInput struct {
 	FeedID  int64   `json:"feed_id"            jsonschema:"The feed ID to discover metadata for"`
 	Speaker *string `json:"speaker,omitempty"   jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
@@ -104,4 +218,25 @@ type searchInput struct {
 	Speaker *string `json:"speaker,omitempty"  jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
 }
 
+type opmlImportInput struct {
+	Content  *string `json:"content,omitempty"  jsonschema:"Inline OPML document to import. Provide either content or url."`
+	URL      *string `json:"url,omitempty"      jsonschema:"URL of a hosted OPML document to fetch and import. Provide either content or url."`
+	Validate bool    `json:"validate,omitempty" jsonschema:"Fetch each new feed before subscribing and report any that fail to parse as failed. Slower for large documents."`
+	Speaker  *string `json:"speaker,omitempty"  jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type pollHistoryInput struct {
+	Limit *int `json:"limit,omitempty" jsonschema:"Maximum number of poll runs to return (default 20)"`
+}
+
+type notificationsPresentInput struct {
+	Limit   *int    `json:"limit,omitempty"   jsonschema:"Maximum number of articles to surface (default 5)"`
+	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
+type readingStatsInput struct {
+	Days    *int    `json:"days,omitempty"    jsonschema:"Number of days to cover, counting today (default 7)"`
+	Speaker *string `json:"speaker,omitempty" jsonschema:"Speaker name for multi-user resolution. If omitted uses the default user."`
+}
+
 type emptyInput struct{}
@@ -6,18 +6,22 @@
 // With --poll, it also runs a background goroutine that fetches feeds and
 // scores articles on a timer, replacing the polling loop previously
 // embedded in the majordomo daemon.
+//
+// With --config, thresholds, keywords and models are read from a herald
+// config.yaml and re-read on SIGHUP without restarting.
 package main
 
 import (
 	"context"
 	"flag"
-	"log"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/matthewjhunter/herald"
+	"github.com/matthewjhunter/herald/internal/logging"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -26,6 +30,7 @@ func main() {
 	defaultDB := filepath.Join(home, ".local", "share", "majordomo", "mcp", "herald", "herald.db")
 
 	dbPath := flag.String("db", defaultDB, "path to herald database")
+	configPath := flag.String("config", "", "herald config.yaml supplying the database path, thresholds, keywords and models; re-read on SIGHUP")
 	ollamaURL := flag.String("ollama", "http://localhost:11434", "Ollama base URL")
 	userID := flag.Int64("user", 1, "user ID for article operations")
 	poll := flag.Bool("poll", false, "enable background feed polling")
@@ -36,8 +41,33 @@ func main() {
 	securityThreshold := flag.Float64("security-threshold", 7.0, "security score threshold")
 	keywords := flag.String("keywords", "", "comma-separated interest keywords")
 	maxParallel := flag.Int("max-parallel", 1, "max concurrent AI pipeline workers")
+	processDelay := flag.Duration("process-delay", 0, "minimum published age before an article is scored, letting post-publish edits settle")
+	busyTimeout := flag.Duration("busy-timeout", 0, "SQLite lock wait (default 15s)")
+	journalMode := flag.String("journal-mode", "", "SQLite journal mode (default WAL)")
+	createDefaultUser := flag.Bool("create-default-user", true, "create user 1 (\"default\") when the database has no users")
+	fetchTimeout := flag.Duration("fetch-timeout", 30*time.Second, "per-attempt feed fetch timeout")
+	fetchConcurrency := flag.Int("fetch-concurrency", 1, "feeds fetched in parallel while polling")
+	fetchRetries := flag.Int("fetch-retries", 0, "extra attempts after a failed feed fetch")
+	fetchProxy := flag.String("fetch-proxy", "", "proxy URL for feed fetches (default: HTTP_PROXY/HTTPS_PROXY/NO_PROXY)")
+	detectLanguage := flag.Bool("detect-language", false, "tag newly fetched articles with their detected language")
+	securityMaxContent := flag.Int("security-max-content", 3000, "characters of article content sent to the security model (minimum 1000)")
+	curationMaxContent := flag.Int("curation-max-content", 3000, "characters of article content sent to the curation model; capped at -security-max-content")
+	groupTitleThreshold := flag.Float64("group-title-threshold", 0.6, "title word overlap (0-1) for grouping articles without the LLM; 0 disables")
+	minGroupSize := flag.Int("min-group-size", 1, "related articles needed before a new group is created; unmatched articles stay ungrouped until then")
+	summaryMinChange := flag.Int("group-summary-min-change", 0, "articles a group must gain or lose before a poll regenerates its summary; 0 regenerates on any change")
+	promptDir := flag.String("prompt-dir", "", "directory of <type>.txt prompt templates replacing the embedded defaults")
+	webhookBlockPrivate := flag.Bool("webhook-block-private", false, "refuse notify_webhook_url targets on localhost or private, loopback and link-local addresses")
+	logFormat := flag.String("log-format", "text", "log output format: text or json")
+	logLevel := flag.String("log-level", "info", "minimum log level: debug, info, warn or error")
 	flag.Parse()
 
+	// Logs go to stderr; stdout is the JSON-RPC channel.
+	logger, err := logging.Setup(os.Stderr, *logFormat, *logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "herald-mcp: %v\n", err)
+		os.Exit(2)
+	}
+
 	var kwList []string
 	if *keywords != "" {
 		for _, kw := range strings.Split(*keywords, ",") {
@@ -47,25 +77,60 @@ func main() {
 		}
 	}
 
+	titleThreshold := *groupTitleThreshold
+	if titleThreshold == 0 {
+		titleThreshold = -1 // EngineConfig reads 0 as "use the default"
+	}
+
 	engineCfg := herald.EngineConfig{
-		DBPath:            *dbPath,
-		OllamaBaseURL:     *ollamaURL,
-		SecurityModel:     *securityModel,
-		CurationModel:     *curationModel,
-		InterestThreshold: *threshold,
-		SecurityThreshold: *securityThreshold,
-		Keywords:          kwList,
-		UserID:            *userID,
-		MaxParallel:       *maxParallel,
+		DBPath:              *dbPath,
+		BusyTimeout:         *busyTimeout,
+		JournalMode:         *journalMode,
+		CreateDefaultUser:   *createDefaultUser,
+		OllamaBaseURL:       *ollamaURL,
+		SecurityModel:       *securityModel,
+		CurationModel:       *curationModel,
+		InterestThreshold:   *threshold,
+		SecurityThreshold:   *securityThreshold,
+		Keywords:            kwList,
+		UserID:              *userID,
+		MaxParallel:         *maxParallel,
+		ProcessDelay:        *processDelay,
+		FetchTimeout:        *fetchTimeout,
+		FetchConcurrency:    *fetchConcurrency,
+		FetchRetries:        *fetchRetries,
+		FetchProxy:          *fetchProxy,
+		DetectLanguage:      *detectLanguage,
+		GroupTitleThreshold: titleThreshold,
+		MinGroupSize:        *minGroupSize,
+		SummaryMinChange:    *summaryMinChange,
+		SecurityMaxContent:  *securityMaxContent,
+		CurationMaxContent:  *curationMaxContent,
+		PromptDir:           *promptDir,
+		Logger:              logger,
+	}
+
+	// The config file supplies defaults; explicit flags still win.
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	flagCfg := engineCfg
+	if *configPath != "" {
+		if err := applyConfigFile(&engineCfg, *configPath, explicit); err != nil {
+			logger.Error("load config", "path", *configPath, "err", err)
+			os.Exit(2)
+		}
 	}
 
 	engine, err := herald.NewEngine(engineCfg)
 	if err != nil {
-		log.Fatalf("create herald engine: %v", err)
+		logger.Error("create herald engine", "err", err)
+		os.Exit(1)
 	}
 	defer engine.Close()
 
-	log.SetOutput(os.Stderr)
+	if *configPath != "" {
+		go reloadOnSIGHUP(engine, flagCfg, *configPath, explicit)
+	}
 
 	hs := newHeraldServer(engine, *userID)
 
@@ -73,17 +138,19 @@ func main() {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
-		p := newPoller(engine, *userID, *pollInterval, *threshold)
+		p := newPoller(engine, *userID, *pollInterval)
+		p.webhook.BlockPrivate = *webhookBlockPrivate
 		p.start(ctx)
 		defer p.stop()
 
 		hs.poller = p
 	}
 
-	log.Printf("herald-mcp starting (user=%d)", hs.userID)
+	logger.Info("herald-mcp starting", "event", "server_start", "user_id", hs.userID)
 
 	mcpSrv := newMCPServer(hs)
 	if err := mcpSrv.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
-		log.Fatalf("server error: %v", err)
+		logger.Error("server error", "err", err)
+		os.Exit(1)
 	}
 }
@@ -2,31 +2,37 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/matthewjhunter/herald"
+	"github.com/matthewjhunter/herald/internal/notify"
+	"golang.org/x/sync/errgroup"
 )
 
-// poller runs a background feed-fetch and article-scoring loop.
+// poller runs a background feed-fetch and article-scoring loop. Each cycle
+// is a two-stage pipeline: every feed is fetched first, and only once the
+// fetch has fully completed are new articles scored for every user with a
+// subscription. Users are processed concurrently, bounded by the engine's
+// max_parallel limit, and their AI calls share that same limiter.
 type poller struct {
-	engine    *herald.Engine
-	userID    int64
-	interval  time.Duration
-	threshold float64
+	engine   *herald.Engine
+	userID   int64 // default user, whose pending counts are logged each cycle
+	interval time.Duration
+	webhook  notify.Options // delivery settings for each user's notify_webhook_url
 
 	mu   sync.Mutex
 	done chan struct{}
 }
 
-func newPoller(engine *herald.Engine, userID int64, interval time.Duration, threshold float64) *poller {
+func newPoller(engine *herald.Engine, userID int64, interval time.Duration) *poller {
 	return &poller{
-		engine:    engine,
-		userID:    userID,
-		interval:  interval,
-		threshold: threshold,
-		done:      make(chan struct{}),
+		engine:   engine,
+		userID:   userID,
+		interval: interval,
+		webhook:  notify.Options{MaxRetries: notify.DefaultMaxRetries},
+		done:     make(chan struct{}),
 	}
 }
 
@@ -34,64 +40,159 @@ func newPoller(engine *herald.Engine, userID int64, interval time.Duration, thre
 // each tick of the configured interval.
 func (p *poller) start(ctx context.Context) {
 	go p.loop(ctx)
-	log.Printf("poller: started (interval=%s, threshold=%.1f)", p.interval, p.threshold)
+	slog.Info("poller started", "event", "poller_start", "interval", p.interval, "default_threshold", p.engine.InterestThreshold())
 }
 
 // stop signals the poll loop to exit.
 func (p *poller) stop() {
 	close(p.done)
-	log.Printf("poller: stopped")
+	slog.Info("poller stopped", "event", "poller_stop")
 }
 
 // poll runs a single fetch-score cycle. Exported for the poll_now MCP tool.
+// The result's ProcessedCount and HighInterest are totals across users.
 func (p *poller) poll(ctx context.Context) (*herald.FetchResult, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	start := time.Now()
+	slog.Info("poll started", "event", "poll_start")
+
 	result, err := p.engine.FetchAllFeeds(ctx)
 	if err != nil {
 		return nil, err
 	}
+	var users int
+	defer func() {
+		elapsed := time.Since(start)
+		slog.Info("poll finished", "event", "poll_finish", "users", users,
+			"new_articles", result.NewArticles, "scored", result.ProcessedCount,
+			"high_interest", result.HighInterest, "duration_ms", elapsed.Milliseconds())
+		if err := p.engine.RecordPollRun(start, elapsed, result); err != nil {
+			slog.Warn("recording poll run failed", "err", err)
+		}
+	}()
 
 	unsummarized, unscored, pendErr := p.engine.PendingCounts(p.userID)
 	if pendErr != nil {
-		log.Printf("poller: pending counts: %v", pendErr)
+		slog.Warn("pending counts failed", "user_id", p.userID, "err", pendErr)
 	}
 
-	log.Printf("poller: %d/%d feeds downloaded, %d not modified, %d errors, %d new articles, %d pending content scan, %d pending security scan",
-		result.FeedsDownloaded, result.FeedsTotal,
-		result.FeedsNotModified, result.FeedsErrored,
-		result.NewArticles, unsummarized, unscored)
+	slog.Info("feeds fetched", "event", "poll_fetch", "feeds", result.FeedsTotal,
+		"downloaded", result.FeedsDownloaded, "not_modified", result.FeedsNotModified,
+		"errors", result.FeedsErrored, "new_articles", result.NewArticles,
+		"pending_summary", unsummarized, "pending_score", unscored)
 
+	userIDs, err := p.engine.SubscribingUsers()
+	if err != nil {
+		return result, err
+	}
 	if result.NewArticles == 0 {
+		userIDs = p.withPending(userIDs)
+	}
+	users = len(userIDs)
+	if users == 0 {
 		return result, nil
 	}
 
-	scored, err := p.engine.ProcessNewArticles(ctx, p.userID)
-	if err != nil {
-		return result, err
+	p.process(ctx, userIDs, result)
+	return result, nil
+}
+
+// withPending filters userIDs to the users with articles still unscored, such
+// as ones held back by process_delay_seconds or left over from an
+// interrupted cycle. A user whose count fails is kept, so processing retries.
+func (p *poller) withPending(userIDs []int64) []int64 {
+	var pending []int64
+	for _, userID := range userIDs {
+		_, unscored, err := p.engine.PendingCounts(userID)
+		if err != nil {
+			slog.Warn("pending counts failed", "user_id", userID, "err", err)
+		}
+		if err != nil || unscored > 0 {
+			pending = append(pending, userID)
+		}
 	}
+	return pending
+}
 
-	var highCount int
-	for _, s := range scored {
-		if s.InterestScore >= p.threshold {
-			highCount++
+// process scores new articles for each user and delivers the results,
+// adding the totals to result. At most MaxParallel users are in flight at
+// once; within ProcessNewArticles the engine's shared limiter bounds the
+// number of concurrent AI calls across all of them.
+func (p *poller) process(ctx context.Context, userIDs []int64, result *herald.FetchResult) {
+	var mu sync.Mutex // guards result
+	var g errgroup.Group
+	g.SetLimit(p.engine.MaxParallel())
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			break
 		}
+		g.Go(func() error {
+			// As in the CLI, one user's failure doesn't stop the others.
+			scored, err := p.engine.ProcessNewArticles(ctx, userID)
+			if err != nil {
+				slog.Warn("processing articles failed", "user_id", userID, "err", err)
+				return nil
+			}
+			// Each user's own threshold, read per cycle so preference
+			// changes and config reloads take effect.
+			prefs, _ := p.engine.GetPreferences(userID) // falls back to defaults
+			mu.Lock()
+			result.ProcessedCount += len(scored)
+			for _, s := range scored {
+				if s.InterestScore >= prefs.InterestThreshold {
+					result.HighInterest++
+				}
+			}
+			mu.Unlock()
+			p.deliver(ctx, userID, scored, prefs)
+			return nil
+		})
 	}
+	g.Wait() //nolint:errcheck
+}
 
-	result.ProcessedCount = len(scored)
-	result.HighInterest = highCount
+// deliver announces a user's freshly scored articles: webhooks first, then
+// notify_when routing.
+func (p *poller) deliver(ctx context.Context, userID int64, scored []herald.ScoredArticle, prefs *herald.UserPreferences) {
+	p.dispatchWebhooks(ctx, userID, scored, prefs)
 
-	if highCount > 0 {
-		log.Printf("poller: %d high-interest articles (of %d scored)", highCount, len(scored))
+	// Honor notify_when: "always" announces each article now, "queue" holds
+	// them for the next briefing, "present" leaves them in the unread list.
+	notable, err := p.engine.RouteNotifications(userID, scored)
+	if err != nil {
+		slog.Warn("routing notifications failed", "user_id", userID, "err", err)
+	}
+	for _, a := range notable {
+		slog.Info("high-interest article", "event", "notify", "user_id", userID,
+			"article_id", a.ID, "title", a.Title, "url", a.URL, "score", a.InterestScore)
 	}
+}
 
-	return result, nil
+// dispatchWebhooks POSTs each safe article at or above the user's interest
+// threshold to their notify_webhook_url, if one is set. Delivery failures are
+// logged and don't fail the poll.
+func (p *poller) dispatchWebhooks(ctx context.Context, userID int64, scored []herald.ScoredArticle, prefs *herald.UserPreferences) {
+	if prefs.NotifyWebhookURL == "" {
+		return
+	}
+	for _, s := range scored {
+		if !s.Safe || s.InterestScore < prefs.InterestThreshold {
+			continue
+		}
+		article := notify.Article{ID: s.ID, FeedID: s.FeedID, Title: s.Title, URL: s.URL}
+		if err := notify.Dispatch(ctx, prefs.NotifyWebhookURL, article, s.InterestScore, p.webhook); err != nil {
+			slog.Warn("webhook delivery failed", "user_id", userID, "article_id", s.ID, "err", err)
+			continue
+		}
+		slog.Info("webhook delivered", "event", "webhook", "user_id", userID, "article_id", s.ID, "score", s.InterestScore)
+	}
 }
 
 func (p *poller) loop(ctx context.Context) {
 	if _, err := p.poll(ctx); err != nil {
-		log.Printf("poller: initial poll error: %v", err)
+		slog.Error("initial poll failed", "event", "poll_error", "err", err)
 	}
 
 	ticker := time.NewTicker(p.interval)
@@ -105,7 +206,7 @@ func (p *poller) loop(ctx context.Context) {
 			return
 		case <-ticker.C:
 			if _, err := p.poll(ctx); err != nil {
-				log.Printf("poller: poll error: %v", err)
+				slog.Error("poll failed", "event", "poll_error", "err", err)
 			}
 		}
 	}
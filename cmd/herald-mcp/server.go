@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/matthewjhunter/herald"
@@ -72,6 +75,12 @@ func errResult(format string, args ...any) (*mcp.CallToolResult, any, error) {
 	}, nil, nil
 }
 
+// logTool records a completed tool call as a structured event named after
+// the tool; args are slog key/value pairs.
+func logTool(name string, args ...any) {
+	slog.Info("tool call", append([]any{"event", name}, args...)...)
+}
+
 func ptrStr(s *string) string {
 	if s == nil {
 		return ""
@@ -79,12 +88,12 @@ func ptrStr(s *string) string {
 	return *s
 }
 
-// registerTools registers all 26 herald tools with the MCP server.
+// registerTools registers all herald tools with the MCP server.
 func registerTools(s *mcp.Server, hs *heraldServer) {
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "articles_unread",
-		Description: "Get unread articles from subscribed feeds, optionally filtered by minimum interest score. Returns article titles, URLs, summaries, and scores.",
+		Description: "Get unread articles from subscribed feeds, optionally filtered by minimum interest score. Returns article titles, URLs, summaries, and scores. When min_score is set, interest_score is the age-decayed score used for ordering and raw_interest_score is the original score (min_score applies to the raw score). Without min_score, order picks newest (default), oldest, or score (age-decayed interest score) first.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input articlesUnreadInput) (*mcp.CallToolResult, any, error) {
 		userID := hs.resolveUser(ptrStr(input.Speaker))
 		limit := 20
@@ -99,43 +108,159 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if input.MinScore != nil {
 			minScore = *input.MinScore
 		}
+		order := ptrStr(input.Order)
+		if err := herald.ValidateArticleOrder(order); err != nil {
+			return errResult("%v", err)
+		}
 
 		if minScore > 0 {
-			articles, scores, err := hs.engine.GetHighInterestArticles(userID, minScore, limit, offset)
+			articles, scores, rawScores, err := hs.engine.GetHighInterestArticles(userID, minScore, limit, offset)
 			if err != nil {
 				return errResult("%v", err)
 			}
+			// interest_score is the time-decayed value used for ordering;
+			// raw_interest_score is the score the article was originally given.
 			type scoredArticle struct {
 				herald.Article
-				InterestScore float64 `json:"interest_score"`
+				InterestScore    float64 `json:"interest_score"`
+				RawInterestScore float64 `json:"raw_interest_score"`
 			}
 			result := make([]scoredArticle, len(articles))
 			for i, a := range articles {
 				a.Content = ""
-				score := 0.0
+				score, raw := 0.0, 0.0
 				if i < len(scores) {
 					score = scores[i]
 				}
-				result[i] = scoredArticle{Article: a, InterestScore: score}
+				if i < len(rawScores) {
+					raw = rawScores[i]
+				}
+				result[i] = scoredArticle{Article: a, InterestScore: score, RawInterestScore: raw}
 			}
-			log.Printf("articles_unread: limit=%d min_score=%.1f -> %d results", limit, minScore, len(result))
+			logTool("articles_unread", "limit", limit, "min_score", minScore, "results", len(result))
 			return jsonResult(result)
 		}
 
-		articles, err := hs.engine.GetUnreadArticles(userID, limit, offset)
+		articles, err := hs.engine.GetUnreadArticlesOrdered(userID, limit, offset, order)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		for i := range articles {
+			articles[i].Content = ""
+		}
+		logTool("articles_unread", "limit", limit, "order", order, "results", len(articles))
+		return jsonResult(articles)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "articles_ungrouped",
+		Description: "Get unread, scored articles that were not clustered into any article group, highest interest first. Use this to find singleton stories that the group views would otherwise hide.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articlesUngroupedInput) (*mcp.CallToolResult, any, error) {
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		limit := 20
+		if input.Limit != nil && *input.Limit > 0 {
+			limit = *input.Limit
+		}
+		offset := 0
+		if input.Offset != nil {
+			offset = *input.Offset
+		}
+		articles, err := hs.engine.GetUngroupedArticles(userID, limit, offset)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		for i := range articles {
+			articles[i].Content = ""
+		}
+		logTool("articles_ungrouped", "limit", limit, "results", len(articles))
+		return jsonResult(articles)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "articles_blocked",
+		Description: "Get articles the security check scored below the security threshold, newest first, with their security_score and security_reason. These were never summarized or curated. Use this to audit false positives.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articlesBlockedInput) (*mcp.CallToolResult, any, error) {
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		limit := 20
+		if input.Limit != nil && *input.Limit > 0 {
+			limit = *input.Limit
+		}
+		offset := 0
+		if input.Offset != nil {
+			offset = *input.Offset
+		}
+		articles, err := hs.engine.GetBlockedArticles(userID, limit, offset)
 		if err != nil {
 			return errResult("%v", err)
 		}
 		for i := range articles {
 			articles[i].Content = ""
 		}
-		log.Printf("articles_unread: limit=%d -> %d results", limit, len(articles))
+		logTool("articles_blocked", "limit", limit, "results", len(articles))
+		return jsonResult(articles)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "articles_read_history",
+		Description: "Get articles the user has already read, most recently read first. Use this to re-find something read earlier, e.g. \"that article I read yesterday\". Only articles from current subscriptions are included. open_count is how many times the user opened each article, a rough signal of what they found worth revisiting.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articlesReadHistoryInput) (*mcp.CallToolResult, any, error) {
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		limit := 20
+		if input.Limit != nil && *input.Limit > 0 {
+			limit = *input.Limit
+		}
+		offset := 0
+		if input.Offset != nil {
+			offset = *input.Offset
+		}
+		articles, err := hs.engine.GetReadHistory(userID, limit, offset)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		for i := range articles {
+			articles[i].Content = ""
+		}
+		logTool("articles_read_history", "limit", limit, "results", len(articles))
+		return jsonResult(articles)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "articles_since",
+		Description: "Get articles fetched since a point in time, newest first, read or unread. Use this for \"what's new since I last looked\". Without since, uses the user's last visit to the web UI.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articlesSinceInput) (*mcp.CallToolResult, any, error) {
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		limit := 50
+		if input.Limit != nil && *input.Limit > 0 {
+			limit = *input.Limit
+		}
+		var since time.Time
+		if s := ptrStr(input.Since); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return errResult("since must be an RFC3339 timestamp: %v", err)
+			}
+			since = t
+		} else {
+			t, err := hs.engine.LastVisit(userID)
+			if err != nil {
+				return errResult("%v", err)
+			}
+			since = t
+		}
+		articles, err := hs.engine.GetArticlesSince(userID, since, limit)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		for i := range articles {
+			articles[i].Content = ""
+		}
+		logTool("articles_since", "since", since.Format(time.RFC3339), "results", len(articles))
 		return jsonResult(articles)
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "articles_get",
-		Description: "Get full article content by ID. Use this to read the complete text of an article for follow-up discussion or analysis.",
+		Description: "Get full article content by ID. Use this to read the complete text of an article for follow-up discussion or analysis. Includes word_count and an estimated reading_minutes when known. Each call counts as an opening in the user's read history.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input articleIDInput) (*mcp.CallToolResult, any, error) {
 		if input.ArticleID == 0 {
 			return errResult("article_id parameter is required")
@@ -145,7 +270,8 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("articles_get: id=%d", input.ArticleID)
+		hs.engine.RecordArticleOpen(userID, input.ArticleID) //nolint:errcheck
+		logTool("articles_get", "article_id", input.ArticleID)
 		return jsonResult(article)
 	})
 
@@ -160,20 +286,20 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err := hs.engine.MarkArticleRead(userID, input.ArticleID); err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("articles_mark_read: id=%d", input.ArticleID)
+		logTool("articles_mark_read", "article_id", input.ArticleID)
 		return textResult("Article %d marked as read.", input.ArticleID)
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "feeds_list",
-		Description: "List all subscribed RSS/Atom feeds with their titles, URLs, and last fetch times.",
+		Description: "List all subscribed RSS/Atom feeds with their titles, URLs and health: last_fetched is the last successful fetch, last_error the most recent fetch error (absent when the last fetch succeeded), consecutive_errors the failures since then, enabled whether polling is on, and status \"active\" or \"dead\" (polling gave up). Use these to report feeds that have been failing.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input speakerOnlyInput) (*mcp.CallToolResult, any, error) {
 		userID := hs.resolveUser(ptrStr(input.Speaker))
 		feeds, err := hs.engine.GetUserFeeds(userID)
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("feeds_list: %d feeds", len(feeds))
+		logTool("feeds_list", "feeds", len(feeds))
 		return jsonResult(feeds)
 	})
 
@@ -192,13 +318,40 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err := hs.engine.SubscribeFeed(userID, input.URL, title); err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("feed_subscribe: url=%s title=%q", input.URL, title)
+		logTool("feed_subscribe", "url", input.URL, "title", title)
 		return textResult("Subscribed to %s", input.URL)
 	})
 
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "opml_import",
+		Description: "Import feed subscriptions from an OPML document, given either inline OPML content or the URL of a hosted OPML export from another feed reader. Nested folders are flattened. Reports how many feeds were added, skipped as already subscribed, and failed; set validate=true to fetch each new feed first and fail any that don't parse.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input opmlImportInput) (*mcp.CallToolResult, any, error) {
+		content, opmlURL := ptrStr(input.Content), ptrStr(input.URL)
+		if (content == "") == (opmlURL == "") {
+			return errResult("exactly one of content or url is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		var result *herald.OPMLImportResult
+		var err error
+		if opmlURL != "" {
+			result, err = hs.engine.ImportOPMLFromURL(ctx, opmlURL, userID, input.Validate)
+		} else {
+			result, err = hs.engine.ImportOPMLReader(strings.NewReader(content), userID, input.Validate)
+		}
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("opml_import", "url", opmlURL, "added", result.Added, "skipped", result.Skipped, "failed", len(result.Failed))
+		msg := fmt.Sprintf("Imported OPML: %d added, %d already subscribed, %d failed.", result.Added, result.Skipped, len(result.Failed))
+		if len(result.Failed) > 0 {
+			msg += "\nFailed feeds:\n- " + strings.Join(result.Failed, "\n- ")
+		}
+		return textResult("%s", msg)
+	})
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "feed_unsubscribe",
-		Description: "Unsubscribe from a feed by ID. Use feeds_list to find the feed ID. If no other users subscribe to it, the feed and its articles are deleted.",
+		Description: "Unsubscribe from a feed by ID. Use feeds_list to find the feed ID. If no other users subscribe to it, the feed and its articles are deleted after a 24-hour grace period; feed_resubscribe undoes the unsubscribe until then.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input feedIDInput) (*mcp.CallToolResult, any, error) {
 		if input.FeedID == 0 {
 			return errResult("feed_id parameter is required")
@@ -207,10 +360,25 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err := hs.engine.UnsubscribeFeed(userID, input.FeedID); err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("feed_unsubscribe: feed_id=%d", input.FeedID)
+		logTool("feed_unsubscribe", "feed_id", input.FeedID)
 		return textResult("Unsubscribed from feed %d.", input.FeedID)
 	})
 
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "feed_resubscribe",
+		Description: "Undo a recent feed_unsubscribe by feed ID, restoring the subscription with its articles and read state. Only the user who unsubscribed can undo it, once, within 24 hours.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input feedIDInput) (*mcp.CallToolResult, any, error) {
+		if input.FeedID == 0 {
+			return errResult("feed_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.ResubscribeFeed(userID, input.FeedID); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("feed_resubscribe", "feed_id", input.FeedID)
+		return textResult("Resubscribed to feed %d.", input.FeedID)
+	})
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "feed_rename",
 		Description: "Rename a feed's display title. Use feeds_list to find the feed ID.",
@@ -224,20 +392,127 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err := hs.engine.RenameFeed(input.FeedID, input.Title); err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("feed_rename: feed_id=%d title=%q", input.FeedID, input.Title)
+		logTool("feed_rename", "feed_id", input.FeedID, "title", input.Title)
 		return textResult("Feed %d renamed to %q.", input.FeedID, input.Title)
 	})
 
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "feed_dedupe",
+		Description: "Choose how a feed's articles are deduplicated. Set by_url=true for feeds that regenerate GUIDs on every publish and flood the reader with duplicates; Herald also turns this on automatically when it detects persistent GUID churn. Set by_url=false to go back to GUID matching.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input feedDedupeInput) (*mcp.CallToolResult, any, error) {
+		if input.FeedID == 0 {
+			return errResult("feed_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.SetFeedDedupeByURL(userID, input.FeedID, input.ByURL); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("feed_dedupe", "feed_id", input.FeedID, "by_url", input.ByURL)
+		if input.ByURL {
+			return textResult("Feed %d now deduplicates articles by URL.", input.FeedID)
+		}
+		return textResult("Feed %d now deduplicates articles by GUID.", input.FeedID)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "feed_max_articles",
+		Description: "Cap how many articles Herald keeps for a high-volume feed. After each fetch the oldest articles beyond max_articles are deleted; starred articles are always kept and don't count toward the cap. Set max_articles=0 for unlimited.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input feedMaxArticlesInput) (*mcp.CallToolResult, any, error) {
+		if input.FeedID == 0 {
+			return errResult("feed_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.SetFeedMaxArticles(userID, input.FeedID, input.MaxArticles); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("feed_max_articles", "feed_id", input.FeedID, "max_articles", input.MaxArticles)
+		if input.MaxArticles == 0 {
+			return textResult("Feed %d now keeps unlimited articles.", input.FeedID)
+		}
+		return textResult("Feed %d now keeps its %d newest unstarred articles.", input.FeedID, input.MaxArticles)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "feed_content_source",
+		Description: "Choose which article field Herald reads for a feed, for both AI processing and display. Use source=summary for feeds that put the real text in the summary and junk in the content, or source=longest to take whichever has more text. The default is content.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input feedContentSourceInput) (*mcp.CallToolResult, any, error) {
+		if input.FeedID == 0 {
+			return errResult("feed_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.SetFeedContentSource(userID, input.FeedID, input.Source); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("feed_content_source", "feed_id", input.FeedID, "source", input.Source)
+		return textResult("Feed %d now takes article text from %s.", input.FeedID, input.Source)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "feed_strip_patterns",
+		Description: "Set regular expressions removed from a feed's article text before summarization and curation, for boilerplate such as \"This article first appeared on ...\" that skews summaries. Use (?s) or (?m) flags as needed. The security check and display are unaffected and already-scored articles are not reprocessed. Replaces the feed's existing patterns; pass [] to clear them.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input feedStripPatternsInput) (*mcp.CallToolResult, any, error) {
+		if input.FeedID == 0 {
+			return errResult("feed_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.SetFeedStripPatterns(userID, input.FeedID, input.Patterns); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("feed_strip_patterns", "feed_id", input.FeedID, "patterns", len(input.Patterns))
+		if len(input.Patterns) == 0 {
+			return textResult("Cleared strip patterns for feed %d.", input.FeedID)
+		}
+		return textResult("Feed %d now strips %d pattern(s) before AI processing.", input.FeedID, len(input.Patterns))
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "feed_set_headers",
+		Description: "Set custom HTTP headers (such as Authorization or Cookie) sent when fetching a private feed that otherwise fails with 401/403. Only allowed on a feed the user alone subscribes to; while headers are set, other users can't subscribe to it. Replaces the feed's existing headers; pass {} to clear them. Header values are stored but never echoed back or logged.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input feedSetHeadersInput) (*mcp.CallToolResult, any, error) {
+		if input.FeedID == 0 {
+			return errResult("feed_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.SetFeedFetchHeaders(userID, input.FeedID, input.Headers); err != nil {
+			return errResult("%v", err)
+		}
+		names := slices.Sorted(maps.Keys(input.Headers))
+		logTool("feed_set_headers", "feed_id", input.FeedID, "headers", names)
+		if len(names) == 0 {
+			return textResult("Cleared custom fetch headers for feed %d.", input.FeedID)
+		}
+		return textResult("Feed %d will be fetched with %d custom header(s): %s.", input.FeedID, len(names), strings.Join(names, ", "))
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "feed_mute",
+		Description: "Mute a subscription: the feed keeps fetching and its articles stay searchable and visible in the feed's own view, but they are hidden from articles_unread and unread counts. Pass muted=false to unmute.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input feedMuteInput) (*mcp.CallToolResult, any, error) {
+		if input.FeedID == 0 {
+			return errResult("feed_id parameter is required")
+		}
+		muted := input.Muted == nil || *input.Muted
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.SetFeedMuted(userID, input.FeedID, muted); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("feed_mute", "feed_id", input.FeedID, "muted", muted)
+		if muted {
+			return textResult("Feed %d muted.", input.FeedID)
+		}
+		return textResult("Feed %d unmuted.", input.FeedID)
+	})
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "article_groups",
-		Description: "List article groups (clusters of articles covering the same event or topic). Each group has a topic label, article count, and max interest score. Use this for briefings to present related coverage together.",
+		Description: "List article groups (clusters of articles covering the same event or topic). Each group has a topic label, article count, unread count, and max interest score. Use this for briefings to present related coverage together.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input speakerOnlyInput) (*mcp.CallToolResult, any, error) {
 		userID := hs.resolveUser(ptrStr(input.Speaker))
 		groups, err := hs.engine.GetUserGroups(userID)
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("article_groups: %d groups", len(groups))
+		logTool("article_groups", "groups", len(groups))
 		return jsonResult(groups)
 	})
 
@@ -252,10 +527,109 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("article_group_get: id=%d", input.GroupID)
+		logTool("article_group_get", "group_id", input.GroupID)
 		return jsonResult(group)
 	})
 
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "group_rename",
+		Description: "Rename an article group's topic. Use article_groups to find the group ID.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input groupRenameInput) (*mcp.CallToolResult, any, error) {
+		if input.GroupID == 0 {
+			return errResult("group_id parameter is required")
+		}
+		if input.Name == "" {
+			return errResult("name parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.RenameGroup(userID, input.GroupID, input.Name); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("group_rename", "group_id", input.GroupID, "name", input.Name)
+		return textResult("Group %d renamed to %q.", input.GroupID, input.Name)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "group_merge",
+		Description: "Merge one article group into another when both cover the same story. Articles from the source group move to the target group, the source group is deleted, and the target's summary is regenerated.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input groupMergeInput) (*mcp.CallToolResult, any, error) {
+		if input.SourceGroupID == 0 || input.TargetGroupID == 0 {
+			return errResult("source_group_id and target_group_id parameters are required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.MergeGroups(ctx, userID, input.SourceGroupID, input.TargetGroupID); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("group_merge", "source_group_id", input.SourceGroupID, "group_id", input.TargetGroupID)
+		return textResult("Group %d merged into group %d.", input.SourceGroupID, input.TargetGroupID)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "group_resummarize",
+		Description: "Regenerate an article group's headline and summary from its current articles. Use this after moving articles into or out of a group, or when the summary is stale.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articleGroupGetInput) (*mcp.CallToolResult, any, error) {
+		if input.GroupID == 0 {
+			return errResult("group_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.RegenerateGroupSummary(ctx, userID, input.GroupID); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("group_resummarize", "group_id", input.GroupID)
+		return textResult("Summary for group %d regenerated.", input.GroupID)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "group_delete",
+		Description: "Delete an article group. Its articles are not deleted; they return to their feeds as ungrouped articles.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articleGroupGetInput) (*mcp.CallToolResult, any, error) {
+		if input.GroupID == 0 {
+			return errResult("group_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.DisbandGroup(userID, input.GroupID); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("group_delete", "group_id", input.GroupID)
+		return textResult("Group %d deleted.", input.GroupID)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "group_mark_read",
+		Description: "Mark every unread article in an article group as read, clearing the whole story at once. Scores are kept, so the articles are not re-processed. Returns the number of articles marked.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articleGroupGetInput) (*mcp.CallToolResult, any, error) {
+		if input.GroupID == 0 {
+			return errResult("group_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		n, err := hs.engine.MarkGroupRead(userID, input.GroupID)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("group_mark_read", "group_id", input.GroupID, "marked", n)
+		return textResult("Marked %d articles in group %d as read.", n, input.GroupID)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "article_move_group",
+		Description: "Move an article into a different group when clustering filed it in the wrong one. The article is removed from its current group, and summaries of both affected groups are regenerated. Omit group_id to give the article a new group of its own.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articleMoveGroupInput) (*mcp.CallToolResult, any, error) {
+		if input.ArticleID == 0 {
+			return errResult("article_id parameter is required")
+		}
+		var groupID int64
+		if input.GroupID != nil {
+			groupID = *input.GroupID
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		target, err := hs.engine.MoveArticleToGroup(ctx, userID, input.ArticleID, groupID)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("article_move_group", "article_id", input.ArticleID, "group_id", target)
+		return textResult("Article %d moved to group %d.", input.ArticleID, target)
+	})
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "feed_stats",
 		Description: "Get article statistics per feed and totals: total articles, unread count, and unsummarized count. Use this to understand pipeline health and coverage.",
@@ -265,13 +639,31 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("feed_stats")
+		logTool("feed_stats")
+		return jsonResult(stats)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "reading_stats",
+		Description: "Summarize what the user has read over the last few days (default 7, counting today): articles read, how many of those are starred, how many scored high-interest (8+), and a per-day count. Use this for questions like \"how much did I read this week?\"",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input readingStatsInput) (*mcp.CallToolResult, any, error) {
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		days := 7
+		if input.Days != nil && *input.Days > 0 {
+			days = *input.Days
+		}
+		since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+		stats, err := hs.engine.GetReadingStats(userID, since)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("reading_stats", "days", days, "read", stats.Read)
 		return jsonResult(stats)
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "poll_now",
-		Description: "Trigger an immediate feed poll cycle: fetch all feeds, score new articles through the AI pipeline, and return results. Only available when the server is running with --poll. Use this when the user asks to check for new articles right now.",
+		Description: "Trigger an immediate feed poll cycle: fetch all feeds, score new articles through the AI pipeline for every subscribed user, and return results with processed and high-interest counts totalled across users. Only available when the server is running with --poll. Use this when the user asks to check for new articles right now.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input emptyInput) (*mcp.CallToolResult, any, error) {
 		if hs.poller == nil {
 			return errResult("polling is not enabled (start with --poll)")
@@ -282,28 +674,43 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("poll failed: %v", err)
 		}
-		log.Printf("poll_now: %d/%d feeds, %d new, %d scored, %d high-interest",
-			result.FeedsDownloaded, result.FeedsTotal,
-			result.NewArticles, result.ProcessedCount, result.HighInterest)
+		logTool("poll_now", "feeds", result.FeedsTotal, "downloaded", result.FeedsDownloaded,
+			"new_articles", result.NewArticles, "scored", result.ProcessedCount, "high_interest", result.HighInterest)
 		return jsonResult(result)
 	})
 
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "poll_history",
+		Description: "List recent poll cycles, most recent first: start time, duration, feeds fetched and downloaded, new articles, articles processed by the AI pipeline, and feeds that errored. Use this to spot trends such as slowing polls or rising fetch errors. Cycles are recorded by the background poller and poll_now.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input pollHistoryInput) (*mcp.CallToolResult, any, error) {
+		limit := 20
+		if input.Limit != nil && *input.Limit > 0 {
+			limit = *input.Limit
+		}
+		runs, err := hs.engine.GetPollRuns(limit)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("poll_history", "count", len(runs))
+		return jsonResult(runs)
+	})
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "preferences_get",
-		Description: "Get all user preferences as structured JSON. Returns keywords, interest threshold, and notification settings.",
+		Description: "Get all user preferences as structured JSON. Returns keywords, avoid keywords, interest threshold, and notification settings.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input speakerOnlyInput) (*mcp.CallToolResult, any, error) {
 		userID := hs.resolveUser(ptrStr(input.Speaker))
 		prefs, err := hs.engine.GetPreferences(userID)
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("preferences_get")
+		logTool("preferences_get")
 		return jsonResult(prefs)
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "preference_set",
-		Description: "Set a single user preference by key. Valid keys: keywords (JSON array), interest_threshold (number), notify_when (\"present\"|\"always\"|\"queue\"), notify_min_score (number).",
+		Description: "Set a single user preference by key. Valid keys: keywords (JSON array of terms, or of {\"term\", \"weight\"} objects where weight is 0-10 and defaults to 1; higher-weighted interests count for more when scoring), avoid_keywords (same shapes as keywords; topics the curation model scores lower, a higher weight pushing harder), interest_threshold (number), notify_when (\"present\"|\"always\"|\"queue\"), notify_min_score (number), notify_webhook_url (http or https URL that receives a JSON POST for each safe high-interest article; empty to disable), autostar_min_score (number; newly scored articles at or above it are starred, 0 disables), summary_max_words (integer, 0 = no limit), summary_style (\"terse\"|\"detailed\"|\"bullets\"), languages (JSON array of language codes such as [\"en\"]; restricts unread articles to those languages, articles of unknown language always shown), timezone (IANA zone name such as \"America/New_York\" for dates in the web UI), date_format (\"relative\"|\"absolute\"), sanitize_policy (\"ugc\"|\"strict\"|\"relaxed\"; how article HTML is filtered in the web UI, strict dropping images and embeds).",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input preferenceSetInput) (*mcp.CallToolResult, any, error) {
 		if input.Key == "" {
 			return errResult("key parameter is required")
@@ -315,7 +722,7 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err := hs.engine.SetPreference(userID, input.Key, input.Value); err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("preference_set: %s=%s", input.Key, input.Value)
+		logTool("preference_set", "key", input.Key, "value", input.Value)
 		return textResult("Preference %q set.", input.Key)
 	})
 
@@ -328,13 +735,13 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("prompts_list: %d types", len(prompts))
+		logTool("prompts_list", "types", len(prompts))
 		return jsonResult(prompts)
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "prompt_get",
-		Description: "Get the active prompt template and temperature for a given type. Prompt types: curation, summarization, group_summary, related_groups.",
+		Description: "Get the active prompt template and temperature for a given type. Prompt types: curation, summarization, group_summary, related_groups, briefing.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input promptTypeInput) (*mcp.CallToolResult, any, error) {
 		if input.PromptType == "" {
 			return errResult("prompt_type parameter is required")
@@ -347,13 +754,13 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("prompt_get: type=%s custom=%v", input.PromptType, detail.IsCustom)
+		logTool("prompt_get", "prompt_type", input.PromptType, "custom", detail.IsCustom)
 		return jsonResult(detail)
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "prompt_set",
-		Description: "Customize a prompt template and/or temperature. At least one of template or temperature must be provided. Prompt types: curation, summarization, group_summary, related_groups.",
+		Description: "Customize a prompt template and/or temperature. At least one of template or temperature must be provided. Prompt types: curation, summarization, group_summary, related_groups, briefing (the layout of the briefing tool's output, not an AI prompt).",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input promptSetInput) (*mcp.CallToolResult, any, error) {
 		if input.PromptType == "" {
 			return errResult("prompt_type parameter is required")
@@ -372,7 +779,7 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err := hs.engine.SetPrompt(userID, input.PromptType, template, input.Temperature, nil); err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("prompt_set: type=%s", input.PromptType)
+		logTool("prompt_set", "prompt_type", input.PromptType)
 		return textResult("Prompt %q updated.", input.PromptType)
 	})
 
@@ -390,13 +797,13 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err := hs.engine.ResetPrompt(userID, input.PromptType); err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("prompt_reset: type=%s", input.PromptType)
+		logTool("prompt_reset", "prompt_type", input.PromptType)
 		return textResult("Prompt %q reset to default.", input.PromptType)
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "briefing",
-		Description: "Generate a markdown briefing from high-interest unread articles. Includes titles, scores, URLs, and AI summaries.",
+		Description: "Generate a markdown briefing from unread articles at or above the user's notify_min_score. Includes titles, scores, URLs, and AI summaries. When notify_when is \"queue\", the articles held since the last briefing lead the list and the queue is cleared.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input speakerOnlyInput) (*mcp.CallToolResult, any, error) {
 		userID := hs.resolveUser(ptrStr(input.Speaker))
 		briefing, err := hs.engine.GenerateBriefing(userID)
@@ -406,10 +813,66 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if briefing == "" {
 			return textResult("No high-interest unread articles for a briefing.")
 		}
-		log.Printf("briefing: generated")
+		logTool("briefing")
+		return textResult("%s", briefing)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "combined_briefing",
+		Description: "Generate one markdown briefing for several users, e.g. a household's shared display. Merges each user's unread articles at or above their notify_min_score, lists an article flagged by more than one user once, and names who flagged each. Notification queues are left untouched.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input combinedBriefingInput) (*mcp.CallToolResult, any, error) {
+		if len(input.Speakers) == 0 {
+			return errResult("speakers is required")
+		}
+		userIDs := make([]int64, 0, len(input.Speakers))
+		for _, name := range input.Speakers {
+			id, err := hs.engine.ResolveUser(name)
+			if err != nil || id == 0 {
+				return errResult("unknown speaker %q", name)
+			}
+			userIDs = append(userIDs, id)
+		}
+		briefing, err := hs.engine.GenerateCombinedBriefing(ctx, userIDs)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		if briefing == "" {
+			return textResult("No high-interest unread articles for a briefing.")
+		}
+		logTool("combined_briefing", "users", len(userIDs))
 		return textResult("%s", briefing)
 	})
 
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "notifications_present",
+		Description: "For a user whose notify_when is \"present\", return high-interest unread articles at or above notify_min_score that have not been surfaced before, highest score first. Each returned article is marked as presented and will not be returned again, so call this at the start of a conversation turn to mention new articles without repeating old ones. Returns nothing for other notify_when modes.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input notificationsPresentInput) (*mcp.CallToolResult, any, error) {
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		limit := 5
+		if input.Limit != nil && *input.Limit > 0 {
+			limit = *input.Limit
+		}
+		prefs, err := hs.engine.GetPreferences(userID)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		if prefs.NotifyWhen != "present" {
+			return textResult("notify_when is %q; articles are only surfaced here in \"present\" mode.", prefs.NotifyWhen)
+		}
+		presented, err := hs.engine.PresentNotifications(userID, limit)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("notifications_present", "limit", limit, "results", len(presented))
+		if len(presented) == 0 {
+			return textResult("No new high-interest articles to present.")
+		}
+		for i := range presented {
+			presented[i].Content = ""
+		}
+		return jsonResult(presented)
+	})
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "article_star",
 		Description: "Set or clear the starred flag on an article. Starred articles are saved for later reference.",
@@ -428,10 +891,81 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if !*input.Starred {
 			action = "unstarred"
 		}
-		log.Printf("article_star: id=%d %s", input.ArticleID, action)
+		logTool("article_star", "article_id", input.ArticleID, "action", action)
 		return textResult("Article %d %s.", input.ArticleID, action)
 	})
 
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "article_note_set",
+		Description: "Attach a private note to an article, replacing any existing note. An empty note deletes it. Notes are per-user and are returned by articles_get.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articleNoteSetInput) (*mcp.CallToolResult, any, error) {
+		if input.ArticleID == 0 {
+			return errResult("article_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.SetArticleNote(userID, input.ArticleID, input.Note); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("article_note_set", "article_id", input.ArticleID, "note_length", len(input.Note))
+		if strings.TrimSpace(input.Note) == "" {
+			return textResult("Note on article %d deleted.", input.ArticleID)
+		}
+		return textResult("Note on article %d saved.", input.ArticleID)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "article_resummarize",
+		Description: "Discard an article's cached AI summary and generate a new one using the current summarization prompt and summary preferences. Returns the new summary.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articleResummarizeInput) (*mcp.CallToolResult, any, error) {
+		if input.ArticleID == 0 {
+			return errResult("article_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		summary, err := hs.engine.RegenerateSummary(ctx, userID, input.ArticleID)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("article_resummarize", "article_id", input.ArticleID, "summary_length", len(summary))
+		return textResult("%s", summary)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "article_unblock",
+		Description: "Override a security-check block on an article (see articles_blocked) that you judge to be a false positive. The article is summarized, scored for interest and grouped, then appears in the unread list. The override is recorded on the article.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articleUnblockInput) (*mcp.CallToolResult, any, error) {
+		if input.ArticleID == 0 {
+			return errResult("article_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		if err := hs.engine.OverrideSecurity(ctx, userID, input.ArticleID); err != nil {
+			return errResult("%v", err)
+		}
+		logTool("article_unblock", "article_id", input.ArticleID)
+		return textResult("Article %d unblocked and scored.", input.ArticleID)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "ai_status",
+		Description: "Check the AI backend: whether it is configured, which security and curation models it uses, and whether the endpoint has both models available. When ready is false, error explains what is missing, such as a model that needs to be pulled.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input speakerOnlyInput) (*mcp.CallToolResult, any, error) {
+		status := hs.engine.AIStatus(ctx)
+		logTool("ai_status", "ready", status.Ready)
+		return jsonResult(status)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "articles_rescore",
+		Description: "Re-run interest scoring on the user's unread articles with their current keywords. Use after changing keywords or interest_threshold so already-scored articles are re-ranked. Does not re-run the security check or regenerate summaries. Returns the number of articles rescored.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input speakerOnlyInput) (*mcp.CallToolResult, any, error) {
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		n, err := hs.engine.RescoreUnread(ctx, userID)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("articles_rescore", "rescored", n)
+		return textResult("Rescored %d unread articles.", n)
+	})
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "filter_rules_list",
 		Description: "List filter rules for the user. Optionally filter by feed_id to see rules scoped to a specific feed plus global rules.",
@@ -441,13 +975,13 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("filter_rules_list: %d rules", len(rules))
+		logTool("filter_rules_list", "rules", len(rules))
 		return jsonResult(rules)
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "filter_rule_add",
-		Description: "Add a filter rule. Rules score articles by author, category, or tag. Positive scores boost, negative penalize. Use feed_metadata to discover available values first.",
+		Description: "Add a filter rule. Rules score articles by author, category, tag, domain (matching subdomains), title (case-insensitive substring), or lang (detected language code, e.g. de). Positive scores boost, negative penalize; block=true hides matching articles entirely. Use feed_metadata to discover available values first.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input filterRuleAddInput) (*mcp.CallToolResult, any, error) {
 		if input.Axis == "" {
 			return errResult("axis parameter is required")
@@ -461,13 +995,14 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 			Axis:   input.Axis,
 			Value:  input.Value,
 			Score:  input.Score,
+			Block:  input.Block,
 		}
 		id, err := hs.engine.AddFilterRule(userID, rule)
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("filter_rule_add: id=%d axis=%s value=%q score=%d", id, input.Axis, input.Value, input.Score)
-		return jsonResult(map[string]any{"id": id, "axis": input.Axis, "value": input.Value, "score": input.Score})
+		logTool("filter_rule_add", "rule_id", id, "axis", input.Axis, "value", input.Value, "score", input.Score, "block", input.Block)
+		return jsonResult(map[string]any{"id": id, "axis": input.Axis, "value": input.Value, "score": input.Score, "block": input.Block})
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
@@ -480,7 +1015,7 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err := hs.engine.UpdateFilterRule(input.RuleID, input.Score); err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("filter_rule_update: id=%d score=%d", input.RuleID, input.Score)
+		logTool("filter_rule_update", "rule_id", input.RuleID, "score", input.Score)
 		return textResult("Filter rule %d updated to score %d.", input.RuleID, input.Score)
 	})
 
@@ -494,10 +1029,59 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err := hs.engine.DeleteFilterRule(input.RuleID); err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("filter_rule_delete: id=%d", input.RuleID)
+		logTool("filter_rule_delete", "rule_id", input.RuleID)
 		return textResult("Filter rule %d deleted.", input.RuleID)
 	})
 
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "filter_rules_import",
+		Description: "Add a batch of filter rules, such as the output of filter_rules_export from another user or machine. Rules that duplicate an existing one are skipped; an invalid rule rejects the whole batch. Feed-scoped rules keep their feed_id, which only matches the same feed in the same database. Returns the added and skipped counts.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input filterRulesImportInput) (*mcp.CallToolResult, any, error) {
+		if input.Rules == "" {
+			return errResult("rules parameter is required")
+		}
+		var rules []herald.FilterRule
+		if err := json.Unmarshal([]byte(input.Rules), &rules); err != nil {
+			return errResult("rules must be a JSON array of filter rules: %v", err)
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		added, skipped, err := hs.engine.ImportFilterRules(userID, rules)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("filter_rules_import", "added", added, "skipped", skipped)
+		return jsonResult(map[string]int{"added": added, "skipped": skipped})
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "filter_rules_export",
+		Description: "Export the user's filter rules as a JSON array that filter_rules_import accepts, without rule IDs or timestamps.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input filterRulesExportInput) (*mcp.CallToolResult, any, error) {
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		data, err := hs.engine.ExportFilterRules(userID)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("filter_rules_export")
+		return textResult("%s", data)
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "article_explain",
+		Description: "Explain how filter rules score an article: each matching rule (axis, value, score, block), the total, the user's threshold, and whether the article is visible. Use this to tune filter rules and thresholds.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input articleExplainInput) (*mcp.CallToolResult, any, error) {
+		if input.ArticleID == 0 {
+			return errResult("article_id parameter is required")
+		}
+		userID := hs.resolveUser(ptrStr(input.Speaker))
+		ex, err := hs.engine.ExplainArticleScore(userID, input.ArticleID)
+		if err != nil {
+			return errResult("%v", err)
+		}
+		logTool("article_explain", "article_id", input.ArticleID, "matches", len(ex.Matches), "total", ex.Total)
+		return jsonResult(ex)
+	})
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "feed_metadata",
 		Description: "Discover authors and categories from a feed's articles. Use this to find values for creating filter rules. Requires feed_id from feeds_list.",
@@ -509,7 +1093,7 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("feed_metadata: feed_id=%d authors=%d categories=%d", input.FeedID, len(meta.Authors), len(meta.Categories))
+		logTool("feed_metadata", "feed_id", input.FeedID, "authors", len(meta.Authors), "categories", len(meta.Categories))
 		return jsonResult(meta)
 	})
 
@@ -524,7 +1108,7 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("user_register: name=%q id=%d", input.Name, id)
+		logTool("user_register", "user_id", id, "name", input.Name)
 		return jsonResult(map[string]any{"id": id, "name": input.Name})
 	})
 
@@ -536,7 +1120,7 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("user_list: %d users", len(users))
+		logTool("user_list", "users", len(users))
 		return jsonResult(users)
 	})
 
@@ -560,7 +1144,7 @@ func registerTools(s *mcp.Server, hs *heraldServer) {
 		if err != nil {
 			return errResult("%v", err)
 		}
-		log.Printf("search: q=%q results=%d", input.Query, len(results))
+		logTool("search", "query", input.Query, "results", len(results))
 		return jsonResult(results)
 	})
 }
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -156,15 +158,17 @@ func TestToolsList(t *testing.T) {
 	}
 
 	expected := []string{
-		"articles_unread", "articles_get", "articles_mark_read",
-		"feeds_list", "feed_subscribe", "feed_unsubscribe", "feed_rename",
-		"article_groups", "article_group_get", "feed_stats", "poll_now",
+		"articles_unread", "articles_ungrouped", "articles_blocked", "articles_read_history", "articles_since", "articles_get", "articles_mark_read",
+		"feeds_list", "feed_subscribe", "feed_unsubscribe", "feed_resubscribe", "feed_rename",
+		"feed_dedupe", "feed_max_articles", "feed_content_source", "feed_strip_patterns", "feed_set_headers", "feed_mute", "opml_import",
+		"article_groups", "article_group_get", "feed_stats", "reading_stats", "poll_now", "poll_history",
+		"group_rename", "group_merge", "group_resummarize", "group_delete", "group_mark_read", "article_move_group",
 		"preferences_get", "preference_set",
 		"prompts_list", "prompt_get", "prompt_set", "prompt_reset",
-		"briefing", "article_star",
+		"briefing", "combined_briefing", "notifications_present", "article_star", "article_note_set", "article_resummarize", "article_unblock", "articles_rescore", "ai_status",
 		"user_register", "user_list",
 		"filter_rules_list", "filter_rule_add", "filter_rule_update",
-		"filter_rule_delete", "feed_metadata", "search",
+		"filter_rule_delete", "filter_rules_import", "filter_rules_export", "article_explain", "feed_metadata", "search",
 	}
 	if len(result.Tools) != len(expected) {
 		t.Fatalf("got %d tools, want %d", len(result.Tools), len(expected))
@@ -226,6 +230,46 @@ func TestFeedSubscribeMissingURL(t *testing.T) {
 	expectError(t, session, "feed_subscribe", map[string]any{})
 }
 
+func TestOPMLImport(t *testing.T) {
+	_, session := newTestSession(t)
+
+	inline := `<?xml version="1.0"?><opml version="2.0"><body>
+<outline text="Folder"><outline text="A" type="rss" xmlUrl="https://example.com/a.xml"/></outline>
+</body></opml>`
+	result := mustCallTool(t, session, "opml_import", map[string]any{"content": inline})
+	if result.IsError {
+		t.Fatalf("inline import error: %s", resultText(t, result))
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/x-opml")
+		fmt.Fprint(w, `<?xml version="1.0"?><opml version="2.0"><body>
+<outline text="B" type="rss" xmlUrl="https://example.com/b.xml"/>
+</body></opml>`)
+	}))
+	t.Cleanup(ts.Close)
+	result = mustCallTool(t, session, "opml_import", map[string]any{"url": ts.URL + "/feeds.opml"})
+	if result.IsError {
+		t.Fatalf("url import error: %s", resultText(t, result))
+	}
+
+	result = mustCallTool(t, session, "feeds_list", map[string]any{})
+	var feeds []struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, result)), &feeds); err != nil {
+		t.Fatalf("unmarshal feeds: %v", err)
+	}
+	if len(feeds) != 2 {
+		t.Fatalf("got %d feeds, want 2", len(feeds))
+	}
+
+	// Neither or both sources is an error, as is a URL that isn't OPML.
+	expectError(t, session, "opml_import", map[string]any{})
+	expectError(t, session, "opml_import", map[string]any{"content": inline, "url": ts.URL})
+	expectError(t, session, "opml_import", map[string]any{"url": feedServer(t).URL + "/feed.xml"})
+}
+
 func TestFeedRename(t *testing.T) {
 	_, session := newTestSession(t)
 	ts := feedServer(t)
@@ -261,6 +305,134 @@ func TestFeedRenameMissingParams(t *testing.T) {
 	expectError(t, session, "feed_rename", map[string]any{"title": "Foo"})
 }
 
+func TestFeedDedupe(t *testing.T) {
+	_, session := newTestSession(t)
+	ts := feedServer(t)
+	feedID := subscribeFeed(t, session, ts.URL+"/feed.xml")
+
+	result := mustCallTool(t, session, "feed_dedupe", map[string]any{"feed_id": feedID, "by_url": true})
+	if result.IsError {
+		t.Fatalf("feed_dedupe error: %s", resultText(t, result))
+	}
+	if text := resultText(t, result); !strings.Contains(text, "by URL") {
+		t.Errorf("unexpected result: %s", text)
+	}
+
+	expectError(t, session, "feed_dedupe", map[string]any{"by_url": true})
+	expectError(t, session, "feed_dedupe", map[string]any{"feed_id": 99999, "by_url": true})
+}
+
+func TestFeedMaxArticles(t *testing.T) {
+	_, session := newTestSession(t)
+	ts := feedServer(t)
+	feedID := subscribeFeed(t, session, ts.URL+"/feed.xml")
+
+	result := mustCallTool(t, session, "feed_max_articles", map[string]any{"feed_id": feedID, "max_articles": 50})
+	if result.IsError {
+		t.Fatalf("feed_max_articles error: %s", resultText(t, result))
+	}
+	if text := resultText(t, result); !strings.Contains(text, "50 newest") {
+		t.Errorf("unexpected result: %s", text)
+	}
+
+	expectError(t, session, "feed_max_articles", map[string]any{"max_articles": 50})
+	expectError(t, session, "feed_max_articles", map[string]any{"feed_id": feedID, "max_articles": -1})
+	expectError(t, session, "feed_max_articles", map[string]any{"feed_id": 99999, "max_articles": 50})
+}
+
+func TestFeedContentSource(t *testing.T) {
+	_, session := newTestSession(t)
+	ts := feedServer(t)
+	feedID := subscribeFeed(t, session, ts.URL+"/feed.xml")
+
+	result := mustCallTool(t, session, "feed_content_source", map[string]any{"feed_id": feedID, "source": "summary"})
+	if result.IsError {
+		t.Fatalf("feed_content_source error: %s", resultText(t, result))
+	}
+	if text := resultText(t, result); !strings.Contains(text, "from summary") {
+		t.Errorf("unexpected result: %s", text)
+	}
+
+	expectError(t, session, "feed_content_source", map[string]any{"source": "summary"})
+	expectError(t, session, "feed_content_source", map[string]any{"feed_id": feedID, "source": "description"})
+	expectError(t, session, "feed_content_source", map[string]any{"feed_id": 99999, "source": "longest"})
+}
+
+func TestFeedSetHeaders(t *testing.T) {
+	hs, session := newTestSession(t)
+	ts := feedServer(t)
+	feedID := subscribeFeed(t, session, ts.URL+"/feed.xml")
+
+	result := mustCallTool(t, session, "feed_set_headers", map[string]any{
+		"feed_id": feedID,
+		"headers": map[string]string{"authorization": "Bearer s3cret"},
+	})
+	if result.IsError {
+		t.Fatalf("feed_set_headers error: %s", resultText(t, result))
+	}
+	if text := resultText(t, result); strings.Contains(text, "s3cret") {
+		t.Errorf("result echoes the header value: %s", text)
+	}
+	headers, err := hs.engine.GetFeedFetchHeaders(hs.resolveUser(""), feedID)
+	if err != nil {
+		t.Fatalf("GetFeedFetchHeaders: %v", err)
+	}
+	if headers["Authorization"] != "Bearer s3cret" || len(headers) != 1 {
+		t.Errorf("headers = %v", headers)
+	}
+
+	result = mustCallTool(t, session, "feed_set_headers", map[string]any{"feed_id": feedID, "headers": map[string]string{}})
+	if text := resultText(t, result); !strings.Contains(text, "Cleared") {
+		t.Errorf("unexpected result: %s", text)
+	}
+
+	expectError(t, session, "feed_set_headers", map[string]any{"headers": map[string]string{"X-Key": "v"}})
+	expectError(t, session, "feed_set_headers", map[string]any{"feed_id": feedID, "headers": map[string]string{"Bad Name": "v"}})
+	expectError(t, session, "feed_set_headers", map[string]any{"feed_id": 99999, "headers": map[string]string{"X-Key": "v"}})
+}
+
+func TestFeedsListReportsFetchErrors(t *testing.T) {
+	hs, session := newTestSession(t)
+	var failing atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "upstream down", http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, testRSS)
+	}))
+	t.Cleanup(ts.Close)
+	feedID := subscribeFeed(t, session, ts.URL+"/feed.xml")
+
+	failing.Store(true)
+	if _, err := hs.engine.FetchAllFeeds(context.Background()); err != nil {
+		t.Fatalf("FetchAllFeeds: %v", err)
+	}
+
+	result := mustCallTool(t, session, "feeds_list", map[string]any{})
+	var feeds []map[string]any
+	if err := json.Unmarshal([]byte(resultText(t, result)), &feeds); err != nil {
+		t.Fatalf("unmarshal feeds: %v", err)
+	}
+	if len(feeds) != 1 || feeds[0]["id"] != float64(feedID) {
+		t.Fatalf("feeds = %v, want the one subscribed feed", feeds)
+	}
+	f := feeds[0]
+	if msg, _ := f["last_error"].(string); !strings.Contains(msg, "502") {
+		t.Errorf("last_error = %v, want the HTTP 502 failure", f["last_error"])
+	}
+	if f["consecutive_errors"] != float64(1) {
+		t.Errorf("consecutive_errors = %v, want 1", f["consecutive_errors"])
+	}
+	if f["enabled"] != true || f["status"] != "active" {
+		t.Errorf("enabled/status = %v/%v, want true/active", f["enabled"], f["status"])
+	}
+	if _, ok := f["last_fetched"]; !ok {
+		t.Error("last_fetched missing; the initial subscribe fetch succeeded")
+	}
+}
+
 func TestFeedUnsubscribe(t *testing.T) {
 	_, session := newTestSession(t)
 	ts := feedServer(t)
@@ -282,6 +454,21 @@ func TestFeedUnsubscribe(t *testing.T) {
 	if len(remaining) != 0 {
 		t.Errorf("expected 0 feeds after unsubscribe, got %d", len(remaining))
 	}
+
+	// Undo within the grace period
+	result = mustCallTool(t, session, "feed_resubscribe", map[string]any{
+		"feed_id": feedID,
+	})
+	if result.IsError {
+		t.Fatalf("resubscribe error: %s", resultText(t, result))
+	}
+	result = mustCallTool(t, session, "feeds_list", map[string]any{})
+	json.Unmarshal([]byte(resultText(t, result)), &remaining)
+	if len(remaining) != 1 {
+		t.Errorf("expected 1 feed after resubscribe, got %d", len(remaining))
+	}
+
+	expectError(t, session, "feed_resubscribe", map[string]any{"feed_id": 99999})
 }
 
 func TestArticlesUnreadEmpty(t *testing.T) {
@@ -349,6 +536,16 @@ func TestArticlesGetAndMarkRead(t *testing.T) {
 	if result.IsError {
 		t.Fatalf("articles_mark_read error: %s", resultText(t, result))
 	}
+
+	// The read article shows up in history.
+	result = mustCallTool(t, session, "articles_read_history", map[string]any{"limit": 5})
+	var history []struct {
+		ID int64 `json:"id"`
+	}
+	json.Unmarshal([]byte(resultText(t, result)), &history)
+	if len(history) != 1 || history[0].ID != articleID {
+		t.Errorf("read history = %v, want article %d", history, articleID)
+	}
 }
 
 func TestArticlesGetMissingID(t *testing.T) {
@@ -361,6 +558,39 @@ func TestArticlesMarkReadMissingID(t *testing.T) {
 	expectError(t, session, "articles_mark_read", map[string]any{})
 }
 
+func TestArticlesUngroupedEmpty(t *testing.T) {
+	_, session := newTestSession(t)
+	result := mustCallTool(t, session, "articles_ungrouped", map[string]any{"limit": 5})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+}
+
+func TestArticlesBlockedEmpty(t *testing.T) {
+	_, session := newTestSession(t)
+	result := mustCallTool(t, session, "articles_blocked", map[string]any{"limit": 5})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+}
+
+func TestArticleUnblockErrors(t *testing.T) {
+	_, session := newTestSession(t)
+	expectError(t, session, "article_unblock", map[string]any{})
+	expectError(t, session, "article_unblock", map[string]any{"article_id": 99999})
+}
+
+func TestAIStatusUnconfigured(t *testing.T) {
+	_, session := newTestSession(t)
+	result := mustCallTool(t, session, "ai_status", map[string]any{})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if text := resultText(t, result); !strings.Contains(text, `"ready": false`) && !strings.Contains(text, `"ready":false`) {
+		t.Errorf("ai_status without AI should not be ready: %s", text)
+	}
+}
+
 func TestArticleGroupsEmpty(t *testing.T) {
 	_, session := newTestSession(t)
 	result := mustCallTool(t, session, "article_groups", map[string]any{})
@@ -374,6 +604,20 @@ func TestArticleGroupGetMissingID(t *testing.T) {
 	expectError(t, session, "article_group_get", map[string]any{})
 }
 
+func TestGroupEditMissingOrUnknown(t *testing.T) {
+	_, session := newTestSession(t)
+	expectError(t, session, "group_rename", map[string]any{"group_id": 1})
+	expectError(t, session, "group_rename", map[string]any{"group_id": 999, "name": "Nope"})
+	expectError(t, session, "group_merge", map[string]any{"source_group_id": 1})
+	expectError(t, session, "group_merge", map[string]any{"source_group_id": 998, "target_group_id": 999})
+	expectError(t, session, "group_resummarize", map[string]any{})
+	expectError(t, session, "group_resummarize", map[string]any{"group_id": 999})
+	expectError(t, session, "group_delete", map[string]any{})
+	expectError(t, session, "group_delete", map[string]any{"group_id": 999})
+	expectError(t, session, "article_move_group", map[string]any{})
+	expectError(t, session, "article_move_group", map[string]any{"article_id": 999})
+}
+
 func TestFeedStats(t *testing.T) {
 	_, session := newTestSession(t)
 	result := mustCallTool(t, session, "feed_stats", map[string]any{})
@@ -382,6 +626,26 @@ func TestFeedStats(t *testing.T) {
 	}
 }
 
+func TestReadingStats(t *testing.T) {
+	_, session := newTestSession(t)
+	result := mustCallTool(t, session, "reading_stats", map[string]any{"days": 3})
+	if result.IsError {
+		t.Fatalf("reading_stats error: %s", resultText(t, result))
+	}
+	var stats struct {
+		Read  int `json:"read"`
+		Daily []struct {
+			Date string `json:"date"`
+		} `json:"daily"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, result)), &stats); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if stats.Read != 0 || len(stats.Daily) != 3 {
+		t.Errorf("got read=%d over %d days, want 0 over 3", stats.Read, len(stats.Daily))
+	}
+}
+
 func TestFeedStatsWithFeed(t *testing.T) {
 	_, session := newTestSession(t)
 	ts := feedServer(t)
@@ -436,7 +700,7 @@ func TestPollNowEnabled(t *testing.T) {
 	subscribeFeed(t, session, ts.URL+"/feed.xml")
 
 	// Attach a poller
-	p := newPoller(hs.engine, hs.userID, 10*time.Minute, 8.0)
+	p := newPoller(hs.engine, hs.userID, 10*time.Minute)
 	hs.poller = p
 
 	result := mustCallTool(t, session, "poll_now", map[string]any{})
@@ -455,6 +719,141 @@ func TestPollNowEnabled(t *testing.T) {
 	if pollResult.FeedsTotal == 0 {
 		t.Error("expected non-zero feeds_total")
 	}
+
+	result = mustCallTool(t, session, "poll_history", map[string]any{})
+	if result.IsError {
+		t.Fatalf("poll_history error: %s", resultText(t, result))
+	}
+	var runs []struct {
+		FeedsTotal  int `json:"feeds_total"`
+		NewArticles int `json:"new_articles"`
+	}
+	if err := json.Unmarshal([]byte(resultText(t, result)), &runs); err != nil {
+		t.Fatalf("unmarshal poll history: %v", err)
+	}
+	if len(runs) != 1 || runs[0].FeedsTotal != pollResult.FeedsTotal {
+		t.Errorf("poll history = %+v, want the one poll just run", runs)
+	}
+}
+
+func TestPollProcessesAllUsers(t *testing.T) {
+	// Each request serves a new item, so the poll after subscribing has
+	// fresh articles to process.
+	var hits atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>%[1]s</title>
+<item><title>Item %[2]d</title><link>https://example.com%[1]s/%[2]d</link><guid>%[1]s-%[2]d</guid><description>Short.</description></item>
+</channel></rss>`, r.URL.Path, n)
+	}))
+	t.Cleanup(ts.Close)
+
+	// An AI processor is needed for processing to run; articles this short
+	// are marked processed without calling the model.
+	engine, err := herald.NewEngine(herald.EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: "http://127.0.0.1:1",
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	t.Cleanup(func() { engine.Close() })
+
+	users := map[string]int64{}
+	for _, name := range []string{"alice", "bob"} {
+		id, err := engine.RegisterUser(name)
+		if err != nil {
+			t.Fatalf("RegisterUser: %v", err)
+		}
+		users[name] = id
+		if err := engine.SubscribeFeed(id, ts.URL+"/"+name+".xml", ""); err != nil {
+			t.Fatalf("SubscribeFeed(%s): %v", name, err)
+		}
+	}
+
+	p := newPoller(engine, users["alice"], 10*time.Minute)
+	result, err := p.poll(context.Background())
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if result.NewArticles != 2 {
+		t.Fatalf("new articles = %d, want 2", result.NewArticles)
+	}
+	for name, id := range users {
+		_, unscored, err := engine.PendingCounts(id)
+		if err != nil {
+			t.Fatalf("PendingCounts(%s): %v", name, err)
+		}
+		if unscored != 0 {
+			t.Errorf("%s has %d unprocessed articles after the poll", name, unscored)
+		}
+	}
+}
+
+func TestPollFetchesAllFeedsBeforeProcessing(t *testing.T) {
+	// The AI endpoint records when it is first called; its errors leave
+	// articles unscored, which is fine for this test.
+	var firstAI atomic.Int64
+	aiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		firstAI.CompareAndSwap(0, time.Now().UnixNano())
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(aiServer.Close)
+
+	// Each request serves a new item long enough to reach the model. The
+	// slow feed finishes well after the fast one, so processing the fast
+	// feed's articles early would call the model before it is served.
+	body := strings.Repeat("Long enough to be worth scoring. ", 10)
+	var hits atomic.Int32
+	var slowServed atomic.Int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		if r.URL.Path == "/slow.xml" {
+			time.Sleep(300 * time.Millisecond)
+			defer slowServed.Store(time.Now().UnixNano())
+		}
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>%[1]s</title>
+<item><title>Item %[2]d</title><link>https://example.com%[1]s/%[2]d</link><guid>%[1]s-%[2]d</guid><description>%[3]s</description></item>
+</channel></rss>`, r.URL.Path, n, body)
+	}))
+	t.Cleanup(ts.Close)
+
+	engine, err := herald.NewEngine(herald.EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: aiServer.URL,
+		MaxParallel:   4,
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	t.Cleanup(func() { engine.Close() })
+
+	userID, err := engine.RegisterUser("alice")
+	if err != nil {
+		t.Fatalf("RegisterUser: %v", err)
+	}
+	for _, path := range []string{"/fast.xml", "/slow.xml"} {
+		if err := engine.SubscribeFeed(userID, ts.URL+path, ""); err != nil {
+			t.Fatalf("SubscribeFeed(%s): %v", path, err)
+		}
+	}
+	firstAI.Store(0)
+
+	p := newPoller(engine, userID, 10*time.Minute)
+	result, err := p.poll(context.Background())
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if result.NewArticles != 2 {
+		t.Fatalf("new articles = %d, want 2", result.NewArticles)
+	}
+	if firstAI.Load() == 0 {
+		t.Fatal("poll never called the model")
+	}
+	if firstAI.Load() < slowServed.Load() {
+		t.Errorf("model called %v before the slow feed was fetched",
+			time.Duration(slowServed.Load()-firstAI.Load()))
+	}
 }
 
 func TestFeedUnsubscribeMissingID(t *testing.T) {
@@ -522,7 +921,7 @@ func TestPreferenceSetAndGet(t *testing.T) {
 		t.Fatalf("unmarshal preferences: %v", err)
 	}
 
-	if len(prefs.Keywords) != 2 || prefs.Keywords[0] != "security" || prefs.Keywords[1] != "golang" {
+	if len(prefs.Keywords) != 2 || prefs.Keywords[0].Term != "security" || prefs.Keywords[1].Term != "golang" {
 		t.Errorf("keywords = %v, want [security golang]", prefs.Keywords)
 	}
 	if prefs.InterestThreshold != 6.5 {
@@ -545,6 +944,12 @@ func TestPreferenceSetValidation(t *testing.T) {
 		{"keywords not JSON", "keywords", "not-json"},
 		{"threshold not number", "interest_threshold", "abc"},
 		{"notify_when invalid", "notify_when", "never"},
+		{"summary_max_words negative", "summary_max_words", "-1"},
+		{"summary_style invalid", "summary_style", "haiku"},
+		{"timezone invalid", "timezone", "Mars/Olympus_Mons"},
+		{"webhook not http", "notify_webhook_url", "ftp://example.com/hook"},
+		{"date_format invalid", "date_format", "sometimes"},
+		{"sanitize_policy invalid", "sanitize_policy", "none"},
 		{"missing key", "", "value"},
 		{"missing value", "keywords", ""},
 	}
@@ -576,8 +981,8 @@ func TestPromptsListDefaults(t *testing.T) {
 	if err := json.Unmarshal([]byte(text), &prompts); err != nil {
 		t.Fatalf("unmarshal prompts: %v", err)
 	}
-	if len(prompts) != 4 {
-		t.Fatalf("got %d prompt types, want 4", len(prompts))
+	if len(prompts) != 5 {
+		t.Fatalf("got %d prompt types, want 5", len(prompts))
 	}
 
 	for _, p := range prompts {
@@ -787,6 +1192,33 @@ func TestBriefingEmpty(t *testing.T) {
 	}
 }
 
+func TestCombinedBriefing(t *testing.T) {
+	hs, session := newTestSession(t)
+	for _, name := range []string{"alice", "bob"} {
+		if _, err := hs.engine.RegisterUser(name); err != nil {
+			t.Fatalf("RegisterUser(%s): %v", name, err)
+		}
+	}
+
+	result := mustCallTool(t, session, "combined_briefing", map[string]any{"speakers": []string{"alice", "bob"}})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if text := resultText(t, result); !strings.Contains(text, "No high-interest") {
+		t.Errorf("expected empty-briefing message, got %q", text)
+	}
+
+	result = mustCallTool(t, session, "combined_briefing", map[string]any{"speakers": []string{"alice", "nobody"}})
+	if !result.IsError || !strings.Contains(resultText(t, result), "nobody") {
+		t.Errorf("expected unknown speaker error, got %q", resultText(t, result))
+	}
+
+	result = mustCallTool(t, session, "combined_briefing", map[string]any{"speakers": []string{}})
+	if !result.IsError {
+		t.Error("expected error for empty speakers")
+	}
+}
+
 // --- Article star tests ---
 
 func TestArticleStarAndUnstar(t *testing.T) {
@@ -1033,6 +1465,12 @@ func TestFilterRuleAddValidation(t *testing.T) {
 	}
 }
 
+func TestArticleExplainValidation(t *testing.T) {
+	_, session := newTestSession(t)
+	expectError(t, session, "article_explain", map[string]any{})
+	expectError(t, session, "article_explain", map[string]any{"article_id": 9999})
+}
+
 func TestFilterRuleUpdate(t *testing.T) {
 	_, session := newTestSession(t)
 
@@ -1110,6 +1548,45 @@ func TestFilterRuleDelete(t *testing.T) {
 	}
 }
 
+func TestFilterRulesImportExport(t *testing.T) {
+	_, session := newTestSession(t)
+
+	mustCallTool(t, session, "filter_rule_add", map[string]any{
+		"axis": "tag", "value": "golang", "score": 2,
+	})
+	result := mustCallTool(t, session, "filter_rules_import", map[string]any{
+		"rules": `[{"axis":"tag","value":"golang","score":2},{"axis":"author","value":"Bob","score":-1}]`,
+	})
+	if result.IsError {
+		t.Fatalf("import error: %s", resultText(t, result))
+	}
+	var counts struct {
+		Added   int `json:"added"`
+		Skipped int `json:"skipped"`
+	}
+	json.Unmarshal([]byte(resultText(t, result)), &counts)
+	if counts.Added != 1 || counts.Skipped != 1 {
+		t.Errorf("added %d skipped %d, want 1 and 1", counts.Added, counts.Skipped)
+	}
+
+	result = mustCallTool(t, session, "filter_rules_export", map[string]any{})
+	var exported []map[string]any
+	if err := json.Unmarshal([]byte(resultText(t, result)), &exported); err != nil {
+		t.Fatalf("export is not JSON: %v", err)
+	}
+	if len(exported) != 2 {
+		t.Errorf("expected 2 exported rules, got %d", len(exported))
+	}
+	if _, ok := exported[0]["id"]; ok {
+		t.Error("export should leave out rule IDs")
+	}
+
+	result = mustCallTool(t, session, "filter_rules_import", map[string]any{"rules": "not json"})
+	if !result.IsError {
+		t.Error("expected an error for malformed rules")
+	}
+}
+
 func TestFeedMetadata(t *testing.T) {
 	_, session := newTestSession(t)
 	ts := feedServer(t)
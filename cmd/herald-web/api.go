@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	herald "github.com/matthewjhunter/herald"
+)
+
+// The /api/v1 routes serve JSON versions of the HTML views for custom
+// frontends. They share the HTML UI's cookie auth and act on the signed-in
+// user; unauthenticated requests get a 401 instead of a login redirect.
+
+// apiArticleList is the response body for GET /api/v1/articles.
+type apiArticleList struct {
+	Articles   []herald.Article `json:"articles"`
+	HasMore    bool             `json:"has_more"`
+	NextOffset int              `json:"next_offset,omitempty"`
+}
+
+// apiFeed is a subscription with its article counts.
+type apiFeed struct {
+	herald.Feed
+	TotalArticles  int `json:"total_articles"`
+	UnreadArticles int `json:"unread_articles"`
+}
+
+// apiError is the body of every non-2xx API response.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeAPIJSON(w, status, apiError{Error: msg})
+}
+
+// writeAPIFailed is writeFailed for the JSON API.
+func writeAPIFailed(w http.ResponseWriter, err error, msg string) {
+	if errors.Is(err, herald.ErrReadOnly) {
+		w.Header().Set(readOnlyHeader, "1")
+		writeAPIError(w, http.StatusForbidden, "Herald is running in read-only mode")
+		return
+	}
+	writeAPIError(w, http.StatusInternalServerError, msg)
+}
+
+// handleAPIArticles lists articles. With no filter it returns unread articles;
+// feed_id, group_id and starred=1 narrow it the same way the HTML list does.
+// Content is omitted; fetch a single article for the full text.
+func (h *handlers) handleAPIArticles(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	limit := parseIntParam(r, "limit", 30)
+	offset := parseIntParam(r, "offset", 0)
+	feedID := parseInt64Param(r, "feed_id")
+	groupID := parseInt64Param(r, "group_id")
+	order := r.URL.Query().Get("order")
+	if err := herald.ValidateArticleOrder(order); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var articles []herald.Article
+	var err error
+	switch {
+	case r.URL.Query().Get("starred") == "1":
+		articles, err = h.engine.GetStarredArticles(uid, limit+1, offset)
+	case groupID > 0:
+		articles, err = h.engine.GetUnreadGroupArticles(uid, groupID, limit+1, offset)
+	case feedID > 0:
+		articles, err = h.engine.GetUnreadArticlesByFeed(uid, feedID, limit+1, offset)
+	default:
+		articles, err = h.engine.GetUnreadArticlesOrdered(uid, limit+1, offset, order)
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "Failed to load articles")
+		return
+	}
+
+	resp := apiArticleList{Articles: articles}
+	if len(articles) > limit {
+		resp.Articles = articles[:limit]
+		resp.HasMore = true
+		resp.NextOffset = offset + limit
+	}
+	if resp.Articles == nil {
+		resp.Articles = []herald.Article{}
+	}
+	for i := range resp.Articles {
+		resp.Articles[i].Content = ""
+		resp.Articles[i].LinkedContent = ""
+	}
+	writeAPIJSON(w, http.StatusOK, resp)
+}
+
+// handleAPIArticle returns one article with content, AI summary and note.
+// Unlike the HTML view it does not mark the article read.
+func (h *handlers) handleAPIArticle(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "Invalid article ID")
+		return
+	}
+	article, err := h.engine.GetArticleForUser(uid, articleID)
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, "Article not found")
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, article)
+}
+
+func (h *handlers) handleAPIFeeds(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	feeds, err := h.engine.GetUserFeeds(uid)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "Failed to load feeds")
+		return
+	}
+	counts := make(map[int64]herald.FeedStats)
+	if stats, err := h.engine.GetFeedStats(uid); err == nil && stats != nil {
+		for _, fs := range stats.Feeds {
+			counts[fs.FeedID] = fs
+		}
+	}
+
+	out := make([]apiFeed, len(feeds))
+	for i, f := range feeds {
+		out[i] = apiFeed{
+			Feed:           f,
+			TotalArticles:  counts[f.ID].TotalArticles,
+			UnreadArticles: counts[f.ID].UnreadArticles,
+		}
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]any{"feeds": out})
+}
+
+func (h *handlers) handleAPIGroups(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	groups, err := h.engine.GetGroupStats(uid)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "Failed to load groups")
+		return
+	}
+	if groups == nil {
+		groups = []herald.GroupStats{}
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]any{"groups": groups})
+}
+
+// handleAPIArticleRead marks an article read, or unread with {"read": false}.
+func (h *handlers) handleAPIArticleRead(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Read *bool `json:"read"`
+	}
+	uid, articleID, ok := h.apiArticleAction(w, r, &body)
+	if !ok {
+		return
+	}
+	read := body.Read == nil || *body.Read
+
+	var err error
+	if read {
+		err = h.engine.MarkArticleRead(uid, articleID)
+	} else {
+		err = h.engine.MarkArticleUnread(uid, articleID)
+	}
+	if err != nil {
+		writeAPIFailed(w, err, "Failed to update read state")
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]any{"id": articleID, "read": read})
+}
+
+// handleAPIArticleStar stars an article, or unstars it with {"starred": false}.
+func (h *handlers) handleAPIArticleStar(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Starred *bool `json:"starred"`
+	}
+	uid, articleID, ok := h.apiArticleAction(w, r, &body)
+	if !ok {
+		return
+	}
+	starred := body.Starred == nil || *body.Starred
+
+	if err := h.engine.StarArticle(uid, articleID, starred); err != nil {
+		writeAPIFailed(w, err, "Failed to update star")
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]any{"id": articleID, "starred": starred})
+}
+
+// apiArticleAction does the common work of the per-article POST endpoints:
+// parse the article ID, check the article exists, and decode the optional
+// JSON body into body. It writes the error response itself and reports
+// whether the caller should continue.
+func (h *handlers) apiArticleAction(w http.ResponseWriter, r *http.Request, body any) (uid, articleID int64, ok bool) {
+	uid = userFromContext(r.Context()).ID
+	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "Invalid article ID")
+		return 0, 0, false
+	}
+	if _, err := h.engine.GetArticle(articleID); err != nil {
+		writeAPIError(w, http.StatusNotFound, "Article not found")
+		return 0, 0, false
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(body); err != nil && !errors.Is(err, io.EOF) {
+		writeAPIError(w, http.StatusBadRequest, "Invalid JSON body")
+		return 0, 0, false
+	}
+	return uid, articleID, true
+}
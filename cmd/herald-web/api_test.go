@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// apiRequest sends an authenticated API request with an optional JSON body.
+func apiRequest(t *testing.T, tf *testFixtures, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.AddCookie(&http.Cookie{Name: "test_jwt", Value: tf.jwtToken})
+	rr := httptest.NewRecorder()
+	tf.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestAPIArticles(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	rr := apiRequest(t, tf, "GET", "/api/v1/articles", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v\n%s", err, rr.Body.String())
+	}
+	for _, key := range []string{"articles", "has_more"} {
+		if _, ok := resp[key]; !ok {
+			t.Errorf("response missing %q: %s", key, rr.Body.String())
+		}
+	}
+
+	var articles []map[string]any
+	if err := json.Unmarshal(resp["articles"], &articles); err != nil {
+		t.Fatalf("decode articles: %v", err)
+	}
+	if len(articles) != 1 {
+		t.Fatalf("got %d articles, want 1", len(articles))
+	}
+	a := articles[0]
+	if a["id"] != float64(tf.articleID) || a["title"] != "Test Article" || a["feed_id"] != float64(tf.feedID) {
+		t.Errorf("article = %v, want id/title/feed_id of the fixture", a)
+	}
+	if a["content"] != "" {
+		t.Errorf("list content = %q, want it omitted", a["content"])
+	}
+
+	// An empty page is an empty array, not null.
+	rr = apiRequest(t, tf, "GET", "/api/v1/articles?offset=10", "")
+	if !strings.Contains(rr.Body.String(), `"articles":[]`) {
+		t.Errorf("empty page should encode articles as []: %s", rr.Body.String())
+	}
+}
+
+func TestAPIArticleActions(t *testing.T) {
+	tf := newTestFixtures(t)
+	base := "/api/v1/articles/" + itoa(tf.articleID)
+
+	rr := apiRequest(t, tf, "POST", base+"/star", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("star status: got %d, want %d: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	rr = apiRequest(t, tf, "GET", "/api/v1/articles?starred=1", "")
+	if !strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("starred list should contain the starred article")
+	}
+
+	rr = apiRequest(t, tf, "POST", base+"/star", `{"starred": false}`)
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"starred":false`) {
+		t.Errorf("unstar: got %d %s", rr.Code, rr.Body.String())
+	}
+
+	rr = apiRequest(t, tf, "POST", base+"/read", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("read status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	rr = apiRequest(t, tf, "GET", "/api/v1/articles", "")
+	if strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("read article should drop out of the unread list")
+	}
+
+	rr = apiRequest(t, tf, "POST", base+"/read", `{"read": false}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unread status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	rr = apiRequest(t, tf, "GET", "/api/v1/articles", "")
+	if !strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("article marked unread should be back in the unread list")
+	}
+
+	rr = apiRequest(t, tf, "POST", "/api/v1/articles/999999/read", "")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("missing article: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	rr = apiRequest(t, tf, "POST", base+"/star", `{not json`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("bad body: got %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAPIFeedsAndGroups(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	rr := apiRequest(t, tf, "GET", "/api/v1/feeds", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("feeds status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	var feeds struct {
+		Feeds []struct {
+			ID             int64  `json:"id"`
+			Title          string `json:"title"`
+			UnreadArticles int    `json:"unread_articles"`
+		} `json:"feeds"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &feeds); err != nil {
+		t.Fatalf("decode feeds: %v", err)
+	}
+	if len(feeds.Feeds) != 1 || feeds.Feeds[0].ID != tf.feedID || feeds.Feeds[0].UnreadArticles != 1 {
+		t.Errorf("feeds = %+v, want the fixture feed with 1 unread", feeds.Feeds)
+	}
+
+	rr = apiRequest(t, tf, "GET", "/api/v1/groups", "")
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"groups":[]`) {
+		t.Errorf("groups: got %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAPIUnauthenticated(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	rr := request(t, tf.router, "GET", "/api/v1/articles", nil)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -18,10 +20,37 @@ type AdminConfig struct {
 // Config holds all herald-web configuration. Values are loaded from a TOML
 // file and may be overridden by CLI flags.
 type Config struct {
-	DB      string        `toml:"db"`
-	Addr    string        `toml:"addr"`
-	Webauth WebauthConfig `toml:"webauth"`
-	Admin   AdminConfig   `toml:"admin"`
+	DB          string          `toml:"db"`
+	BusyTimeout time.Duration   `toml:"busy_timeout"` // SQLite lock wait; default 15s
+	JournalMode string          `toml:"journal_mode"` // SQLite journal mode; default WAL
+	ReadOnly    bool            `toml:"read_only"`    // open the database read-only
+	Addr        string          `toml:"addr"`
+	LogFormat   string          `toml:"log_format"` // text (default) or json
+	LogLevel    string          `toml:"log_level"`  // debug, info (default), warn or error
+	AccessLog   AccessLogConfig `toml:"access_log"`
+	Metrics     MetricsConfig   `toml:"metrics"`
+	Webauth     WebauthConfig   `toml:"webauth"`
+	Admin       AdminConfig     `toml:"admin"`
+}
+
+// MetricsConfig controls the Prometheus /metrics endpoint.
+type MetricsConfig struct {
+	// Token is the bearer token a scraper must send. The endpoint is not
+	// served when it is empty.
+	Token string `toml:"token"`
+}
+
+// AccessLogConfig controls the per-request access log.
+type AccessLogConfig struct {
+	// Off disables the access log.
+	Off bool `toml:"off"`
+	// SampleRate is the fraction of requests logged, from 0 to 1. Unset or
+	// 0 logs every request.
+	SampleRate float64 `toml:"sample_rate"`
+	// Exclude lists paths that are never logged, each covering everything
+	// beneath it. Defaults to /healthz and /metrics; an empty list logs all
+	// paths.
+	Exclude []string `toml:"exclude"`
 }
 
 // WebauthConfig holds webauth OIDC settings.
@@ -58,6 +87,38 @@ func loadConfig(path string) (Config, error) {
 	return cfg, nil
 }
 
+// accessLogSettings merges the -access-log flag over the config file's
+// access_log section. The flag is "on", "off" or a sample rate between 0
+// and 1; empty defers to the config.
+func accessLogSettings(flagVal string, cfg AccessLogConfig) (accessLogOptions, error) {
+	opts := accessLogOptions{SampleRate: 1, Exclude: cfg.Exclude}
+	if opts.Exclude == nil {
+		opts.Exclude = defaultAccessLogExclude
+	}
+	switch {
+	case cfg.Off:
+		opts.SampleRate = 0
+	case cfg.SampleRate < 0 || cfg.SampleRate > 1:
+		return opts, fmt.Errorf("access_log.sample_rate must be between 0 and 1, got %v", cfg.SampleRate)
+	case cfg.SampleRate > 0:
+		opts.SampleRate = cfg.SampleRate
+	}
+	switch flagVal {
+	case "":
+	case "on":
+		opts.SampleRate = 1
+	case "off":
+		opts.SampleRate = 0
+	default:
+		rate, err := strconv.ParseFloat(flagVal, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return opts, fmt.Errorf("-access-log must be on, off or a sample rate between 0 and 1, got %q", flagVal)
+		}
+		opts.SampleRate = rate
+	}
+	return opts, nil
+}
+
 // mergeString returns flag if non-empty, otherwise falls back to the config value.
 func mergeString(flag, cfgVal string) string {
 	if flag != "" {
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/microcosm-cc/bluemonday"
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
 )
@@ -60,6 +65,20 @@ func processNode(n *html.Node, seen map[string]bool) {
 		stripImageAttrs(n)
 	}
 
+	// Iframes only survive sanitization as sandboxed video players. Drop any
+	// whose source was stripped, and give the rest the player sandbox.
+	if n.Type == html.ElementNode && n.DataAtom == atom.Iframe {
+		if !embedSrc.MatchString(getAttr(n, "src")) {
+			n.Type = html.TextNode
+			n.Data = ""
+			n.DataAtom = 0
+			n.Attr = nil
+			n.FirstChild, n.LastChild = nil, nil
+			return
+		}
+		setAttr(n, "sandbox", embedSandbox)
+	}
+
 	// Always open links in a new tab; only the user (ctrl-click etc.) can override
 	if n.Type == html.ElementNode && n.DataAtom == atom.A {
 		setAttr(n, "target", "_blank")
@@ -142,10 +161,12 @@ func getAttr(n *html.Node, key string) string {
 }
 
 // rewriteImageURLs replaces <img src="originalURL"> with <img src="/images/{id}">
-// for every URL present in imageMap. URLs not in the map are left unchanged,
-// so articles with partially cached images still display what's available.
-func rewriteImageURLs(content string, imageMap map[string]int64) string {
-	if len(imageMap) == 0 || content == "" {
+// for every URL present in imageMap. Other URLs are passed to proxy, when
+// non-nil, and replaced by its result unless that is empty; otherwise they
+// are left unchanged, so articles with partially cached images still
+// display what's available.
+func rewriteImageURLs(content string, imageMap map[string]int64, proxy func(src string) string) string {
+	if (len(imageMap) == 0 && proxy == nil) || content == "" {
 		return content
 	}
 
@@ -161,6 +182,10 @@ func rewriteImageURLs(content string, imageMap map[string]int64) string {
 				if a.Key == "src" {
 					if id, ok := imageMap[a.Val]; ok {
 						n.Attr[i].Val = fmt.Sprintf("/images/%d", id)
+					} else if proxy != nil {
+						if p := proxy(a.Val); p != "" {
+							n.Attr[i].Val = p
+						}
 					}
 					break
 				}
@@ -194,3 +219,106 @@ func findBody(n *html.Node) *html.Node {
 	}
 	return nil
 }
+
+// imageSignature is the hex HMAC-SHA256 binding imgURL to articleID.
+func imageSignature(key []byte, articleID int64, imgURL string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strconv.FormatInt(articleID, 10) + "\n" + imgURL))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signImageURL returns the /img proxy path for an article image. The
+// signature keeps the proxy from fetching URLs herald didn't emit itself.
+func signImageURL(key []byte, articleID int64, imgURL string) string {
+	q := url.Values{}
+	q.Set("a", strconv.FormatInt(articleID, 10))
+	q.Set("u", imgURL)
+	q.Set("s", imageSignature(key, articleID, imgURL))
+	return "/img?" + q.Encode()
+}
+
+// validImageSignature reports whether sig was produced by signImageURL.
+func validImageSignature(key []byte, articleID int64, imgURL, sig string) bool {
+	want := imageSignature(key, articleID, imgURL)
+	return hmac.Equal([]byte(sig), []byte(want))
+}
+
+// imageProxyFunc returns a rewriteImageURLs proxy that resolves src against
+// the article's base URL and signs http(s) results. Anything else, such
+// as data: URIs, is left alone.
+func imageProxyFunc(key []byte, articleID int64, base string) func(string) string {
+	baseURL, _ := url.Parse(base)
+	return func(src string) string {
+		u, err := url.Parse(strings.TrimSpace(src))
+		if err != nil {
+			return ""
+		}
+		if baseURL != nil {
+			u = baseURL.ResolveReference(u)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ""
+		}
+		return signImageURL(key, articleID, u.String())
+	}
+}
+
+// Article content sanitization policies, selected by the sanitize_policy
+// preference. "ugc" is the default.
+const (
+	policyUGC     = "ugc"
+	policyStrict  = "strict"
+	policyRelaxed = "relaxed"
+)
+
+// newSanitizePolicies builds every sanitization policy once; bluemonday
+// policies are safe for concurrent use once built.
+func newSanitizePolicies() map[string]*bluemonday.Policy {
+	return map[string]*bluemonday.Policy{
+		policyUGC:     bluemonday.UGCPolicy(),
+		policyStrict:  strictPolicy(),
+		policyRelaxed: relaxedPolicy(),
+	}
+}
+
+// strictPolicy is the UGC policy without images, so articles never load
+// remote media. bluemonday can't remove elements from a policy, so this
+// rebuilds UGC's text, link, list and table rules without AllowImages.
+func strictPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowStandardAttributes()
+	p.AllowStandardURLs()
+	p.AllowAttrs("href").OnElements("a")
+	p.RequireNoFollowOnLinks(true)
+	p.AllowElements("p", "br", "hr", "div", "span",
+		"h1", "h2", "h3", "h4", "h5", "h6",
+		"b", "strong", "i", "em", "u", "s", "strike", "del", "ins", "mark", "small", "sub", "sup",
+		"abbr", "cite", "q", "blockquote", "pre", "code", "kbd", "samp", "var",
+		"figure", "figcaption", "details", "summary")
+	p.AllowAttrs("cite").OnElements("blockquote", "q", "del", "ins")
+	p.AllowLists()
+	p.AllowTables()
+	return p
+}
+
+// embedSrc matches the iframe sources relaxedPolicy allows: YouTube and
+// Vimeo video players.
+var embedSrc = regexp.MustCompile(`^https://(?:(?:www\.)?youtube(?:-nocookie)?\.com/embed/|player\.vimeo\.com/video/)[\w-]+(?:[?#].*)?$`)
+
+// embedSandbox is the sandbox given to allowed video player iframes: enough
+// for the player to run, but no top-level navigation, forms or downloads.
+const embedSandbox = "allow-scripts allow-same-origin allow-presentation allow-popups"
+
+// relaxedPolicy extends UGC with embedded video, audio and YouTube or Vimeo
+// player iframes, which UGC drops. Every iframe is sandboxed.
+func relaxedPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowElements("video", "audio", "source", "iframe")
+	p.AllowAttrs("src").OnElements("video", "audio", "source")
+	p.AllowAttrs("src").Matching(embedSrc).OnElements("iframe")
+	p.AllowAttrs("type").OnElements("source")
+	p.AllowAttrs("controls", "poster", "loop", "muted", "width", "height").OnElements("video", "audio")
+	p.AllowAttrs("width", "height", "allowfullscreen", "loading").OnElements("iframe")
+	p.RequireSandboxOnIFrame()
+	return p
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"html"
 	"strings"
 	"testing"
 )
@@ -106,7 +107,7 @@ func TestNormalizeContent_OverridesFeedTarget(t *testing.T) {
 func TestRewriteImageURLs_ReplacesKnownURL(t *testing.T) {
 	imageMap := map[string]int64{"https://example.com/photo.jpg": 42}
 	input := `<p>Text</p><img src="https://example.com/photo.jpg"/>`
-	got := rewriteImageURLs(input, imageMap)
+	got := rewriteImageURLs(input, imageMap, nil)
 	if !strings.Contains(got, `/images/42`) {
 		t.Errorf("expected /images/42 in output, got:\n%s", got)
 	}
@@ -118,7 +119,7 @@ func TestRewriteImageURLs_ReplacesKnownURL(t *testing.T) {
 func TestRewriteImageURLs_PreservesUnknownURL(t *testing.T) {
 	imageMap := map[string]int64{"https://example.com/other.jpg": 99}
 	input := `<img src="https://example.com/photo.jpg"/>`
-	got := rewriteImageURLs(input, imageMap)
+	got := rewriteImageURLs(input, imageMap, nil)
 	if !strings.Contains(got, "photo.jpg") {
 		t.Errorf("unknown URL should be preserved, got:\n%s", got)
 	}
@@ -126,7 +127,7 @@ func TestRewriteImageURLs_PreservesUnknownURL(t *testing.T) {
 
 func TestRewriteImageURLs_EmptyMap(t *testing.T) {
 	input := `<img src="https://example.com/photo.jpg"/>`
-	got := rewriteImageURLs(input, nil)
+	got := rewriteImageURLs(input, nil, nil)
 	if got != input {
 		t.Errorf("empty map should return input unchanged")
 	}
@@ -138,8 +139,89 @@ func TestRewriteImageURLs_MultipleImages(t *testing.T) {
 		"https://example.com/b.jpg": 2,
 	}
 	input := `<img src="https://example.com/a.jpg"/><img src="https://example.com/b.jpg"/>`
-	got := rewriteImageURLs(input, imageMap)
+	got := rewriteImageURLs(input, imageMap, nil)
 	if !strings.Contains(got, "/images/1") || !strings.Contains(got, "/images/2") {
 		t.Errorf("expected both images rewritten, got:\n%s", got)
 	}
 }
+
+func TestRewriteImageURLs_Proxy(t *testing.T) {
+	key := []byte("test-key")
+	imageMap := map[string]int64{"https://example.com/cached.jpg": 7}
+	input := `<img src="https://example.com/cached.jpg"/><img src="/pics/new.jpg"/><img src="data:image/gif;base64,R0lGOD"/>`
+	got := rewriteImageURLs(input, imageMap, imageProxyFunc(key, 3, "https://example.com/posts/1"))
+	if !strings.Contains(got, "/images/7") {
+		t.Errorf("cached image should use /images/7, got:\n%s", got)
+	}
+	want := signImageURL(key, 3, "https://example.com/pics/new.jpg")
+	if !strings.Contains(got, html.EscapeString(want)) {
+		t.Errorf("relative image should be proxied as %s, got:\n%s", want, got)
+	}
+	if !strings.Contains(got, "data:image/gif") {
+		t.Errorf("data: image should be left alone, got:\n%s", got)
+	}
+	if !validImageSignature(key, 3, "https://example.com/pics/new.jpg", imageSignature(key, 3, "https://example.com/pics/new.jpg")) {
+		t.Error("signature should verify")
+	}
+	if validImageSignature(key, 4, "https://example.com/pics/new.jpg", imageSignature(key, 3, "https://example.com/pics/new.jpg")) {
+		t.Error("signature should not verify for another article")
+	}
+}
+
+func TestSanitizePolicies(t *testing.T) {
+	policies := newSanitizePolicies()
+	input := `<p>Text</p><img src="https://example.com/photo.jpg" alt="photo"><iframe src="https://www.youtube.com/embed/x"></iframe>`
+
+	tests := []struct {
+		policy     string
+		wantImg    bool
+		wantIframe bool
+	}{
+		{policyUGC, true, false},
+		{policyStrict, false, false},
+		{policyRelaxed, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			got := policies[tt.policy].Sanitize(input)
+			if !strings.Contains(got, "<p>Text</p>") {
+				t.Errorf("text should survive every policy, got %q", got)
+			}
+			if has := strings.Contains(got, "<img"); has != tt.wantImg {
+				t.Errorf("<img> kept = %v, want %v: %q", has, tt.wantImg, got)
+			}
+			if has := strings.Contains(got, "<iframe"); has != tt.wantIframe {
+				t.Errorf("<iframe> kept = %v, want %v: %q", has, tt.wantIframe, got)
+			}
+		})
+	}
+}
+
+func TestRelaxedPolicyIframes(t *testing.T) {
+	policy := newSanitizePolicies()[policyRelaxed]
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"youtube", `<iframe src="https://www.youtube.com/embed/abc_123"></iframe>`, true},
+		{"vimeo", `<iframe src="https://player.vimeo.com/video/42" sandbox="allow-top-navigation"></iframe>`, true},
+		{"other host", `<iframe src="https://evil.example.com/embed/x"></iframe>`, false},
+		{"plain http", `<iframe src="http://www.youtube.com/embed/abc"></iframe>`, false},
+		{"no src", `<iframe width="640"></iframe>`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeContent(policy.Sanitize(tt.input))
+			if has := strings.Contains(got, "<iframe"); has != tt.want {
+				t.Fatalf("<iframe> kept = %v, want %v: %q", has, tt.want, got)
+			}
+			if tt.want && !strings.Contains(got, `sandbox="`+embedSandbox+`"`) {
+				t.Errorf("kept iframe should carry the player sandbox: %q", got)
+			}
+			if strings.Contains(got, "allow-top-navigation") {
+				t.Errorf("feed-supplied sandbox values should be dropped: %q", got)
+			}
+		})
+	}
+}
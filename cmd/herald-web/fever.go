@@ -4,7 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -117,12 +117,12 @@ func (h *handlers) feverMark(userID int64, r *http.Request) {
 			err = h.engine.StarArticle(userID, id, false)
 		}
 		if err != nil {
-			log.Printf("fever: mark item %d as=%q user=%d: %v", id, as, userID, err)
+			slog.Warn("fever: mark item failed", "article_id", id, "as", as, "user_id", userID, "err", err)
 		}
 	case "feed":
 		if as == "read" {
 			if err := h.engine.FeverMarkFeedRead(userID, id, before); err != nil {
-				log.Printf("fever: mark feed %d read user=%d: %v", id, userID, err)
+				slog.Warn("fever: mark feed read failed", "feed_id", id, "user_id", userID, "err", err)
 			}
 		}
 	case "group":
@@ -135,7 +135,7 @@ func (h *handlers) feverMark(userID int64, r *http.Request) {
 				err = h.engine.FeverMarkGroupRead(userID, id, before)
 			}
 			if err != nil {
-				log.Printf("fever: mark group %d read user=%d: %v", id, userID, err)
+				slog.Warn("fever: mark group read failed", "group_id", id, "user_id", userID, "err", err)
 			}
 		}
 	}
@@ -178,12 +178,16 @@ func (h *handlers) feverAddFeeds(userID int64, resp map[string]any) {
 		if faviconFeeds[f.ID] {
 			faviconID = f.ID // favicon_id == feed_id in herald
 		}
+		siteURL := f.SiteURL
+		if siteURL == "" {
+			siteURL = f.URL
+		}
 		ff[i] = feverFeed{
 			ID:                f.ID,
 			FaviconID:         faviconID,
 			Title:             f.Title,
 			URL:               f.URL,
-			SiteURL:           f.URL, // no separate site URL stored
+			SiteURL:           siteURL,
 			LastUpdatedOnTime: lastUpdated,
 		}
 	}
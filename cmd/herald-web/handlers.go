@@ -3,21 +3,26 @@ package main
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"io/fs"
-	"log"
+	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/infodancer/oidclient"
 	herald "github.com/matthewjhunter/herald"
+	"github.com/matthewjhunter/herald/internal/langdetect"
 	"github.com/matthewjhunter/herald/internal/storage"
 	"github.com/microcosm-cc/bluemonday"
 )
@@ -27,9 +32,12 @@ type handlers struct {
 	engine     *herald.Engine
 	validator  *oidclient.Client
 	pages      map[string]*template.Template // per-page template sets
-	policy     *bluemonday.Policy
-	adminRole  string   // JWT role value that grants admin access (default: "admin")
-	adminUsers []string // fallback email list when the IdP does not issue role claims
+	policy     *bluemonday.Policy            // default (UGC) policy for newsletters and unset preferences
+	policies   map[string]*bluemonday.Policy // article policies keyed by sanitize_policy value
+	imageKey   []byte                        // HMAC key for /img proxy URLs, random per process
+	adminRole  string                        // JWT role value that grants admin access (default: "admin")
+	adminUsers []string                      // fallback email list when the IdP does not issue role claims
+	metricsKey string                        // bearer token for /metrics; empty disables the endpoint
 }
 
 // isAdminCtx reports whether the request context carries admin privileges.
@@ -79,7 +87,7 @@ type promptUIEntry struct {
 }
 
 // promptTypeOrder defines the display order for prompt types in the UI.
-var promptTypeOrder = []string{"curation", "summarization", "group_summary", "related_groups", "newsletter"}
+var promptTypeOrder = []string{"curation", "summarization", "group_summary", "related_groups", "newsletter", "briefing"}
 
 var promptLabels = map[string]string{
 	"curation":       "Article Curation",
@@ -87,6 +95,7 @@ var promptLabels = map[string]string{
 	"group_summary":  "Group Summary",
 	"related_groups": "Related Groups",
 	"newsletter":     "Newsletter",
+	"briefing":       "Briefing Layout",
 }
 
 // loadPromptEntries builds the UI entry list for a given userID.
@@ -158,7 +167,7 @@ func (h *handlers) init() {
 	shared := []string{"base.html", "nav.html", "settings_subnav.html", "feed_sidebar.html", "article_list.html", "article_row.html", "article_view.html", "search_results.html", "newsletter_view.html", "error.html"}
 
 	// Pages that get their own template tree.
-	pages := []string{"home.html", "feeds_manage.html", "settings.html", "settings_sync.html", "settings_prompts.html", "filters.html", "admin_prompts.html", "admin_stats.html", "stats.html", "newsletters_manage.html"}
+	pages := []string{"home.html", "feeds_manage.html", "settings.html", "settings_sync.html", "settings_prompts.html", "filters.html", "admin_prompts.html", "admin_stats.html", "stats.html", "status.html", "newsletters_manage.html", "article_reader.html"}
 
 	h.pages = make(map[string]*template.Template, len(pages))
 	for _, page := range pages {
@@ -167,7 +176,27 @@ func (h *handlers) init() {
 		h.pages[page] = t
 	}
 
-	h.policy = bluemonday.UGCPolicy()
+	h.policies = newSanitizePolicies()
+	h.policy = h.policies[policyUGC]
+
+	h.imageKey = make([]byte, 32)
+	if _, err := rand.Read(h.imageKey); err != nil {
+		panic("image proxy key: " + err.Error())
+	}
+}
+
+// policyFor returns the article sanitization policy the user's
+// sanitize_policy preference selects, falling back to UGC.
+func (h *handlers) policyFor(uid int64) *bluemonday.Policy {
+	h.init()
+	prefs, err := h.engine.GetPreferences(uid)
+	if err != nil {
+		return h.policy
+	}
+	if p, ok := h.policies[prefs.SanitizePolicy]; ok {
+		return p
+	}
+	return h.policy
 }
 
 // --- Template data types ---
@@ -182,6 +211,49 @@ type homeData struct {
 	ActiveGroup      int64
 	ActiveNewsletter int64
 	ActiveStarred    bool
+	ActiveUngrouped  bool
+	ActiveNew        bool
+	ActiveQueue      bool
+	ActiveBlocked    bool
+	ActiveHistory    bool
+	Reading          *readingCard // this week's reading, shown in the empty reading pane
+}
+
+// readingCard is the home page's weekly reading summary.
+type readingCard struct {
+	Read         int
+	Starred      int
+	HighInterest int
+	Days         []readingDay
+}
+
+type readingDay struct {
+	Date  string
+	Label string // weekday abbreviation
+	Count int
+	Pct   int // bar height relative to the busiest day
+}
+
+// readingStatsDays is how many days, counting today, the home page card covers.
+const readingStatsDays = 7
+
+func newReadingCard(stats *herald.ReadingStats) *readingCard {
+	card := &readingCard{Read: stats.Read, Starred: stats.Starred, HighInterest: stats.HighInterest}
+	peak := 0
+	for _, d := range stats.Daily {
+		peak = max(peak, d.Count)
+	}
+	for _, d := range stats.Daily {
+		day := readingDay{Date: d.Date, Count: d.Count}
+		if t, err := time.Parse(time.DateOnly, d.Date); err == nil {
+			day.Label = t.Format("Mon")
+		}
+		if peak > 0 {
+			day.Pct = d.Count * 100 / peak
+		}
+		card.Days = append(card.Days, day)
+	}
+	return card
 }
 
 type articleListData struct {
@@ -190,9 +262,20 @@ type articleListData struct {
 	NextOffset    int
 	FeedID        int64
 	GroupID       int64
+	GroupName     string
 	GroupHeadline string
 	GroupSummary  string
+	MergeTargets  []herald.ArticleGroup // other groups this one can be merged into
+	Category      string                // feed-supplied category filter
 	Starred       bool
+	Ungrouped     bool
+	MinScore      float64
+	NewSince      string // set on the "new since last visit" list
+	Queue         bool   // the reading queue, paged via /queue
+	Blocked       bool   // articles held back by the security check, paged via /blocked
+	History       bool   // recently read articles, paged via /history
+	Order         string // unread-list order; empty for the default, newest first
+	ShowOrder     bool   // render the order picker (first page of the unread list)
 }
 
 type articleRow struct {
@@ -201,8 +284,16 @@ type articleRow struct {
 	Author           string
 	FeedTitle        string
 	PublishedDateFmt string
+	ReadingTime      int // estimated minutes; 0 when the word count is unknown
 	Read             bool
 	Starred          bool
+	HasScore         bool
+	Score            float64 // time-decayed interest score
+	RawScore         float64 // interest score as originally assigned
+	Blocked          bool    // held back by the security check
+	SecurityScore    float64 // set when Blocked
+	SecurityReason   string  // the check's rationale, when recorded
+	OpenCount        int     // times the user opened the article; set in history
 }
 
 type searchResultsData struct {
@@ -214,21 +305,45 @@ type searchResultsData struct {
 
 type articleViewData struct {
 	ID                     int64
+	Slug                   string // permalink key; empty for articles without one
 	Title                  string
 	Author                 string
+	Authors                []string // feed-supplied author list; replaces Author when set
+	Categories             []string // feed-supplied categories, linking to their article lists
 	FeedTitle              string
 	URL                    string
 	PublishedDateFmt       string
+	ReadingTime            int
 	AISummary              string
 	SanitizedContent       template.HTML
 	Starred                bool
 	LinkedURL              string
 	LinkedDomain           string
 	SanitizedLinkedContent template.HTML
+	Lang                   string // detected language; empty when unknown
+	Note                   string
+	InterestReason         string   // curation's justification, shown as "Surfaced because"
+	Tags                   []string // curation-suggested topic tags
+	GroupID                int64
+	GroupOptions           []groupOption
+}
+
+// groupOption is one entry in the article view's "move to group" dropdown.
+type groupOption struct {
+	ID   int64
+	Name string
 }
 
 type feedManageData struct {
 	Feeds []feedRow
+	Query string    // title/URL filter applied to Feeds
+	Undo  *feedUndo // set right after an unsubscribe to offer an undo toast
+}
+
+// feedUndo identifies a just-unsubscribed feed for the undo toast.
+type feedUndo struct {
+	FeedID int64
+	Title  string
 }
 
 type feedRow struct {
@@ -236,19 +351,36 @@ type feedRow struct {
 	Title                string
 	URL                  string
 	SiteURL              string
+	Language             string // feed-declared language
+	Generator            string // software that produced the feed
 	TotalArticles        int
 	UnreadArticles       int
 	UnsummarizedArticles int
 	LastError            string
 	LastFetchedFmt       string
 	LastPostDateFmt      string
+	HeaderNames          []string // custom fetch headers; values are never rendered
+}
+
+// feedHeadersData drives the feed_headers fragment.
+type feedHeadersData struct {
+	FeedID int64
+	Names  []string
+	Error  string
 }
 
 type settingsData struct {
 	Keywords          string
+	AvoidKeywords     string
 	InterestThreshold float64
 	NotifyWhen        string
 	NotifyMinScore    float64
+	AutostarMinScore  float64
+	SummaryMaxWords   int
+	SummaryStyle      string
+	Timezone          string
+	DateFormat        string
+	SanitizePolicy    string
 	IsAdmin           bool
 }
 
@@ -269,6 +401,7 @@ type filtersData struct {
 	Rules           []filterRuleRow
 	Feeds           []herald.Feed
 	IsAdmin         bool
+	ImportResult    string // outcome of a rules import, shown above the table
 }
 
 type filterRuleRow struct {
@@ -276,6 +409,7 @@ type filterRuleRow struct {
 	Axis      string
 	Value     string
 	Score     int
+	Block     bool
 	FeedTitle string
 }
 
@@ -298,7 +432,7 @@ func (h *handlers) renderPage(w http.ResponseWriter, r *http.Request, name strin
 	// Look up the per-page template tree
 	t, ok := h.pages[name]
 	if !ok {
-		log.Printf("herald-web: unknown page template: %s", name)
+		slog.Error("unknown page template", "template", name)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -306,7 +440,7 @@ func (h *handlers) renderPage(w http.ResponseWriter, r *http.Request, name strin
 	// Render full page with base layout
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	if err := t.ExecuteTemplate(w, "base.html", data); err != nil {
-		log.Printf("herald-web: template error: %v", err)
+		slog.Error("template error", "template", name, "err", err)
 	}
 }
 
@@ -318,12 +452,12 @@ func (h *handlers) renderFragment(w http.ResponseWriter, name string, data any)
 	for _, t := range h.pages {
 		if tmpl := t.Lookup(name); tmpl != nil {
 			if err := tmpl.Execute(w, data); err != nil {
-				log.Printf("herald-web: template error: %v", err)
+				slog.Error("template error", "template", name, "err", err)
 			}
 			return
 		}
 	}
-	log.Printf("herald-web: unknown fragment template: %s", name)
+	slog.Error("unknown fragment template", "template", name)
 	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 }
 
@@ -340,6 +474,21 @@ func (h *handlers) renderError(w http.ResponseWriter, status int, msg string) {
 	}
 }
 
+// readOnlyHeader marks responses refused because the database is read-only,
+// so herald.js can tell the user rather than failing silently.
+const readOnlyHeader = "X-Herald-Read-Only"
+
+// writeFailed reports a failed write. A read-only database gets a 403 with
+// readOnlyHeader set; anything else is a 500 with msg.
+func writeFailed(w http.ResponseWriter, err error, msg string) {
+	if errors.Is(err, herald.ErrReadOnly) {
+		w.Header().Set(readOnlyHeader, "1")
+		http.Error(w, "Herald is running in read-only mode", http.StatusForbidden)
+		return
+	}
+	http.Error(w, msg, http.StatusInternalServerError)
+}
+
 // trailingURLRe matches a separator followed by a bare URL at the end of a string.
 // Used to strip tweet URLs appended to Instapundit-style RSS titles.
 var trailingURLRe = regexp.MustCompile(`[:\s]+(https?://\S+)\s*$`)
@@ -367,7 +516,43 @@ func bestDate(published, fetched *time.Time) *time.Time {
 	return fetched
 }
 
-func formatDate(t *time.Time) string {
+// dateFormatter renders dates for one request in the user's timezone and
+// preferred style.
+type dateFormatter struct {
+	loc      *time.Location
+	absolute bool
+}
+
+// dateFormatterFor loads the user's timezone and date_format preferences. An
+// unset timezone uses the server's local time; an invalid one falls back to
+// UTC.
+func (h *handlers) dateFormatterFor(uid int64) dateFormatter {
+	df := dateFormatter{loc: time.Local}
+	prefs, err := h.engine.GetPreferences(uid)
+	if err != nil {
+		return df
+	}
+	if prefs.Timezone != "" {
+		loc, err := time.LoadLocation(prefs.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		df.loc = loc
+	}
+	df.absolute = prefs.DateFormat == "absolute"
+	return df
+}
+
+func (df dateFormatter) format(t *time.Time) string {
+	if df.absolute {
+		return formatAbsoluteDateIn(t, df.loc)
+	}
+	return formatDateIn(t, df.loc)
+}
+
+// formatDateIn renders t relative to now when recent, and as a calendar date
+// in loc otherwise.
+func formatDateIn(t *time.Time, loc *time.Location) string {
 	if t == nil {
 		return ""
 	}
@@ -381,8 +566,16 @@ func formatDate(t *time.Time) string {
 	case diff < 7*24*time.Hour:
 		return fmt.Sprintf("%dd ago", int(diff.Hours()/24))
 	default:
-		return t.Format("Jan 2, 2006")
+		return t.In(loc).Format("Jan 2, 2006")
+	}
+}
+
+// formatAbsoluteDateIn renders t as a date and time in loc.
+func formatAbsoluteDateIn(t *time.Time, loc *time.Location) string {
+	if t == nil {
+		return ""
 	}
+	return t.In(loc).Format("Jan 2, 2006 3:04 PM")
 }
 
 func parseIntParam(r *http.Request, name string, defaultVal int) int {
@@ -408,6 +601,80 @@ func parseInt64Param(r *http.Request, name string) int64 {
 
 // --- Full-page handlers ---
 
+// handleHealthz is the liveness/readiness probe. It returns 503 when the
+// database does not answer.
+func (h *handlers) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	resp := struct {
+		Status  string `json:"status"`
+		DBOK    bool   `json:"db_ok"`
+		Version string `json:"version"`
+	}{Status: "ok", DBOK: true, Version: version}
+
+	status := http.StatusOK
+	if err := h.engine.Ping(); err != nil {
+		slog.Warn("healthz: database ping failed", "err", err)
+		resp.Status = "unavailable"
+		resp.DBOK = false
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp) //nolint:errcheck
+}
+
+// handleMetrics serves Prometheus text exposition to scrapers presenting the
+// configured bearer token; without one configured the endpoint is not
+// served. Everything is read from the database at scrape time: poll and
+// fetch-error totals come from the poll history the poller records, so they
+// cover polls run by any process sharing the database.
+func (h *handlers) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if h.metricsKey == "" {
+		http.NotFound(w, r)
+		return
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.metricsKey)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	stats, err := h.engine.GetDBStats()
+	if err != nil {
+		slog.Error("metrics", "err", err)
+		http.Error(w, "failed to load stats", http.StatusInternalServerError)
+		return
+	}
+
+	var b strings.Builder
+	writeMetricHeader(&b, "herald_feeds_total", "gauge", "Feeds in the database.")
+	fmt.Fprintf(&b, "herald_feeds_total %d\n", stats.TotalFeeds)
+	writeMetricHeader(&b, "herald_articles_total", "gauge", "Articles in the database.")
+	fmt.Fprintf(&b, "herald_articles_total %d\n", stats.TotalArticles)
+
+	writeMetricHeader(&b, "herald_unread_total", "gauge", "Unread articles summed over all users.")
+	fmt.Fprintf(&b, "herald_unread_total %d\n", stats.TotalUnread)
+
+	writeMetricHeader(&b, "herald_feeds_erroring", "gauge", "Feeds whose last fetch failed.")
+	fmt.Fprintf(&b, "herald_feeds_erroring %d\n", stats.FeedsErroring)
+
+	writeMetricHeader(&b, "herald_fetch_errors_total", "counter", "Feed fetches that failed in recorded poll cycles.")
+	fmt.Fprintf(&b, "herald_fetch_errors_total %d\n", stats.PollErrors)
+
+	writeMetricHeader(&b, "herald_poll_duration_seconds", "summary", "Duration of recorded feed poll cycles.")
+	fmt.Fprintf(&b, "herald_poll_duration_seconds_sum %g\n", stats.PollSeconds)
+	fmt.Fprintf(&b, "herald_poll_duration_seconds_count %d\n", stats.PollRuns)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write([]byte(b.String())) //nolint:errcheck
+}
+
+func writeMetricHeader(b *strings.Builder, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
 // handleLogout redirects to the webauth logout endpoint.
 func (h *handlers) handleLogout(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, h.validator.LogoutURL(), http.StatusFound)
@@ -422,7 +689,7 @@ func (h *handlers) handleCallback(w http.ResponseWriter, r *http.Request) {
 
 	// Surface upstream errors (e.g. user denied access).
 	if errParam := r.URL.Query().Get("error"); errParam != "" {
-		log.Printf("herald-web: callback error from webauth: %s", errParam)
+		slog.Warn("callback error from webauth", "error", errParam)
 		http.Error(w, "Authentication error: "+errParam, http.StatusUnauthorized)
 		return
 	}
@@ -454,7 +721,7 @@ func (h *handlers) handleCallback(w http.ResponseWriter, r *http.Request) {
 	// Exchange the authorization code for an access token.
 	accessToken, _, err := h.validator.ExchangeCode(r.Context(), code, verifier)
 	if err != nil {
-		log.Printf("herald-web: callback token exchange: %v", err)
+		slog.Warn("callback token exchange failed", "err", err)
 		http.Error(w, "Authentication failed", http.StatusBadGateway)
 		return
 	}
@@ -477,8 +744,15 @@ func (h *handlers) handleHome(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Mark the visit so the next one's "new" list starts from here.
+	h.engine.RecordVisit(uid) //nolint:errcheck // read-only databases can't record visits
+
 	data := homeData{
-		UserName: user.Name,
+		UserName:      user.Name,
+		ActiveNew:     r.URL.Path == "/new",
+		ActiveQueue:   r.URL.Path == "/queue",
+		ActiveBlocked: r.URL.Path == "/blocked",
+		ActiveHistory: r.URL.Path == "/history",
 	}
 	if stats != nil {
 		data.Feeds = stats.Feeds
@@ -490,14 +764,20 @@ func (h *handlers) handleHome(w http.ResponseWriter, r *http.Request) {
 	if newsletters, err := h.engine.GetNewsletterStats(uid); err == nil {
 		data.Newsletters = newsletters
 	}
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(readingStatsDays - 1))
+	if reading, err := h.engine.GetReadingStats(uid, since); err == nil {
+		data.Reading = newReadingCard(reading)
+	}
 
 	h.renderPage(w, r, "home.html", data)
 }
 
 func (h *handlers) handleFeedsManage(w http.ResponseWriter, r *http.Request) {
 	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
 
-	feeds, err := h.engine.GetUserFeeds(uid)
+	feeds, err := h.engine.SearchUserFeeds(uid, query)
 	if err != nil {
 		h.renderError(w, http.StatusInternalServerError, "Failed to load feeds")
 		return
@@ -511,31 +791,44 @@ func (h *handlers) handleFeedsManage(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	data := feedManageData{}
+	data := feedManageData{Query: query}
 	for _, f := range feeds {
 		row := feedRow{
-			FeedID:  f.ID,
-			Title:   f.Title,
-			URL:     f.URL,
-			SiteURL: f.SiteURL,
+			FeedID:    f.ID,
+			Title:     f.Title,
+			URL:       f.URL,
+			SiteURL:   f.SiteURL,
+			Language:  f.Language,
+			Generator: f.Generator,
 		}
 		if f.LastError != nil {
 			row.LastError = *f.LastError
 		}
 		if f.LastFetched != nil {
-			row.LastFetchedFmt = formatDate(f.LastFetched)
+			row.LastFetchedFmt = dates.format(f.LastFetched)
+		}
+		if headers, err := h.engine.GetFeedFetchHeaders(uid, f.ID); err == nil {
+			row.HeaderNames = slices.Sorted(maps.Keys(headers))
 		}
 		if s, ok := statsMap[f.ID]; ok {
 			row.TotalArticles = s.TotalArticles
 			row.UnreadArticles = s.UnreadArticles
 			row.UnsummarizedArticles = s.UnsummarizedArticles
 			if s.LastPostDate != nil {
-				row.LastPostDateFmt = formatDate(s.LastPostDate)
+				row.LastPostDateFmt = dates.format(s.LastPostDate)
 			}
 		}
 		data.Feeds = append(data.Feeds, row)
 	}
+	if undoID := parseInt64Param(r, "undo"); undoID > 0 {
+		data.Undo = &feedUndo{FeedID: undoID, Title: r.URL.Query().Get("title")}
+	}
 
+	// The filter box swaps just the feed table.
+	if r.Header.Get("HX-Request") == "true" && r.URL.Query().Has("q") {
+		h.renderFragment(w, "feed_list", data)
+		return
+	}
 	h.renderPage(w, r, "feeds_manage.html", data)
 }
 
@@ -550,10 +843,17 @@ func (h *handlers) handleSettings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := settingsData{
-		Keywords:          strings.Join(prefs.Keywords, ", "),
+		Keywords:          formatKeywords(prefs.Keywords),
+		AvoidKeywords:     formatKeywords(prefs.AvoidKeywords),
 		InterestThreshold: prefs.InterestThreshold,
 		NotifyWhen:        prefs.NotifyWhen,
 		NotifyMinScore:    prefs.NotifyMinScore,
+		AutostarMinScore:  prefs.AutostarMinScore,
+		SummaryMaxWords:   prefs.SummaryMaxWords,
+		SummaryStyle:      prefs.SummaryStyle,
+		Timezone:          prefs.Timezone,
+		DateFormat:        prefs.DateFormat,
+		SanitizePolicy:    prefs.SanitizePolicy,
 		IsAdmin:           h.isAdminCtx(r.Context()),
 	}
 
@@ -600,24 +900,42 @@ func (h *handlers) handleSettingsPrompts(w http.ResponseWriter, r *http.Request)
 
 func (h *handlers) handleArticleList(w http.ResponseWriter, r *http.Request) {
 	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
 	limit := parseIntParam(r, "limit", 30)
 	offset := parseIntParam(r, "offset", 0)
 	feedID := parseInt64Param(r, "feed_id")
 	groupID := parseInt64Param(r, "group_id")
+	category := r.URL.Query().Get("category")
 	starred := r.URL.Query().Get("starred") == "1"
+	ungrouped := r.URL.Query().Get("ungrouped") == "1"
+	minScore, _ := strconv.ParseFloat(r.URL.Query().Get("min_score"), 64)
+	order := r.URL.Query().Get("order")
+	if err := herald.ValidateArticleOrder(order); err != nil {
+		h.renderError(w, http.StatusBadRequest, "Invalid order")
+		return
+	}
 
 	var articles []herald.Article
+	var scores, rawScores []float64
+	var unreadList bool
 	var err error
 
 	switch {
+	case minScore > 0:
+		articles, scores, rawScores, err = h.engine.GetHighInterestArticles(uid, minScore, limit+1, offset)
 	case starred:
 		articles, err = h.engine.GetStarredArticles(uid, limit+1, offset)
+	case category != "":
+		articles, err = h.engine.GetArticlesByCategory(uid, category, limit+1, offset)
+	case ungrouped:
+		articles, err = h.engine.GetUngroupedArticles(uid, limit+1, offset)
 	case groupID > 0:
 		articles, err = h.engine.GetUnreadGroupArticles(uid, groupID, limit+1, offset)
 	case feedID > 0:
 		articles, err = h.engine.GetUnreadArticlesByFeed(uid, feedID, limit+1, offset)
 	default:
-		articles, err = h.engine.GetUnreadArticles(uid, limit+1, offset)
+		unreadList = true
+		articles, err = h.engine.GetUnreadArticlesOrdered(uid, limit+1, offset, order)
 	}
 
 	if err != nil {
@@ -644,32 +962,270 @@ func (h *handlers) handleArticleList(w http.ResponseWriter, r *http.Request) {
 		NextOffset: offset + limit,
 		FeedID:     feedID,
 		GroupID:    groupID,
+		Category:   category,
 		Starred:    starred,
+		Ungrouped:  ungrouped,
+		MinScore:   minScore,
+		Order:      order,
+		ShowOrder:  unreadList && offset == 0,
 	}
 
-	// Load group summary banner when viewing a group
+	// Load group summary banner and edit controls when viewing a group
 	if groupID > 0 {
 		if group, err := h.engine.GetGroupArticles(groupID); err == nil && group != nil {
+			data.GroupName = group.DisplayName
+			if data.GroupName == "" {
+				data.GroupName = group.Topic
+			}
 			data.GroupHeadline = group.Headline
 			data.GroupSummary = group.Summary
 		}
+		if groups, err := h.engine.GetUserGroups(uid); err == nil {
+			for _, g := range groups {
+				if g.ID != groupID {
+					data.MergeTargets = append(data.MergeTargets, g)
+				}
+			}
+		}
 	}
 
+	// Lists other than the high-interest one don't carry scores, so look up
+	// the stored ones in a single batch. Unscored articles show no badge.
+	var stored map[int64]float64
+	if scores == nil && len(articles) > 0 {
+		ids := make([]int64, len(articles))
+		for i, a := range articles {
+			ids[i] = a.ID
+		}
+		stored, err = h.engine.GetInterestScores(uid, ids)
+		if err != nil {
+			h.renderError(w, http.StatusInternalServerError, "Failed to load articles")
+			return
+		}
+	}
+
+	for i, a := range articles {
+		row := articleRow{
+			ID:               a.ID,
+			Title:            a.Title,
+			Author:           a.Author,
+			FeedTitle:        feedTitles[a.FeedID],
+			PublishedDateFmt: dates.format(bestDate(a.PublishedDate, &a.FetchedDate)),
+			ReadingTime:      a.ReadingTime,
+		}
+		if i < len(scores) && i < len(rawScores) {
+			row.HasScore = true
+			row.Score = scores[i]
+			row.RawScore = rawScores[i]
+		} else if score, ok := stored[a.ID]; ok {
+			row.HasScore = true
+			row.Score = score
+			row.RawScore = score
+		}
+		data.Articles = append(data.Articles, row)
+	}
+
+	h.renderFragment(w, "article_list", data)
+	h.renderSidebarOOB(w, uid, homeData{ActiveFeed: feedID, ActiveGroup: groupID, ActiveStarred: starred, ActiveUngrouped: ungrouped})
+}
+
+// newArticlesLimit caps the "new since last visit" list.
+const newArticlesLimit = 200
+
+// handleNewArticles lists articles fetched since the user's previous visit.
+// A full-page load renders the home layout with this list selected.
+func (h *handlers) handleNewArticles(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("HX-Request") != "true" {
+		h.handleHome(w, r)
+		return
+	}
+	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
+
+	// Read the cutoff before recording this visit so the articles that
+	// arrived since the previous one still show.
+	since, err := h.engine.LastVisit(uid)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to load last visit")
+		return
+	}
+	h.engine.RecordVisit(uid) //nolint:errcheck // read-only databases can't record visits
+
+	articles, err := h.engine.GetArticlesSince(uid, since, newArticlesLimit)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to load articles")
+		return
+	}
+
+	feedTitles := make(map[int64]string)
+	if stats, err := h.engine.GetFeedStats(uid); err == nil && stats != nil {
+		for _, fs := range stats.Feeds {
+			feedTitles[fs.FeedID] = fs.FeedTitle
+		}
+	}
+
+	data := articleListData{NewSince: dates.format(&since)}
 	for _, a := range articles {
 		data.Articles = append(data.Articles, articleRow{
 			ID:               a.ID,
 			Title:            a.Title,
 			Author:           a.Author,
 			FeedTitle:        feedTitles[a.FeedID],
-			PublishedDateFmt: formatDate(bestDate(a.PublishedDate, &a.FetchedDate)),
+			PublishedDateFmt: dates.format(bestDate(a.PublishedDate, &a.FetchedDate)),
+			ReadingTime:      a.ReadingTime,
 		})
 	}
 
 	h.renderFragment(w, "article_list", data)
+	h.renderSidebarOOB(w, uid, homeData{ActiveNew: true})
+}
 
-	// Append OOB sidebar so HTMX refreshes it with the correct active state
-	// in the same round-trip, without a separate /sidebar request.
-	sidebarData := homeData{ActiveFeed: feedID, ActiveGroup: groupID, ActiveStarred: starred}
+// handleQueue lists the reading queue: starred articles not yet read, oldest
+// first. A full-page load renders the home layout with this list selected.
+func (h *handlers) handleQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("HX-Request") != "true" {
+		h.handleHome(w, r)
+		return
+	}
+	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
+	limit := parseIntParam(r, "limit", 30)
+	offset := parseIntParam(r, "offset", 0)
+
+	articles, err := h.engine.GetReadingQueue(uid, limit+1, offset)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to load reading queue")
+		return
+	}
+	hasMore := len(articles) > limit
+	if hasMore {
+		articles = articles[:limit]
+	}
+
+	feedTitles := make(map[int64]string)
+	if stats, err := h.engine.GetFeedStats(uid); err == nil && stats != nil {
+		for _, fs := range stats.Feeds {
+			feedTitles[fs.FeedID] = fs.FeedTitle
+		}
+	}
+
+	data := articleListData{HasMore: hasMore, NextOffset: offset + limit, Queue: true}
+	for _, a := range articles {
+		data.Articles = append(data.Articles, articleRow{
+			ID:               a.ID,
+			Title:            a.Title,
+			Author:           a.Author,
+			FeedTitle:        feedTitles[a.FeedID],
+			PublishedDateFmt: dates.format(bestDate(a.PublishedDate, &a.FetchedDate)),
+			ReadingTime:      a.ReadingTime,
+			Starred:          true,
+		})
+	}
+
+	h.renderFragment(w, "article_list", data)
+	h.renderSidebarOOB(w, uid, homeData{ActiveQueue: true})
+}
+
+// handleHistory lists the articles the user has read, most recently read
+// first.
+func (h *handlers) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("HX-Request") != "true" {
+		h.handleHome(w, r)
+		return
+	}
+	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
+	limit := parseIntParam(r, "limit", 30)
+	offset := parseIntParam(r, "offset", 0)
+
+	articles, err := h.engine.GetReadHistory(uid, limit+1, offset)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to load reading history")
+		return
+	}
+	hasMore := len(articles) > limit
+	if hasMore {
+		articles = articles[:limit]
+	}
+
+	feedTitles := make(map[int64]string)
+	if stats, err := h.engine.GetFeedStats(uid); err == nil && stats != nil {
+		for _, fs := range stats.Feeds {
+			feedTitles[fs.FeedID] = fs.FeedTitle
+		}
+	}
+
+	data := articleListData{HasMore: hasMore, NextOffset: offset + limit, History: true}
+	for _, a := range articles {
+		data.Articles = append(data.Articles, articleRow{
+			ID:               a.ID,
+			Title:            a.Title,
+			Author:           a.Author,
+			FeedTitle:        feedTitles[a.FeedID],
+			PublishedDateFmt: dates.format(bestDate(a.PublishedDate, &a.FetchedDate)),
+			ReadingTime:      a.ReadingTime,
+			Read:             true,
+			OpenCount:        a.OpenCount,
+		})
+	}
+
+	h.renderFragment(w, "article_list", data)
+	h.renderSidebarOOB(w, uid, homeData{ActiveHistory: true})
+}
+
+// handleBlocked lists articles the security check scored below threshold,
+// newest first, with the check's reasoning, so false positives can be
+// spotted. Opening one still goes through the normal article view.
+func (h *handlers) handleBlocked(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("HX-Request") != "true" {
+		h.handleHome(w, r)
+		return
+	}
+	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
+	limit := parseIntParam(r, "limit", 30)
+	offset := parseIntParam(r, "offset", 0)
+
+	articles, err := h.engine.GetBlockedArticles(uid, limit+1, offset)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to load blocked articles")
+		return
+	}
+	hasMore := len(articles) > limit
+	if hasMore {
+		articles = articles[:limit]
+	}
+
+	feedTitles := make(map[int64]string)
+	if stats, err := h.engine.GetFeedStats(uid); err == nil && stats != nil {
+		for _, fs := range stats.Feeds {
+			feedTitles[fs.FeedID] = fs.FeedTitle
+		}
+	}
+
+	data := articleListData{HasMore: hasMore, NextOffset: offset + limit, Blocked: true}
+	for _, a := range articles {
+		data.Articles = append(data.Articles, articleRow{
+			ID:               a.ID,
+			Title:            a.Title,
+			Author:           a.Author,
+			FeedTitle:        feedTitles[a.FeedID],
+			PublishedDateFmt: dates.format(bestDate(a.PublishedDate, &a.FetchedDate)),
+			ReadingTime:      a.ReadingTime,
+			Blocked:          true,
+			SecurityScore:    a.SecurityScore,
+			SecurityReason:   a.SecurityReason,
+		})
+	}
+
+	h.renderFragment(w, "article_list", data)
+	h.renderSidebarOOB(w, uid, homeData{ActiveBlocked: true})
+}
+
+// renderSidebarOOB appends an out-of-band sidebar so htmx refreshes it with
+// the correct active state in the same round-trip, without a separate
+// /sidebar request.
+func (h *handlers) renderSidebarOOB(w http.ResponseWriter, uid int64, sidebarData homeData) {
 	if stats, err := h.engine.GetFeedStats(uid); err == nil && stats != nil {
 		sidebarData.Feeds = stats.Feeds
 		sidebarData.TotalUnread = stats.Total.UnreadArticles
@@ -685,6 +1241,7 @@ func (h *handlers) handleArticleList(w http.ResponseWriter, r *http.Request) {
 
 func (h *handlers) handleSearch(w http.ResponseWriter, r *http.Request) {
 	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
 	query := r.URL.Query().Get("q")
 	limit := parseIntParam(r, "limit", 30)
 	offset := parseIntParam(r, "offset", 0)
@@ -724,16 +1281,90 @@ func (h *handlers) handleSearch(w http.ResponseWriter, r *http.Request) {
 			Title:            r.Title,
 			Author:           r.Author,
 			FeedTitle:        feedTitles[r.FeedID],
-			PublishedDateFmt: formatDate(bestDate(r.PublishedDate, &r.FetchedDate)),
+			PublishedDateFmt: dates.format(bestDate(r.PublishedDate, &r.FetchedDate)),
+			ReadingTime:      r.ReadingTime,
 		})
 	}
 
 	h.renderFragment(w, "search_results", data)
 }
 
+// handleArticlePermalink serves an article by its stable slug, which unlike
+// the numeric ID survives a database rebuild. htmx requests get the article
+// view fragment; a full-page load, such as a shared link, gets reader mode.
+func (h *handlers) handleArticlePermalink(w http.ResponseWriter, r *http.Request) {
+	article, err := h.engine.GetArticleBySlug(r.PathValue("slug"))
+	if err != nil {
+		h.renderError(w, http.StatusNotFound, "Article not found")
+		return
+	}
+	r.SetPathValue("articleID", strconv.FormatInt(article.ID, 10))
+	if r.Header.Get("HX-Request") == "true" {
+		h.handleArticleView(w, r)
+		return
+	}
+	h.handleArticleReader(w, r)
+}
+
+// handleArticleReader serves an article as a standalone reader-mode page:
+// the full text re-extracted from the original when the feed only carried an
+// excerpt, sanitized and wrapped in Herald's layout.
+func (h *handlers) handleArticleReader(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
+	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
+	if err != nil {
+		h.renderError(w, http.StatusBadRequest, "Invalid article ID")
+		return
+	}
+
+	article, err := h.engine.GetArticleForUser(uid, articleID)
+	if err != nil {
+		h.renderError(w, http.StatusNotFound, "Article not found")
+		return
+	}
+	body, err := h.engine.GetReadableArticle(uid, articleID)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to load article")
+		return
+	}
+	h.engine.MarkArticleRead(uid, articleID)
+
+	h.init()
+	content := normalizeContent(string(body))
+	imageMap, _ := h.engine.GetArticleImageMap(articleID)
+	base := article.URL
+	if article.LinkedURL != "" {
+		base = article.LinkedURL
+	}
+	content = rewriteImageURLs(content, imageMap, imageProxyFunc(h.imageKey, articleID, base))
+
+	feedTitle := ""
+	if feeds, err := h.engine.GetUserFeeds(uid); err == nil {
+		for _, f := range feeds {
+			if f.ID == article.FeedID {
+				feedTitle = f.Title
+				break
+			}
+		}
+	}
+
+	h.renderPage(w, r, "article_reader.html", articleViewData{
+		ID:               article.ID,
+		Title:            article.Title,
+		Author:           article.Author,
+		FeedTitle:        feedTitle,
+		URL:              article.URL,
+		PublishedDateFmt: dates.format(bestDate(article.PublishedDate, &article.FetchedDate)),
+		ReadingTime:      article.ReadingTime,
+		SanitizedContent: template.HTML(content), //nolint:gosec // sanitized by the engine
+	})
+}
+
 func (h *handlers) handleArticleView(w http.ResponseWriter, r *http.Request) {
 	h.init()
 	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
 	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
 	if err != nil {
 		h.renderError(w, http.StatusBadRequest, "Invalid article ID")
@@ -748,20 +1379,18 @@ func (h *handlers) handleArticleView(w http.ResponseWriter, r *http.Request) {
 
 	// Auto-mark as read
 	h.engine.MarkArticleRead(uid, articleID)
+	h.engine.RecordArticleOpen(uid, articleID)
 
-	// Sanitize HTML content, then rewrite <img src> to local cached URLs.
+	// Sanitize HTML content, then rewrite <img src> to local cached URLs,
+	// or to the signed image proxy for images not cached yet.
 	// Share a single seen map across both content blocks so images that appear
 	// in the RSS content are not repeated in the linked full-text content.
-	content := article.Content
-	if content == "" {
-		content = article.Summary
-	}
+	content := h.engine.ArticleBody(article)
+	policy := h.policyFor(uid)
 	seenImages := make(map[string]bool)
 	imageMap, _ := h.engine.GetArticleImageMap(article.ID)
-	sanitized := normalizeContentWithSeen(h.policy.Sanitize(content), seenImages)
-	if len(imageMap) > 0 {
-		sanitized = rewriteImageURLs(sanitized, imageMap)
-	}
+	sanitized := normalizeContentWithSeen(policy.Sanitize(content), seenImages)
+	sanitized = rewriteImageURLs(sanitized, imageMap, imageProxyFunc(h.imageKey, article.ID, article.URL))
 
 	// Look up feed title
 	feedTitle := ""
@@ -776,28 +1405,51 @@ func (h *handlers) handleArticleView(w http.ResponseWriter, r *http.Request) {
 
 	data := articleViewData{
 		ID:               article.ID,
+		Slug:             article.Slug,
 		Title:            article.Title,
 		Author:           article.Author,
 		FeedTitle:        feedTitle,
 		URL:              article.URL,
-		PublishedDateFmt: formatDate(bestDate(article.PublishedDate, &article.FetchedDate)),
+		PublishedDateFmt: dates.format(bestDate(article.PublishedDate, &article.FetchedDate)),
+		ReadingTime:      article.ReadingTime,
 		AISummary:        article.AISummary,
 		SanitizedContent: template.HTML(sanitized), //nolint:gosec // sanitized by bluemonday
 		LinkedURL:        article.LinkedURL,
+		Note:             article.Note,
+		InterestReason:   article.InterestReason,
+		Tags:             article.Tags,
+	}
+	if authors, err := h.engine.GetArticleAuthors(article.ID); err == nil {
+		data.Authors = authors
+	}
+	if categories, err := h.engine.GetArticleCategories(article.ID); err == nil {
+		data.Categories = categories
+	}
+	if article.Lang != langdetect.Unknown {
+		data.Lang = article.Lang
 	}
 	if article.LinkedURL != "" {
 		if u, err := url.Parse(article.LinkedURL); err == nil {
 			data.LinkedDomain = u.Hostname()
 		}
 		if article.LinkedContent != "" {
-			sanitizedLinked := normalizeContentWithSeen(h.policy.Sanitize(article.LinkedContent), seenImages)
-			if len(imageMap) > 0 {
-				sanitizedLinked = rewriteImageURLs(sanitizedLinked, imageMap)
-			}
+			sanitizedLinked := normalizeContentWithSeen(policy.Sanitize(article.LinkedContent), seenImages)
+			sanitizedLinked = rewriteImageURLs(sanitizedLinked, imageMap, imageProxyFunc(h.imageKey, article.ID, article.LinkedURL))
 			data.SanitizedLinkedContent = template.HTML(sanitizedLinked) //nolint:gosec // sanitized by bluemonday
 		}
 	}
 
+	data.GroupID, _ = h.engine.ArticleGroupID(uid, articleID)
+	if groups, err := h.engine.GetUserGroups(uid); err == nil {
+		for _, g := range groups {
+			name := g.DisplayName
+			if name == "" {
+				name = g.Topic
+			}
+			data.GroupOptions = append(data.GroupOptions, groupOption{ID: g.ID, Name: name})
+		}
+	}
+
 	h.renderFragment(w, "article_view", data)
 }
 
@@ -811,9 +1463,10 @@ func (h *handlers) handleSidebar(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := homeData{
-		ActiveFeed:    parseInt64Param(r, "feed_id"),
-		ActiveGroup:   parseInt64Param(r, "group_id"),
-		ActiveStarred: r.URL.Query().Get("starred") == "1",
+		ActiveFeed:      parseInt64Param(r, "feed_id"),
+		ActiveGroup:     parseInt64Param(r, "group_id"),
+		ActiveStarred:   r.URL.Query().Get("starred") == "1",
+		ActiveUngrouped: r.URL.Query().Get("ungrouped") == "1",
 	}
 	if stats != nil {
 		data.Feeds = stats.Feeds
@@ -838,54 +1491,120 @@ func (h *handlers) handleMarkAllRead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ids := parseIDList(r.FormValue("ids"))
+	if len(ids) > 0 {
+		if err := h.engine.MarkArticlesRead(uid, ids); err != nil {
+			writeFailed(w, err, "failed to mark read")
+			return
+		}
+	}
+
+	w.Header().Set("HX-Trigger", "articles-marked-read")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleStarBatch stars the comma-separated article IDs in the ids form
+// value, or unstars them when starred=false.
+func (h *handlers) handleStarBatch(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	ids := parseIDList(r.FormValue("ids"))
+	starred := r.FormValue("starred") != "false"
+
+	if len(ids) > 0 {
+		if err := h.engine.StarArticles(uid, ids, starred); err != nil {
+			writeFailed(w, err, "failed to update stars")
+			return
+		}
+	}
+
+	w.Header().Set("HX-Trigger", "feeds-changed")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseIDList parses a comma-separated list of IDs, skipping blank and
+// malformed entries.
+func parseIDList(s string) []int64 {
 	var ids []int64
-	for s := range strings.SplitSeq(r.FormValue("ids"), ",") {
-		s = strings.TrimSpace(s)
-		if s == "" {
+	for part := range strings.SplitSeq(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
 			continue
 		}
-		id, err := strconv.ParseInt(s, 10, 64)
+		id, err := strconv.ParseInt(part, 10, 64)
 		if err != nil {
 			continue
 		}
 		ids = append(ids, id)
 	}
-
-	if len(ids) > 0 {
-		if err := h.engine.MarkArticlesRead(uid, ids); err != nil {
-			http.Error(w, "failed to mark read", http.StatusInternalServerError)
-			return
-		}
+	return ids
+}
+
+func (h *handlers) handleGroupMute(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	groupID, err := strconv.ParseInt(r.PathValue("groupID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid group ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.engine.MuteGroup(uid, groupID); err != nil {
+		http.Error(w, "failed to mute group", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("HX-Redirect", "/")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handlers) handleGroupDisband(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	groupID, err := strconv.ParseInt(r.PathValue("groupID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid group ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.engine.DisbandGroup(uid, groupID); err != nil {
+		http.Error(w, "failed to disband group", http.StatusInternalServerError)
+		return
 	}
-
-	w.Header().Set("HX-Trigger", "articles-marked-read")
+	w.Header().Set("HX-Redirect", "/")
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *handlers) handleGroupMute(w http.ResponseWriter, r *http.Request) {
+// handleGroupRename replaces the group's topic with the submitted name.
+func (h *handlers) handleGroupRename(w http.ResponseWriter, r *http.Request) {
 	uid := userFromContext(r.Context()).ID
 	groupID, err := strconv.ParseInt(r.PathValue("groupID"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid group ID", http.StatusBadRequest)
 		return
 	}
-	if err := h.engine.MuteGroup(uid, groupID); err != nil {
-		http.Error(w, "failed to mute group", http.StatusInternalServerError)
+	if err := h.engine.RenameGroup(uid, groupID, r.FormValue("name")); err != nil {
+		http.Error(w, "failed to rename group", http.StatusBadRequest)
 		return
 	}
-	w.Header().Set("HX-Redirect", "/")
+	w.Header().Set("HX-Trigger", "feeds-changed")
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *handlers) handleGroupDisband(w http.ResponseWriter, r *http.Request) {
+// handleGroupMerge folds the group into the group named by target_id.
+func (h *handlers) handleGroupMerge(w http.ResponseWriter, r *http.Request) {
 	uid := userFromContext(r.Context()).ID
 	groupID, err := strconv.ParseInt(r.PathValue("groupID"), 10, 64)
 	if err != nil {
 		http.Error(w, "invalid group ID", http.StatusBadRequest)
 		return
 	}
-	if err := h.engine.DisbandGroup(uid, groupID); err != nil {
-		http.Error(w, "failed to disband group", http.StatusInternalServerError)
+	targetID, err := strconv.ParseInt(r.FormValue("target_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid target group ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.engine.MergeGroups(r.Context(), uid, groupID, targetID); err != nil {
+		http.Error(w, "failed to merge groups", http.StatusBadRequest)
 		return
 	}
 	w.Header().Set("HX-Redirect", "/")
@@ -899,14 +1618,38 @@ func (h *handlers) handleGroupMarkRead(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid group ID", http.StatusBadRequest)
 		return
 	}
-	if err := h.engine.MarkGroupRead(uid, groupID, 0); err != nil {
-		http.Error(w, "failed to mark group read", http.StatusInternalServerError)
+	if _, err := h.engine.MarkGroupRead(uid, groupID); err != nil {
+		writeFailed(w, err, "failed to mark group read")
 		return
 	}
 	w.Header().Set("HX-Trigger", "feeds-changed")
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *handlers) handleGroupResummarize(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	groupID, err := strconv.ParseInt(r.PathValue("groupID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid group ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.engine.RegenerateGroupSummary(r.Context(), uid, groupID); err != nil {
+		slog.Warn("group resummarize failed", "group_id", groupID, "err", err)
+		writeFailed(w, err, "failed to regenerate group summary")
+		return
+	}
+	group, err := h.engine.GetGroupArticles(groupID)
+	if err != nil {
+		writeFailed(w, err, "failed to load group")
+		return
+	}
+	h.renderFragment(w, "group_summary", struct {
+		GroupID       int64
+		GroupHeadline string
+		GroupSummary  string
+	}{groupID, group.Headline, group.Summary})
+}
+
 // --- Newsletter handlers ---
 
 type newsletterViewData struct {
@@ -970,6 +1713,7 @@ func (h *handlers) handleNewsletterCreate(w http.ResponseWriter, r *http.Request
 func (h *handlers) handleNewsletterView(w http.ResponseWriter, r *http.Request) {
 	h.init()
 	uid := userFromContext(r.Context()).ID
+	dates := h.dateFormatterFor(uid)
 	newsletterID, err := strconv.ParseInt(r.PathValue("newsletterID"), 10, 64)
 	if err != nil {
 		h.renderError(w, http.StatusBadRequest, "Invalid newsletter ID")
@@ -992,9 +1736,9 @@ func (h *handlers) handleNewsletterView(w http.ResponseWriter, r *http.Request)
 	if issue, err := h.engine.GetLatestNewsletterIssue(newsletterID); err == nil {
 		data.LatestIssue = issue
 		data.SanitizedHTML = template.HTML(h.policy.Sanitize(issue.ContentHTML)) //nolint:gosec
-		data.GeneratedFmt = formatDate(&issue.GeneratedAt)
+		data.GeneratedFmt = dates.format(&issue.GeneratedAt)
 		if issue.SentAt != nil {
-			data.SentFmt = formatDate(issue.SentAt)
+			data.SentFmt = dates.format(issue.SentAt)
 		}
 	}
 
@@ -1002,7 +1746,7 @@ func (h *handlers) handleNewsletterView(w http.ResponseWriter, r *http.Request)
 	if issues, err := h.engine.GetNewsletterIssues(newsletterID, 10, 1); err == nil {
 		for _, i := range issues {
 			data.PastIssues = append(data.PastIssues, newsletterIssueRow{
-				ID: i.ID, Headline: i.Headline, GeneratedFmt: formatDate(&i.GeneratedAt),
+				ID: i.ID, Headline: i.Headline, GeneratedFmt: dates.format(&i.GeneratedAt),
 			})
 		}
 	}
@@ -1081,6 +1825,7 @@ func (h *handlers) handleNewsletterSend(w http.ResponseWriter, r *http.Request)
 
 func (h *handlers) handleNewsletterIssueView(w http.ResponseWriter, r *http.Request) {
 	h.init()
+	dates := h.dateFormatterFor(userFromContext(r.Context()).ID)
 	newsletterID, err := strconv.ParseInt(r.PathValue("newsletterID"), 10, 64)
 	if err != nil {
 		h.renderError(w, http.StatusBadRequest, "Invalid newsletter ID")
@@ -1111,15 +1856,103 @@ func (h *handlers) handleNewsletterIssueView(w http.ResponseWriter, r *http.Requ
 		},
 		LatestIssue:   issue,
 		SanitizedHTML: template.HTML(h.policy.Sanitize(issue.ContentHTML)), //nolint:gosec
-		GeneratedFmt:  formatDate(&issue.GeneratedAt),
+		GeneratedFmt:  dates.format(&issue.GeneratedAt),
 	}
 	if issue.SentAt != nil {
-		data.SentFmt = formatDate(issue.SentAt)
+		data.SentFmt = dates.format(issue.SentAt)
 	}
 
 	h.renderFragment(w, "newsletter_view", data)
 }
 
+// handleArticleMoveGroup refiles an article under the group named by group_id.
+// A group_id of 0 gives the article a new group of its own.
+func (h *handlers) handleArticleMoveGroup(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid article ID", http.StatusBadRequest)
+		return
+	}
+	groupID, err := strconv.ParseInt(r.FormValue("group_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid group ID", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.engine.MoveArticleToGroup(r.Context(), uid, articleID, groupID); err != nil {
+		http.Error(w, "failed to move article", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("HX-Trigger", "feeds-changed")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleArticleResummarize regenerates the article's AI summary and returns
+// the refreshed summary block.
+func (h *handlers) handleArticleResummarize(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
+	if err != nil {
+		h.renderError(w, http.StatusBadRequest, "Invalid article ID")
+		return
+	}
+
+	summary, err := h.engine.RegenerateSummary(r.Context(), uid, articleID)
+	if err != nil {
+		slog.Warn("resummarize failed", "article_id", articleID, "err", err)
+		writeFailed(w, err, "Failed to regenerate summary")
+		return
+	}
+
+	h.renderFragment(w, "ai_summary", struct {
+		ID        int64
+		AISummary string
+	}{articleID, summary})
+}
+
+// handleArticleUnblock overrides the security check for a blocked article.
+// The response is empty so the row swaps out of the blocked list.
+func (h *handlers) handleArticleUnblock(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
+	if err != nil {
+		h.renderError(w, http.StatusBadRequest, "Invalid article ID")
+		return
+	}
+
+	if err := h.engine.OverrideSecurity(r.Context(), uid, articleID); err != nil {
+		slog.Warn("security override failed", "article_id", articleID, "err", err)
+		writeFailed(w, err, "Failed to unblock article")
+		return
+	}
+	w.Header().Set("HX-Trigger", "feeds-changed")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *handlers) handleArticleNote(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
+	if err != nil {
+		h.renderError(w, http.StatusBadRequest, "Invalid article ID")
+		return
+	}
+
+	if err := h.engine.SetArticleNote(uid, articleID, r.FormValue("note")); err != nil {
+		writeFailed(w, err, "Failed to save note")
+		return
+	}
+	note, err := h.engine.GetArticleNote(uid, articleID)
+	if err != nil {
+		writeFailed(w, err, "Failed to load note")
+		return
+	}
+
+	h.renderFragment(w, "article_note", struct {
+		ID   int64
+		Note string
+	}{articleID, note})
+}
+
 func (h *handlers) handleStarToggle(w http.ResponseWriter, r *http.Request) {
 	uid := userFromContext(r.Context()).ID
 	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
@@ -1133,7 +1966,7 @@ func (h *handlers) handleStarToggle(w http.ResponseWriter, r *http.Request) {
 	starred := r.FormValue("starred") != "false"
 
 	if err := h.engine.StarArticle(uid, articleID, starred); err != nil {
-		h.renderError(w, http.StatusInternalServerError, "Failed to toggle star")
+		writeFailed(w, err, "Failed to toggle star")
 		return
 	}
 
@@ -1153,6 +1986,48 @@ func (h *handlers) handleStarToggle(w http.ResponseWriter, r *http.Request) {
 		cls, articleID, nextState, label)
 }
 
+// handleReadToggle flips an article between read and unread and returns its
+// list row in the new state. The row's displayed scores come back as the
+// score and raw_score form values so the badge survives the swap; they are
+// only echoed, never stored.
+func (h *handlers) handleReadToggle(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	articleID, err := strconv.ParseInt(r.PathValue("articleID"), 10, 64)
+	if err != nil {
+		h.renderError(w, http.StatusBadRequest, "Invalid article ID")
+		return
+	}
+	article, err := h.engine.GetArticle(articleID)
+	if err != nil {
+		h.renderError(w, http.StatusNotFound, "Article not found")
+		return
+	}
+
+	read, err := h.engine.ToggleRead(uid, articleID)
+	if err != nil {
+		writeFailed(w, err, "Failed to toggle read state")
+		return
+	}
+
+	row := articleRow{
+		ID:               article.ID,
+		Title:            article.Title,
+		Author:           article.Author,
+		PublishedDateFmt: h.dateFormatterFor(uid).format(bestDate(article.PublishedDate, &article.FetchedDate)),
+		ReadingTime:      article.ReadingTime,
+		Read:             read,
+	}
+	row.FeedTitle, _ = h.engine.GetUserFeedTitle(uid, article.FeedID)
+	if scores, err := h.engine.GetInterestScores(uid, []int64{articleID}); err == nil {
+		if score, ok := scores[articleID]; ok {
+			row.HasScore, row.Score, row.RawScore = true, score, score
+		}
+	}
+
+	w.Header().Set("HX-Trigger", "feeds-changed")
+	h.renderFragment(w, "article_row", row)
+}
+
 // discoverResultsData is the template data for the feed_discover_results fragment.
 type discoverResultsData struct {
 	PageURL string
@@ -1255,14 +2130,100 @@ func (h *handlers) handleFeedUnsubscribe(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Look up the title first; once unsubscribed the feed is no longer
+	// among the user's feeds.
+	var title string
+	if feeds, err := h.engine.GetUserFeeds(uid); err == nil {
+		for _, f := range feeds {
+			if f.ID == feedID {
+				title = f.Title
+				break
+			}
+		}
+	}
+
 	if err := h.engine.UnsubscribeFeed(uid, feedID); err != nil {
 		h.renderError(w, http.StatusInternalServerError, "Failed to unsubscribe")
 		return
 	}
 
+	q := url.Values{"undo": {strconv.FormatInt(feedID, 10)}, "title": {title}}
+	w.Header().Set("HX-Redirect", "/feeds?"+q.Encode())
+}
+
+// handleFeedResubscribe undoes an unsubscribe from the feeds page toast.
+func (h *handlers) handleFeedResubscribe(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	feedID, err := strconv.ParseInt(r.PathValue("feedID"), 10, 64)
+	if err != nil {
+		h.renderError(w, http.StatusBadRequest, "Invalid feed ID")
+		return
+	}
+
+	if err := h.engine.ResubscribeFeed(uid, feedID); err != nil {
+		h.renderError(w, http.StatusNotFound, "That feed has already been removed")
+		return
+	}
+
 	w.Header().Set("HX-Redirect", "/feeds")
 }
 
+// handleFeedMute mutes a subscription, or unmutes it with muted=false. The
+// feed keeps fetching; it just leaves the unread stream.
+func (h *handlers) handleFeedMute(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	feedID, err := strconv.ParseInt(r.PathValue("feedID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid feed ID", http.StatusBadRequest)
+		return
+	}
+	muted := r.FormValue("muted") != "false"
+	if err := h.engine.SetFeedMuted(uid, feedID, muted); err != nil {
+		writeFailed(w, err, "failed to mute feed")
+		return
+	}
+	w.Header().Set("HX-Trigger", "feeds-changed")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleFeedHeaders sets or, given an empty value, removes one custom fetch
+// header on a feed and re-renders the feed's header list. Values are
+// write-only: the fragment shows names with a masked value.
+func (h *handlers) handleFeedHeaders(w http.ResponseWriter, r *http.Request) {
+	feedID, err := strconv.ParseInt(r.PathValue("feedID"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid feed ID", http.StatusBadRequest)
+		return
+	}
+	uid := userFromContext(r.Context()).ID
+	headers, err := h.engine.GetFeedFetchHeaders(uid, feedID)
+	if err != nil {
+		h.renderFragment(w, "feed_headers", feedHeadersData{FeedID: feedID, Error: err.Error()})
+		return
+	}
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	name := http.CanonicalHeaderKey(strings.TrimSpace(r.FormValue("name")))
+	if value := r.FormValue("value"); value == "" {
+		delete(headers, name)
+	} else {
+		headers[name] = value
+	}
+
+	data := feedHeadersData{FeedID: feedID}
+	if err := h.engine.SetFeedFetchHeaders(uid, feedID, headers); err != nil {
+		if errors.Is(err, herald.ErrReadOnly) {
+			writeFailed(w, err, "failed to set feed headers")
+			return
+		}
+		data.Error = err.Error()
+		headers, _ = h.engine.GetFeedFetchHeaders(uid, feedID)
+	}
+	data.Names = slices.Sorted(maps.Keys(headers))
+	h.renderFragment(w, "feed_headers", data)
+}
+
 // handleFeedTitleDisplay returns the static display fragment for a feed title cell.
 func (h *handlers) handleFeedTitleDisplay(w http.ResponseWriter, r *http.Request) {
 	feedID, err := strconv.ParseInt(r.PathValue("feedID"), 10, 64)
@@ -1327,8 +2288,39 @@ func (h *handlers) handleArticleImage(w http.ResponseWriter, r *http.Request) {
 	w.Write(img.Data)                                          //nolint:errcheck
 }
 
+// handleImageProxy serves a remote article image through herald so the
+// browser never contacts the publisher. Only URLs signed by
+// signImageURL are fetched; the result is cached as an article image.
+func (h *handlers) handleImageProxy(w http.ResponseWriter, r *http.Request) {
+	h.init()
+	q := r.URL.Query()
+	articleID, err := strconv.ParseInt(q.Get("a"), 10, 64)
+	imgURL := q.Get("u")
+	if err != nil || imgURL == "" || !validImageSignature(h.imageKey, articleID, imgURL, q.Get("s")) {
+		http.Error(w, "invalid image signature", http.StatusForbidden)
+		return
+	}
+	uid := userFromContext(r.Context()).ID
+	if _, err := h.engine.GetArticleForUser(uid, articleID); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	img, err := h.engine.ProxyArticleImage(r.Context(), articleID, imgURL)
+	if err != nil {
+		slog.Debug("image proxy fetch failed", "article_id", articleID, "url", imgURL, "err", err)
+		http.Error(w, "image unavailable", http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", img.MimeType)
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=2592000") // 30 days
+	w.Write(img.Data)                                           //nolint:errcheck
+}
+
 // handleFeedFavicon serves the cached favicon for a feed as an image.
-// Returns 404 if no favicon has been fetched yet.
+// Feeds without one yet get the generic feed icon, cached briefly so the
+// real favicon shows up once a poll has fetched it.
 func (h *handlers) handleFeedFavicon(w http.ResponseWriter, r *http.Request) {
 	feedID, err := strconv.ParseInt(r.PathValue("feedID"), 10, 64)
 	if err != nil {
@@ -1337,7 +2329,14 @@ func (h *handlers) handleFeedFavicon(w http.ResponseWriter, r *http.Request) {
 	}
 	fav, err := h.engine.GetFeedFavicon(feedID)
 	if err != nil || fav == nil {
-		http.NotFound(w, r)
+		icon, err := embedded.ReadFile("static/feed-icon.svg")
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		w.Write(icon) //nolint:errcheck
 		return
 	}
 	w.Header().Set("Content-Type", fav.MimeType)
@@ -1360,7 +2359,7 @@ func (h *handlers) handleOPMLImport(w http.ResponseWriter, r *http.Request) {
 	}
 	defer f.Close()
 
-	if err := h.engine.ImportOPMLReader(f, uid); err != nil {
+	if _, err := h.engine.ImportOPMLReader(f, uid, false); err != nil {
 		h.renderError(w, http.StatusBadRequest, fmt.Sprintf("Failed to import OPML: %v", err))
 		return
 	}
@@ -1368,6 +2367,39 @@ func (h *handlers) handleOPMLImport(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/feeds", http.StatusSeeOther)
 }
 
+// formatKeywords renders keywords for the settings form as comma-separated
+// terms, with non-default weights appended as "term:weight".
+func formatKeywords(kw herald.Keywords) string {
+	parts := make([]string, len(kw))
+	for i, k := range kw {
+		parts[i] = k.Term
+		if k.Weight != storage.DefaultKeywordWeight {
+			parts[i] += ":" + strconv.FormatFloat(k.Weight, 'g', -1, 64)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseKeywords is the inverse of formatKeywords. A ":suffix" that is not a
+// number is left as part of the term, so "C++: the language" survives.
+func parseKeywords(s string) herald.Keywords {
+	var kw herald.Keywords
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		k := herald.Keyword{Term: p, Weight: storage.DefaultKeywordWeight}
+		if i := strings.LastIndexByte(p, ':'); i > 0 {
+			if w, err := strconv.ParseFloat(strings.TrimSpace(p[i+1:]), 64); err == nil {
+				k = herald.Keyword{Term: strings.TrimSpace(p[:i]), Weight: w}
+			}
+		}
+		kw = append(kw, k)
+	}
+	return kw
+}
+
 func (h *handlers) handleSettingsSave(w http.ResponseWriter, r *http.Request) {
 	uid := userFromContext(r.Context()).ID
 
@@ -1376,30 +2408,48 @@ func (h *handlers) handleSettingsSave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Keywords: convert comma-separated to JSON array
+	prefs := make(map[string]string)
+
+	// Keywords: convert comma-separated "term" or "term:weight" to JSON
 	if kw := r.FormValue("keywords"); kw != "" {
-		parts := strings.Split(kw, ",")
-		var cleaned []string
-		for _, p := range parts {
-			p = strings.TrimSpace(p)
-			if p != "" {
-				cleaned = append(cleaned, p)
-			}
+		kwJSON, _ := json.Marshal(parseKeywords(kw))
+		prefs["keywords"] = string(kwJSON)
+	}
+	// Unlike interests, the avoid list may be cleared entirely.
+	if _, ok := r.Form["avoid_keywords"]; ok {
+		avoid := parseKeywords(r.FormValue("avoid_keywords"))
+		if avoid == nil {
+			avoid = herald.Keywords{}
 		}
-		kwJSON, _ := json.Marshal(cleaned)
-		h.engine.SetPreference(uid, "keywords", string(kwJSON))
+		avoidJSON, _ := json.Marshal(avoid)
+		prefs["avoid_keywords"] = string(avoidJSON)
 	}
-
-	if v := r.FormValue("interest_threshold"); v != "" {
-		h.engine.SetPreference(uid, "interest_threshold", v)
+	for _, key := range []string{"interest_threshold", "notify_when", "notify_min_score"} {
+		if v := r.FormValue(key); v != "" {
+			prefs[key] = v
+		}
 	}
-
-	if v := r.FormValue("notify_when"); v != "" {
-		h.engine.SetPreference(uid, "notify_when", v)
+	// Summary, date and content preferences may be cleared back to their defaults.
+	for _, key := range []string{"summary_style", "timezone", "date_format", "sanitize_policy"} {
+		if _, ok := r.Form[key]; ok {
+			prefs[key] = strings.TrimSpace(r.FormValue(key))
+		}
+	}
+	for _, key := range []string{"summary_max_words", "autostar_min_score"} {
+		if _, ok := r.Form[key]; ok {
+			v := strings.TrimSpace(r.FormValue(key))
+			if v == "" {
+				v = "0"
+			}
+			prefs[key] = v
+		}
 	}
 
-	if v := r.FormValue("notify_min_score"); v != "" {
-		h.engine.SetPreference(uid, "notify_min_score", v)
+	for key, v := range prefs {
+		if err := h.engine.SetPreference(uid, key, v); err != nil {
+			writeFailed(w, err, "Failed to save settings")
+			return
+		}
 	}
 
 	w.Header().Set("HX-Trigger", "settings-saved")
@@ -1554,6 +2604,40 @@ func (h *handlers) handleStats(w http.ResponseWriter, r *http.Request) {
 	h.renderPage(w, r, "stats.html", data)
 }
 
+// statusData is the template data for the poll status page.
+type statusData struct {
+	Runs []statusRun
+}
+
+// statusRun is a poll run with its start time and duration preformatted.
+type statusRun struct {
+	herald.PollRun
+	StartedFmt  string
+	DurationFmt string
+}
+
+// statusRunLimit is how many poll runs the status page shows.
+const statusRunLimit = 50
+
+func (h *handlers) handleStatus(w http.ResponseWriter, r *http.Request) {
+	h.init()
+	runs, err := h.engine.GetPollRuns(statusRunLimit)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to load poll history")
+		return
+	}
+	loc := h.dateFormatterFor(userFromContext(r.Context()).ID).loc
+	data := statusData{Runs: make([]statusRun, len(runs))}
+	for i, run := range runs {
+		data.Runs[i] = statusRun{
+			PollRun:     run,
+			StartedFmt:  run.StartedAt.In(loc).Format("2006-01-02 15:04:05"),
+			DurationFmt: (time.Duration(run.DurationMS) * time.Millisecond).Round(100 * time.Millisecond).String(),
+		}
+	}
+	h.renderPage(w, r, "status.html", data)
+}
+
 // adminStatsData is the template data for the admin stats page.
 type adminStatsData struct {
 	TotalArticles int
@@ -1689,6 +2773,7 @@ func (h *handlers) handleFilters(w http.ResponseWriter, r *http.Request) {
 			Axis:  r.Axis,
 			Value: r.Value,
 			Score: r.Score,
+			Block: r.Block,
 		}
 		if r.FeedID != nil {
 			row.FeedTitle = feedTitles[*r.FeedID]
@@ -1706,17 +2791,24 @@ func (h *handlers) handleFilterAdd(w http.ResponseWriter, r *http.Request) {
 	value := strings.TrimSpace(r.FormValue("value"))
 	scoreStr := r.FormValue("score")
 	feedIDStr := r.FormValue("feed_id")
+	block := r.FormValue("action") == "block"
 
-	score, err := strconv.Atoi(scoreStr)
-	if err != nil {
-		h.renderError(w, http.StatusBadRequest, "Invalid score")
-		return
+	// Block rules ignore the score field entirely.
+	var score int
+	if !block {
+		var err error
+		score, err = strconv.Atoi(scoreStr)
+		if err != nil {
+			h.renderError(w, http.StatusBadRequest, "Invalid score")
+			return
+		}
 	}
 
 	rule := herald.FilterRule{
 		Axis:  axis,
 		Value: value,
 		Score: score,
+		Block: block,
 	}
 	if feedIDStr != "" {
 		fid, err := strconv.ParseInt(feedIDStr, 10, 64)
@@ -1811,6 +2903,20 @@ func (h *handlers) handleFilterValues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// domain and title are free text; a domain rule matches the host and its
+	// subdomains, a title rule any title containing the value
+	switch axis {
+	case "domain":
+		fmt.Fprint(w, `<input type="text" name="value" id="value-select" placeholder="e.g. example.com" required>`)
+		return
+	case "title":
+		fmt.Fprint(w, `<input type="text" name="value" id="value-select" placeholder="e.g. CVE" required>`)
+		return
+	case "lang":
+		fmt.Fprint(w, `<input type="text" name="value" id="value-select" placeholder="e.g. de" required>`)
+		return
+	}
+
 	// tag axis has no discoverable metadata
 	if axis == "tag" || feedIDStr == "" {
 		fmt.Fprintf(w, `<input type="text" name="value" id="value-select" placeholder="e.g. security" required>`)
@@ -1851,7 +2957,70 @@ func (h *handlers) handleFilterValues(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, b.String())
 }
 
+// handleFilterExplain returns a fragment listing the filter rules that match
+// an article and whether the article clears the user's threshold.
+func (h *handlers) handleFilterExplain(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+
+	articleID, err := strconv.ParseInt(r.URL.Query().Get("article_id"), 10, 64)
+	if err != nil || articleID <= 0 {
+		h.renderError(w, http.StatusBadRequest, "Invalid article ID")
+		return
+	}
+
+	ex, err := h.engine.ExplainArticleScore(uid, articleID)
+	if err != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<p class="empty-state">Article not found.</p>`)
+		return
+	}
+
+	h.renderFragment(w, "filter_explain", ex)
+}
+
+// handleFilterImport adds the rules pasted into the import form, a JSON
+// array as written by handleFilterExport, and re-renders the rules table
+// with the added and skipped counts.
+func (h *handlers) handleFilterImport(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	var rules []herald.FilterRule
+	if err := json.Unmarshal([]byte(r.FormValue("rules")), &rules); err != nil {
+		h.renderError(w, http.StatusBadRequest, "Rules must be a JSON array of filter rules")
+		return
+	}
+	added, skipped, err := h.engine.ImportFilterRules(uid, rules)
+	if err != nil {
+		h.renderError(w, http.StatusBadRequest, fmt.Sprintf("Failed to import rules: %v", err))
+		return
+	}
+	result := fmt.Sprintf("Imported %d rules", added)
+	if skipped > 0 {
+		result += fmt.Sprintf("; skipped %d already defined", skipped)
+	}
+	h.renderFilterRulesFragmentWith(w, uid, result+".")
+}
+
+// handleFilterExport downloads the user's filter rules as JSON for import
+// elsewhere.
+func (h *handlers) handleFilterExport(w http.ResponseWriter, r *http.Request) {
+	uid := userFromContext(r.Context()).ID
+	data, err := h.engine.ExportFilterRules(uid)
+	if err != nil {
+		h.renderError(w, http.StatusInternalServerError, "Failed to export filter rules")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="herald-filters.json"`)
+	w.Write(data)
+}
+
 func (h *handlers) renderFilterRulesFragment(w http.ResponseWriter, userID int64) {
+	h.renderFilterRulesFragmentWith(w, userID, "")
+}
+
+// renderFilterRulesFragmentWith renders the rules table with importResult
+// shown above it.
+func (h *handlers) renderFilterRulesFragmentWith(w http.ResponseWriter, userID int64, importResult string) {
 	rules, _ := h.engine.GetFilterRules(userID, nil)
 	feeds, _ := h.engine.GetUserFeeds(userID)
 
@@ -1860,13 +3029,14 @@ func (h *handlers) renderFilterRulesFragment(w http.ResponseWriter, userID int64
 		feedTitles[f.ID] = f.Title
 	}
 
-	data := filtersData{}
+	data := filtersData{ImportResult: importResult}
 	for _, r := range rules {
 		row := filterRuleRow{
 			ID:    r.ID,
 			Axis:  r.Axis,
 			Value: r.Value,
 			Score: r.Score,
+			Block: r.Block,
 		}
 		if r.FeedID != nil {
 			row.FeedTitle = feedTitles[*r.FeedID]
@@ -1,16 +1,21 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
+	"html"
+	"image"
+	imagepng "image/png"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
@@ -196,6 +201,7 @@ type testFixtures struct {
 	feedID    int64
 	articleID int64
 	jwtToken  string // valid JWT for the test user
+	dbPath    string
 }
 
 func newTestFixtures(t *testing.T) *testFixtures {
@@ -203,8 +209,7 @@ func newTestFixtures(t *testing.T) *testFixtures {
 	dbPath := filepath.Join(t.TempDir(), "test.db")
 
 	engine, err := herald.NewEngine(herald.EngineConfig{
-		DBPath:   dbPath,
-		ReadOnly: true,
+		DBPath: dbPath,
 	})
 	if err != nil {
 		t.Fatalf("NewEngine: %v", err)
@@ -245,7 +250,7 @@ func newTestFixtures(t *testing.T) *testFixtures {
 	}
 
 	validator, jwtToken := newTestValidator(t)
-	router := newRouter(engine, validator, "", nil)
+	router := newRouter(engine, validator, "", nil, "")
 
 	t.Cleanup(func() {
 		engine.Close()
@@ -260,6 +265,7 @@ func newTestFixtures(t *testing.T) *testFixtures {
 		feedID:    feedID,
 		articleID: articleID,
 		jwtToken:  jwtToken,
+		dbPath:    dbPath,
 	}
 }
 
@@ -370,6 +376,9 @@ func TestHandleHome(t *testing.T) {
 	if !strings.Contains(body, "Test Feed") {
 		t.Error("home page should contain feed title")
 	}
+	if !strings.Contains(body, "read this week") {
+		t.Error("home page should show the reading stats card")
+	}
 }
 
 func TestHandleHome_Unauthenticated(t *testing.T) {
@@ -395,6 +404,42 @@ func TestHandleArticleList_Default(t *testing.T) {
 	}
 }
 
+func TestHandleArticleList_Order(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	pub := time.Now().Add(-48 * time.Hour)
+	if _, err := tf.store.AddArticle(&storage.Article{
+		FeedID:        tf.feedID,
+		GUID:          "guid-older",
+		Title:         "Older Article",
+		URL:           "https://example.com/article/older",
+		PublishedDate: &pub,
+	}); err != nil {
+		t.Fatalf("AddArticle: %v", err)
+	}
+
+	for order, first := range map[string]string{"newest": "Test Article", "oldest": "Older Article"} {
+		rr := authedRequest(t, tf, "GET", "/articles?order="+order, map[string]string{"HX-Request": "true"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("order %s: status %d, want %d", order, rr.Code, http.StatusOK)
+		}
+		body := rr.Body.String()
+		if i, j := strings.Index(body, "Test Article"), strings.Index(body, "Older Article"); i < 0 || j < 0 {
+			t.Fatalf("order %s: both articles should be listed", order)
+		} else if (first == "Test Article") != (i < j) {
+			t.Errorf("order %s: %q should come first", order, first)
+		}
+		if !strings.Contains(body, `value="`+order+`" selected`) {
+			t.Errorf("order %s: picker should show the current order", order)
+		}
+	}
+
+	rr := authedRequest(t, tf, "GET", "/articles?order=sideways", map[string]string{"HX-Request": "true"})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid order: status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
 func TestHandleArticleList_ByFeed(t *testing.T) {
 	tf := newTestFixtures(t)
 
@@ -420,6 +465,205 @@ func TestHandleArticleList_Starred_Empty(t *testing.T) {
 	}
 }
 
+func TestHandleArticleList_MinScore(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	score, sec := 8.0, 9.0
+	if err := tf.store.UpdateReadState(tf.userID, tf.articleID, false, &score, &sec, nil); err != nil {
+		t.Fatalf("UpdateReadState: %v", err)
+	}
+
+	rr := authedRequest(t, tf, "GET", "/articles?min_score=7", map[string]string{"HX-Request": "true"})
+	if rr.Code != http.StatusOK {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Test Article") {
+		t.Error("min_score list should contain the high-interest article")
+	}
+	if !strings.Contains(body, "raw 8.0") {
+		t.Error("score tooltip should include the raw score")
+	}
+
+	rr = authedRequest(t, tf, "GET", "/articles?min_score=9", map[string]string{"HX-Request": "true"})
+	if strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("min_score above the raw score should exclude the article")
+	}
+}
+
+func TestHandleArticleList_StoredScores(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	rr := authedRequest(t, tf, "GET", "/articles", map[string]string{"HX-Request": "true"})
+	if strings.Contains(rr.Body.String(), `class="score"`) {
+		t.Error("an unscored article should show no score badge")
+	}
+
+	score := 6.5
+	if err := tf.store.UpdateReadState(tf.userID, tf.articleID, false, &score, nil, nil); err != nil {
+		t.Fatalf("UpdateReadState: %v", err)
+	}
+	for _, path := range []string{"/articles", "/articles?feed_id=" + itoa(tf.feedID)} {
+		rr := authedRequest(t, tf, "GET", path, map[string]string{"HX-Request": "true"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: status %d, want %d", path, rr.Code, http.StatusOK)
+		}
+		if !strings.Contains(rr.Body.String(), `title="Interest score 6.5">6.5</span>`) {
+			t.Errorf("%s: row should include the stored interest score", path)
+		}
+	}
+}
+
+func TestHandleArticleList_Ungrouped(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	score := 8.0
+	if err := tf.store.UpdateReadState(tf.userID, tf.articleID, false, &score, nil, nil); err != nil {
+		t.Fatalf("UpdateReadState: %v", err)
+	}
+
+	rr := authedRequest(t, tf, "GET", "/articles?ungrouped=1", map[string]string{"HX-Request": "true"})
+	if rr.Code != http.StatusOK {
+		t.Errorf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("ungrouped list should contain the scored ungrouped article")
+	}
+
+	groupID, _ := tf.store.CreateArticleGroup(tf.userID, "Cluster")
+	tf.store.AddArticleToGroup(groupID, tf.articleID)
+	rr = authedRequest(t, tf, "GET", "/articles?ungrouped=1", map[string]string{"HX-Request": "true"})
+	if strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("grouped article should not appear in the ungrouped list")
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	// Unauthenticated: no cookie or bearer token.
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	rr := httptest.NewRecorder()
+	tf.router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	var body struct {
+		Status  string `json:"status"`
+		DBOK    bool   `json:"db_ok"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Status != "ok" || !body.DBOK || body.Version == "" {
+		t.Errorf("unexpected body: %+v", body)
+	}
+
+	// A closed database reports 503.
+	closed, err := herald.NewEngine(herald.EngineConfig{DBPath: tf.dbPath})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	closed.Close()
+	rr = httptest.NewRecorder()
+	newRouter(closed, nil, "", nil, "").ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("closed db status: got %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	// Without a configured token the endpoint is not served.
+	rr := httptest.NewRecorder()
+	tf.router.ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("no token configured: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+
+	// Poll totals come from the persisted history, not from counters in this
+	// process, which never polls.
+	for _, errored := range []int{2, 1} {
+		run := &herald.FetchResult{FeedsTotal: 3, FeedsErrored: errored}
+		if err := tf.engine.RecordPollRun(time.Now(), 1500*time.Millisecond, run); err != nil {
+			t.Fatalf("RecordPollRun: %v", err)
+		}
+	}
+
+	router := newRouter(tf.engine, nil, "", nil, "scrape-secret")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("missing token: got %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape-secret")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("content type: got %q", ct)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"# TYPE herald_feeds_total gauge",
+		"herald_feeds_total 1",
+		"herald_articles_total ",
+		"herald_unread_total 1",
+		"herald_feeds_erroring 0",
+		"herald_fetch_errors_total 3",
+		"herald_poll_duration_seconds_sum 3",
+		"herald_poll_duration_seconds_count 2",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "herald_ollama_calls_total") {
+		t.Errorf("metrics should not report model calls herald-web cannot observe:\n%s", body)
+	}
+	if strings.Contains(body, "user_id=") {
+		t.Errorf("metrics should not carry per-user labels:\n%s", body)
+	}
+}
+
+func TestReadOnlyModeRefusesWrites(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	ro, err := herald.NewEngine(herald.EngineConfig{DBPath: tf.dbPath, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("NewEngine read-only: %v", err)
+	}
+	t.Cleanup(func() { ro.Close() })
+	validator, jwtToken := newTestValidator(t)
+	tf.router = newRouter(ro, validator, "", nil, "")
+	tf.jwtToken = jwtToken
+
+	rr := authedRequest(t, tf, "GET", "/articles/"+itoa(tf.articleID), nil)
+	if rr.Code != http.StatusOK {
+		t.Errorf("article view status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	rr = authedRequest(t, tf, "POST", "/articles/"+itoa(tf.articleID)+"/star", nil)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("star status: got %d, want %d", rr.Code, http.StatusForbidden)
+	}
+	if rr.Header().Get(readOnlyHeader) == "" {
+		t.Error("star response should carry the read-only header")
+	}
+
+	rr = authedRequestForm(t, tf, "POST", "/settings", url.Values{"interest_threshold": {"7"}})
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("settings status: got %d, want %d", rr.Code, http.StatusForbidden)
+	}
+}
+
 func TestHandleArticleView(t *testing.T) {
 	tf := newTestFixtures(t)
 
@@ -437,6 +681,169 @@ func TestHandleArticleView(t *testing.T) {
 	}
 }
 
+func TestHandleArticlePermalink(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	slug := storage.ArticleSlug("https://example.com/feed", "guid-1")
+	rr := authedRequest(t, tf, "GET", "/a/"+slug, map[string]string{"HX-Request": "true"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Test Article") {
+		t.Error("permalink should render the article view")
+	}
+	if !strings.Contains(body, `href="/a/`+slug+`"`) {
+		t.Error("article view should link its permalink")
+	}
+
+	rr = authedRequest(t, tf, "GET", "/a/0000000000000000", map[string]string{"HX-Request": "true"})
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown slug: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleArticleView_AuthorsAndCategories(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	if err := tf.store.StoreArticleAuthors(tf.articleID, []storage.ArticleAuthor{
+		{Name: "Ada Lovelace"}, {Name: "Grace Hopper"},
+	}); err != nil {
+		t.Fatalf("StoreArticleAuthors: %v", err)
+	}
+	if err := tf.store.StoreArticleCategories(tf.articleID, []string{"Compilers", "C & C++"}); err != nil {
+		t.Fatalf("StoreArticleCategories: %v", err)
+	}
+
+	rr := authedRequest(t, tf, "GET", "/articles/"+itoa(tf.articleID), map[string]string{"HX-Request": "true"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Ada Lovelace, Grace Hopper") {
+		t.Error("article view should list both authors")
+	}
+	if !strings.Contains(body, `hx-get="/articles?category=Compilers"`) {
+		t.Error("article view should link the Compilers category")
+	}
+	// "C & C++" is query-escaped, then HTML-escaped.
+	if !strings.Contains(body, `hx-get="/articles?category=C&#43;%26&#43;C%2B%2B"`) {
+		t.Error("article view should link the query-escaped C & C++ category")
+	}
+
+	// The chip's target lists the article.
+	rr = authedRequest(t, tf, "GET", "/articles?category=compilers", map[string]string{"HX-Request": "true"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("category list status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("category list should contain the tagged article")
+	}
+}
+
+func TestHandleArticleView_StrictPolicy(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	pub := time.Now()
+	articleID, err := tf.store.AddArticle(&storage.Article{
+		FeedID:        tf.feedID,
+		GUID:          "guid-img",
+		Title:         "Picture Post",
+		URL:           "https://example.com/article/img",
+		Content:       `<p>Caption</p><img src="https://example.com/photo.jpg">`,
+		PublishedDate: &pub,
+	})
+	if err != nil {
+		t.Fatalf("AddArticle: %v", err)
+	}
+	path := "/articles/" + itoa(articleID)
+
+	rr := authedRequest(t, tf, "GET", path, map[string]string{"HX-Request": "true"})
+	if !strings.Contains(rr.Body.String(), "photo.jpg") {
+		t.Error("default policy should keep the image")
+	}
+
+	if err := tf.engine.SetPreference(tf.userID, "sanitize_policy", "strict"); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	rr = authedRequest(t, tf, "GET", path, map[string]string{"HX-Request": "true"})
+	body := rr.Body.String()
+	if strings.Contains(body, "photo.jpg") {
+		t.Error("strict policy should strip the image")
+	}
+	if !strings.Contains(body, "Caption") {
+		t.Error("strict policy should keep the text")
+	}
+}
+
+func TestHandleImageProxy(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	var png bytes.Buffer
+	if err := imagepng.Encode(&png, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	fetches := 0
+	imgSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png.Bytes()) //nolint:errcheck
+	}))
+	defer imgSrv.Close()
+
+	pub := time.Now()
+	articleID, err := tf.store.AddArticle(&storage.Article{
+		FeedID:        tf.feedID,
+		GUID:          "guid-proxy",
+		Title:         "Proxied Picture",
+		URL:           imgSrv.URL + "/post/1",
+		Content:       `<p>Caption</p><img src="/pics/a.png">`,
+		PublishedDate: &pub,
+	})
+	if err != nil {
+		t.Fatalf("AddArticle: %v", err)
+	}
+
+	rr := authedRequest(t, tf, "GET", "/articles/"+itoa(articleID), map[string]string{"HX-Request": "true"})
+	m := regexp.MustCompile(`<img src="(/img\?[^"]+)"`).FindStringSubmatch(rr.Body.String())
+	if m == nil {
+		t.Fatalf("img src not rewritten to the proxy:\n%s", rr.Body.String())
+	}
+	proxyPath := html.UnescapeString(m[1])
+	u, _ := url.Parse(proxyPath)
+	if got, want := u.Query().Get("u"), imgSrv.URL+"/pics/a.png"; got != want {
+		t.Errorf("proxied URL: got %q, want %q", got, want)
+	}
+
+	rr = authedRequest(t, tf, "GET", proxyPath, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("signed request: got %d, want 200", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Content-Type: got %q, want image/png", ct)
+	}
+	// The second request is served from the article image cache.
+	authedRequest(t, tf, "GET", proxyPath, nil)
+	if fetches != 1 {
+		t.Errorf("upstream fetches: got %d, want 1", fetches)
+	}
+
+	q := u.Query()
+	q.Del("s")
+	if rr := authedRequest(t, tf, "GET", "/img?"+q.Encode(), nil); rr.Code != http.StatusForbidden {
+		t.Errorf("unsigned request: got %d, want 403", rr.Code)
+	}
+	q.Set("s", strings.Repeat("0", 64))
+	if rr := authedRequest(t, tf, "GET", "/img?"+q.Encode(), nil); rr.Code != http.StatusForbidden {
+		t.Errorf("bad signature: got %d, want 403", rr.Code)
+	}
+	q = u.Query()
+	q.Set("u", "http://169.254.169.254/latest/meta-data")
+	if rr := authedRequest(t, tf, "GET", "/img?"+q.Encode(), nil); rr.Code != http.StatusForbidden {
+		t.Errorf("signature reused for another URL: got %d, want 403", rr.Code)
+	}
+}
+
 func TestHandleArticleView_SanitizesXSS(t *testing.T) {
 	tf := newTestFixtures(t)
 
@@ -502,6 +909,335 @@ func TestHandleStarToggle(t *testing.T) {
 	}
 }
 
+func TestHandleReadToggle(t *testing.T) {
+	tf := newTestFixtures(t)
+	score := 8.5
+	if err := tf.store.UpdateReadState(tf.userID, tf.articleID, false, &score, &score, nil); err != nil {
+		t.Fatalf("UpdateReadState: %v", err)
+	}
+
+	path := "/articles/" + itoa(tf.articleID) + "/read-toggle"
+	// Scores posted by the client are ignored; the row shows the stored one.
+	rr := authedRequestForm(t, tf, "POST", path, url.Values{"score": {"1.0"}, "raw_score": {"1.0"}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("toggle status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{`class="article-row read"`, "Mark unread", "Test Article", "Test Feed", ">8.5</span>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("row should contain %q, got:\n%s", want, body)
+		}
+	}
+	if rr.Header().Get("HX-Trigger") != "feeds-changed" {
+		t.Error("toggle should refresh the sidebar counts")
+	}
+	if scores, _ := tf.store.GetInterestScores(tf.userID, []int64{tf.articleID}); scores[tf.articleID] != score {
+		t.Errorf("interest score after toggle = %v, want %v", scores[tf.articleID], score)
+	}
+
+	rr = authedRequestForm(t, tf, "POST", path, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("second toggle status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	body = rr.Body.String()
+	if strings.Contains(body, `article-row read`) || !strings.Contains(body, "Mark read") {
+		t.Errorf("row should be unread after a second toggle, got:\n%s", body)
+	}
+	if unread, _ := tf.store.GetUnreadArticlesForUser(tf.userID, 10, 0, nil, nil); len(unread) != 1 {
+		t.Errorf("unread articles = %d, want 1", len(unread))
+	}
+}
+
+func TestHandleStarBatchAndQueue(t *testing.T) {
+	tf := newTestFixtures(t)
+	hx := map[string]string{"HX-Request": "true"}
+
+	rr := authedRequest(t, tf, "GET", "/queue", hx)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("queue status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("queue should be empty before anything is starred")
+	}
+
+	ids := itoa(tf.articleID) + ",bogus," + itoa(tf.articleID)
+	rr = authedRequestForm(t, tf, "POST", "/articles/star-batch", url.Values{"ids": {ids}})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("star-batch status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+	rr = authedRequest(t, tf, "GET", "/queue", hx)
+	if !strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("starred unread article should be in the queue")
+	}
+
+	if err := tf.engine.MarkArticleRead(tf.userID, tf.articleID); err != nil {
+		t.Fatalf("MarkArticleRead: %v", err)
+	}
+	rr = authedRequest(t, tf, "GET", "/queue", hx)
+	if strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("read article should leave the queue")
+	}
+	rr = authedRequest(t, tf, "GET", "/articles?starred=1", nil)
+	if !strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("read article should stay in the starred list")
+	}
+
+	rr = authedRequestForm(t, tf, "POST", "/articles/star-batch", url.Values{"ids": {itoa(tf.articleID)}, "starred": {"false"}})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("unstar-batch status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+	rr = authedRequest(t, tf, "GET", "/articles?starred=1", nil)
+	if strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("unstarred article should leave the starred list")
+	}
+
+	// A full-page load renders the home layout with the queue selected.
+	rr = authedRequest(t, tf, "GET", "/queue", nil)
+	if !strings.Contains(rr.Body.String(), `hx-get="/queue"`) {
+		t.Error("full-page /queue should load the queue list")
+	}
+}
+
+func TestHandleHistory(t *testing.T) {
+	tf := newTestFixtures(t)
+	hx := map[string]string{"HX-Request": "true"}
+
+	rr := authedRequest(t, tf, "GET", "/history", hx)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("history status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("history should be empty before anything is read")
+	}
+
+	if err := tf.engine.MarkArticleRead(tf.userID, tf.articleID); err != nil {
+		t.Fatalf("MarkArticleRead: %v", err)
+	}
+	rr = authedRequest(t, tf, "GET", "/history", hx)
+	if !strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("read article should be in the history")
+	}
+
+	rr = authedRequest(t, tf, "GET", "/history", nil)
+	if !strings.Contains(rr.Body.String(), `hx-get="/history" hx-trigger="load"`) {
+		t.Error("full-page /history should load the history list")
+	}
+}
+
+func TestHandleArticleView_CountsOpens(t *testing.T) {
+	tf := newTestFixtures(t)
+	hx := map[string]string{"HX-Request": "true"}
+
+	path := "/articles/" + itoa(tf.articleID)
+	for range 2 {
+		if rr := authedRequest(t, tf, "GET", path, hx); rr.Code != http.StatusOK {
+			t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+		}
+	}
+
+	history, err := tf.engine.GetReadHistory(tf.userID, 10, 0)
+	if err != nil {
+		t.Fatalf("GetReadHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].OpenCount != 2 {
+		t.Fatalf("history = %+v, want the article with open_count 2", history)
+	}
+
+	rr := authedRequest(t, tf, "GET", "/history", hx)
+	if !strings.Contains(rr.Body.String(), "opened 2 times") {
+		t.Error("history should show how often the article was opened")
+	}
+}
+
+func TestHandleBlocked(t *testing.T) {
+	tf := newTestFixtures(t)
+	hx := map[string]string{"HX-Request": "true"}
+
+	zero, sec := 0.0, 1.5
+	reason := "tries to override the reader's instructions"
+	if err := tf.store.UpdateReadState(tf.userID, tf.articleID, false, &zero, &sec, &reason); err != nil {
+		t.Fatalf("UpdateReadState: %v", err)
+	}
+
+	rr := authedRequest(t, tf, "GET", "/blocked", hx)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want 200", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Test Article") {
+		t.Error("blocked article should be listed")
+	}
+	if !strings.Contains(body, "security 1.5") || !strings.Contains(body, "override the reader&#39;s instructions") {
+		t.Errorf("blocked list should show the security score and reason:\n%s", body)
+	}
+
+	if !strings.Contains(body, `hx-post="/articles/`+itoa(tf.articleID)+`/unblock"`) {
+		t.Error("blocked article should offer an unblock button")
+	}
+
+	rr = authedRequest(t, tf, "GET", "/articles?min_score=1", hx)
+	if strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("blocked article should not appear in the scored unread list")
+	}
+
+	// Unblocking re-runs curation, which needs AI.
+	rr = authedRequest(t, tf, "POST", "/articles/"+itoa(tf.articleID)+"/unblock", hx)
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("unblock without AI: got %d, want 500", rr.Code)
+	}
+
+	rr = authedRequest(t, tf, "GET", "/blocked", nil)
+	if !strings.Contains(rr.Body.String(), `hx-get="/blocked"`) {
+		t.Error("full-page /blocked should load the blocked list")
+	}
+}
+
+func TestHandleFeedMute(t *testing.T) {
+	tf := newTestFixtures(t)
+	path := "/feeds/" + itoa(tf.feedID) + "/mute"
+
+	rr := authedRequestForm(t, tf, "POST", path, url.Values{"muted": {"true"}})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("mute status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+	rr = authedRequest(t, tf, "GET", "/articles", nil)
+	if strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("muted feed's article should not be in the unread list")
+	}
+	rr = authedRequest(t, tf, "GET", "/articles?feed_id="+itoa(tf.feedID), nil)
+	if !strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("muted feed's article should still show when browsing the feed")
+	}
+	rr = authedRequest(t, tf, "GET", "/", nil)
+	if !strings.Contains(rr.Body.String(), "feed-muted") {
+		t.Error("sidebar should mark the muted feed")
+	}
+
+	rr = authedRequestForm(t, tf, "POST", path, url.Values{"muted": {"false"}})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("unmute status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+	rr = authedRequest(t, tf, "GET", "/articles", nil)
+	if !strings.Contains(rr.Body.String(), "Test Article") {
+		t.Error("unmuted feed's article should be back in the unread list")
+	}
+}
+
+func TestHandleArticleNote(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	path := "/articles/" + itoa(tf.articleID) + "/note"
+
+	rr := authedRequestForm(t, tf, "POST", path, url.Values{"note": {"  follow up on this  "}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("save status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), ">follow up on this</textarea>") {
+		t.Errorf("response should contain the trimmed note, got:\n%s", rr.Body.String())
+	}
+
+	rr = authedRequest(t, tf, "GET", "/articles/"+itoa(tf.articleID), nil)
+	if !strings.Contains(rr.Body.String(), "follow up on this") {
+		t.Error("article view should show the saved note")
+	}
+
+	rr = authedRequestForm(t, tf, "POST", path, url.Values{"note": {""}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("clear status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if n, err := tf.store.GetArticleNote(tf.userID, tf.articleID); err != nil || n != nil {
+		t.Errorf("GetArticleNote after clear = %v, %v; want nil, nil", n, err)
+	}
+}
+
+func TestHandleNewArticles(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	// The user last looked two hours ago, before the fixture article arrived.
+	if err := tf.store.SetUserLastSeen(tf.userID, time.Now().Add(-2*time.Hour), time.Time{}); err != nil {
+		t.Fatalf("SetUserLastSeen: %v", err)
+	}
+
+	// Both loads belong to the same visit, so the second must not lose the
+	// articles the first one showed.
+	for i := range 2 {
+		rr := authedRequest(t, tf, "GET", "/new", map[string]string{"HX-Request": "true"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("load %d status: got %d, want %d", i, rr.Code, http.StatusOK)
+		}
+		if !strings.Contains(rr.Body.String(), "Test Article") {
+			t.Errorf("load %d should list the article fetched since the last visit", i)
+		}
+	}
+
+	last, prev, err := tf.store.GetUserLastSeen(tf.userID)
+	if err != nil {
+		t.Fatalf("GetUserLastSeen: %v", err)
+	}
+	if time.Since(last) > time.Minute {
+		t.Errorf("last_seen_at = %v, want about now", last)
+	}
+	if time.Since(prev) < time.Hour {
+		t.Errorf("prev_seen_at = %v, want the earlier visit", prev)
+	}
+}
+
+func TestHandleFeedFavicon(t *testing.T) {
+	tf := newTestFixtures(t)
+	path := "/feeds/" + itoa(tf.feedID) + "/favicon"
+
+	// No favicon cached yet: the generic icon is served instead of a 404.
+	rr := authedRequest(t, tf, "GET", path, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("fallback status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "image/svg+xml" {
+		t.Errorf("fallback Content-Type = %q, want image/svg+xml", ct)
+	}
+
+	icon := []byte("\x89PNG\r\n\x1a\nfake-icon-bytes")
+	if err := tf.store.StoreFeedFavicon(tf.feedID, icon, "image/png"); err != nil {
+		t.Fatalf("StoreFeedFavicon: %v", err)
+	}
+	rr = authedRequest(t, tf, "GET", path, nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", ct)
+	}
+	if rr.Body.String() != string(icon) {
+		t.Errorf("body = %q, want the cached favicon bytes", rr.Body.String())
+	}
+}
+
+func TestHandleFilterImportExport(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	rules := `[{"axis":"author","value":"Alice","score":2},{"axis":"author","value":"Alice","score":2},{"axis":"title","value":"ad","block":true}]`
+	rr := authedRequestForm(t, tf, "POST", "/filters/import", url.Values{"rules": {rules}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("import status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, "Imported 2 rules; skipped 1 already defined.") {
+		t.Errorf("import result missing from the rules table: %s", body)
+	}
+
+	rr = authedRequest(t, tf, "GET", "/filters/export.json", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("export status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	var exported []herald.FilterRule
+	if err := json.Unmarshal(rr.Body.Bytes(), &exported); err != nil || len(exported) != 2 {
+		t.Errorf("export: got %d rules, err %v; want 2", len(exported), err)
+	}
+
+	rr = authedRequestForm(t, tf, "POST", "/filters/import", url.Values{"rules": {"{not json"}})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("malformed import: got %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
 func TestHandleSidebar(t *testing.T) {
 	tf := newTestFixtures(t)
 
@@ -530,6 +1266,70 @@ func TestHandleFeedsManage(t *testing.T) {
 	}
 }
 
+func TestHandleFeedHeaders(t *testing.T) {
+	tf := newTestFixtures(t)
+	path := "/feeds/" + itoa(tf.feedID) + "/headers"
+
+	rr := authedRequestForm(t, tf, "POST", path, url.Values{"name": {"authorization"}, "value": {"Bearer s3cret"}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("set status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, "Authorization") || strings.Contains(body, "s3cret") {
+		t.Errorf("fragment should list the header name with a masked value: %s", body)
+	}
+	headers, _ := tf.engine.GetFeedFetchHeaders(tf.userID, tf.feedID)
+	if headers["Authorization"] != "Bearer s3cret" {
+		t.Errorf("stored headers = %v", headers)
+	}
+
+	rr = authedRequest(t, tf, "GET", "/feeds", nil)
+	if body := rr.Body.String(); !strings.Contains(body, "Fetch headers (1)") || strings.Contains(body, "s3cret") {
+		t.Error("feeds page should count the header without revealing its value")
+	}
+
+	rr = authedRequestForm(t, tf, "POST", path, url.Values{"name": {"Bad Name"}, "value": {"x"}})
+	if !strings.Contains(rr.Body.String(), "invalid header name") {
+		t.Errorf("expected validation error, got: %s", rr.Body.String())
+	}
+
+	authedRequestForm(t, tf, "POST", path, url.Values{"name": {"Authorization"}, "value": {""}})
+	if headers, _ := tf.engine.GetFeedFetchHeaders(tf.userID, tf.feedID); len(headers) != 0 {
+		t.Errorf("header not removed: %v", headers)
+	}
+}
+
+func TestHandleFeedsManageSearch(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	otherID, err := tf.store.AddFeed("https://blog.example.org/rss", "Other Blog", "")
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	if err := tf.store.SubscribeUserToFeed(tf.userID, otherID); err != nil {
+		t.Fatalf("SubscribeUserToFeed: %v", err)
+	}
+
+	rr := authedRequest(t, tf, "GET", "/feeds?q=other", map[string]string{"HX-Request": "true"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "Other Blog") {
+		t.Error("filtered list should contain the matching feed")
+	}
+	if strings.Contains(body, "Test Feed") {
+		t.Error("filtered list should not contain non-matching feeds")
+	}
+	if strings.Contains(body, "Subscribe to Feed") {
+		t.Error("htmx filter should render only the feed list fragment")
+	}
+
+	rr = authedRequest(t, tf, "GET", "/feeds?q=nothing-matches", map[string]string{"HX-Request": "true"})
+	if !strings.Contains(rr.Body.String(), "No feeds match") {
+		t.Error("empty filter result should say nothing matched")
+	}
+}
+
 func TestHandleSettings(t *testing.T) {
 	tf := newTestFixtures(t)
 
@@ -547,6 +1347,8 @@ func TestHandleSettingsSave(t *testing.T) {
 		"interest_threshold": {"7.5"},
 		"notify_when":        {"always"},
 		"notify_min_score":   {"6.0"},
+		"avoid_keywords":     {"crypto, NFT:3"},
+		"autostar_min_score": {"9"},
 	})
 	if rr.Code != http.StatusOK {
 		t.Errorf("status: got %d, want %d", rr.Code, http.StatusOK)
@@ -562,6 +1364,18 @@ func TestHandleSettingsSave(t *testing.T) {
 	if prefs.NotifyWhen != "always" {
 		t.Errorf("notify_when: got %q, want always", prefs.NotifyWhen)
 	}
+	if prefs.AutostarMinScore != 9 {
+		t.Errorf("autostar_min_score: got %f, want 9", prefs.AutostarMinScore)
+	}
+	if got := formatKeywords(prefs.AvoidKeywords); got != "crypto, NFT:3" {
+		t.Errorf("avoid_keywords: got %q, want %q", got, "crypto, NFT:3")
+	}
+
+	// An empty avoid list clears it.
+	authedRequestForm(t, tf, "POST", "/settings", url.Values{"avoid_keywords": {""}})
+	if prefs, _ := tf.engine.GetPreferences(tf.userID); len(prefs.AvoidKeywords) != 0 {
+		t.Errorf("avoid_keywords after clearing: got %v, want none", prefs.AvoidKeywords)
+	}
 }
 
 func TestHandleOIDCUserProvisioning(t *testing.T) {
@@ -611,14 +1425,55 @@ func TestFormatDate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := formatDate(tt.time)
+			got := formatDateIn(tt.time, time.UTC)
 			if got != tt.want {
-				t.Errorf("formatDate: got %q, want %q", got, tt.want)
+				t.Errorf("formatDateIn: got %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestFormatDateIn_Zones(t *testing.T) {
+	// 02:30 UTC on Jan 15 is still Jan 14 in New York.
+	ts := time.Date(2024, 1, 15, 2, 30, 0, 0, time.UTC)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	if got := formatDateIn(&ts, tokyo); got != "Jan 15, 2024" {
+		t.Errorf("Tokyo: got %q", got)
+	}
+	if got := formatDateIn(&ts, newYork); got != "Jan 14, 2024" {
+		t.Errorf("New York: got %q", got)
+	}
+	if got := formatAbsoluteDateIn(&ts, tokyo); got != "Jan 15, 2024 11:30 AM" {
+		t.Errorf("Tokyo absolute: got %q", got)
+	}
+	if got := formatAbsoluteDateIn(&ts, newYork); got != "Jan 14, 2024 9:30 PM" {
+		t.Errorf("New York absolute: got %q", got)
+	}
+}
+
+func TestDateFormatterFor(t *testing.T) {
+	tf := newTestFixtures(t)
+	h := &handlers{engine: tf.engine}
+
+	if df := h.dateFormatterFor(tf.userID); df.loc != time.Local || df.absolute {
+		t.Errorf("default formatter = %+v, want server local and relative", df)
+	}
+
+	tf.store.SetUserPreference(tf.userID, "timezone", "Not/AZone")   //nolint:errcheck
+	tf.store.SetUserPreference(tf.userID, "date_format", "absolute") //nolint:errcheck
+	if df := h.dateFormatterFor(tf.userID); df.loc != time.UTC || !df.absolute {
+		t.Errorf("invalid timezone formatter = %+v, want UTC and absolute", df)
+	}
+}
+
 func TestBestDate(t *testing.T) {
 	now := time.Now()
 	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
@@ -693,7 +1548,7 @@ func TestHandleCallback_SetsJWTCookie(t *testing.T) {
 	tf := newTestFixtures(t)
 
 	validator := newTestValidatorWithOIDC(t, nil)
-	router := newRouter(tf.engine, validator, "", nil)
+	router := newRouter(tf.engine, validator, "", nil, "")
 
 	state := "test-state-nonce"
 	verifier := "test-pkce-verifier"
@@ -730,7 +1585,7 @@ func TestHandleCallback_DefaultRedirect(t *testing.T) {
 	tf := newTestFixtures(t)
 
 	validator := newTestValidatorWithOIDC(t, nil)
-	router := newRouter(tf.engine, validator, "", nil)
+	router := newRouter(tf.engine, validator, "", nil, "")
 
 	state := "test-state"
 	req := httptest.NewRequest("GET", "/auth/callback?code=test-code&state="+state, nil)
@@ -753,7 +1608,7 @@ func TestHandleCallback_InvalidState(t *testing.T) {
 	tf := newTestFixtures(t)
 
 	validator := newTestValidatorWithOIDC(t, nil)
-	router := newRouter(tf.engine, validator, "", nil)
+	router := newRouter(tf.engine, validator, "", nil, "")
 
 	req := httptest.NewRequest("GET", "/auth/callback?code=test-code&state=WRONG", nil)
 	req.AddCookie(&http.Cookie{Name: oidclient.CookieState, Value: "correct-state"})
@@ -771,7 +1626,7 @@ func TestHandleCallback_MissingVerifier(t *testing.T) {
 	tf := newTestFixtures(t)
 
 	validator := newTestValidatorWithOIDC(t, nil)
-	router := newRouter(tf.engine, validator, "", nil)
+	router := newRouter(tf.engine, validator, "", nil, "")
 
 	state := "test-state"
 	req := httptest.NewRequest("GET", "/auth/callback?code=test-code&state="+state, nil)
@@ -793,7 +1648,7 @@ func TestHandleCallback_TokenExchangeError(t *testing.T) {
 	validator := newTestValidatorWithOIDC(t, func(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid_grant", http.StatusUnauthorized)
 	})
-	router := newRouter(tf.engine, validator, "", nil)
+	router := newRouter(tf.engine, validator, "", nil, "")
 
 	state := "test-state"
 	req := httptest.NewRequest("GET", "/auth/callback?code=bad-code&state="+state, nil)
@@ -812,7 +1667,7 @@ func TestHandleCallback_UpstreamAuthError(t *testing.T) {
 	tf := newTestFixtures(t)
 
 	validator := newTestValidatorWithOIDC(t, nil)
-	router := newRouter(tf.engine, validator, "", nil)
+	router := newRouter(tf.engine, validator, "", nil, "")
 
 	// Webauth redirects with ?error=access_denied when the user denies.
 	req := httptest.NewRequest("GET", "/auth/callback?error=access_denied&error_description=User+denied+access", nil)
@@ -891,6 +1746,103 @@ func TestHandleGroupMute(t *testing.T) {
 	}
 }
 
+func TestHandleGroupRename(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	groupID, _ := tf.store.CreateArticleGroup(tf.userID, "Old Topic")
+
+	path := "/groups/" + itoa(groupID)
+	rr := authedRequestForm(t, tf, "PATCH", path, url.Values{"name": {"New Topic"}})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("rename status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+
+	group, _ := tf.store.GetGroup(groupID)
+	if group == nil || group.Topic != "New Topic" {
+		t.Errorf("topic after rename: got %+v, want %q", group, "New Topic")
+	}
+
+	rr = authedRequestForm(t, tf, "PATCH", path, url.Values{"name": {"  "}})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("blank rename status: got %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleGroupMerge(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	srcID, _ := tf.store.CreateArticleGroup(tf.userID, "Source")
+	tf.store.AddArticleToGroup(srcID, tf.articleID)
+	dstID, _ := tf.store.CreateArticleGroup(tf.userID, "Target")
+
+	path := "/groups/" + itoa(srcID) + "/merge"
+	rr := authedRequestForm(t, tf, "POST", path, url.Values{"target_id": {itoa(dstID)}})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("merge status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+
+	if group, _ := tf.store.GetGroup(srcID); group != nil {
+		t.Error("source group should be deleted after merge")
+	}
+	members, _ := tf.store.GetGroupArticles(dstID)
+	if len(members) != 1 || members[0].ID != tf.articleID {
+		t.Errorf("target members after merge: got %d, want 1 (article %d)", len(members), tf.articleID)
+	}
+}
+
+func TestHandleGroupResummarize(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	groupID, _ := tf.store.CreateArticleGroup(tf.userID, "Story")
+	tf.store.AddArticleToGroup(groupID, tf.articleID)
+
+	rr := authedRequest(t, tf, "GET", "/articles?group_id="+itoa(groupID), nil)
+	if !strings.Contains(rr.Body.String(), `hx-post="/groups/`+itoa(groupID)+`/summary"`) {
+		t.Error("group banner should include the regenerate summary button")
+	}
+
+	// The fixture engine has no AI processor, so regeneration fails cleanly.
+	rr = authedRequest(t, tf, "POST", "/groups/"+itoa(groupID)+"/summary", nil)
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("resummarize without AI: got %d, want %d", rr.Code, http.StatusInternalServerError)
+	}
+	rr = authedRequest(t, tf, "POST", "/groups/abc/summary", nil)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("invalid group ID: got %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleArticleMoveGroup(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	srcID, _ := tf.store.CreateArticleGroup(tf.userID, "Source")
+	tf.store.AddArticleToGroup(srcID, tf.articleID)
+	dstID, _ := tf.store.CreateArticleGroup(tf.userID, "Target")
+
+	// The article view offers the move dropdown.
+	rr := authedRequest(t, tf, "GET", "/articles/"+itoa(tf.articleID), nil)
+	if !strings.Contains(rr.Body.String(), `hx-post="/articles/`+itoa(tf.articleID)+`/group"`) {
+		t.Error("article view should include the move-to-group dropdown")
+	}
+
+	path := "/articles/" + itoa(tf.articleID) + "/group"
+	rr = authedRequestForm(t, tf, "POST", path, url.Values{"group_id": {itoa(dstID)}})
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("move status: got %d, want %d", rr.Code, http.StatusNoContent)
+	}
+
+	members, _ := tf.store.GetGroupArticles(dstID)
+	if len(members) != 1 || members[0].ID != tf.articleID {
+		t.Errorf("target members after move: got %d, want 1 (article %d)", len(members), tf.articleID)
+	}
+
+	otherID, _ := tf.store.CreateArticleGroup(tf.userID+1, "Not Mine")
+	rr = authedRequestForm(t, tf, "POST", path, url.Values{"group_id": {itoa(otherID)}})
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("move into foreign group status: got %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
 func TestHandleGroupDisband(t *testing.T) {
 	tf := newTestFixtures(t)
 
@@ -909,3 +1861,82 @@ func TestHandleGroupDisband(t *testing.T) {
 		t.Error("group should be deleted after DELETE /groups/{id}")
 	}
 }
+
+func TestHandleFeedUnsubscribeUndo(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	rr := authedRequest(t, tf, "DELETE", "/feeds/"+itoa(tf.feedID), nil)
+	redirect := rr.Header().Get("HX-Redirect")
+	if !strings.HasPrefix(redirect, "/feeds?") || !strings.Contains(redirect, "undo="+itoa(tf.feedID)) {
+		t.Fatalf("HX-Redirect = %q, want /feeds with an undo parameter", redirect)
+	}
+	if feeds, _ := tf.engine.GetUserFeeds(tf.userID); len(feeds) != 0 {
+		t.Fatalf("expected no feeds after unsubscribe, got %d", len(feeds))
+	}
+
+	rr = authedRequest(t, tf, "GET", redirect, nil)
+	body := rr.Body.String()
+	if !strings.Contains(body, "/feeds/"+itoa(tf.feedID)+"/resubscribe") {
+		t.Errorf("feeds page should offer an undo toast:\n%s", body)
+	}
+
+	rr = authedRequest(t, tf, "POST", "/feeds/"+itoa(tf.feedID)+"/resubscribe", nil)
+	if rr.Header().Get("HX-Redirect") != "/feeds" {
+		t.Errorf("resubscribe: got %d, HX-Redirect %q", rr.Code, rr.Header().Get("HX-Redirect"))
+	}
+	if feeds, _ := tf.engine.GetUserFeeds(tf.userID); len(feeds) != 1 {
+		t.Fatalf("expected the feed back after undo, got %d feeds", len(feeds))
+	}
+	if _, err := tf.engine.GetArticle(tf.articleID); err != nil {
+		t.Errorf("article should survive the undo: %v", err)
+	}
+
+	rr = authedRequest(t, tf, "POST", "/feeds/999999/resubscribe", nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("missing feed: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleArticleReader(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	rr := authedRequest(t, tf, "GET", "/articles/"+itoa(tf.articleID)+"/reader", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{"<html", "Test Article", "<p>Hello, world!</p>", "https://example.com/article/1"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("reader page missing %q", want)
+		}
+	}
+
+	rr = authedRequest(t, tf, "GET", "/articles/999999/reader", nil)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("missing article: got %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleStatus(t *testing.T) {
+	tf := newTestFixtures(t)
+
+	rr := authedRequest(t, tf, "GET", "/status", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status: got %d, want %d", rr.Code, http.StatusOK)
+	}
+	if !strings.Contains(rr.Body.String(), "No polls recorded yet") {
+		t.Error("empty history should say no polls are recorded")
+	}
+
+	run := &herald.FetchResult{FeedsTotal: 12, FeedsDownloaded: 7, NewArticles: 31, ProcessedCount: 29, FeedsErrored: 2}
+	if err := tf.engine.RecordPollRun(time.Now(), 4200*time.Millisecond, run); err != nil {
+		t.Fatalf("RecordPollRun: %v", err)
+	}
+	rr = authedRequest(t, tf, "GET", "/status", nil)
+	body := rr.Body.String()
+	for _, want := range []string{"4.2s", ">12<", ">31<", ">29<"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("status page missing %q", want)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,6 +13,7 @@ import (
 
 	"github.com/infodancer/oidclient"
 	herald "github.com/matthewjhunter/herald"
+	heraldlog "github.com/matthewjhunter/herald/internal/logging"
 )
 
 // version and buildTime are optionally injected at build time via ldflags.
@@ -54,6 +54,11 @@ func main() {
 	// CLI flags — all default to \"\" so config file values take effect when flags are omitted.
 	dbPath := flag.String("db", "", "path to SQLite database (default ./herald.db)")
 	addr := flag.String("addr", "", "listen address (default :8080)")
+	readOnly := flag.Bool("read-only", false, "open the database read-only; starring, marking read and settings changes are refused")
+	logFormat := flag.String("log-format", "", "log output format: text or json (default text)")
+	logLevel := flag.String("log-level", "", "minimum log level: debug, info, warn or error (default info)")
+	accessLogFlag := flag.String("access-log", "", "request logging: on, off, or the fraction of requests to log, e.g. 0.1 (default on)")
+	metricsTokenFlag := flag.String("metrics-token", "", "bearer token Prometheus must send to scrape /metrics (default: /metrics disabled)")
 
 	// Auth flags.
 	webauthIssuer := flag.String("webauth-issuer", "", "OIDC issuer URL, e.g. https://auth.infodancer.net/t/infodancer (enables autodiscovery)")
@@ -80,6 +85,19 @@ func main() {
 	clientID := mergeString(*webauthClientID, cfg.Webauth.ClientID)
 	callbackURL := mergeString(*webauthCallbackURL, cfg.Webauth.CallbackURL)
 
+	logger, err := heraldlog.Setup(os.Stderr, mergeString(*logFormat, cfg.LogFormat), mergeString(*logLevel, cfg.LogLevel))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "herald-web: %v\n", err)
+		os.Exit(1)
+	}
+
+	accessLogOpts, err := accessLogSettings(*accessLogFlag, cfg.AccessLog)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "herald-web: %v\n", err)
+		os.Exit(1)
+	}
+	accessLogOpts.Logger = logger
+
 	if issuerURL == "" {
 		fmt.Fprintln(os.Stderr, "herald-web: webauth.issuer_url (or -webauth-issuer) is required")
 		os.Exit(1)
@@ -99,8 +117,11 @@ func main() {
 	}
 
 	engine, err := herald.NewEngine(herald.EngineConfig{
-		DBPath:   db,
-		ReadOnly: true,
+		DBPath:      db,
+		BusyTimeout: cfg.BusyTimeout,
+		JournalMode: cfg.JournalMode,
+		ReadOnly:    *readOnly || cfg.ReadOnly,
+		Logger:      logger,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "herald-web: %v\n", err)
@@ -108,11 +129,11 @@ func main() {
 	}
 	defer engine.Close()
 
-	mux := newRouter(engine, validator, cfg.Admin.Role, cfg.Admin.Users)
+	mux := newRouter(engine, validator, cfg.Admin.Role, cfg.Admin.Users, mergeString(*metricsTokenFlag, cfg.Metrics.Token))
 
 	srv := &http.Server{
 		Addr:         listenAddr,
-		Handler:      logging(recovery(mux)),
+		Handler:      accessLog(accessLogOpts, recovery(mux)),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -123,20 +144,22 @@ func main() {
 	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
-		log.Printf("herald-web: listening on %s", listenAddr)
+		logger.Info("listening", "event", "server_start", "addr", listenAddr, "version", version)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("herald-web: %v", err)
+			logger.Error("listen failed", "err", err)
+			os.Exit(1)
 		}
 	}()
 
 	<-done
-	log.Println("herald-web: shutting down...")
+	logger.Info("shutting down", "event", "server_stop")
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("herald-web: shutdown error: %v", err)
+		logger.Error("shutdown failed", "err", err)
+		os.Exit(1)
 	}
-	log.Println("herald-web: stopped")
+	logger.Info("stopped")
 }
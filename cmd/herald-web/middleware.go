@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/infodancer/oidclient"
@@ -45,6 +47,11 @@ func (h *handlers) requireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		claims, err := h.validator.ValidateCookie(r)
 		if err != nil {
+			// API clients can't follow a login redirect.
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				writeAPIError(w, http.StatusUnauthorized, "Not authenticated")
+				return
+			}
 			// For HTMX partial requests, the fragment URL (e.g. /sidebar) is not a
 			// meaningful post-login destination — redirect to the home page instead.
 			returnTo := r.URL.RequestURI()
@@ -81,7 +88,7 @@ func (h *handlers) requireAuth(next http.Handler) http.Handler {
 
 		user, err := h.engine.GetOrProvisionOIDCUser(claims.Sub, claims.Name, claims.Email)
 		if err != nil {
-			log.Printf("herald-web: provision user: %v", err)
+			slog.Error("provision user failed", "sub", claims.Sub, "err", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
@@ -92,22 +99,84 @@ func (h *handlers) requireAuth(next http.Handler) http.Handler {
 	})
 }
 
-// logging logs each request with method, path, status, and duration.
-func logging(next http.Handler) http.Handler {
+// defaultAccessLogExclude lists the paths left out of the access log when
+// none are configured. Health checks and metrics scrapes would otherwise
+// drown out real traffic.
+var defaultAccessLogExclude = []string{"/healthz", "/metrics"}
+
+// accessLogOptions configures the access log middleware.
+type accessLogOptions struct {
+	// SampleRate is the fraction of requests logged: 1 logs every request,
+	// 0 none.
+	SampleRate float64
+	// Exclude lists paths never logged. Each entry matches the path itself
+	// and everything beneath it, so "/static" covers "/static/app.css".
+	Exclude []string
+	// Logger receives the log lines; nil means slog.Default().
+	Logger *slog.Logger
+
+	// random returns a number in [0, 1) for sampling; tests replace it.
+	random func() float64
+}
+
+// accessLog logs a sample of requests with method, path, status and duration.
+// Query values are redacted, since they can carry search terms and tokens;
+// the keys are kept.
+func accessLog(opts accessLogOptions, next http.Handler) http.Handler {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.random == nil {
+		opts.random = rand.Float64
+	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if excludedPath(r.URL.Path, opts.Exclude) || opts.SampleRate <= 0 ||
+			(opts.SampleRate < 1 && opts.random() >= opts.SampleRate) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		start := time.Now()
 		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
 		next.ServeHTTP(rw, r)
-		log.Printf("%s %s %d %s", r.Method, r.URL.Path, rw.status, time.Since(start).Round(time.Millisecond))
+		attrs := []any{"event", "http_request", "method", r.Method, "path", r.URL.Path}
+		if r.URL.RawQuery != "" {
+			attrs = append(attrs, "query", redactQuery(r.URL.RawQuery))
+		}
+		attrs = append(attrs, "status", rw.status, "duration_ms", time.Since(start).Milliseconds())
+		opts.Logger.Info("request", attrs...)
 	})
 }
 
+// excludedPath reports whether path is one of prefixes or lies beneath one.
+func excludedPath(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		p = strings.TrimSuffix(p, "/")
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// redactQuery replaces every value in a raw query string with "REDACTED",
+// keeping the keys and their order: "q=secret&page=2" becomes
+// "q=REDACTED&page=REDACTED".
+func redactQuery(rawQuery string) string {
+	parts := strings.Split(rawQuery, "&")
+	for i, part := range parts {
+		if key, _, found := strings.Cut(part, "="); found {
+			parts[i] = key + "=REDACTED"
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
 // recovery catches panics and returns a 500.
 func recovery(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("herald-web: panic: %v", err)
+				slog.Error("panic", "event", "http_panic", "method", r.Method, "path", r.URL.Path, "err", err)
 				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			}
 		}()
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccessLog(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	serve := func(opts accessLogOptions, target string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		opts.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+		accessLog(opts, ok).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil))
+		return buf.String()
+	}
+
+	line := serve(accessLogOptions{SampleRate: 1}, "/search?q=private+medical+question&page=2")
+	for _, want := range []string{"method=GET", "path=/search", `query="q=REDACTED&page=REDACTED"`, "status=418", "duration_ms="} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log line missing %q: %s", want, line)
+		}
+	}
+	if strings.Contains(line, "medical") {
+		t.Errorf("log line leaks the query value: %s", line)
+	}
+
+	// Sampled out: the draw is above the rate.
+	sampled := accessLogOptions{SampleRate: 0.25, random: func() float64 { return 0.5 }}
+	if line := serve(sampled, "/articles"); line != "" {
+		t.Errorf("sampled-out request was logged: %s", line)
+	}
+	sampled.random = func() float64 { return 0.1 }
+	if line := serve(sampled, "/articles"); line == "" {
+		t.Error("sampled-in request was not logged")
+	}
+
+	if line := serve(accessLogOptions{SampleRate: 0}, "/articles"); line != "" {
+		t.Errorf("request logged with logging off: %s", line)
+	}
+
+	excluded := accessLogOptions{SampleRate: 1, Exclude: []string{"/static/", "/healthz"}}
+	for _, path := range []string{"/static/app.css", "/healthz"} {
+		if line := serve(excluded, path); line != "" {
+			t.Errorf("excluded path %s was logged: %s", path, line)
+		}
+	}
+	if line := serve(excluded, "/staticky"); line == "" {
+		t.Error("/staticky should not match the /static exclusion")
+	}
+}
+
+func TestAccessLogSettings(t *testing.T) {
+	opts, err := accessLogSettings("", AccessLogConfig{})
+	if err != nil {
+		t.Fatalf("defaults: %v", err)
+	}
+	if opts.SampleRate != 1 || len(opts.Exclude) != len(defaultAccessLogExclude) {
+		t.Errorf("defaults: got rate %v exclude %v", opts.SampleRate, opts.Exclude)
+	}
+
+	opts, _ = accessLogSettings("", AccessLogConfig{SampleRate: 0.1, Exclude: []string{}})
+	if opts.SampleRate != 0.1 || len(opts.Exclude) != 0 {
+		t.Errorf("config: got rate %v exclude %v", opts.SampleRate, opts.Exclude)
+	}
+
+	// The flag wins over the config file.
+	if opts, _ := accessLogSettings("on", AccessLogConfig{Off: true}); opts.SampleRate != 1 {
+		t.Errorf("-access-log on: got rate %v", opts.SampleRate)
+	}
+	if opts, _ := accessLogSettings("0.5", AccessLogConfig{}); opts.SampleRate != 0.5 {
+		t.Errorf("-access-log 0.5: got rate %v", opts.SampleRate)
+	}
+	if opts, _ := accessLogSettings("off", AccessLogConfig{}); opts.SampleRate != 0 {
+		t.Errorf("-access-log off: got rate %v", opts.SampleRate)
+	}
+
+	for _, bad := range []string{"sometimes", "1.5", "-0.1"} {
+		if _, err := accessLogSettings(bad, AccessLogConfig{}); err == nil {
+			t.Errorf("-access-log %q: expected an error", bad)
+		}
+	}
+	if _, err := accessLogSettings("", AccessLogConfig{SampleRate: 2}); err == nil {
+		t.Error("sample_rate 2: expected an error")
+	}
+}
@@ -13,16 +13,22 @@ import (
 var embedded embed.FS
 
 // newRouter sets up all routes using Go 1.22+ enhanced routing.
-func newRouter(engine *herald.Engine, validator *oidclient.Client, adminRole string, adminUsers []string) http.Handler {
+func newRouter(engine *herald.Engine, validator *oidclient.Client, adminRole string, adminUsers []string, metricsToken string) http.Handler {
 	mux := http.NewServeMux()
 
 	// Static files — no auth required.
 	staticFS, _ := fs.Sub(embedded, "static")
 	mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(staticFS)))
 
-	h := &handlers{engine: engine, validator: validator, adminRole: adminRole, adminUsers: adminUsers}
+	h := &handlers{engine: engine, validator: validator, adminRole: adminRole, adminUsers: adminUsers, metricsKey: metricsToken}
 	auth := h.requireAuth
 
+	// Health check for load balancers — no auth, not logged.
+	mux.HandleFunc("GET /healthz", h.handleHealthz)
+
+	// Prometheus scrape target — bearer-token auth, not logged.
+	mux.HandleFunc("GET /metrics", h.handleMetrics)
+
 	// Auth callback — receives the code from webauth, exchanges it for a JWT cookie.
 	mux.HandleFunc("GET /auth/callback", h.handleCallback)
 
@@ -44,6 +50,19 @@ func newRouter(engine *herald.Engine, validator *oidclient.Client, adminRole str
 	mux.Handle("GET /settings/prompts", auth(http.HandlerFunc(h.handleSettingsPrompts)))
 	mux.Handle("GET /filters", auth(http.HandlerFunc(h.handleFilters)))
 	mux.Handle("GET /stats", auth(http.HandlerFunc(h.handleStats)))
+	mux.Handle("GET /status", auth(http.HandlerFunc(h.handleStatus)))
+	mux.Handle("GET /new", auth(http.HandlerFunc(h.handleNewArticles)))
+	mux.Handle("GET /queue", auth(http.HandlerFunc(h.handleQueue)))
+	mux.Handle("GET /blocked", auth(http.HandlerFunc(h.handleBlocked)))
+	mux.Handle("GET /history", auth(http.HandlerFunc(h.handleHistory)))
+
+	// JSON API for custom frontends; same auth as the HTML UI.
+	mux.Handle("GET /api/v1/articles", auth(http.HandlerFunc(h.handleAPIArticles)))
+	mux.Handle("GET /api/v1/articles/{articleID}", auth(http.HandlerFunc(h.handleAPIArticle)))
+	mux.Handle("POST /api/v1/articles/{articleID}/read", auth(http.HandlerFunc(h.handleAPIArticleRead)))
+	mux.Handle("POST /api/v1/articles/{articleID}/star", auth(http.HandlerFunc(h.handleAPIArticleStar)))
+	mux.Handle("GET /api/v1/feeds", auth(http.HandlerFunc(h.handleAPIFeeds)))
+	mux.Handle("GET /api/v1/groups", auth(http.HandlerFunc(h.handleAPIGroups)))
 
 	// htmx fragment routes.
 	mux.Handle("GET /search", auth(http.HandlerFunc(h.handleSearch)))
@@ -51,14 +70,26 @@ func newRouter(engine *herald.Engine, validator *oidclient.Client, adminRole str
 	mux.Handle("GET /articles/{articleID}", auth(http.HandlerFunc(h.handleArticleView)))
 	mux.Handle("GET /sidebar", auth(http.HandlerFunc(h.handleSidebar)))
 	mux.Handle("POST /articles/mark-all-read", auth(http.HandlerFunc(h.handleMarkAllRead)))
+	mux.Handle("POST /articles/star-batch", auth(http.HandlerFunc(h.handleStarBatch)))
 	mux.Handle("POST /articles/{articleID}/star", auth(http.HandlerFunc(h.handleStarToggle)))
+	mux.Handle("POST /articles/{articleID}/read-toggle", auth(http.HandlerFunc(h.handleReadToggle)))
+	mux.Handle("POST /articles/{articleID}/note", auth(http.HandlerFunc(h.handleArticleNote)))
+	mux.Handle("GET /articles/{articleID}/reader", auth(http.HandlerFunc(h.handleArticleReader)))
+	mux.Handle("GET /a/{slug}", auth(http.HandlerFunc(h.handleArticlePermalink)))
+	mux.Handle("POST /articles/{articleID}/unblock", auth(http.HandlerFunc(h.handleArticleUnblock)))
+	mux.Handle("POST /articles/{articleID}/summary", auth(http.HandlerFunc(h.handleArticleResummarize)))
+	mux.Handle("POST /articles/{articleID}/group", auth(http.HandlerFunc(h.handleArticleMoveGroup)))
 	mux.Handle("GET /images/{imageID}", auth(http.HandlerFunc(h.handleArticleImage)))
+	mux.Handle("GET /img", auth(http.HandlerFunc(h.handleImageProxy)))
 	mux.Handle("GET /feeds/{feedID}/favicon", auth(http.HandlerFunc(h.handleFeedFavicon)))
 	mux.Handle("GET /feeds/export.opml", auth(http.HandlerFunc(h.handleOPMLExport)))
 	mux.Handle("POST /feeds/discover", auth(http.HandlerFunc(h.handleFeedDiscover)))
 	mux.Handle("POST /feeds", auth(http.HandlerFunc(h.handleFeedSubscribe)))
 	mux.Handle("POST /feeds/import", auth(http.HandlerFunc(h.handleOPMLImport)))
 	mux.Handle("DELETE /feeds/{feedID}", auth(http.HandlerFunc(h.handleFeedUnsubscribe)))
+	mux.Handle("POST /feeds/{feedID}/resubscribe", auth(http.HandlerFunc(h.handleFeedResubscribe)))
+	mux.Handle("POST /feeds/{feedID}/mute", auth(http.HandlerFunc(h.handleFeedMute)))
+	mux.Handle("POST /feeds/{feedID}/headers", auth(http.HandlerFunc(h.handleFeedHeaders)))
 	mux.Handle("PATCH /feeds/{feedID}", auth(http.HandlerFunc(h.handleFeedRename)))
 	mux.Handle("GET /feeds/{feedID}/edit-title", auth(http.HandlerFunc(h.handleFeedEditTitle)))
 	mux.Handle("GET /feeds/{feedID}/title", auth(http.HandlerFunc(h.handleFeedTitleDisplay)))
@@ -68,15 +99,21 @@ func newRouter(engine *herald.Engine, validator *oidclient.Client, adminRole str
 	mux.Handle("DELETE /settings/fever", auth(http.HandlerFunc(h.handleFeverCredentialDelete)))
 	mux.Handle("POST /filters", auth(http.HandlerFunc(h.handleFilterAdd)))
 	mux.Handle("POST /filters/threshold", auth(http.HandlerFunc(h.handleFilterThreshold)))
+	mux.Handle("POST /filters/import", auth(http.HandlerFunc(h.handleFilterImport)))
 	mux.Handle("DELETE /filters/{ruleID}", auth(http.HandlerFunc(h.handleFilterDelete)))
 	mux.Handle("GET /feeds/{feedID}/metadata", auth(http.HandlerFunc(h.handleFeedMetadata)))
 	mux.Handle("GET /feeds/metadata", auth(http.HandlerFunc(h.handleFeedMetadataByQuery)))
 	mux.Handle("GET /filters/values", auth(http.HandlerFunc(h.handleFilterValues)))
+	mux.Handle("GET /filters/explain", auth(http.HandlerFunc(h.handleFilterExplain)))
+	mux.Handle("GET /filters/export.json", auth(http.HandlerFunc(h.handleFilterExport)))
 
 	// Group virtual feed actions.
 	mux.Handle("POST /groups/{groupID}/mute", auth(http.HandlerFunc(h.handleGroupMute)))
 	mux.Handle("DELETE /groups/{groupID}", auth(http.HandlerFunc(h.handleGroupDisband)))
+	mux.Handle("PATCH /groups/{groupID}", auth(http.HandlerFunc(h.handleGroupRename)))
+	mux.Handle("POST /groups/{groupID}/merge", auth(http.HandlerFunc(h.handleGroupMerge)))
 	mux.Handle("POST /groups/{groupID}/mark-read", auth(http.HandlerFunc(h.handleGroupMarkRead)))
+	mux.Handle("POST /groups/{groupID}/summary", auth(http.HandlerFunc(h.handleGroupResummarize)))
 
 	// Newsletter routes.
 	mux.Handle("GET /newsletters", auth(http.HandlerFunc(h.handleNewslettersManage)))
@@ -156,21 +156,29 @@
         var feedLink = e.target.closest('a[data-feed-id]');
         var groupLink = e.target.closest('a[data-group-id]');
         var unsubBtn = document.getElementById('unsubscribe-feed-btn');
+        var muteFeedBtn = document.getElementById('mute-feed-btn');
         var muteBtn = document.getElementById('mute-group-btn');
         var ungroupBtn = document.getElementById('ungroup-btn');
 
         if (feedLink) {
-            // Feed selected — show unsubscribe, hide group buttons
+            // Feed selected — show unsubscribe and mute, hide group buttons
             if (unsubBtn) {
                 unsubBtn.dataset.feedId = feedLink.dataset.feedId;
                 unsubBtn.title = 'Unsubscribe from ' + feedLink.dataset.feedTitle;
                 unsubBtn.style.display = '';
             }
+            if (muteFeedBtn) {
+                muteFeedBtn.dataset.feedId = feedLink.dataset.feedId;
+                muteFeedBtn.dataset.muted = feedLink.dataset.feedMuted ? 'true' : 'false';
+                muteFeedBtn.textContent = feedLink.dataset.feedMuted ? 'Unmute Feed' : 'Mute Feed';
+                muteFeedBtn.style.display = '';
+            }
             if (muteBtn) muteBtn.style.display = 'none';
             if (ungroupBtn) ungroupBtn.style.display = 'none';
         } else if (groupLink) {
             // Group selected — show mute and ungroup, hide unsubscribe
             if (unsubBtn) { unsubBtn.style.display = 'none'; unsubBtn.dataset.feedId = ''; }
+            if (muteFeedBtn) { muteFeedBtn.style.display = 'none'; muteFeedBtn.dataset.feedId = ''; }
             if (muteBtn) {
                 muteBtn.dataset.groupId = groupLink.dataset.groupId;
                 muteBtn.title = 'Mute ' + groupLink.dataset.groupTitle;
@@ -184,6 +192,7 @@
         } else if (e.target.closest('#sidebar a:not([data-feed-id]):not([data-group-id])')) {
             // "All Articles" or "Starred" — hide all action buttons
             if (unsubBtn) { unsubBtn.style.display = 'none'; unsubBtn.dataset.feedId = ''; }
+            if (muteFeedBtn) { muteFeedBtn.style.display = 'none'; muteFeedBtn.dataset.feedId = ''; }
             if (muteBtn) { muteBtn.style.display = 'none'; }
             if (ungroupBtn) { ungroupBtn.style.display = 'none'; }
         }
@@ -203,6 +212,26 @@
             });
     });
 
+    // Mute feed handler: toggles, keeping the feed selected.
+    document.addEventListener('click', function(e) {
+        var btn = e.target.closest('#mute-feed-btn');
+        if (!btn || !btn.dataset.feedId) return;
+        var mute = btn.dataset.muted !== 'true';
+        fetch('/feeds/' + btn.dataset.feedId + '/mute', {
+            method: 'POST',
+            headers: {'Content-Type': 'application/x-www-form-urlencoded'},
+            body: 'muted=' + mute
+        }).then(function(res) {
+            if (res.ok || res.status === 204) {
+                btn.dataset.muted = mute ? 'true' : 'false';
+                btn.textContent = mute ? 'Unmute Feed' : 'Mute Feed';
+                htmx.trigger(document.body, 'feeds-changed');
+            } else {
+                notifyIfReadOnly(res);
+            }
+        });
+    });
+
     // Mute group handler
     document.addEventListener('click', function(e) {
         var btn = e.target.closest('#mute-group-btn');
@@ -228,6 +257,67 @@
             });
     });
 
+    // Read-only mode: writes are refused with a 403 carrying X-Herald-Read-Only.
+    function notifyIfReadOnly(xhrOrRes) {
+        var flag = xhrOrRes.headers ? xhrOrRes.headers.get('X-Herald-Read-Only')
+                                    : xhrOrRes.getResponseHeader('X-Herald-Read-Only');
+        if (flag) alert('Herald is running in read-only mode; changes are not saved.');
+    }
+    document.addEventListener('htmx:responseError', function(e) {
+        notifyIfReadOnly(e.detail.xhr);
+    });
+
+    // Multi-select: star or unstar the checked articles in one request.
+    function selectedArticleBoxes() {
+        return Array.from(document.querySelectorAll('#article-list .article-select:checked'));
+    }
+    function updateStarSelectedBar() {
+        var bar = document.querySelector('.star-selected-bar');
+        if (bar) bar.hidden = selectedArticleBoxes().length === 0;
+    }
+    document.addEventListener('change', function(e) {
+        if (e.target.classList && e.target.classList.contains('article-select')) updateStarSelectedBar();
+    });
+    document.addEventListener('htmx:afterSwap', function(e) {
+        if (e.detail.target && e.detail.target.id === 'article-list') updateStarSelectedBar();
+    });
+    document.addEventListener('click', function(e) {
+        var btn = e.target.closest('.star-selected-btn');
+        if (!btn) return;
+
+        var boxes = selectedArticleBoxes();
+        if (!boxes.length) return;
+        var starred = btn.dataset.starred === 'true';
+        var ids = boxes.map(function(box) { return box.value; }).join(',');
+
+        fetch('/articles/star-batch', {
+            method: 'POST',
+            headers: {'Content-Type': 'application/x-www-form-urlencoded'},
+            body: 'ids=' + encodeURIComponent(ids) + '&starred=' + starred
+        }).then(function(res) {
+            if (res.ok || res.status === 204) {
+                boxes.forEach(function(box) {
+                    var h4 = box.closest('h4');
+                    var star = h4 && h4.querySelector('.starred');
+                    if (starred && h4 && !star) {
+                        var span = document.createElement('span');
+                        span.className = 'starred';
+                        span.setAttribute('aria-label', 'starred');
+                        span.innerHTML = '&#9733;';
+                        box.after(span, ' ');
+                    } else if (!starred && star) {
+                        star.remove();
+                    }
+                    box.checked = false;
+                });
+                updateStarSelectedBar();
+                htmx.trigger(document.body, 'feeds-changed');
+            } else {
+                notifyIfReadOnly(res);
+            }
+        });
+    });
+
     // Mark all as read
     document.addEventListener('click', function(e) {
         var btn = e.target.closest('.mark-all-read-btn');
@@ -250,6 +340,8 @@
                     el.classList.add('read');
                 });
                 htmx.trigger(document.body, 'feeds-changed');
+            } else {
+                notifyIfReadOnly(res);
             }
         });
     });
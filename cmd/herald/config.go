@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	herald "github.com/matthewjhunter/herald"
+	"github.com/spf13/cobra"
+)
+
+func configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Export or import a user's personalization as JSON",
+		Long: `Moves a user's subscriptions, preferences, custom prompts and filter rules
+between databases or users as one JSON document. Articles and read state are
+not included; use OPML export for a plain feed list.
+
+Examples:
+  herald config export --user 2 > alice.json
+  herald config import --user 5 alice.json`,
+	}
+	cmd.AddCommand(configExportCmd())
+	cmd.AddCommand(configImportCmd())
+	return cmd
+}
+
+// openConfigEngine opens the configured database for the config
+// subcommands, which need no AI.
+func openConfigEngine() (*herald.Engine, error) {
+	engine, err := herald.NewEngine(herald.EngineConfig{
+		DBPath:            cfg.Database.Path,
+		BusyTimeout:       cfg.Database.BusyTimeout,
+		JournalMode:       cfg.Database.JournalMode,
+		CreateDefaultUser: cfg.Database.CreateDefaultUser,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return engine, nil
+}
+
+func configExportCmd() *cobra.Command {
+	var userID int64
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Print the user's personalization as JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("user") {
+				userID = cfg.DefaultUserID
+			}
+
+			engine, err := openConfigEngine()
+			if err != nil {
+				return err
+			}
+			defer engine.Close()
+
+			export, err := engine.ExportUserConfig(userID)
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(export, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return nil
+		},
+	}
+	cmd.Flags().Int64VarP(&userID, "user", "u", 0, "user ID")
+	return cmd
+}
+
+func configImportCmd() *cobra.Command {
+	var userID int64
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Apply an exported personalization to a user",
+		Long: `Applies a document written by 'herald config export' to the user. Nothing
+is written unless every feed URL, preference, prompt and filter rule in it is
+valid, and the import is applied in one transaction: if any write fails,
+nothing is imported. New feeds are subscribed without being fetched; the next poll picks them up.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("user") {
+				userID = cfg.DefaultUserID
+			}
+
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var export herald.UserConfigExport
+			if err := json.Unmarshal(data, &export); err != nil {
+				return fmt.Errorf("%s is not a config export: %w", args[0], err)
+			}
+
+			engine, err := openConfigEngine()
+			if err != nil {
+				return err
+			}
+			defer engine.Close()
+
+			r, err := engine.ImportUserConfig(userID, export)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported into user %d: %d feeds added (%d already subscribed), %d preferences, %d prompts, %d filter rules added (%d duplicates skipped)\n",
+				userID, r.FeedsAdded, r.FeedsSkipped, r.Preferences, r.Prompts, r.RulesAdded, r.RulesSkipped)
+			return nil
+		},
+	}
+	cmd.Flags().Int64VarP(&userID, "user", "u", 0, "user ID")
+	return cmd
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/matthewjhunter/herald/internal/storage"
+)
+
+func TestConfigCmd(t *testing.T) {
+	cfg = storage.DefaultConfig()
+	cfg.Database.Path = filepath.Join(t.TempDir(), "herald.db")
+	store, err := storage.NewSQLiteStore(cfg.Database.Path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	alice, _ := store.CreateUser("alice")
+	bob, err := store.CreateUser("bob")
+	if err == nil {
+		err = store.SetUserPreference(alice, "interest_threshold", "6.5")
+	}
+	store.Close()
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := configCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("config %v: %v\n%s", args, err, out.String())
+		}
+		return out.String()
+	}
+
+	exported := run("export", "--user="+strconv.FormatInt(alice, 10))
+	path := filepath.Join(t.TempDir(), "alice.json")
+	if err := os.WriteFile(path, []byte(exported), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if out := run("import", "--user="+strconv.FormatInt(bob, 10), path); !strings.Contains(out, "1 preferences") {
+		t.Errorf("import output = %q", out)
+	}
+
+	store, err = storage.NewSQLiteStore(cfg.Database.Path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer store.Close()
+	if v, _ := store.GetUserPreference(bob, "interest_threshold"); v != "6.5" {
+		t.Errorf("bob's interest_threshold = %q, want 6.5", v)
+	}
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -19,38 +19,42 @@ func daemonCmd() *cobra.Command {
 		Short: "Run fetch+process in a loop with configurable interval",
 		Long: `Continuously fetch feeds and process articles with AI on a timer.
 Designed for running inside a Docker container or as a background service.
-Handles SIGINT/SIGTERM for graceful shutdown (finishes the current cycle).`,
+Handles SIGINT/SIGTERM for graceful shutdown (finishes the current cycle).
+SIGHUP re-reads the config file before the next cycle; database settings
+need a restart.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
 			sig := make(chan os.Signal, 1)
 			signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+			hup := make(chan os.Signal, 1)
+			signal.Notify(hup, syscall.SIGHUP)
 
 			// Cancel the context immediately on signal so in-flight
 			// HTTP and Ollama requests abort rather than waiting for
 			// the current cycle to complete.
 			go func() {
 				<-sig
-				log.Println("herald daemon: received shutdown signal, cancelling current cycle")
+				slog.Info("received shutdown signal, cancelling current cycle", "event", "daemon_signal")
 				cancel()
 			}()
 
-			log.Printf("herald daemon: starting with interval %s", interval)
+			slog.Info("daemon starting", "event", "daemon_start", "interval", interval)
 
 			cycle := 1
 			for {
 				start := time.Now()
-				log.Printf("herald daemon: cycle %d starting", cycle)
+				slog.Info("cycle starting", "event", "cycle_start", "cycle", cycle)
 
 				if err := doFetch(ctx); err != nil {
 					if ctx.Err() != nil {
-						log.Println("herald daemon: cycle cancelled, exiting")
+						slog.Info("cycle cancelled, exiting", "event", "daemon_stop", "cycle", cycle)
 						return nil
 					}
-					log.Printf("herald daemon: cycle %d error: %v", cycle, err)
+					slog.Error("cycle failed", "event", "cycle_error", "cycle", cycle, "err", err)
 				} else {
-					log.Printf("herald daemon: cycle %d completed in %s", cycle, time.Since(start).Round(time.Millisecond))
+					slog.Info("cycle finished", "event", "cycle_finish", "cycle", cycle, "duration_ms", time.Since(start).Milliseconds())
 				}
 
 				// Process due newsletters (hourly/daily).
@@ -58,18 +62,30 @@ Handles SIGINT/SIGTERM for graceful shutdown (finishes the current cycle).`,
 					if ctx.Err() != nil {
 						return nil
 					}
-					log.Printf("herald daemon: newsletter processing error: %v", err)
+					slog.Error("newsletter processing failed", "err", err)
 				}
 
 				cycle++
 
-				// Wait for the next tick or a shutdown signal.
+				// Wait for the next tick or a shutdown signal. A SIGHUP
+				// received mid-cycle stays buffered and is handled here, so
+				// cfg never changes under a running cycle.
 				timer := time.NewTimer(interval)
-				select {
-				case <-ctx.Done():
-					timer.Stop()
-					return nil
-				case <-timer.C:
+			wait:
+				for {
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return nil
+					case <-hup:
+						if err := reloadConfig(); err != nil {
+							slog.Error("config reload failed", "event", "config_reload_error", "path", configPath, "err", err)
+						} else {
+							slog.Info("config reloaded", "event", "config_reload", "path", configPath)
+						}
+					case <-timer.C:
+						break wait
+					}
 				}
 			}
 		},
@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	herald "github.com/matthewjhunter/herald"
 	"github.com/matthewjhunter/herald/internal/ai"
 	"github.com/matthewjhunter/herald/internal/feeds"
+	"github.com/matthewjhunter/herald/internal/logging"
 	"github.com/matthewjhunter/herald/internal/output"
 	"github.com/matthewjhunter/herald/internal/storage"
 	"github.com/spf13/cobra"
@@ -23,6 +27,9 @@ var (
 	configPath   string
 	cfg          *storage.Config
 	outputFormat string
+	quiet        bool
+	logFormat    string
+	logLevel     string
 )
 
 // processArticlesForUser runs the AI pipeline (summarize, security check,
@@ -38,11 +45,18 @@ var (
 // Within each article, summarization and security check run in parallel
 // since they are independent; curation and group matching run after.
 // Articles are processed in batches of 100 until the queue is empty.
-// Group summary updates are deferred until all batches complete.
+// Group summary updates are deferred until all batches complete, so each
+// changed group is regenerated at most once per run, subject to
+// grouping.summary_min_change as in the engine pipeline.
 func processArticlesForUser(ctx context.Context, store storage.Store, processor *ai.AIProcessor, formatter *output.Formatter, appCfg *storage.Config, userID int64) (int, error) {
 	embedder := embedding.NewOpenAIEmbedder(appCfg.Ollama.BaseURL, appCfg.Ollama.APIKey, appCfg.Ollama.EmbeddingModel)
 	groupMatcher := ai.NewGroupMatcher(embedder, store, appCfg.Ollama.EmbeddingModel, appCfg.Grouping.SimilarityThreshold)
 
+	keywords, avoidKeywords, err := userKeywords(store, appCfg, userID)
+	if err != nil {
+		return 0, err
+	}
+
 	maxParallel := appCfg.Ollama.MaxParallel
 	if maxParallel < 1 {
 		maxParallel = 1
@@ -58,7 +72,7 @@ func processArticlesForUser(ctx context.Context, store storage.Store, processor
 	var wg sync.WaitGroup
 
 	for ctx.Err() == nil { //nolint:staticcheck // QF1006: batch-fetch-then-check pattern is intentional
-		unscoredArticles, err := store.GetUnscoredArticlesForUser(userID, 100)
+		unscoredArticles, err := store.GetUnscoredArticlesForUser(userID, 100, time.Duration(appCfg.Ollama.ProcessDelaySeconds)*time.Second)
 		if err != nil {
 			return processed, fmt.Errorf("failed to get unscored articles for user %d: %w", userID, err)
 		}
@@ -77,10 +91,13 @@ func processArticlesForUser(ctx context.Context, store storage.Store, processor
 			go func(article storage.Article) {
 				defer func() { <-sem; wg.Done() }()
 
-				content := article.Content
-				if content == "" {
-					content = article.Summary
+				source, err := store.GetFeedContentSource(article.FeedID)
+				if err != nil {
+					// Leave the article unscored; the next run retries it.
+					formatter.Warning("skipping article %d: %v", article.ID, err)
+					return
 				}
+				content := storage.ArticleBody(source, article.Content, article.Summary)
 				if content == "" {
 					formatter.Warning("skipping article %d %q: no content", article.ID, article.Title)
 					// Mark as scored so it doesn't block the queue forever.
@@ -93,6 +110,14 @@ func processArticlesForUser(ctx context.Context, store storage.Store, processor
 				if article.LinkedContent != "" {
 					content = content + "\n\n" + article.LinkedContent
 				}
+				// The security check screens the text as the feed sent it;
+				// strip patterns only trim what the later models read.
+				raw := content
+				patterns, err := store.GetFeedStripPatterns(article.FeedID)
+				if err != nil {
+					formatter.Warning("failed to get strip patterns for feed %d: %v", article.FeedID, err)
+				}
+				content = storage.StripBoilerplate(patterns, processor.ScreenedText(raw))
 
 				// Skip entire AI pipeline for articles too short to process meaningfully.
 				// Mark as scored so they don't block the queue forever.
@@ -139,7 +164,7 @@ func processArticlesForUser(ctx context.Context, store storage.Store, processor
 				})
 
 				g.Go(func() error {
-					secResult, secErr = processor.SecurityCheck(gctx, userID, article.Title, content)
+					secResult, secErr = processor.SecurityCheck(gctx, userID, article.Title, raw)
 					return nil
 				})
 
@@ -159,7 +184,8 @@ func processArticlesForUser(ctx context.Context, store storage.Store, processor
 				}
 
 				// 3. Interest scoring
-				curResult, err := processor.CurateArticle(ctx, userID, article.Title, content, appCfg.Preferences.Keywords)
+				feedTitle, _ := store.GetUserFeedTitle(userID, article.FeedID)
+				curResult, err := processor.CurateArticle(ctx, userID, article.Title, content, feedTitle, keywords, avoidKeywords)
 				if err != nil {
 					formatter.Warning("curation failed for article %d: %v", article.ID, err)
 					return
@@ -168,6 +194,8 @@ func processArticlesForUser(ctx context.Context, store storage.Store, processor
 				secScore := secResult.Score
 				interestScore := curResult.InterestScore
 				store.UpdateReadState(userID, article.ID, false, &interestScore, &secScore, &secResult.Reasoning) //nolint:errcheck
+				store.SetInterestReason(userID, article.ID, curResult.Reasoning)                                  //nolint:errcheck
+				store.StoreAITags(userID, article.ID, curResult.Tags)                                             //nolint:errcheck
 				formatter.OutputProcessingStatus(article.ID, article.Title, interestScore, secScore, true)
 
 				// 4. Vector-based group matching
@@ -203,7 +231,7 @@ func processArticlesForUser(ctx context.Context, store storage.Store, processor
 
 	// 5. Update group summaries for changed groups — sequential, after all batches.
 	for groupID := range updatedGroups {
-		if err := updateGroupSummary(ctx, store, processor, groupID, userID); err != nil {
+		if err := updateGroupSummary(ctx, store, processor, groupID, userID, appCfg.Grouping.SummaryMinChange); err != nil {
 			formatter.Warning("failed to update group summary for group %d: %v", groupID, err)
 		}
 	}
@@ -211,8 +239,33 @@ func processArticlesForUser(ctx context.Context, store storage.Store, processor
 	return processed, nil
 }
 
-// updateGroupSummary regenerates the summary for a group
-func updateGroupSummary(ctx context.Context, store storage.Store, processor *ai.AIProcessor, groupID, userID int64) error {
+// userKeywords returns the curation keywords and avoid keywords for userID:
+// the user's own preferences where set, else the config file's.
+func userKeywords(store storage.Store, appCfg *storage.Config, userID int64) (keywords, avoid storage.Keywords, err error) {
+	keywords, avoid = appCfg.Preferences.Keywords, appCfg.Preferences.AvoidKeywords
+	prefs, err := store.GetAllUserPreferences(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get preferences for user %d: %w", userID, err)
+	}
+	// Decode into fresh slices; json would reuse the config's arrays.
+	if v, ok := prefs["keywords"]; ok {
+		keywords = nil
+		if err := json.Unmarshal([]byte(v), &keywords); err != nil {
+			return nil, nil, fmt.Errorf("user %d keywords: %w", userID, err)
+		}
+	}
+	if v, ok := prefs["avoid_keywords"]; ok {
+		avoid = nil
+		if err := json.Unmarshal([]byte(v), &avoid); err != nil {
+			return nil, nil, fmt.Errorf("user %d avoid_keywords: %w", userID, err)
+		}
+	}
+	return keywords, avoid, nil
+}
+
+// updateGroupSummary regenerates the summary for a group, unless its
+// membership has moved by no more than minChange since the last one.
+func updateGroupSummary(ctx context.Context, store storage.Store, processor *ai.AIProcessor, groupID, userID int64, minChange int) error {
 	// Get all articles in the group
 	articles, err := store.GetGroupArticles(groupID)
 	if err != nil {
@@ -223,6 +276,12 @@ func updateGroupSummary(ctx context.Context, store storage.Store, processor *ai.
 		return nil
 	}
 
+	if minChange > 0 {
+		if prev, err := store.GetGroupSummary(groupID); err == nil && !prev.Stale(len(articles), minChange) {
+			return nil
+		}
+	}
+
 	// Get first article group to get topic
 	userGroups, err := store.GetUserGroups(userID)
 	if err != nil {
@@ -237,6 +296,15 @@ func updateGroupSummary(ctx context.Context, store storage.Store, processor *ai.
 		}
 	}
 
+	articleIDs := make([]int64, len(articles))
+	for i, article := range articles {
+		articleIDs[i] = article.ID
+	}
+	scores, err := store.GetInterestScores(userID, articleIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get interest scores: %w", err)
+	}
+
 	// Build input for group summary
 	var summaryInputs []ai.GroupSummaryInput
 	var maxScore float64
@@ -248,10 +316,10 @@ func updateGroupSummary(ctx context.Context, store storage.Store, processor *ai.
 			continue
 		}
 
-		// Get interest score
-		// For now, query from read_state
-		// TODO: could add a method to get this more efficiently
-		score := 5.0 // default
+		score, ok := scores[article.ID]
+		if !ok {
+			score = 5.0 // unscored: neutral weight
+		}
 
 		summaryInputs = append(summaryInputs, ai.GroupSummaryInput{
 			Title:     article.Title,
@@ -297,12 +365,28 @@ func main() {
 		Use:   "herald",
 		Short: "Your AI-powered news herald - intelligent RSS/Atom feed reader with AI curation",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			return loadConfig()
+			if _, err := logging.Setup(os.Stderr, logFormat, logLevel); err != nil {
+				return err
+			}
+			if err := loadConfig(); err != nil {
+				return err
+			}
+			// Config supplies defaults; explicit flags still win.
+			if !cmd.Flags().Changed("format") && cfg.Output.DefaultFormat != "" {
+				outputFormat = cfg.Output.DefaultFormat
+			}
+			if !cmd.Flags().Changed("quiet") {
+				quiet = cfg.Output.Quiet
+			}
+			return nil
 		},
 	}
 
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "config file path (default: ./config/config.yaml)")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "format", "f", "json", "output format: json, text, human (default: json)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "format", "f", "json", "output format: json, text, human (default: output.default_format in config, else json)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress warnings and errors on stderr (default: output.quiet in config)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log output format: text or json")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "minimum log level: debug, info, warn or error")
 
 	rootCmd.AddCommand(createUserCmd())
 	rootCmd.AddCommand(importCmd())
@@ -311,14 +395,21 @@ func main() {
 	rootCmd.AddCommand(fetchCmd())
 	rootCmd.AddCommand(daemonCmd())
 	rootCmd.AddCommand(listCmd())
+	rootCmd.AddCommand(feedsCmd())
 	rootCmd.AddCommand(readCmd())
+	rootCmd.AddCommand(prefsCmd())
+	rootCmd.AddCommand(configCmd())
 	rootCmd.AddCommand(initConfigCmd())
 	rootCmd.AddCommand(migrateDBCmd())
 	rootCmd.AddCommand(resetScoresCmd())
 	rootCmd.AddCommand(backfillEmbeddingsCmd())
+	rootCmd.AddCommand(resummarizeCmd())
+	rootCmd.AddCommand(rescoreCmd())
+	rootCmd.AddCommand(backupCmd())
+	rootCmd.AddCommand(maintenanceCmd())
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		newFormatter().Error("Error: %v", err)
 		os.Exit(1)
 	}
 }
@@ -348,13 +439,40 @@ func loadConfig() error {
 	return nil
 }
 
+// reloadConfig re-reads the config file for a running daemon. A config that
+// fails to load leaves the current one in place. Database settings are kept
+// from the running config, with a warning, since they can't change without a
+// restart.
+func reloadConfig() error {
+	prev := cfg
+	if err := loadConfig(); err != nil {
+		cfg = prev
+		return err
+	}
+	if cfg.Database != prev.Database {
+		slog.Warn("config reload: database settings can't change without a restart; ignoring",
+			"current", prev.Database.Path, "requested", cfg.Database.Path)
+		cfg.Database = prev.Database
+	}
+	return nil
+}
+
+// newFormatter returns a formatter for the selected output format, silenced
+// in quiet mode.
+func newFormatter() *output.Formatter {
+	return output.NewFormatterWithOptions(output.Options{
+		Format: output.Format(outputFormat),
+		Quiet:  quiet,
+	})
+}
+
 func createUserCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "create-user <name>",
 		Short: "Create a new user",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			store, err := storage.NewStore(cfg.Database.Path)
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
 			if err != nil {
 				return fmt.Errorf("failed to open database: %w", err)
 			}
@@ -373,32 +491,53 @@ func createUserCmd() *cobra.Command {
 
 func importCmd() *cobra.Command {
 	var userID int64
+	var opmlURL string
+	var validate bool
 	cmd := &cobra.Command{
-		Use:   "import <opml-file>",
-		Short: "Import feeds from an OPML file and subscribe user",
-		Args:  cobra.ExactArgs(1),
+		Use:   "import [opml-file]",
+		Short: "Import feeds from an OPML file or URL and subscribe user",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opmlURL != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if !cmd.Flags().Changed("user") {
 				userID = cfg.DefaultUserID
 			}
-			opmlPath := args[0]
 
-			store, err := storage.NewStore(cfg.Database.Path)
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
 			if err != nil {
 				return fmt.Errorf("failed to open database: %w", err)
 			}
 			defer store.Close()
 
 			fetcher := feeds.NewFetcher(store)
-			if err := fetcher.ImportOPML(opmlPath, userID); err != nil {
+			opts := feeds.OPMLImportOptions{Validate: validate}
+			source := opmlURL
+			var result *feeds.OPMLImportResult
+			if opmlURL != "" {
+				result, err = fetcher.ImportOPMLURL(context.Background(), opmlURL, userID, opts)
+			} else {
+				source = args[0]
+				result, err = fetcher.ImportOPML(source, userID, opts)
+			}
+			if err != nil {
 				return fmt.Errorf("failed to import OPML: %w", err)
 			}
 
-			fmt.Printf("Successfully imported and subscribed user %d to feeds from %s\n", userID, opmlPath)
+			fmt.Printf("Imported %s for user %d: %d added, %d already subscribed, %d failed\n",
+				source, userID, result.Added, result.Skipped, len(result.Failed))
+			for _, u := range result.Failed {
+				fmt.Printf("  failed: %s\n", u)
+			}
 			return nil
 		},
 	}
 	cmd.Flags().Int64VarP(&userID, "user", "u", 0, "user ID to subscribe to feeds")
+	cmd.Flags().StringVar(&opmlURL, "url", "", "fetch the OPML document from this URL instead of a local file")
+	cmd.Flags().BoolVar(&validate, "validate", false, "fetch each new feed before subscribing and skip any that fail to parse")
 	return cmd
 }
 
@@ -408,9 +547,9 @@ func fetchFeedsCmd() *cobra.Command {
 		Short: "Fetch all subscribed feeds and store articles (no AI processing)",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
-			formatter := output.NewFormatter(output.Format(outputFormat))
+			formatter := newFormatter()
 
-			store, err := storage.NewStore(cfg.Database.Path)
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
 			if err != nil {
 				return fmt.Errorf("failed to open database: %w", err)
 			}
@@ -428,46 +567,9 @@ func fetchFeedsCmd() *cobra.Command {
 			}
 
 			// Fetch each feed once (efficient, no AI processing)
-			fetcher := feeds.NewFetcher(store)
-			fetchResult := &output.FetchResult{FeedsTotal: len(subscribedFeeds)}
-			for _, feed := range subscribedFeeds {
-				feedCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-				result, err := fetcher.FetchFeed(feedCtx, feed)
-				cancel()
-
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to fetch feed %s: %v\n", feed.URL, err)
-					fetchResult.FeedsErrored++
-					continue
-				}
-
-				if result.NotModified {
-					fetchResult.FeedsNotModified++
-					store.UpdateFeedLastFetched(feed.ID)
-					continue
-				}
-
-				fetchResult.FeedsDownloaded++
-
-				// Store articles (global, fetched once)
-				stored, err := fetcher.StoreArticles(feed.ID, result.Feed)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: error storing articles from %s: %v\n", feed.URL, err)
-				}
-				fetchResult.NewArticles += stored
-
-				// Persist cache headers for next conditional request
-				if result.ETag != "" || result.LastModified != "" {
-					store.UpdateFeedCacheHeaders(feed.ID, result.ETag, result.LastModified)
-				}
-
-				// Update last fetched timestamp
-				if err := store.UpdateFeedLastFetched(feed.ID); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to update last_fetched for %s: %v\n", feed.URL, err)
-				}
-			}
-
-			return formatter.OutputFetchResult(fetchResult)
+			fetcher := feeds.NewFetcherWithOptions(store, feeds.OptionsFromConfig(cfg))
+			stats := fetcher.FetchFeeds(ctx, subscribedFeeds)
+			return formatter.OutputFetchResult(fetchResultFromStats(stats))
 		},
 	}
 }
@@ -482,9 +584,9 @@ func processCmd() *cobra.Command {
 				userID = cfg.DefaultUserID
 			}
 			ctx := context.Background()
-			formatter := output.NewFormatter(output.Format(outputFormat))
+			formatter := newFormatter()
 
-			store, err := storage.NewStore(cfg.Database.Path)
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
 			if err != nil {
 				return fmt.Errorf("failed to open database: %w", err)
 			}
@@ -497,6 +599,7 @@ func processCmd() *cobra.Command {
 				formatter.Warning("skipping AI processing (Ollama may not be running)")
 				return nil
 			}
+			warnMissingModels(ctx, processor, formatter)
 
 			processed, err := processArticlesForUser(ctx, store, processor, formatter, cfg, userID)
 			if err != nil {
@@ -508,7 +611,7 @@ func processCmd() *cobra.Command {
 			}
 
 			// Get and output high-interest articles
-			highInterestArticles, scores, err := store.GetArticlesByInterestScore(userID, cfg.Thresholds.InterestScore, 10, 0, nil)
+			highInterestArticles, scores, _, err := store.GetArticlesByInterestScore(userID, notifyMinScore(userID), 10, 0, nil)
 			if err != nil {
 				return fmt.Errorf("failed to get high-interest articles: %w", err)
 			}
@@ -545,14 +648,17 @@ func processCmd() *cobra.Command {
 // and the `daemon` command call this. It uses the package-level cfg and
 // outputFormat variables.
 func doFetch(ctx context.Context) error {
-	formatter := output.NewFormatter(output.Format(outputFormat))
+	formatter := newFormatter()
 
-	store, err := storage.NewStore(cfg.Database.Path)
+	store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 	defer store.Close()
 
+	// Remove unsubscribed feeds whose undo window has passed before fetching.
+	deleteExpiredFeeds(store, formatter)
+
 	// Get all feeds that ANY user is subscribed to
 	subscribedFeeds, err := store.GetAllSubscribedFeeds()
 	if err != nil {
@@ -565,45 +671,8 @@ func doFetch(ctx context.Context) error {
 	}
 
 	// Fetch each feed once (efficient)
-	fetcher := feeds.NewFetcher(store)
-	fetchResult := &output.FetchResult{FeedsTotal: len(subscribedFeeds)}
-	for _, feed := range subscribedFeeds {
-		feedCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		result, err := fetcher.FetchFeed(feedCtx, feed)
-		cancel()
-
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to fetch feed %s: %v\n", feed.URL, err)
-			store.UpdateFeedError(feed.ID, err.Error()) //nolint:errcheck
-			fetchResult.FeedsErrored++
-			continue
-		}
-
-		if result.NotModified {
-			fetchResult.FeedsNotModified++
-			store.UpdateFeedLastFetched(feed.ID)
-			continue
-		}
-
-		fetchResult.FeedsDownloaded++
-
-		// Store articles (global, fetched once)
-		stored, err := fetcher.StoreArticles(feed.ID, result.Feed)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: error storing articles from %s: %v\n", feed.URL, err)
-		}
-		fetchResult.NewArticles += stored
-
-		// Persist cache headers for next conditional request
-		if result.ETag != "" || result.LastModified != "" {
-			store.UpdateFeedCacheHeaders(feed.ID, result.ETag, result.LastModified)
-		}
-
-		// Update last fetched timestamp
-		if err := store.UpdateFeedLastFetched(feed.ID); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to update last_fetched for %s: %v\n", feed.URL, err)
-		}
-	}
+	fetcher := feeds.NewFetcherWithOptions(store, feeds.OptionsFromConfig(cfg))
+	fetchResult := fetchResultFromStats(fetcher.FetchFeeds(ctx, subscribedFeeds))
 
 	// Fetch full text for any articles whose feed content appears truncated.
 	// This runs after all feeds are stored so the AI pipeline gets the best content.
@@ -627,7 +696,11 @@ func doFetch(ctx context.Context) error {
 		fmt.Fprintf(os.Stdout, "Cached favicons for %d feeds\n", faviconStored)
 	}
 
-	if fetchResult.NewArticles == 0 {
+	allUserIDs, err := usersToProcess(store, fetchResult.NewArticles > 0)
+	if err != nil {
+		return err
+	}
+	if len(allUserIDs) == 0 {
 		return formatter.OutputFetchResult(fetchResult)
 	}
 
@@ -638,17 +711,7 @@ func doFetch(ctx context.Context) error {
 		formatter.Warning("skipping AI processing (Ollama may not be running)")
 		return formatter.OutputFetchResult(fetchResult)
 	}
-
-	// Get all users who have subscriptions
-	allUserIDs, err := store.GetAllSubscribingUsers()
-	if err != nil {
-		return fmt.Errorf("failed to get subscribing users: %w", err)
-	}
-
-	if len(allUserIDs) == 0 {
-		formatter.Warning("no users with subscriptions")
-		return formatter.OutputFetchResult(fetchResult)
-	}
+	warnMissingModels(ctx, processor, formatter)
 
 	totalProcessed := 0
 
@@ -670,7 +733,7 @@ func doFetch(ctx context.Context) error {
 	if len(allUserIDs) > 0 {
 		displayUserID = allUserIDs[0]
 	}
-	highInterestArticles, scores, err := store.GetArticlesByInterestScore(displayUserID, cfg.Thresholds.InterestScore, 10, 0, nil)
+	highInterestArticles, scores, _, err := store.GetArticlesByInterestScore(displayUserID, notifyMinScore(displayUserID), 10, 0, nil)
 	if err != nil {
 		return fmt.Errorf("failed to get high-interest articles: %w", err)
 	}
@@ -692,17 +755,93 @@ func doFetch(ctx context.Context) error {
 	return nil
 }
 
+// notifyMinScore returns the user's notify_min_score preference as the engine
+// resolves it, falling back to the default when preferences can't be read.
+func notifyMinScore(userID int64) float64 {
+	engine, err := herald.NewEngine(herald.EngineConfig{
+		DBPath:      cfg.Database.Path,
+		BusyTimeout: cfg.Database.BusyTimeout,
+		JournalMode: cfg.Database.JournalMode,
+		ReadOnly:    true,
+	})
+	if err != nil {
+		return herald.DefaultNotifyMinScore
+	}
+	defer engine.Close()
+
+	prefs, err := engine.GetPreferences(userID)
+	if err != nil {
+		return herald.DefaultNotifyMinScore
+	}
+	return prefs.NotifyMinScore
+}
+
+// usersToProcess returns the subscribing users whose articles need AI
+// processing: all of them after new articles arrived, otherwise only those
+// with articles still unscored, such as ones held back by
+// process_delay_seconds or left over from an interrupted run.
+func usersToProcess(store storage.Store, newArticles bool) ([]int64, error) {
+	userIDs, err := store.GetAllSubscribingUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscribing users: %w", err)
+	}
+	if newArticles {
+		return userIDs, nil
+	}
+	var pending []int64
+	for _, userID := range userIDs {
+		n, err := store.GetUnscoredArticleCount(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count unscored articles for user %d: %w", userID, err)
+		}
+		if n > 0 {
+			pending = append(pending, userID)
+		}
+	}
+	return pending, nil
+}
+
+// deleteExpiredFeeds removes unsubscribed feeds whose grace period has ended,
+// as the engine's poll cycle does.
+func deleteExpiredFeeds(store storage.Store, formatter *output.Formatter) {
+	ids, err := store.GetFeedsDueForDeletion(time.Now())
+	if err != nil {
+		formatter.Warning("list feeds due for deletion: %v", err)
+		return
+	}
+	for _, feedID := range ids {
+		if _, err := store.DeleteFeedIfOrphaned(feedID); err != nil {
+			formatter.Warning("cleanup orphaned feed %d: %v", feedID, err)
+		}
+	}
+}
+
+// fetchResultFromStats converts fetcher stats to the CLI output shape.
+func fetchResultFromStats(s *feeds.FetchStats) *output.FetchResult {
+	return &output.FetchResult{
+		FeedsTotal:       s.FeedsTotal,
+		FeedsDownloaded:  s.FeedsDownloaded,
+		FeedsNotModified: s.FeedsNotModified,
+		FeedsErrored:     s.FeedsErrored,
+		NewArticles:      s.NewArticles,
+	}
+}
+
 // processNewsletters creates a temporary Engine and processes due newsletters.
 func processNewsletters(ctx context.Context) error {
 	if cfg.Ollama.BaseURL == "" {
 		return nil // AI not configured, skip newsletters
 	}
 	engine, err := herald.NewEngine(herald.EngineConfig{
-		DBPath:        cfg.Database.Path,
-		OllamaBaseURL: cfg.Ollama.BaseURL,
-		SecurityModel: cfg.Ollama.SecurityModel,
-		CurationModel: cfg.Ollama.CurationModel,
-		UserID:        cfg.DefaultUserID,
+		DBPath:            cfg.Database.Path,
+		BusyTimeout:       cfg.Database.BusyTimeout,
+		JournalMode:       cfg.Database.JournalMode,
+		CreateDefaultUser: cfg.Database.CreateDefaultUser,
+		OllamaBaseURL:     cfg.Ollama.BaseURL,
+		SecurityModel:     cfg.Ollama.SecurityModel,
+		CurationModel:     cfg.Ollama.CurationModel,
+		CurationModels:    cfg.Ollama.CurationModels,
+		UserID:            cfg.DefaultUserID,
 	})
 	if err != nil {
 		return fmt.Errorf("create engine for newsletters: %w", err)
@@ -712,6 +851,20 @@ func processNewsletters(ctx context.Context) error {
 	return engine.ProcessDueNewsletters(ctx)
 }
 
+// modelCheckTimeout bounds the startup model availability check.
+const modelCheckTimeout = 10 * time.Second
+
+// warnMissingModels checks the configured models before processing starts,
+// so a model that was never pulled shows up as one clear warning rather
+// than a failure on every article.
+func warnMissingModels(ctx context.Context, processor *ai.AIProcessor, formatter *output.Formatter) {
+	ctx, cancel := context.WithTimeout(ctx, modelCheckTimeout)
+	defer cancel()
+	if err := processor.CheckModels(ctx); err != nil {
+		formatter.Warning("%v", err)
+	}
+}
+
 func fetchCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "fetch",
@@ -730,9 +883,9 @@ func listCmd() *cobra.Command {
 		Short: "List unread articles",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
-			formatter := output.NewFormatter(output.Format(outputFormat))
+			formatter := newFormatter()
 
-			store, err := storage.NewStore(cfg.Database.Path)
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
 			if err != nil {
 				return fmt.Errorf("failed to open database: %w", err)
 			}
@@ -779,6 +932,48 @@ func listCmd() *cobra.Command {
 	return cmd
 }
 
+func feedsCmd() *cobra.Command {
+	var stats bool
+	cmd := &cobra.Command{
+		Use:   "feeds",
+		Short: "List all feeds",
+		Long: `Lists every feed in the database, across all users, with its status.
+
+--stats adds each feed's subscriber count and stored-article total and
+sorts the most-subscribed feeds first, which helps decide which feeds are
+worth fetching in a multi-user install.
+
+Example:
+  herald feeds --stats --format human`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			formatter := newFormatter()
+
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
+			if err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer store.Close()
+
+			dbStats, err := store.GetDBStats()
+			if err != nil {
+				return fmt.Errorf("failed to get feeds: %w", err)
+			}
+			list := dbStats.Feeds
+			if stats {
+				sort.SliceStable(list, func(i, j int) bool {
+					if list[i].Subscribers != list[j].Subscribers {
+						return list[i].Subscribers > list[j].Subscribers
+					}
+					return list[i].Articles > list[j].Articles
+				})
+			}
+			return formatter.OutputFeedList(list, stats)
+		},
+	}
+	cmd.Flags().BoolVar(&stats, "stats", false, "include subscriber counts and article totals")
+	return cmd
+}
+
 func readCmd() *cobra.Command {
 	var userID int64
 	cmd := &cobra.Command{
@@ -794,7 +989,7 @@ func readCmd() *cobra.Command {
 				return fmt.Errorf("invalid article ID: %w", err)
 			}
 
-			store, err := storage.NewStore(cfg.Database.Path)
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
 			if err != nil {
 				return fmt.Errorf("failed to open database: %w", err)
 			}
@@ -833,8 +1028,12 @@ func initConfigCmd() *cobra.Command {
 			}
 
 			// Write default config
-			cfg := storage.DefaultConfig()
-			data, err := yaml.Marshal(cfg)
+			var doc yaml.Node
+			if err := doc.Encode(storage.DefaultConfig()); err != nil {
+				return fmt.Errorf("failed to marshal config: %w", err)
+			}
+			annotateConfig(&doc)
+			data, err := yaml.Marshal(&doc)
 			if err != nil {
 				return fmt.Errorf("failed to marshal config: %w", err)
 			}
@@ -849,6 +1048,43 @@ func initConfigCmd() *cobra.Command {
 	}
 }
 
+// configComments annotates sections of the file written by init-config.
+// The "" entry is the section's head comment; the rest are per-key comments.
+var configComments = map[string]map[string]string{
+	"fetch": {
+		"":                      "Feed fetching. Raise max_concurrency when polling many feeds; raise the\ntimeout or max_retries on slow or flaky networks.",
+		"fetch_timeout_seconds": "per-attempt HTTP timeout",
+		"max_concurrency":       "feeds fetched in parallel (1 = serial)",
+		"max_retries":           "extra attempts after a failed fetch, with doubling backoff",
+		"detect_language":       "tag new articles with their detected language",
+	},
+	"ollama": {
+		"security_max_content": "article characters sent to the security model (minimum 1000)",
+		"curation_max_content": "article characters sent for scoring and summaries; capped at security_max_content",
+	},
+	"output": {
+		"default_format": "default for --format: json, text or human",
+		"quiet":          "suppress warnings and errors on stderr (--quiet)",
+	},
+}
+
+// annotateConfig attaches configComments to an encoded config mapping.
+func annotateConfig(doc *yaml.Node) {
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, val := doc.Content[i], doc.Content[i+1]
+		comments, ok := configComments[key.Value]
+		if !ok {
+			continue
+		}
+		key.HeadComment = comments[""]
+		for j := 0; j+1 < len(val.Content); j += 2 {
+			if c, ok := comments[val.Content[j].Value]; ok {
+				val.Content[j+1].LineComment = c
+			}
+		}
+	}
+}
+
 func migrateDBCmd() *cobra.Command {
 	var srcDSN, dstDSN string
 	cmd := &cobra.Command{
@@ -910,6 +1146,87 @@ Example — PostgreSQL to SQLite:
 	return cmd
 }
 
+func backupCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "backup <path>",
+		Short: "Write a consistent snapshot of the SQLite database",
+		Long: `Writes a snapshot of the configured SQLite database to <path>. The
+snapshot is consistent even while herald-web or the daemon are running,
+unlike copying the database file directly. <path> must not already exist.
+
+PostgreSQL deployments should use pg_dump instead.
+
+Example:
+  herald backup ./backups/herald-$(date +%F).db`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
+			if err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer store.Close()
+
+			if err := store.Backup(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Backed up database to %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func maintenanceCmd() *cobra.Command {
+	var check, vacuum bool
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Verify database integrity and reclaim unused space",
+		Long: `Runs database maintenance tasks that are too slow to do on every open.
+
+--check runs SQLite's integrity check and lists any problems found.
+--vacuum rebuilds the database file to reclaim space, which is worthwhile
+after pruning a large number of articles.
+
+Example:
+  herald maintenance --check --vacuum`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !check && !vacuum {
+				return fmt.Errorf("at least one of --check or --vacuum is required")
+			}
+
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
+			if err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer store.Close()
+
+			if check {
+				problems, err := store.IntegrityCheck()
+				if err != nil {
+					return err
+				}
+				if len(problems) > 0 {
+					for _, p := range problems {
+						fmt.Fprintf(os.Stderr, "  %s\n", p)
+					}
+					return fmt.Errorf("integrity check found %d problem(s)", len(problems))
+				}
+				fmt.Println("Integrity check: ok")
+			}
+
+			if vacuum {
+				if err := store.Vacuum(); err != nil {
+					return err
+				}
+				fmt.Println("Vacuum complete")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&check, "check", false, "run an integrity check")
+	cmd.Flags().BoolVar(&vacuum, "vacuum", false, "vacuum the database to reclaim space")
+	return cmd
+}
+
 func resetScoresCmd() *cobra.Command {
 	var userID int64
 	var securityOnly bool
@@ -934,7 +1251,7 @@ Examples:
   # Reset only the worst security failures:
   herald reset-scores --security-only --below 4.0`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			store, err := storage.NewStore(cfg.Database.Path)
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
 			if err != nil {
 				return fmt.Errorf("failed to open store: %w", err)
 			}
@@ -958,6 +1275,105 @@ Examples:
 	return cmd
 }
 
+func resummarizeCmd() *cobra.Command {
+	var userID, feedID int64
+	cmd := &cobra.Command{
+		Use:   "resummarize",
+		Short: "Regenerate a feed's AI summaries with the current prompt",
+		Long: `Discards and regenerates the AI summary of every article in a feed that
+already has one for the user. Run this after changing the summarization
+prompt or summary preferences; new articles pick up changes automatically.
+
+Example:
+  herald resummarize --user 1 --feed 42`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("user") {
+				userID = cfg.DefaultUserID
+			}
+			if cfg.Ollama.BaseURL == "" {
+				return fmt.Errorf("resummarize requires ollama.base_url to be configured")
+			}
+
+			engine, err := herald.NewEngine(herald.EngineConfig{
+				DBPath:            cfg.Database.Path,
+				BusyTimeout:       cfg.Database.BusyTimeout,
+				JournalMode:       cfg.Database.JournalMode,
+				CreateDefaultUser: cfg.Database.CreateDefaultUser,
+				OllamaBaseURL:     cfg.Ollama.BaseURL,
+				SecurityModel:     cfg.Ollama.SecurityModel,
+				CurationModel:     cfg.Ollama.CurationModel,
+				CurationModels:    cfg.Ollama.CurationModels,
+				UserID:            userID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create engine: %w", err)
+			}
+			defer engine.Close()
+
+			n, err := engine.RegenerateFeedSummaries(context.Background(), userID, feedID)
+			if err != nil {
+				return fmt.Errorf("resummarize failed: %w", err)
+			}
+			fmt.Printf("Regenerated %d summaries for feed %d\n", n, feedID)
+			return nil
+		},
+	}
+	cmd.Flags().Int64VarP(&userID, "user", "u", 0, "user ID whose summaries to regenerate")
+	cmd.Flags().Int64Var(&feedID, "feed", 0, "feed ID to regenerate summaries for")
+	cmd.MarkFlagRequired("feed") //nolint:errcheck
+	return cmd
+}
+
+func rescoreCmd() *cobra.Command {
+	var userID int64
+	cmd := &cobra.Command{
+		Use:   "rescore",
+		Short: "Re-run interest scoring on unread articles with the current keywords",
+		Long: `Clears and recomputes the interest score of every unread article that
+passed the security check, using the user's current keywords. Run this after
+changing keywords or interest_threshold; otherwise only new articles pick up
+the change. Security verdicts, summaries and read state are left untouched.
+
+Example:
+  herald rescore --user 1`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("user") {
+				userID = cfg.DefaultUserID
+			}
+			if cfg.Ollama.BaseURL == "" {
+				return fmt.Errorf("rescore requires ollama.base_url to be configured")
+			}
+
+			engine, err := herald.NewEngine(herald.EngineConfig{
+				DBPath:            cfg.Database.Path,
+				BusyTimeout:       cfg.Database.BusyTimeout,
+				JournalMode:       cfg.Database.JournalMode,
+				CreateDefaultUser: cfg.Database.CreateDefaultUser,
+				OllamaBaseURL:     cfg.Ollama.BaseURL,
+				SecurityModel:     cfg.Ollama.SecurityModel,
+				CurationModel:     cfg.Ollama.CurationModel,
+				CurationModels:    cfg.Ollama.CurationModels,
+				SecurityThreshold: cfg.Thresholds.SecurityScore,
+				Keywords:          cfg.Preferences.Keywords.Terms(),
+				UserID:            userID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create engine: %w", err)
+			}
+			defer engine.Close()
+
+			n, err := engine.RescoreUnread(context.Background(), userID)
+			if err != nil {
+				return fmt.Errorf("rescore failed: %w", err)
+			}
+			fmt.Printf("Rescored %d unread articles for user %d\n", n, userID)
+			return nil
+		},
+	}
+	cmd.Flags().Int64VarP(&userID, "user", "u", 0, "user ID whose articles to rescore")
+	return cmd
+}
+
 func backfillEmbeddingsCmd() *cobra.Command {
 	var batchSize int
 	cmd := &cobra.Command{
@@ -972,9 +1388,12 @@ Existing embeddings are not regenerated unless the embedding model has changed.`
 			ctx := context.Background()
 
 			engine, err := herald.NewEngine(herald.EngineConfig{
-				DBPath:        cfg.Database.Path,
-				OllamaBaseURL: cfg.Ollama.BaseURL,
-				UserID:        cfg.DefaultUserID,
+				DBPath:            cfg.Database.Path,
+				BusyTimeout:       cfg.Database.BusyTimeout,
+				JournalMode:       cfg.Database.JournalMode,
+				CreateDefaultUser: cfg.Database.CreateDefaultUser,
+				OllamaBaseURL:     cfg.Ollama.BaseURL,
+				UserID:            cfg.DefaultUserID,
 			})
 			if err != nil {
 				return fmt.Errorf("failed to create engine: %w", err)
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/matthewjhunter/herald/internal/storage"
+)
+
+func TestUsersToProcess(t *testing.T) {
+	store, err := storage.NewSQLiteStore(filepath.Join(t.TempDir(), "herald.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer store.Close()
+
+	idle := subscribeUser(t, store, "idle", "https://example.com/idle.xml")
+	busy := subscribeUser(t, store, "busy", "https://example.com/busy.xml")
+	feeds, err := store.GetUserFeeds(busy)
+	if err != nil || len(feeds) != 1 {
+		t.Fatalf("GetUserFeeds = %v, %v", feeds, err)
+	}
+	if _, err := store.AddArticle(&storage.Article{FeedID: feeds[0].ID, GUID: "a1", Title: "Unscored"}); err != nil {
+		t.Fatalf("AddArticle: %v", err)
+	}
+
+	// A fetch that found nothing new still processes users with a backlog.
+	got, err := usersToProcess(store, false)
+	if err != nil {
+		t.Fatalf("usersToProcess: %v", err)
+	}
+	if !slices.Equal(got, []int64{busy}) {
+		t.Errorf("without new articles = %v, want [%d]", got, busy)
+	}
+
+	got, err = usersToProcess(store, true)
+	if err != nil {
+		t.Fatalf("usersToProcess: %v", err)
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []int64{idle, busy}) {
+		t.Errorf("with new articles = %v, want [%d %d]", got, idle, busy)
+	}
+}
+
+// subscribeUser creates a user subscribed to a new feed at url.
+func subscribeUser(t *testing.T, store *storage.SQLiteStore, name, url string) int64 {
+	t.Helper()
+	userID, err := store.CreateUser(name)
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	feedID, err := store.AddFeed(url, name, "")
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	if err := store.SubscribeUserToFeed(userID, feedID); err != nil {
+		t.Fatalf("SubscribeUserToFeed: %v", err)
+	}
+	return userID
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	herald "github.com/matthewjhunter/herald"
+	"github.com/matthewjhunter/herald/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func prefsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prefs",
+		Short: "Show or change a user's preferences",
+		Long: `Reads and writes the per-user preferences otherwise set through the web
+UI or MCP tools, so provisioning scripts can configure users.
+
+Examples:
+  herald prefs get --user 2
+  herald prefs set --user 2 interest_threshold 7.5
+  herald prefs set --user 2 keywords '["golang", {"term": "rust", "weight": 2}]'`,
+	}
+	cmd.AddCommand(prefsGetCmd())
+	cmd.AddCommand(prefsSetCmd())
+	return cmd
+}
+
+func prefsGetCmd() *cobra.Command {
+	var userID int64
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Print all stored preferences as JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("user") {
+				userID = cfg.DefaultUserID
+			}
+
+			store, err := storage.NewStoreWithOptions(cfg.Database.Path, cfg.SQLiteOptions())
+			if err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer store.Close()
+
+			prefs, err := store.GetAllUserPreferences(userID)
+			if err != nil {
+				return fmt.Errorf("failed to get preferences: %w", err)
+			}
+			if prefs == nil {
+				prefs = map[string]string{}
+			}
+			data, err := json.MarshalIndent(prefs, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return nil
+		},
+	}
+	cmd.Flags().Int64VarP(&userID, "user", "u", 0, "user ID")
+	return cmd
+}
+
+func prefsSetCmd() *cobra.Command {
+	var userID int64
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Validate and store one preference",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("user") {
+				userID = cfg.DefaultUserID
+			}
+
+			engine, err := herald.NewEngine(herald.EngineConfig{
+				DBPath:            cfg.Database.Path,
+				BusyTimeout:       cfg.Database.BusyTimeout,
+				JournalMode:       cfg.Database.JournalMode,
+				CreateDefaultUser: cfg.Database.CreateDefaultUser,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer engine.Close()
+
+			if err := engine.SetPreference(userID, args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Set %s for user %d\n", args[0], userID)
+			return nil
+		},
+	}
+	cmd.Flags().Int64VarP(&userID, "user", "u", 0, "user ID")
+	return cmd
+}
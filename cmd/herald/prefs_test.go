@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/matthewjhunter/herald/internal/storage"
+)
+
+// runPrefs runs a prefs subcommand against a temporary database.
+func runPrefs(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := prefsCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestPrefsCmd(t *testing.T) {
+	cfg = storage.DefaultConfig()
+	cfg.Database.Path = filepath.Join(t.TempDir(), "herald.db")
+	store, err := storage.NewSQLiteStore(cfg.Database.Path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	userID, err := store.CreateUser("alice")
+	store.Close()
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	user := "--user=" + strconv.FormatInt(userID, 10)
+
+	if _, err := runPrefs(t, "set", user, "favourite_colour", "blue"); err == nil ||
+		!strings.Contains(err.Error(), `unknown preference key: "favourite_colour"`) {
+		t.Errorf("set unknown key: err = %v", err)
+	}
+	if _, err := runPrefs(t, "set", user, "interest_threshold", "eleven"); err == nil {
+		t.Error("set with an invalid value should fail")
+	}
+
+	if _, err := runPrefs(t, "set", user, "interest_threshold", "7.5"); err != nil {
+		t.Fatalf("set interest_threshold: %v", err)
+	}
+	out, err := runPrefs(t, "get", user)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	var prefs map[string]string
+	if err := json.Unmarshal([]byte(out), &prefs); err != nil {
+		t.Fatalf("get output is not JSON: %v\n%s", err, out)
+	}
+	if prefs["interest_threshold"] != "7.5" {
+		t.Errorf("interest_threshold = %q, want 7.5 (all: %v)", prefs["interest_threshold"], prefs)
+	}
+}
@@ -1,26 +1,42 @@
 package herald
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"html"
+	"html/template"
 	"io"
-	"log"
+	"log/slog"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	embedding "github.com/matthewjhunter/go-embedding"
 	"github.com/matthewjhunter/herald/internal/ai"
 	emailpkg "github.com/matthewjhunter/herald/internal/email"
 	"github.com/matthewjhunter/herald/internal/feeds"
+	"github.com/matthewjhunter/herald/internal/langdetect"
+	"github.com/matthewjhunter/herald/internal/notify"
 	"github.com/matthewjhunter/herald/internal/storage"
+	"github.com/microcosm-cc/bluemonday"
 )
 
+// ErrReadOnly is returned by Engine methods that write when the engine was
+// created with EngineConfig.ReadOnly.
+var ErrReadOnly = storage.ErrReadOnly
+
+// ErrPrivateFeed is returned when subscribing to a feed that another user
+// fetches with custom headers, since those are that user's credentials.
+var ErrPrivateFeed = storage.ErrPrivateFeed
+
 // Engine is the public API for herald's content processing pipeline.
 // It wraps the internal storage, feed fetcher, and AI processor.
 type Engine struct {
@@ -29,8 +45,13 @@ type Engine struct {
 	ai           *ai.AIProcessor
 	groupMatcher *ai.GroupMatcher
 	config       *storage.Config
-	maxParallel  int          // max concurrent AI pipeline workers (1 = serial)
-	mu           sync.RWMutex // protects config fields modified at runtime
+	maxParallel  int           // max concurrent AI pipeline workers (1 = serial)
+	aiSem        chan struct{} // shared by every ProcessNewArticles call; capacity maxParallel
+	processDelay time.Duration // minimum article age before AI processing
+	readOnly     bool          // refuse AI-driven writes such as RegenerateSummary
+	log          *slog.Logger  // structured event log; never nil
+	dbPath       string        // database the engine was opened on; fixed for its lifetime
+	mu           sync.RWMutex  // guards the config pointer; see cfg and updateConfig
 }
 
 // NewEngine creates a herald content engine backed by the given SQLite database.
@@ -41,6 +62,9 @@ func NewEngine(cfg EngineConfig) (*Engine, error) {
 	if cfg.SecurityModel == "" {
 		cfg.SecurityModel = "gemma4"
 	}
+	if len(cfg.CurationModels) > 0 {
+		cfg.CurationModel = cfg.CurationModels[0]
+	}
 	if cfg.CurationModel == "" {
 		cfg.CurationModel = "gemma4"
 	}
@@ -51,7 +75,12 @@ func NewEngine(cfg EngineConfig) (*Engine, error) {
 		cfg.SecurityThreshold = 7.0
 	}
 
-	store, err := storage.NewStore(cfg.DBPath)
+	store, err := storage.NewStoreWithOptions(cfg.DBPath, storage.SQLiteOptions{
+		BusyTimeout:       cfg.BusyTimeout,
+		JournalMode:       cfg.JournalMode,
+		ReadOnly:          cfg.ReadOnly,
+		CreateDefaultUser: cfg.CreateDefaultUser,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -62,14 +91,38 @@ func NewEngine(cfg EngineConfig) (*Engine, error) {
 	}
 	storeCfg.Ollama.SecurityModel = cfg.SecurityModel
 	storeCfg.Ollama.CurationModel = cfg.CurationModel
+	storeCfg.Ollama.CurationModels = cfg.CurationModels
 	storeCfg.Thresholds.InterestScore = cfg.InterestThreshold
 	storeCfg.Thresholds.SecurityScore = cfg.SecurityThreshold
-	storeCfg.Preferences.Keywords = cfg.Keywords
+	storeCfg.Preferences.Keywords = storage.KeywordsFromTerms(cfg.Keywords)
+	storeCfg.Preferences.AvoidKeywords = storage.KeywordsFromTerms(cfg.AvoidKeywords)
+	if cfg.GroupTitleThreshold != 0 {
+		storeCfg.Grouping.TitleSimilarityThreshold = cfg.GroupTitleThreshold
+	}
+	if cfg.MinGroupSize > 0 {
+		storeCfg.Grouping.MinGroupSize = cfg.MinGroupSize
+	}
+	if cfg.SummaryMinChange > 0 {
+		storeCfg.Grouping.SummaryMinChange = cfg.SummaryMinChange
+	}
+	if cfg.SecurityMaxContent > 0 {
+		storeCfg.Ollama.SecurityMaxContent = cfg.SecurityMaxContent
+	}
+	if cfg.CurationMaxContent > 0 {
+		storeCfg.Ollama.CurationMaxContent = cfg.CurationMaxContent
+	}
+	storeCfg.Prompts.Dir = cfg.PromptDir
 
 	// Fetcher is always created; it is a stateless HTTP client wrapper with no
 	// background goroutines.  Background polling (FetchAllFeeds) is only called
 	// by the daemon process and is separately guarded by ReadOnly.
-	fetcher := feeds.NewFetcher(store)
+	fetcher := feeds.NewFetcherWithOptions(store, feeds.Options{
+		Timeout:        cfg.FetchTimeout,
+		MaxConcurrency: cfg.FetchConcurrency,
+		MaxRetries:     cfg.FetchRetries,
+		DetectLanguage: cfg.DetectLanguage,
+		Proxy:          cfg.FetchProxy,
+	})
 
 	var processor *ai.AIProcessor
 	if !cfg.ReadOnly && cfg.OllamaBaseURL != "" {
@@ -101,26 +154,136 @@ func NewEngine(cfg EngineConfig) (*Engine, error) {
 		groupMatcher: groupMatcher,
 		config:       storeCfg,
 		maxParallel:  maxParallel,
+		aiSem:        make(chan struct{}, maxParallel),
+		processDelay: cfg.ProcessDelay,
+		readOnly:     cfg.ReadOnly,
+		log:          cfg.Logger,
+		dbPath:       cfg.DBPath,
+	}
+	if e.log == nil {
+		e.log = slog.Default()
 	}
 
-	// Overlay DB-stored preferences onto config (DB takes precedence over CLI flags).
-	if cfg.UserID > 0 {
-		if prefs, err := store.GetAllUserPreferences(cfg.UserID); err == nil {
-			if v, ok := prefs["keywords"]; ok {
-				var kw []string
-				if json.Unmarshal([]byte(v), &kw) == nil {
-					storeCfg.Preferences.Keywords = kw
-				}
-			}
-			if v, ok := prefs["interest_threshold"]; ok {
-				if f, err := strconv.ParseFloat(v, 64); err == nil {
-					storeCfg.Thresholds.InterestScore = f
-				}
-			}
+	overlayUserPreferences(store, storeCfg, cfg.UserID)
+
+	return e, nil
+}
+
+// overlayUserPreferences applies userID's DB-stored keywords and interest
+// threshold onto cfg; DB values take precedence over CLI flags and config
+// files. A zero userID leaves cfg unchanged.
+func overlayUserPreferences(store storage.Store, cfg *storage.Config, userID int64) {
+	if userID <= 0 {
+		return
+	}
+	prefs, err := store.GetAllUserPreferences(userID)
+	if err != nil {
+		return
+	}
+	if v, ok := prefs["keywords"]; ok {
+		var kw storage.Keywords
+		if json.Unmarshal([]byte(v), &kw) == nil {
+			cfg.Preferences.Keywords = kw
+		}
+	}
+	if v, ok := prefs["avoid_keywords"]; ok {
+		var kw storage.Keywords
+		if json.Unmarshal([]byte(v), &kw) == nil {
+			cfg.Preferences.AvoidKeywords = kw
 		}
 	}
+	if v, ok := prefs["interest_threshold"]; ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.Thresholds.InterestScore = f
+		}
+	}
+}
 
-	return e, nil
+// ReloadConfig applies the live-changeable fields of cfg to a running engine:
+// interest and security thresholds, keywords, and the security and curation
+// models. Zero thresholds and empty model names keep the current values, and
+// DB-stored preferences for cfg.UserID still take precedence. Fields that
+// can't change without reopening the engine (DBPath) are ignored with a
+// warning.
+func (e *Engine) ReloadConfig(cfg EngineConfig) {
+	if cfg.DBPath != "" && cfg.DBPath != e.dbPath {
+		e.log.Warn("config reload: db path can't change without a restart; ignoring",
+			"current", e.dbPath, "requested", cfg.DBPath)
+	}
+
+	if len(cfg.CurationModels) > 0 {
+		cfg.CurationModel = cfg.CurationModels[0]
+	}
+	c := e.updateConfig(func(c *storage.Config) {
+		if cfg.InterestThreshold != 0 {
+			c.Thresholds.InterestScore = cfg.InterestThreshold
+		}
+		if cfg.SecurityThreshold != 0 {
+			c.Thresholds.SecurityScore = cfg.SecurityThreshold
+		}
+		c.Preferences.Keywords = storage.KeywordsFromTerms(cfg.Keywords)
+		c.Preferences.AvoidKeywords = storage.KeywordsFromTerms(cfg.AvoidKeywords)
+		if cfg.SecurityModel != "" {
+			c.Ollama.SecurityModel = cfg.SecurityModel
+		}
+		if cfg.CurationModel != "" {
+			c.Ollama.CurationModel = cfg.CurationModel
+		}
+		c.Ollama.CurationModels = cfg.CurationModels
+		overlayUserPreferences(e.store, c, cfg.UserID)
+	})
+	if e.ai != nil {
+		e.ai.SetModels(cfg.SecurityModel, cfg.CurationModel)
+		var fallbacks []string
+		if len(cfg.CurationModels) > 1 {
+			fallbacks = cfg.CurationModels[1:]
+		}
+		e.ai.SetCurationFallbacks(fallbacks)
+	}
+
+	e.log.Info("config reloaded", "event", "config_reload",
+		"interest_threshold", c.Thresholds.InterestScore,
+		"security_threshold", c.Thresholds.SecurityScore,
+		"keywords", len(c.Preferences.Keywords),
+		"avoid_keywords", len(c.Preferences.AvoidKeywords),
+		"security_model", c.Ollama.SecurityModel,
+		"curation_model", c.Ollama.CurationModel)
+}
+
+// cfg returns the engine's current config. The returned value is a snapshot:
+// updates replace it rather than modifying it, so callers may read it without
+// holding e.mu.
+func (e *Engine) cfg() *storage.Config {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.config
+}
+
+// updateConfig applies fn to a copy of the current config and installs the
+// copy, passing it on to the AI processor. It returns the new config.
+func (e *Engine) updateConfig(fn func(*storage.Config)) *storage.Config {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c := *e.config
+	fn(&c)
+	e.config = &c
+	if e.ai != nil {
+		e.ai.SetConfig(&c)
+	}
+	return &c
+}
+
+// InterestThreshold returns the engine's default high-interest score
+// threshold, reflecting any ReloadConfig changes. A user's own threshold
+// comes from GetPreferences.
+func (e *Engine) InterestThreshold() float64 {
+	return e.cfg().Thresholds.InterestScore
+}
+
+// MaxParallel returns the engine-wide limit on concurrently processed
+// articles.
+func (e *Engine) MaxParallel() int {
+	return e.maxParallel
 }
 
 // FetchAllFeeds fetches all subscribed feeds and stores new articles.
@@ -128,10 +291,18 @@ func (e *Engine) FetchAllFeeds(ctx context.Context) (*FetchResult, error) {
 	if e.fetcher == nil {
 		return nil, fmt.Errorf("feed fetching not available in read-only mode")
 	}
+	e.deleteExpiredFeeds()
 	stats, err := e.fetcher.FetchAllFeeds(ctx)
 	if err != nil {
 		return nil, err
 	}
+	// Pick up favicons for feeds subscribed since the last poll. This runs
+	// after the fetch so new feeds already have their site link recorded.
+	if n, err := e.fetcher.FetchFaviconsForFeeds(ctx); err != nil {
+		e.log.Warn("favicon fetch failed", "err", err)
+	} else if n > 0 {
+		e.log.Info("cached feed favicons", "count", n)
+	}
 	return &FetchResult{
 		FeedsTotal:       stats.FeedsTotal,
 		FeedsDownloaded:  stats.FeedsDownloaded,
@@ -145,9 +316,16 @@ func (e *Engine) FetchAllFeeds(ctx context.Context) (*FetchResult, error) {
 // scoring) on unscored articles for the given user. Returns scored articles.
 // Articles that fail individual AI steps are skipped, not fatal.
 //
-// Up to e.maxParallel articles are processed concurrently. Within each article,
-// summarization and security check run in parallel since they are independent;
-// curation runs only after security passes.
+// Up to e.maxParallel articles are processed concurrently. The limit is
+// engine-wide, so concurrent calls for different users share it rather than
+// each getting their own. Within each article, summarization and security
+// check run in parallel since they are independent; curation runs only after
+// security passes.
+//
+// Articles scoring at or above the user's autostar_min_score are starred.
+// Groups that gained articles are only marked dirty while articles are
+// processed; each one's summary is regenerated once, after the queue is
+// drained (see refreshGroupSummaries).
 func (e *Engine) ProcessNewArticles(ctx context.Context, userID int64) ([]ScoredArticle, error) {
 	if e.ai == nil {
 		return nil, nil
@@ -156,14 +334,22 @@ func (e *Engine) ProcessNewArticles(ctx context.Context, userID int64) ([]Scored
 	var (
 		mu     sync.Mutex
 		scored []ScoredArticle
+		dirty  = make(map[int64]bool)
 	)
+	var autostar float64
+	if prefs, err := e.GetPreferences(userID); err == nil {
+		autostar = prefs.AutostarMinScore
+	}
+	start := time.Now()
+	defer func() {
+		e.log.Info("article processing finished", "event", "process_finish", "user_id", userID,
+			"scored", len(scored), "duration_ms", time.Since(start).Milliseconds())
+	}()
 
-	// sem limits the number of concurrently running article pipelines.
-	sem := make(chan struct{}, e.maxParallel)
 	var wg sync.WaitGroup
 
 	for ctx.Err() == nil { //nolint:staticcheck // QF1006: batch-fetch-then-check pattern is intentional
-		articles, err := e.store.GetUnscoredArticlesForUser(userID, 100)
+		articles, err := e.store.GetUnscoredArticlesForUser(userID, 100, e.processDelay)
 		if err != nil {
 			return scored, fmt.Errorf("get unscored articles: %w", err)
 		}
@@ -176,24 +362,32 @@ func (e *Engine) ProcessNewArticles(ctx context.Context, userID int64) ([]Scored
 				break
 			}
 
-			sem <- struct{}{}
+			// e.aiSem limits the number of concurrently running article
+			// pipelines across all callers.
+			acquired := false
+			select {
+			case e.aiSem <- struct{}{}:
+				acquired = true
+			case <-ctx.Done():
+			}
+			if !acquired {
+				break
+			}
+
 			wg.Add(1)
 			go func(article storage.Article) {
-				defer func() { <-sem; wg.Done() }()
+				defer func() { <-e.aiSem; wg.Done() }()
 
-				content := article.Content
-				if content == "" {
-					content = article.Summary
-				}
-				if article.LinkedContent != "" {
-					content = content + "\n\n" + article.LinkedContent
-				}
+				// The security check screens the text as the feed sent it;
+				// strip patterns only trim what the later models read.
+				raw := e.rawArticleText(article)
+				content := e.stripBoilerplate(article.FeedID, e.ai.ScreenedText(raw))
 
 				// Skip entire AI pipeline for articles too short to process meaningfully.
 				// Mark as scored so they don't block the queue forever.
-				minLen := e.config.Summarization.MinArticleLength
+				minLen := e.cfg().Summarization.MinArticleLength
 				if minLen > 0 && len(content) < minLen {
-					log.Printf("herald: skipping AI pipeline for article %d: content too short (%d < %d)", article.ID, len(content), minLen)
+					e.log.Info("skipping AI pipeline: content too short", "event", "article_skip", "article_id", article.ID, "length", len(content), "min_length", minLen)
 					zero := 0.0
 					reason := fmt.Sprintf("content too short (%d < %d)", len(content), minLen)
 					e.store.UpdateReadState(userID, article.ID, false, &zero, &zero, &reason) //nolint:errcheck
@@ -202,15 +396,15 @@ func (e *Engine) ProcessNewArticles(ctx context.Context, userID int64) ([]Scored
 
 				// Security check runs first — blocks summarization and curation
 				// of content that may contain prompt injection or adversarial text.
-				secResult, secErr := e.ai.SecurityCheck(ctx, userID, article.Title, content)
+				secResult, secErr := e.ai.SecurityCheck(ctx, userID, article.Title, raw)
 
 				if secErr != nil {
-					log.Printf("herald: security check failed for article %d: %v", article.ID, secErr)
+					e.log.Warn("security check failed", "article_id", article.ID, "err", secErr)
 					e.store.IncrementAIRetries(userID, article.ID) //nolint:errcheck
 					return
 				}
 
-				if !secResult.Safe || secResult.Score < e.config.Thresholds.SecurityScore {
+				if !secResult.Safe || secResult.Score < e.cfg().Thresholds.SecurityScore {
 					secScore := secResult.Score
 					zero := 0.0
 					e.store.UpdateReadState(userID, article.ID, false, &zero, &secScore, &secResult.Reasoning) //nolint:errcheck
@@ -218,26 +412,9 @@ func (e *Engine) ProcessNewArticles(ctx context.Context, userID int64) ([]Scored
 				}
 
 				// Summarization and curation run after security passes.
-				existing, _ := e.store.GetArticleSummary(userID, article.ID)
-				if existing == nil {
-					maxLen := e.config.Summarization.MaxSummaryLength
-					summary, err := e.ai.SummarizeArticle(ctx, userID, article.Title, content, maxLen)
-					if err != nil {
-						log.Printf("herald: summarization failed for article %d: %v", article.ID, err)
-					} else if LooksLikeGarbage(summary) {
-						log.Printf("herald: discarding garbled summary for article %d", article.ID)
-					} else if len(summary) > len(content) {
-						log.Printf("herald: discarding summary for article %d: summary longer than content (%d > %d)", article.ID, len(summary), len(content))
-					} else if maxLen > 0 && len(summary) > maxLen+maxLen*15/100 {
-						log.Printf("herald: discarding summary for article %d: exceeds max length by >15%% (%d > %d)", article.ID, len(summary), maxLen)
-					} else {
-						e.store.UpdateArticleAISummary(userID, article.ID, summary) //nolint:errcheck
-					}
-				}
-
-				curResult, err := e.ai.CurateArticle(ctx, userID, article.Title, content, e.config.Preferences.Keywords)
+				curResult, err := e.summarizeAndCurate(ctx, userID, article, content)
 				if err != nil {
-					log.Printf("herald: curation failed for article %d: %v", article.ID, err)
+					e.log.Warn("curation failed", "article_id", article.ID, "err", err)
 					e.store.IncrementAIRetries(userID, article.ID) //nolint:errcheck
 					return
 				}
@@ -245,63 +422,18 @@ func (e *Engine) ProcessNewArticles(ctx context.Context, userID int64) ([]Scored
 				secScore := secResult.Score
 				interestScore := curResult.InterestScore
 				e.store.UpdateReadState(userID, article.ID, false, &interestScore, &secScore, &secResult.Reasoning) //nolint:errcheck
-
-				// Group management: embed article, use similarity as pre-filter,
-				// then call LLM only when embedding suggests a possible match.
-				var articleEmb []float32
-				if e.groupMatcher != nil {
-					articleEmb, _ = e.groupMatcher.EmbedArticle(ctx, article.Title, content)
-				}
-
-				// Persist the article embedding for semantic search.
-				if articleEmb != nil && e.groupMatcher != nil {
-					e.store.StoreArticleEmbedding(article.ID, embedding.EncodeFloat32s(articleEmb), e.groupMatcher.Model()) //nolint:errcheck
-				}
-
-				// Pre-filter: skip LLM grouping call if no existing group is
-				// even remotely similar. This prevents nonsensical matches.
-				skipLLM := false
-				if articleEmb != nil && e.groupMatcher != nil {
-					bestSim, _ := e.groupMatcher.BestGroupSimilarity(userID, articleEmb)
-					if bestSim < e.config.Grouping.PreFilterThreshold {
-						skipLLM = true
-					}
-				}
-
-				if !skipLLM {
-					userGroups, _ := e.store.GetUserGroups(userID)
-					groupResult, _ := e.ai.FindRelatedGroups(ctx, userID, article, userGroups, e.store)
-					if groupResult != nil && groupResult.IsRelated && len(groupResult.ExistingGroups) > 0 {
-						gID := groupResult.ExistingGroups[0]
-						e.store.AddArticleToGroup(gID, article.ID) //nolint:errcheck
-						// Update group centroid with this article's embedding
-						if articleEmb != nil && e.groupMatcher != nil {
-							e.groupMatcher.UpdateGroupCentroid(ctx, gID, articleEmb) //nolint:errcheck
-						}
-						// If the group is muted, immediately mark the article as read
-						if muted, err := e.store.IsGroupMuted(gID); err == nil && muted {
-							e.store.UpdateReadState(userID, article.ID, true, nil, nil, nil) //nolint:errcheck
-						}
-					} else if groupResult != nil && groupResult.CreateGroup {
-						topic := article.Title
-						if len(topic) > 100 {
-							topic = topic[:100]
-						}
-						displayName := strings.Trim(groupResult.DisplayName, "\"'")
-						if newGroupID, err := e.store.CreateArticleGroup(userID, topic); err == nil {
-							e.store.AddArticleToGroup(newGroupID, article.ID) //nolint:errcheck
-							if displayName != "" {
-								e.store.UpdateGroupDisplayName(newGroupID, displayName) //nolint:errcheck
-							}
-							// Set initial centroid for the new group
-							if articleEmb != nil {
-								e.store.UpdateGroupEmbedding(newGroupID, embedding.EncodeFloat32s(articleEmb), e.groupMatcher.Model()) //nolint:errcheck
-							}
-						}
+				e.recordCuration(userID, article.ID, curResult)
+				if autostar > 0 && interestScore >= autostar {
+					if err := e.store.UpdateStarred(userID, article.ID, true); err != nil {
+						e.log.Warn("autostar failed", "article_id", article.ID, "err", err)
 					}
 				}
+				groupID := e.groupArticle(ctx, userID, article, content)
 
 				mu.Lock()
+				if groupID != 0 {
+					dirty[groupID] = true
+				}
 				scored = append(scored, ScoredArticle{
 					Article:       articleFromInternal(article),
 					InterestScore: interestScore,
@@ -315,18 +447,525 @@ func (e *Engine) ProcessNewArticles(ctx context.Context, userID int64) ([]Scored
 		wg.Wait()
 	}
 
+	e.refreshGroupSummaries(ctx, userID, dirty)
 	return scored, nil
 }
 
-// GetUnreadArticles returns unread articles for a user, up to limit starting at offset.
+// refreshGroupSummaries regenerates the summary of each group that gained
+// articles during a processing run, once per group however many it gained.
+// With grouping.summary_min_change set, a group whose membership has moved
+// by no more than that since its summary was written is left alone; the
+// change carries over to later runs until it adds up.
+func (e *Engine) refreshGroupSummaries(ctx context.Context, userID int64, groups map[int64]bool) {
+	minChange := e.cfg().Grouping.SummaryMinChange
+	for groupID := range groups {
+		if ctx.Err() != nil {
+			return
+		}
+		if minChange > 0 {
+			n, err := e.store.GetGroupArticleCount(groupID)
+			prev, _ := e.store.GetGroupSummary(groupID)
+			if err == nil && !prev.Stale(n, minChange) {
+				continue
+			}
+		}
+		if err := e.regenerateGroupSummary(ctx, userID, groupID); err != nil {
+			e.log.Warn("regenerate group summary failed", "group_id", groupID, "err", err)
+		}
+	}
+}
+
+// summarizeAndCurate runs the pipeline steps that follow a passed security
+// check: it summarizes the article unless a summary is already cached, then
+// curates it. Only a curation failure is returned; a bad summary is logged
+// and dropped.
+func (e *Engine) summarizeAndCurate(ctx context.Context, userID int64, article storage.Article, content string) (*ai.CurationResult, error) {
+	existing, _ := e.store.GetArticleSummary(userID, article.ID)
+	if existing == nil {
+		maxLen := e.cfg().Summarization.MaxSummaryLength
+		summary, err := e.ai.SummarizeArticle(ctx, userID, article.Title, content, maxLen)
+		if err != nil {
+			e.log.Warn("summarization failed", "article_id", article.ID, "err", err)
+		} else if LooksLikeGarbage(summary) {
+			e.log.Warn("discarding summary: garbled", "article_id", article.ID)
+		} else if len(summary) > len(content) {
+			e.log.Warn("discarding summary: longer than content", "article_id", article.ID, "summary_length", len(summary), "length", len(content))
+		} else if maxLen > 0 && len(summary) > maxLen+maxLen*15/100 {
+			e.log.Warn("discarding summary: exceeds max length by >15%", "article_id", article.ID, "summary_length", len(summary), "max_length", maxLen)
+		} else {
+			e.store.UpdateArticleAISummary(userID, article.ID, summary) //nolint:errcheck
+		}
+	}
+	prefs, err := e.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	return e.ai.CurateArticle(ctx, userID, article.Title, content, e.feedTitle(userID, article.FeedID), prefs.Keywords, prefs.AvoidKeywords)
+}
+
+// GetUserFeedTitle returns the user's name for a feed: their rename if they
+// set one, else the feed's own title. Unknown feeds yield "".
+func (e *Engine) GetUserFeedTitle(userID, feedID int64) (string, error) {
+	return e.store.GetUserFeedTitle(userID, feedID)
+}
+
+// feedTitle returns the user's name for a feed, for the curation prompt.
+// A lookup failure is logged and yields "", which the prompt omits.
+func (e *Engine) feedTitle(userID, feedID int64) string {
+	title, err := e.store.GetUserFeedTitle(userID, feedID)
+	if err != nil {
+		e.log.Warn("get feed title failed", "feed_id", feedID, "err", err)
+	}
+	return title
+}
+
+// groupArticle embeds a scored article and places it in a group. Embedding
+// similarity is the pre-filter; the LLM is only asked when the embedding
+// suggests a possible match. Returns the group the article joined, or 0 if
+// it was left ungrouped.
+func (e *Engine) groupArticle(ctx context.Context, userID int64, article storage.Article, content string) int64 {
+	var articleEmb []float32
+	if e.groupMatcher != nil {
+		articleEmb, _ = e.groupMatcher.EmbedArticle(ctx, article.Title, content)
+	}
+
+	// Persist the article embedding for semantic search.
+	if articleEmb != nil && e.groupMatcher != nil {
+		e.store.StoreArticleEmbedding(article.ID, embedding.EncodeFloat32s(articleEmb), e.groupMatcher.Model()) //nolint:errcheck
+	}
+
+	// Compare against cached group centroids first. A match above
+	// the similarity threshold joins that group without an LLM
+	// call; nothing even remotely similar skips the LLM too, which
+	// prevents nonsensical matches. Only the ambiguous middle band
+	// (or an article without an embedding) goes to FindRelatedGroups.
+	skipLLM := false
+	var centroidMatch *int64
+	if articleEmb != nil && e.groupMatcher != nil {
+		var bestSim float64
+		centroidMatch, bestSim, _ = e.groupMatcher.MatchEmbedding(userID, articleEmb)
+		if centroidMatch != nil || bestSim < e.cfg().Grouping.PreFilterThreshold {
+			skipLLM = true
+		}
+	}
+
+	if centroidMatch != nil {
+		return e.joinGroup(ctx, userID, *centroidMatch, article.ID, articleEmb)
+	}
+	if skipLLM {
+		return 0
+	}
+	userGroups, _ := e.store.GetUserGroups(userID)
+	groupResult, err := e.ai.FindRelatedGroups(ctx, userID, article, userGroups, e.store)
+	switch {
+	case err != nil:
+		// LLM grouping unavailable: fall back to title similarity.
+		e.log.Debug("related groups check failed, grouping by title", "article_id", article.ID, "err", err)
+		return e.groupByTitle(ctx, userID, article, articleEmb, "")
+	case groupResult.IsRelated && len(groupResult.ExistingGroups) > 0:
+		return e.joinGroup(ctx, userID, groupResult.ExistingGroups[0], article.ID, articleEmb)
+	case groupResult.CreateGroup:
+		return e.groupByTitle(ctx, userID, article, articleEmb, strings.Trim(groupResult.DisplayName, "\"'"))
+	}
+	return 0
+}
+
+// OverrideSecurity overrules a security check that blocked an article for
+// the user, for a verdict believed to be a false positive. The article is
+// summarized, curated and grouped like one that passed, then its security
+// score is raised to the threshold and it is flagged security_overridden.
+// It fails if the article isn't currently blocked for the user.
+func (e *Engine) OverrideSecurity(ctx context.Context, userID, articleID int64) error {
+	if e.readOnly {
+		return ErrReadOnly
+	}
+	if e.ai == nil {
+		return fmt.Errorf("AI processing is not configured")
+	}
+	article, err := e.ownedArticle(userID, articleID)
+	if err != nil {
+		return err
+	}
+	// Check before curating, so an unblocked article never reaches the AI.
+	threshold := e.cfg().Thresholds.SecurityScore
+	blocked, err := e.store.IsArticleBlocked(userID, articleID, threshold)
+	if err != nil {
+		return err
+	}
+	if !blocked {
+		return fmt.Errorf("article %d is not blocked", articleID)
+	}
+	content := e.articleText(*article)
+	curResult, err := e.summarizeAndCurate(ctx, userID, *article, content)
+	if err != nil {
+		return fmt.Errorf("curate article %d: %w", articleID, err)
+	}
+	if err := e.store.OverrideSecurity(userID, articleID, threshold, curResult.InterestScore); err != nil {
+		return err
+	}
+	e.recordCuration(userID, articleID, curResult)
+	e.groupArticle(ctx, userID, *article, content)
+	e.log.Info("security verdict overridden", "event", "security_override", "user_id", userID, "article_id", articleID,
+		"interest_score", curResult.InterestScore)
+	return nil
+}
+
+// joinGroup adds an article to an existing group during scoring, folds its
+// embedding (if any) into the group centroid incrementally, and marks the
+// article read when the group is muted. Returns groupID, or 0 if the article
+// could not be added.
+func (e *Engine) joinGroup(ctx context.Context, userID, groupID, articleID int64, articleEmb []float32) int64 {
+	if err := e.store.AddArticleToGroup(groupID, articleID); err != nil {
+		e.log.Warn("add article to group failed", "article_id", articleID, "group_id", groupID, "err", err)
+		return 0
+	}
+	if articleEmb != nil && e.groupMatcher != nil {
+		e.groupMatcher.UpdateGroupCentroid(ctx, groupID, articleEmb) //nolint:errcheck
+	}
+	if muted, err := e.store.IsGroupMuted(groupID); err == nil && muted {
+		e.store.UpdateReadState(userID, articleID, true, nil, nil, nil) //nolint:errcheck
+	}
+	return groupID
+}
+
+// titleMatchWindow limits groupByTitle to groups active this recently, so a
+// recurring headline ("Weekly roundup") does not revive a months-old group.
+const titleMatchWindow = 7 * 24 * time.Hour
+
+// groupByTitle puts an article in the recent group whose topic or name is
+// most similar to its title, if any clears
+// grouping.title_similarity_threshold, and otherwise starts a new group named
+// after the article. Single-article groups are candidates, which is how a
+// second report of a story finds the first. With grouping.min_group_size
+// above 1, the new group is only created once that many related articles
+// exist, pulling in the ungrouped ones; until then the article stays
+// ungrouped. It needs no AI calls, so it also serves as the grouping
+// fallback when the LLM is unavailable. displayName labels a new group.
+// Returns the group the article joined, or 0 if it stays ungrouped.
+func (e *Engine) groupByTitle(ctx context.Context, userID int64, article storage.Article, articleEmb []float32, displayName string) int64 {
+	groups, err := e.store.GetRecentGroups(userID, time.Now().Add(-titleMatchWindow))
+	if err != nil {
+		e.log.Warn("list recent groups failed", "article_id", article.ID, "err", err)
+	}
+	if groupID, ok := bestTitleMatch(article.Title, groups, e.cfg().Grouping.TitleSimilarityThreshold); ok {
+		return e.joinGroup(ctx, userID, groupID, article.ID, articleEmb)
+	}
+
+	var related []relatedArticle
+	if minSize := e.cfg().Grouping.MinGroupSize; minSize > 1 {
+		related = e.relatedUngrouped(userID, article, articleEmb)
+		if len(related)+1 < minSize {
+			e.log.Debug("leaving article ungrouped", "article_id", article.ID, "related", len(related), "min_group_size", minSize)
+			return 0
+		}
+	}
+
+	topic := article.Title
+	if len(topic) > 100 {
+		topic = topic[:100]
+	}
+	newGroupID, err := e.store.CreateArticleGroup(userID, topic)
+	if err != nil {
+		e.log.Warn("create group failed", "article_id", article.ID, "err", err)
+		return 0
+	}
+	e.store.AddArticleToGroup(newGroupID, article.ID) //nolint:errcheck
+	if displayName != "" {
+		e.store.UpdateGroupDisplayName(newGroupID, displayName) //nolint:errcheck
+	}
+	// Set initial centroid for the new group
+	if articleEmb != nil && e.groupMatcher != nil {
+		e.store.UpdateGroupEmbedding(newGroupID, embedding.EncodeFloat32s(articleEmb), e.groupMatcher.Model()) //nolint:errcheck
+	}
+	for _, r := range related {
+		e.joinGroup(ctx, userID, newGroupID, r.id, r.emb)
+	}
+	return newGroupID
+}
+
+// relatedArticle is an ungrouped article close enough to another to share
+// a group with it.
+type relatedArticle struct {
+	id  int64
+	emb []float32
+}
+
+// maxRelatedCandidates bounds the ungrouped articles relatedUngrouped
+// compares against.
+const maxRelatedCandidates = 200
+
+// relatedUngrouped returns the user's recent ungrouped articles related to
+// article: a title overlap reaching grouping.title_similarity_threshold, or
+// when both have embeddings, a cosine similarity reaching
+// grouping.similarity_threshold.
+func (e *Engine) relatedUngrouped(userID int64, article storage.Article, articleEmb []float32) []relatedArticle {
+	candidates, err := e.store.GetUngroupedArticles(userID, maxRelatedCandidates, 0)
+	if err != nil {
+		e.log.Warn("list ungrouped articles failed", "article_id", article.ID, "err", err)
+		return nil
+	}
+
+	cutoff := time.Now().Add(-titleMatchWindow)
+	embs := map[int64][]float32{}
+	if articleEmb != nil && e.groupMatcher != nil {
+		rows, err := e.store.GetArticleEmbeddings(userID, e.groupMatcher.Model(), cutoff)
+		if err != nil {
+			e.log.Warn("load article embeddings failed", "article_id", article.ID, "err", err)
+		}
+		for _, r := range rows {
+			embs[r.ArticleID] = embedding.DecodeFloat32s(r.Embedding)
+		}
+	}
+
+	grouping := e.cfg().Grouping
+	titleThreshold := grouping.TitleSimilarityThreshold
+	var related []relatedArticle
+	for _, c := range candidates {
+		if c.ID == article.ID || c.FetchedDate.Before(cutoff) {
+			continue
+		}
+		emb := embs[c.ID]
+		match := titleThreshold > 0 && titleSimilarity(article.Title, c.Title) >= titleThreshold
+		if !match && emb != nil {
+			match = embedding.CosineSimilarity(articleEmb, emb) >= grouping.SimilarityThreshold
+		}
+		if match {
+			related = append(related, relatedArticle{id: c.ID, emb: emb})
+		}
+	}
+	return related
+}
+
+// bestTitleMatch returns the group whose topic or display name has the
+// highest titleSimilarity to title, provided it reaches threshold. A
+// threshold of 0 or less disables matching.
+func bestTitleMatch(title string, groups []storage.ArticleGroup, threshold float64) (int64, bool) {
+	if threshold <= 0 {
+		return 0, false
+	}
+	var bestID int64
+	best := 0.0
+	for _, g := range groups {
+		sim := max(titleSimilarity(title, g.Topic), titleSimilarity(title, g.DisplayName))
+		if sim > best {
+			bestID, best = g.ID, sim
+		}
+	}
+	return bestID, best >= threshold
+}
+
+// titleStopwords are dropped before comparing titles; they match everything.
+var titleStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true,
+	"that": true, "this": true, "are": true, "was": true, "its": true,
+}
+
+// titleSimilarity is the Jaccard index of two titles' word sets: lowercased,
+// split on anything that is not a letter or digit, ignoring words under three
+// characters and common stopwords. It is 0 when either title has no words
+// left.
+func titleSimilarity(a, b string) float64 {
+	ta, tb := titleTokens(a), titleTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range ta {
+		if tb[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+func titleTokens(s string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make(map[string]bool, len(words))
+	for _, w := range words {
+		if len([]rune(w)) >= 3 && !titleStopwords[w] {
+			tokens[w] = true
+		}
+	}
+	return tokens
+}
+
+// articleText returns the text the summarizer and curator read for an
+// article: rawArticleText with the feed's strip patterns removed.
+func (e *Engine) articleText(a storage.Article) string {
+	return e.stripBoilerplate(a.FeedID, e.rawArticleText(a))
+}
+
+// rawArticleText returns an article's body, picked per the feed's content
+// source, plus any linked content. The security check screens this.
+func (e *Engine) rawArticleText(a storage.Article) string {
+	content := e.articleBody(a.FeedID, a.Content, a.Summary)
+	if a.LinkedContent != "" {
+		content = content + "\n\n" + a.LinkedContent
+	}
+	return content
+}
+
+// stripBoilerplate removes feedID's strip patterns from text.
+func (e *Engine) stripBoilerplate(feedID int64, text string) string {
+	patterns, err := e.store.GetFeedStripPatterns(feedID)
+	if err != nil {
+		e.log.Warn("get feed strip patterns failed", "feed_id", feedID, "err", err)
+		return text
+	}
+	return storage.StripBoilerplate(patterns, text)
+}
+
+// RegenerateSummary discards the cached AI summary for an article and
+// summarizes it afresh with the user's current prompt and summary
+// preferences, storing and returning the new text.
+func (e *Engine) RegenerateSummary(ctx context.Context, userID, articleID int64) (string, error) {
+	if e.readOnly {
+		return "", ErrReadOnly
+	}
+	if e.ai == nil {
+		return "", fmt.Errorf("AI processing is not configured")
+	}
+	a, err := e.ownedArticle(userID, articleID)
+	if err != nil {
+		return "", err
+	}
+	summary, err := e.ai.SummarizeArticle(ctx, userID, a.Title, e.articleText(*a), e.cfg().Summarization.MaxSummaryLength)
+	if err != nil {
+		return "", fmt.Errorf("summarize article %d: %w", articleID, err)
+	}
+	if LooksLikeGarbage(summary) {
+		return "", fmt.Errorf("summarize article %d: model returned a garbled summary", articleID)
+	}
+	if err := e.store.UpdateArticleAISummary(userID, articleID, summary); err != nil {
+		return "", err
+	}
+	return summary, nil
+}
+
+// RegenerateFeedSummaries re-runs RegenerateSummary for every article in a
+// feed that already has an AI summary for the user, typically after a prompt
+// change. Per-article failures are logged and skipped. Returns the number of
+// summaries replaced.
+func (e *Engine) RegenerateFeedSummaries(ctx context.Context, userID, feedID int64) (int, error) {
+	if e.readOnly {
+		return 0, ErrReadOnly
+	}
+	if e.ai == nil {
+		return 0, fmt.Errorf("AI processing is not configured")
+	}
+	ids, err := e.store.GetSummarizedArticleIDs(userID, feedID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return n, ctx.Err()
+		}
+		if _, err := e.RegenerateSummary(ctx, userID, id); err != nil {
+			e.log.Warn("resummarize failed", "article_id", id, "err", err)
+			continue
+		}
+		n++
+	}
+	return n, nil
+}
+
+// RescoreUnread re-runs interest curation on the user's unread articles with
+// their current keywords, so preference changes apply to articles that were
+// already scored. Only articles that passed the security check are touched:
+// their interest scores are cleared and recomputed, while read flags,
+// security verdicts and cached summaries are kept. Articles whose curation
+// fails are left unscored and retried by the next call. Returns the number of
+// articles rescored.
+func (e *Engine) RescoreUnread(ctx context.Context, userID int64) (int, error) {
+	if e.readOnly {
+		return 0, ErrReadOnly
+	}
+	if e.ai == nil {
+		return 0, fmt.Errorf("AI processing is not configured")
+	}
+	prefs, err := e.GetPreferences(userID)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := e.store.ClearInterestScores(userID, e.cfg().Thresholds.SecurityScore); err != nil {
+		return 0, err
+	}
+	articles, err := e.store.GetUncuratedUnreadArticles(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, article := range articles {
+		if ctx.Err() != nil {
+			return n, ctx.Err()
+		}
+		result, err := e.ai.CurateArticle(ctx, userID, article.Title, e.articleText(article), e.feedTitle(userID, article.FeedID), prefs.Keywords, prefs.AvoidKeywords)
+		if err != nil {
+			e.log.Warn("rescore failed", "article_id", article.ID, "err", err)
+			continue
+		}
+		if err := e.store.UpdateInterestScore(userID, article.ID, result.InterestScore); err != nil {
+			return n, err
+		}
+		e.recordCuration(userID, article.ID, result)
+		n++
+	}
+	e.log.Info("rescore finished", "event", "rescore_finish", "user_id", userID, "rescored", n)
+	return n, nil
+}
+
+// recordCuration stores curation's justification and suggested tags for an
+// article whose interest score has just been saved. Failures are logged
+// rather than returned: the score is what matters.
+func (e *Engine) recordCuration(userID, articleID int64, result *ai.CurationResult) {
+	if err := e.store.SetInterestReason(userID, articleID, result.Reasoning); err != nil {
+		e.log.Warn("store interest reason failed", "article_id", articleID, "err", err)
+	}
+	if err := e.store.StoreAITags(userID, articleID, result.Tags); err != nil {
+		e.log.Warn("store ai tags failed", "article_id", articleID, "err", err)
+	}
+}
+
+// GetUnreadArticles returns unread articles for a user, newest first, up to
+// limit starting at offset.
 func (e *Engine) GetUnreadArticles(userID int64, limit, offset int) ([]Article, error) {
-	articles, err := e.store.GetUnreadArticlesForUser(userID, limit, offset, e.resolveFilterThreshold(userID))
+	return e.GetUnreadArticlesOrdered(userID, limit, offset, OrderNewest)
+}
+
+// ValidateArticleOrder returns an error unless order names a supported article
+// ordering. The empty string is accepted as OrderNewest.
+func ValidateArticleOrder(order string) error {
+	switch order {
+	case "", OrderNewest, OrderOldest, OrderScore:
+		return nil
+	}
+	return fmt.Errorf("invalid order %q: want %s, %s or %s", order, OrderNewest, OrderOldest, OrderScore)
+}
+
+// GetUnreadArticlesOrdered is GetUnreadArticles sorted by order, one of
+// OrderNewest, OrderOldest or OrderScore. An empty order means OrderNewest.
+func (e *Engine) GetUnreadArticlesOrdered(userID int64, limit, offset int, order string) ([]Article, error) {
+	if err := ValidateArticleOrder(order); err != nil {
+		return nil, err
+	}
+	articles, err := e.store.GetUnreadArticlesOrdered(userID, limit, offset, e.resolveFilterThreshold(userID), e.resolveLanguages(userID), order)
 	if err != nil {
 		return nil, err
 	}
 	return articlesFromInternal(articles), nil
 }
 
+// GetInterestScores returns the user's stored interest scores for
+// articleIDs, keyed by article ID. Unscored articles are absent from the map.
+func (e *Engine) GetInterestScores(userID int64, articleIDs []int64) (map[int64]float64, error) {
+	return e.store.GetInterestScores(userID, articleIDs)
+}
+
 // GetStarredArticles returns starred articles for a user.
 func (e *Engine) GetStarredArticles(userID int64, limit, offset int) ([]Article, error) {
 	articles, err := e.store.GetStarredArticles(userID, limit, offset, e.resolveFilterThreshold(userID))
@@ -336,6 +975,154 @@ func (e *Engine) GetStarredArticles(userID int64, limit, offset int) ([]Article,
 	return articlesFromInternal(articles), nil
 }
 
+// GetArticlesByCategory returns the user's articles tagged with a
+// feed-supplied category, newest first.
+func (e *Engine) GetArticlesByCategory(userID int64, category string, limit, offset int) ([]Article, error) {
+	articles, err := e.store.GetArticlesByCategory(userID, category, limit, offset, e.resolveFilterThreshold(userID))
+	if err != nil {
+		return nil, err
+	}
+	return articlesFromInternal(articles), nil
+}
+
+// GetArticleAuthors returns the author names recorded for an article from
+// its feed entry, which may list several.
+func (e *Engine) GetArticleAuthors(articleID int64) ([]string, error) {
+	authors, err := e.store.GetArticleAuthors(articleID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(authors))
+	for _, a := range authors {
+		names = append(names, a.Name)
+	}
+	return names, nil
+}
+
+// GetArticleCategories returns the categories the article's feed entry
+// was published with.
+func (e *Engine) GetArticleCategories(articleID int64) ([]string, error) {
+	return e.store.GetArticleCategories(articleID)
+}
+
+// GetReadingQueue returns starred articles the user has not read yet, oldest
+// first: the read-later queue, as opposed to the full starred list.
+func (e *Engine) GetReadingQueue(userID int64, limit, offset int) ([]Article, error) {
+	articles, err := e.store.GetReadingQueue(userID, limit, offset, e.resolveFilterThreshold(userID))
+	if err != nil {
+		return nil, err
+	}
+	return articlesFromInternal(articles), nil
+}
+
+// GetReadHistory returns the articles the user has read, most recently read
+// first, so something read yesterday can be found again. Each article
+// carries the number of times the user opened it.
+func (e *Engine) GetReadHistory(userID int64, limit, offset int) ([]Article, error) {
+	articles, err := e.store.GetReadArticles(userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	counts, err := e.store.GetOpenCounts(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := articlesFromInternal(articles)
+	for i := range out {
+		out[i].OpenCount = counts[out[i].ID]
+	}
+	return out, nil
+}
+
+// GetUngroupedArticles returns unread scored articles that clustering did not
+// place in any of the user's groups.
+func (e *Engine) GetUngroupedArticles(userID int64, limit, offset int) ([]Article, error) {
+	articles, err := e.store.GetUngroupedArticles(userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return articlesFromInternal(articles), nil
+}
+
+// GetBlockedArticles returns the user's articles that scored below the
+// security threshold, newest first, for auditing false positives.
+func (e *Engine) GetBlockedArticles(userID int64, limit, offset int) ([]BlockedArticle, error) {
+	blocked, err := e.store.GetBlockedArticles(userID, e.cfg().Thresholds.SecurityScore, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]BlockedArticle, len(blocked))
+	for i, b := range blocked {
+		out[i] = BlockedArticle{
+			Article:        articleFromInternal(b.Article),
+			SecurityScore:  b.SecurityScore,
+			SecurityReason: b.SecurityReason,
+		}
+	}
+	return out, nil
+}
+
+// GetArticlesSince returns articles from the user's subscriptions fetched
+// after since, newest first.
+func (e *Engine) GetArticlesSince(userID int64, since time.Time, limit int) ([]Article, error) {
+	articles, err := e.store.GetArticlesSince(userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return articlesFromInternal(articles), nil
+}
+
+// visitGap is how long a user must be away before a page load counts as a new
+// visit. Reloads inside the gap keep the earlier cutoff, so "new since last
+// visit" doesn't empty itself on every refresh.
+const visitGap = 30 * time.Minute
+
+// firstVisitWindow is how far back "new since last visit" reaches for a user
+// with no earlier visit on record.
+const firstVisitWindow = 24 * time.Hour
+
+// LastVisit returns the cutoff for "new since last visit": when the user's
+// previous visit happened. Call it before RecordVisit so the articles that
+// arrived since then still count as new during the current visit.
+func (e *Engine) LastVisit(userID int64) (time.Time, error) {
+	last, prev, err := e.store.GetUserLastSeen(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return visitCutoff(last, prev, time.Now()), nil
+}
+
+// RecordVisit marks the user as seen now. A page load more than visitGap
+// after the last one starts a new visit, moving the old time to prev_seen_at.
+func (e *Engine) RecordVisit(userID int64) error {
+	last, prev, err := e.store.GetUserLastSeen(userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if !last.IsZero() && now.Sub(last) > visitGap {
+		prev = last
+	}
+	return e.store.SetUserLastSeen(userID, now, prev)
+}
+
+// visitCutoff picks the start of the "new" window given the last two
+// recorded visit times and the current time.
+func visitCutoff(last, prev, now time.Time) time.Time {
+	switch {
+	case !last.IsZero() && now.Sub(last) > visitGap:
+		return last
+	case !prev.IsZero():
+		return prev
+	default:
+		return now.Add(-firstVisitWindow)
+	}
+}
+
 // GetUnreadArticlesByFeed returns unread articles for a user filtered to a specific feed.
 func (e *Engine) GetUnreadArticlesByFeed(userID, feedID int64, limit, offset int) ([]Article, error) {
 	articles, err := e.store.GetUnreadArticlesByFeed(userID, feedID, limit, offset, e.resolveFilterThreshold(userID))
@@ -345,9 +1132,19 @@ func (e *Engine) GetUnreadArticlesByFeed(userID, feedID int64, limit, offset int
 	return articlesFromInternal(articles), nil
 }
 
-// GetArticle returns a single article by ID.
-func (e *Engine) GetArticle(articleID int64) (*Article, error) {
-	a, err := e.store.GetArticle(articleID)
+// GetArticle returns a single article by ID.
+func (e *Engine) GetArticle(articleID int64) (*Article, error) {
+	a, err := e.store.GetArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+	result := articleFromInternal(*a)
+	return &result, nil
+}
+
+// GetArticleBySlug returns the article with the given permalink slug.
+func (e *Engine) GetArticleBySlug(slug string) (*Article, error) {
+	a, err := e.store.GetArticleBySlug(slug)
 	if err != nil {
 		return nil, err
 	}
@@ -355,7 +1152,8 @@ func (e *Engine) GetArticle(articleID int64) (*Article, error) {
 	return &result, nil
 }
 
-// GetArticleForUser returns a single article enriched with its AI summary for the given user.
+// GetArticleForUser returns a single article enriched with the user's AI
+// summary, note, and curation reasoning and tags.
 func (e *Engine) GetArticleForUser(userID, articleID int64) (*Article, error) {
 	a, err := e.store.GetArticle(articleID)
 	if err != nil {
@@ -365,16 +1163,65 @@ func (e *Engine) GetArticleForUser(userID, articleID int64) (*Article, error) {
 	if summary, err := e.store.GetArticleSummary(userID, articleID); err == nil && summary != nil {
 		result.AISummary = summary.AISummary
 	}
+	if note, err := e.store.GetArticleNote(userID, articleID); err == nil && note != nil {
+		result.Note = note.Note
+	}
+	if reason, err := e.store.GetInterestReason(userID, articleID); err == nil {
+		result.InterestReason = reason
+	}
+	if tags, err := e.store.GetAITags(userID, articleID); err == nil {
+		result.Tags = tags
+	}
 	return &result, nil
 }
 
-// GetHighInterestArticles returns unread articles scored above the threshold.
-func (e *Engine) GetHighInterestArticles(userID int64, threshold float64, limit, offset int) ([]Article, []float64, error) {
-	articles, scores, err := e.store.GetArticlesByInterestScore(userID, threshold, limit, offset, e.resolveFilterThreshold(userID))
+// readerFetchTimeout bounds the page fetch behind GetReadableArticle.
+const readerFetchTimeout = 20 * time.Second
+
+// readerPolicy sanitizes reader-view HTML. Policies are safe for concurrent
+// use once built.
+var readerPolicy = bluemonday.UGCPolicy()
+
+// GetReadableArticle returns an article's body for a distraction-free reader
+// view, sanitized for direct embedding in a page. When the stored content is
+// a truncated excerpt the original page is fetched and extracted with
+// readability; if that fails the feed content is used, then the feed summary,
+// then the user's AI summary. An article with none of these yields "".
+func (e *Engine) GetReadableArticle(userID, articleID int64) (template.HTML, error) {
+	a, err := e.store.GetArticle(articleID)
+	if err != nil {
+		return "", err
+	}
+
+	content := e.articleBody(a.FeedID, a.Content, a.Summary)
+	if e.fetcher != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), readerFetchTimeout)
+		body := *a
+		body.Content = content
+		content = e.fetcher.ReadableContent(ctx, body)
+		cancel()
+	}
+	if strings.TrimSpace(content) == "" {
+		content = a.Summary
+	}
+	if strings.TrimSpace(content) == "" {
+		if s, err := e.store.GetArticleSummary(userID, articleID); err == nil && s != nil && s.AISummary != "" {
+			content = "<p>" + html.EscapeString(s.AISummary) + "</p>"
+		}
+	}
+	return template.HTML(readerPolicy.Sanitize(content)), nil //nolint:gosec // sanitized by bluemonday
+}
+
+// GetHighInterestArticles returns unread articles scored above the threshold,
+// ordered by time-decayed score. The threshold applies to the raw stored score.
+// The first score slice holds decayed effective scores, the second the raw
+// stored scores.
+func (e *Engine) GetHighInterestArticles(userID int64, threshold float64, limit, offset int) ([]Article, []float64, []float64, error) {
+	articles, scores, rawScores, err := e.store.GetArticlesByInterestScore(userID, threshold, limit, offset, e.resolveFilterThreshold(userID))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
-	return articlesFromInternal(articles), scores, nil
+	return articlesFromInternal(articles), scores, rawScores, nil
 }
 
 // Search runs full-text search and (when available) semantic embedding search,
@@ -413,7 +1260,7 @@ func (e *Engine) Search(ctx context.Context, userID int64, query string, limit,
 	if e.groupMatcher != nil {
 		queryEmb, embErr := e.groupMatcher.EmbedText(ctx, query)
 		if embErr == nil && queryEmb != nil {
-			rows, embErr := e.store.GetArticleEmbeddings(userID, e.groupMatcher.Model())
+			rows, embErr := e.store.GetArticleEmbeddings(userID, e.groupMatcher.Model(), time.Time{})
 			if embErr == nil && len(rows) > 0 {
 				// Compute cosine similarity for all embeddings.
 				type scored struct {
@@ -493,13 +1340,10 @@ func (e *Engine) BackfillEmbeddings(ctx context.Context, batchSize int) (int, er
 
 	count := 0
 	for _, a := range articles {
-		content := a.Content
-		if content == "" {
-			content = a.Summary
-		}
+		content := e.articleBody(a.FeedID, a.Content, a.Summary)
 		emb, err := e.groupMatcher.EmbedArticle(ctx, a.Title, content)
 		if err != nil {
-			log.Printf("backfill embed article %d: %v", a.ID, err)
+			e.log.Warn("backfill embed failed", "article_id", a.ID, "err", err)
 			e.store.StoreArticleEmbedding(a.ID, sentinel, e.groupMatcher.Model()) //nolint:errcheck
 			continue
 		}
@@ -509,7 +1353,7 @@ func (e *Engine) BackfillEmbeddings(ctx context.Context, batchSize int) (int, er
 			continue
 		}
 		if err := e.store.StoreArticleEmbedding(a.ID, embedding.EncodeFloat32s(emb), e.groupMatcher.Model()); err != nil {
-			log.Printf("backfill store embedding %d: %v", a.ID, err)
+			e.log.Warn("backfill store embedding failed", "article_id", a.ID, "err", err)
 			continue
 		}
 		count++
@@ -522,6 +1366,19 @@ func (e *Engine) MarkArticleRead(userID, articleID int64) error {
 	return e.store.UpdateReadState(userID, articleID, true, nil, nil, nil)
 }
 
+// RecordArticleOpen counts one opening of the article's full view by the
+// user. The running total is reported by GetReadHistory as a popularity
+// signal.
+func (e *Engine) RecordArticleOpen(userID, articleID int64) error {
+	return e.store.RecordArticleOpen(userID, articleID)
+}
+
+// ToggleRead flips an article between read and unread for the user and
+// returns the new state. Scores are preserved.
+func (e *Engine) ToggleRead(userID, articleID int64) (bool, error) {
+	return e.store.ToggleRead(userID, articleID)
+}
+
 // MarkArticlesRead marks a list of articles as read.
 func (e *Engine) MarkArticlesRead(userID int64, articleIDs []int64) error {
 	for _, id := range articleIDs {
@@ -532,14 +1389,46 @@ func (e *Engine) MarkArticlesRead(userID int64, articleIDs []int64) error {
 	return nil
 }
 
-// ImportOPML imports feeds from an OPML file and subscribes the user.
-func (e *Engine) ImportOPML(path string, userID int64) error {
-	return e.fetcher.ImportOPML(path, userID)
+// ImportOPML imports feeds from an OPML file and subscribes the user. With
+// validate set, each new feed is fetched first and counted as failed unless
+// it parses.
+func (e *Engine) ImportOPML(path string, userID int64, validate bool) (*OPMLImportResult, error) {
+	return opmlResult(e.fetcher.ImportOPML(path, userID, feeds.OPMLImportOptions{Validate: validate}))
 }
 
 // ImportOPMLReader imports feeds from an OPML reader and subscribes the user.
-func (e *Engine) ImportOPMLReader(r io.Reader, userID int64) error {
-	return e.fetcher.ImportOPMLReader(r, userID)
+func (e *Engine) ImportOPMLReader(r io.Reader, userID int64, validate bool) (*OPMLImportResult, error) {
+	return opmlResult(e.fetcher.ImportOPMLReader(r, userID, feeds.OPMLImportOptions{Validate: validate}))
+}
+
+// ImportOPMLFromURL fetches a hosted OPML document and subscribes the user to
+// its feeds. Unreachable URLs and non-OPML responses return an error.
+func (e *Engine) ImportOPMLFromURL(ctx context.Context, url string, userID int64, validate bool) (*OPMLImportResult, error) {
+	return opmlResult(e.fetcher.ImportOPMLURL(ctx, url, userID, feeds.OPMLImportOptions{Validate: validate}))
+}
+
+// SyncOPML brings the user's subscriptions up to date with a master OPML file
+// by subscribing to the feeds they don't already have, compared by normalized
+// URL. Existing subscriptions keep their titles, folders and settings, and
+// feeds missing from the file are left alone, so syncing the same file again
+// is a no-op. unchanged counts the file's feeds that were already subscribed.
+// Feeds that could not be added are reported in err after the rest are synced.
+func (e *Engine) SyncOPML(path string, userID int64) (added, unchanged int, err error) {
+	r, err := e.fetcher.ImportOPML(path, userID, feeds.OPMLImportOptions{})
+	if r == nil {
+		return 0, 0, err
+	}
+	if err == nil && len(r.Failed) > 0 {
+		err = fmt.Errorf("%d feeds could not be added: %s", len(r.Failed), strings.Join(r.Failed, ", "))
+	}
+	return r.Added, r.Skipped, err
+}
+
+func opmlResult(r *feeds.OPMLImportResult, err error) (*OPMLImportResult, error) {
+	if r == nil {
+		return nil, err
+	}
+	return &OPMLImportResult{Added: r.Added, Skipped: r.Skipped, Failed: r.Failed}, err
 }
 
 // GetUserFeeds returns all feeds a user is subscribed to.
@@ -551,10 +1440,28 @@ func (e *Engine) GetUserFeeds(userID int64) ([]Feed, error) {
 	return feedsFromInternal(feeds), nil
 }
 
+// SearchUserFeeds returns the user's feeds whose title or URL contains q,
+// ignoring case. An empty q returns all subscribed feeds.
+func (e *Engine) SearchUserFeeds(userID int64, q string) ([]Feed, error) {
+	feeds, err := e.store.SearchUserFeeds(userID, q)
+	if err != nil {
+		return nil, err
+	}
+	return feedsFromInternal(feeds), nil
+}
+
 // SubscribeFeed adds a feed and subscribes the user to it.
 // Validates the URL by fetching the feed first; returns an error if the URL
 // is unreachable or not a valid RSS/Atom feed.
 func (e *Engine) SubscribeFeed(userID int64, url, title string) error {
+	// An equivalent URL (http vs https, www, trailing slash) reuses the feed
+	// already on file; it was validated when it was first added.
+	if existing, err := e.store.FindFeedByNormalizedURL(url); err != nil {
+		return fmt.Errorf("look up feed: %w", err)
+	} else if existing != nil {
+		return e.store.SubscribeUserToFeed(userID, existing.ID)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -564,6 +1471,9 @@ func (e *Engine) SubscribeFeed(userID int64, url, title string) error {
 	if err != nil {
 		return fmt.Errorf("validate feed: %w", err)
 	}
+	if result.ParseError != nil {
+		return fmt.Errorf("validate feed: %w", result.ParseError)
+	}
 
 	// Use the feed's own title if none provided
 	if title == "" && result.Feed.Title != "" {
@@ -573,29 +1483,38 @@ func (e *Engine) SubscribeFeed(userID int64, url, title string) error {
 		title = url
 	}
 
-	feedID, err := e.store.AddFeed(url, title, result.Feed.Description)
-	if err != nil {
-		return fmt.Errorf("add feed: %w", err)
-	}
+	// Add the feed, its initial articles and the subscription together, so
+	// a failure part-way leaves no half-subscribed feed behind.
+	var feedID int64
+	var stored int
+	err = e.store.WithTx(func(tx *storage.Tx) error {
+		feedID, err = tx.AddFeed(url, title, result.Feed.Description)
+		if err != nil {
+			return fmt.Errorf("add feed: %w", err)
+		}
 
-	// Store blog homepage URL from feed metadata
-	if result.Feed.Link != "" {
-		e.store.UpdateFeedSiteURL(feedID, result.Feed.Link)
-	}
+		// Store the initial articles we already fetched
+		if stored, err = e.fetcher.StoreArticlesTx(tx, feedID, result.Feed); err != nil {
+			return fmt.Errorf("store articles: %w", err)
+		}
 
-	// Store the initial articles we already fetched
-	if stored, err := e.fetcher.StoreArticles(feedID, result.Feed); err == nil && stored > 0 {
-		log.Printf("herald: stored %d initial articles from %s", stored, url)
-	}
+		// Persist cache headers for next conditional request
+		if err := tx.UpdateFeedCacheHeaders(feedID, result.ETag, result.LastModified, result.BodyHash); err != nil {
+			return fmt.Errorf("store cache headers: %w", err)
+		}
 
-	// Persist cache headers for next conditional request
-	if result.ETag != "" || result.LastModified != "" {
-		e.store.UpdateFeedCacheHeaders(feedID, result.ETag, result.LastModified)
+		if err := tx.MarkFeedFetched(feedID); err != nil {
+			return fmt.Errorf("mark feed fetched: %w", err)
+		}
+		return tx.SubscribeUserToFeed(userID, feedID)
+	})
+	if err != nil {
+		return err
 	}
-
-	e.store.MarkFeedFetched(feedID)
-
-	return e.store.SubscribeUserToFeed(userID, feedID)
+	if stored > 0 {
+		e.log.Info("stored initial articles", "event", "feed_subscribe", "feed_id", feedID, "url", url, "stored", stored)
+	}
+	return nil
 }
 
 // DiscoverFeeds fetches pageURL and returns any feeds found via standard
@@ -632,26 +1551,43 @@ type opmlBody struct {
 }
 
 type opmlOutline struct {
-	Text   string `xml:"text,attr"`
-	Title  string `xml:"title,attr"`
-	Type   string `xml:"type,attr"`
-	XMLURL string `xml:"xmlUrl,attr"`
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
 }
 
-func marshalOPML(title string, feeds []storage.Feed) ([]byte, error) {
-	outlines := make([]opmlOutline, len(feeds))
-	for i, f := range feeds {
-		outlines[i] = opmlOutline{
-			Text:   f.Title,
-			Title:  f.Title,
-			Type:   "rss",
-			XMLURL: f.URL,
+// marshalOPML renders feeds keyed by folder path as nested folder outlines.
+// Unfiled feeds (the empty key) come first, then folders in name order.
+func marshalOPML(title string, byFolder map[string][]storage.Feed) ([]byte, error) {
+	paths := make([]string, 0, len(byFolder))
+	for path := range byFolder {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var root opmlOutline
+	for _, path := range paths {
+		node := &root
+		if path != "" {
+			for _, name := range strings.Split(path, "/") {
+				node = opmlFolder(node, name)
+			}
+		}
+		for _, f := range byFolder[path] {
+			node.Outlines = append(node.Outlines, opmlOutline{
+				Text:   f.Title,
+				Title:  f.Title,
+				Type:   "rss",
+				XMLURL: f.URL,
+			})
 		}
 	}
 	doc := opmlExport{
 		Version: "2.0",
 		Head:    opmlHead{Title: title, DateCreated: time.Now().UTC().Format(time.RFC1123)},
-		Body:    opmlBody{Outlines: outlines},
+		Body:    opmlBody{Outlines: root.Outlines},
 	}
 	data, err := xml.MarshalIndent(doc, "", "  ")
 	if err != nil {
@@ -660,14 +1596,26 @@ func marshalOPML(title string, feeds []storage.Feed) ([]byte, error) {
 	return append([]byte(xml.Header), data...), nil
 }
 
+// opmlFolder returns the folder outline called name under parent, adding it
+// if it doesn't exist yet.
+func opmlFolder(parent *opmlOutline, name string) *opmlOutline {
+	for i := range parent.Outlines {
+		if o := &parent.Outlines[i]; o.XMLURL == "" && o.Text == name {
+			return o
+		}
+	}
+	parent.Outlines = append(parent.Outlines, opmlOutline{Text: name, Title: name})
+	return &parent.Outlines[len(parent.Outlines)-1]
+}
+
 // ExportOPML returns an OPML 2.0 document containing all feeds the given user
-// is subscribed to.
+// is subscribed to, nested under their folders.
 func (e *Engine) ExportOPML(userID int64) ([]byte, error) {
-	feeds, err := e.store.GetUserFeeds(userID)
+	byFolder, err := e.store.GetFeedsByFolder(userID)
 	if err != nil {
 		return nil, fmt.Errorf("get user feeds: %w", err)
 	}
-	return marshalOPML("Herald Subscriptions", feeds)
+	return marshalOPML("Herald Subscriptions", byFolder)
 }
 
 // ExportAllFeedsOPML returns an OPML 2.0 document containing every feed
@@ -677,24 +1625,72 @@ func (e *Engine) ExportAllFeedsOPML() ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("get all feeds: %w", err)
 	}
-	return marshalOPML("Herald - All Subscriptions", feeds)
+	return marshalOPML("Herald - All Subscriptions", map[string][]storage.Feed{"": feeds})
 }
 
-// UnsubscribeFeed removes a user's subscription to a feed. If no subscribers
-// remain, the feed and its articles are deleted asynchronously so the caller
-// returns immediately regardless of how many articles need to be cleaned up.
+// UnsubscribeGrace is how long an unsubscribed feed with no remaining
+// subscribers is kept before it and its articles are deleted. Resubscribing
+// within the window restores it untouched.
+const UnsubscribeGrace = 24 * time.Hour
+
+// UnsubscribeFeed removes a user's subscription to a feed, which the user can
+// undo with ResubscribeFeed within UnsubscribeGrace. If no subscribers
+// remain, the feed is scheduled for deletion after the same grace period; the
+// next FetchAllFeeds after the deadline removes it and its articles.
 func (e *Engine) UnsubscribeFeed(userID, feedID int64) error {
+	deadline := time.Now().Add(UnsubscribeGrace)
+	if err := e.store.RecordUnsubscribe(userID, feedID, deadline); err != nil {
+		return fmt.Errorf("unsubscribe: %w", err)
+	}
 	if err := e.store.UnsubscribeUserFromFeed(userID, feedID); err != nil {
 		return fmt.Errorf("unsubscribe: %w", err)
 	}
-	go func() {
+	if scheduled, err := e.store.ScheduleFeedDeletion(feedID, deadline); err != nil {
+		e.log.Warn("schedule orphaned feed deletion failed", "feed_id", feedID, "err", err)
+	} else if scheduled {
+		e.log.Info("scheduled orphaned feed deletion", "event", "feed_delete_scheduled", "feed_id", feedID, "delete_after", deadline)
+	}
+	return nil
+}
+
+// ResubscribeFeed undoes UnsubscribeFeed: it re-adds the user's subscription
+// and cancels any pending deletion. Only the user who unsubscribed can undo
+// it, and only once, within UnsubscribeGrace.
+func (e *Engine) ResubscribeFeed(userID, feedID int64) error {
+	exists, err := e.store.FeedExists(feedID)
+	if err != nil {
+		return fmt.Errorf("resubscribe: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("feed %d no longer exists", feedID)
+	}
+	ok, err := e.store.TakeUnsubscribe(userID, feedID, time.Now())
+	if err != nil {
+		return fmt.Errorf("resubscribe: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("no unsubscribe from feed %d to undo", feedID)
+	}
+	if err := e.store.SubscribeUserToFeed(userID, feedID); err != nil {
+		return fmt.Errorf("resubscribe: %w", err)
+	}
+	return nil
+}
+
+// deleteExpiredFeeds removes unsubscribed feeds whose grace period has ended.
+func (e *Engine) deleteExpiredFeeds() {
+	ids, err := e.store.GetFeedsDueForDeletion(time.Now())
+	if err != nil {
+		e.log.Warn("list feeds due for deletion failed", "err", err)
+		return
+	}
+	for _, feedID := range ids {
 		if deleted, err := e.store.DeleteFeedIfOrphaned(feedID); err != nil {
-			log.Printf("herald: cleanup orphaned feed %d: %v", feedID, err)
+			e.log.Warn("cleanup orphaned feed failed", "feed_id", feedID, "err", err)
 		} else if deleted {
-			log.Printf("herald: deleted orphaned feed %d", feedID)
+			e.log.Info("deleted orphaned feed", "event", "feed_delete", "feed_id", feedID)
 		}
-	}()
-	return nil
+	}
 }
 
 // RenameFeed updates the display title of a feed.
@@ -702,17 +1698,154 @@ func (e *Engine) RenameFeed(feedID int64, title string) error {
 	return e.store.RenameFeed(feedID, title)
 }
 
+// SetFeedDedupeByURL switches a feed between GUID and URL deduplication.
+// URL mode is for feeds that regenerate GUIDs on every publish; the fetcher
+// also enables it automatically when it detects persistent GUID churn.
+// userID must subscribe to the feed.
+func (e *Engine) SetFeedDedupeByURL(userID, feedID int64, enabled bool) error {
+	if err := e.subscribed(userID, feedID); err != nil {
+		return err
+	}
+	return e.store.SetFeedDedupeByURL(feedID, enabled)
+}
+
+// SetFeedContentSource chooses which article field a feed's text comes from
+// for AI processing and display: "content" (the default), "summary" for
+// feeds that put the real text in the summary, or "longest". userID must
+// subscribe to the feed.
+func (e *Engine) SetFeedContentSource(userID, feedID int64, source string) error {
+	if err := e.subscribed(userID, feedID); err != nil {
+		return err
+	}
+	return e.store.SetFeedContentSource(feedID, source)
+}
+
+// GetFeedStripPatterns returns the regexps removed from a feed's text
+// before it reaches the AI.
+func (e *Engine) GetFeedStripPatterns(feedID int64) ([]string, error) {
+	return e.store.GetFeedStripPatterns(feedID)
+}
+
+// SetFeedStripPatterns replaces the regexps removed from a feed's text
+// before summarization and curation, such as "This article first appeared
+// on ..." footers. The security check and display still see the full text.
+// userID must subscribe to the feed. An empty list clears them.
+func (e *Engine) SetFeedStripPatterns(userID, feedID int64, patterns []string) error {
+	if err := e.subscribed(userID, feedID); err != nil {
+		return err
+	}
+	return e.store.SetFeedStripPatterns(feedID, patterns)
+}
+
+// ArticleBody returns the text to show for a, picked from its content and
+// summary per the feed's content source.
+func (e *Engine) ArticleBody(a *Article) string {
+	return e.articleBody(a.FeedID, a.Content, a.Summary)
+}
+
+// articleBody picks between content and summary per the content source of
+// feedID, treating a lookup failure as the default source.
+func (e *Engine) articleBody(feedID int64, content, summary string) string {
+	source, err := e.store.GetFeedContentSource(feedID)
+	if err != nil {
+		e.log.Warn("get feed content source failed", "feed_id", feedID, "err", err)
+		source = storage.ContentSourceContent
+	}
+	return storage.ArticleBody(source, content, summary)
+}
+
+// SetFeedMaxArticles caps how many unstarred articles a feed keeps; older
+// ones are pruned after each fetch. 0 means unlimited. userID must subscribe
+// to the feed.
+func (e *Engine) SetFeedMaxArticles(userID, feedID int64, n int) error {
+	if err := e.subscribed(userID, feedID); err != nil {
+		return err
+	}
+	return e.store.SetFeedMaxArticles(feedID, n)
+}
+
+// GetFeedFetchHeaders returns the extra HTTP headers sent when fetching a
+// feed. The values are typically credentials; callers must not log them.
+// Only a feed's sole subscriber may read them.
+func (e *Engine) GetFeedFetchHeaders(userID, feedID int64) (map[string]string, error) {
+	if err := e.soleSubscriber(userID, feedID); err != nil {
+		return nil, err
+	}
+	return e.store.GetFeedFetchHeaders(feedID)
+}
+
+// SetFeedFetchHeaders replaces the extra HTTP headers sent when fetching a
+// feed, such as Authorization or Cookie for a private feed. They're applied
+// on top of the conditional-GET headers; an empty map clears them. Headers
+// are feed-wide, so only a feed's sole subscriber may set them, and other
+// users can't subscribe to the feed while they're set.
+func (e *Engine) SetFeedFetchHeaders(userID, feedID int64, headers map[string]string) error {
+	if err := e.soleSubscriber(userID, feedID); err != nil {
+		return err
+	}
+	return e.store.SetFeedFetchHeaders(feedID, headers)
+}
+
+// subscribed verifies userID subscribes to feedID.
+func (e *Engine) subscribed(userID, feedID int64) error {
+	ok, err := e.store.IsSubscribed(userID, feedID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("feed %d not found or not in the user's feeds", feedID)
+	}
+	return nil
+}
+
+// soleSubscriber verifies userID is the only user subscribed to feedID.
+func (e *Engine) soleSubscriber(userID, feedID int64) error {
+	if err := e.subscribed(userID, feedID); err != nil {
+		return err
+	}
+	subscribers, err := e.store.GetFeedSubscribers(feedID)
+	if err != nil {
+		return err
+	}
+	if len(subscribers) > 1 {
+		return fmt.Errorf("feed %d is shared with other users; fetch headers can only be used on a feed you alone subscribe to", feedID)
+	}
+	return nil
+}
+
 // RenameUserFeed sets a per-user display title for a feed subscription.
 func (e *Engine) RenameUserFeed(userID, feedID int64, title string) error {
 	return e.store.RenameUserFeed(userID, feedID, title)
 }
 
+// SetFeedMuted mutes or unmutes a subscription. Muted feeds keep fetching and
+// stay searchable and browsable by feed, but drop out of the unread stream.
+func (e *Engine) SetFeedMuted(userID, feedID int64, muted bool) error {
+	return e.store.SetFeedMuted(userID, feedID, muted)
+}
+
+// SetFeedFolder files a subscription under a "/"-separated folder path. Empty
+// segments are dropped; an empty path leaves the feed unfiled.
+func (e *Engine) SetFeedFolder(userID, feedID int64, folder string) error {
+	var parts []string
+	for _, p := range strings.Split(folder, "/") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return e.store.SetFeedFolder(userID, feedID, strings.Join(parts, "/"))
+}
+
 // GetUserGroups returns all article groups for a user.
 func (e *Engine) GetUserGroups(userID int64) ([]ArticleGroup, error) {
 	groups, err := e.store.GetUserGroups(userID)
 	if err != nil {
 		return nil, err
 	}
+	unread, err := e.store.GetGroupUnreadCounts(userID)
+	if err != nil {
+		return nil, err
+	}
 	var result []ArticleGroup
 	for _, g := range groups {
 		ag := ArticleGroup{
@@ -723,6 +1856,7 @@ func (e *Engine) GetUserGroups(userID int64) ([]ArticleGroup, error) {
 			Muted:       g.Muted,
 			CreatedAt:   g.CreatedAt,
 			UpdatedAt:   g.UpdatedAt,
+			Unread:      unread[g.ID],
 		}
 		// Attach summary if available
 		if gs, err := e.store.GetGroupSummary(g.ID); err == nil && gs != nil {
@@ -802,9 +1936,14 @@ func (e *Engine) GetGroupStats(userID int64) ([]GroupStats, error) {
 	return result, nil
 }
 
-// MarkGroupRead marks all articles in a group as read.
-func (e *Engine) MarkGroupRead(userID, groupID int64, before int64) error {
-	return e.store.MarkGroupArticlesRead(userID, groupID, before)
+// MarkGroupRead marks every unread article in one of the user's groups as
+// read. Scores are left intact so the articles are not re-processed.
+// Returns the number of articles marked.
+func (e *Engine) MarkGroupRead(userID, groupID int64) (int, error) {
+	if _, err := e.ownedGroup(userID, groupID); err != nil {
+		return 0, err
+	}
+	return e.store.MarkGroupArticlesRead(userID, groupID, 0)
 }
 
 // MuteGroup mutes a group (hides from sidebar) and marks all its articles as read.
@@ -812,20 +1951,224 @@ func (e *Engine) MuteGroup(userID, groupID int64) error {
 	if err := e.store.SetGroupMuted(groupID, true); err != nil {
 		return err
 	}
-	return e.store.MarkGroupArticlesRead(userID, groupID, 0)
-}
-
-// DisbandGroup deletes a group; its articles return to their feeds.
-func (e *Engine) DisbandGroup(userID, groupID int64) error {
-	// Verify the group belongs to this user
-	group, err := e.store.GetGroup(groupID)
-	if err != nil {
-		return fmt.Errorf("get group: %w", err)
+	_, err := e.store.MarkGroupArticlesRead(userID, groupID, 0)
+	return err
+}
+
+// ownedGroup loads a group and verifies it belongs to userID.
+func (e *Engine) ownedGroup(userID, groupID int64) (*storage.ArticleGroup, error) {
+	group, err := e.store.GetGroup(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("get group: %w", err)
+	}
+	if group == nil || group.UserID != userID {
+		return nil, fmt.Errorf("group %d not found or not owned by user", groupID)
+	}
+	return group, nil
+}
+
+// ownedArticle loads an article and verifies userID subscribes to its feed.
+func (e *Engine) ownedArticle(userID, articleID int64) (*storage.Article, error) {
+	article, err := e.store.GetArticle(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("get article: %w", err)
+	}
+	ok, err := e.store.IsSubscribed(userID, article.FeedID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("article %d not found or not in the user's feeds", articleID)
+	}
+	return article, nil
+}
+
+// DisbandGroup deletes a group; its articles return to their feeds.
+func (e *Engine) DisbandGroup(userID, groupID int64) error {
+	if _, err := e.ownedGroup(userID, groupID); err != nil {
+		return err
+	}
+	return e.store.DisbandGroup(groupID)
+}
+
+// RenameGroup replaces a group's topic label with a user-chosen name.
+func (e *Engine) RenameGroup(userID, groupID int64, topic string) error {
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return fmt.Errorf("group name must not be empty")
+	}
+	if _, err := e.ownedGroup(userID, groupID); err != nil {
+		return err
+	}
+	return e.store.RenameGroup(groupID, topic)
+}
+
+// MergeGroups folds srcID into dstID: members are moved (duplicates dropped),
+// srcID is deleted, and the destination summary is regenerated.
+func (e *Engine) MergeGroups(ctx context.Context, userID, srcID, dstID int64) error {
+	if srcID == dstID {
+		return fmt.Errorf("cannot merge a group into itself")
+	}
+	if _, err := e.ownedGroup(userID, srcID); err != nil {
+		return err
+	}
+	if _, err := e.ownedGroup(userID, dstID); err != nil {
+		return err
+	}
+	if err := e.store.MergeGroups(srcID, dstID); err != nil {
+		return err
+	}
+	if err := e.regenerateGroupSummary(ctx, userID, dstID); err != nil {
+		e.log.Warn("regenerate group summary failed", "group_id", dstID, "err", err)
+	}
+	return nil
+}
+
+// ArticleGroupID returns the ID of the user's group containing the article,
+// or 0 if the article is ungrouped.
+func (e *Engine) ArticleGroupID(userID, articleID int64) (int64, error) {
+	groupID, err := e.store.FindArticleGroup(articleID, userID)
+	if err != nil || groupID == nil {
+		return 0, err
+	}
+	return *groupID, nil
+}
+
+// MoveArticleToGroup files an article under groupID, removing it from any
+// group it currently belongs to. A groupID of 0 creates a new group named
+// after the article. Source groups left empty are disbanded; the summaries
+// of the remaining affected groups are regenerated. Returns the target group ID.
+func (e *Engine) MoveArticleToGroup(ctx context.Context, userID, articleID, groupID int64) (int64, error) {
+	article, err := e.ownedArticle(userID, articleID)
+	if err != nil {
+		return 0, err
+	}
+	if groupID != 0 {
+		if _, err := e.ownedGroup(userID, groupID); err != nil {
+			return 0, err
+		}
+	}
+
+	// An article should only sit in one group per user, but drain defensively
+	// in case older clustering runs left duplicates behind.
+	var sources []int64
+	for {
+		current, err := e.store.FindArticleGroup(articleID, userID)
+		if err != nil {
+			return 0, err
+		}
+		if current == nil {
+			break
+		}
+		if err := e.store.RemoveArticleFromGroup(*current, articleID); err != nil {
+			return 0, err
+		}
+		if *current != groupID {
+			sources = append(sources, *current)
+		}
+	}
+
+	if groupID == 0 {
+		groupID, err = e.store.CreateArticleGroup(userID, article.Title)
+		if err != nil {
+			return 0, fmt.Errorf("create group: %w", err)
+		}
+	}
+	if err := e.store.AddArticleToGroup(groupID, articleID); err != nil {
+		return 0, err
+	}
+
+	for _, src := range sources {
+		if n, err := e.store.GetGroupArticleCount(src); err == nil && n == 0 {
+			e.store.DisbandGroup(src) //nolint:errcheck
+			continue
+		}
+		if err := e.regenerateGroupSummary(ctx, userID, src); err != nil {
+			e.log.Warn("regenerate group summary failed", "group_id", src, "err", err)
+		}
+	}
+	if err := e.regenerateGroupSummary(ctx, userID, groupID); err != nil {
+		e.log.Warn("regenerate group summary failed", "group_id", groupID, "err", err)
+	}
+	return groupID, nil
+}
+
+// RegenerateGroupSummary rebuilds a group's headline and summary on demand,
+// e.g. after articles were merged into or moved out of it. The group must
+// belong to userID.
+func (e *Engine) RegenerateGroupSummary(ctx context.Context, userID, groupID int64) error {
+	if e.readOnly {
+		return ErrReadOnly
+	}
+	if e.ai == nil {
+		return fmt.Errorf("AI processing is not configured")
+	}
+	if _, err := e.ownedGroup(userID, groupID); err != nil {
+		return err
+	}
+	return e.regenerateGroupSummary(ctx, userID, groupID)
+}
+
+// regenerateGroupSummary rebuilds a group's summary after its membership
+// changes, weighting articles by their stored interest scores. Without an AI
+// processor the existing headline and summary are kept and only the article
+// count and max score are refreshed.
+func (e *Engine) regenerateGroupSummary(ctx context.Context, userID, groupID int64) error {
+	group, err := e.store.GetGroup(groupID)
+	if err != nil || group == nil {
+		return err
+	}
+	articles, err := e.store.GetGroupArticles(groupID)
+	if err != nil {
+		return fmt.Errorf("get group articles: %w", err)
+	}
+	if len(articles) == 0 {
+		return nil
+	}
+	prev, _ := e.store.GetGroupSummary(groupID)
+
+	ids := make([]int64, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	scores, err := e.store.GetInterestScores(userID, ids)
+	if err != nil {
+		return err
+	}
+	var maxScore *float64
+	for _, s := range scores {
+		if maxScore == nil || s > *maxScore {
+			maxScore = &s
+		}
+	}
+
+	if e.ai == nil {
+		if prev == nil {
+			return nil
+		}
+		return e.store.UpdateGroupSummary(groupID, prev.Headline, prev.Summary, len(articles), maxScore)
+	}
+
+	var inputs []ai.GroupSummaryInput
+	for _, a := range articles {
+		summary, err := e.store.GetArticleSummary(userID, a.ID)
+		if err != nil || summary == nil {
+			continue
+		}
+		score, ok := scores[a.ID]
+		if !ok {
+			score = 5.0 // unscored: neutral weight
+		}
+		inputs = append(inputs, ai.GroupSummaryInput{Title: a.Title, AISummary: summary.AISummary, Score: score})
+	}
+	if len(inputs) == 0 {
+		return nil
 	}
-	if group == nil || group.UserID != userID {
-		return fmt.Errorf("group not found or not owned by user")
+	result, err := e.ai.GenerateGroupSummary(ctx, userID, group.Topic, inputs)
+	if err != nil {
+		return err
 	}
-	return e.store.DisbandGroup(groupID)
+	return e.store.UpdateGroupSummary(groupID, result.Headline, result.Summary, len(articles), maxScore)
 }
 
 // --- Newsletter methods ---
@@ -1029,13 +2372,14 @@ func (e *Engine) SendNewsletterIssue(issueID int64) error {
 		return fmt.Errorf("no email recipient configured")
 	}
 
+	email := e.cfg().Email
 	sender := &emailpkg.Sender{
-		Host:     e.config.Email.SMTPHost,
-		Port:     e.config.Email.SMTPPort,
-		Username: e.config.Email.Username,
-		Password: e.config.Email.Password,
-		From:     e.config.Email.FromAddress,
-		FromName: e.config.Email.FromName,
+		Host:     email.SMTPHost,
+		Port:     email.SMTPPort,
+		Username: email.Username,
+		Password: email.Password,
+		From:     email.FromAddress,
+		FromName: email.FromName,
 	}
 
 	subject := nl.Name + ": " + issue.Headline
@@ -1051,22 +2395,22 @@ func (e *Engine) ProcessDueNewsletters(ctx context.Context) error {
 	for _, schedule := range []string{"hourly", "daily"} {
 		newsletters, err := e.store.GetDueNewsletters(schedule)
 		if err != nil {
-			log.Printf("get due %s newsletters: %v", schedule, err)
+			e.log.Warn("get due newsletters failed", "schedule", schedule, "err", err)
 			continue
 		}
 		for _, nl := range newsletters {
 			if ctx.Err() != nil {
 				return ctx.Err()
 			}
-			log.Printf("generating %s newsletter %q (id=%d)", schedule, nl.Name, nl.ID)
+			e.log.Info("generating newsletter", "event", "newsletter_generate", "schedule", schedule, "newsletter_id", nl.ID, "name", nl.Name)
 			issue, err := e.GenerateNewsletterIssue(ctx, nl.UserID, nl.ID)
 			if err != nil {
-				log.Printf("newsletter %d generation failed: %v", nl.ID, err)
+				e.log.Warn("newsletter generation failed", "newsletter_id", nl.ID, "err", err)
 				continue
 			}
-			if nl.EmailRecipient != "" && e.config.Email.SMTPHost != "" {
+			if nl.EmailRecipient != "" && e.cfg().Email.SMTPHost != "" {
 				if err := e.SendNewsletterIssue(issue.ID); err != nil {
-					log.Printf("newsletter %d email failed: %v", nl.ID, err)
+					e.log.Warn("newsletter email failed", "newsletter_id", nl.ID, "err", err)
 				}
 			}
 		}
@@ -1075,38 +2419,233 @@ func (e *Engine) ProcessDueNewsletters(ctx context.Context) error {
 }
 
 // GenerateBriefing creates a text briefing from high-interest unread articles.
+// It drains the user's notification queue: queued articles lead the briefing,
+// followed by any other unread articles at or above the user's
+// notify_min_score not already listed. The queue is cleared only once the
+// briefing has been built, so a failure leaves it for the next attempt.
 func (e *Engine) GenerateBriefing(userID int64) (string, error) {
 	if e.ai == nil {
 		return "", nil
 	}
-	articles, scores, err := e.store.GetArticlesByInterestScore(
-		userID, e.config.Thresholds.InterestScore, 20, 0, nil)
+	prefs, err := e.GetPreferences(userID)
+	if err != nil {
+		return "", fmt.Errorf("get preferences: %w", err)
+	}
+	pending, err := e.store.GetQueuedNotifications(userID)
+	if err != nil {
+		return "", fmt.Errorf("get notification queue: %w", err)
+	}
+	queued := e.queuedNotifications(pending)
+	articles, scores, _, err := e.store.GetArticlesByInterestScore(
+		userID, prefs.NotifyMinScore, 20, 0, nil)
 	if err != nil {
 		return "", fmt.Errorf("get high-interest articles: %w", err)
 	}
-	if len(articles) == 0 {
+	if len(articles) == 0 && len(queued) == 0 {
 		return "", nil
 	}
 
-	var briefing strings.Builder
+	var data ai.BriefingData
+	seen := make(map[int64]bool, len(queued))
+	for _, n := range queued {
+		seen[n.ID] = true
+		data.Articles = append(data.Articles, e.briefingEntry(userID, n.ID, n.Title, n.URL, n.Summary, n.InterestScore, true))
+	}
 	for i, article := range articles {
+		if seen[article.ID] {
+			continue
+		}
 		score := 0.0
 		if i < len(scores) {
 			score = scores[i]
 		}
+		data.Articles = append(data.Articles, e.briefingEntry(userID, article.ID, article.Title, article.URL, article.Summary, score, false))
+	}
+	data.Count = len(data.Articles)
+
+	tmpl, err := ai.NewPromptLoader(e.store, e.cfg()).GetPrompt(userID, ai.PromptTypeBriefing)
+	if err != nil {
+		return "", fmt.Errorf("load briefing template: %w", err)
+	}
+	briefing, err := ai.ExecutePrompt(tmpl, data)
+	if err != nil {
+		return "", fmt.Errorf("render briefing: %w", err)
+	}
+	if len(pending) > 0 {
+		if err := e.store.DeleteQueuedNotifications(userID, pending[len(pending)-1].ID); err != nil {
+			return "", fmt.Errorf("drain notification queue: %w", err)
+		}
+	}
+	return briefing, nil
+}
+
+// GenerateCombinedBriefing renders one briefing for several users, such as
+// a household's shared display. Each user's unread articles at or above
+// their notify_min_score are merged, deduplicated by URL, and attributed to
+// the users who flagged them, highest score first. Unlike GenerateBriefing
+// it leaves the users' notification queues alone, and it renders with the
+// global briefing template rather than any one user's.
+func (e *Engine) GenerateCombinedBriefing(ctx context.Context, userIDs []int64) (string, error) {
+	if e.ai == nil || len(userIDs) == 0 {
+		return "", nil
+	}
+	users, err := e.store.ListUsers()
+	if err != nil {
+		return "", fmt.Errorf("list users: %w", err)
+	}
+	names := make(map[int64]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+
+	var data ai.BriefingData
+	byURL := make(map[string]int) // dedupe key -> index in data.Articles
+	for _, userID := range slices.Compact(slices.Sorted(slices.Values(userIDs))) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		prefs, err := e.GetPreferences(userID)
+		if err != nil {
+			return "", fmt.Errorf("get preferences for user %d: %w", userID, err)
+		}
+		articles, scores, _, err := e.store.GetArticlesByInterestScore(
+			userID, prefs.NotifyMinScore, 20, 0, nil)
+		if err != nil {
+			return "", fmt.Errorf("get high-interest articles for user %d: %w", userID, err)
+		}
+		name := names[userID]
+		if name == "" {
+			name = fmt.Sprintf("user %d", userID)
+		}
+		for i, article := range articles {
+			score := 0.0
+			if i < len(scores) {
+				score = scores[i]
+			}
+			key := article.URL
+			if key == "" {
+				key = fmt.Sprintf("id:%d", article.ID)
+			}
+			if j, ok := byURL[key]; ok {
+				entry := &data.Articles[j]
+				entry.FlaggedBy = append(entry.FlaggedBy, name)
+				entry.Score = max(entry.Score, score)
+				continue
+			}
+			entry := e.briefingEntry(userID, article.ID, article.Title, article.URL, article.Summary, score, false)
+			entry.FlaggedBy = []string{name}
+			byURL[key] = len(data.Articles)
+			data.Articles = append(data.Articles, entry)
+		}
+	}
+	if len(data.Articles) == 0 {
+		return "", nil
+	}
+	slices.SortStableFunc(data.Articles, func(a, b ai.BriefingArticle) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	data.Count = len(data.Articles)
+
+	tmpl, err := ai.NewPromptLoader(e.store, e.cfg()).GetPrompt(0, ai.PromptTypeBriefing)
+	if err != nil {
+		return "", fmt.Errorf("load briefing template: %w", err)
+	}
+	briefing, err := ai.ExecutePrompt(tmpl, data)
+	if err != nil {
+		return "", fmt.Errorf("render briefing: %w", err)
+	}
+	return briefing, nil
+}
+
+// briefingEntry builds one briefing article, preferring the user's AI
+// summary over the feed-provided one.
+func (e *Engine) briefingEntry(userID, articleID int64, title, url, feedSummary string, score float64, queued bool) ai.BriefingArticle {
+	entry := ai.BriefingArticle{ID: articleID, Title: title, URL: url, Summary: feedSummary, Score: score, Queued: queued}
+	if summary, err := e.store.GetArticleSummary(userID, articleID); err == nil && summary != nil {
+		entry.Summary = summary.AISummary
+	}
+	return entry
+}
+
+// RouteNotifications applies a user's notify_when preference to freshly
+// scored articles. Safe articles at or above notify_min_score are returned
+// for immediate delivery under "always" and held in the notification queue
+// under "queue". Under "present" nothing is returned; PresentNotifications
+// surfaces them the next time the user checks in.
+func (e *Engine) RouteNotifications(userID int64, scored []ScoredArticle) ([]ScoredArticle, error) {
+	prefs, err := e.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	var notable []ScoredArticle
+	for _, s := range scored {
+		if s.Safe && s.InterestScore >= prefs.NotifyMinScore {
+			notable = append(notable, s)
+		}
+	}
 
-		fmt.Fprintf(&briefing, "## %s (%.1f/10)\n", article.Title, score)
-		fmt.Fprintf(&briefing, "%s\n", article.URL)
+	switch prefs.NotifyWhen {
+	case "always":
+		return notable, nil
+	case "queue":
+		for _, s := range notable {
+			if err := e.store.EnqueueNotification(userID, s.ID, s.InterestScore); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, nil
+}
 
-		if summary, err := e.store.GetArticleSummary(userID, article.ID); err == nil && summary != nil {
-			fmt.Fprintf(&briefing, "%s\n", summary.AISummary)
-		} else if article.Summary != "" {
-			fmt.Fprintf(&briefing, "%s\n", article.Summary)
+// queuedNotifications resolves stored queue entries to their articles,
+// skipping any that can no longer be loaded.
+func (e *Engine) queuedNotifications(queued []storage.QueuedNotification) []QueuedNotification {
+	out := make([]QueuedNotification, 0, len(queued))
+	for _, n := range queued {
+		a, err := e.store.GetArticle(n.ArticleID)
+		if err != nil {
+			e.log.Warn("queued article unavailable", "article_id", n.ArticleID, "err", err)
+			continue
 		}
-		briefing.WriteString("\n")
+		out = append(out, QueuedNotification{
+			Article:       articleFromInternal(*a),
+			InterestScore: n.InterestScore,
+			QueuedAt:      n.QueuedAt,
+		})
 	}
+	return out
+}
 
-	return briefing.String(), nil
+// PresentNotifications surfaces high-interest articles for a user whose
+// notify_when preference is "present": unread, safe articles at or above
+// notify_min_score that haven't been presented yet, highest score first, up
+// to limit. Each article is marked presented and won't be returned again.
+// For other notify_when modes it returns nil.
+func (e *Engine) PresentNotifications(userID int64, limit int) ([]PresentedArticle, error) {
+	prefs, err := e.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs.NotifyWhen != "present" {
+		return nil, nil
+	}
+	claimed, err := e.store.PresentArticles(userID, prefs.NotifyMinScore, e.cfg().Thresholds.SecurityScore, limit, e.resolveFilterThreshold(userID))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PresentedArticle, 0, len(claimed))
+	for _, p := range claimed {
+		a, err := e.store.GetArticle(p.ArticleID)
+		if err != nil {
+			e.log.Warn("presented article unavailable", "article_id", p.ArticleID, "err", err)
+			continue
+		}
+		out = append(out, PresentedArticle{
+			Article:       articleFromInternal(*a),
+			InterestScore: p.InterestScore,
+		})
+	}
+	return out, nil
 }
 
 // GetFeedStats returns per-feed article counts and an aggregate total for a user.
@@ -1126,14 +2665,43 @@ func (e *Engine) GetFeedStats(userID int64) (*FeedStatsResult, error) {
 			UnreadArticles:       fs.UnreadArticles,
 			UnsummarizedArticles: fs.UnsummarizedArticles,
 			LastPostDate:         fs.LastPostDate,
+			Muted:                fs.Muted,
+			Folder:               fs.Folder,
 		}
 		result.Total.TotalArticles += fs.TotalArticles
-		result.Total.UnreadArticles += fs.UnreadArticles
+		if !fs.Muted {
+			result.Total.UnreadArticles += fs.UnreadArticles
+		}
 		result.Total.UnsummarizedArticles += fs.UnsummarizedArticles
 	}
 	return result, nil
 }
 
+// GetReadingStats returns what the user has read since the given time. The
+// daily histogram has an entry for every UTC day in the range, zeros included.
+func (e *Engine) GetReadingStats(userID int64, since time.Time) (*ReadingStats, error) {
+	internal, err := e.store.GetReadingStats(userID, since)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(internal.Daily))
+	for _, d := range internal.Daily {
+		counts[d.Day] = d.Count
+	}
+	result := &ReadingStats{
+		Since:        since,
+		Read:         internal.Read,
+		Starred:      internal.Starred,
+		HighInterest: internal.HighInterest,
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for day := since.UTC().Truncate(24 * time.Hour); !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		result.Daily = append(result.Daily, DailyRead{Date: date, Count: counts[date]})
+	}
+	return result, nil
+}
+
 // GetScoreStats returns AI scoring breakdown per feed for a user.
 func (e *Engine) GetScoreStats(userID int64) (*ScoreStatsResult, error) {
 	internal, err := e.store.GetScoreStats(userID)
@@ -1168,6 +2736,40 @@ func (e *Engine) GetScoreStats(userID int64) (*ScoreStatsResult, error) {
 	return result, nil
 }
 
+// RecordPollRun adds a finished poll cycle to the poll history.
+func (e *Engine) RecordPollRun(startedAt time.Time, duration time.Duration, result *FetchResult) error {
+	return e.store.RecordPollRun(storage.PollRun{
+		StartedAt:       startedAt,
+		DurationMS:      duration.Milliseconds(),
+		FeedsTotal:      result.FeedsTotal,
+		FeedsDownloaded: result.FeedsDownloaded,
+		NewArticles:     result.NewArticles,
+		Processed:       result.ProcessedCount,
+		Errored:         result.FeedsErrored,
+	})
+}
+
+// GetPollRuns returns up to limit recorded poll cycles, most recent first.
+func (e *Engine) GetPollRuns(limit int) ([]PollRun, error) {
+	runs, err := e.store.GetPollRuns(limit)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PollRun, len(runs))
+	for i, r := range runs {
+		out[i] = PollRun{
+			StartedAt:       r.StartedAt,
+			DurationMS:      r.DurationMS,
+			FeedsTotal:      r.FeedsTotal,
+			FeedsDownloaded: r.FeedsDownloaded,
+			NewArticles:     r.NewArticles,
+			Processed:       r.Processed,
+			Errored:         r.Errored,
+		}
+	}
+	return out, nil
+}
+
 // PendingCounts returns the number of articles awaiting AI processing.
 func (e *Engine) PendingCounts(userID int64) (unsummarized, unscored int, err error) {
 	unsummarized, err = e.store.GetUnsummarizedArticleCount(userID)
@@ -1188,6 +2790,7 @@ var allowedPromptTypes = map[string]bool{
 	"summarization":  true,
 	"group_summary":  true,
 	"related_groups": true,
+	"briefing":       true,
 }
 
 // GetUserPreference returns a single raw preference value for a user.
@@ -1205,30 +2808,47 @@ func (e *Engine) SetUserPreference(userID int64, key, value string) error {
 // allowedPreferenceKeys lists preference keys that can be set via MCP.
 var allowedPreferenceKeys = map[string]bool{
 	"keywords":           true,
+	"avoid_keywords":     true,
 	"interest_threshold": true,
 	"filter_threshold":   true,
 	"notify_when":        true,
 	"notify_min_score":   true,
+	"notify_webhook_url": true,
+	"autostar_min_score": true,
+	"summary_max_words":  true,
+	"summary_style":      true,
+	"languages":          true,
+	"timezone":           true,
+	"date_format":        true,
+	"sanitize_policy":    true,
 }
 
+// maxSummaryWords caps summary_max_words; beyond this the preference stops
+// meaning "short".
+const maxSummaryWords = 500
+
 // allowedFilterAxes are the valid axis values for filter rules.
 var allowedFilterAxes = map[string]bool{
 	"author":   true,
 	"category": true,
 	"tag":      true,
+	"domain":   true,
+	"title":    true,
+	"lang":     true,
 }
 
 // GetPreferences returns all user preferences, merging DB values over config defaults.
 func (e *Engine) GetPreferences(userID int64) (*UserPreferences, error) {
 	prefs := &UserPreferences{
-		InterestThreshold: e.config.Thresholds.InterestScore,
-		NotifyWhen:        "present",
-		NotifyMinScore:    7.0,
+		NotifyWhen:     "present",
+		NotifyMinScore: DefaultNotifyMinScore,
+		Languages:      []string{},
 	}
 
-	e.mu.RLock()
-	prefs.Keywords = append([]string{}, e.config.Preferences.Keywords...)
-	e.mu.RUnlock()
+	cfg := e.cfg()
+	prefs.InterestThreshold = cfg.Thresholds.InterestScore
+	prefs.Keywords = append(Keywords{}, cfg.Preferences.Keywords...)
+	prefs.AvoidKeywords = append(Keywords{}, cfg.Preferences.AvoidKeywords...)
 
 	dbPrefs, err := e.store.GetAllUserPreferences(userID)
 	if err != nil {
@@ -1236,11 +2856,17 @@ func (e *Engine) GetPreferences(userID int64) (*UserPreferences, error) {
 	}
 
 	if v, ok := dbPrefs["keywords"]; ok {
-		var kw []string
+		var kw Keywords
 		if json.Unmarshal([]byte(v), &kw) == nil {
 			prefs.Keywords = kw
 		}
 	}
+	if v, ok := dbPrefs["avoid_keywords"]; ok {
+		var kw Keywords
+		if json.Unmarshal([]byte(v), &kw) == nil {
+			prefs.AvoidKeywords = kw
+		}
+	}
 	if v, ok := dbPrefs["interest_threshold"]; ok {
 		if f, err := strconv.ParseFloat(v, 64); err == nil {
 			prefs.InterestThreshold = f
@@ -1259,25 +2885,66 @@ func (e *Engine) GetPreferences(userID int64) (*UserPreferences, error) {
 			prefs.NotifyMinScore = f
 		}
 	}
+	if v, ok := dbPrefs["notify_webhook_url"]; ok {
+		prefs.NotifyWebhookURL = v
+	}
+	if v, ok := dbPrefs["autostar_min_score"]; ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			prefs.AutostarMinScore = f
+		}
+	}
+	if v, ok := dbPrefs["summary_max_words"]; ok {
+		if i, err := strconv.Atoi(v); err == nil {
+			prefs.SummaryMaxWords = i
+		}
+	}
+	if v, ok := dbPrefs["summary_style"]; ok {
+		prefs.SummaryStyle = v
+	}
+	if v, ok := dbPrefs["languages"]; ok {
+		json.Unmarshal([]byte(v), &prefs.Languages) //nolint:errcheck
+	}
+	if v, ok := dbPrefs["timezone"]; ok {
+		prefs.Timezone = v
+	}
+	if v, ok := dbPrefs["date_format"]; ok {
+		prefs.DateFormat = v
+	}
+	if v, ok := dbPrefs["sanitize_policy"]; ok {
+		prefs.SanitizePolicy = v
+	}
 
 	return prefs, nil
 }
 
-// SetPreference validates and stores a single preference, updating runtime config
-// for keys that affect scoring (keywords, interest_threshold).
+// SetPreference validates and stores a single preference. Preferences stay
+// per-user: curation and the poller read them through GetPreferences, so
+// none of them change the engine-wide defaults.
 func (e *Engine) SetPreference(userID int64, key, value string) error {
+	if err := validatePreference(key, value); err != nil {
+		return err
+	}
+	return e.store.SetUserPreference(userID, key, value)
+}
+
+// validatePreference checks that key is a settable preference and value is
+// valid for it.
+func validatePreference(key, value string) error {
 	if !allowedPreferenceKeys[key] {
 		return fmt.Errorf("unknown preference key: %q", key)
 	}
 
 	// Validate value by type
 	switch key {
-	case "keywords":
-		var kw []string
+	case "keywords", "avoid_keywords":
+		var kw Keywords
 		if err := json.Unmarshal([]byte(value), &kw); err != nil {
-			return fmt.Errorf("keywords must be a JSON array of strings: %w", err)
+			return fmt.Errorf("%s must be a JSON array of strings or {\"term\", \"weight\"} objects: %w", key, err)
 		}
-	case "interest_threshold", "notify_min_score":
+		if err := kw.Validate(); err != nil {
+			return err
+		}
+	case "interest_threshold", "notify_min_score", "autostar_min_score":
 		if _, err := strconv.ParseFloat(value, 64); err != nil {
 			return fmt.Errorf("%s must be a number: %w", key, err)
 		}
@@ -1291,25 +2958,51 @@ func (e *Engine) SetPreference(userID int64, key, value string) error {
 		default:
 			return fmt.Errorf("notify_when must be \"present\", \"always\", or \"queue\"")
 		}
+	case "notify_webhook_url":
+		// Internal hosts are refused at delivery time when the server is
+		// configured to block them.
+		if value != "" {
+			if err := notify.ValidateURL(value, false); err != nil {
+				return err
+			}
+		}
+	case "summary_max_words":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > maxSummaryWords {
+			return fmt.Errorf("summary_max_words must be an integer from 0 (no limit) to %d", maxSummaryWords)
+		}
+	case "summary_style":
+		if !ai.SummaryStyles[value] {
+			return fmt.Errorf("summary_style must be \"terse\", \"detailed\", \"bullets\", or empty for the default")
+		}
+	case "languages":
+		var langs []string
+		if err := json.Unmarshal([]byte(value), &langs); err != nil {
+			return fmt.Errorf("languages must be a JSON array of language codes: %w", err)
+		}
+		known := langdetect.Languages()
+		for _, l := range langs {
+			if !slices.Contains(known, l) {
+				return fmt.Errorf("unsupported language %q (supported: %s)", l, strings.Join(known, ", "))
+			}
+		}
+	case "timezone":
+		if _, err := time.LoadLocation(value); err != nil {
+			return fmt.Errorf("timezone must be an IANA zone name such as \"Europe/Berlin\": %w", err)
+		}
+	case "date_format":
+		switch value {
+		case "", "relative", "absolute":
+		default:
+			return fmt.Errorf("date_format must be \"relative\" or \"absolute\"")
+		}
+	case "sanitize_policy":
+		switch value {
+		case "", "ugc", "strict", "relaxed":
+		default:
+			return fmt.Errorf("sanitize_policy must be \"ugc\", \"strict\" or \"relaxed\"")
+		}
 	}
-
-	if err := e.store.SetUserPreference(userID, key, value); err != nil {
-		return err
-	}
-
-	// Update runtime config for scoring-affecting keys
-	e.mu.Lock()
-	defer e.mu.Unlock()
-	switch key {
-	case "keywords":
-		var kw []string
-		json.Unmarshal([]byte(value), &kw) // already validated above
-		e.config.Preferences.Keywords = kw
-	case "interest_threshold":
-		f, _ := strconv.ParseFloat(value, 64) // already validated above
-		e.config.Thresholds.InterestScore = f
-	}
-
 	return nil
 }
 
@@ -1325,7 +3018,7 @@ func (e *Engine) ListPrompts(userID int64) ([]PromptInfo, error) {
 		customMap[customPrompts[i].PromptType] = &customPrompts[i]
 	}
 
-	promptLoader := ai.NewPromptLoader(e.store, e.config)
+	promptLoader := ai.NewPromptLoader(e.store, e.cfg())
 
 	var result []PromptInfo
 	for pt := range allowedPromptTypes {
@@ -1349,7 +3042,7 @@ func (e *Engine) GetPrompt(userID int64, promptType string) (*PromptDetail, erro
 		return nil, fmt.Errorf("unknown or restricted prompt type: %q", promptType)
 	}
 
-	promptLoader := ai.NewPromptLoader(e.store, e.config)
+	promptLoader := ai.NewPromptLoader(e.store, e.cfg())
 
 	template, err := promptLoader.GetPrompt(userID, ai.PromptType(promptType))
 	if err != nil {
@@ -1382,18 +3075,41 @@ func (e *Engine) ListModels(ctx context.Context) ([]string, error) {
 	return e.ai.ListModels(ctx)
 }
 
-// SetPrompt customizes a prompt template, temperature, and/or model.
+// AIStatus checks that the AI endpoint is reachable and serves the
+// configured security and curation models.
+func (e *Engine) AIStatus(ctx context.Context) AIStatus {
+	if e.ai == nil {
+		return AIStatus{Error: "AI processing is not configured"}
+	}
+	status := AIStatus{Configured: true}
+	status.SecurityModel, status.CurationModel = e.ai.Models()
+	if err := e.ai.CheckModels(ctx); err != nil {
+		status.Error = err.Error()
+	} else {
+		status.Ready = true
+	}
+	return status
+}
+
+// SetPrompt customizes a prompt template, temperature, and/or model. A new
+// template must parse; a briefing template must also render against sample
+// data.
 func (e *Engine) SetPrompt(userID int64, promptType, template string, temp *float64, model *string) error {
 	if !allowedPromptTypes[promptType] {
 		return fmt.Errorf("unknown or restricted prompt type: %q", promptType)
 	}
+	if template != "" {
+		if err := ai.ValidatePrompt(ai.PromptType(promptType), template); err != nil {
+			return fmt.Errorf("invalid %s template: %w", promptType, err)
+		}
+	}
 
 	// If only temperature/model is being set, we need to fetch the existing template
 	if template == "" {
 		existing, err := e.store.GetUserPrompt(userID, promptType)
 		if err == sql.ErrNoRows || existing == "" {
 			// Get the default template
-			promptLoader := ai.NewPromptLoader(e.store, e.config)
+			promptLoader := ai.NewPromptLoader(e.store, e.cfg())
 			template, err = promptLoader.GetPrompt(userID, ai.PromptType(promptType))
 			if err != nil {
 				return fmt.Errorf("get default prompt: %w", err)
@@ -1429,6 +3145,32 @@ func (e *Engine) StarArticle(userID, articleID int64, starred bool) error {
 	return e.store.UpdateStarred(userID, articleID, starred)
 }
 
+// StarArticles sets or clears the starred flag on several articles at once.
+// Duplicate IDs are ignored.
+func (e *Engine) StarArticles(userID int64, articleIDs []int64, starred bool) error {
+	ids := slices.Clone(articleIDs)
+	slices.Sort(ids)
+	return e.store.SetStarredBatch(userID, slices.Compact(ids), starred)
+}
+
+// SetArticleNote saves the user's private note on an article. Surrounding
+// whitespace is trimmed; a blank note removes any existing one.
+func (e *Engine) SetArticleNote(userID, articleID int64, note string) error {
+	if _, err := e.store.GetArticle(articleID); err != nil {
+		return fmt.Errorf("article %d: %w", articleID, err)
+	}
+	return e.store.SetArticleNote(userID, articleID, strings.TrimSpace(note))
+}
+
+// GetArticleNote returns the user's note on an article, or "" if none.
+func (e *Engine) GetArticleNote(userID, articleID int64) (string, error) {
+	n, err := e.store.GetArticleNote(userID, articleID)
+	if err != nil || n == nil {
+		return "", err
+	}
+	return n.Note, nil
+}
+
 // RegisterUser creates a new user by name and returns the ID.
 func (e *Engine) RegisterUser(name string) (int64, error) {
 	return e.store.CreateUser(name)
@@ -1443,6 +3185,12 @@ func (e *Engine) ResolveUser(name string) (int64, error) {
 	return u.ID, nil
 }
 
+// SubscribingUsers returns the IDs of all users with at least one feed
+// subscription.
+func (e *Engine) SubscribingUsers() ([]int64, error) {
+	return e.store.GetAllSubscribingUsers()
+}
+
 // ListUsers returns all registered users.
 func (e *Engine) ListUsers() ([]User, error) {
 	users, err := e.store.ListUsers()
@@ -1497,20 +3245,85 @@ func userFromStorage(u storage.User) User {
 
 // AddFilterRule validates and stores a new filter rule. Returns the rule ID.
 func (e *Engine) AddFilterRule(userID int64, rule FilterRule) (int64, error) {
+	sr, err := validFilterRule(userID, rule)
+	if err != nil {
+		return 0, err
+	}
+	return e.store.AddFilterRule(sr)
+}
+
+// validFilterRule validates rule and normalizes its value, returning the
+// storage rule to insert for userID.
+func validFilterRule(userID int64, rule FilterRule) (*storage.FilterRule, error) {
 	if !allowedFilterAxes[rule.Axis] {
-		return 0, fmt.Errorf("invalid filter axis: %q (must be author, category, or tag)", rule.Axis)
+		return nil, fmt.Errorf("invalid filter axis: %q (must be author, category, tag, domain, title, or lang)", rule.Axis)
+	}
+	if rule.Axis == "domain" {
+		rule.Value = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(rule.Value), "."))
+	}
+	if rule.Axis == "lang" {
+		rule.Value = strings.ToLower(strings.TrimSpace(rule.Value))
 	}
 	if rule.Value == "" {
-		return 0, fmt.Errorf("filter rule value cannot be empty")
+		return nil, fmt.Errorf("filter rule value cannot be empty")
 	}
-	sr := &storage.FilterRule{
+	if rule.Block && rule.Score != 0 {
+		return nil, fmt.Errorf("block rules hide matching articles outright and cannot carry a score")
+	}
+	return &storage.FilterRule{
 		UserID: userID,
 		FeedID: rule.FeedID,
 		Axis:   rule.Axis,
 		Value:  rule.Value,
 		Score:  rule.Score,
+		Block:  rule.Block,
+	}, nil
+}
+
+// ImportFilterRules adds a batch of filter rules for the user, such as one
+// written by ExportFilterRules. Every rule is validated first, and an invalid
+// one rejects the whole batch. Rules duplicating an existing rule are skipped
+// and counted in skipped. The rules' IDs, user IDs and creation times are
+// ignored; feed IDs are kept, so feed-scoped rules only carry over between
+// databases that share feed IDs.
+func (e *Engine) ImportFilterRules(userID int64, rules []FilterRule) (added, skipped int, err error) {
+	batch := make([]storage.FilterRule, len(rules))
+	for i, rule := range rules {
+		sr, err := validFilterRule(userID, rule)
+		if err != nil {
+			return 0, 0, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		batch[i] = *sr
 	}
-	return e.store.AddFilterRule(sr)
+	added, err = e.store.ImportFilterRules(batch)
+	if err != nil {
+		return 0, 0, err
+	}
+	return added, len(rules) - added, nil
+}
+
+// exportedFilterRule is the portable form of a filter rule written by
+// ExportFilterRules. It decodes as a FilterRule.
+type exportedFilterRule struct {
+	FeedID *int64 `json:"feed_id,omitempty"`
+	Axis   string `json:"axis"`
+	Value  string `json:"value"`
+	Score  int    `json:"score"`
+	Block  bool   `json:"block,omitempty"`
+}
+
+// ExportFilterRules returns the user's filter rules as a JSON array that
+// ImportFilterRules accepts, leaving out IDs and timestamps.
+func (e *Engine) ExportFilterRules(userID int64) ([]byte, error) {
+	rules, err := e.store.GetFilterRules(userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]exportedFilterRule, len(rules))
+	for i, r := range rules {
+		out[i] = exportedFilterRule{FeedID: r.FeedID, Axis: r.Axis, Value: r.Value, Score: r.Score, Block: r.Block}
+	}
+	return json.MarshalIndent(out, "", "  ")
 }
 
 // GetFilterRules returns filter rules for a user, optionally scoped to a feed.
@@ -1528,6 +3341,7 @@ func (e *Engine) GetFilterRules(userID int64, feedID *int64) ([]FilterRule, erro
 			Axis:      r.Axis,
 			Value:     r.Value,
 			Score:     r.Score,
+			Block:     r.Block,
 			CreatedAt: r.CreatedAt,
 		}
 	}
@@ -1544,6 +3358,63 @@ func (e *Engine) DeleteFilterRule(ruleID int64) error {
 	return e.store.DeleteFilterRule(ruleID)
 }
 
+// ExplainArticleScore reports which of the user's filter rules match an
+// article, their combined score, and whether the article clears the user's
+// filter threshold.
+func (e *Engine) ExplainArticleScore(userID, articleID int64) (*ScoreExplanation, error) {
+	a, err := e.store.GetArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := e.store.ExplainArticleScore(userID, articleID)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := e.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ex := &ScoreExplanation{
+		ArticleID: articleID,
+		Title:     a.Title,
+		Matches:   make([]FilterMatch, len(matches)),
+		Threshold: prefs.FilterThreshold,
+	}
+	hasScoringRules := false
+	for i, m := range matches {
+		ex.Matches[i] = FilterMatch{
+			RuleID: m.RuleID,
+			FeedID: m.FeedID,
+			Axis:   m.Axis,
+			Value:  m.Value,
+			Score:  m.Score,
+			Block:  m.Block,
+		}
+		if m.Block {
+			ex.Blocked = true
+		} else {
+			ex.Total += m.Score
+		}
+	}
+	if !ex.Blocked && ex.Threshold != 0 {
+		rules, err := e.store.GetFilterRules(userID, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rules {
+			if !r.Block {
+				hasScoringRules = true
+				break
+			}
+		}
+	}
+	// Mirrors filterScoreClause: a user with no scoring rules is not held
+	// to the threshold.
+	ex.Visible = !ex.Blocked && (ex.Threshold == 0 || !hasScoringRules || ex.Total >= ex.Threshold)
+	return ex, nil
+}
+
 // GetFeedMetadata returns discoverable authors and categories for a feed.
 func (e *Engine) GetFeedMetadata(feedID int64) (*FeedMetadata, error) {
 	authors, err := e.store.GetFeedAuthors(feedID)
@@ -1576,11 +3447,26 @@ func (e *Engine) resolveFilterThreshold(userID int64) *int {
 	return &prefs.FilterThreshold
 }
 
+// resolveLanguages returns the user's language allow-list, or nil when the
+// user has not restricted languages.
+func (e *Engine) resolveLanguages(userID int64) []string {
+	prefs, err := e.GetPreferences(userID)
+	if err != nil {
+		return nil
+	}
+	return prefs.Languages
+}
+
 // Close releases all resources held by the engine.
 func (e *Engine) Close() error {
 	return e.store.Close()
 }
 
+// Ping reports whether the underlying database is reachable.
+func (e *Engine) Ping() error {
+	return e.store.Ping()
+}
+
 // --- internal type conversion helpers ---
 
 func articleFromInternal(a storage.Article) Article {
@@ -1594,9 +3480,32 @@ func articleFromInternal(a storage.Article) Article {
 		Author:        a.Author,
 		PublishedDate: a.PublishedDate,
 		FetchedDate:   a.FetchedDate,
+		EffectiveDate: effectiveDate(a),
 		LinkedURL:     a.LinkedURL,
 		LinkedContent: a.LinkedContent,
+		Lang:          a.Lang,
+		WordCount:     a.WordCount,
+		ReadingTime:   readingMinutes(a.WordCount),
+		Slug:          a.Slug,
+	}
+}
+
+// effectiveDate is the date article lists sort on: the published date, or
+// the fetch time when the feed gave none.
+func effectiveDate(a storage.Article) time.Time {
+	if a.PublishedDate != nil {
+		return *a.PublishedDate
 	}
+	return a.FetchedDate
+}
+
+// readingWPM is the reading speed used for reading-time estimates.
+const readingWPM = 200
+
+// readingMinutes estimates how long words take to read, rounded up so that
+// any non-empty article takes at least a minute.
+func readingMinutes(words int) int {
+	return (words + readingWPM - 1) / readingWPM
 }
 
 func articlesFromInternal(articles []storage.Article) []Article {
@@ -1624,6 +3533,45 @@ func (e *Engine) GetArticleImage(imageID int64) (*storage.ArticleImage, error) {
 	return e.store.GetArticleImage(imageID)
 }
 
+// imageProxyTimeout bounds an on-demand image fetch for ProxyArticleImage.
+const imageProxyTimeout = 15 * time.Second
+
+// ProxyArticleImage returns the image at imgURL for an article, from the
+// article image cache when present and otherwise fetched and cached. The
+// web layer uses it to serve publisher images without the browser
+// contacting the publisher.
+func (e *Engine) ProxyArticleImage(ctx context.Context, articleID int64, imgURL string) (*storage.ArticleImage, error) {
+	if imageMap, err := e.store.GetArticleImageMap(articleID); err == nil {
+		if id, ok := imageMap[imgURL]; ok {
+			if img, err := e.store.GetArticleImage(id); err == nil && img != nil {
+				return img, nil
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, imageProxyTimeout)
+	defer cancel()
+	data, mimeType, width, height, err := e.fetcher.FetchImage(ctx, imgURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch image: %w", err)
+	}
+	img := &storage.ArticleImage{
+		ArticleID:   articleID,
+		OriginalURL: imgURL,
+		Data:        data,
+		MimeType:    mimeType,
+		Width:       width,
+		Height:      height,
+	}
+	// A failed cache write (e.g. read-only mode) still serves the image.
+	if id, err := e.store.StoreArticleImage(articleID, imgURL, data, mimeType, width, height); err != nil {
+		e.log.Warn("cache proxied image failed", "article_id", articleID, "url", imgURL, "err", err)
+	} else {
+		img.ID = id
+	}
+	return img, nil
+}
+
 // LooksLikeGarbage detects model output that contains training-data artifacts
 // or prompt injection patterns rather than a real summary. Small models under
 // load sometimes produce this kind of garbled output.
@@ -1654,10 +3602,15 @@ func feedFromInternal(f storage.Feed) Feed {
 		Title:       f.Title,
 		Description: f.Description,
 		SiteURL:     f.SiteURL,
+		Generator:   f.Generator,
+		Language:    f.Language,
 		LastFetched: f.LastFetched,
 		LastError:   f.LastError,
 		Enabled:     f.Enabled,
 		CreatedAt:   f.CreatedAt,
+
+		ConsecutiveErrors: f.ConsecutiveErrors,
+		Status:            f.Status,
 	}
 }
 
@@ -67,7 +67,8 @@ func (e *Engine) FeverMarkFeedRead(userID, feedID int64, before int64) error {
 
 // FeverMarkGroupRead marks article-group articles as read up to the given timestamp.
 func (e *Engine) FeverMarkGroupRead(userID, groupID int64, before int64) error {
-	return e.store.MarkGroupArticlesRead(userID, groupID, before)
+	_, err := e.store.MarkGroupArticlesRead(userID, groupID, before)
+	return err
 }
 
 // FeverMarkAllRead marks all articles read for a user up to the given timestamp.
@@ -1,7 +1,19 @@
 package herald
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -76,6 +88,33 @@ func subscribeDirect(t *testing.T, engine *Engine, userID int64, url, title stri
 	return feedID
 }
 
+// newFakeChat starts an OpenAI-compatible chat endpoint that answers each
+// request with reply(prompt), where prompt is the last message sent. Other
+// paths, such as embeddings, are not found. The server is closed when the
+// test ends.
+func newFakeChat(t *testing.T, reply func(prompt string) string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		var req struct {
+			Messages []struct{ Content string } `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		var prompt string
+		if len(req.Messages) > 0 {
+			prompt = req.Messages[len(req.Messages)-1].Content
+		}
+		body, _ := json.Marshal(map[string]any{"choices": []any{map[string]any{"message": map[string]string{"role": "assistant", "content": reply(prompt)}}}})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
 func TestSubscribeAndGetFeeds(t *testing.T) {
 	engine, cleanup := newTestEngine(t)
 	defer cleanup()
@@ -97,6 +136,222 @@ func TestSubscribeAndGetFeeds(t *testing.T) {
 	}
 }
 
+func TestSubscribeFeed_EquivalentURL(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "http://example.com/feed/", "Example")
+	const other = 2
+
+	// Resolves to the existing row without fetching, so no server is needed.
+	if err := engine.SubscribeFeed(other, "https://example.com/feed", ""); err != nil {
+		t.Fatalf("SubscribeFeed: %v", err)
+	}
+	feeds, err := engine.GetUserFeeds(other)
+	if err != nil {
+		t.Fatalf("GetUserFeeds: %v", err)
+	}
+	if len(feeds) != 1 || feeds[0].ID != feedID {
+		t.Errorf("subscribed feeds = %+v, want existing feed %d", feeds, feedID)
+	}
+}
+
+func TestSubscribeFeed_StoresMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel>
+<title>Meta Feed</title><link>https://blog.example.com/</link>
+<language>en-us</language><generator>WordPress 6.5</generator>
+<item><title>Post</title><link>https://blog.example.com/post</link><guid>p1</guid></item>
+</channel></rss>`)
+	}))
+	defer srv.Close()
+
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+	if err := engine.SubscribeFeed(1, srv.URL, ""); err != nil {
+		t.Fatalf("SubscribeFeed: %v", err)
+	}
+	feeds, err := engine.GetUserFeeds(1)
+	if err != nil || len(feeds) != 1 {
+		t.Fatalf("GetUserFeeds = %+v, %v", feeds, err)
+	}
+	f := feeds[0]
+	if f.SiteURL != "https://blog.example.com/" || f.Language != "en-us" || f.Generator != "WordPress 6.5" {
+		t.Errorf("metadata = %q, %q, %q; want the feed's link, language and generator", f.SiteURL, f.Language, f.Generator)
+	}
+
+	// A later fetch without a link keeps the known site URL.
+	if err := engine.store.UpdateFeedMetadata(f.ID, "", "", "de"); err != nil {
+		t.Fatalf("UpdateFeedMetadata: %v", err)
+	}
+	feeds, _ = engine.GetUserFeeds(1)
+	if feeds[0].SiteURL != "https://blog.example.com/" || feeds[0].Language != "de" || feeds[0].Generator != "" {
+		t.Errorf("after update: %+v", feeds[0])
+	}
+}
+
+func TestUnsubscribeGracePeriod(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	articleID, err := engine.store.AddArticle(&storage.Article{
+		FeedID: feedID,
+		GUID:   "grace-1",
+		Title:  "Kept Article",
+		URL:    "https://example.com/article/1",
+	})
+	if err != nil {
+		t.Fatalf("AddArticle: %v", err)
+	}
+	if err := engine.StarArticle(1, articleID, true); err != nil {
+		t.Fatalf("StarArticle: %v", err)
+	}
+
+	if err := engine.UnsubscribeFeed(1, feedID); err != nil {
+		t.Fatalf("UnsubscribeFeed: %v", err)
+	}
+	if feeds, _ := engine.GetUserFeeds(1); len(feeds) != 0 {
+		t.Fatalf("expected no feeds after unsubscribe, got %d", len(feeds))
+	}
+
+	// Still inside the grace period: the poller's sweep leaves it alone.
+	engine.deleteExpiredFeeds()
+	if err := engine.ResubscribeFeed(1, feedID); err != nil {
+		t.Fatalf("ResubscribeFeed: %v", err)
+	}
+	if feeds, _ := engine.GetUserFeeds(1); len(feeds) != 1 || feeds[0].ID != feedID {
+		t.Fatalf("expected the feed back after resubscribe, got %+v", feeds)
+	}
+	starred, err := engine.GetStarredArticles(1, 10, 0)
+	if err != nil {
+		t.Fatalf("GetStarredArticles: %v", err)
+	}
+	if len(starred) != 1 || starred[0].ID != articleID {
+		t.Errorf("expected the starred article to survive, got %+v", starred)
+	}
+	if due, _ := engine.store.GetFeedsDueForDeletion(time.Now().Add(2 * UnsubscribeGrace)); len(due) != 0 {
+		t.Errorf("resubscribe should cancel the pending deletion, still due: %v", due)
+	}
+
+	// Past the deadline the sweep deletes the feed and undo is no longer possible.
+	if err := engine.UnsubscribeFeed(1, feedID); err != nil {
+		t.Fatalf("UnsubscribeFeed: %v", err)
+	}
+	if _, err := engine.store.ScheduleFeedDeletion(feedID, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("ScheduleFeedDeletion: %v", err)
+	}
+	engine.deleteExpiredFeeds()
+	if _, err := engine.GetArticle(articleID); err == nil {
+		t.Error("article should be deleted with its feed")
+	}
+	if err := engine.ResubscribeFeed(1, feedID); err == nil {
+		t.Error("expected ResubscribeFeed to fail after the feed was deleted")
+	}
+}
+
+func TestResubscribeFeedOnlyUndoesOwnUnsubscribe(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	alice, _ := engine.store.CreateUser("alice")
+	bob, _ := engine.store.CreateUser("bob")
+	feedID := subscribeDirect(t, engine, alice, "https://example.com/feed.xml", "Test Feed")
+
+	if err := engine.UnsubscribeFeed(alice, feedID); err != nil {
+		t.Fatalf("UnsubscribeFeed: %v", err)
+	}
+	if err := engine.ResubscribeFeed(bob, feedID); err == nil {
+		t.Error("another user should not be able to undo alice's unsubscribe")
+	}
+	// Unsubscribing from a feed bob never had doesn't earn him an undo.
+	if err := engine.UnsubscribeFeed(bob, feedID); err != nil {
+		t.Fatalf("UnsubscribeFeed(bob): %v", err)
+	}
+	if err := engine.ResubscribeFeed(bob, feedID); err == nil {
+		t.Error("undo should require having been subscribed")
+	}
+	if ok, _ := engine.store.IsSubscribed(bob, feedID); ok {
+		t.Fatal("bob should not be subscribed")
+	}
+
+	if err := engine.ResubscribeFeed(alice, feedID); err != nil {
+		t.Fatalf("ResubscribeFeed(alice): %v", err)
+	}
+	// An undo is used up once taken.
+	engine.store.UnsubscribeUserFromFeed(alice, feedID)
+	if err := engine.ResubscribeFeed(alice, feedID); err == nil {
+		t.Error("an undo should only work once")
+	}
+
+	// Nor does it outlive the grace period.
+	engine.store.SubscribeUserToFeed(alice, feedID)
+	if err := engine.UnsubscribeFeed(alice, feedID); err != nil {
+		t.Fatalf("UnsubscribeFeed: %v", err)
+	}
+	if ok, _ := engine.store.TakeUnsubscribe(alice, feedID, time.Now().Add(2*UnsubscribeGrace)); ok {
+		t.Error("undo should expire with the grace period")
+	}
+}
+
+func TestGetReadableArticle(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("The reader view shows the whole story without the clutter of the original page. ", 12) + "</p>"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><title>Full</title></head><body><article><h1>Full Story</h1>%s%s<script>steal()</script></article></body></html>`, paragraph, paragraph)
+	}))
+	defer srv.Close()
+
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	add := func(guid, url, content, summary string) int64 {
+		t.Helper()
+		id, err := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID, GUID: guid, Title: guid, URL: url, Content: content, Summary: summary,
+		})
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		return id
+	}
+
+	// Complete stored content is used as is, minus anything unsafe.
+	id := add("stored", "", `<p>Safe <b>content</b> here.</p><script>alert("x")</script><a href="javascript:alert(1)">link</a>`, "")
+	got, err := engine.GetReadableArticle(1, id)
+	if err != nil {
+		t.Fatalf("GetReadableArticle: %v", err)
+	}
+	if !strings.Contains(string(got), "<p>Safe <b>content</b> here.</p>") {
+		t.Errorf("safe content missing: %s", got)
+	}
+	if strings.Contains(string(got), "<script") || strings.Contains(string(got), "javascript:") {
+		t.Errorf("unsafe content survived: %s", got)
+	}
+
+	// A truncated excerpt is replaced by the extracted page.
+	id = add("excerpt", srv.URL+"/story", "<p>The reader view shows...</p>", "")
+	got, _ = engine.GetReadableArticle(1, id)
+	if !strings.Contains(string(got), "without the clutter") {
+		t.Errorf("expected extracted full text, got: %s", got)
+	}
+	if strings.Contains(string(got), "steal()") {
+		t.Errorf("script from the fetched page survived: %s", got)
+	}
+
+	// No content at all falls back to the feed summary.
+	id = add("summary-only", "", "", "Just the summary.")
+	got, _ = engine.GetReadableArticle(1, id)
+	if !strings.Contains(string(got), "Just the summary.") {
+		t.Errorf("expected summary fallback, got: %s", got)
+	}
+
+	if _, err := engine.GetReadableArticle(1, 999999); err == nil {
+		t.Error("expected an error for a missing article")
+	}
+}
+
 func TestGetUserFeedsEmpty(t *testing.T) {
 	engine, cleanup := newTestEngine(t)
 	defer cleanup()
@@ -123,6 +378,14 @@ func TestGetUnreadArticlesEmpty(t *testing.T) {
 	}
 }
 
+func TestReadingMinutes(t *testing.T) {
+	for words, want := range map[int]int{0: 0, 1: 1, 200: 1, 201: 2, 1000: 5} {
+		if got := readingMinutes(words); got != want {
+			t.Errorf("readingMinutes(%d) = %d, want %d", words, got, want)
+		}
+	}
+}
+
 func TestArticleLifecycle(t *testing.T) {
 	engine, cleanup := newTestEngine(t)
 	defer cleanup()
@@ -288,158 +551,1309 @@ func TestGroupLifecycle(t *testing.T) {
 	}
 }
 
-func TestGetGroupArticlesNotFound(t *testing.T) {
-	engine, cleanup := newTestEngine(t)
-	defer cleanup()
+func TestReadOnlyEngineRejectsWrites(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	rw, err := NewEngine(EngineConfig{DBPath: dbPath})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	feedID := subscribeDirect(t, rw, 1, "https://example.com/feed.xml", "Test Feed")
+	now := time.Now()
+	articleID, _ := rw.store.AddArticle(&storage.Article{
+		FeedID: feedID, GUID: "ro1", Title: "Read Only",
+		URL: "https://example.com/ro1", PublishedDate: &now,
+	})
+	rw.Close()
 
-	group, err := engine.GetGroupArticles(999)
+	ro, err := NewEngine(EngineConfig{DBPath: dbPath, ReadOnly: true})
 	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+		t.Fatalf("NewEngine read-only: %v", err)
 	}
-	// Empty group is fine, not an error
-	if len(group.Articles) != 0 {
-		t.Errorf("expected 0 articles, got %d", len(group.Articles))
+	defer ro.Close()
+
+	if _, err := ro.GetArticle(articleID); err != nil {
+		t.Errorf("GetArticle on read-only engine: %v", err)
+	}
+	if err := ro.StarArticle(1, articleID, true); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("StarArticle on read-only engine: got %v, want ErrReadOnly", err)
+	}
+	if _, err := ro.RegenerateSummary(context.Background(), 1, articleID); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("RegenerateSummary on read-only engine: got %v, want ErrReadOnly", err)
 	}
 }
 
-func TestGenerateBriefingEmpty(t *testing.T) {
-	engine, cleanup := newTestEngine(t)
-	defer cleanup()
+func TestProcessNewArticles_SharedLimit(t *testing.T) {
+	// Fake endpoint that tracks how many model calls are in flight at once.
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
 
-	briefing, err := engine.GenerateBriefing(1)
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+		MaxParallel:   1,
+	})
 	if err != nil {
-		t.Fatalf("GenerateBriefing: %v", err)
-	}
-	if briefing != "" {
-		t.Errorf("expected empty briefing with no articles, got %q", briefing)
+		t.Fatalf("NewEngine: %v", err)
 	}
-}
+	defer engine.Close()
 
-func TestGetFeedStats(t *testing.T) {
-	engine, cleanup := newTestEngine(t)
-	defer cleanup()
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	other, err := engine.RegisterUser("bob")
+	if err != nil {
+		t.Fatalf("RegisterUser: %v", err)
+	}
+	if err := engine.store.SubscribeUserToFeed(other, feedID); err != nil {
+		t.Fatalf("SubscribeUserToFeed: %v", err)
+	}
+	body := strings.Repeat("Long enough to be worth scoring. ", 10)
+	for i := range 3 {
+		engine.store.AddArticle(&storage.Article{ //nolint:errcheck
+			FeedID: feedID, GUID: fmt.Sprintf("sl%d", i), Title: fmt.Sprintf("Article %d", i),
+			URL: fmt.Sprintf("https://example.com/sl%d", i), Content: body,
+		})
+	}
 
-	// Two feeds, user 1
-	feed1 := subscribeDirect(t, engine, 1, "https://example.com/feed1.xml", "Feed One")
-	feed2 := subscribeDirect(t, engine, 1, "https://example.com/feed2.xml", "Feed Two")
+	// Two users processed at once still share the engine's single slot.
+	done := make(chan struct{})
+	for _, userID := range []int64{1, other} {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			engine.ProcessNewArticles(context.Background(), userID) //nolint:errcheck
+		}()
+	}
+	<-done
+	<-done
 
-	now := time.Now()
+	if got := peak.Load(); got != 1 {
+		t.Errorf("peak concurrent model calls = %d, want 1 (MaxParallel)", got)
+	}
+}
 
-	// Feed 1: 3 articles
-	id1, _ := engine.store.AddArticle(&storage.Article{
-		FeedID: feed1, GUID: "f1-1", Title: "Article 1",
-		URL: "https://example.com/1", PublishedDate: &now,
-	})
-	engine.store.AddArticle(&storage.Article{
-		FeedID: feed1, GUID: "f1-2", Title: "Article 2",
-		URL: "https://example.com/2", PublishedDate: &now,
-	})
-	engine.store.AddArticle(&storage.Article{
-		FeedID: feed1, GUID: "f1-3", Title: "Article 3",
-		URL: "https://example.com/3", PublishedDate: &now,
+func TestProcessNewArticles_GroupSummaryOncePerRun(t *testing.T) {
+	// Fake model that passes every article, asks for a new group, and
+	// counts group-summary requests. Embeddings are unavailable, so
+	// grouping falls through to the title match.
+	var summaries atomic.Int32
+	srv := newFakeChat(t, func(prompt string) string {
+		switch {
+		case strings.Contains(prompt, "security filter"):
+			return `{"safe": true, "score": 9, "reasoning": "ok"}`
+		case strings.Contains(prompt, "relates to existing article groups"):
+			return `{"is_related": false, "existing_groups": [], "create_group": true, "display_name": "Quake", "reasoning": "new"}`
+		case strings.Contains(prompt, "covering the same event"):
+			summaries.Add(1)
+			return `{"headline": "Earthquake hits Chile", "summary": "A major earthquake struck Chile."}`
+		case strings.Contains(prompt, "news curator"):
+			return `{"interest_score": 7, "reasoning": "test"}`
+		}
+		return "A short summary of the article."
 	})
 
-	// Feed 2: 2 articles
-	id4, _ := engine.store.AddArticle(&storage.Article{
-		FeedID: feed2, GUID: "f2-1", Title: "Article 4",
-		URL: "https://example.com/4", PublishedDate: &now,
-	})
-	engine.store.AddArticle(&storage.Article{
-		FeedID: feed2, GUID: "f2-2", Title: "Article 5",
-		URL: "https://example.com/5", PublishedDate: &now,
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+		MaxParallel:   1,
 	})
-
-	// Mark one article read in feed 1
-	engine.store.UpdateReadState(1, id1, true, nil, nil, nil)
-
-	// Summarize one article in feed 2
-	engine.store.UpdateArticleAISummary(1, id4, "Summary of article 4")
-
-	result, err := engine.GetFeedStats(1)
 	if err != nil {
-		t.Fatalf("GetFeedStats: %v", err)
-	}
-
-	if len(result.Feeds) != 2 {
-		t.Fatalf("expected 2 feeds, got %d", len(result.Feeds))
+		t.Fatalf("NewEngine: %v", err)
 	}
+	defer engine.Close()
 
-	// Feeds are ordered by title
-	f1 := result.Feeds[0] // "Feed One"
-	f2 := result.Feeds[1] // "Feed Two"
-
-	if f1.FeedTitle != "Feed One" || f2.FeedTitle != "Feed Two" {
-		t.Fatalf("unexpected feed order: %q, %q", f1.FeedTitle, f2.FeedTitle)
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	body := strings.Repeat("A major earthquake struck off the coast of Chile today. ", 5)
+	next := 0
+	process := func(n int) {
+		t.Helper()
+		for range n {
+			next++
+			engine.store.AddArticle(&storage.Article{ //nolint:errcheck
+				FeedID: feedID, GUID: fmt.Sprintf("q%d", next), Title: "Major earthquake strikes off the coast of Chile",
+				URL: fmt.Sprintf("https://example.com/q%d", next), Content: body,
+			})
+		}
+		scored, err := engine.ProcessNewArticles(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("ProcessNewArticles: %v", err)
+		}
+		if len(scored) != n {
+			t.Fatalf("scored %d articles, want %d", len(scored), n)
+		}
 	}
 
-	// Feed One: 3 total, 2 unread (1 read), 3 unsummarized
-	if f1.TotalArticles != 3 {
-		t.Errorf("feed1 total: got %d, want 3", f1.TotalArticles)
+	process(3)
+	groups, _ := engine.store.GetUserGroups(1)
+	if len(groups) != 1 {
+		t.Fatalf("got %d groups, want 1", len(groups))
 	}
-	if f1.UnreadArticles != 2 {
-		t.Errorf("feed1 unread: got %d, want 2", f1.UnreadArticles)
+	if n, _ := engine.store.GetGroupArticleCount(groups[0].ID); n != 3 {
+		t.Fatalf("group has %d articles, want 3", n)
 	}
-	if f1.UnsummarizedArticles != 3 {
-		t.Errorf("feed1 unsummarized: got %d, want 3", f1.UnsummarizedArticles)
+	if got := summaries.Load(); got != 1 {
+		t.Errorf("group summaries generated = %d, want 1", got)
 	}
 
-	// Feed Two: 2 total, 2 unread, 1 unsummarized
-	if f2.TotalArticles != 2 {
-		t.Errorf("feed2 total: got %d, want 2", f2.TotalArticles)
+	// With a minimum change of 2, two more articles don't warrant a new
+	// summary; a third does, counting from the last summary.
+	engine.config.Grouping.SummaryMinChange = 2
+	process(2)
+	if got := summaries.Load(); got != 1 {
+		t.Errorf("after +2: group summaries generated = %d, want 1", got)
 	}
-	if f2.UnreadArticles != 2 {
-		t.Errorf("feed2 unread: got %d, want 2", f2.UnreadArticles)
-	}
-	if f2.UnsummarizedArticles != 1 {
-		t.Errorf("feed2 unsummarized: got %d, want 1", f2.UnsummarizedArticles)
+	process(1)
+	if got := summaries.Load(); got != 2 {
+		t.Errorf("after +3: group summaries generated = %d, want 2", got)
 	}
+}
 
-	// Totals: 5 total, 4 unread, 4 unsummarized
-	if result.Total.TotalArticles != 5 {
-		t.Errorf("total articles: got %d, want 5", result.Total.TotalArticles)
-	}
-	if result.Total.UnreadArticles != 4 {
-		t.Errorf("total unread: got %d, want 4", result.Total.UnreadArticles)
-	}
-	if result.Total.UnsummarizedArticles != 4 {
-		t.Errorf("total unsummarized: got %d, want 4", result.Total.UnsummarizedArticles)
+func TestRegenerateSummary(t *testing.T) {
+	// Fake OpenAI-compatible endpoint that always answers with the same text.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"A fresh summary of the article."}}]}`))
+	}))
+	defer srv.Close()
+
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
 	}
-}
+	defer engine.Close()
 
-func TestGetFeedStatsEmpty(t *testing.T) {
-	engine, cleanup := newTestEngine(t)
-	defer cleanup()
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	now := time.Now()
+	articleID, _ := engine.store.AddArticle(&storage.Article{
+		FeedID: feedID, GUID: "rs1", Title: "Resummarize Me",
+		URL: "https://example.com/rs1", Content: "Some article body text.", PublishedDate: &now,
+	})
+	engine.store.UpdateArticleAISummary(1, articleID, "A stale summary.")
 
-	result, err := engine.GetFeedStats(1)
+	if _, err := engine.RegenerateSummary(context.Background(), 2, articleID); err == nil {
+		t.Error("RegenerateSummary should refuse an article outside the user's feeds")
+	}
+
+	summary, err := engine.RegenerateSummary(context.Background(), 1, articleID)
 	if err != nil {
-		t.Fatalf("GetFeedStats: %v", err)
+		t.Fatalf("RegenerateSummary: %v", err)
 	}
-	if len(result.Feeds) != 0 {
-		t.Errorf("expected 0 feeds, got %d", len(result.Feeds))
+	if summary != "A fresh summary of the article." {
+		t.Errorf("returned summary = %q", summary)
 	}
-	if result.Total.TotalArticles != 0 {
-		t.Errorf("expected 0 total, got %d", result.Total.TotalArticles)
+	stored, _ := engine.store.GetArticleSummary(1, articleID)
+	if stored == nil || stored.AISummary != summary {
+		t.Errorf("stored summary = %+v, want %q", stored, summary)
 	}
-}
-
-func TestClose(t *testing.T) {
-	engine, cleanup := newTestEngine(t)
-	_ = cleanup // don't use cleanup, test Close directly
 
-	err := engine.Close()
+	// The bulk path only touches articles that were already summarized.
+	engine.store.UpdateArticleAISummary(1, articleID, "Stale again.")
+	n, err := engine.RegenerateFeedSummaries(context.Background(), 1, feedID)
 	if err != nil {
-		t.Fatalf("Close: %v", err)
+		t.Fatalf("RegenerateFeedSummaries: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("RegenerateFeedSummaries replaced %d, want 1", n)
 	}
 }
 
-// --- Filter rules tests ---
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		min, max float64
+	}{
+		{"Apple unveils the iPhone 17 at September event", "Apple unveils iPhone 17 at its September event", 0.99, 1},
+		{"Apple unveils iPhone 17", "APPLE UNVEILS IPHONE 17!", 0.99, 1},
+		{"Apple unveils iPhone 17 at September event", "Apple reports record quarterly revenue", 0.1, 0.2},
+		{"Storm floods coastal towns", "Senate passes budget bill", 0, 0},
+		{"The and for", "The and for", 0, 0}, // nothing left after stopwords
+	}
+	for _, tt := range tests {
+		if got := titleSimilarity(tt.a, tt.b); got < tt.min || got > tt.max {
+			t.Errorf("titleSimilarity(%q, %q) = %.2f, want %.2f-%.2f", tt.a, tt.b, got, tt.min, tt.max)
+		}
+	}
+}
 
-func TestFilterRulesCRUD(t *testing.T) {
+func TestGroupByTitle(t *testing.T) {
+	// No AI is reachable here; grouping by title must not need it.
 	engine, cleanup := newTestEngine(t)
 	defer cleanup()
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+
+	add := func(guid, title string) storage.Article {
+		t.Helper()
+		a := storage.Article{FeedID: feedID, GUID: guid, Title: title, URL: "https://example.com/" + guid}
+		id, err := engine.store.AddArticle(&a)
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		a.ID = id
+		return a
+	}
+	group := func(a storage.Article) {
+		engine.groupByTitle(context.Background(), 1, a, nil, "")
+	}
+
+	first := add("a1", "Major earthquake strikes off the coast of Chile")
+	second := add("a2", "Major earthquake strikes off Chile coast")
+	other := add("a3", "Central bank holds interest rates steady")
+	group(first)
+	group(second)
+	group(other)
+
+	groups, _ := engine.store.GetRecentGroups(1, time.Now().Add(-time.Hour))
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d: %+v", len(groups), groups)
+	}
+	for _, g := range groups {
+		members, _ := engine.store.GetGroupArticles(g.ID)
+		switch g.Topic {
+		case first.Title:
+			if len(members) != 2 {
+				t.Errorf("earthquake group has %d articles, want 2", len(members))
+			}
+		case other.Title:
+			if len(members) != 1 {
+				t.Errorf("rates group has %d articles, want 1", len(members))
+			}
+		default:
+			t.Errorf("unexpected group topic %q", g.Topic)
+		}
+	}
+}
+
+func TestGroupByTitle_MinGroupSize(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+	engine.config.Grouping.MinGroupSize = 2
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+
+	group := func(guid, title string) storage.Article {
+		t.Helper()
+		a := storage.Article{FeedID: feedID, GUID: guid, Title: title, URL: "https://example.com/" + guid}
+		id, err := engine.store.AddArticle(&a)
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		a.ID = id
+		score := 5.0
+		if err := engine.store.UpdateReadState(1, id, false, &score, nil, nil); err != nil {
+			t.Fatalf("UpdateReadState: %v", err)
+		}
+		engine.groupByTitle(context.Background(), 1, a, nil, "")
+		return a
+	}
+	recent := func() []storage.ArticleGroup {
+		t.Helper()
+		groups, err := engine.store.GetRecentGroups(1, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("GetRecentGroups: %v", err)
+		}
+		return groups
+	}
+
+	group("a1", "Major earthquake strikes off the coast of Chile")
+	group("a2", "Central bank holds interest rates steady")
+	if groups := recent(); len(groups) != 0 {
+		t.Fatalf("unrelated articles formed groups: %+v", groups)
+	}
+
+	group("a3", "Major earthquake strikes off Chile coast")
+	groups := recent()
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d: %+v", len(groups), groups)
+	}
+	members, _ := engine.store.GetGroupArticles(groups[0].ID)
+	if len(members) != 2 {
+		t.Errorf("earthquake group has %d articles, want 2", len(members))
+	}
+	ungrouped, _ := engine.store.GetUngroupedArticles(1, 10, 0)
+	if len(ungrouped) != 1 || ungrouped[0].GUID != "a2" {
+		t.Errorf("ungrouped = %+v, want only a2", ungrouped)
+	}
+}
+
+func TestVisitCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		last, prev time.Time
+		want       time.Time
+	}{
+		{"first visit", time.Time{}, time.Time{}, now.Add(-firstVisitWindow)},
+		{"reload in first visit", now.Add(-time.Minute), time.Time{}, now.Add(-firstVisitWindow)},
+		{"new visit", now.Add(-2 * time.Hour), now.Add(-48 * time.Hour), now.Add(-2 * time.Hour)},
+		{"reload in later visit", now.Add(-time.Minute), now.Add(-2 * time.Hour), now.Add(-2 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := visitCutoff(tt.last, tt.prev, now); !got.Equal(tt.want) {
+				t.Errorf("visitCutoff = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRescoreUnread(t *testing.T) {
+	// Fake curation model: an article scores high only when the keyword
+	// written for it appears in the prompt.
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		prompt := string(body)
+		score := 2
+		if (strings.Contains(prompt, "Go generics") && strings.Contains(prompt, "gopher")) ||
+			(strings.Contains(prompt, "Rust lifetimes") && strings.Contains(prompt, "rustacean")) {
+			score = 9
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"{\"interest_score\": %d, \"reasoning\": \"test\"}"}}]}`, score)
+	}))
+	defer srv.Close()
+
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+		Keywords:      []string{"gopher"},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer engine.Close()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	now := time.Now()
+	add := func(guid, title string, interest, security float64) int64 {
+		t.Helper()
+		id, err := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID, GUID: guid, Title: title,
+			URL: "https://example.com/" + guid, Content: "Article body for " + title, PublishedDate: &now,
+		})
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		reason := "ok"
+		engine.store.UpdateReadState(1, id, false, &interest, &security, &reason) //nolint:errcheck
+		return id
+	}
+	goID := add("go", "Go generics", 9, 9)
+	rustID := add("rust", "Rust lifetimes", 2, 9)
+	readID := add("read", "Rust lifetimes, read", 2, 9)
+	engine.store.UpdateReadState(1, readID, true, nil, nil, nil) //nolint:errcheck
+	add("unsafe", "Rust lifetimes, unsafe", 0, 1)
+
+	if err := engine.SetPreference(1, "keywords", `["rustacean"]`); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	n, err := engine.RescoreUnread(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("RescoreUnread: %v", err)
+	}
+	// The read article and the security failure must not be re-curated, and
+	// no summaries are generated: one model call per rescored article.
+	if n != 2 || calls.Load() != 2 {
+		t.Errorf("rescored %d articles with %d model calls, want 2 and 2", n, calls.Load())
+	}
+
+	articles, _, raw, err := engine.GetHighInterestArticles(1, 0, 10, 0)
+	if err != nil {
+		t.Fatalf("GetHighInterestArticles: %v", err)
+	}
+	if len(articles) != 3 {
+		t.Fatalf("got %d unread scored articles, want 3", len(articles))
+	}
+	if articles[0].ID != rustID || raw[0] != 9 {
+		t.Errorf("top article = %d (score %.0f), want %d (score 9)", articles[0].ID, raw[0], rustID)
+	}
+	if articles[1].ID != goID || raw[1] != 2 {
+		t.Errorf("second article = %d (score %.0f), want %d (score 2)", articles[1].ID, raw[1], goID)
+	}
+
+	stats, err := engine.GetScoreStats(1)
+	if err != nil {
+		t.Fatalf("GetScoreStats: %v", err)
+	}
+	if stats.Total.SecPass != 3 || stats.Total.SecFail != 1 {
+		t.Errorf("security verdicts changed: %d pass, %d fail", stats.Total.SecPass, stats.Total.SecFail)
+	}
+}
+
+func TestOverrideSecurity(t *testing.T) {
+	var aiCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		aiCalls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"{\"interest_score\": 7, \"reasoning\": \"test\"}"}}]}`)
+	}))
+	defer srv.Close()
+
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer engine.Close()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	now := time.Now()
+	id, err := engine.store.AddArticle(&storage.Article{
+		FeedID: feedID, GUID: "fp", Title: "Prompt engineering tips",
+		URL: "https://example.com/fp", Content: "How to write better prompts.", PublishedDate: &now,
+	})
+	if err != nil {
+		t.Fatalf("AddArticle: %v", err)
+	}
+	zero, sec := 0.0, 2.0
+	reason := "mentions prompts"
+	engine.store.UpdateReadState(1, id, false, &zero, &sec, &reason) //nolint:errcheck
+
+	if articles, _, _, _ := engine.GetHighInterestArticles(1, 1, 10, 0); len(articles) != 0 {
+		t.Fatalf("blocked article should not be in the unread list, got %d", len(articles))
+	}
+
+	// Another user can't override an article outside their feeds.
+	if err := engine.OverrideSecurity(context.Background(), 2, id); err == nil {
+		t.Error("expected an error overriding another user's article")
+	}
+	if n := aiCalls.Load(); n != 0 {
+		t.Errorf("refused override made %d AI calls", n)
+	}
+
+	if err := engine.OverrideSecurity(context.Background(), 1, id); err != nil {
+		t.Fatalf("OverrideSecurity: %v", err)
+	}
+	articles, _, raw, err := engine.GetHighInterestArticles(1, 1, 10, 0)
+	if err != nil {
+		t.Fatalf("GetHighInterestArticles: %v", err)
+	}
+	if len(articles) != 1 || articles[0].ID != id || raw[0] != 7 {
+		t.Fatalf("overridden article should be unread with interest 7, got %d articles", len(articles))
+	}
+	if blocked, _ := engine.GetBlockedArticles(1, 10, 0); len(blocked) != 0 {
+		t.Errorf("overridden article should leave the blocked list, got %d", len(blocked))
+	}
+
+	calls := aiCalls.Load()
+	if err := engine.OverrideSecurity(context.Background(), 1, id); err == nil {
+		t.Error("expected an error overriding an article that isn't blocked")
+	}
+	if n := aiCalls.Load(); n != calls {
+		t.Errorf("override of an unblocked article made %d AI calls", n-calls)
+	}
+}
+
+func TestMergeGroupsOwnership(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	now := time.Now()
+	id1, _ := engine.store.AddArticle(&storage.Article{
+		FeedID: feedID, GUID: "g1", Title: "Article One",
+		URL: "https://example.com/1", PublishedDate: &now,
+	})
+	id2, _ := engine.store.AddArticle(&storage.Article{
+		FeedID: feedID, GUID: "g2", Title: "Article Two",
+		URL: "https://example.com/2", PublishedDate: &now,
+	})
+
+	src, _ := engine.store.CreateArticleGroup(1, "Source")
+	dst, _ := engine.store.CreateArticleGroup(1, "Destination")
+	other, _ := engine.store.CreateArticleGroup(2, "Someone Else")
+	engine.store.AddArticleToGroup(src, id1)
+	engine.store.AddArticleToGroup(dst, id2)
+
+	if err := engine.MergeGroups(context.Background(), 1, src, other); err == nil {
+		t.Error("merging into another user's group should fail")
+	}
+	if err := engine.MergeGroups(context.Background(), 1, src, src); err == nil {
+		t.Error("merging a group into itself should fail")
+	}
+	if err := engine.MergeGroups(context.Background(), 1, src, dst); err != nil {
+		t.Fatalf("MergeGroups: %v", err)
+	}
+
+	group, err := engine.GetGroupArticles(dst)
+	if err != nil {
+		t.Fatalf("GetGroupArticles: %v", err)
+	}
+	if group.Count != 2 {
+		t.Errorf("merged group count: got %d, want 2", group.Count)
+	}
+
+	if err := engine.RenameGroup(2, dst, "Hijacked"); err == nil {
+		t.Error("renaming another user's group should fail")
+	}
+	if err := engine.RenameGroup(1, dst, "  "); err == nil {
+		t.Error("renaming to a blank name should fail")
+	}
+}
+
+func TestRegenerateGroupSummary(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"headline\": \"Two reports\", \"summary\": \"Both articles cover the story.\"}"}}]}`))
+	}))
+	defer srv.Close()
+
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer engine.Close()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	now := time.Now()
+	groupID, _ := engine.store.CreateArticleGroup(1, "Story")
+	add := func(guid string, interest float64) int64 {
+		t.Helper()
+		id, err := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID, GUID: guid, Title: "Article " + guid,
+			URL: "https://example.com/" + guid, PublishedDate: &now,
+		})
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		security, reason := 9.0, "ok"
+		engine.store.UpdateReadState(1, id, false, &interest, &security, &reason) //nolint:errcheck
+		engine.store.UpdateArticleAISummary(1, id, "Summary of "+guid)            //nolint:errcheck
+		return id
+	}
+
+	engine.store.AddArticleToGroup(groupID, add("g1", 6)) //nolint:errcheck
+	if err := engine.RegenerateGroupSummary(context.Background(), 1, groupID); err != nil {
+		t.Fatalf("RegenerateGroupSummary: %v", err)
+	}
+	gs, err := engine.store.GetGroupSummary(groupID)
+	if err != nil {
+		t.Fatalf("GetGroupSummary: %v", err)
+	}
+	if gs.ArticleCount != 1 || gs.Summary != "Summary of g1" {
+		t.Errorf("single-article summary = %+v", gs)
+	}
+
+	engine.store.AddArticleToGroup(groupID, add("g2", 9)) //nolint:errcheck
+	if err := engine.RegenerateGroupSummary(context.Background(), 1, groupID); err != nil {
+		t.Fatalf("RegenerateGroupSummary: %v", err)
+	}
+	gs, err = engine.store.GetGroupSummary(groupID)
+	if err != nil {
+		t.Fatalf("GetGroupSummary: %v", err)
+	}
+	if gs.ArticleCount != 2 || gs.Headline != "Two reports" || gs.Summary != "Both articles cover the story." {
+		t.Errorf("regenerated summary = %+v", gs)
+	}
+	if gs.MaxInterestScore == nil || *gs.MaxInterestScore != 9 {
+		t.Errorf("max interest score = %v, want 9", gs.MaxInterestScore)
+	}
+
+	if err := engine.RegenerateGroupSummary(context.Background(), 2, groupID); err == nil {
+		t.Error("regenerating another user's group should fail")
+	}
+}
+
+func TestMarkGroupRead(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	now := time.Now()
+	groupID, _ := engine.store.CreateArticleGroup(1, "Story")
+	for _, guid := range []string{"g1", "g2"} {
+		id, _ := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID, GUID: guid, Title: "Article " + guid,
+			URL: "https://example.com/" + guid, PublishedDate: &now,
+		})
+		interest, security, reason := 8.0, 9.0, "ok"
+		engine.store.UpdateReadState(1, id, false, &interest, &security, &reason)
+		engine.store.AddArticleToGroup(groupID, id)
+	}
+
+	if _, err := engine.MarkGroupRead(2, groupID); err == nil {
+		t.Error("marking another user's group read should fail")
+	}
+
+	n, err := engine.MarkGroupRead(1, groupID)
+	if err != nil {
+		t.Fatalf("MarkGroupRead: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("marked %d articles, want 2", n)
+	}
+	unread, _, _, _ := engine.GetHighInterestArticles(1, 0, 10, 0)
+	if len(unread) != 0 {
+		t.Errorf("got %d unread articles after marking the group read, want 0", len(unread))
+	}
+	// Scores survive, so nothing is queued for the AI pipeline again.
+	if pending, _ := engine.store.GetUnscoredArticleCount(1); pending != 0 {
+		t.Errorf("%d articles queued for rescoring, want 0", pending)
+	}
+
+	if n, _ := engine.MarkGroupRead(1, groupID); n != 0 {
+		t.Errorf("second MarkGroupRead marked %d articles, want 0", n)
+	}
+}
+
+func TestMoveArticleToGroup(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	now := time.Now()
+	var ids []int64
+	for _, guid := range []string{"g1", "g2", "g3"} {
+		id, _ := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID, GUID: guid, Title: "Article " + guid,
+			URL: "https://example.com/" + guid, PublishedDate: &now,
+		})
+		ids = append(ids, id)
+	}
+
+	src, _ := engine.store.CreateArticleGroup(1, "Source")
+	dst, _ := engine.store.CreateArticleGroup(1, "Destination")
+	other, _ := engine.store.CreateArticleGroup(2, "Someone Else")
+	engine.store.AddArticleToGroup(src, ids[0])
+	engine.store.AddArticleToGroup(src, ids[1])
+	engine.store.AddArticleToGroup(dst, ids[2])
+
+	if _, err := engine.MoveArticleToGroup(context.Background(), 1, ids[0], other); err == nil {
+		t.Error("moving into another user's group should fail")
+	}
+	if _, err := engine.MoveArticleToGroup(context.Background(), 2, ids[0], other); err == nil {
+		t.Error("moving an article from an unsubscribed feed should fail")
+	}
+
+	got, err := engine.MoveArticleToGroup(context.Background(), 1, ids[0], dst)
+	if err != nil {
+		t.Fatalf("MoveArticleToGroup: %v", err)
+	}
+	if got != dst {
+		t.Errorf("target group: got %d, want %d", got, dst)
+	}
+	if n, _ := engine.store.GetGroupArticleCount(src); n != 1 {
+		t.Errorf("source count: got %d, want 1", n)
+	}
+	if n, _ := engine.store.GetGroupArticleCount(dst); n != 2 {
+		t.Errorf("destination count: got %d, want 2", n)
+	}
+
+	// Moving the last article out disbands the source; 0 creates a new group.
+	fresh, err := engine.MoveArticleToGroup(context.Background(), 1, ids[1], 0)
+	if err != nil {
+		t.Fatalf("MoveArticleToGroup to new group: %v", err)
+	}
+	if fresh == src || fresh == dst {
+		t.Errorf("expected a new group, got %d", fresh)
+	}
+	if g, _ := engine.store.GetGroup(src); g != nil {
+		t.Error("empty source group should be disbanded")
+	}
+	if n, _ := engine.store.GetGroupArticleCount(fresh); n != 1 {
+		t.Errorf("new group count: got %d, want 1", n)
+	}
+}
+
+func TestGetGroupArticlesNotFound(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	group, err := engine.GetGroupArticles(999)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Empty group is fine, not an error
+	if len(group.Articles) != 0 {
+		t.Errorf("expected 0 articles, got %d", len(group.Articles))
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	if got := engine.InterestThreshold(); got != 8 {
+		t.Fatalf("initial threshold = %v, want 8", got)
+	}
+
+	engine.ReloadConfig(EngineConfig{
+		DBPath:            "/elsewhere/herald.db",
+		InterestThreshold: 6,
+		Keywords:          []string{"golang"},
+		CurationModel:     "llama3",
+	})
+	if got := engine.InterestThreshold(); got != 6 {
+		t.Errorf("threshold after reload = %v, want 6", got)
+	}
+	prefs, err := engine.GetPreferences(1)
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	if prefs.InterestThreshold != 6 || len(prefs.Keywords) != 1 || prefs.Keywords[0].Term != "golang" {
+		t.Errorf("prefs after reload = %+v", prefs)
+	}
+	if engine.config.Thresholds.SecurityScore != 7 {
+		t.Errorf("zero security threshold should keep 7, got %v", engine.config.Thresholds.SecurityScore)
+	}
+	if engine.config.Ollama.CurationModel != "llama3" || engine.config.Ollama.SecurityModel != "gemma4" {
+		t.Errorf("models after reload: security=%q curation=%q",
+			engine.config.Ollama.SecurityModel, engine.config.Ollama.CurationModel)
+	}
+	if engine.dbPath == "/elsewhere/herald.db" {
+		t.Error("reload should not change the database path")
+	}
+
+	// A stored preference still outranks the reloaded config.
+	if err := engine.SetPreference(1, "interest_threshold", "9"); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	engine.ReloadConfig(EngineConfig{UserID: 1, InterestThreshold: 5})
+	if got := engine.InterestThreshold(); got != 9 {
+		t.Errorf("threshold with stored preference = %v, want 9", got)
+	}
+}
+
+func TestSetPreferenceInterestThresholdIsPerUser(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	if err := engine.SetPreference(2, "interest_threshold", "5"); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	if got := engine.InterestThreshold(); got != 8 {
+		t.Errorf("engine threshold after another user's preference = %v, want 8", got)
+	}
+	if prefs, _ := engine.GetPreferences(1); prefs.InterestThreshold != 8 {
+		t.Errorf("user 1 threshold = %v, want the default 8", prefs.InterestThreshold)
+	}
+	if prefs, _ := engine.GetPreferences(2); prefs.InterestThreshold != 5 {
+		t.Errorf("user 2 threshold = %v, want 5", prefs.InterestThreshold)
+	}
+}
+
+// TestReloadConfigConcurrent exercises reloads alongside config reads; run
+// with -race to catch unguarded access.
+func TestReloadConfigConcurrent(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 50 {
+			engine.ReloadConfig(EngineConfig{InterestThreshold: float64(5 + i%3), Keywords: []string{"go"}})
+		}
+	}()
+	for range 50 {
+		engine.InterestThreshold()
+		if _, err := engine.GetPreferences(1); err != nil {
+			t.Fatalf("GetPreferences: %v", err)
+		}
+		if _, err := engine.GetBlockedArticles(1, 10, 0); err != nil {
+			t.Fatalf("GetBlockedArticles: %v", err)
+		}
+	}
+	<-done
+}
+
+func TestGenerateBriefingEmpty(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	briefing, err := engine.GenerateBriefing(1)
+	if err != nil {
+		t.Fatalf("GenerateBriefing: %v", err)
+	}
+	if briefing != "" {
+		t.Errorf("expected empty briefing with no articles, got %q", briefing)
+	}
+}
+
+func TestNotificationQueue(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed", "Feed")
+	var scored []ScoredArticle
+	for i, score := range []float64{9, 3} {
+		id, err := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID,
+			GUID:   fmt.Sprintf("guid-%d", i),
+			Title:  fmt.Sprintf("Article %d", i),
+			URL:    fmt.Sprintf("https://example.com/%d", i),
+		})
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		scored = append(scored, ScoredArticle{
+			Article:       Article{ID: id, Title: fmt.Sprintf("Article %d", i)},
+			InterestScore: score,
+			Safe:          true,
+		})
+	}
+
+	// The default "present" mode neither delivers nor queues.
+	notify, err := engine.RouteNotifications(1, scored)
+	if err != nil {
+		t.Fatalf("RouteNotifications: %v", err)
+	}
+	if len(notify) != 0 {
+		t.Errorf("present mode returned %d articles, want 0", len(notify))
+	}
+
+	if err := engine.SetPreference(1, "notify_when", "always"); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	notify, _ = engine.RouteNotifications(1, scored)
+	if len(notify) != 1 || notify[0].ID != scored[0].ID {
+		t.Errorf("always mode returned %v, want only the high-scoring article", notify)
+	}
+
+	if err := engine.SetPreference(1, "notify_when", "queue"); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	for range 2 {
+		notify, err = engine.RouteNotifications(1, scored)
+		if err != nil {
+			t.Fatalf("RouteNotifications: %v", err)
+		}
+		if len(notify) != 0 {
+			t.Errorf("queue mode returned %d articles for immediate delivery", len(notify))
+		}
+	}
+
+	briefing, err := engine.GenerateBriefing(1)
+	if err != nil {
+		t.Fatalf("GenerateBriefing: %v", err)
+	}
+	if strings.Count(briefing, "## Article 0 (9.0/10)") != 1 {
+		t.Errorf("briefing should list the queued article once, got %q", briefing)
+	}
+	if strings.Contains(briefing, "Article 1") {
+		t.Errorf("briefing should not include the low-scoring article, got %q", briefing)
+	}
+
+	briefing, err = engine.GenerateBriefing(1)
+	if err != nil {
+		t.Fatalf("GenerateBriefing: %v", err)
+	}
+	if briefing != "" {
+		t.Errorf("queue should be empty after a briefing, got %q", briefing)
+	}
+}
+
+func TestPresentNotifications(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed", "Feed")
+	addScored := func(guid string, interest, security float64) int64 {
+		t.Helper()
+		id, err := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID,
+			GUID:   guid,
+			Title:  "Article " + guid,
+			URL:    "https://example.com/" + guid,
+		})
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		if err := engine.store.UpdateReadState(1, id, false, &interest, &security, nil); err != nil {
+			t.Fatalf("UpdateReadState: %v", err)
+		}
+		return id
+	}
+	high := addScored("high", 9, 9)
+	addScored("dull", 3, 9)
+	addScored("unsafe", 9, 2)
+	read := addScored("read", 9, 9)
+	if err := engine.MarkArticleRead(1, read); err != nil {
+		t.Fatalf("MarkArticleRead: %v", err)
+	}
+
+	presented, err := engine.PresentNotifications(1, 10)
+	if err != nil {
+		t.Fatalf("PresentNotifications: %v", err)
+	}
+	if len(presented) != 1 || presented[0].ID != high || presented[0].InterestScore != 9 {
+		t.Fatalf("presented = %+v, want only the high-interest article", presented)
+	}
+
+	// Already presented: nothing new until another article arrives.
+	presented, _ = engine.PresentNotifications(1, 10)
+	if len(presented) != 0 {
+		t.Errorf("second call presented %d articles, want 0", len(presented))
+	}
+	fresh := addScored("fresh", 8, 9)
+	presented, _ = engine.PresentNotifications(1, 10)
+	if len(presented) != 1 || presented[0].ID != fresh {
+		t.Errorf("presented = %+v, want only the new article", presented)
+	}
+
+	// Other modes leave present-mode surfacing alone.
+	addScored("queued", 9, 9)
+	if err := engine.SetPreference(1, "notify_when", "queue"); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	if presented, _ = engine.PresentNotifications(1, 10); presented != nil {
+		t.Errorf("queue mode presented %d articles, want none", len(presented))
+	}
+}
+
+func TestGenerateBriefing_NotifyMinScore(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed", "Feed")
+	for _, score := range []float64{9, 7.5} {
+		id, err := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID,
+			GUID:   fmt.Sprintf("guid-%v", score),
+			Title:  fmt.Sprintf("Scored %v", score),
+			URL:    fmt.Sprintf("https://example.com/%v", score),
+		})
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		if err := engine.store.UpdateReadState(1, id, false, &score, nil, nil); err != nil {
+			t.Fatalf("UpdateReadState: %v", err)
+		}
+	}
+
+	briefing, err := engine.GenerateBriefing(1)
+	if err != nil {
+		t.Fatalf("GenerateBriefing: %v", err)
+	}
+	if !strings.Contains(briefing, "Scored 9") || !strings.Contains(briefing, "Scored 7.5") {
+		t.Errorf("default briefing should include both articles, got %q", briefing)
+	}
+
+	if err := engine.SetPreference(1, "notify_min_score", "8"); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	briefing, err = engine.GenerateBriefing(1)
+	if err != nil {
+		t.Fatalf("GenerateBriefing: %v", err)
+	}
+	if !strings.Contains(briefing, "Scored 9") || strings.Contains(briefing, "Scored 7.5") {
+		t.Errorf("briefing should only include the 9.0 article, got %q", briefing)
+	}
+
+	// The unread list isn't gated by notify_min_score.
+	unread, err := engine.GetUnreadArticles(1, 10, 0)
+	if err != nil {
+		t.Fatalf("GetUnreadArticles: %v", err)
+	}
+	if len(unread) != 2 {
+		t.Errorf("unread = %d articles, want 2", len(unread))
+	}
+}
+
+func TestGenerateBriefingCustomTemplate(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed", "Feed")
+	id, err := engine.store.AddArticle(&storage.Article{
+		FeedID:  feedID,
+		GUID:    "guid-1",
+		Title:   "Scored",
+		URL:     "https://example.com/1",
+		Summary: "Feed summary.",
+	})
+	if err != nil {
+		t.Fatalf("AddArticle: %v", err)
+	}
+	score := 9.0
+	if err := engine.store.UpdateReadState(1, id, false, &score, nil, nil); err != nil {
+		t.Fatalf("UpdateReadState: %v", err)
+	}
+
+	// The default template keeps the original Markdown layout.
+	briefing, err := engine.GenerateBriefing(1)
+	if err != nil {
+		t.Fatalf("GenerateBriefing: %v", err)
+	}
+	if want := "## Scored (9.0/10)\nhttps://example.com/1\nFeed summary.\n\n"; briefing != want {
+		t.Errorf("default briefing = %q, want %q", briefing, want)
+	}
+
+	if err := engine.SetPrompt(1, "briefing", "{{range .Articles}}- {{.Title}} <{{.URL}}>\n{{end}}", nil, nil); err != nil {
+		t.Fatalf("SetPrompt: %v", err)
+	}
+	briefing, err = engine.GenerateBriefing(1)
+	if err != nil {
+		t.Fatalf("GenerateBriefing: %v", err)
+	}
+	if want := "- Scored <https://example.com/1>\n"; briefing != want {
+		t.Errorf("custom briefing = %q, want %q", briefing, want)
+	}
+
+	for _, bad := range []string{"{{range .Articles}}", "{{.Headline}}", "{{range .Articles}}{{.Scroe}}{{end}}"} {
+		if err := engine.SetPrompt(1, "briefing", bad, nil, nil); err == nil {
+			t.Errorf("SetPrompt(%q) should fail validation", bad)
+		}
+	}
+
+	// A template that fails to render leaves the notification queue intact.
+	if err := engine.store.EnqueueNotification(1, id, score); err != nil {
+		t.Fatalf("EnqueueNotification: %v", err)
+	}
+	if err := engine.store.SetUserPrompt(1, "briefing", "{{index .Articles 5}}", nil, nil); err != nil {
+		t.Fatalf("SetUserPrompt: %v", err)
+	}
+	if _, err := engine.GenerateBriefing(1); err == nil {
+		t.Fatal("GenerateBriefing should fail when the template can't render")
+	}
+	queued, err := engine.store.GetQueuedNotifications(1)
+	if err != nil {
+		t.Fatalf("GetQueuedNotifications: %v", err)
+	}
+	if len(queued) != 1 {
+		t.Errorf("queue has %d entries after a failed briefing, want 1", len(queued))
+	}
+}
+
+func TestGenerateCombinedBriefing(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	alice, err := engine.store.CreateUser("alice")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	bob, err := engine.store.CreateUser("bob")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	feedID := subscribeDirect(t, engine, alice, "https://example.com/feed", "Feed")
+	if err := engine.store.SubscribeUserToFeed(bob, feedID); err != nil {
+		t.Fatalf("SubscribeUserToFeed: %v", err)
+	}
+	add := func(guid, title string) int64 {
+		t.Helper()
+		id, err := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID, GUID: guid, Title: title, URL: "https://example.com/" + guid,
+		})
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		return id
+	}
+	score := func(userID, articleID int64, s float64) {
+		t.Helper()
+		if err := engine.store.UpdateReadState(userID, articleID, false, &s, nil, nil); err != nil {
+			t.Fatalf("UpdateReadState: %v", err)
+		}
+	}
+	shared := add("shared", "Shared")
+	solo := add("solo", "Solo")
+	score(alice, shared, 8)
+	score(bob, shared, 9)
+	score(bob, solo, 8.5)
+	score(alice, solo, 2) // below alice's threshold
+
+	briefing, err := engine.GenerateCombinedBriefing(context.Background(), []int64{alice, bob, alice})
+	if err != nil {
+		t.Fatalf("GenerateCombinedBriefing: %v", err)
+	}
+	want := "## Shared (9.0/10)\nhttps://example.com/shared\nFlagged by: alice, bob\n\n" +
+		"## Solo (8.5/10)\nhttps://example.com/solo\nFlagged by: bob\n\n"
+	if briefing != want {
+		t.Errorf("combined briefing = %q, want %q", briefing, want)
+	}
+
+	// Personal briefings don't carry attributions.
+	personal, err := engine.GenerateBriefing(alice)
+	if err != nil {
+		t.Fatalf("GenerateBriefing: %v", err)
+	}
+	if strings.Contains(personal, "Flagged by") {
+		t.Errorf("personal briefing has attributions: %q", personal)
+	}
+}
+
+func TestGetFeedStats(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	// Two feeds, user 1
+	feed1 := subscribeDirect(t, engine, 1, "https://example.com/feed1.xml", "Feed One")
+	feed2 := subscribeDirect(t, engine, 1, "https://example.com/feed2.xml", "Feed Two")
+
+	now := time.Now()
+
+	// Feed 1: 3 articles
+	id1, _ := engine.store.AddArticle(&storage.Article{
+		FeedID: feed1, GUID: "f1-1", Title: "Article 1",
+		URL: "https://example.com/1", PublishedDate: &now,
+	})
+	engine.store.AddArticle(&storage.Article{
+		FeedID: feed1, GUID: "f1-2", Title: "Article 2",
+		URL: "https://example.com/2", PublishedDate: &now,
+	})
+	engine.store.AddArticle(&storage.Article{
+		FeedID: feed1, GUID: "f1-3", Title: "Article 3",
+		URL: "https://example.com/3", PublishedDate: &now,
+	})
+
+	// Feed 2: 2 articles
+	id4, _ := engine.store.AddArticle(&storage.Article{
+		FeedID: feed2, GUID: "f2-1", Title: "Article 4",
+		URL: "https://example.com/4", PublishedDate: &now,
+	})
+	engine.store.AddArticle(&storage.Article{
+		FeedID: feed2, GUID: "f2-2", Title: "Article 5",
+		URL: "https://example.com/5", PublishedDate: &now,
+	})
+
+	// Mark one article read in feed 1
+	engine.store.UpdateReadState(1, id1, true, nil, nil, nil)
+
+	// Summarize one article in feed 2
+	engine.store.UpdateArticleAISummary(1, id4, "Summary of article 4")
+
+	result, err := engine.GetFeedStats(1)
+	if err != nil {
+		t.Fatalf("GetFeedStats: %v", err)
+	}
+
+	if len(result.Feeds) != 2 {
+		t.Fatalf("expected 2 feeds, got %d", len(result.Feeds))
+	}
+
+	// Feeds are ordered by title
+	f1 := result.Feeds[0] // "Feed One"
+	f2 := result.Feeds[1] // "Feed Two"
+
+	if f1.FeedTitle != "Feed One" || f2.FeedTitle != "Feed Two" {
+		t.Fatalf("unexpected feed order: %q, %q", f1.FeedTitle, f2.FeedTitle)
+	}
+
+	// Feed One: 3 total, 2 unread (1 read), 3 unsummarized
+	if f1.TotalArticles != 3 {
+		t.Errorf("feed1 total: got %d, want 3", f1.TotalArticles)
+	}
+	if f1.UnreadArticles != 2 {
+		t.Errorf("feed1 unread: got %d, want 2", f1.UnreadArticles)
+	}
+	if f1.UnsummarizedArticles != 3 {
+		t.Errorf("feed1 unsummarized: got %d, want 3", f1.UnsummarizedArticles)
+	}
+
+	// Feed Two: 2 total, 2 unread, 1 unsummarized
+	if f2.TotalArticles != 2 {
+		t.Errorf("feed2 total: got %d, want 2", f2.TotalArticles)
+	}
+	if f2.UnreadArticles != 2 {
+		t.Errorf("feed2 unread: got %d, want 2", f2.UnreadArticles)
+	}
+	if f2.UnsummarizedArticles != 1 {
+		t.Errorf("feed2 unsummarized: got %d, want 1", f2.UnsummarizedArticles)
+	}
+
+	// Totals: 5 total, 4 unread, 4 unsummarized
+	if result.Total.TotalArticles != 5 {
+		t.Errorf("total articles: got %d, want 5", result.Total.TotalArticles)
+	}
+	if result.Total.UnreadArticles != 4 {
+		t.Errorf("total unread: got %d, want 4", result.Total.UnreadArticles)
+	}
+	if result.Total.UnsummarizedArticles != 4 {
+		t.Errorf("total unsummarized: got %d, want 4", result.Total.UnsummarizedArticles)
+	}
+}
+
+func TestGetFeedStatsEmpty(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	result, err := engine.GetFeedStats(1)
+	if err != nil {
+		t.Fatalf("GetFeedStats: %v", err)
+	}
+	if len(result.Feeds) != 0 {
+		t.Errorf("expected 0 feeds, got %d", len(result.Feeds))
+	}
+	if result.Total.TotalArticles != 0 {
+		t.Errorf("expected 0 total, got %d", result.Total.TotalArticles)
+	}
+}
+
+func TestGetReadingStats(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	articleID, err := engine.store.AddArticle(&storage.Article{
+		FeedID: feedID,
+		GUID:   "read-1",
+		Title:  "Read Article",
+		URL:    "https://example.com/article/1",
+	})
+	if err != nil {
+		t.Fatalf("AddArticle: %v", err)
+	}
+	if err := engine.MarkArticleRead(1, articleID); err != nil {
+		t.Fatalf("MarkArticleRead: %v", err)
+	}
+
+	// Three days counting today: the quiet days are filled in with zeros.
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -2)
+	stats, err := engine.GetReadingStats(1, since)
+	if err != nil {
+		t.Fatalf("GetReadingStats: %v", err)
+	}
+	if stats.Read != 1 {
+		t.Errorf("read = %d, want 1", stats.Read)
+	}
+	if len(stats.Daily) != 3 {
+		t.Fatalf("got %d days, want 3", len(stats.Daily))
+	}
+	if stats.Daily[0].Count != 0 || stats.Daily[2].Count != 1 {
+		t.Errorf("daily = %+v, want the read on the last day", stats.Daily)
+	}
+	if today := time.Now().UTC().Format(time.DateOnly); stats.Daily[2].Date != today {
+		t.Errorf("last day = %s, want %s", stats.Daily[2].Date, today)
+	}
+}
+
+func TestClose(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	_ = cleanup // don't use cleanup, test Close directly
+
+	err := engine.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+// --- Filter rules tests ---
+
+func TestFilterRulesCRUD(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	userID := int64(1)
 
-	userID := int64(1)
-
 	// No rules initially
 	rules, err := engine.GetFilterRules(userID, nil)
 	if err != nil {
@@ -472,43 +1886,217 @@ func TestFilterRulesCRUD(t *testing.T) {
 	if err := engine.UpdateFilterRule(id, 10); err != nil {
 		t.Fatalf("UpdateFilterRule: %v", err)
 	}
-	rules, _ = engine.GetFilterRules(userID, nil)
-	if rules[0].Score != 10 {
-		t.Errorf("expected score 10, got %d", rules[0].Score)
+	rules, _ = engine.GetFilterRules(userID, nil)
+	if rules[0].Score != 10 {
+		t.Errorf("expected score 10, got %d", rules[0].Score)
+	}
+
+	// Delete
+	if err := engine.DeleteFilterRule(id); err != nil {
+		t.Fatalf("DeleteFilterRule: %v", err)
+	}
+	rules, _ = engine.GetFilterRules(userID, nil)
+	if len(rules) != 0 {
+		t.Errorf("expected 0 rules after delete, got %d", len(rules))
+	}
+}
+
+func TestFilterRuleValidation(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	// Invalid axis
+	_, err := engine.AddFilterRule(1, FilterRule{Axis: "bogus", Value: "x", Score: 1})
+	if err == nil {
+		t.Fatal("expected error for invalid axis")
+	}
+
+	// Empty value
+	_, err = engine.AddFilterRule(1, FilterRule{Axis: "author", Value: "", Score: 1})
+	if err == nil {
+		t.Fatal("expected error for empty value")
+	}
+
+	// Block rules cannot also carry a score
+	_, err = engine.AddFilterRule(1, FilterRule{Axis: "author", Value: "x", Score: 3, Block: true})
+	if err == nil {
+		t.Fatal("expected error for block rule with a score")
+	}
+
+	// Valid axes
+	for _, axis := range []string{"author", "category", "tag", "domain", "title"} {
+		_, err := engine.AddFilterRule(1, FilterRule{Axis: axis, Value: "test", Score: 1})
+		if err != nil {
+			t.Errorf("AddFilterRule(%s): %v", axis, err)
+		}
+	}
+}
+
+func TestFeedSettingsRequireSubscription(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed", "Feed")
+
+	if err := engine.SetFeedDedupeByURL(2, feedID, true); err == nil {
+		t.Error("a non-subscriber should not be able to switch dedupe mode")
+	}
+	if err := engine.SetFeedMaxArticles(2, feedID, 1); err == nil {
+		t.Error("a non-subscriber should not be able to cap the feed's articles")
+	}
+	if on, _ := engine.store.GetFeedDedupeByURL(feedID); on {
+		t.Error("refused dedupe change was applied")
+	}
+	if n, _ := engine.store.GetFeedMaxArticles(feedID); n != 0 {
+		t.Errorf("refused max articles change was applied: %d", n)
+	}
+
+	if err := engine.SetFeedDedupeByURL(1, feedID, true); err != nil {
+		t.Fatalf("SetFeedDedupeByURL: %v", err)
+	}
+	if err := engine.SetFeedMaxArticles(1, feedID, 50); err != nil {
+		t.Fatalf("SetFeedMaxArticles: %v", err)
+	}
+	if n, _ := engine.store.GetFeedMaxArticles(feedID); n != 50 {
+		t.Errorf("max articles = %d, want 50", n)
+	}
+}
+
+func TestFeedContentSourceSummary(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed", "Inverted")
+	articleID, err := engine.store.AddArticle(&storage.Article{
+		FeedID:  feedID,
+		GUID:    "inverted-1",
+		Title:   "Inverted",
+		URL:     "https://example.com/1",
+		Content: "<p>Share this post</p>",
+		Summary: "The article text the feed put in its summary.",
+	})
+	if err != nil {
+		t.Fatalf("AddArticle: %v", err)
+	}
+	stored, _ := engine.store.GetArticle(articleID)
+	if got := engine.articleText(*stored); got != stored.Content {
+		t.Errorf("default articleText = %q, want content", got)
+	}
+
+	if err := engine.SetFeedContentSource(2, feedID, "longest"); err == nil {
+		t.Error("a non-subscriber should not be able to change the content source")
+	}
+	if err := engine.SetFeedContentSource(1, feedID, "summary"); err != nil {
+		t.Fatalf("SetFeedContentSource: %v", err)
+	}
+	if got := engine.articleText(*stored); got != stored.Summary {
+		t.Errorf("articleText = %q, want summary", got)
+	}
+	if got := engine.ArticleBody(&Article{FeedID: feedID, Content: stored.Content, Summary: stored.Summary}); got != stored.Summary {
+		t.Errorf("ArticleBody = %q, want summary", got)
+	}
+}
+
+func TestImportExportFilterRules(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	if _, err := engine.AddFilterRule(1, FilterRule{Axis: "author", Value: "Alice", Score: 5}); err != nil {
+		t.Fatalf("AddFilterRule: %v", err)
+	}
+
+	// One rule duplicates Alice; the other two are new.
+	added, skipped, err := engine.ImportFilterRules(1, []FilterRule{
+		{Axis: "author", Value: "Alice", Score: 2},
+		{Axis: "domain", Value: ".Example.com", Score: -3},
+		{Axis: "title", Value: "sponsored", Block: true},
+	})
+	if err != nil {
+		t.Fatalf("ImportFilterRules: %v", err)
+	}
+	if added != 2 || skipped != 1 {
+		t.Errorf("added %d skipped %d, want 2 and 1", added, skipped)
+	}
+	rules, _ := engine.GetFilterRules(1, nil)
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 rules, got %d", len(rules))
+	}
+	for _, r := range rules {
+		if r.Axis == "author" && r.Score != 5 {
+			t.Errorf("duplicate import changed the existing rule's score to %d", r.Score)
+		}
+		if r.Axis == "domain" && r.Value != "example.com" {
+			t.Errorf("imported domain not normalized: %q", r.Value)
+		}
+	}
+
+	// An invalid rule rejects the whole batch.
+	if _, _, err := engine.ImportFilterRules(1, []FilterRule{
+		{Axis: "tag", Value: "go", Score: 1},
+		{Axis: "bogus", Value: "x", Score: 1},
+	}); err == nil {
+		t.Error("expected an error for an invalid rule")
+	}
+	if rules, _ := engine.GetFilterRules(1, nil); len(rules) != 3 {
+		t.Errorf("rejected import added rules: got %d, want 3", len(rules))
 	}
 
-	// Delete
-	if err := engine.DeleteFilterRule(id); err != nil {
-		t.Fatalf("DeleteFilterRule: %v", err)
+	// The export round-trips into another user's rules.
+	data, err := engine.ExportFilterRules(1)
+	if err != nil {
+		t.Fatalf("ExportFilterRules: %v", err)
 	}
-	rules, _ = engine.GetFilterRules(userID, nil)
-	if len(rules) != 0 {
-		t.Errorf("expected 0 rules after delete, got %d", len(rules))
+	var exported []FilterRule
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("decode export: %v", err)
+	}
+	added, skipped, err = engine.ImportFilterRules(2, exported)
+	if err != nil {
+		t.Fatalf("ImportFilterRules for user 2: %v", err)
+	}
+	if added != 3 || skipped != 0 {
+		t.Errorf("round trip: added %d skipped %d, want 3 and 0", added, skipped)
 	}
 }
 
-func TestFilterRuleValidation(t *testing.T) {
+func TestFeedFetchHeadersScoping(t *testing.T) {
 	engine, cleanup := newTestEngine(t)
 	defer cleanup()
 
-	// Invalid axis
-	_, err := engine.AddFilterRule(1, FilterRule{Axis: "bogus", Value: "x", Score: 1})
-	if err == nil {
-		t.Fatal("expected error for invalid axis")
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/private.xml", "Private")
+	headers := map[string]string{"Authorization": "Bearer s3cret"}
+
+	// A user who doesn't subscribe can neither read nor set the headers.
+	if err := engine.SetFeedFetchHeaders(2, feedID, headers); err == nil {
+		t.Error("SetFeedFetchHeaders by a non-subscriber should fail")
+	}
+	if _, err := engine.GetFeedFetchHeaders(2, feedID); err == nil {
+		t.Error("GetFeedFetchHeaders by a non-subscriber should fail")
 	}
 
-	// Empty value
-	_, err = engine.AddFilterRule(1, FilterRule{Axis: "author", Value: "", Score: 1})
-	if err == nil {
-		t.Fatal("expected error for empty value")
+	if err := engine.SetFeedFetchHeaders(1, feedID, headers); err != nil {
+		t.Fatalf("SetFeedFetchHeaders: %v", err)
+	}
+	got, err := engine.GetFeedFetchHeaders(1, feedID)
+	if err != nil || got["Authorization"] != "Bearer s3cret" {
+		t.Errorf("GetFeedFetchHeaders = %v, %v", got, err)
 	}
 
-	// Valid axes
-	for _, axis := range []string{"author", "category", "tag"} {
-		_, err := engine.AddFilterRule(1, FilterRule{Axis: axis, Value: "test", Score: 1})
-		if err != nil {
-			t.Errorf("AddFilterRule(%s): %v", axis, err)
-		}
+	// Nobody else can subscribe to a feed fetched with user 1's credentials.
+	if err := engine.store.SubscribeUserToFeed(2, feedID); !errors.Is(err, ErrPrivateFeed) {
+		t.Errorf("subscribing user 2 = %v, want ErrPrivateFeed", err)
+	}
+	if err := engine.SubscribeFeed(2, "https://example.com/private.xml", ""); !errors.Is(err, ErrPrivateFeed) {
+		t.Errorf("SubscribeFeed for user 2 = %v, want ErrPrivateFeed", err)
+	}
+
+	// Headers can't be set on a feed shared with another user.
+	shared := subscribeDirect(t, engine, 1, "https://example.com/shared.xml", "Shared")
+	if err := engine.store.SubscribeUserToFeed(2, shared); err != nil {
+		t.Fatalf("SubscribeUserToFeed: %v", err)
+	}
+	if err := engine.SetFeedFetchHeaders(1, shared, headers); err == nil {
+		t.Error("SetFeedFetchHeaders on a shared feed should fail")
 	}
 }
 
@@ -574,6 +2162,294 @@ func TestFilterThresholdPreference(t *testing.T) {
 	}
 }
 
+func TestKeywordsPreference(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	// Legacy plain array: default weights, and re-encodes as a plain array.
+	if err := engine.SetPreference(1, "keywords", `["security", "golang"]`); err != nil {
+		t.Fatalf("SetPreference legacy: %v", err)
+	}
+	prefs, err := engine.GetPreferences(1)
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	want := Keywords{{Term: "security", Weight: 1}, {Term: "golang", Weight: 1}}
+	if !slices.Equal(prefs.Keywords, want) {
+		t.Errorf("legacy keywords = %v, want %v", prefs.Keywords, want)
+	}
+	if b, _ := json.Marshal(prefs.Keywords); string(b) != `["security","golang"]` {
+		t.Errorf("legacy keywords marshal = %s, want a plain string array", b)
+	}
+
+	// Weighted form, mixed with a bare term.
+	if err := engine.SetPreference(1, "keywords", `[{"term": "golang", "weight": 3}, "news", {"term": "rust"}]`); err != nil {
+		t.Fatalf("SetPreference weighted: %v", err)
+	}
+	prefs, _ = engine.GetPreferences(1)
+	want = Keywords{{Term: "golang", Weight: 3}, {Term: "news", Weight: 1}, {Term: "rust", Weight: 1}}
+	if !slices.Equal(prefs.Keywords, want) {
+		t.Errorf("weighted keywords = %v, want %v", prefs.Keywords, want)
+	}
+	b, _ := json.Marshal(prefs.Keywords)
+	var back Keywords
+	if err := json.Unmarshal(b, &back); err != nil || !slices.Equal(back, want) {
+		t.Errorf("weighted keywords round-trip via %s = %v, %v", b, back, err)
+	}
+
+	for _, bad := range []string{
+		`"golang"`,
+		`[42]`,
+		`[{"term": "", "weight": 2}]`,
+		`[{"term": "golang", "weight": 0}]`,
+		`[{"term": "golang", "weight": 11}]`,
+	} {
+		if err := engine.SetPreference(1, "keywords", bad); err == nil {
+			t.Errorf("SetPreference(%s): expected error", bad)
+		}
+	}
+}
+
+func TestAutostarMinScore(t *testing.T) {
+	// Fake model that passes every article and scores "Great" titles 9.5
+	// and everything else 6.
+	srv := newFakeChat(t, func(prompt string) string {
+		switch {
+		case strings.Contains(prompt, "security filter"):
+			return `{"safe": true, "score": 9, "reasoning": "ok"}`
+		case strings.Contains(prompt, "news curator") && strings.Contains(prompt, "Great"):
+			return `{"interest_score": 9.5, "reasoning": "test"}`
+		case strings.Contains(prompt, "news curator"):
+			return `{"interest_score": 6, "reasoning": "test"}`
+		}
+		return "A short summary of the article."
+	})
+
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer engine.Close()
+
+	if err := engine.SetPreference(1, "autostar_min_score", "nine"); err == nil {
+		t.Error("non-numeric autostar_min_score was accepted")
+	}
+	if err := engine.SetPreference(1, "autostar_min_score", "9"); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	body := strings.Repeat("Long enough to be worth scoring. ", 10)
+	add := func(guid, title string) int64 {
+		t.Helper()
+		id, err := engine.store.AddArticle(&storage.Article{
+			FeedID: feedID, GUID: guid, Title: title, URL: "https://example.com/" + guid, Content: body,
+		})
+		if err != nil {
+			t.Fatalf("AddArticle: %v", err)
+		}
+		return id
+	}
+	great := add("as1", "Great article")
+	meh := add("as2", "Ordinary article")
+
+	if _, err := engine.ProcessNewArticles(context.Background(), 1); err != nil {
+		t.Fatalf("ProcessNewArticles: %v", err)
+	}
+	starred, err := engine.store.GetStarredArticleIDsForUser(1)
+	if err != nil {
+		t.Fatalf("GetStarredArticleIDsForUser: %v", err)
+	}
+	if !slices.Contains(starred, great) {
+		t.Errorf("article scored 9.5 was not starred (starred: %v)", starred)
+	}
+	if slices.Contains(starred, meh) {
+		t.Errorf("article scored 6 was starred (starred: %v)", starred)
+	}
+}
+
+func TestCurationPromptFeedTitle(t *testing.T) {
+	// Fake model that passes every article and records the curation prompt.
+	var prompt atomic.Value
+	srv := newFakeChat(t, func(sent string) string {
+		switch {
+		case strings.Contains(sent, "security filter"):
+			return `{"safe": true, "score": 9, "reasoning": "ok"}`
+		case strings.Contains(sent, "news curator"):
+			prompt.Store(sent)
+			return `{"interest_score": 6, "reasoning": "test"}`
+		}
+		return "A short summary of the article."
+	})
+
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer engine.Close()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Example Wire")
+	subscribeDirect(t, engine, 1, "https://example.com/other.xml", "Other Feed")
+	if err := engine.RenameUserFeed(1, feedID, "Trusted Wire"); err != nil {
+		t.Fatalf("RenameUserFeed: %v", err)
+	}
+	engine.store.AddArticle(&storage.Article{ //nolint:errcheck
+		FeedID: feedID, GUID: "ft1", Title: "Wire story", URL: "https://example.com/ft1",
+		Content: strings.Repeat("Long enough to be worth scoring. ", 10),
+	})
+
+	if _, err := engine.ProcessNewArticles(context.Background(), 1); err != nil {
+		t.Fatalf("ProcessNewArticles: %v", err)
+	}
+	sent, _ := prompt.Load().(string)
+	if !strings.Contains(sent, "Source feed: Trusted Wire") {
+		t.Errorf("curation prompt does not name the article's feed:\n%s", sent)
+	}
+}
+
+func TestFeedStripPatterns(t *testing.T) {
+	// Fake model that passes every article and records the security,
+	// summary and curation prompts.
+	var securityPrompt, summaryPrompt, curationPrompt atomic.Value
+	srv := newFakeChat(t, func(sent string) string {
+		switch {
+		case strings.Contains(sent, "security filter"):
+			securityPrompt.Store(sent)
+			return `{"safe": true, "score": 9, "reasoning": "ok"}`
+		case strings.Contains(sent, "news curator"):
+			curationPrompt.Store(sent)
+			return `{"interest_score": 6, "reasoning": "test"}`
+		case strings.Contains(sent, "Output the summary only"):
+			summaryPrompt.Store(sent)
+			return "A short summary."
+		}
+		return `{"is_related": false, "existing_groups": [], "create_group": false, "reasoning": "none"}`
+	})
+
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer engine.Close()
+
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Spammy Feed")
+	if err := engine.SetFeedStripPatterns(2, feedID, []string{`anything`}); err == nil {
+		t.Error("SetFeedStripPatterns accepted a user not subscribed to the feed")
+	}
+	if err := engine.SetFeedStripPatterns(1, feedID, []string{`(?s)This article first appeared on .*?\.`}); err != nil {
+		t.Fatalf("SetFeedStripPatterns: %v", err)
+	}
+	if err := engine.SetFeedStripPatterns(1, feedID, []string{`(unclosed`}); err == nil {
+		t.Error("SetFeedStripPatterns accepted an invalid regexp")
+	}
+	if err := engine.SetFeedStripPatterns(1, feedID, []string{strings.Repeat("a", storage.MaxStripPatternLength+1)}); err == nil {
+		t.Error("SetFeedStripPatterns accepted an overlong pattern")
+	}
+	if patterns, _ := engine.GetFeedStripPatterns(feedID); len(patterns) != 1 {
+		t.Fatalf("patterns = %q, want the first valid set kept", patterns)
+	}
+
+	body := strings.Repeat("Long enough to be worth scoring. ", 10)
+	engine.store.AddArticle(&storage.Article{ //nolint:errcheck
+		FeedID: feedID, GUID: "sp1", Title: "Syndicated story", URL: "https://example.com/sp1",
+		Content: "This article first appeared on Example Syndication. " + body,
+	})
+
+	if _, err := engine.ProcessNewArticles(context.Background(), 1); err != nil {
+		t.Fatalf("ProcessNewArticles: %v", err)
+	}
+	for name, v := range map[string]*atomic.Value{"summary": &summaryPrompt, "curation": &curationPrompt} {
+		sent, _ := v.Load().(string)
+		if !strings.Contains(sent, "Long enough to be worth scoring.") {
+			t.Errorf("%s prompt lacks the article body:\n%s", name, sent)
+		}
+		if strings.Contains(sent, "first appeared on") {
+			t.Errorf("%s prompt still contains the boilerplate:\n%s", name, sent)
+		}
+	}
+	// The security check screens the text as the feed sent it.
+	if sent, _ := securityPrompt.Load().(string); !strings.Contains(sent, "first appeared on") {
+		t.Errorf("security prompt lacks the unstripped text:\n%s", sent)
+	}
+}
+
+func TestAvoidKeywordsPreference(t *testing.T) {
+	// Fake endpoint that records the curation prompt it was sent.
+	var prompt atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct{ Content string } `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		if len(req.Messages) > 0 {
+			prompt.Store(req.Messages[len(req.Messages)-1].Content)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"interest_score\": 1, \"reasoning\": \"crypto\"}"}}]}`))
+	}))
+	defer srv.Close()
+
+	engine, err := NewEngine(EngineConfig{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		OllamaBaseURL: srv.URL,
+		Keywords:      []string{"security"},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+	defer engine.Close()
+
+	if err := engine.SetPreference(1, "avoid_keywords", `["crypto", {"term": "NFT", "weight": 3}]`); err != nil {
+		t.Fatalf("SetPreference: %v", err)
+	}
+	prefs, err := engine.GetPreferences(1)
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	want := Keywords{{Term: "crypto", Weight: 1}, {Term: "NFT", Weight: 3}}
+	if !slices.Equal(prefs.AvoidKeywords, want) {
+		t.Errorf("avoid keywords = %v, want %v", prefs.AvoidKeywords, want)
+	}
+	if want := (Keywords{{Term: "security", Weight: 1}}); !slices.Equal(prefs.Keywords, want) {
+		t.Errorf("interest keywords = %v, want %v unchanged", prefs.Keywords, want)
+	}
+
+	// Another user's preferences are unaffected.
+	if other, _ := engine.GetPreferences(2); len(other.AvoidKeywords) != 0 {
+		t.Errorf("user 2 avoid keywords = %v, want none", other.AvoidKeywords)
+	}
+
+	for _, bad := range []string{`"crypto"`, `[{"term": " "}]`, `[{"term": "crypto", "weight": -1}]`} {
+		if err := engine.SetPreference(1, "avoid_keywords", bad); err == nil {
+			t.Errorf("SetPreference(%s): expected error", bad)
+		}
+	}
+
+	// Both lists reach the curation prompt.
+	feedID := subscribeDirect(t, engine, 1, "https://example.com/feed.xml", "Test Feed")
+	id, _ := engine.store.AddArticle(&storage.Article{
+		FeedID: feedID, GUID: "coin", Title: "New coin launches", URL: "https://example.com/coin", Content: "Token sale.",
+	})
+	interest, security := 8.0, 9.0
+	engine.store.UpdateReadState(1, id, false, &interest, &security, nil) //nolint:errcheck
+	if _, err := engine.RescoreUnread(context.Background(), 1); err != nil {
+		t.Fatalf("RescoreUnread: %v", err)
+	}
+	got, _ := prompt.Load().(string)
+	if !strings.Contains(got, "User interests: security\n") || !strings.Contains(got, "Topics to avoid: crypto, NFT (weight 3)\n") {
+		t.Errorf("curation prompt should list interests and topics to avoid, got:\n%s", got)
+	}
+}
+
 func TestSummarizationConfigDefaults(t *testing.T) {
 	cfg := storage.DefaultConfig()
 	if cfg.Summarization.MinArticleLength != 200 {
@@ -613,3 +2489,306 @@ func TestLooksLikeGarbage(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncOPML(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	// An existing subscription, renamed and filed by the user.
+	krebs := subscribeDirect(t, engine, 1, "https://example.com/krebs.xml", "My Krebs")
+	if err := engine.SetFeedFolder(1, krebs, "Security"); err != nil {
+		t.Fatalf("SetFeedFolder: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "master.opml")
+	writeOPML := func(urls ...string) {
+		t.Helper()
+		var b strings.Builder
+		b.WriteString(`<?xml version="1.0"?><opml version="2.0"><body><outline text="News">`)
+		for _, u := range urls {
+			fmt.Fprintf(&b, `<outline type="rss" text="Master title" xmlUrl="%s"/>`, u)
+		}
+		b.WriteString(`</outline></body></opml>`)
+		if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+			t.Fatalf("write OPML: %v", err)
+		}
+	}
+	sync := func(wantAdded, wantUnchanged int) {
+		t.Helper()
+		added, unchanged, err := engine.SyncOPML(path, 1)
+		if err != nil {
+			t.Fatalf("SyncOPML: %v", err)
+		}
+		if added != wantAdded || unchanged != wantUnchanged {
+			t.Errorf("SyncOPML = %d added, %d unchanged; want %d, %d", added, unchanged, wantAdded, wantUnchanged)
+		}
+	}
+
+	// The master lists the existing feed under an equivalent URL.
+	writeOPML("http://www.example.com/krebs.xml", "https://example.com/dev.xml")
+	sync(1, 1)
+	sync(0, 2)
+
+	// One feed added to the master: exactly that one is subscribed.
+	writeOPML("http://www.example.com/krebs.xml", "https://example.com/dev.xml", "https://example.com/new.xml")
+	sync(1, 2)
+
+	feeds, err := engine.GetUserFeeds(1)
+	if err != nil {
+		t.Fatalf("GetUserFeeds: %v", err)
+	}
+	if len(feeds) != 3 {
+		t.Errorf("subscribed to %d feeds, want 3", len(feeds))
+	}
+	byFolder, _ := engine.store.GetFeedsByFolder(1)
+	for folder, fs := range byFolder {
+		for _, f := range fs {
+			if f.ID == krebs && (folder != "Security" || f.Title != "My Krebs") {
+				t.Errorf("existing feed changed to %q in folder %q", f.Title, folder)
+			}
+		}
+	}
+
+	// Removing a feed from the master leaves the subscription alone.
+	writeOPML("https://example.com/dev.xml")
+	sync(0, 1)
+	if feeds, _ := engine.GetUserFeeds(1); len(feeds) != 3 {
+		t.Errorf("after dropping a feed from the master: %d feeds, want 3", len(feeds))
+	}
+}
+
+func TestExportOPML_RoundTripsFolders(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	krebs := subscribeDirect(t, engine, 1, "https://example.com/krebs.xml", "Krebs")
+	dev := subscribeDirect(t, engine, 1, "https://example.com/dev.xml", "Dev")
+	subscribeDirect(t, engine, 1, "https://example.com/top.xml", "Top")
+	if err := engine.SetFeedFolder(1, krebs, " Technology / Security "); err != nil {
+		t.Fatalf("SetFeedFolder: %v", err)
+	}
+	if err := engine.SetFeedFolder(1, dev, "Technology"); err != nil {
+		t.Fatalf("SetFeedFolder: %v", err)
+	}
+
+	data, err := engine.ExportOPML(1)
+	if err != nil {
+		t.Fatalf("ExportOPML: %v", err)
+	}
+
+	// Importing the export for another user reproduces the folder layout.
+	const other = 2
+	if _, err := engine.ImportOPMLReader(strings.NewReader(string(data)), other, false); err != nil {
+		t.Fatalf("ImportOPMLReader: %v", err)
+	}
+	byFolder, err := engine.store.GetFeedsByFolder(other)
+	if err != nil {
+		t.Fatalf("GetFeedsByFolder: %v", err)
+	}
+	got := make(map[string]string)
+	for folder, feeds := range byFolder {
+		for _, f := range feeds {
+			got[f.URL] = folder
+		}
+	}
+	want := map[string]string{
+		"https://example.com/krebs.xml": "Technology/Security",
+		"https://example.com/dev.xml":   "Technology",
+		"https://example.com/top.xml":   "",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("round-tripped folders = %v, want %v", got, want)
+	}
+}
+
+func TestImportUserConfigIsAtomic(t *testing.T) {
+	engine, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	// Another user fetches this feed with private headers, so subscribing
+	// to it fails after the import's first subscription has been written.
+	alice, _ := engine.store.CreateUser("alice")
+	bob, _ := engine.store.CreateUser("bob")
+	private := subscribeDirect(t, engine, bob, "https://example.com/private.xml", "Private")
+	if err := engine.SetFeedFetchHeaders(bob, private, map[string]string{"Authorization": "Bearer x"}); err != nil {
+		t.Fatalf("SetFeedFetchHeaders: %v", err)
+	}
+
+	export := UserConfigExport{
+		Version: UserConfigExportVersion,
+		Subscriptions: []ExportedSubscription{
+			{URL: "https://example.com/public.xml", Title: "Public"},
+			{URL: "https://example.com/private.xml"},
+		},
+		Preferences: map[string]string{"summary_style": "terse"},
+		Prompts:     []ExportedPrompt{{Type: "briefing", Template: "{{range .Articles}}{{.Title}}\n{{end}}"}},
+		FilterRules: []ExportedFilterRule{{Axis: "author", Value: "Jane Doe", Score: 3}},
+	}
+	_, err := engine.ImportUserConfig(alice, export)
+	if !errors.Is(err, ErrPrivateFeed) {
+		t.Fatalf("ImportUserConfig error = %v, want ErrPrivateFeed", err)
+	}
+
+	if feeds, _ := engine.GetUserFeeds(alice); len(feeds) != 0 {
+		t.Errorf("failed import left %d subscriptions", len(feeds))
+	}
+	if f, _ := engine.store.FindFeedByNormalizedURL("https://example.com/public.xml"); f != nil {
+		t.Error("failed import left the new feed behind")
+	}
+	if prefs, _ := engine.store.GetAllUserPreferences(alice); len(prefs) != 0 {
+		t.Errorf("failed import left preferences %v", prefs)
+	}
+	if p, _ := engine.store.GetUserPrompt(alice, "briefing"); p != "" {
+		t.Errorf("failed import left a briefing prompt %q", p)
+	}
+	if rules, _ := engine.GetFilterRules(alice, nil); len(rules) != 0 {
+		t.Errorf("failed import left %d filter rules", len(rules))
+	}
+}
+
+func TestUserConfigRoundTrip(t *testing.T) {
+	src, cleanup := newTestEngine(t)
+	defer cleanup()
+
+	news := subscribeDirect(t, src, 1, "https://example.com/news.xml", "News")
+	subscribeDirect(t, src, 1, "https://example.com/blog.xml", "Blog")
+	src.RenameUserFeed(1, news, "My News") //nolint:errcheck
+	src.SetFeedFolder(1, news, "Daily")    //nolint:errcheck
+	src.SetFeedMuted(1, news, true)        //nolint:errcheck
+	for key, value := range map[string]string{
+		"keywords":           `["golang", {"term": "rust", "weight": 2}]`,
+		"interest_threshold": "7.5",
+		"summary_style":      "terse",
+	} {
+		if err := src.SetPreference(1, key, value); err != nil {
+			t.Fatalf("SetPreference %s: %v", key, err)
+		}
+	}
+	src.SetUserPreference(1, "opml_sync_token", "secret") //nolint:errcheck
+	temp, model := 0.2, "tiny-model"
+	const briefing = "{{range .Articles}}- {{.Title}} <{{.URL}}>\n{{end}}"
+	if err := src.SetPrompt(1, "briefing", briefing, &temp, &model); err != nil {
+		t.Fatalf("SetPrompt: %v", err)
+	}
+	for _, rule := range []FilterRule{
+		{Axis: "author", Value: "Jane Doe", Score: 3},
+		{FeedID: &news, Axis: "category", Value: "Sports", Block: true},
+	} {
+		if _, err := src.AddFilterRule(1, rule); err != nil {
+			t.Fatalf("AddFilterRule: %v", err)
+		}
+	}
+
+	export, err := src.ExportUserConfig(1)
+	if err != nil {
+		t.Fatalf("ExportUserConfig: %v", err)
+	}
+	if _, ok := export.Preferences["opml_sync_token"]; ok {
+		t.Error("export includes the internal opml_sync_token preference")
+	}
+	// Through JSON, as the CLI would carry it.
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded UserConfigExport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	// A fresh database whose feed IDs don't line up with the source's.
+	dst, cleanupDst := newTestEngine(t)
+	defer cleanupDst()
+	subscribeDirect(t, dst, 1, "https://example.com/other.xml", "Other")
+
+	result, err := dst.ImportUserConfig(1, decoded)
+	if err != nil {
+		t.Fatalf("ImportUserConfig: %v", err)
+	}
+	want := UserConfigImportResult{FeedsAdded: 2, Preferences: 3, Prompts: 1, RulesAdded: 2}
+	if *result != want {
+		t.Errorf("result = %+v, want %+v", *result, want)
+	}
+
+	srcPrefs, _ := src.GetPreferences(1)
+	dstPrefs, _ := dst.GetPreferences(1)
+	if !slices.Equal(dstPrefs.Keywords, srcPrefs.Keywords) || dstPrefs.InterestThreshold != 7.5 || dstPrefs.SummaryStyle != "terse" {
+		t.Errorf("imported preferences = %+v, want %+v", dstPrefs, srcPrefs)
+	}
+
+	prompt, err := dst.GetPrompt(1, "briefing")
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if !prompt.IsCustom || prompt.Template != briefing || prompt.Temperature != temp || prompt.Model != model {
+		t.Errorf("imported prompt = %+v", prompt)
+	}
+
+	var dstNews int64
+	feeds, _ := dst.GetUserFeeds(1)
+	for _, f := range feeds {
+		if f.URL == "https://example.com/news.xml" {
+			dstNews = f.ID
+			if f.Title != "My News" {
+				t.Errorf("imported feed title = %q, want My News", f.Title)
+			}
+		}
+	}
+	if dstNews == 0 || dstNews == news {
+		t.Fatalf("news feed ID in destination = %d (source %d)", dstNews, news)
+	}
+	stats, _ := dst.GetFeedStats(1)
+	for _, s := range stats.Feeds {
+		if s.FeedID == dstNews && (s.Folder != "Daily" || !s.Muted) {
+			t.Errorf("imported feed folder = %q, muted = %v; want Daily, true", s.Folder, s.Muted)
+		}
+	}
+
+	rules, _ := dst.GetFilterRules(1, nil)
+	if len(rules) != 2 {
+		t.Fatalf("imported %d filter rules, want 2", len(rules))
+	}
+	for _, r := range rules {
+		switch r.Axis {
+		case "author":
+			if r.FeedID != nil || r.Value != "Jane Doe" || r.Score != 3 {
+				t.Errorf("author rule = %+v", r)
+			}
+		case "category":
+			if r.FeedID == nil || *r.FeedID != dstNews || !r.Block {
+				t.Errorf("category rule = %+v, want block scoped to feed %d", r, dstNews)
+			}
+		}
+	}
+
+	// Importing again changes nothing.
+	result, err = dst.ImportUserConfig(1, decoded)
+	if err != nil {
+		t.Fatalf("second ImportUserConfig: %v", err)
+	}
+	if result.FeedsAdded != 0 || result.FeedsSkipped != 2 || result.RulesAdded != 0 || result.RulesSkipped != 2 {
+		t.Errorf("second import = %+v, want everything skipped", *result)
+	}
+
+	// An invalid preference rejects the import before anything is written.
+	decoded.Preferences["interest_threshold"] = "high"
+	decoded.Subscriptions = append(decoded.Subscriptions, ExportedSubscription{URL: "https://example.com/new.xml"})
+	if _, err := dst.ImportUserConfig(1, decoded); err == nil {
+		t.Error("import with an invalid preference succeeded")
+	}
+	if feeds, _ := dst.GetUserFeeds(1); len(feeds) != 3 {
+		t.Errorf("rejected import left %d feeds, want 3", len(feeds))
+	}
+
+	// So does a feed URL that isn't http or https.
+	delete(decoded.Preferences, "interest_threshold")
+	for _, bad := range []string{"file:///etc/passwd", "example.com/feed.xml", "https://"} {
+		decoded.Subscriptions[len(decoded.Subscriptions)-1].URL = bad
+		if _, err := dst.ImportUserConfig(1, decoded); err == nil {
+			t.Errorf("import with feed URL %q succeeded", bad)
+		}
+	}
+	if feeds, _ := dst.GetUserFeeds(1); len(feeds) != 3 {
+		t.Errorf("rejected import left %d feeds, want 3", len(feeds))
+	}
+}
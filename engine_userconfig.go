@@ -0,0 +1,260 @@
+package herald
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/matthewjhunter/herald/internal/ai"
+	"github.com/matthewjhunter/herald/internal/storage"
+)
+
+// ExportUserConfig collects the user's subscriptions, preferences, custom
+// prompts and filter rules for ImportUserConfig. Only preferences settable
+// through SetPreference are included, so internal values such as sync
+// tokens stay behind. Filter rules scoped to a feed the user is no longer
+// subscribed to have no effect and are left out.
+func (e *Engine) ExportUserConfig(userID int64) (UserConfigExport, error) {
+	out := UserConfigExport{
+		Version:       UserConfigExportVersion,
+		ExportedAt:    time.Now().UTC(),
+		Subscriptions: []ExportedSubscription{},
+		Preferences:   map[string]string{},
+		Prompts:       []ExportedPrompt{},
+		FilterRules:   []ExportedFilterRule{},
+	}
+
+	feeds, err := e.store.GetUserFeeds(userID)
+	if err != nil {
+		return out, fmt.Errorf("get user feeds: %w", err)
+	}
+	stats, err := e.store.GetFeedStats(userID)
+	if err != nil {
+		return out, fmt.Errorf("get feed stats: %w", err)
+	}
+	byID := make(map[int64]storage.FeedStats, len(stats))
+	for _, s := range stats {
+		byID[s.FeedID] = s
+	}
+	feedURLs := make(map[int64]string, len(feeds))
+	for _, f := range feeds {
+		feedURLs[f.ID] = f.URL
+		out.Subscriptions = append(out.Subscriptions, ExportedSubscription{
+			URL:    f.URL,
+			Title:  f.Title,
+			Folder: byID[f.ID].Folder,
+			Muted:  byID[f.ID].Muted,
+		})
+	}
+
+	prefs, err := e.store.GetAllUserPreferences(userID)
+	if err != nil {
+		return out, fmt.Errorf("get preferences: %w", err)
+	}
+	for key, value := range prefs {
+		if allowedPreferenceKeys[key] {
+			out.Preferences[key] = value
+		}
+	}
+
+	prompts, err := e.store.ListUserPrompts(userID)
+	if err != nil {
+		return out, fmt.Errorf("list prompts: %w", err)
+	}
+	for _, p := range prompts {
+		if allowedPromptTypes[p.PromptType] {
+			out.Prompts = append(out.Prompts, ExportedPrompt{
+				Type:        p.PromptType,
+				Template:    p.PromptTemplate,
+				Temperature: p.Temperature,
+				Model:       p.Model,
+			})
+		}
+	}
+
+	rules, err := e.store.GetFilterRules(userID, nil)
+	if err != nil {
+		return out, fmt.Errorf("get filter rules: %w", err)
+	}
+	for _, r := range rules {
+		rule := ExportedFilterRule{Axis: r.Axis, Value: r.Value, Score: r.Score, Block: r.Block}
+		if r.FeedID != nil {
+			url, ok := feedURLs[*r.FeedID]
+			if !ok {
+				continue
+			}
+			rule.FeedURL = url
+		}
+		out.FilterRules = append(out.FilterRules, rule)
+	}
+	return out, nil
+}
+
+// ImportUserConfig applies an export written by ExportUserConfig to the
+// user, who may live in a different database. Everything is validated
+// before anything is written, and an invalid feed URL, preference, prompt
+// or rule rejects the whole import. The writes run in one transaction, so if
+// one fails, such as subscribing to a feed another user fetches with
+// private headers, nothing is imported.
+//
+// Feeds are subscribed without being fetched; new ones are picked up by the
+// next poll. Preferences and prompts in the export overwrite the user's
+// current values, while anything the export doesn't mention is left alone.
+// Filter rules duplicating an existing rule are skipped.
+func (e *Engine) ImportUserConfig(userID int64, cfg UserConfigExport) (*UserConfigImportResult, error) {
+	if e.readOnly {
+		return nil, ErrReadOnly
+	}
+	if cfg.Version != UserConfigExportVersion {
+		return nil, fmt.Errorf("unsupported export version %d (want %d)", cfg.Version, UserConfigExportVersion)
+	}
+
+	for _, sub := range cfg.Subscriptions {
+		if sub.URL == "" {
+			return nil, fmt.Errorf("subscription without a URL")
+		}
+		if u, err := url.Parse(sub.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("subscription %q: feed URL must be an absolute http or https URL", sub.URL)
+		}
+	}
+	subscribed := make(map[string]bool, len(cfg.Subscriptions))
+	for _, sub := range cfg.Subscriptions {
+		subscribed[storage.NormalizeFeedURL(sub.URL)] = true
+	}
+	keys := slices.Sorted(maps.Keys(cfg.Preferences))
+	for _, key := range keys {
+		if err := validatePreference(key, cfg.Preferences[key]); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range cfg.Prompts {
+		if !allowedPromptTypes[p.Type] {
+			return nil, fmt.Errorf("unknown or restricted prompt type: %q", p.Type)
+		}
+		if err := ai.ValidatePrompt(ai.PromptType(p.Type), p.Template); err != nil {
+			return nil, fmt.Errorf("invalid %s template: %w", p.Type, err)
+		}
+	}
+	for i, r := range cfg.FilterRules {
+		if r.FeedURL != "" && !subscribed[storage.NormalizeFeedURL(r.FeedURL)] {
+			return nil, fmt.Errorf("filter rule %d: feed %s is not among the subscriptions", i+1, r.FeedURL)
+		}
+		if _, err := validFilterRule(userID, FilterRule{Axis: r.Axis, Value: r.Value, Score: r.Score, Block: r.Block}); err != nil {
+			return nil, fmt.Errorf("filter rule %d: %w", i+1, err)
+		}
+	}
+
+	current, err := e.store.GetUserFeeds(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user feeds: %w", err)
+	}
+
+	var result *UserConfigImportResult
+	err = e.store.WithTx(func(tx *storage.Tx) error {
+		result = &UserConfigImportResult{}
+		had := make(map[string]bool, len(current))
+		for _, f := range current {
+			had[storage.NormalizeFeedURL(f.URL)] = true
+		}
+		feedIDs := make(map[string]int64, len(cfg.Subscriptions))
+		for _, sub := range cfg.Subscriptions {
+			key := storage.NormalizeFeedURL(sub.URL)
+			feedID, err := importSubscription(tx, userID, sub)
+			if err != nil {
+				return fmt.Errorf("subscribe %s: %w", sub.URL, err)
+			}
+			feedIDs[key] = feedID
+			if had[key] {
+				result.FeedsSkipped++
+			} else {
+				had[key] = true
+				result.FeedsAdded++
+			}
+		}
+
+		for _, key := range keys {
+			if err := tx.SetUserPreference(userID, key, cfg.Preferences[key]); err != nil {
+				return fmt.Errorf("set preference %s: %w", key, err)
+			}
+			result.Preferences++
+		}
+
+		for _, p := range cfg.Prompts {
+			var model *string
+			if p.Model != "" {
+				model = &p.Model
+			}
+			if err := tx.SetUserPrompt(userID, p.Type, p.Template, p.Temperature, model); err != nil {
+				return fmt.Errorf("set %s prompt: %w", p.Type, err)
+			}
+			result.Prompts++
+		}
+
+		rules := make([]storage.FilterRule, len(cfg.FilterRules))
+		for i, r := range cfg.FilterRules {
+			rule := FilterRule{Axis: r.Axis, Value: r.Value, Score: r.Score, Block: r.Block}
+			if r.FeedURL != "" {
+				feedID := feedIDs[storage.NormalizeFeedURL(r.FeedURL)]
+				rule.FeedID = &feedID
+			}
+			sr, err := validFilterRule(userID, rule)
+			if err != nil {
+				return fmt.Errorf("filter rule %d: %w", i+1, err)
+			}
+			rules[i] = *sr
+		}
+		added, err := tx.ImportFilterRules(rules)
+		if err != nil {
+			return err
+		}
+		result.RulesAdded, result.RulesSkipped = added, len(rules)-added
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importSubscription subscribes the user to an exported feed, adding the
+// feed if this database doesn't have it yet, and applies the exported
+// title, folder and mute state through tx. Returns the feed ID.
+func importSubscription(tx *storage.Tx, userID int64, sub ExportedSubscription) (int64, error) {
+	existing, err := tx.FindFeedByNormalizedURL(sub.URL)
+	if err != nil {
+		return 0, err
+	}
+	var feedID int64
+	if existing != nil {
+		feedID = existing.ID
+	} else {
+		title := sub.Title
+		if title == "" {
+			title = sub.URL
+		}
+		if feedID, err = tx.AddFeed(sub.URL, title, ""); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.SubscribeUserToFeed(userID, feedID); err != nil {
+		return 0, err
+	}
+	if existing != nil && sub.Title != "" && sub.Title != existing.Title {
+		if err := tx.RenameUserFeed(userID, feedID, sub.Title); err != nil {
+			return 0, err
+		}
+	}
+	if sub.Folder != "" {
+		if err := tx.SetFeedFolder(userID, feedID, sub.Folder); err != nil {
+			return 0, err
+		}
+	}
+	if sub.Muted {
+		if err := tx.SetFeedMuted(userID, feedID, true); err != nil {
+			return 0, err
+		}
+	}
+	return feedID, nil
+}
@@ -6,7 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -81,8 +81,8 @@ func (c *openAIClient) tripBreaker(statusCode int) {
 	if c.consecutive4xx >= clientBreakerThreshold && !c.circuitOpen {
 		c.circuitOpen = true
 		c.openedAt = time.Now()
-		log.Printf("herald: circuit breaker OPEN — %d consecutive HTTP %d responses from %s; will retry after %v",
-			c.consecutive4xx, statusCode, c.baseURL, c.breakerCooldown)
+		slog.Warn("circuit breaker open", "event", "ai_breaker_open",
+			"consecutive", c.consecutive4xx, "status", statusCode, "base_url", c.baseURL, "cooldown", c.breakerCooldown)
 	}
 }
 
@@ -107,8 +107,8 @@ func (c *openAIClient) isOpen() bool {
 		return false
 	}
 	if time.Since(c.openedAt) >= c.breakerCooldown {
-		log.Printf("herald: circuit breaker half-open after %v cooldown; allowing probe requests to %s",
-			c.breakerCooldown, c.baseURL)
+		slog.Info("circuit breaker half-open; allowing probe requests", "event", "ai_breaker_half_open",
+			"cooldown", c.breakerCooldown, "base_url", c.baseURL)
 		c.circuitOpen = false
 		c.consecutive4xx = 0
 		return false
@@ -223,8 +223,8 @@ func (c *openAIClient) generate(ctx context.Context, model, prompt string, tempe
 		if len(resultPreview) > 500 {
 			resultPreview = resultPreview[:500] + "...[truncated]"
 		}
-		log.Printf("[DEBUG-AI] model=%s temp=%.1f prompt_len=%d\n--- PROMPT ---\n%s\n--- RESPONSE ---\n%s\n--- END ---",
-			model, temperature, len(prompt), promptPreview, resultPreview)
+		slog.Info("ai call", "event", "ai_debug", "model", model, "temperature", temperature,
+			"prompt_len", len(prompt), "prompt", promptPreview, "response", resultPreview)
 	}
 
 	return result, nil
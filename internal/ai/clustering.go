@@ -2,7 +2,6 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -53,11 +52,10 @@ Rules:
 	callCtx, cancel := p.withCallTimeout(ctx)
 	defer cancel()
 
-	responseText, err := p.client.generate(callCtx, p.curationModel, prompt, 0.3)
+	responseText, err := p.client.generate(callCtx, p.curationModelName(), prompt, 0.3)
 	if err != nil {
 		return nil, fmt.Errorf("clustering failed: %w", err)
 	}
-	responseText = extractJSON(responseText)
 
 	var result struct {
 		Groups []struct {
@@ -66,7 +64,7 @@ Rules:
 		} `json:"groups"`
 	}
 
-	if err := json.Unmarshal([]byte(responseText), &result); err != nil {
+	if err := parseModelJSON(responseText, &result); err != nil {
 		// If clustering fails, return each article as its own group
 		var groups []output.ArticleGroup
 		for i, article := range articles {
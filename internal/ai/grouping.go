@@ -43,13 +43,18 @@ func (m *GroupMatcher) MatchArticleToGroup(ctx context.Context, userID int64, ti
 		return nil, nil, fmt.Errorf("embed article: %w", err)
 	}
 
+	groupID, _, err := m.MatchEmbedding(userID, articleEmb)
+	return groupID, articleEmb, err
+}
+
+// MatchEmbedding compares an already-computed article embedding against the
+// user's cached group centroids. It returns the best-matching group ID if its
+// similarity is >= threshold (nil otherwise), along with that best similarity
+// so callers can apply a looser pre-filter of their own.
+func (m *GroupMatcher) MatchEmbedding(userID int64, articleEmb []float32) (*int64, float64, error) {
 	groups, err := m.store.GetGroupsWithEmbeddings(userID, m.model)
 	if err != nil {
-		return nil, articleEmb, fmt.Errorf("get groups: %w", err)
-	}
-
-	if len(groups) == 0 {
-		return nil, articleEmb, nil
+		return nil, 0, fmt.Errorf("get groups: %w", err)
 	}
 
 	var bestID int64
@@ -64,10 +69,10 @@ func (m *GroupMatcher) MatchArticleToGroup(ctx context.Context, userID int64, ti
 		}
 	}
 
-	if bestSim >= m.threshold {
-		return &bestID, articleEmb, nil
+	if bestID != 0 && bestSim >= m.threshold {
+		return &bestID, bestSim, nil
 	}
-	return nil, articleEmb, nil
+	return nil, bestSim, nil
 }
 
 // minEmbedContentLen is the minimum article content length (in bytes) required
@@ -101,19 +106,8 @@ func (m *GroupMatcher) EmbedArticle(ctx context.Context, title, content string)
 // embedding and any existing group centroid for this user. Returns 0 if there
 // are no groups with embeddings.
 func (m *GroupMatcher) BestGroupSimilarity(userID int64, articleEmb []float32) (float64, error) {
-	groups, err := m.store.GetGroupsWithEmbeddings(userID, m.model)
-	if err != nil {
-		return 0, fmt.Errorf("get groups: %w", err)
-	}
-	var best float64
-	for _, g := range groups {
-		centroid := embedding.DecodeFloat32s(g.Embedding)
-		sim := embedding.CosineSimilarity(articleEmb, centroid)
-		if sim > best {
-			best = sim
-		}
-	}
-	return best, nil
+	_, best, err := m.MatchEmbedding(userID, articleEmb)
+	return best, err
 }
 
 // UpdateGroupCentroid performs an incremental centroid update after adding an
@@ -200,6 +200,36 @@ func TestMatchArticleToGroup_BestMatch(t *testing.T) {
 	}
 }
 
+func TestMatchEmbedding(t *testing.T) {
+	store := newMockStore()
+	store.groups = []storage.ArticleGroupWithEmbedding{
+		{
+			ArticleGroup: storage.ArticleGroup{ID: 10, UserID: 1, Topic: "Middling"},
+			Embedding:    embedding.EncodeFloat32s([]float32{0.6, 0.8, 0}),
+		},
+	}
+	matcher := NewGroupMatcher(&mockEmbedder{model: "test"}, store, "test", 0.75)
+
+	// cos([1,0,0], [0.6,0.8,0]) = 0.6: below threshold, but the similarity is
+	// still reported so the caller can decide whether to ask the LLM.
+	matchedID, sim, err := matcher.MatchEmbedding(1, []float32{1, 0, 0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if matchedID != nil {
+		t.Errorf("expected no match below threshold, got %d", *matchedID)
+	}
+	if math.Abs(sim-0.6) > 1e-6 {
+		t.Errorf("similarity: got %f, want 0.6", sim)
+	}
+
+	// An identical vector clears the threshold without any embedding call.
+	matchedID, _, _ = matcher.MatchEmbedding(1, []float32{0.6, 0.8, 0})
+	if matchedID == nil || *matchedID != 10 {
+		t.Errorf("expected match on group 10, got %v", matchedID)
+	}
+}
+
 func TestUpdateGroupCentroid_FirstArticle(t *testing.T) {
 	store := newMockStore()
 	store.articleCounts[1] = 1 // just added the first article
@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoJSON is returned when a model response contains no JSON object.
+var ErrNoJSON = errors.New("no JSON object in model response")
+
+// parseModelJSON unmarshals the first JSON object in a model response into v.
+// Models routinely wrap structured output in ```json fences, lead with a
+// sentence of prose, or leave a trailing comma after the last field; all of
+// those are tolerated. Every structured-output call should go through here.
+func parseModelJSON(response string, v any) error {
+	obj, err := extractJSONObject(response)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(obj), v); err != nil {
+		return fmt.Errorf("parse model JSON: %w", err)
+	}
+	return nil
+}
+
+// extractJSONObject returns the first balanced {...} span in text that is
+// valid JSON once trailing commas are removed. Braces inside JSON strings are
+// skipped, so prose such as "use {name}" ahead of the real object is passed
+// over rather than mistaken for it.
+func extractJSONObject(text string) (string, error) {
+	found := false
+	for start := strings.IndexByte(text, '{'); start >= 0; {
+		if end := matchingBrace(text, start); end > start {
+			found = true
+			candidate := stripTrailingCommas(text[start : end+1])
+			if json.Valid([]byte(candidate)) {
+				return candidate, nil
+			}
+		}
+		next := strings.IndexByte(text[start+1:], '{')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	if found {
+		return "", errors.New("malformed JSON object in model response")
+	}
+	return "", ErrNoJSON
+}
+
+// matchingBrace returns the index of the '}' that closes the '{' at
+// text[start], or -1 if the object is never closed.
+func matchingBrace(text string, start int) int {
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// stripTrailingCommas removes commas that directly precede a closing '}' or
+// ']' (ignoring whitespace), leaving commas inside strings alone.
+func stripTrailingCommas(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == ',':
+			j := i + 1
+			for j < len(s) && strings.IndexByte(" \t\r\n", s[j]) >= 0 {
+				j++
+			}
+			if j < len(s) && (s[j] == '}' || s[j] == ']') {
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
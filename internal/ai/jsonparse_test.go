@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseModelJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     SecurityResult
+	}{
+		{
+			name:     "bare",
+			response: `{"safe": true, "score": 9, "reasoning": "ok"}`,
+			want:     SecurityResult{Safe: true, Score: 9, Reasoning: "ok"},
+		},
+		{
+			name:     "fenced",
+			response: "```json\n{\"safe\": true, \"score\": 8.5, \"reasoning\": \"fine\"}\n```",
+			want:     SecurityResult{Safe: true, Score: 8.5, Reasoning: "fine"},
+		},
+		{
+			name:     "prose before and after",
+			response: "Here is my assessment:\n{\"safe\": false, \"score\": 2, \"reasoning\": \"injection\"}\nLet me know if you need more.",
+			want:     SecurityResult{Safe: false, Score: 2, Reasoning: "injection"},
+		},
+		{
+			name:     "trailing commas",
+			response: "{\n  \"safe\": true,\n  \"score\": 7,\n  \"reasoning\": \"a, b,\",\n}",
+			want:     SecurityResult{Safe: true, Score: 7, Reasoning: "a, b,"},
+		},
+		{
+			name:     "braces in prose and strings",
+			response: `Fill in {reasoning} below. {"safe": true, "score": 9, "reasoning": "uses {} and \"quotes\""}`,
+			want:     SecurityResult{Safe: true, Score: 9, Reasoning: `uses {} and "quotes"`},
+		},
+		{
+			name:     "first of several objects",
+			response: `{"safe": true, "score": 9, "reasoning": "first"} {"safe": false, "score": 1, "reasoning": "second"}`,
+			want:     SecurityResult{Safe: true, Score: 9, Reasoning: "first"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SecurityResult
+			if err := parseModelJSON(tt.response, &got); err != nil {
+				t.Fatalf("parseModelJSON: %v", err)
+			}
+			if got.Safe != tt.want.Safe || got.Score != tt.want.Score || got.Reasoning != tt.want.Reasoning {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseModelJSONNested(t *testing.T) {
+	var got RelatedArticlesResult
+	response := "```json\n{\"is_related\": true, \"existing_groups\": [3, 7,], \"reasoning\": \"same story\",}\n```"
+	if err := parseModelJSON(response, &got); err != nil {
+		t.Fatalf("parseModelJSON: %v", err)
+	}
+	if !got.IsRelated || len(got.ExistingGroups) != 2 || got.ExistingGroups[0] != 3 || got.ExistingGroups[1] != 7 {
+		t.Errorf("got %+v, want is_related with existing groups [3 7]", got)
+	}
+}
+
+func TestParseModelJSONErrors(t *testing.T) {
+	var v SecurityResult
+	if err := parseModelJSON("I cannot help with that.", &v); !errors.Is(err, ErrNoJSON) {
+		t.Errorf("no JSON: err = %v, want ErrNoJSON", err)
+	}
+	if err := parseModelJSON(`{"safe": true, "score": }`, &v); err == nil || errors.Is(err, ErrNoJSON) {
+		t.Errorf("malformed JSON: err = %v, want a non-ErrNoJSON error", err)
+	}
+	if err := parseModelJSON(`{"safe": true`, &v); !errors.Is(err, ErrNoJSON) {
+		t.Errorf("unclosed object: err = %v, want ErrNoJSON", err)
+	}
+}
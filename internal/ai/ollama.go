@@ -2,9 +2,13 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/matthewjhunter/herald/internal/storage"
@@ -12,10 +16,19 @@ import (
 
 type AIProcessor struct {
 	client        *openAIClient
+	modelMu       sync.RWMutex // guards the model names, which SetModels and SetCurationFallbacks swap at runtime
 	securityModel string
 	curationModel string
-	promptLoader  *PromptLoader
-	callTimeout   time.Duration
+	// curationFallbacks are tried in order when the curation model is
+	// missing or fails transiently.
+	curationFallbacks []string
+	promptLoader      *PromptLoader
+	callTimeout       time.Duration
+
+	// Content limits, in characters, for the security check and for the
+	// curation-model stages (scoring and summarization).
+	securityMaxContent int
+	curationMaxContent int
 }
 
 // withCallTimeout wraps ctx with the per-call timeout so that a hung
@@ -32,8 +45,84 @@ type SecurityResult struct {
 }
 
 type CurationResult struct {
-	InterestScore float64 `json:"interest_score"`
-	Reasoning     string  `json:"reasoning"`
+	InterestScore float64  `json:"interest_score"`
+	Reasoning     string   `json:"reasoning"`
+	Tags          []string `json:"tags,omitempty"` // optional topic tags; absent when the prompt doesn't ask for them
+}
+
+// maxCurationTags caps how many model-suggested tags are kept per article.
+const maxCurationTags = 5
+
+// normalizeTags lowercases and trims model-suggested tags, dropping empty,
+// overlong and duplicate entries, and keeps at most maxCurationTags.
+func normalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, t := range tags {
+		t = strings.ToLower(strings.Join(strings.Fields(t), " "))
+		if t == "" || len(t) > 40 || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+		if len(out) == maxCurationTags {
+			break
+		}
+	}
+	return out
+}
+
+// SetModels replaces the default security and curation models for subsequent
+// calls. An empty name keeps the current model.
+func (p *AIProcessor) SetModels(securityModel, curationModel string) {
+	p.modelMu.Lock()
+	defer p.modelMu.Unlock()
+	if securityModel != "" {
+		p.securityModel = securityModel
+	}
+	if curationModel != "" {
+		p.curationModel = curationModel
+	}
+}
+
+// SetConfig replaces the config the processor's prompt loader consults. The
+// engine calls it with a fresh copy whenever its runtime config changes.
+func (p *AIProcessor) SetConfig(config interface{}) {
+	p.promptLoader.SetConfig(config)
+}
+
+// SetCurationFallbacks replaces the models CurateArticle falls back to, in
+// order, after the curation model.
+func (p *AIProcessor) SetCurationFallbacks(models []string) {
+	p.modelMu.Lock()
+	defer p.modelMu.Unlock()
+	p.curationFallbacks = slices.Clone(models)
+}
+
+// curationChain returns model followed by the curation fallbacks, without
+// duplicates or empty names.
+func (p *AIProcessor) curationChain(model string) []string {
+	p.modelMu.RLock()
+	defer p.modelMu.RUnlock()
+	chain := make([]string, 0, 1+len(p.curationFallbacks))
+	for _, m := range append([]string{model}, p.curationFallbacks...) {
+		if m != "" && !slices.Contains(chain, m) {
+			chain = append(chain, m)
+		}
+	}
+	return chain
+}
+
+func (p *AIProcessor) securityModelName() string {
+	p.modelMu.RLock()
+	defer p.modelMu.RUnlock()
+	return p.securityModel
+}
+
+func (p *AIProcessor) curationModelName() string {
+	p.modelMu.RLock()
+	defer p.modelMu.RUnlock()
+	return p.curationModel
 }
 
 // NewAIProcessor creates a new AI processor backed by an OpenAI-compatible
@@ -44,7 +133,9 @@ func NewAIProcessor(baseURL, securityModel, curationModel string, store interfac
 	}
 
 	var apiKey string
+	var curationFallbacks []string
 	callTimeout := 2 * time.Minute
+	securityMax, curationMax := defaultMaxContentLen, defaultMaxContentLen
 	if cfg, ok := config.(*storage.Config); ok && cfg != nil {
 		if cfg.Ollama.APIKey != "" {
 			apiKey = cfg.Ollama.APIKey
@@ -52,9 +143,19 @@ func NewAIProcessor(baseURL, securityModel, curationModel string, store interfac
 		if cfg.Ollama.Timeout > 0 {
 			callTimeout = cfg.Ollama.Timeout
 		}
+		securityMax, curationMax = contentLimits(cfg.Ollama.SecurityMaxContent, cfg.Ollama.CurationMaxContent)
+		if chain := cfg.Ollama.CurationModels; len(chain) > 0 {
+			curationModel = chain[0]
+			curationFallbacks = slices.Clone(chain[1:])
+		}
 	}
 
 	promptLoader := newPromptLoaderSafe(store, config)
+	if cfg, ok := config.(*storage.Config); ok && cfg != nil && cfg.Prompts.Dir != "" {
+		if err := promptLoader.LoadPromptDir(cfg.Prompts.Dir); err != nil {
+			return nil, err
+		}
+	}
 
 	return &AIProcessor{
 		client:        newOpenAIClient(baseURL, apiKey),
@@ -62,9 +163,36 @@ func NewAIProcessor(baseURL, securityModel, curationModel string, store interfac
 		curationModel: curationModel,
 		promptLoader:  promptLoader,
 		callTimeout:   callTimeout,
+
+		curationFallbacks: curationFallbacks,
+
+		securityMaxContent: securityMax,
+		curationMaxContent: curationMax,
 	}, nil
 }
 
+// contentLimits resolves the configured content limits; 0 means the default.
+// The security limit is raised to minSecurityContentLen, and the curation
+// limit is capped at the security limit so that no model sees content the
+// security check has not screened.
+func contentLimits(security, curation int) (int, int) {
+	if security <= 0 {
+		security = defaultMaxContentLen
+	}
+	if security < minSecurityContentLen {
+		security = minSecurityContentLen
+	}
+	if curation <= 0 {
+		curation = min(defaultMaxContentLen, security)
+	}
+	if curation > security {
+		slog.Warn("curation_max_content exceeds security_max_content; capping it",
+			"curation_max_content", curation, "security_max_content", security)
+		curation = security
+	}
+	return security, curation
+}
+
 // newPromptLoaderSafe creates a PromptLoader with nil-safe type assertions.
 func newPromptLoaderSafe(store, config interface{}) *PromptLoader {
 	return &PromptLoader{
@@ -74,6 +202,14 @@ func newPromptLoaderSafe(store, config interface{}) *PromptLoader {
 	}
 }
 
+// ScreenedText returns the part of content SecurityCheck screens. Text
+// derived from content for the other models, such as with a feed's strip
+// patterns removed, should be derived from this, so that nothing beyond the
+// screened part reaches them.
+func (p *AIProcessor) ScreenedText(content string) string {
+	return truncateText(content, p.securityMaxContent)
+}
+
 // SecurityCheck analyzes content for security threats (prompt injection, malicious content).
 func (p *AIProcessor) SecurityCheck(ctx context.Context, userID int64, title, content string) (*SecurityResult, error) {
 	promptTemplate, err := p.promptLoader.GetPrompt(userID, PromptTypeSecurity)
@@ -83,7 +219,7 @@ func (p *AIProcessor) SecurityCheck(ctx context.Context, userID int64, title, co
 
 	data := map[string]interface{}{
 		"Title":   title,
-		"Content": truncateText(content, maxPromptContentLen),
+		"Content": truncateText(content, p.securityMaxContent),
 	}
 	prompt, err := ExecutePrompt(promptTemplate, data)
 	if err != nil {
@@ -93,7 +229,7 @@ func (p *AIProcessor) SecurityCheck(ctx context.Context, userID int64, title, co
 	temperature := p.promptLoader.GetTemperature(userID, PromptTypeSecurity)
 	model := p.promptLoader.GetModel(userID, PromptTypeSecurity)
 	if model == "" {
-		model = p.securityModel
+		model = p.securityModelName()
 	}
 
 	callCtx, cancel := p.withCallTimeout(ctx)
@@ -105,7 +241,8 @@ func (p *AIProcessor) SecurityCheck(ctx context.Context, userID int64, title, co
 	}
 
 	var result SecurityResult
-	if err := json.Unmarshal([]byte(extractJSON(responseText)), &result); err != nil {
+	if err := parseModelJSON(responseText, &result); err != nil {
+		slog.Warn("unparseable security response", "event", "ai_parse_failed", "model", model, "err", err)
 		return &SecurityResult{
 			Safe:      false,
 			Score:     0,
@@ -116,23 +253,22 @@ func (p *AIProcessor) SecurityCheck(ctx context.Context, userID int64, title, co
 	return &result, nil
 }
 
-// CurateArticle scores an article for interest/relevance.
-func (p *AIProcessor) CurateArticle(ctx context.Context, userID int64, title, content string, keywords []string) (*CurationResult, error) {
-	keywordStr := "No specific preferences"
-	if len(keywords) > 0 {
-		keywordStr = strings.Join(keywords, ", ")
-	}
-
+// CurateArticle scores an article for interest/relevance against keywords,
+// down-ranking topics in avoid. feedTitle names the feed the article came
+// from, so the model can weigh the source; it may be empty, and since the
+// feed chooses it, it is flattened to one short line (see promptLine). It
+// tries the curation model and then each fallback in order, moving on when
+// a model is missing or fails transiently; see shouldFallback.
+func (p *AIProcessor) CurateArticle(ctx context.Context, userID int64, title, content, feedTitle string, keywords, avoid storage.Keywords) (*CurationResult, error) {
 	promptTemplate, err := p.promptLoader.GetPrompt(userID, PromptTypeCuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load curation prompt: %w", err)
 	}
 
-	data := map[string]interface{}{
-		"Title":    title,
-		"Content":  truncateText(content, maxPromptContentLen),
-		"Keywords": keywordStr,
-	}
+	data := p.promptLoader.CurationTemplateData(keywords, avoid)
+	data["Title"] = title
+	data["Content"] = truncateText(content, p.curationMaxContent)
+	data["FeedTitle"] = promptLine(feedTitle, maxFeedTitleLen)
 	prompt, err := ExecutePrompt(promptTemplate, data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render curation prompt: %w", err)
@@ -141,53 +277,100 @@ func (p *AIProcessor) CurateArticle(ctx context.Context, userID int64, title, co
 	temperature := p.promptLoader.GetTemperature(userID, PromptTypeCuration)
 	model := p.promptLoader.GetModel(userID, PromptTypeCuration)
 	if model == "" {
-		model = p.curationModel
+		model = p.curationModelName()
 	}
 
-	callCtx, cancel := p.withCallTimeout(ctx)
-	defer cancel()
-
-	responseText, err := p.client.generate(callCtx, model, prompt, temperature)
+	var responseText string
+	var failed []string
+	for _, m := range p.curationChain(model) {
+		callCtx, cancel := p.withCallTimeout(ctx)
+		responseText, err = p.client.generate(callCtx, m, prompt, temperature)
+		cancel()
+		if err == nil {
+			model = m
+			break
+		}
+		failed = append(failed, m)
+		if !shouldFallback(ctx, err) {
+			break
+		}
+		slog.Warn("curation model failed", "event", "ai_model_failed", "model", m, "err", err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("ollama curation failed: %w", err)
 	}
+	if len(failed) > 0 {
+		slog.Info("curation model fallback", "event", "ai_model_fallback", "model", model, "failed", failed)
+	}
 
 	var result CurationResult
-	if err := json.Unmarshal([]byte(extractJSON(responseText)), &result); err != nil {
+	if err := parseModelJSON(responseText, &result); err != nil {
+		slog.Warn("unparseable curation response", "event", "ai_parse_failed", "model", model, "err", err)
 		return &CurationResult{
 			InterestScore: 0,
 			Reasoning:     "Curation response did not match expected JSON format -- possible prompt injection",
 		}, nil
 	}
+	result.Tags = normalizeTags(result.Tags)
 
 	return &result, nil
 }
 
+// shouldFallback reports whether a failed curation call should move on to the
+// next model in the chain. A missing model and transient failures do; a
+// cancelled caller, an open circuit breaker and other client errors such as
+// a bad API key would fail the same way on every model, so they don't.
+func shouldFallback(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var ce *ClientError
+	if errors.As(err, &ce) {
+		return ce.StatusCode == http.StatusNotFound || strings.Contains(strings.ToLower(ce.Body), "not found")
+	}
+	return true
+}
+
 // ListModels returns the names of all models available at the configured endpoint.
 func (p *AIProcessor) ListModels(ctx context.Context) ([]string, error) {
 	return p.client.listModels(ctx)
 }
 
-// truncateText truncates text to maxLen characters.
-// maxPromptContentLen is the maximum number of characters of article content
-// sent to any AI model. All prompt stages (security, summarization, curation)
-// must use the same limit so the security check screens everything downstream
-// models will see.
-const maxPromptContentLen = 3000
+// Models returns the current security and curation model names.
+func (p *AIProcessor) Models() (securityModel, curationModel string) {
+	return p.securityModelName(), p.curationModelName()
+}
 
-func truncateText(text string, maxLen int) string {
-	if len(text) <= maxLen {
-		return text
+// CheckModels confirms that the security and curation models are available
+// at the endpoint, so a missing model is reported up front instead of as a
+// failed call deep in processing. The error names every missing model.
+func (p *AIProcessor) CheckModels(ctx context.Context) error {
+	available, err := p.client.listModels(ctx)
+	if err != nil {
+		return fmt.Errorf("list models at %s: %w", p.client.baseURL, err)
 	}
-	return text[:maxLen] + "..."
+	var missing []string
+	for _, model := range []string{p.securityModelName(), p.curationModelName()} {
+		if !modelAvailable(model, available) && !slices.Contains(missing, model) {
+			missing = append(missing, model)
+		}
+	}
+	switch len(missing) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("model %q is not available at %s; pull it with \"ollama pull %s\"", missing[0], p.client.baseURL, missing[0])
+	}
+	return fmt.Errorf("models %s are not available at %s; pull each with \"ollama pull <model>\"", strings.Join(missing, ", "), p.client.baseURL)
 }
 
-// extractJSON attempts to extract JSON from a text response that might contain extra text.
-func extractJSON(text string) string {
-	start := strings.Index(text, "{")
-	end := strings.LastIndex(text, "}")
-	if start >= 0 && end > start {
-		return text[start : end+1]
+// modelAvailable reports whether model is in available. Ollama lists
+// untagged models with an explicit ":latest", so that tag is implied.
+func modelAvailable(model string, available []string) bool {
+	for _, m := range available {
+		if m == model || (!strings.Contains(model, ":") && m == model+":latest") {
+			return true
+		}
 	}
-	return text
+	return false
 }
@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/matthewjhunter/herald/internal/storage"
+)
+
+func TestCurationResultTags(t *testing.T) {
+	response := "```json\n" + `{"interest_score": 7, "reasoning": "Covers a Go release.", "tags": [" Golang ", "golang", "", "Supply   Chain", "a", "b", "c", "d"]}` + "\n```"
+	var result CurationResult
+	if err := parseModelJSON(response, &result); err != nil {
+		t.Fatalf("parseModelJSON: %v", err)
+	}
+	got := normalizeTags(result.Tags)
+	want := []string{"golang", "supply chain", "a", "b", "c"}
+	if !slices.Equal(got, want) {
+		t.Errorf("normalizeTags = %q, want %q", got, want)
+	}
+
+	// Tags are optional: a response without them parses with none.
+	result = CurationResult{}
+	if err := parseModelJSON(`{"interest_score": 3, "reasoning": "Off topic."}`, &result); err != nil {
+		t.Fatalf("parseModelJSON: %v", err)
+	}
+	if tags := normalizeTags(result.Tags); tags != nil {
+		t.Errorf("tags = %q, want none", tags)
+	}
+}
+
+func TestCheckModels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","data":[{"id":"gemma3:4b"},{"id":"llama3.2:latest"}]}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewAIProcessor(srv.URL, "gemma3:4b", "llama3.2", nil, nil)
+	if err != nil {
+		t.Fatalf("NewAIProcessor: %v", err)
+	}
+	// "llama3.2" matches the ":latest" listing.
+	if err := p.CheckModels(context.Background()); err != nil {
+		t.Errorf("CheckModels with both models present: %v", err)
+	}
+
+	p.SetModels("", "qwen3:8b")
+	err = p.CheckModels(context.Background())
+	if err == nil {
+		t.Fatal("expected an error for a missing curation model")
+	}
+	if !strings.Contains(err.Error(), "qwen3:8b") || strings.Contains(err.Error(), "gemma3:4b") {
+		t.Errorf("error should name only the missing model: %v", err)
+	}
+}
+
+func TestCurateArticleFallback(t *testing.T) {
+	var tried []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		tried = append(tried, req.Model)
+		switch req.Model {
+		case "missing":
+			http.Error(w, `{"error":"model \"missing\" not found"}`, http.StatusNotFound)
+		case "denied":
+			http.Error(w, `{"error":"invalid api key"}`, http.StatusUnauthorized)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"interest_score\": 7, \"reasoning\": \"ok\"}"}}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	cfg := storage.DefaultConfig()
+	cfg.Ollama.CurationModels = []string{"missing", "backup"}
+	p, err := NewAIProcessor(srv.URL, "gemma4", "gemma4", nil, cfg)
+	if err != nil {
+		t.Fatalf("NewAIProcessor: %v", err)
+	}
+	result, err := p.CurateArticle(context.Background(), 1, "Title", "Content", "", nil, nil)
+	if err != nil {
+		t.Fatalf("CurateArticle: %v", err)
+	}
+	if result.InterestScore != 7 {
+		t.Errorf("InterestScore = %v, want 7 from the fallback model", result.InterestScore)
+	}
+	if !slices.Equal(tried, []string{"missing", "backup"}) {
+		t.Errorf("models tried = %v, want [missing backup]", tried)
+	}
+
+	// An auth failure would fail the same way on every model.
+	tried = nil
+	cfg.Ollama.CurationModels = []string{"denied", "backup"}
+	p, err = NewAIProcessor(srv.URL, "gemma4", "gemma4", nil, cfg)
+	if err != nil {
+		t.Fatalf("NewAIProcessor: %v", err)
+	}
+	if _, err := p.CurateArticle(context.Background(), 1, "Title", "Content", "", nil, nil); err == nil {
+		t.Error("expected an error when the first model is denied")
+	}
+	if !slices.Equal(tried, []string{"denied"}) {
+		t.Errorf("models tried = %v, want [denied]", tried)
+	}
+}
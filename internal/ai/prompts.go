@@ -2,7 +2,12 @@ package ai
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
@@ -30,6 +35,9 @@ var defaultRelatedGroupsPrompt string
 //go:embed prompts/newsletter.txt
 var defaultNewsletterPrompt string
 
+//go:embed prompts/briefing.txt
+var defaultBriefingPrompt string
+
 // PromptType represents the type of AI prompt
 type PromptType string
 
@@ -40,14 +48,29 @@ const (
 	PromptTypeGroupSummary  PromptType = "group_summary"
 	PromptTypeRelatedGroups PromptType = "related_groups"
 	PromptTypeNewsletter    PromptType = "newsletter"
+	// PromptTypeBriefing isn't sent to a model: it lays out the briefing
+	// text itself, rendered with BriefingData.
+	PromptTypeBriefing PromptType = "briefing"
 )
 
-// PromptLoader handles 3-tier prompt loading: embedded -> config -> database
+// allPromptTypes lists every prompt type with an embedded default.
+var allPromptTypes = []PromptType{
+	PromptTypeSecurity,
+	PromptTypeCuration,
+	PromptTypeSummarization,
+	PromptTypeGroupSummary,
+	PromptTypeRelatedGroups,
+	PromptTypeNewsletter,
+	PromptTypeBriefing,
+}
+
+// PromptLoader handles tiered prompt loading: embedded -> prompt dir -> config -> database
 type PromptLoader struct {
-	store  interface{}       // storage.Store
-	config interface{}       // *storage.Config
-	mu     sync.RWMutex      // protects cache
-	cache  map[string]string // cache of loaded prompts per user
+	store      interface{}           // storage.Store
+	config     interface{}           // *storage.Config
+	dirPrompts map[PromptType]string // defaults loaded from the prompt directory
+	mu         sync.RWMutex          // protects cache and config
+	cache      map[string]string     // cache of loaded prompts per user
 }
 
 // NewPromptLoader creates a new prompt loader
@@ -59,6 +82,51 @@ func NewPromptLoader(store, config interface{}) *PromptLoader {
 	}
 }
 
+// SetConfig replaces the config consulted for prompts, temperatures and
+// models, and drops any prompts cached from the old one.
+func (pl *PromptLoader) SetConfig(config interface{}) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.config = config
+	pl.cache = make(map[string]string)
+}
+
+// currentConfig returns the loader's config, or nil if it has none.
+func (pl *PromptLoader) currentConfig() *storage.Config {
+	pl.mu.RLock()
+	defer pl.mu.RUnlock()
+	config, _ := pl.config.(*storage.Config)
+	return config
+}
+
+// LoadPromptDir reads <type>.txt for each prompt type from dir and uses
+// them in place of the embedded defaults. Missing files keep the embedded
+// default. Every file found must parse as a template, so a typo fails at
+// startup rather than on the first AI call.
+func (pl *PromptLoader) LoadPromptDir(dir string) error {
+	prompts := make(map[PromptType]string)
+	for _, pt := range allPromptTypes {
+		path := filepath.Join(dir, string(pt)+".txt")
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read prompt %s: %w", path, err)
+		}
+		if _, err := template.New(string(pt)).Parse(string(data)); err != nil {
+			return fmt.Errorf("parse prompt %s: %w", path, err)
+		}
+		prompts[pt] = string(data)
+	}
+
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.dirPrompts = prompts
+	pl.cache = make(map[string]string)
+	return nil
+}
+
 // DefaultPrompt returns the embedded default prompt for the given type.
 func DefaultPrompt(pt PromptType) (string, error) {
 	switch pt {
@@ -74,13 +142,15 @@ func DefaultPrompt(pt PromptType) (string, error) {
 		return defaultRelatedGroupsPrompt, nil
 	case PromptTypeNewsletter:
 		return defaultNewsletterPrompt, nil
+	case PromptTypeBriefing:
+		return defaultBriefingPrompt, nil
 	default:
 		return "", fmt.Errorf("unknown prompt type: %s", pt)
 	}
 }
 
-// GetPrompt loads a prompt with 4-tier fallback
-// Priority: user database -> global admin (user_id=0) -> config file -> embedded default
+// GetPrompt loads a prompt with 5-tier fallback
+// Priority: user database -> global admin (user_id=0) -> config file -> prompt dir -> embedded default
 func (pl *PromptLoader) GetPrompt(userID int64, promptType PromptType) (string, error) {
 	cacheKey := fmt.Sprintf("%d:%s", userID, promptType)
 
@@ -118,32 +188,43 @@ func (pl *PromptLoader) GetPrompt(userID int64, promptType PromptType) (string,
 
 	// Tier 2: Check config file
 	var configPrompt string
-	if pl.config != nil {
-		if config, ok := pl.config.(*storage.Config); ok {
-			switch promptType {
-			case PromptTypeSecurity:
-				configPrompt = config.Prompts.Security
-			case PromptTypeCuration:
-				configPrompt = config.Prompts.Curation
-			case PromptTypeSummarization:
-				configPrompt = config.Prompts.Summarization
-			case PromptTypeGroupSummary:
-				configPrompt = config.Prompts.GroupSummary
-			case PromptTypeRelatedGroups:
-				configPrompt = config.Prompts.RelatedGroups
-			case PromptTypeNewsletter:
-				configPrompt = config.Prompts.Newsletter
-			}
+	if config := pl.currentConfig(); config != nil {
+		switch promptType {
+		case PromptTypeSecurity:
+			configPrompt = config.Prompts.Security
+		case PromptTypeCuration:
+			configPrompt = config.Prompts.Curation
+		case PromptTypeSummarization:
+			configPrompt = config.Prompts.Summarization
+		case PromptTypeGroupSummary:
+			configPrompt = config.Prompts.GroupSummary
+		case PromptTypeRelatedGroups:
+			configPrompt = config.Prompts.RelatedGroups
+		case PromptTypeNewsletter:
+			configPrompt = config.Prompts.Newsletter
+		case PromptTypeBriefing:
+			configPrompt = config.Prompts.Briefing
+		}
 
-			if configPrompt != "" {
-				pl.mu.Lock()
-				pl.cache[cacheKey] = configPrompt
-				pl.mu.Unlock()
-				return configPrompt, nil
-			}
+		if configPrompt != "" {
+			pl.mu.Lock()
+			pl.cache[cacheKey] = configPrompt
+			pl.mu.Unlock()
+			return configPrompt, nil
 		}
 	}
 
+	// Prompt directory override of the embedded default
+	pl.mu.RLock()
+	dirPrompt := pl.dirPrompts[promptType]
+	pl.mu.RUnlock()
+	if dirPrompt != "" {
+		pl.mu.Lock()
+		pl.cache[cacheKey] = dirPrompt
+		pl.mu.Unlock()
+		return dirPrompt, nil
+	}
+
 	// Tier 1: Use embedded default (lowest priority)
 	var defaultPrompt string
 	switch promptType {
@@ -159,6 +240,8 @@ func (pl *PromptLoader) GetPrompt(userID int64, promptType PromptType) (string,
 		defaultPrompt = defaultRelatedGroupsPrompt
 	case PromptTypeNewsletter:
 		defaultPrompt = defaultNewsletterPrompt
+	case PromptTypeBriefing:
+		defaultPrompt = defaultBriefingPrompt
 	default:
 		return "", fmt.Errorf("unknown prompt type: %s", promptType)
 	}
@@ -183,27 +266,25 @@ func (pl *PromptLoader) GetTemperature(userID int64, promptType PromptType) floa
 	}
 
 	// Tier 2: Check config file
-	if pl.config != nil {
-		if config, ok := pl.config.(*storage.Config); ok {
-			var configTemp float64
-			switch promptType {
-			case PromptTypeSecurity:
-				configTemp = config.Temperatures.Security
-			case PromptTypeCuration:
-				configTemp = config.Temperatures.Curation
-			case PromptTypeSummarization:
-				configTemp = config.Temperatures.Summarization
-			case PromptTypeGroupSummary:
-				configTemp = config.Temperatures.GroupSummary
-			case PromptTypeRelatedGroups:
-				configTemp = config.Temperatures.RelatedGroups
-			case PromptTypeNewsletter:
-				configTemp = config.Temperatures.Newsletter
-			}
+	if config := pl.currentConfig(); config != nil {
+		var configTemp float64
+		switch promptType {
+		case PromptTypeSecurity:
+			configTemp = config.Temperatures.Security
+		case PromptTypeCuration:
+			configTemp = config.Temperatures.Curation
+		case PromptTypeSummarization:
+			configTemp = config.Temperatures.Summarization
+		case PromptTypeGroupSummary:
+			configTemp = config.Temperatures.GroupSummary
+		case PromptTypeRelatedGroups:
+			configTemp = config.Temperatures.RelatedGroups
+		case PromptTypeNewsletter:
+			configTemp = config.Temperatures.Newsletter
+		}
 
-			if configTemp > 0 {
-				return configTemp
-			}
+		if configTemp > 0 {
+			return configTemp
 		}
 	}
 
@@ -243,23 +324,113 @@ func (pl *PromptLoader) GetModel(userID int64, promptType PromptType) string {
 			}
 		}
 	}
-	if pl.config != nil {
-		if config, ok := pl.config.(*storage.Config); ok {
-			switch promptType {
-			case PromptTypeSecurity:
-				if config.Ollama.SecurityModel != "" {
-					return config.Ollama.SecurityModel
-				}
-			default:
-				if config.Ollama.CurationModel != "" {
-					return config.Ollama.CurationModel
-				}
+	if config := pl.currentConfig(); config != nil {
+		switch promptType {
+		case PromptTypeSecurity:
+			if config.Ollama.SecurityModel != "" {
+				return config.Ollama.SecurityModel
+			}
+		default:
+			if chain := config.CurationChain(); len(chain) > 0 {
+				return chain[0]
 			}
 		}
 	}
 	return ""
 }
 
+// SummaryStyles lists the accepted values of the summary_style preference.
+// The empty string selects the default template wording.
+var SummaryStyles = map[string]bool{
+	"":         true,
+	"terse":    true,
+	"detailed": true,
+	"bullets":  true,
+}
+
+// SummaryTemplateData returns the user's summary_max_words and summary_style
+// preferences as summarization template variables ({{.MaxWords}} and
+// {{.Style}}). Both keys are always present; unset or invalid preferences
+// yield 0 and "".
+func (pl *PromptLoader) SummaryTemplateData(userID int64) map[string]interface{} {
+	data := map[string]interface{}{
+		"MaxWords": 0,
+		"Style":    "",
+	}
+	store, ok := pl.store.(interface {
+		GetUserPreference(userID int64, key string) (string, error)
+	})
+	if !ok {
+		return data
+	}
+	if v, err := store.GetUserPreference(userID, "summary_max_words"); err == nil {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			data["MaxWords"] = n
+		}
+	}
+	if v, err := store.GetUserPreference(userID, "summary_style"); err == nil && SummaryStyles[v] {
+		data["Style"] = v
+	}
+	return data
+}
+
+// CurationTemplateData returns the curation template variables for keywords:
+// {{.Keywords}}, a comma-separated string with non-default weights noted
+// ("golang (weight 3), news"), and {{.WeightedKeywords}}, the storage.Keywords
+// themselves for templates that want to range over terms and weights. The
+// topics to down-rank are {{.AvoidKeywords}}, rendered the same way but empty
+// when there are none, and {{.WeightedAvoidKeywords}}.
+func (pl *PromptLoader) CurationTemplateData(keywords, avoid storage.Keywords) map[string]interface{} {
+	keywordStr := "No specific preferences"
+	if len(keywords) > 0 {
+		keywordStr = keywords.String()
+	}
+	return map[string]interface{}{
+		"Keywords":              keywordStr,
+		"WeightedKeywords":      keywords,
+		"AvoidKeywords":         avoid.String(),
+		"WeightedAvoidKeywords": avoid,
+	}
+}
+
+// BriefingArticle is one entry in a briefing template's {{.Articles}}.
+type BriefingArticle struct {
+	ID      int64
+	Title   string
+	URL     string
+	Summary string  // the user's AI summary, else the feed's summary; may be empty
+	Score   float64 // interest score, 0-10
+	Queued  bool    // came from the notification queue rather than the score scan
+	// FlaggedBy names the users who found the article high-interest; set
+	// only in combined (household) briefings.
+	FlaggedBy []string
+}
+
+// BriefingData holds the briefing template variables: {{.Articles}}, the
+// entries in briefing order, and {{.Count}}. It's a struct rather than a map
+// so a misspelled field fails to render instead of printing "<no value>".
+type BriefingData struct {
+	Articles []BriefingArticle
+	Count    int
+}
+
+// ValidatePrompt reports whether tmpl parses as a template. Briefing
+// templates are also rendered against sample data, catching misspelled
+// fields before a briefing is due.
+func ValidatePrompt(pt PromptType, tmpl string) error {
+	if pt == PromptTypeBriefing {
+		_, err := ExecutePrompt(tmpl, BriefingData{
+			Articles: []BriefingArticle{{ID: 1, Title: "Example", URL: "https://example.com/", Summary: "Summary.", Score: 7.5}},
+			Count:    1,
+		})
+		return err
+	}
+	if _, err := template.New(string(pt)).Parse(tmpl); err != nil {
+		return fmt.Errorf("failed to parse prompt template: %w", err)
+	}
+	return nil
+}
+
 // ExecutePrompt renders a prompt template with the given data
 func ExecutePrompt(promptTemplate string, data interface{}) (string, error) {
 	tmpl, err := template.New("prompt").Parse(promptTemplate)
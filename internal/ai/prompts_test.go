@@ -1,7 +1,9 @@
 package ai
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/matthewjhunter/herald/internal/storage"
@@ -89,6 +91,150 @@ func TestGetPrompt_DatabaseOverride(t *testing.T) {
 	}
 }
 
+func TestGetPrompt_PromptDir(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "security.txt"), []byte("dir security prompt {{.Title}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "curation.txt"), []byte("dir curation prompt"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetUserPrompt(1, "curation", "db curation prompt", nil, nil); err != nil {
+		t.Fatalf("SetUserPrompt failed: %v", err)
+	}
+
+	pl := NewPromptLoader(store, nil)
+	if err := pl.LoadPromptDir(dir); err != nil {
+		t.Fatalf("LoadPromptDir failed: %v", err)
+	}
+
+	// The file replaces the embedded default.
+	security, err := pl.GetPrompt(1, PromptTypeSecurity)
+	if err != nil {
+		t.Fatalf("GetPrompt(security) failed: %v", err)
+	}
+	if security != "dir security prompt {{.Title}}" {
+		t.Errorf("expected prompt dir override, got: %q", security)
+	}
+
+	// A per-user database prompt still wins over the file.
+	curation, err := pl.GetPrompt(1, PromptTypeCuration)
+	if err != nil {
+		t.Fatalf("GetPrompt(curation) failed: %v", err)
+	}
+	if curation != "db curation prompt" {
+		t.Errorf("expected database override, got: %q", curation)
+	}
+
+	// Types without a file keep the embedded default.
+	summary, err := pl.GetPrompt(1, PromptTypeSummarization)
+	if err != nil {
+		t.Fatalf("GetPrompt(summarization) failed: %v", err)
+	}
+	if summary != defaultSummarizationPrompt {
+		t.Errorf("expected embedded default for summarization, got: %q", summary)
+	}
+}
+
+func TestLoadPromptDir_InvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "security.txt"), []byte("broken {{.Title"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pl := NewPromptLoader(nil, nil)
+	err := pl.LoadPromptDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "security.txt") {
+		t.Fatalf("expected parse error naming security.txt, got %v", err)
+	}
+}
+
+func TestSummaryTemplateData(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	pl := NewPromptLoader(store, nil)
+
+	// Defaults: both keys present so templates can always reference them.
+	data := pl.SummaryTemplateData(1)
+	if data["MaxWords"] != 0 || data["Style"] != "" {
+		t.Errorf("defaults: got %v", data)
+	}
+
+	store.SetUserPreference(1, "summary_max_words", "12")
+	store.SetUserPreference(1, "summary_style", "terse")
+
+	data = pl.SummaryTemplateData(1)
+	data["Title"] = "Title"
+	data["Content"] = "Body"
+	data["MaxSummaryLength"] = 0
+	prompt, err := ExecutePrompt(defaultSummarizationPrompt, data)
+	if err != nil {
+		t.Fatalf("ExecutePrompt: %v", err)
+	}
+	if !strings.Contains(prompt, "at most 12 words") {
+		t.Errorf("expected word limit in prompt, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "single-sentence summary") {
+		t.Errorf("expected terse wording in prompt, got:\n%s", prompt)
+	}
+
+	// Another user is unaffected.
+	data = pl.SummaryTemplateData(2)
+	if data["MaxWords"] != 0 || data["Style"] != "" {
+		t.Errorf("user 2: got %v", data)
+	}
+}
+
+func TestCurationTemplateData(t *testing.T) {
+	pl := NewPromptLoader(nil, nil)
+
+	data := pl.CurationTemplateData(nil, nil)
+	data["Title"], data["Content"] = "Title", "Body"
+	prompt, err := ExecutePrompt(defaultCurationPrompt, data)
+	if err != nil {
+		t.Fatalf("ExecutePrompt: %v", err)
+	}
+	if !strings.Contains(prompt, "User interests: No specific preferences") {
+		t.Errorf("expected no-preference wording, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "weight") {
+		t.Errorf("unweighted prompt should not mention weights, got:\n%s", prompt)
+	}
+
+	data = pl.CurationTemplateData(storage.Keywords{{Term: "golang", Weight: 3}, {Term: "news", Weight: 1}}, nil)
+	data["Title"], data["Content"] = "Title", "Body"
+	prompt, err = ExecutePrompt(defaultCurationPrompt, data)
+	if err != nil {
+		t.Fatalf("ExecutePrompt: %v", err)
+	}
+	if !strings.Contains(prompt, "User interests: golang (weight 3), news\n") {
+		t.Errorf("expected weighted keywords in prompt, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "weight above 1 matter more") {
+		t.Errorf("expected weighting guidance in prompt, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "Topics to avoid") {
+		t.Errorf("prompt without avoid keywords should not mention them, got:\n%s", prompt)
+	}
+
+	data = pl.CurationTemplateData(nil, storage.Keywords{{Term: "crypto", Weight: 1}})
+	data["Title"], data["Content"] = "Title", "Body"
+	prompt, err = ExecutePrompt(defaultCurationPrompt, data)
+	if err != nil {
+		t.Fatalf("ExecutePrompt: %v", err)
+	}
+	if !strings.Contains(prompt, "Topics to avoid: crypto\n") {
+		t.Errorf("expected avoid keywords in prompt, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "pushed down harder") {
+		t.Errorf("unweighted avoid list should not mention weights, got:\n%s", prompt)
+	}
+}
+
 func TestGetPrompt_Cache(t *testing.T) {
 	pl := NewPromptLoader(nil, nil)
 
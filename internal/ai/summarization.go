@@ -2,7 +2,6 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -17,11 +16,10 @@ func (p *AIProcessor) SummarizeArticle(ctx context.Context, userID int64, title,
 		return "", fmt.Errorf("failed to load summarization prompt: %w", err)
 	}
 
-	data := map[string]interface{}{
-		"Title":            title,
-		"Content":          truncateText(content, maxPromptContentLen),
-		"MaxSummaryLength": maxSummaryLength,
-	}
+	data := p.promptLoader.SummaryTemplateData(userID)
+	data["Title"] = title
+	data["Content"] = truncateText(content, p.curationMaxContent)
+	data["MaxSummaryLength"] = maxSummaryLength
 	prompt, err := ExecutePrompt(promptTemplate, data)
 	if err != nil {
 		return "", fmt.Errorf("failed to render summarization prompt: %w", err)
@@ -32,7 +30,7 @@ func (p *AIProcessor) SummarizeArticle(ctx context.Context, userID int64, title,
 	callCtx, cancel := p.withCallTimeout(ctx)
 	defer cancel()
 
-	result, err := p.client.generate(callCtx, p.curationModel, prompt, temperature)
+	result, err := p.client.generate(callCtx, p.curationModelName(), prompt, temperature)
 	if err != nil {
 		return "", fmt.Errorf("article summarization failed: %w", err)
 	}
@@ -88,7 +86,7 @@ func (p *AIProcessor) GenerateGroupSummary(ctx context.Context, userID int64, to
 	callCtx, cancel := p.withCallTimeout(ctx)
 	defer cancel()
 
-	result, err := p.client.generate(callCtx, p.curationModel, prompt, temperature)
+	result, err := p.client.generate(callCtx, p.curationModelName(), prompt, temperature)
 	if err != nil {
 		return nil, fmt.Errorf("group summarization failed: %w", err)
 	}
@@ -97,7 +95,7 @@ func (p *AIProcessor) GenerateGroupSummary(ctx context.Context, userID int64, to
 
 	// Parse JSON response
 	var gsr GroupSummaryResult
-	if err := json.Unmarshal([]byte(result), &gsr); err != nil {
+	if err := parseModelJSON(result, &gsr); err != nil {
 		// Fallback: treat entire response as plain summary (legacy prompt or parse failure)
 		return &GroupSummaryResult{Summary: result}, nil
 	}
@@ -116,7 +114,7 @@ Summary:
 	callCtx, cancel := p.withCallTimeout(ctx)
 	defer cancel()
 
-	topic, err := p.client.generate(callCtx, p.curationModel, prompt, 0.3)
+	topic, err := p.client.generate(callCtx, p.curationModelName(), prompt, 0.3)
 	if err != nil {
 		return "", fmt.Errorf("topic refinement failed: %w", err)
 	}
@@ -197,7 +195,7 @@ func (p *AIProcessor) GenerateNewsletterContent(ctx context.Context, userID int6
 	callCtx, cancel := p.withCallTimeout(ctx)
 	defer cancel()
 
-	result, err := p.client.generate(callCtx, p.curationModel, prompt, temperature)
+	result, err := p.client.generate(callCtx, p.curationModelName(), prompt, temperature)
 	if err != nil {
 		return nil, fmt.Errorf("newsletter generation failed: %w", err)
 	}
@@ -205,7 +203,7 @@ func (p *AIProcessor) GenerateNewsletterContent(ctx context.Context, userID int6
 	result = strings.TrimSpace(result)
 
 	var nr NewsletterResult
-	if err := json.Unmarshal([]byte(extractJSON(result)), &nr); err != nil {
+	if err := parseModelJSON(result, &nr); err != nil {
 		// Fallback: treat entire response as body
 		return &NewsletterResult{Headline: newsletterName, Body: result}, nil
 	}
@@ -277,13 +275,13 @@ func (p *AIProcessor) FindRelatedGroups(ctx context.Context, userID int64, newAr
 	callCtx, cancel := p.withCallTimeout(ctx)
 	defer cancel()
 
-	responseText, err := p.client.generate(callCtx, p.curationModel, prompt, temperature)
+	responseText, err := p.client.generate(callCtx, p.curationModelName(), prompt, temperature)
 	if err != nil {
 		return nil, fmt.Errorf("related groups check failed: %w", err)
 	}
 
 	var result RelatedArticlesResult
-	if err := json.Unmarshal([]byte(extractJSON(responseText)), &result); err != nil {
+	if err := parseModelJSON(responseText, &result); err != nil {
 		return &RelatedArticlesResult{}, nil
 	}
 
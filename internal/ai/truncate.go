@@ -0,0 +1,83 @@
+package ai
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultMaxContentLen is the number of characters of article content sent
+// to a model when the config does not set a limit.
+const defaultMaxContentLen = 3000
+
+// minSecurityContentLen is the floor on security_max_content: however small
+// the security model's context, the check always screens at least this much.
+const minSecurityContentLen = 1000
+
+// truncationMarker is appended to text cut by truncateText.
+const truncationMarker = " [...]"
+
+// maxFeedTitleLen bounds the feed title in the curation prompt.
+const maxFeedTitleLen = 100
+
+// promptLine readies untrusted text, such as a feed's title, for a prompt
+// line outside the <article> block: runs of whitespace, line breaks
+// included, become single spaces, angle brackets are dropped so it can't
+// open or close a tag, and it is cut to maxLen bytes.
+func promptLine(text string, maxLen int) string {
+	text = strings.NewReplacer("<", "", ">", "").Replace(text)
+	return truncateText(strings.Join(strings.Fields(text), " "), maxLen)
+}
+
+// truncateText shortens text to at most maxLen bytes, marker included. It
+// cuts at the last sentence end in the final quarter of the allowed length,
+// falling back to the last word break there, and only then to a hard cut on
+// a rune boundary.
+//
+// Cutting the same text to a smaller limit always yields a prefix of the cut
+// at a larger one, so a security check run with the larger limit has screened
+// everything a downstream model with the smaller limit will see.
+func truncateText(text string, maxLen int) string {
+	if len(text) <= maxLen {
+		return text
+	}
+	if maxLen <= len(truncationMarker) {
+		return text[:runeStart(text, maxLen)]
+	}
+	limit := runeStart(text, maxLen-len(truncationMarker))
+	// Include the byte after the limit so a sentence or word that ends
+	// exactly at it is kept whole.
+	window := text[:limit+1]
+	floor := limit * 3 / 4
+	if i := lastSentenceEnd(window); i >= floor && i > 0 {
+		return text[:i] + truncationMarker
+	}
+	if i := strings.LastIndexAny(window, " \t\r\n"); i >= floor && i > 0 {
+		return strings.TrimRight(text[:i], " \t\r\n") + truncationMarker
+	}
+	return text[:limit] + truncationMarker
+}
+
+// lastSentenceEnd returns the index just past the last sentence-ending
+// punctuation in s that is followed by whitespace, or the index of the last
+// line break, whichever is later; -1 if there is neither.
+func lastSentenceEnd(s string) int {
+	for i := len(s) - 2; i >= 0; i-- {
+		switch s[i] {
+		case '\n':
+			return i
+		case '.', '!', '?':
+			if next := s[i+1]; next == ' ' || next == '\n' || next == '\t' || next == '\r' {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// runeStart returns the largest index <= n that starts a rune in s.
+func runeStart(s string, n int) int {
+	for n > 0 && n < len(s) && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
@@ -78,7 +78,7 @@ func (f *Fetcher) DiscoverFeeds(ctx context.Context, pageURL string) ([]Discover
 
 	// If Content-Type suggests a feed, try to parse it directly.
 	if isFeedContentType(resp.Header.Get("Content-Type")) {
-		if parsed, parseErr := f.parser.ParseString(string(body)); parseErr == nil {
+		if parsed, parseErr := newFeedParser().ParseString(string(body)); parseErr == nil {
 			df := DiscoveredFeed{URL: pageURL, Title: parsed.Title}
 			if parsed.FeedType == "atom" {
 				df.Type = "atom"
@@ -10,11 +10,13 @@ import (
 	"image/jpeg"
 	"image/png"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/matthewjhunter/herald/internal/storage"
 	"golang.org/x/net/html"
 )
 
@@ -25,9 +27,12 @@ const maxFaviconBytes = 256 * 1024 // 256 KB
 // Images larger than this are resized (nearest-neighbour) before storing.
 const maxFaviconDim = 64
 
+// faviconTimeout bounds the whole lookup for one feed: homepage, then icon.
+const faviconTimeout = 15 * time.Second
+
 // FetchFaviconsForFeeds fetches and caches favicons for all subscribed feeds
-// that don't yet have a cached favicon. Each feed is processed at most once;
-// failures are logged and skipped without retry.
+// that don't yet have a cached favicon. Failures are logged and recorded, and
+// the feed isn't tried again for a day.
 //
 // Returns the number of favicons successfully stored.
 func (f *Fetcher) FetchFaviconsForFeeds(ctx context.Context) (int, error) {
@@ -41,13 +46,14 @@ func (f *Fetcher) FetchFaviconsForFeeds(ctx context.Context) (int, error) {
 		if ctx.Err() != nil {
 			break
 		}
-		data, mimeType, err := fetchFavicon(ctx, f.client, feed.URL)
-		if err != nil {
-			log.Printf("herald: favicon fetch failed for feed %d (%s): %v", feed.ID, feed.URL, err)
-			continue
-		}
-		if err := f.store.StoreFeedFavicon(feed.ID, data, mimeType); err != nil {
-			log.Printf("herald: failed to store favicon for feed %d: %v", feed.ID, err)
+		if err := f.FetchFeedFavicon(ctx, feed); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			slog.Warn("favicon fetch failed", "feed_id", feed.ID, "url", feed.URL, "err", err)
+			if err := f.store.MarkFaviconChecked(feed.ID); err != nil {
+				slog.Warn("failed to record favicon check", "feed_id", feed.ID, "err", err)
+			}
 			continue
 		}
 		stored++
@@ -55,20 +61,41 @@ func (f *Fetcher) FetchFaviconsForFeeds(ctx context.Context) (int, error) {
 	return stored, nil
 }
 
-// fetchFavicon fetches the best favicon for the site at feedURL.
+// FetchFeedFavicon fetches and caches the favicon for one feed. The feed's
+// site link is preferred over its own URL, since feeds are often served from
+// a different host than the site they belong to.
+func (f *Fetcher) FetchFeedFavicon(ctx context.Context, feed storage.Feed) error {
+	ctx, cancel := context.WithTimeout(ctx, faviconTimeout)
+	defer cancel()
+
+	siteURL := feed.SiteURL
+	if siteURL == "" {
+		siteURL = feed.URL
+	}
+	data, mimeType, err := fetchFavicon(ctx, f.client, siteURL)
+	if err != nil {
+		return err
+	}
+	if err := f.store.StoreFeedFavicon(feed.ID, data, mimeType); err != nil {
+		return fmt.Errorf("store favicon: %w", err)
+	}
+	return nil
+}
+
+// fetchFavicon fetches the best favicon for the site at siteURL.
 //
 // Strategy:
-//  1. Derive the site root from feedURL's scheme+host.
+//  1. Derive the site root from siteURL's scheme+host.
 //  2. Fetch the site root HTML and look for <link rel="icon"> / <link rel="shortcut icon">.
 //  3. If found, fetch that URL.
 //  4. If not found or the fetch fails, fall back to scheme://host/favicon.ico.
 //
 // The returned data is either a resized PNG (for decodable PNG/JPEG sources)
 // or the raw bytes for formats we can't decode (ICO, GIF, etc.).
-func fetchFavicon(ctx context.Context, client *http.Client, feedURL string) ([]byte, string, error) {
-	parsed, err := url.Parse(feedURL)
+func fetchFavicon(ctx context.Context, client *http.Client, siteURL string) ([]byte, string, error) {
+	parsed, err := url.Parse(siteURL)
 	if err != nil || parsed.Host == "" {
-		return nil, "", fmt.Errorf("invalid feed URL %q: %w", feedURL, err)
+		return nil, "", fmt.Errorf("invalid site URL %q: %w", siteURL, err)
 	}
 	siteRoot := &url.URL{Scheme: parsed.Scheme, Host: parsed.Host}
 
@@ -156,6 +183,7 @@ func extractIconHref(body []byte, base *url.URL) string {
 // fetchAndNormalize fetches iconURL and returns (data, mimeType).
 // PNG and JPEG images are decoded, resized if needed, and re-encoded as PNG.
 // Other formats (ICO, GIF, SVG) are stored as raw bytes up to maxFaviconBytes.
+// Responses that aren't images, such as soft-404 HTML pages, are rejected.
 func fetchAndNormalize(ctx context.Context, client *http.Client, iconURL string) ([]byte, string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iconURL, nil)
 	if err != nil {
@@ -202,6 +230,9 @@ func fetchAndNormalize(ctx context.Context, client *http.Client, iconURL string)
 	if ct == "" || ct == "application/octet-stream" {
 		ct = http.DetectContentType(data)
 	}
+	if !strings.HasPrefix(ct, "image/") {
+		return nil, "", fmt.Errorf("%s is %s, not an image", iconURL, ct)
+	}
 	return data, ct, nil
 }
 
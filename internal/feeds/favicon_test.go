@@ -192,6 +192,21 @@ func TestFetchFavicon_404(t *testing.T) {
 	}
 }
 
+func TestFetchFavicon_RejectsHTML(t *testing.T) {
+	// Some sites answer /favicon.ico with a 200 HTML error page.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Not found</title></head></html>`)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	_, _, err := fetchFavicon(context.Background(), client, srv.URL)
+	if err == nil {
+		t.Error("expected error when the favicon response is not an image")
+	}
+}
+
 // --- FetchFaviconsForFeeds integration ---
 
 func TestFetchFaviconsForFeeds(t *testing.T) {
@@ -262,6 +277,28 @@ func TestFetchFaviconsForFeeds_DoesNotRefetch(t *testing.T) {
 	}
 }
 
+func TestFetchFaviconsForFeeds_BacksOffAfterFailure(t *testing.T) {
+	callCount := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			callCount++
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	store := newFullTextTestStore(t)
+	feedID, _ := store.AddFeed(srv.URL+"/feed.xml", "No Icon", "")
+	store.SubscribeUserToFeed(1, feedID)
+
+	fetcher := NewFetcher(store)
+	fetcher.FetchFaviconsForFeeds(context.Background())
+	fetcher.FetchFaviconsForFeeds(context.Background())
+	if callCount != 1 {
+		t.Errorf("expected 1 lookup after a failure, got %d", callCount)
+	}
+}
+
 // helpers
 
 func makePNG(t *testing.T, w, h int) []byte {
@@ -2,14 +2,21 @@ package feeds
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/xml"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/matthewjhunter/herald/internal/langdetect"
+	"github.com/matthewjhunter/herald/internal/retry"
 	"github.com/matthewjhunter/herald/internal/storage"
 	"github.com/mmcdole/gofeed"
 )
@@ -22,10 +29,67 @@ func sanitizeText(s string) string {
 	return strings.ReplaceAll(s, "\x00", "")
 }
 
+// wordCount counts the words in an HTML fragment's text.
+func wordCount(html string) int {
+	return len(strings.Fields(stripTags(html)))
+}
+
+// articleWordCount counts the words in an article's content, falling back
+// to its summary when the content has no text.
+func articleWordCount(a *storage.Article) int {
+	if n := wordCount(a.Content); n > 0 {
+		return n
+	}
+	return wordCount(a.Summary)
+}
+
 type Fetcher struct {
-	parser *gofeed.Parser
 	client *http.Client
 	store  storage.Store
+	opts   Options
+}
+
+// Options tunes how feeds are fetched. Zero values fall back to the
+// defaults, which match herald's historical serial, single-attempt polling.
+type Options struct {
+	Timeout        time.Duration // per-attempt HTTP timeout; default 30s
+	MaxConcurrency int           // feeds fetched in parallel; default 1
+	MaxRetries     int           // extra attempts after a failed fetch; default 0
+	DetectLanguage bool          // tag new articles with their detected language
+	Proxy          string        // proxy URL for all fetches; "" = HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+}
+
+// Fetch option defaults.
+const (
+	DefaultFetchTimeout        = 30 * time.Second
+	DefaultFetchMaxConcurrency = 1
+)
+
+// retryBackoff is the delay before the first retry; it doubles per attempt.
+var retryBackoff = 2 * time.Second
+
+// OptionsFromConfig reads fetch options from the config file's fetch section.
+func OptionsFromConfig(cfg *storage.Config) Options {
+	return Options{
+		Timeout:        time.Duration(cfg.Fetch.FetchTimeoutSeconds) * time.Second,
+		MaxConcurrency: cfg.Fetch.MaxConcurrency,
+		MaxRetries:     cfg.Fetch.MaxRetries,
+		DetectLanguage: cfg.Fetch.DetectLanguage,
+		Proxy:          cfg.Fetch.FetchProxy,
+	}
+}
+
+func (o Options) withDefaults() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultFetchTimeout
+	}
+	if o.MaxConcurrency < 1 {
+		o.MaxConcurrency = DefaultFetchMaxConcurrency
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+	return o
 }
 
 // OPML structures for parsing
@@ -47,15 +111,44 @@ type OPMLOutline struct {
 	Outlines []OPMLOutline `xml:"outline"`
 }
 
-// NewFetcher creates a new feed fetcher
+// NewFetcher creates a new feed fetcher with default options.
 func NewFetcher(store storage.Store) *Fetcher {
-	parser := gofeed.NewParser()
-	parser.UserAgent = FeedUserAgent
+	return NewFetcherWithOptions(store, Options{})
+}
+
+// NewFetcherWithOptions creates a feed fetcher tuned by opts.
+func NewFetcherWithOptions(store storage.Store, opts Options) *Fetcher {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc(opts.Proxy)
 	return &Fetcher{
-		parser: parser,
-		client: &http.Client{},
+		client: &http.Client{Transport: transport},
 		store:  store,
+		opts:   opts.withDefaults(),
+	}
+}
+
+// proxyFunc returns the transport proxy for a configured proxy URL. An
+// empty or unparseable one falls back to the standard proxy environment
+// variables.
+func proxyFunc(proxy string) func(*http.Request) (*url.URL, error) {
+	if proxy == "" {
+		return http.ProxyFromEnvironment
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		slog.Warn("ignoring invalid fetch proxy, using environment", "proxy", proxy, "err", err)
+		return http.ProxyFromEnvironment
 	}
+	return http.ProxyURL(u)
+}
+
+// newFeedParser returns a fresh parser. gofeed parsers keep per-parse
+// state, so concurrent fetches must not share one.
+func newFeedParser() *gofeed.Parser {
+	parser := gofeed.NewParser()
+	parser.UserAgent = FeedUserAgent
+	parser.RSSTranslator = &rssTranslator{}
+	return parser
 }
 
 // FetchResult holds the outcome of a conditional feed fetch.
@@ -63,13 +156,29 @@ type FetchResult struct {
 	Feed         *gofeed.Feed // nil when NotModified is true
 	ETag         string       // ETag from response (empty if absent)
 	LastModified string       // Last-Modified from response (empty if absent)
-	NotModified  bool         // true when server returned 304
+	BodyHash     string       // SHA-256 of the response body, hex-encoded
+	// NotModified is true when the server returned 304, or returned the
+	// same body as the last stored fetch (feed.BodyHash).
+	NotModified bool
+
+	// ParseError is set when the server returned 200 but the body didn't
+	// parse as a feed (e.g. truncated XML). Feed and the cache headers are
+	// left empty so a bad response is never cached.
+	ParseError error
 }
 
+// parseErrorPrefix marks feeds.last_error values recorded for a ParseError,
+// distinguishing them from network and HTTP failures.
+const parseErrorPrefix = "parse_error: "
+
 // FetchFeed fetches and parses a single feed using conditional HTTP requests.
 // If the feed has stored ETag or Last-Modified values, they are sent as
 // If-None-Match / If-Modified-Since headers. A 304 response skips parsing
-// entirely and returns NotModified=true.
+// entirely and returns NotModified=true, as does a body identical to the
+// last stored one, which covers servers that send neither header. A body
+// that fails to parse is reported in FetchResult.ParseError rather than as
+// an error. A stored feed's custom fetch headers (e.g. Authorization) are
+// added last and win over the conditional ones.
 func (f *Fetcher) FetchFeed(ctx context.Context, feed storage.Feed) (*FetchResult, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
 	if err != nil {
@@ -82,6 +191,15 @@ func (f *Fetcher) FetchFeed(ctx context.Context, feed storage.Feed) (*FetchResul
 	if feed.LastModified != "" {
 		req.Header.Set("If-Modified-Since", feed.LastModified)
 	}
+	if feed.ID != 0 {
+		headers, err := f.store.GetFeedFetchHeaders(feed.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fetch headers for %s: %w", feed.URL, err)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+	}
 
 	resp, err := f.client.Do(req)
 	if err != nil {
@@ -102,98 +220,271 @@ func (f *Fetcher) FetchFeed(ctx context.Context, feed storage.Feed) (*FetchResul
 		return nil, fmt.Errorf("failed to read feed %s: %w", feed.URL, err)
 	}
 
-	parsed, err := f.parser.ParseString(string(body))
+	sum := sha256.Sum256(body)
+	bodyHash := hex.EncodeToString(sum[:])
+	if feed.BodyHash != "" && bodyHash == feed.BodyHash {
+		return &FetchResult{NotModified: true, BodyHash: bodyHash}, nil
+	}
+
+	parsed, err := newFeedParser().ParseString(string(body))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse feed %s: %w", feed.URL, err)
+		return &FetchResult{ParseError: fmt.Errorf("failed to parse feed %s: %w", feed.URL, err)}, nil
 	}
 
 	return &FetchResult{
 		Feed:         parsed,
 		ETag:         resp.Header.Get("ETag"),
 		LastModified: resp.Header.Get("Last-Modified"),
+		BodyHash:     bodyHash,
 	}, nil
 }
 
+// OPMLImportOptions tunes an OPML import.
+type OPMLImportOptions struct {
+	// Validate fetches each feed not already subscribed and skips it as
+	// failed unless it parses as RSS/Atom.
+	Validate bool
+}
+
+// OPMLImportResult summarizes what an OPML import did.
+type OPMLImportResult struct {
+	Added   int      // feeds newly subscribed
+	Skipped int      // feeds already subscribed, or listed twice in the document
+	Failed  []string // feed URLs that failed validation or could not be added
+}
+
 // ImportOPMLReader imports feeds from an OPML reader and subscribes user to them.
-func (f *Fetcher) ImportOPMLReader(r io.Reader, userID int64) error {
+func (f *Fetcher) ImportOPMLReader(r io.Reader, userID int64, opts OPMLImportOptions) (*OPMLImportResult, error) {
 	data, err := io.ReadAll(r)
 	if err != nil {
-		return fmt.Errorf("failed to read OPML: %w", err)
+		return nil, fmt.Errorf("failed to read OPML: %w", err)
 	}
-	return f.importOPMLBytes(data, userID)
+	return f.importOPMLBytes(context.Background(), data, userID, opts)
 }
 
 // ImportOPML imports feeds from an OPML file and subscribes user to them
-func (f *Fetcher) ImportOPML(opmlPath string, userID int64) error {
+func (f *Fetcher) ImportOPML(opmlPath string, userID int64, opts OPMLImportOptions) (*OPMLImportResult, error) {
 	data, err := os.ReadFile(opmlPath)
 	if err != nil {
-		return fmt.Errorf("failed to read OPML file: %w", err)
+		return nil, fmt.Errorf("failed to read OPML file: %w", err)
 	}
-	return f.importOPMLBytes(data, userID)
+	return f.importOPMLBytes(context.Background(), data, userID, opts)
 }
 
-func (f *Fetcher) importOPMLBytes(data []byte, userID int64) error {
+// maxOPMLBytes caps the size of an OPML document fetched over HTTP.
+const maxOPMLBytes = 4 << 20 // 4 MB
+
+// ImportOPMLURL fetches an OPML document from opmlURL and subscribes user to
+// its feeds. The request is bounded by a 30 second timeout and maxOPMLBytes.
+func (f *Fetcher) ImportOPMLURL(ctx context.Context, opmlURL string, userID int64, opts OPMLImportOptions) (*OPMLImportResult, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, opmlURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OPML URL %q: %w", opmlURL, err)
+	}
+	req.Header.Set("User-Agent", FeedUserAgent)
+	req.Header.Set("Accept", "text/x-opml, application/xml, text/xml;q=0.9, */*;q=0.8")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OPML from %s: %w", opmlURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OPML URL %s returned status %d", opmlURL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOPMLBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OPML from %s: %w", opmlURL, err)
+	}
+	if len(data) > maxOPMLBytes {
+		return nil, fmt.Errorf("OPML at %s exceeds %d byte limit", opmlURL, maxOPMLBytes)
+	}
+	result, err := f.importOPMLBytes(ctx, data, userID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid OPML document: %w", opmlURL, err)
+	}
+	return result, nil
+}
+
+// opmlValidateTimeout bounds the fetch that validates each imported feed.
+const opmlValidateTimeout = 30 * time.Second
+
+func (f *Fetcher) importOPMLBytes(ctx context.Context, data []byte, userID int64, opts OPMLImportOptions) (*OPMLImportResult, error) {
 	var opml OPML
 	if err := xml.Unmarshal(data, &opml); err != nil {
-		return fmt.Errorf("failed to parse OPML: %w", err)
+		return nil, fmt.Errorf("failed to parse OPML: %w", err)
+	}
+
+	// Feeds the user already has are skipped, as are repeats within the
+	// document; neither counts as a failure. URLs are compared normalized so
+	// http/https and www variants count as the same feed.
+	subscribed, err := f.store.GetUserFeeds(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	seen := make(map[string]bool, len(subscribed))
+	for _, feed := range subscribed {
+		seen[storage.NormalizeFeedURL(feed.URL)] = true
+	}
+
+	result := &OPMLImportResult{}
+	importFeed := func(outline OPMLOutline, folder string) {
+		key := storage.NormalizeFeedURL(outline.XMLURL)
+		if seen[key] {
+			result.Skipped++
+			return
+		}
+		seen[key] = true
+
+		title := outline.Title
+		if title == "" {
+			title = outline.Text
+		}
+		if title == "" {
+			title = outline.XMLURL
+		}
+
+		if opts.Validate {
+			vctx, cancel := context.WithTimeout(ctx, opmlValidateTimeout)
+			fetched, err := f.FetchFeed(vctx, storage.Feed{URL: outline.XMLURL})
+			cancel()
+			if err == nil && fetched.ParseError != nil {
+				err = fetched.ParseError
+			}
+			if err != nil {
+				slog.Warn("OPML: feed failed validation", "url", outline.XMLURL, "err", err)
+				result.Failed = append(result.Failed, outline.XMLURL)
+				return
+			}
+		}
+
+		// AddFeed hands back the existing row when another user already
+		// has this feed.
+		feedID, err := f.store.AddFeed(outline.XMLURL, title, "")
+		if err != nil {
+			slog.Warn("OPML: add feed failed", "url", outline.XMLURL, "err", err)
+			result.Failed = append(result.Failed, outline.XMLURL)
+			return
+		}
+
+		// Subscribe user to this feed
+		if err := f.store.SubscribeUserToFeed(userID, feedID); err != nil {
+			slog.Warn("OPML: subscribe failed", "url", outline.XMLURL, "err", err)
+			result.Failed = append(result.Failed, outline.XMLURL)
+			return
+		}
+		if folder != "" {
+			if err := f.store.SetFeedFolder(userID, feedID, folder); err != nil {
+				slog.Warn("OPML: set folder failed", "url", outline.XMLURL, "folder", folder, "err", err)
+			}
+		}
+		result.Added++
 	}
 
-	// Process outlines recursively
-	added := 0
-	var processOutlines func(outlines []OPMLOutline)
-	processOutlines = func(outlines []OPMLOutline) {
+	// Process outlines recursively, tracking the folder path
+	var processOutlines func(outlines []OPMLOutline, folder string)
+	processOutlines = func(outlines []OPMLOutline, folder string) {
 		for _, outline := range outlines {
+			if ctx.Err() != nil {
+				return
+			}
 			// If this outline has a feed URL, add it
 			if outline.XMLURL != "" {
-				title := outline.Title
-				if title == "" {
-					title = outline.Text
-				}
-				if title == "" {
-					title = outline.XMLURL
-				}
-
-				feedID, err := f.store.AddFeed(outline.XMLURL, title, "")
-				if err != nil {
-					// Feed might already exist, try to get it
-					feeds, err2 := f.store.GetAllFeeds()
-					if err2 == nil {
-						for _, existingFeed := range feeds {
-							if existingFeed.URL == outline.XMLURL {
-								feedID = existingFeed.ID
-								break
-							}
-						}
-					}
-					if feedID == 0 {
-						fmt.Fprintf(os.Stderr, "Warning: failed to add feed %s: %v\n", outline.XMLURL, err)
-						continue
-					}
-				}
-
-				// Subscribe user to this feed
-				if err := f.store.SubscribeUserToFeed(userID, feedID); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to subscribe to feed %s: %v\n", outline.XMLURL, err)
-				} else {
-					added++
-				}
+				importFeed(outline, folder)
 			}
 
 			// Process nested outlines (folders)
 			if len(outline.Outlines) > 0 {
-				processOutlines(outline.Outlines)
+				processOutlines(outline.Outlines, opmlFolderPath(folder, outline))
 			}
 		}
 	}
 
-	processOutlines(opml.Body.Outlines)
-	fmt.Printf("Added %d feeds from OPML\n", added)
-	return nil
+	processOutlines(opml.Body.Outlines, "")
+	// Logged rather than printed: stdout is the protocol channel for herald-mcp.
+	slog.Info("imported feeds from OPML", "event", "opml_import",
+		"added", result.Added, "skipped", result.Skipped, "failed", len(result.Failed))
+	return result, ctx.Err()
+}
+
+// opmlFolderPath appends a folder outline's name to parent, joining nested
+// folders with "/". Outlines that are themselves feeds don't start a folder.
+func opmlFolderPath(parent string, outline OPMLOutline) string {
+	if outline.XMLURL != "" {
+		return parent
+	}
+	name := strings.TrimSpace(outline.Text)
+	if name == "" {
+		name = strings.TrimSpace(outline.Title)
+	}
+	name = strings.ReplaceAll(name, "/", "-")
+	if name == "" {
+		return parent
+	}
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
+
+// GUID churn detection: a poll counts as churned when more than
+// guidChurnRatio of its items (and at least guidChurnMinItems) re-publish a
+// known URL under a new GUID. After guidChurnPolls consecutive churned polls
+// the feed is switched to URL-based dedupe.
+const (
+	guidChurnRatio    = 0.9
+	guidChurnMinItems = 3
+	guidChurnPolls    = 3
+)
+
+// articleStore is the storage an article batch is written to; both
+// storage.Store and *storage.Tx satisfy it.
+type articleStore interface {
+	GetFeedDedupeByURL(feedID int64) (bool, error)
+	SetFeedDedupeByURL(feedID int64, enabled bool) error
+	GetFeedMaxArticles(feedID int64) (int, error)
+	SetFeedSuggestedInterval(feedID int64, interval time.Duration) error
+	UpdateFeedMetadata(feedID int64, siteLink, generator, lang string) error
+	TrimFeedArticles(feedID int64, keep int) (int, error)
+	RecordGUIDChurn(feedID int64, churned bool) (int, error)
+	UpsertArticle(article *storage.Article) (int64, bool, error)
+	FindDuplicateArticle(feedID int64, guid, title string, publishedDate *time.Time) (int64, error)
+	FindArticleByURL(feedID int64, url string) (int64, string, error)
+	StoreArticleAuthors(articleID int64, authors []storage.ArticleAuthor) error
+	StoreArticleCategories(articleID int64, categories []string) error
 }
 
-// StoreArticles stores articles from a feed into the database
+// StoreArticles stores articles from a feed into the database. Articles are
+// deduplicated on (feed, GUID), or on URL for feeds flagged dedupe_by_url.
+// The feed's advertised polling interval (see SuggestedInterval) and its
+// site link, generator and language are recorded alongside.
 func (f *Fetcher) StoreArticles(feedID int64, feed *gofeed.Feed) (int, error) {
-	stored := 0
+	return f.storeArticles(f.store, feedID, feed)
+}
+
+// StoreArticlesTx is StoreArticles run on a transaction.
+func (f *Fetcher) StoreArticlesTx(tx *storage.Tx, feedID int64, feed *gofeed.Feed) (int, error) {
+	return f.storeArticles(tx, feedID, feed)
+}
+
+func (f *Fetcher) storeArticles(st articleStore, feedID int64, feed *gofeed.Feed) (int, error) {
+	dedupeByURL, err := st.GetFeedDedupeByURL(feedID)
+	if err != nil {
+		slog.Warn("read feed dedupe mode failed", "feed_id", feedID, "err", err)
+	}
+	if err := st.SetFeedSuggestedInterval(feedID, SuggestedInterval(feed)); err != nil {
+		slog.Warn("record feed suggested interval failed", "feed_id", feedID, "err", err)
+	}
+	if err := st.UpdateFeedMetadata(feedID, sanitizeText(feed.Link), sanitizeText(feed.Generator), sanitizeText(feed.Language)); err != nil {
+		slog.Warn("record feed metadata failed", "feed_id", feedID, "err", err)
+	}
+
+	stored, churnHits := 0, 0
 	for _, item := range feed.Items {
 		var author string
 		if item.Author != nil {
@@ -203,7 +494,7 @@ func (f *Fetcher) StoreArticles(feedID int64, feed *gofeed.Feed) (int, error) {
 			FeedID:  feedID,
 			GUID:    item.GUID,
 			Title:   sanitizeText(item.Title),
-			URL:     item.Link,
+			URL:     storage.NormalizeArticleURL(item.Link),
 			Summary: sanitizeText(item.Description),
 			Author:  sanitizeText(author),
 		}
@@ -223,21 +514,40 @@ func (f *Fetcher) StoreArticles(feedID int64, feed *gofeed.Feed) (int, error) {
 			}
 		}
 
+		article.WordCount = articleWordCount(article)
+
+		if f.opts.DetectLanguage {
+			article.Lang = langdetect.Detect(article.Title + "\n" + stripTags(article.Content))
+		}
+
 		// Parse published date
 		if item.PublishedParsed != nil {
 			article.PublishedDate = item.PublishedParsed
 		} else if item.UpdatedParsed != nil {
 			article.PublishedDate = item.UpdatedParsed
 		}
+		article.UpdatedDate = item.UpdatedParsed
 
 		// Skip cross-posted duplicates: same title + published date from a different feed
-		if dupeID, err := f.store.FindDuplicateArticle(article.Title, article.PublishedDate); err == nil && dupeID > 0 {
+		if dupeID, err := st.FindDuplicateArticle(feedID, article.GUID, article.Title, article.PublishedDate); err == nil && dupeID > 0 {
 			continue
 		}
 
-		// Store article (ignore duplicates)
-		articleID, err := f.store.AddArticle(article)
-		if err == nil && articleID > 0 {
+		// A known URL under a different GUID is either skipped (URL dedupe)
+		// or counted towards GUID churn detection.
+		if existingID, existingGUID, err := st.FindArticleByURL(feedID, article.URL); err == nil && existingID > 0 && existingGUID != article.GUID {
+			if dedupeByURL {
+				continue
+			}
+			churnHits++
+		}
+
+		// Store article; a known item is only refreshed if the feed says it
+		// was edited since.
+		articleID, refreshed, err := st.UpsertArticle(article)
+		if err == nil && refreshed {
+			slog.Debug("refreshed edited article", "feed_id", feedID, "article_id", articleID)
+		} else if err == nil && articleID > 0 {
 			stored++
 
 			// Store authors from gofeed (plural, non-deprecated)
@@ -249,25 +559,72 @@ func (f *Fetcher) StoreArticles(feedID int64, feed *gofeed.Feed) (int, error) {
 					}
 				}
 				if len(authors) > 0 {
-					f.store.StoreArticleAuthors(articleID, authors)
+					st.StoreArticleAuthors(articleID, authors)
 				}
 			} else if item.Author != nil && item.Author.Name != "" {
 				// Fallback to deprecated singular Author
-				f.store.StoreArticleAuthors(articleID, []storage.ArticleAuthor{
+				st.StoreArticleAuthors(articleID, []storage.ArticleAuthor{
 					{Name: item.Author.Name, Email: item.Author.Email},
 				})
 			}
 
 			// Store categories
 			if len(item.Categories) > 0 {
-				f.store.StoreArticleCategories(articleID, item.Categories)
+				st.StoreArticleCategories(articleID, item.Categories)
 			}
 		}
 	}
 
+	if !dedupeByURL {
+		trackGUIDChurn(st, feedID, len(feed.Items), churnHits)
+	}
+	if stored > 0 {
+		trimFeed(st, feedID)
+	}
 	return stored, nil
 }
 
+// trimFeed enforces the feed's max_articles cap, if any. Items older than
+// the cap that are still in the feed document are re-added and trimmed
+// again on each poll; the cap bounds storage, not fetch work.
+func trimFeed(st articleStore, feedID int64) {
+	keep, err := st.GetFeedMaxArticles(feedID)
+	if err != nil {
+		slog.Warn("read feed max articles failed", "feed_id", feedID, "err", err)
+		return
+	}
+	if keep <= 0 {
+		return
+	}
+	trimmed, err := st.TrimFeedArticles(feedID, keep)
+	if err != nil {
+		slog.Warn("trim feed articles failed", "feed_id", feedID, "err", err)
+		return
+	}
+	if trimmed > 0 {
+		slog.Debug("trimmed feed articles", "feed_id", feedID, "keep", keep, "trimmed", trimmed)
+	}
+}
+
+// trackGUIDChurn records whether this poll looked like GUID churn and flags
+// the feed for URL-based dedupe once churn persists for guidChurnPolls polls.
+func trackGUIDChurn(st articleStore, feedID int64, items, churnHits int) {
+	churned := items >= guidChurnMinItems && float64(churnHits) > guidChurnRatio*float64(items)
+	polls, err := st.RecordGUIDChurn(feedID, churned)
+	if err != nil {
+		slog.Warn("record guid churn failed", "feed_id", feedID, "err", err)
+		return
+	}
+	if polls < guidChurnPolls {
+		return
+	}
+	if err := st.SetFeedDedupeByURL(feedID, true); err != nil {
+		slog.Warn("enable URL dedupe failed", "feed_id", feedID, "err", err)
+		return
+	}
+	slog.Info("feed regenerates GUIDs; deduplicating by URL", "event", "feed_dedupe_by_url", "feed_id", feedID, "polls", polls)
+}
+
 // mediaGroupHTML extracts a description and thumbnail from a <media:group>
 // extension element (used by YouTube Atom feeds) and returns a simple HTML
 // snippet, or "" if nothing useful is found.
@@ -321,63 +678,132 @@ type FetchStats struct {
 	FeedsTotal       int // total feeds attempted
 	FeedsDownloaded  int // feeds that returned new content (HTTP 200)
 	FeedsNotModified int // feeds that returned 304
-	FeedsErrored     int // feeds that failed
+	FeedsErrored     int // feeds that failed, including parse failures
+	FeedsParseFailed int // feeds that returned 200 with an unparseable body
 	NewArticles      int // articles newly written to DB
 }
 
+// FetchFeedWithRetry fetches a feed with the configured per-attempt timeout,
+// retrying failed attempts up to MaxRetries times with doubling backoff.
+// Parse failures are retried too, since a truncated body is often transient.
+func (f *Fetcher) FetchFeedWithRetry(ctx context.Context, feed storage.Feed) (*FetchResult, error) {
+	var result *FetchResult
+	err := retry.Do(ctx, f.opts.MaxRetries, retryBackoff, func() error {
+		attemptCtx, cancel := context.WithTimeout(ctx, f.opts.Timeout)
+		defer cancel()
+		var err error
+		result, err = f.FetchFeed(attemptCtx, feed)
+		if err == nil && result.ParseError != nil {
+			return result.ParseError
+		}
+		return err
+	}, func(attempt int, err error) {
+		slog.Debug("retrying feed fetch", "feed_id", feed.ID, "attempt", attempt, "err", err)
+	})
+	if result != nil && result.ParseError != nil && err == result.ParseError {
+		// A parse failure is reported on the result, not as an error.
+		return result, nil
+	}
+	return result, err
+}
+
 // FetchAllFeeds fetches all enabled feeds and stores their articles
 func (f *Fetcher) FetchAllFeeds(ctx context.Context) (*FetchStats, error) {
 	feeds, err := f.store.GetAllFeeds()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get feeds: %w", err)
 	}
+	return f.FetchFeeds(ctx, feeds), nil
+}
 
+// FetchFeeds fetches the given feeds, up to MaxConcurrency at a time, and
+// stores their articles. Per-feed failures are recorded on the feed and
+// counted in the returned stats rather than aborting the cycle.
+func (f *Fetcher) FetchFeeds(ctx context.Context, feeds []storage.Feed) *FetchStats {
+	start := time.Now()
+	slog.Info("fetch started", "event", "fetch_start", "feeds", len(feeds), "concurrency", f.opts.MaxConcurrency)
+
+	var mu sync.Mutex
 	stats := &FetchStats{FeedsTotal: len(feeds)}
+	defer func() {
+		d := time.Since(start)
+		slog.Info("fetch finished", "event", "fetch_finish",
+			"feeds", stats.FeedsTotal, "downloaded", stats.FeedsDownloaded, "not_modified", stats.FeedsNotModified,
+			"errors", stats.FeedsErrored, "parse_errors", stats.FeedsParseFailed, "new_articles", stats.NewArticles, "duration_ms", d.Milliseconds())
+	}()
+
+	sem := make(chan struct{}, f.opts.MaxConcurrency)
+	var wg sync.WaitGroup
 	for _, feed := range feeds {
-		// Add timeout per feed
-		feedCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		result, err := f.FetchFeed(feedCtx, feed)
-		cancel()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to fetch feed %s: %v\n", feed.URL, err)
-			f.store.UpdateFeedError(feed.ID, err.Error())
-			stats.FeedsErrored++
-			continue
-		}
-
-		if result.NotModified {
-			stats.FeedsNotModified++
-			// Clear any previous error and update last_fetched
-			if err := f.store.ClearFeedError(feed.ID); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to update last_fetched for %s: %v\n", feed.URL, err)
-			}
-			continue
+		if ctx.Err() != nil {
+			break
 		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(feed storage.Feed) {
+			defer func() { <-sem; wg.Done() }()
+			f.fetchAndStore(ctx, feed, stats, &mu)
+		}(feed)
+	}
+	wg.Wait()
 
-		stats.FeedsDownloaded++
-
-		// Store articles
-		stored, err := f.StoreArticles(feed.ID, result.Feed)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: error storing articles from %s: %v\n", feed.URL, err)
-		}
-		stats.NewArticles += stored
+	return stats
+}
 
-		// Persist cache headers for next conditional request
-		if result.ETag != "" || result.LastModified != "" {
-			f.store.UpdateFeedCacheHeaders(feed.ID, result.ETag, result.LastModified)
-		}
+// fetchAndStore runs one feed through fetch, store and bookkeeping, adding
+// its outcome to stats under mu.
+func (f *Fetcher) fetchAndStore(ctx context.Context, feed storage.Feed, stats *FetchStats, mu *sync.Mutex) {
+	result, err := f.FetchFeedWithRetry(ctx, feed)
+	if err != nil {
+		slog.Warn("fetch feed failed", "feed_id", feed.ID, "url", feed.URL, "err", err)
+		f.store.UpdateFeedError(feed.ID, err.Error())
+		mu.Lock()
+		stats.FeedsErrored++
+		mu.Unlock()
+		return
+	}
 
-		// Store blog homepage URL from feed metadata
-		if result.Feed.Link != "" && result.Feed.Link != feed.SiteURL {
-			f.store.UpdateFeedSiteURL(feed.ID, result.Feed.Link)
-		}
+	if result.ParseError != nil {
+		// Leave the cache headers alone so the next cycle refetches in full
+		// instead of revalidating against a response we couldn't use.
+		slog.Warn("parse feed failed", "feed_id", feed.ID, "url", feed.URL, "err", result.ParseError)
+		f.store.UpdateFeedError(feed.ID, parseErrorPrefix+result.ParseError.Error())
+		mu.Lock()
+		stats.FeedsErrored++
+		stats.FeedsParseFailed++
+		mu.Unlock()
+		return
+	}
 
+	if result.NotModified {
 		// Clear any previous error and update last_fetched
 		if err := f.store.ClearFeedError(feed.ID); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to update last_fetched for %s: %v\n", feed.URL, err)
+			slog.Warn("update last_fetched failed", "feed_id", feed.ID, "err", err)
 		}
+		mu.Lock()
+		stats.FeedsNotModified++
+		mu.Unlock()
+		return
 	}
 
-	return stats, nil
+	// Store articles
+	stored, err := f.StoreArticles(feed.ID, result.Feed)
+	bodyHash := result.BodyHash
+	if err != nil {
+		slog.Warn("store articles failed", "feed_id", feed.ID, "url", feed.URL, "err", err)
+		// Don't let the next fetch skip this body as unchanged.
+		bodyHash = ""
+	}
+	mu.Lock()
+	stats.FeedsDownloaded++
+	stats.NewArticles += stored
+	mu.Unlock()
+
+	// Persist cache headers for next conditional request
+	f.store.UpdateFeedCacheHeaders(feed.ID, result.ETag, result.LastModified, bodyHash)
+
+	// Clear any previous error and update last_fetched
+	if err := f.store.ClearFeedError(feed.ID); err != nil {
+		slog.Warn("update last_fetched failed", "feed_id", feed.ID, "err", err)
+	}
 }
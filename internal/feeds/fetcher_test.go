@@ -1,19 +1,23 @@
 package feeds
 
 import (
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/matthewjhunter/herald/internal/storage"
 	"github.com/mmcdole/gofeed"
 	ext "github.com/mmcdole/gofeed/extensions"
+	"gopkg.in/yaml.v3"
 )
 
 func newTestStore(t *testing.T) (*storage.SQLiteStore, func()) {
@@ -50,7 +54,7 @@ func TestImportOPML(t *testing.T) {
 	path := writeOPML(t, opml)
 	fetcher := NewFetcher(store)
 
-	if err := fetcher.ImportOPML(path, 1); err != nil {
+	if _, err := fetcher.ImportOPML(path, 1, OPMLImportOptions{}); err != nil {
 		t.Fatalf("ImportOPML failed: %v", err)
 	}
 
@@ -72,11 +76,8 @@ func TestImportOPML(t *testing.T) {
 	}
 }
 
-func TestImportOPML_NestedFolders(t *testing.T) {
-	store, cleanup := newTestStore(t)
-	defer cleanup()
-
-	opml := `<?xml version="1.0" encoding="UTF-8"?>
+// nestedOPML holds three feeds spread across nested folders.
+const nestedOPML = `<?xml version="1.0" encoding="UTF-8"?>
 <opml version="2.0">
   <body>
     <outline text="Technology">
@@ -89,10 +90,14 @@ func TestImportOPML_NestedFolders(t *testing.T) {
   </body>
 </opml>`
 
-	path := writeOPML(t, opml)
+func TestImportOPML_NestedFolders(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	path := writeOPML(t, nestedOPML)
 	fetcher := NewFetcher(store)
 
-	if err := fetcher.ImportOPML(path, 1); err != nil {
+	if _, err := fetcher.ImportOPML(path, 1, OPMLImportOptions{}); err != nil {
 		t.Fatalf("ImportOPML failed: %v", err)
 	}
 
@@ -103,6 +108,61 @@ func TestImportOPML_NestedFolders(t *testing.T) {
 	if len(feeds) != 3 {
 		t.Fatalf("expected 3 feeds from nested OPML, got %d", len(feeds))
 	}
+
+	byFolder, err := store.GetFeedsByFolder(1)
+	if err != nil {
+		t.Fatalf("GetFeedsByFolder failed: %v", err)
+	}
+	want := map[string]string{
+		"https://example.com/krebs.xml": "Technology/Security",
+		"https://example.com/dev.xml":   "Technology",
+		"https://example.com/top.xml":   "",
+	}
+	for folder, feeds := range byFolder {
+		for _, feed := range feeds {
+			if want[feed.URL] != folder {
+				t.Errorf("%s filed under %q, want %q", feed.URL, folder, want[feed.URL])
+			}
+		}
+	}
+}
+
+func TestImportOPMLURL(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/subscriptions.opml":
+			w.Header().Set("Content-Type", "text/x-opml")
+			fmt.Fprint(w, nestedOPML)
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html><body>not an outline</body></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	fetcher := NewFetcher(store)
+	if _, err := fetcher.ImportOPMLURL(context.Background(), ts.URL+"/subscriptions.opml", 1, OPMLImportOptions{}); err != nil {
+		t.Fatalf("ImportOPMLURL failed: %v", err)
+	}
+	feeds, err := store.GetUserFeeds(1)
+	if err != nil {
+		t.Fatalf("GetUserFeeds failed: %v", err)
+	}
+	if len(feeds) != 3 {
+		t.Fatalf("expected 3 feeds from nested OPML URL, got %d", len(feeds))
+	}
+
+	if _, err := fetcher.ImportOPMLURL(context.Background(), ts.URL+"/page.html", 1, OPMLImportOptions{}); err == nil {
+		t.Error("expected error for non-OPML URL, got nil")
+	}
+	if _, err := fetcher.ImportOPMLURL(context.Background(), ts.URL+"/missing.opml", 1, OPMLImportOptions{}); err == nil {
+		t.Error("expected error for 404 URL, got nil")
+	}
 }
 
 func TestImportOPML_DuplicateFeeds(t *testing.T) {
@@ -120,10 +180,10 @@ func TestImportOPML_DuplicateFeeds(t *testing.T) {
 	fetcher := NewFetcher(store)
 
 	// Import twice
-	if err := fetcher.ImportOPML(path, 1); err != nil {
+	if _, err := fetcher.ImportOPML(path, 1, OPMLImportOptions{}); err != nil {
 		t.Fatalf("first ImportOPML failed: %v", err)
 	}
-	if err := fetcher.ImportOPML(path, 1); err != nil {
+	if _, err := fetcher.ImportOPML(path, 1, OPMLImportOptions{}); err != nil {
 		t.Fatalf("second ImportOPML failed: %v", err)
 	}
 
@@ -136,12 +196,67 @@ func TestImportOPML_DuplicateFeeds(t *testing.T) {
 	}
 }
 
+func TestImportOPML_Result(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Feed</title></channel></rss>`)
+	}))
+	defer ts.Close()
+
+	// The user already has "old"; "new" is listed twice; "broken" won't parse.
+	oldID, _ := store.AddFeed(ts.URL+"/old.xml", "Old", "")
+	if err := store.SubscribeUserToFeed(1, oldID); err != nil {
+		t.Fatalf("SubscribeUserToFeed: %v", err)
+	}
+	opml := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <body>
+    <outline text="Old" type="rss" xmlUrl="%[1]s/old.xml"/>
+    <outline text="Folder">
+      <outline text="New" type="rss" xmlUrl="%[1]s/new.xml"/>
+    </outline>
+    <outline text="New again" type="rss" xmlUrl="%[1]s/new.xml"/>
+    <outline text="Broken" type="rss" xmlUrl="%[1]s/broken.xml"/>
+  </body>
+</opml>`, ts.URL)
+	path := writeOPML(t, opml)
+	fetcher := NewFetcher(store)
+
+	result, err := fetcher.ImportOPML(path, 1, OPMLImportOptions{Validate: true})
+	if err != nil {
+		t.Fatalf("ImportOPML failed: %v", err)
+	}
+	if result.Added != 1 || result.Skipped != 2 {
+		t.Errorf("added/skipped = %d/%d, want 1/2", result.Added, result.Skipped)
+	}
+	if len(result.Failed) != 1 || result.Failed[0] != ts.URL+"/broken.xml" {
+		t.Errorf("failed = %v, want only the broken feed", result.Failed)
+	}
+
+	// Without validation the broken feed is subscribed like any other, and
+	// everything else is now a skip.
+	result, err = fetcher.ImportOPML(path, 1, OPMLImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportOPML failed: %v", err)
+	}
+	if result.Added != 1 || result.Skipped != 3 || len(result.Failed) != 0 {
+		t.Errorf("unvalidated import = %+v, want 1 added, 3 skipped", result)
+	}
+}
+
 func TestImportOPML_MissingFile(t *testing.T) {
 	store, cleanup := newTestStore(t)
 	defer cleanup()
 
 	fetcher := NewFetcher(store)
-	err := fetcher.ImportOPML("/nonexistent/feeds.opml", 1)
+	_, err := fetcher.ImportOPML("/nonexistent/feeds.opml", 1, OPMLImportOptions{})
 	if err == nil {
 		t.Fatal("expected error for missing OPML file, got nil")
 	}
@@ -199,6 +314,36 @@ func TestStoreArticles(t *testing.T) {
 	}
 }
 
+func TestStoreArticles_WordCount(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	feedID, _ := store.AddFeed("https://example.com/feed.xml", "Test Feed", "")
+	feed := &gofeed.Feed{
+		Items: []*gofeed.Item{
+			{GUID: "body", Title: "Body", Link: "https://example.com/body",
+				Content: "<p>One two <b>three</b></p>\n<p>four five.</p>"},
+			{GUID: "summary", Title: "Summary", Link: "https://example.com/summary",
+				Description: "Only a summary here"},
+			{GUID: "empty", Title: "Empty", Link: "https://example.com/empty"},
+		},
+	}
+	if _, err := NewFetcher(store).StoreArticles(feedID, feed); err != nil {
+		t.Fatalf("StoreArticles failed: %v", err)
+	}
+
+	articles, err := store.GetUnreadArticles(10)
+	if err != nil {
+		t.Fatalf("GetUnreadArticles failed: %v", err)
+	}
+	want := map[string]int{"body": 5, "summary": 4, "empty": 0}
+	for _, a := range articles {
+		if a.WordCount != want[a.GUID] {
+			t.Errorf("%s: WordCount = %d, want %d", a.GUID, a.WordCount, want[a.GUID])
+		}
+	}
+}
+
 func TestStoreArticles_Duplicates(t *testing.T) {
 	store, cleanup := newTestStore(t)
 	defer cleanup()
@@ -218,10 +363,9 @@ func TestStoreArticles_Duplicates(t *testing.T) {
 		t.Errorf("first store: expected 1, got %d", stored1)
 	}
 
-	// StoreArticles may report >0 on duplicates because SQLite's
-	// LastInsertId returns a stale rowid with ON CONFLICT DO NOTHING.
-	// The important invariant is that the DB only has one row.
-	fetcher.StoreArticles(feedID, feed)
+	if stored2, _ := fetcher.StoreArticles(feedID, feed); stored2 != 0 {
+		t.Errorf("second store: expected 0, got %d", stored2)
+	}
 
 	articles, _ := store.GetUnreadArticles(10)
 	if len(articles) != 1 {
@@ -229,6 +373,252 @@ func TestStoreArticles_Duplicates(t *testing.T) {
 	}
 }
 
+func TestStoreArticles_EditedItem(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	feedID, _ := store.AddFeed("https://example.com/feed.xml", "Test Feed", "")
+	store.SubscribeUserToFeed(1, feedID)
+	fetcher := NewFetcher(store)
+
+	published := time.Now().Add(-48 * time.Hour)
+	item := func(title, content string, updated time.Time) *gofeed.Feed {
+		return &gofeed.Feed{Items: []*gofeed.Item{{
+			GUID: "edited", Title: title, Link: "https://example.com/edited", Content: content,
+			PublishedParsed: &published, UpdatedParsed: &updated,
+		}}}
+	}
+
+	if stored, _ := fetcher.StoreArticles(feedID, item("Original", "<p>first draft</p>", published)); stored != 1 {
+		t.Fatalf("first store: expected 1, got %d", stored)
+	}
+	articles, _ := store.GetUnreadArticles(10)
+	if len(articles) != 1 {
+		t.Fatalf("expected 1 article, got %d", len(articles))
+	}
+	id := articles[0].ID
+	score := 8.0
+	store.UpdateReadState(1, id, false, &score, &score, nil)
+	store.UpdateReadState(1, id, true, nil, nil, nil)
+	store.UpdateArticleAISummary(1, id, "summary of the first draft")
+
+	// An unchanged timestamp leaves the stored copy alone.
+	fetcher.StoreArticles(feedID, item("Original", "<p>first draft</p>", published))
+	if s, _ := store.GetArticleSummary(1, id); s == nil {
+		t.Fatal("summary dropped without an edit")
+	}
+
+	edited := time.Now().Add(time.Minute)
+	if stored, _ := fetcher.StoreArticles(feedID, item("Corrected", "<p>second draft</p>", edited)); stored != 0 {
+		t.Errorf("an edit is not a new article, got stored=%d", stored)
+	}
+	a, err := store.GetArticle(id)
+	if err != nil {
+		t.Fatalf("GetArticle: %v", err)
+	}
+	if a.Title != "Corrected" || a.Content != "<p>second draft</p>" {
+		t.Errorf("article not refreshed: %q %q", a.Title, a.Content)
+	}
+	if s, _ := store.GetArticleSummary(1, id); s != nil {
+		t.Errorf("stale summary kept: %q", s.AISummary)
+	}
+	if unread, _ := store.GetUnreadArticles(10); len(unread) != 0 {
+		t.Error("edit should not clobber read state")
+	}
+	if scores, _ := store.GetInterestScores(1, []int64{id}); scores[id] != score {
+		t.Errorf("interest score = %v, want %v", scores[id], score)
+	}
+	unscored, err := store.GetUnscoredArticlesForUser(1, 10, 0)
+	if err != nil {
+		t.Fatalf("GetUnscoredArticlesForUser: %v", err)
+	}
+	if len(unscored) != 1 || unscored[0].ID != id {
+		t.Errorf("edited article should be queued for scoring again, got %v", unscored)
+	}
+}
+
+// churnFeed returns a feed whose items keep their URLs but carry GUIDs
+// unique to the given poll, as GUID-regenerating feeds do.
+func churnFeed(poll int) *gofeed.Feed {
+	feed := &gofeed.Feed{}
+	for i := 1; i <= 3; i++ {
+		feed.Items = append(feed.Items, &gofeed.Item{
+			GUID:  fmt.Sprintf("poll-%d-item-%d", poll, i),
+			Title: fmt.Sprintf("Article %d", i),
+			Link:  fmt.Sprintf("https://Example.com/post/%d", i),
+		})
+	}
+	return feed
+}
+
+func countArticles(t *testing.T, store *storage.SQLiteStore) int {
+	t.Helper()
+	articles, err := store.GetUnreadArticles(100)
+	if err != nil {
+		t.Fatalf("GetUnreadArticles: %v", err)
+	}
+	return len(articles)
+}
+
+func TestStoreArticles_DedupeByURL(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	feedID, _ := store.AddFeed("https://example.com/feed.xml", "Churny Feed", "")
+	if err := store.SetFeedDedupeByURL(feedID, true); err != nil {
+		t.Fatalf("SetFeedDedupeByURL: %v", err)
+	}
+
+	fetcher := NewFetcher(store)
+	for poll := 1; poll <= 2; poll++ {
+		if _, err := fetcher.StoreArticles(feedID, churnFeed(poll)); err != nil {
+			t.Fatalf("StoreArticles poll %d: %v", poll, err)
+		}
+	}
+
+	if n := countArticles(t, store); n != 3 {
+		t.Errorf("expected 3 articles with URL dedupe, got %d", n)
+	}
+}
+
+func TestStoreArticles_MaxArticles(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	feedID, _ := store.AddFeed("https://example.com/feed.xml", "Busy Feed", "")
+	userID, err := store.CreateUser("alice")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	batch := func(from, to int) *gofeed.Feed {
+		feed := &gofeed.Feed{}
+		for i := from; i <= to; i++ {
+			pub := base.Add(time.Duration(i) * time.Hour)
+			feed.Items = append(feed.Items, &gofeed.Item{
+				GUID:            fmt.Sprintf("item-%d", i),
+				Title:           fmt.Sprintf("Article %d", i),
+				Link:            fmt.Sprintf("https://example.com/post/%d", i),
+				PublishedParsed: &pub,
+			})
+		}
+		return feed
+	}
+	articleID := func(i int) int64 {
+		id, _, err := store.FindArticleByURL(feedID, fmt.Sprintf("https://example.com/post/%d", i))
+		if err != nil {
+			t.Fatalf("FindArticleByURL: %v", err)
+		}
+		return id
+	}
+
+	fetcher := NewFetcher(store)
+	if _, err := fetcher.StoreArticles(feedID, batch(1, 3)); err != nil {
+		t.Fatalf("StoreArticles: %v", err)
+	}
+	if err := store.UpdateStarred(userID, articleID(1), true); err != nil {
+		t.Fatalf("UpdateStarred: %v", err)
+	}
+	if err := store.SetFeedMaxArticles(feedID, 2); err != nil {
+		t.Fatalf("SetFeedMaxArticles: %v", err)
+	}
+	if _, err := fetcher.StoreArticles(feedID, batch(4, 6)); err != nil {
+		t.Fatalf("StoreArticles: %v", err)
+	}
+
+	// The two newest unstarred articles survive, plus the starred one.
+	for i, want := range map[int]bool{1: true, 2: false, 3: false, 4: false, 5: true, 6: true} {
+		if got := articleID(i) > 0; got != want {
+			t.Errorf("article %d kept = %v, want %v", i, got, want)
+		}
+	}
+
+	// Trimmed items still listed by the feed aren't stored again.
+	n, err := fetcher.StoreArticles(feedID, batch(1, 6))
+	if err != nil {
+		t.Fatalf("StoreArticles: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("re-polling the same items stored %d articles, want 0", n)
+	}
+	for _, i := range []int{2, 3, 4} {
+		if articleID(i) > 0 {
+			t.Errorf("trimmed article %d was stored again", i)
+		}
+	}
+
+	if err := store.SetFeedMaxArticles(feedID, -1); err == nil {
+		t.Error("expected error for a negative cap")
+	}
+}
+
+func TestStoreArticles_GUIDChurnDetection(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	feedID, _ := store.AddFeed("https://example.com/feed.xml", "Churny Feed", "")
+	fetcher := NewFetcher(store)
+
+	// The first poll is genuinely new; each later poll re-publishes the
+	// same URLs under new GUIDs until guidChurnPolls churned polls flag it.
+	polls := 1 + guidChurnPolls
+	for poll := 1; poll <= polls; poll++ {
+		if _, err := fetcher.StoreArticles(feedID, churnFeed(poll)); err != nil {
+			t.Fatalf("StoreArticles poll %d: %v", poll, err)
+		}
+		flagged, _ := store.GetFeedDedupeByURL(feedID)
+		if want := poll == polls; flagged != want {
+			t.Fatalf("after poll %d: dedupe_by_url = %v, want %v", poll, flagged, want)
+		}
+	}
+	before := countArticles(t, store)
+
+	// Once flagged, further churn adds nothing.
+	fetcher.StoreArticles(feedID, churnFeed(polls+1))
+	if n := countArticles(t, store); n != before {
+		t.Errorf("expected %d articles after URL dedupe kicked in, got %d", before, n)
+	}
+}
+
+func TestTrackGUIDChurn_RatioIsExclusive(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	// Exactly guidChurnRatio of the items churning is not enough.
+	atRatio, _ := store.AddFeed("https://example.com/at-ratio.xml", "At Ratio", "")
+	for range guidChurnPolls {
+		trackGUIDChurn(store, atRatio, 10, 9)
+	}
+	if flagged, _ := store.GetFeedDedupeByURL(atRatio); flagged {
+		t.Error("churn at exactly the ratio should not flag the feed")
+	}
+
+	above, _ := store.AddFeed("https://example.com/above.xml", "Above Ratio", "")
+	for range guidChurnPolls {
+		trackGUIDChurn(store, above, 10, 10)
+	}
+	if flagged, _ := store.GetFeedDedupeByURL(above); !flagged {
+		t.Error("churn above the ratio should flag the feed")
+	}
+}
+
+func TestStoreArticles_StableGUIDsNotFlagged(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	feedID, _ := store.AddFeed("https://example.com/feed.xml", "Stable Feed", "")
+	fetcher := NewFetcher(store)
+	for i := 0; i < guidChurnPolls+2; i++ {
+		fetcher.StoreArticles(feedID, churnFeed(1))
+	}
+	if flagged, _ := store.GetFeedDedupeByURL(feedID); flagged {
+		t.Error("feed with stable GUIDs should not be flagged for URL dedupe")
+	}
+	if n := countArticles(t, store); n != 3 {
+		t.Errorf("expected 3 articles, got %d", n)
+	}
+}
+
 func TestStoreArticles_NilAuthor(t *testing.T) {
 	store, cleanup := newTestStore(t)
 	defer cleanup()
@@ -506,3 +896,306 @@ func TestFetchFeedConditionalErrorStatus(t *testing.T) {
 		t.Fatal("expected error for 500 status")
 	}
 }
+
+// --- Fetch options ---
+
+func TestFetchFeedWithRetry_AppliesTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	fetcher := NewFetcherWithOptions(store, Options{Timeout: 50 * time.Millisecond})
+	start := time.Now()
+	_, err := fetcher.FetchFeedWithRetry(context.Background(), storage.Feed{URL: srv.URL})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("fetch took %s; custom timeout was not applied", elapsed)
+	}
+}
+
+func TestFetchFeedWithRetry_Retries(t *testing.T) {
+	defer func(d time.Duration) { retryBackoff = d }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, testRSS)
+	}))
+	defer srv.Close()
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	// Two retries are enough to reach the third, successful attempt.
+	fetcher := NewFetcherWithOptions(store, Options{MaxRetries: 2})
+	result, err := fetcher.FetchFeedWithRetry(context.Background(), storage.Feed{URL: srv.URL})
+	if err != nil {
+		t.Fatalf("FetchFeedWithRetry: %v", err)
+	}
+	if result.Feed == nil || len(result.Feed.Items) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	// Without retries the first failure is final.
+	calls.Store(0)
+	if _, err := NewFetcher(store).FetchFeedWithRetry(context.Background(), storage.Feed{URL: srv.URL}); err == nil {
+		t.Error("expected error with no retries")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected 1 attempt without retries, got %d", n)
+	}
+}
+
+func TestFetchFeeds_Concurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/broken") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>%[1]s</title>
+<item><guid>%[1]s-1</guid><title>Item %[1]s</title><link>https://example.com%[1]s</link></item>
+</channel></rss>`, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	var feedList []storage.Feed
+	for _, path := range []string{"/a", "/b", "/c", "/d", "/broken"} {
+		id, err := store.AddFeed(srv.URL+path, path, "")
+		if err != nil {
+			t.Fatalf("AddFeed: %v", err)
+		}
+		feedList = append(feedList, storage.Feed{ID: id, URL: srv.URL + path})
+	}
+
+	fetcher := NewFetcherWithOptions(store, Options{MaxConcurrency: 3})
+	stats := fetcher.FetchFeeds(context.Background(), feedList)
+	if stats.FeedsTotal != 5 || stats.FeedsDownloaded != 4 || stats.FeedsErrored != 1 || stats.NewArticles != 4 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestFetchFeeds_ParseError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"truncated"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Cut`)
+	}))
+	defer srv.Close()
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	feedID, err := store.AddFeed(srv.URL, "Truncated", "")
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	if err := store.SubscribeUserToFeed(1, feedID); err != nil {
+		t.Fatalf("SubscribeUserToFeed: %v", err)
+	}
+
+	result, err := NewFetcher(store).FetchFeed(context.Background(), storage.Feed{URL: srv.URL})
+	if err != nil {
+		t.Fatalf("FetchFeed: %v", err)
+	}
+	if result.ParseError == nil || result.Feed != nil || result.ETag != "" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	stats := NewFetcher(store).FetchFeeds(context.Background(), []storage.Feed{{ID: feedID, URL: srv.URL}})
+	if stats.FeedsErrored != 1 || stats.FeedsParseFailed != 1 || stats.FeedsDownloaded != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	feeds, err := store.GetUserFeeds(1)
+	if err != nil {
+		t.Fatalf("GetUserFeeds: %v", err)
+	}
+	if len(feeds) != 1 {
+		t.Fatalf("expected 1 feed, got %d", len(feeds))
+	}
+	got := feeds[0]
+	if got.LastError == nil || !strings.HasPrefix(*got.LastError, "parse_error: ") {
+		t.Errorf("last_error = %v, want a parse_error prefix", got.LastError)
+	}
+	if got.ETag != "" || got.LastModified != "" {
+		t.Errorf("cache headers stored after a parse error: etag=%q last_modified=%q", got.ETag, got.LastModified)
+	}
+}
+
+func TestFetchFeeds_UnchangedBody(t *testing.T) {
+	body := testRSS
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// No validators, so only the body hash can detect an unchanged feed.
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	feedID, err := store.AddFeed(srv.URL, "Unchanged", "")
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	if err := store.SubscribeUserToFeed(1, feedID); err != nil {
+		t.Fatalf("SubscribeUserToFeed: %v", err)
+	}
+	fetcher := NewFetcher(store)
+	fetch := func() *FetchStats {
+		t.Helper()
+		feeds, err := store.GetUserFeeds(1)
+		if err != nil || len(feeds) != 1 {
+			t.Fatalf("GetUserFeeds: %v (%d feeds)", err, len(feeds))
+		}
+		return fetcher.FetchFeeds(context.Background(), feeds)
+	}
+
+	if stats := fetch(); stats.FeedsDownloaded != 1 || stats.NewArticles != 1 {
+		t.Fatalf("first fetch: unexpected stats: %+v", stats)
+	}
+	feeds, _ := store.GetUserFeeds(1)
+	firstHash := feeds[0].BodyHash
+	if firstHash == "" {
+		t.Fatal("expected body hash to be stored")
+	}
+
+	if stats := fetch(); stats.FeedsNotModified != 1 || stats.FeedsDownloaded != 0 {
+		t.Errorf("unchanged fetch: unexpected stats: %+v", stats)
+	}
+
+	body = strings.Replace(testRSS, "Hello world", "Hello again", 1)
+	if stats := fetch(); stats.FeedsDownloaded != 1 || stats.FeedsNotModified != 0 {
+		t.Errorf("changed fetch: unexpected stats: %+v", stats)
+	}
+	feeds, _ = store.GetUserFeeds(1)
+	if feeds[0].BodyHash == "" || feeds[0].BodyHash == firstHash {
+		t.Errorf("body hash not updated: %q", feeds[0].BodyHash)
+	}
+}
+
+func TestFetchFeedCustomHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("If-None-Match") != `"v1"` {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, testRSS)
+	}))
+	defer srv.Close()
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	feedID, err := store.AddFeed(srv.URL, "Private", "")
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	fetcher := NewFetcher(store)
+	feed := storage.Feed{ID: feedID, URL: srv.URL, ETag: `"v1"`}
+
+	if _, err := fetcher.FetchFeed(context.Background(), feed); err == nil {
+		t.Fatal("expected 401 without configured headers")
+	}
+
+	if err := store.SetFeedFetchHeaders(feedID, map[string]string{"authorization": "Bearer token"}); err != nil {
+		t.Fatalf("SetFeedFetchHeaders: %v", err)
+	}
+	result, err := fetcher.FetchFeed(context.Background(), feed)
+	if err != nil {
+		t.Fatalf("FetchFeed: %v", err)
+	}
+	if result.Feed == nil || len(result.Feed.Items) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestFetchFeedThroughProxy(t *testing.T) {
+	var hits []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy sees the absolute target URL.
+		hits = append(hits, r.URL.String())
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, testRSS)
+		gz.Close()
+	}))
+	defer proxy.Close()
+
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+	fetcher := NewFetcherWithOptions(store, Options{Proxy: proxy.URL})
+	feed := storage.Feed{URL: "http://feeds.example.test/feed.xml"}
+
+	result, err := fetcher.FetchFeed(context.Background(), feed)
+	if err != nil {
+		t.Fatalf("FetchFeed: %v", err)
+	}
+	if result.Feed == nil || result.Feed.Title != "Test Feed" {
+		t.Fatalf("expected the gzipped feed to parse, got %+v", result)
+	}
+	if result.ETag != `"v1"` {
+		t.Errorf("etag=%q, want \"v1\"", result.ETag)
+	}
+
+	feed.ETag = result.ETag
+	result, err = fetcher.FetchFeed(context.Background(), feed)
+	if err != nil {
+		t.Fatalf("conditional FetchFeed: %v", err)
+	}
+	if !result.NotModified {
+		t.Error("expected NotModified through the proxy")
+	}
+
+	if len(hits) != 2 || hits[0] != feed.URL {
+		t.Errorf("proxy saw %v, want two requests for %s", hits, feed.URL)
+	}
+}
+
+func TestOptionsFromConfig(t *testing.T) {
+	// A partial fetch section keeps defaults for the omitted fields.
+	cfg := storage.DefaultConfig()
+	if err := yaml.Unmarshal([]byte("fetch:\n  max_concurrency: 4\n"), cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	opts := OptionsFromConfig(cfg).withDefaults()
+	if opts.Timeout != 30*time.Second || opts.MaxConcurrency != 4 || opts.MaxRetries != 0 || opts.Proxy != "" {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+	if err := yaml.Unmarshal([]byte("fetch:\n  fetch_proxy: http://proxy.corp:3128\n"), cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := OptionsFromConfig(cfg).Proxy; got != "http://proxy.corp:3128" {
+		t.Errorf("proxy = %q, want http://proxy.corp:3128", got)
+	}
+
+	// Zero values (e.g. an explicit 0 timeout) fall back to defaults.
+	if got := (Options{}).withDefaults(); got.Timeout != DefaultFetchTimeout || got.MaxConcurrency != DefaultFetchMaxConcurrency {
+		t.Errorf("zero options: %+v", got)
+	}
+}
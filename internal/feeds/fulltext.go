@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand/v2"
 	"net/http"
 	"net/url"
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	readability "codeberg.org/readeck/go-readability/v2"
+	"github.com/matthewjhunter/herald/internal/storage"
 )
 
 // skipFullTextRe matches URLs that readability cannot usefully process.
@@ -97,12 +98,12 @@ func (f *Fetcher) FetchFullTextForArticles(ctx context.Context) (int, error) {
 			full, err := fetchReadableContent(ctx, f.client, linkedURL)
 			markDone()
 			if err != nil {
-				log.Printf("herald: linked-article fetch failed for article %d (%s): %v", article.ID, linkedURL, err)
+				slog.Warn("linked-article fetch failed", "article_id", article.ID, "url", linkedURL, "err", err)
 				continue
 			}
 			if textLength(full) >= 300 && !looksLikeContactPage(full) {
 				if err := f.store.UpdateArticleLinkedContent(article.ID, linkedURL, sanitizeText(full)); err != nil {
-					log.Printf("herald: failed to store linked content for article %d: %v", article.ID, err)
+					slog.Warn("store linked content failed", "article_id", article.ID, "err", err)
 				} else {
 					updated++
 				}
@@ -117,7 +118,7 @@ func (f *Fetcher) FetchFullTextForArticles(ctx context.Context) (int, error) {
 		full, err := fetchReadableContent(ctx, f.client, article.URL)
 		markDone()
 		if err != nil {
-			log.Printf("herald: full-text fetch failed for article %d (%s): %v", article.ID, article.URL, err)
+			slog.Warn("full-text fetch failed", "article_id", article.ID, "url", article.URL, "err", err)
 			continue
 		}
 
@@ -128,23 +129,51 @@ func (f *Fetcher) FetchFullTextForArticles(ctx context.Context) (int, error) {
 			continue
 		}
 		if looksLikeContactPage(full) {
-			log.Printf("herald: rejecting full text for article %d (%s): looks like contact page", article.ID, article.URL)
+			slog.Info("rejecting full text: looks like contact page", "article_id", article.ID, "url", article.URL)
 			continue
 		}
 		if !feedContentOverlaps(article.Content, full) {
-			log.Printf("herald: rejecting full text for article %d (%s): no phrase overlap with feed content (likely sidebar)", article.ID, article.URL)
+			slog.Info("rejecting full text: no phrase overlap with feed content (likely sidebar)", "article_id", article.ID, "url", article.URL)
 			continue
 		}
-		if err := f.store.UpdateArticleContent(article.ID, sanitizeText(full)); err != nil {
-			log.Printf("herald: failed to store full text for article %d: %v", article.ID, err)
-		} else {
-			updated++
+		full = sanitizeText(full)
+		if err := f.store.UpdateArticleContent(article.ID, full); err != nil {
+			slog.Warn("store full text failed", "article_id", article.ID, "err", err)
+			continue
+		}
+		updated++
+		if err := f.store.UpdateArticleWordCount(article.ID, wordCount(full)); err != nil {
+			slog.Warn("store word count failed", "article_id", article.ID, "err", err)
 		}
 	}
 
 	return updated, nil
 }
 
+// ReadableContent returns the most complete body available for article, for
+// on-demand reading. Stored content that already looks complete is returned
+// as is; otherwise the article page is fetched and run through readability,
+// and the result is used if it has more text and passes the same sidebar
+// checks as the background full-text pass. Link-blog posts get their linked
+// article appended. The result is unsanitized HTML and is not persisted.
+func (f *Fetcher) ReadableContent(ctx context.Context, article storage.Article) string {
+	content := article.Content
+	if article.URL != "" && isTruncated(content) &&
+		!skipFullTextRe.MatchString(article.URL) && !imageURLRe.MatchString(article.URL) {
+		full, err := fetchReadableContent(ctx, f.client, article.URL)
+		switch {
+		case err != nil:
+			slog.Warn("reader fetch failed", "article_id", article.ID, "url", article.URL, "err", err)
+		case textLength(full) > textLength(content) && !looksLikeContactPage(full):
+			content = sanitizeText(full)
+		}
+	}
+	if article.LinkedContent != "" {
+		content += "\n<hr>\n" + article.LinkedContent
+	}
+	return content
+}
+
 // isTruncated returns true when content looks like a feed summary/excerpt
 // rather than a complete article body.
 func isTruncated(content string) bool {
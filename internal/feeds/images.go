@@ -8,7 +8,7 @@ import (
 	"image/jpeg"
 	"image/png"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -172,7 +172,7 @@ func (f *Fetcher) cacheImagesForArticle(ctx context.Context, articleID int64, ar
 	if resolved, changed := resolveTwitterPics(ctx, f.client, content); changed {
 		content = resolved
 		if err := f.store.UpdateArticleContent(articleID, content); err != nil {
-			log.Printf("herald: twitter pic resolution: content update failed for article %d: %v", articleID, err)
+			slog.Warn("twitter pic resolution: content update failed", "article_id", articleID, "err", err)
 		}
 	}
 
@@ -189,11 +189,11 @@ func (f *Fetcher) cacheImagesForArticle(ctx context.Context, articleID int64, ar
 		}
 		data, mimeType, w, h, err := fetchAndNormalizeImage(ctx, f.client, imgURL)
 		if err != nil {
-			log.Printf("herald: image cache failed for article %d (%s): %v", articleID, imgURL, err)
+			slog.Warn("image cache failed", "article_id", articleID, "url", imgURL, "err", err)
 			continue
 		}
 		if _, err := f.store.StoreArticleImage(articleID, imgURL, data, mimeType, w, h); err != nil {
-			log.Printf("herald: failed to store image for article %d: %v", articleID, err)
+			slog.Warn("store image failed", "article_id", articleID, "err", err)
 			continue
 		}
 		stored++
@@ -201,6 +201,20 @@ func (f *Fetcher) cacheImagesForArticle(ctx context.Context, articleID int64, ar
 	return stored
 }
 
+// FetchImage downloads and normalizes a single image on demand, under the
+// same size cap as the background cache. Responses that aren't images are
+// rejected so the result is always safe to serve as one.
+func (f *Fetcher) FetchImage(ctx context.Context, imgURL string) (data []byte, mimeType string, width, height int, err error) {
+	data, mimeType, width, height, err = fetchAndNormalizeImage(ctx, f.client, imgURL)
+	if err != nil {
+		return nil, "", 0, 0, err
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, "", 0, 0, fmt.Errorf("%s is %s, not an image", imgURL, mimeType)
+	}
+	return data, mimeType, width, height, nil
+}
+
 // extractImageURLs parses HTML and returns unique, absolute image src URLs.
 // Data URLs (data:...) are skipped — they're already inline and don't need caching.
 // At most maxImagesPerArticle URLs are returned.
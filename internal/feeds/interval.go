@@ -0,0 +1,78 @@
+package feeds
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// customTTL is the gofeed.Feed.Custom key carrying the RSS <ttl> value,
+// which gofeed's universal feed otherwise drops.
+const customTTL = "ttl"
+
+// rssTranslator is gofeed's default RSS translator plus <ttl>.
+type rssTranslator struct {
+	gofeed.DefaultRSSTranslator
+}
+
+func (t *rssTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	result, err := t.DefaultRSSTranslator.Translate(feed)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := feed.(*rss.Feed); ok && strings.TrimSpace(r.TTL) != "" {
+		if result.Custom == nil {
+			result.Custom = map[string]string{}
+		}
+		result.Custom[customTTL] = strings.TrimSpace(r.TTL)
+	}
+	return result, nil
+}
+
+// syPeriods maps sy:updatePeriod values to their length.
+var syPeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// SuggestedInterval returns the polling interval a feed advertises, from
+// RSS <ttl> (minutes) or the syndication module's sy:updatePeriod divided
+// by sy:updateFrequency. It returns 0 when the feed gives no usable hint.
+func SuggestedInterval(feed *gofeed.Feed) time.Duration {
+	if feed == nil {
+		return 0
+	}
+	if ttl, err := strconv.Atoi(feed.Custom[customTTL]); err == nil && ttl > 0 {
+		return time.Duration(ttl) * time.Minute
+	}
+
+	sy := feed.Extensions["sy"]
+	period, ok := syPeriods[strings.ToLower(strings.TrimSpace(syValue(sy["updatePeriod"])))]
+	if !ok {
+		return 0
+	}
+	frequency := 1
+	if v := syValue(sy["updateFrequency"]); v != "" {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || n <= 0 {
+			return 0
+		}
+		frequency = n
+	}
+	return period / time.Duration(frequency)
+}
+
+// syValue returns the text of the first extension element, if any.
+func syValue(exts []ext.Extension) string {
+	if len(exts) == 0 {
+		return ""
+	}
+	return exts[0].Value
+}
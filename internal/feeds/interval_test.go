@@ -0,0 +1,83 @@
+package feeds
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSuggestedInterval(t *testing.T) {
+	const syNS = `xmlns:sy="http://purl.org/rss/1.0/modules/syndication/"`
+	tests := []struct {
+		name    string
+		channel string
+		want    time.Duration
+	}{
+		{"ttl", `<ttl>60</ttl>`, 60 * time.Minute},
+		{"ttl wins over sy", `<ttl>90</ttl><sy:updatePeriod>daily</sy:updatePeriod>`, 90 * time.Minute},
+		{"sy hourly", `<sy:updatePeriod>hourly</sy:updatePeriod>`, time.Hour},
+		{"sy daily twice", `<sy:updatePeriod>daily</sy:updatePeriod><sy:updateFrequency>2</sy:updateFrequency>`, 12 * time.Hour},
+		{"sy bad frequency", `<sy:updatePeriod>daily</sy:updatePeriod><sy:updateFrequency>0</sy:updateFrequency>`, 0},
+		{"sy unknown period", `<sy:updatePeriod>fortnightly</sy:updatePeriod>`, 0},
+		{"bad ttl", `<ttl>soon</ttl>`, 0},
+		{"none", ``, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := `<?xml version="1.0"?><rss version="2.0" ` + syNS + `><channel><title>T</title>` +
+				tt.channel + `</channel></rss>`
+			feed, err := newFeedParser().ParseString(doc)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if got := SuggestedInterval(feed); got != tt.want {
+				t.Errorf("SuggestedInterval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreArticles_SuggestedInterval(t *testing.T) {
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	feedID, err := store.AddFeed("https://example.com/ttl.xml", "TTL", "")
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	if err := store.SubscribeUserToFeed(1, feedID); err != nil {
+		t.Fatalf("SubscribeUserToFeed: %v", err)
+	}
+	fetcher := NewFetcher(store)
+
+	feed, err := newFeedParser().ParseString(strings.Replace(testRSS, "<channel>", "<channel><ttl>60</ttl>", 1))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if _, err := fetcher.StoreArticles(feedID, feed); err != nil {
+		t.Fatalf("StoreArticles: %v", err)
+	}
+	if got, err := store.GetFeedSuggestedInterval(feedID); err != nil || got != 60*time.Minute {
+		t.Fatalf("suggested interval = %v, %v; want 1h0m0s", got, err)
+	}
+
+	// Scheduling follows the hint rather than the posting-frequency default.
+	before := time.Now()
+	if err := store.UpdateFeedLastFetched(feedID); err != nil {
+		t.Fatalf("UpdateFeedLastFetched: %v", err)
+	}
+	feeds, err := store.GetUserFeeds(1)
+	if err != nil || len(feeds) != 1 || feeds[0].NextFetchAt == nil {
+		t.Fatalf("GetUserFeeds: %v (%+v)", err, feeds)
+	}
+	if d := feeds[0].NextFetchAt.Sub(before); d < 59*time.Minute || d > 61*time.Minute {
+		t.Errorf("next fetch in %v, want ~1h", d)
+	}
+
+	// A feed that drops the hint goes back to the default.
+	feed, _ = newFeedParser().ParseString(testRSS)
+	fetcher.StoreArticles(feedID, feed)
+	if got, _ := store.GetFeedSuggestedInterval(feedID); got != 0 {
+		t.Errorf("suggested interval after hint removed = %v, want 0", got)
+	}
+}
@@ -0,0 +1,99 @@
+// Package langdetect guesses the natural language of article text.
+//
+// Detection counts hits against short lists of each language's most common
+// function words. That is crude next to a trained n-gram model, but it needs
+// no data files, runs in microseconds, and is reliable on the paragraph-sized
+// text feeds provide. When the evidence is thin or ambiguous the result is
+// Unknown rather than a guess.
+package langdetect
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Unknown is returned when the language cannot be determined confidently.
+const Unknown = "unknown"
+
+const (
+	// minHits is the fewest stopword hits the winning language needs.
+	minHits = 4
+	// minMargin is how many times more hits the winner needs than the
+	// runner-up.
+	minMargin = 2.0
+)
+
+// stopwords maps ISO 639-1 codes to each language's most frequent function
+// words. Overlaps between lists are fine; they count towards both.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "that", "with", "for", "this", "are",
+		"was", "have", "from", "which", "not", "but", "they", "been", "their", "would",
+		"what", "about", "there", "when", "it"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "sich", "auf", "für",
+		"ein", "eine", "dem", "den", "auch", "wird", "sind", "von", "zu", "bei",
+		"wie", "noch", "nach", "über", "wir"},
+	"fr": {"le", "la", "les", "et", "est", "des", "une", "pour", "dans", "que",
+		"qui", "pas", "sur", "du", "au", "avec", "sont", "cette", "mais", "nous",
+		"ou", "leur", "été", "aux", "il"},
+	"es": {"el", "los", "las", "y", "es", "del", "por", "una", "con", "para",
+		"que", "como", "pero", "más", "sus", "fue", "son", "está", "también", "entre",
+		"su", "al", "muy", "sobre", "hay"},
+	"it": {"il", "di", "che", "è", "della", "per", "una", "sono", "non", "gli",
+		"con", "del", "nel", "alla", "anche", "più", "questo", "dei", "come", "delle",
+		"ha", "essere", "sul", "lo", "ma"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "zijn",
+		"voor", "met", "ook", "maar", "bij", "wordt", "aan", "naar", "dit", "nog",
+		"wel", "er", "om", "geen", "werd"},
+	"pt": {"o", "os", "as", "do", "da", "em", "não", "uma", "para", "com",
+		"que", "dos", "das", "mais", "foi", "são", "pelo", "pela", "está", "também",
+		"ao", "seu", "sua", "muito", "isso"},
+}
+
+// index maps each stopword to the languages that list it.
+var index = func() map[string][]string {
+	idx := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, w := range words {
+			idx[w] = append(idx[w], lang)
+		}
+	}
+	return idx
+}()
+
+// Languages returns the codes Detect can return besides Unknown, sorted.
+func Languages() []string {
+	langs := make([]string, 0, len(stopwords))
+	for lang := range stopwords {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Detect returns the ISO 639-1 code of text's language, or Unknown.
+func Detect(text string) string {
+	hits := make(map[string]int)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, w := range words {
+		for _, lang := range index[w] {
+			hits[lang]++
+		}
+	}
+
+	best, bestHits, secondHits := Unknown, 0, 0
+	for lang, n := range hits {
+		switch {
+		case n > bestHits:
+			best, bestHits, secondHits = lang, n, bestHits
+		case n > secondHits:
+			secondHits = n
+		}
+	}
+	if bestHits < minHits || float64(bestHits) < minMargin*float64(secondHits) {
+		return Unknown
+	}
+	return best
+}
@@ -0,0 +1,41 @@
+package langdetect
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "english",
+			text: "The city council said on Tuesday that it would delay the vote on the new budget, " +
+				"which has been criticised by residents for cutting funding to libraries and parks.",
+			want: "en",
+		},
+		{
+			name: "german",
+			text: "Der Stadtrat hat am Dienstag mitgeteilt, dass die Abstimmung über den neuen Haushalt " +
+				"verschoben wird. Die Bürger kritisieren, dass auch bei den Bibliotheken und Parks gespart wird.",
+			want: "de",
+		},
+		{
+			name: "too short",
+			text: "Release 2.4.1",
+			want: Unknown,
+		},
+		{
+			name: "markup only",
+			text: "<div><img src=\"x.png\"></div>",
+			want: Unknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// Package logging builds the slog loggers used by herald's binaries.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// New returns a logger that writes to w. format is "text" or "json"; level
+// is "debug", "info", "warn" or "error". Empty values mean text and info.
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q (want debug, info, warn or error)", level)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q (want text or json)", format)
+	}
+}
+
+// Setup builds a logger with New and installs it as the slog default. The
+// standard log package is routed through it as well, so any remaining
+// log.Printf calls come out in the same format.
+func Setup(w io.Writer, format, level string) (*slog.Logger, error) {
+	logger, err := New(w, format, level)
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(logger)
+	return logger, nil
+}
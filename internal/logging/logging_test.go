@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", "info")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	logger.Debug("hidden")
+	logger.Info("poll finished", "event", "poll_finish", "feed_id", int64(7), "duration_ms", int64(12))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1 (debug should be filtered):\n%s", len(lines), buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if rec["event"] != "poll_finish" || rec["feed_id"] != float64(7) || rec["duration_ms"] != float64(12) {
+		t.Errorf("unexpected record: %v", rec)
+	}
+}
+
+func TestNewText(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "", "debug")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	logger.Debug("shown", "article_id", 3)
+	if !strings.Contains(buf.String(), "msg=shown article_id=3") {
+		t.Errorf("unexpected text output: %q", buf.String())
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "xml", ""); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if _, err := New(&bytes.Buffer{}, "text", "loud"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
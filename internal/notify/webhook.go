@@ -0,0 +1,189 @@
+// Package notify delivers high-interest article notifications to
+// user-configured webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/matthewjhunter/herald/internal/retry"
+)
+
+// Article describes the scored article in a webhook payload.
+type Article struct {
+	ID     int64  `json:"id"`
+	FeedID int64  `json:"feed_id"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+}
+
+// Payload is the JSON body POSTed to a webhook.
+type Payload struct {
+	Event   string    `json:"event"` // always "high_interest"
+	Article Article   `json:"article"`
+	Score   float64   `json:"score"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// Options tunes webhook delivery.
+type Options struct {
+	// MaxRetries is the number of extra attempts after a failed delivery.
+	MaxRetries int
+	// BlockPrivate refuses webhooks on localhost or whose address, as given
+	// or once resolved, is loopback, private, link-local or unspecified.
+	// Redirects are held to the same rule.
+	BlockPrivate bool
+}
+
+// DefaultMaxRetries is the retry count herald uses for webhook delivery.
+const DefaultMaxRetries = 2
+
+// attemptTimeout bounds a single delivery attempt.
+const attemptTimeout = 10 * time.Second
+
+// retryBackoff is the delay before the first retry; it doubles per attempt.
+var retryBackoff = 2 * time.Second
+
+var (
+	client        = newClient(false)
+	guardedClient = newClient(true) // used with Options.BlockPrivate
+)
+
+// maxRedirects matches net/http's default redirect limit.
+const maxRedirects = 10
+
+// newClient returns a webhook HTTP client. With blockPrivate, the client
+// refuses to connect to internal addresses and re-validates every redirect.
+// It dials the webhook host directly, ignoring proxy settings, so that the
+// address check applies to the webhook itself.
+func newClient(blockPrivate bool) *http.Client {
+	c := &http.Client{Timeout: attemptTimeout}
+	if !blockPrivate {
+		return c
+	}
+	dialer := &net.Dialer{Timeout: attemptTimeout, Control: refuseInternal}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	c.Transport = transport
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return ValidateURL(req.URL.String(), true)
+	}
+	return c
+}
+
+// refuseInternal is a net.Dialer Control hook that rejects connections to
+// internal addresses. It runs after name resolution, so a public hostname
+// that resolves to a private address is caught.
+func refuseInternal(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("webhook address %s is internal", host)
+	}
+	return nil
+}
+
+// ValidateURL reports whether rawURL is an acceptable webhook target: an
+// absolute http or https URL with a host, and with blockPrivate, not an
+// obviously internal one. Hostnames aren't resolved here; Dispatch checks
+// the resolved address when it connects.
+func ValidateURL(rawURL string, blockPrivate bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("webhook URL must use http or https")
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("webhook URL must include a host")
+	}
+	if blockPrivate && isInternalHost(host) {
+		return fmt.Errorf("webhook host %q is internal", host)
+	}
+	return nil
+}
+
+// isInternalHost reports whether host is localhost or an IP literal that
+// can't be a public endpoint.
+func isInternalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && isInternalIP(ip)
+}
+
+// isInternalIP reports whether ip can't be a public endpoint.
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// Dispatch POSTs a high_interest payload for article to webhookURL, retrying
+// failed attempts up to opts.MaxRetries times with doubling backoff. Any
+// 2xx response counts as delivered.
+func Dispatch(ctx context.Context, webhookURL string, article Article, score float64, opts Options) error {
+	if err := ValidateURL(webhookURL, opts.BlockPrivate); err != nil {
+		return err
+	}
+	body, err := json.Marshal(Payload{
+		Event:   "high_interest",
+		Article: article,
+		Score:   score,
+		SentAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+
+	c := client
+	if opts.BlockPrivate {
+		c = guardedClient
+	}
+	return retry.Do(ctx, opts.MaxRetries, retryBackoff, func() error {
+		return post(ctx, c, webhookURL, body)
+	}, func(attempt int, err error) {
+		slog.Debug("retrying webhook delivery", "article_id", article.ID, "attempt", attempt, "err", err)
+	})
+}
+
+// post makes a single delivery attempt.
+func post(ctx context.Context, c *http.Client, webhookURL string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Herald/1.0 (+https://github.com/matthewjhunter/herald)")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatch(t *testing.T) {
+	retryBackoff = time.Millisecond
+
+	var calls atomic.Int32
+	got := make(chan Payload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt so delivery has to retry.
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		var p Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		got <- p
+	}))
+	defer srv.Close()
+
+	article := Article{ID: 7, FeedID: 3, Title: "Go 2 released", URL: "https://example.com/go2"}
+	if err := Dispatch(context.Background(), srv.URL, article, 9.5, Options{MaxRetries: 1}); err != nil {
+		t.Fatalf("Dispatch: %v", err)
+	}
+	p := <-got
+	if p.Event != "high_interest" || p.Article != article || p.Score != 9.5 || p.SentAt.IsZero() {
+		t.Errorf("payload = %+v", p)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+
+	// Without retries the first failure is final.
+	calls.Store(0)
+	if err := Dispatch(context.Background(), srv.URL, article, 9.5, Options{}); err == nil {
+		t.Error("expected error with no retries")
+	}
+
+	// The test server listens on loopback, which BlockPrivate refuses.
+	calls.Store(1)
+	if err := Dispatch(context.Background(), srv.URL, article, 9.5, Options{BlockPrivate: true}); err == nil {
+		t.Error("expected loopback webhook to be refused")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("refused webhook was contacted %d times", n-1)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		blockPrivate bool
+		ok           bool
+	}{
+		{"https://hooks.example.com/herald", true, true},
+		{"http://127.0.0.1:8080/hook", false, true},
+		{"http://127.0.0.1:8080/hook", true, false},
+		{"http://localhost/hook", true, false},
+		{"http://10.0.0.5/hook", true, false},
+		{"http://169.254.169.254/latest/meta-data", true, false},
+		{"http://[::1]/hook", true, false},
+		{"ftp://example.com/hook", false, false},
+		{"file:///etc/passwd", false, false},
+		{"https:///nohost", false, false},
+	}
+	for _, tt := range tests {
+		err := ValidateURL(tt.url, tt.blockPrivate)
+		if (err == nil) != tt.ok {
+			t.Errorf("ValidateURL(%q, %v) = %v, want ok=%v", tt.url, tt.blockPrivate, err, tt.ok)
+		}
+	}
+}
+
+func TestRefuseInternal(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:80", "10.1.2.3:443", "[::1]:80", "169.254.169.254:80"} {
+		if err := refuseInternal("tcp", addr, nil); err == nil {
+			t.Errorf("refuseInternal(%q) = nil, want error", addr)
+		}
+	}
+	if err := refuseInternal("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("refuseInternal(public) = %v", err)
+	}
+
+	// Redirects to internal hosts are refused too.
+	req := httptest.NewRequest(http.MethodPost, "http://10.0.0.5/hook", nil)
+	if err := guardedClient.CheckRedirect(req, nil); err == nil {
+		t.Error("redirect to an internal host should be refused")
+	}
+}
@@ -23,6 +23,7 @@ type Formatter struct {
 	format Format
 	out    io.Writer
 	err    io.Writer
+	quiet  bool
 }
 
 // NewFormatter creates a new output formatter
@@ -34,6 +35,20 @@ func NewFormatter(format Format) *Formatter {
 	}
 }
 
+// Options selects a formatter's output format and whether warnings and
+// errors are silenced.
+type Options struct {
+	Format Format
+	Quiet  bool
+}
+
+// NewFormatterWithOptions creates a stdout/stderr formatter from opts
+func NewFormatterWithOptions(opts Options) *Formatter {
+	f := NewFormatter(opts.Format)
+	f.quiet = opts.Quiet
+	return f
+}
+
 // NewFormatterWithWriters creates a formatter with custom output writers for testability
 func NewFormatterWithWriters(format Format, out, errW io.Writer) *Formatter {
 	return &Formatter{
@@ -43,6 +58,11 @@ func NewFormatterWithWriters(format Format, out, errW io.Writer) *Formatter {
 	}
 }
 
+// SetQuiet silences Warning and Error, for scripted and cron use.
+func (f *Formatter) SetQuiet(quiet bool) {
+	f.quiet = quiet
+}
+
 // ArticleGroup represents a group of articles covering the same event
 type ArticleGroup struct {
 	Topic    string            `json:"topic"`
@@ -124,6 +144,63 @@ func (f *Formatter) OutputArticleList(articles []storage.Article) error {
 	return fmt.Errorf("unknown format: %s", f.format)
 }
 
+// FeedListEntry is one feed in the feed list output. Subscribers and
+// Articles are only set when stats are requested.
+type FeedListEntry struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Status      string `json:"status"`
+	Subscribers *int   `json:"subscribers,omitempty"`
+	Articles    *int   `json:"articles,omitempty"`
+}
+
+// OutputFeedList outputs feeds, with their subscriber counts and article
+// totals when withStats is set.
+func (f *Formatter) OutputFeedList(feeds []storage.FeedStat, withStats bool) error {
+	entries := make([]FeedListEntry, len(feeds))
+	for i, fs := range feeds {
+		entries[i] = FeedListEntry{ID: fs.ID, Title: fs.Title, URL: fs.URL, Status: fs.Status}
+		if withStats {
+			entries[i].Subscribers = &feeds[i].Subscribers
+			entries[i].Articles = &feeds[i].Articles
+		}
+	}
+
+	switch f.format {
+	case FormatJSON:
+		return json.NewEncoder(f.out).Encode(entries)
+	case FormatText:
+		for _, e := range entries {
+			fmt.Fprintf(f.out, "id=%d\ttitle=%s\turl=%s\tstatus=%s", e.ID, e.Title, e.URL, e.Status)
+			if withStats {
+				fmt.Fprintf(f.out, "\tsubscribers=%d\tarticles=%d", *e.Subscribers, *e.Articles)
+			}
+			fmt.Fprintln(f.out)
+		}
+		return nil
+	case FormatHuman:
+		if len(entries) == 0 {
+			fmt.Fprintln(f.out, "No feeds")
+			return nil
+		}
+		fmt.Fprintf(f.out, "Feeds (%d):\n\n", len(entries))
+		for _, e := range entries {
+			fmt.Fprintf(f.out, "ID: %d\n", e.ID)
+			fmt.Fprintf(f.out, "Title: %s\n", e.Title)
+			fmt.Fprintf(f.out, "URL: %s\n", e.URL)
+			fmt.Fprintf(f.out, "Status: %s\n", e.Status)
+			if withStats {
+				fmt.Fprintf(f.out, "Subscribers: %d\n", *e.Subscribers)
+				fmt.Fprintf(f.out, "Articles: %d\n", *e.Articles)
+			}
+			fmt.Fprintln(f.out, "---")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format: %s", f.format)
+}
+
 // OutputArticleGroups outputs grouped articles
 func (f *Formatter) OutputArticleGroups(groups []ArticleGroup) error {
 	switch f.format {
@@ -242,11 +319,17 @@ func (f *Formatter) OutputProcessingStatus(articleID int64, title string, intere
 
 // Error outputs an error message to stderr
 func (f *Formatter) Error(format string, args ...interface{}) {
+	if f.quiet {
+		return
+	}
 	fmt.Fprintf(f.err, format+"\n", args...)
 }
 
 // Warning outputs a warning message to stderr
 func (f *Formatter) Warning(format string, args ...interface{}) {
+	if f.quiet {
+		return
+	}
 	fmt.Fprintf(f.err, "Warning: "+format+"\n", args...)
 }
 
@@ -141,6 +141,27 @@ func TestOutputArticleList_Human_Empty(t *testing.T) {
 	}
 }
 
+func TestOutputFeedList_Text(t *testing.T) {
+	feeds := []storage.FeedStat{{ID: 3, Title: "Shared", URL: "https://example.com/feed", Status: "active", Articles: 12, Subscribers: 2}}
+
+	var out bytes.Buffer
+	f := NewFormatterWithWriters(FormatText, &out, &bytes.Buffer{})
+	if err := f.OutputFeedList(feeds, false); err != nil {
+		t.Fatalf("OutputFeedList failed: %v", err)
+	}
+	if got := out.String(); strings.Contains(got, "subscribers=") {
+		t.Errorf("stats shown without withStats: %q", got)
+	}
+
+	out.Reset()
+	if err := f.OutputFeedList(feeds, true); err != nil {
+		t.Fatalf("OutputFeedList failed: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "subscribers=2\tarticles=12") {
+		t.Errorf("missing stats in %q", got)
+	}
+}
+
 func TestOutputProcessingStatus_JSON(t *testing.T) {
 	tests := []struct {
 		name string
@@ -319,3 +340,23 @@ func TestTruncate(t *testing.T) {
 		})
 	}
 }
+
+func TestQuietSuppressesStderr(t *testing.T) {
+	var out, errBuf bytes.Buffer
+	f := NewFormatterWithWriters(FormatText, &out, &errBuf)
+	f.SetQuiet(true)
+
+	f.Warning("skipping article %d", 1)
+	f.Error("fetch failed: %v", "boom")
+	if errBuf.Len() != 0 {
+		t.Errorf("quiet formatter wrote to err: %q", errBuf.String())
+	}
+
+	// Results still go to out.
+	if err := f.OutputFetchResult(&FetchResult{NewArticles: 2}); err != nil {
+		t.Fatalf("OutputFetchResult: %v", err)
+	}
+	if !strings.Contains(out.String(), "new_articles=2") {
+		t.Errorf("quiet formatter suppressed results: %q", out.String())
+	}
+}
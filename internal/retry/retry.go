@@ -0,0 +1,31 @@
+// Package retry runs an operation with a bounded number of retries and
+// doubling backoff, as feed fetches and webhook deliveries do.
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// Do calls fn until it returns nil, maxRetries extra attempts have been made,
+// or ctx is done. The delay before the first retry is backoff, doubling for
+// each retry after. onRetry, if non-nil, is called with the attempt number
+// (starting at 1) and the last error before each retry. Do returns fn's last
+// error.
+func Do(ctx context.Context, maxRetries int, backoff time.Duration, fn func() error, onRetry func(attempt int, err error)) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxRetries || ctx.Err() != nil {
+			return err
+		}
+		if onRetry != nil {
+			onRetry(attempt+1, err)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
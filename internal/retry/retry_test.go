@@ -0,0 +1,52 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	errFail := errors.New("fail")
+
+	calls := 0
+	var retried []int
+	err := Do(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errFail
+		}
+		return nil
+	}, func(attempt int, err error) {
+		retried = append(retried, attempt)
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Do = %v after %d calls, want nil after 3", err, calls)
+	}
+	if len(retried) != 2 || retried[0] != 1 || retried[1] != 2 {
+		t.Errorf("onRetry attempts = %v, want [1 2]", retried)
+	}
+
+	// Retries are bounded, and the last error is returned.
+	calls = 0
+	err = Do(context.Background(), 1, time.Millisecond, func() error {
+		calls++
+		return errFail
+	}, nil)
+	if !errors.Is(err, errFail) || calls != 2 {
+		t.Errorf("Do = %v after %d calls, want errFail after 2", err, calls)
+	}
+
+	// A cancelled context stops retrying.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	Do(ctx, 5, time.Millisecond, func() error {
+		calls++
+		return errFail
+	}, nil)
+	if calls != 1 {
+		t.Errorf("cancelled Do made %d calls, want 1", calls)
+	}
+}
@@ -6,9 +6,27 @@ type Config struct {
 	DefaultUserID int64 `yaml:"default_user_id"`
 
 	Database struct {
-		Path string `yaml:"path"`
+		Path        string        `yaml:"path"`
+		BusyTimeout time.Duration `yaml:"busy_timeout"` // SQLite lock wait; default 15s
+		JournalMode string        `yaml:"journal_mode"` // SQLite journal mode; default WAL
+		// CreateDefaultUser creates user 1 ("default") when the database has
+		// no users, so default_user_id works on a fresh database. Default true.
+		CreateDefaultUser bool `yaml:"create_default_user"`
 	} `yaml:"database"`
 
+	Fetch struct {
+		FetchTimeoutSeconds int    `yaml:"fetch_timeout_seconds"` // per-attempt HTTP timeout; default 30
+		MaxConcurrency      int    `yaml:"max_concurrency"`       // feeds fetched in parallel; default 1
+		MaxRetries          int    `yaml:"max_retries"`           // extra attempts after a failed fetch; default 0
+		DetectLanguage      bool   `yaml:"detect_language"`       // tag new articles with their language; default false
+		FetchProxy          string `yaml:"fetch_proxy"`           // proxy URL for feed fetches; default HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+	} `yaml:"fetch"`
+
+	Output struct {
+		DefaultFormat string `yaml:"default_format"` // CLI --format default: json, text or human
+		Quiet         bool   `yaml:"quiet"`          // suppress CLI warnings and errors on stderr
+	} `yaml:"output"`
+
 	Ollama struct {
 		BaseURL        string        `yaml:"base_url"`
 		APIKey         string        `yaml:"api_key"`
@@ -17,6 +35,20 @@ type Config struct {
 		EmbeddingModel string        `yaml:"embedding_model"`
 		Timeout        time.Duration `yaml:"timeout"`
 		MaxParallel    int           `yaml:"max_parallel"`
+		// ProcessDelaySeconds holds back articles published less than this
+		// long ago from AI processing, so feeds that edit posts shortly
+		// after publishing are scored once the edits settle. 0 = immediately.
+		ProcessDelaySeconds int `yaml:"process_delay_seconds"`
+		// CurationModels is an ordered fallback chain for scoring. When set
+		// it replaces curation_model: the first entry is the primary, and
+		// each later one is tried when the model before it is missing or
+		// fails transiently.
+		CurationModels []string `yaml:"curation_models"`
+		// Characters of article content sent to the security model and to
+		// the curation model (scoring and summaries). Curation is capped at
+		// the security limit so the security check screens all of it.
+		SecurityMaxContent int `yaml:"security_max_content"`
+		CurationMaxContent int `yaml:"curation_max_content"`
 	} `yaml:"ollama"`
 
 	Thresholds struct {
@@ -25,17 +57,22 @@ type Config struct {
 	} `yaml:"thresholds"`
 
 	Preferences struct {
-		Keywords         []string `yaml:"keywords"`
+		Keywords         Keywords `yaml:"keywords"`
+		AvoidKeywords    Keywords `yaml:"avoid_keywords"` // topics that should lower the interest score
 		PreferredSources []string `yaml:"preferred_sources"`
 	} `yaml:"preferences"`
 
 	Prompts struct {
+		// Dir holds <type>.txt files (security.txt, curation.txt, ...) that
+		// replace the embedded defaults; absent files keep the default.
+		Dir           string `yaml:"dir,omitempty"`
 		Security      string `yaml:"security,omitempty"`
 		Curation      string `yaml:"curation,omitempty"`
 		Summarization string `yaml:"summarization,omitempty"`
 		GroupSummary  string `yaml:"group_summary,omitempty"`
 		RelatedGroups string `yaml:"related_groups,omitempty"`
 		Newsletter    string `yaml:"newsletter,omitempty"`
+		Briefing      string `yaml:"briefing,omitempty"`
 	} `yaml:"prompts,omitempty"`
 
 	Summarization struct {
@@ -46,6 +83,17 @@ type Config struct {
 	Grouping struct {
 		SimilarityThreshold float64 `yaml:"similarity_threshold"`
 		PreFilterThreshold  float64 `yaml:"pre_filter_threshold"`
+		// TitleSimilarityThreshold is the word-overlap (Jaccard, 0-1) a title
+		// needs with a group's topic to join it without AI; 0 disables.
+		TitleSimilarityThreshold float64 `yaml:"title_similarity_threshold"`
+		// MinGroupSize is how many related articles it takes to create a
+		// group; below that an unmatched article stays ungrouped. 1 groups
+		// every article.
+		MinGroupSize int `yaml:"min_group_size"`
+		// SummaryMinChange is how many articles a group's membership must
+		// have moved by since its summary was written before a poll
+		// regenerates it; 0 regenerates on any change.
+		SummaryMinChange int `yaml:"summary_min_change"`
 	} `yaml:"grouping"`
 
 	Temperatures struct {
@@ -67,20 +115,40 @@ type Config struct {
 	} `yaml:"email,omitempty"`
 }
 
+// CurationChain returns the ordered curation models: curation_models when
+// set, otherwise curation_model alone.
+func (c *Config) CurationChain() []string {
+	if len(c.Ollama.CurationModels) > 0 {
+		return c.Ollama.CurationModels
+	}
+	if c.Ollama.CurationModel == "" {
+		return nil
+	}
+	return []string{c.Ollama.CurationModel}
+}
+
 // DefaultConfig returns a config with sensible defaults
 func DefaultConfig() *Config {
 	cfg := &Config{}
 	cfg.DefaultUserID = 1
 	cfg.Database.Path = "./herald.db"
+	cfg.Database.CreateDefaultUser = true
+	cfg.Fetch.FetchTimeoutSeconds = 30
+	cfg.Fetch.MaxConcurrency = 1
+	cfg.Output.DefaultFormat = "json"
 	cfg.Ollama.BaseURL = "http://localhost:11434"
 	cfg.Ollama.SecurityModel = "gemma4"
 	cfg.Ollama.CurationModel = "gemma4"
 	cfg.Ollama.EmbeddingModel = "nomic-embed-text"
 	cfg.Ollama.Timeout = 2 * time.Minute
+	cfg.Ollama.SecurityMaxContent = 3000
+	cfg.Ollama.CurationMaxContent = 3000
 	cfg.Summarization.MinArticleLength = 200
 	cfg.Summarization.MaxSummaryLength = 500
 	cfg.Grouping.SimilarityThreshold = 0.75
 	cfg.Grouping.PreFilterThreshold = 0.3
+	cfg.Grouping.TitleSimilarityThreshold = 0.6
+	cfg.Grouping.MinGroupSize = 1
 	cfg.Thresholds.InterestScore = 8.0
 	cfg.Thresholds.SecurityScore = 7.0
 	// Default temperatures (can be overridden in config)
@@ -91,3 +159,12 @@ func DefaultConfig() *Config {
 	cfg.Temperatures.RelatedGroups = 0.3
 	return cfg
 }
+
+// SQLiteOptions returns the store options from the database section.
+func (c *Config) SQLiteOptions() SQLiteOptions {
+	return SQLiteOptions{
+		BusyTimeout:       c.Database.BusyTimeout,
+		JournalMode:       c.Database.JournalMode,
+		CreateDefaultUser: c.Database.CreateDefaultUser,
+	}
+}
@@ -195,24 +195,31 @@ func (s *SQLiteStore) MarkFeedArticlesRead(userID, feedID int64, before int64) e
 	return err
 }
 
-// MarkGroupArticlesRead marks all articles in an article group as read for a
-// user, where published_date <= time.Unix(before, 0). before=0 marks everything.
-func (s *SQLiteStore) MarkGroupArticlesRead(userID, groupID int64, before int64) error {
+// MarkGroupArticlesRead marks the unread articles in an article group as
+// read for a user, where published_date <= time.Unix(before, 0). before=0
+// marks everything. Scores are not touched, so the articles are not
+// re-processed. Returns the number of articles marked.
+func (s *SQLiteStore) MarkGroupArticlesRead(userID, groupID int64, before int64) (int, error) {
 	var beforeCond string
-	args := []any{userID, groupID}
+	args := []any{userID, userID, groupID}
 	if before > 0 {
 		beforeCond = `AND (a.published_date IS NULL OR a.published_date <= ?)`
 		args = append(args, time.Unix(before, 0))
 	}
-	_, err := s.db.Exec(fmt.Sprintf(`
+	result, err := s.db.Exec(fmt.Sprintf(`
 		INSERT INTO read_state (user_id, article_id, read, read_date)
 		SELECT ?, a.id, 1, CURRENT_TIMESTAMP
 		FROM articles a
 		JOIN article_group_members agm ON agm.article_id = a.id
-		WHERE agm.group_id = ? %s
+		LEFT JOIN read_state rs ON rs.article_id = a.id AND rs.user_id = ?
+		WHERE agm.group_id = ? AND COALESCE(rs.read, 0) = 0 %s
 		ON CONFLICT(user_id, article_id) DO UPDATE SET read = 1, read_date = CURRENT_TIMESTAMP`,
 		beforeCond), args...)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
 }
 
 // MarkAllArticlesRead marks all articles as read for a user across all
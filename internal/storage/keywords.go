@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultKeywordWeight is the weight of a keyword given as a bare term.
+const DefaultKeywordWeight = 1.0
+
+// MaxKeywordWeight caps keyword weights so one interest cannot drown out the
+// rest of the curation prompt.
+const MaxKeywordWeight = 10.0
+
+// Keyword is a curation interest with its relative importance.
+type Keyword struct {
+	Term   string  `json:"term" yaml:"term"`
+	Weight float64 `json:"weight" yaml:"weight"`
+}
+
+// Keywords is a user's interest list. In JSON and YAML each element may be a
+// bare string ("golang") or an object ({"term": "golang", "weight": 3}); a
+// missing weight means DefaultKeywordWeight. It marshals back to a plain
+// string array when no weight differs from the default, so legacy values
+// round-trip unchanged.
+type Keywords []Keyword
+
+// KeywordsFromTerms returns terms as keywords of default weight.
+func KeywordsFromTerms(terms []string) Keywords {
+	if terms == nil {
+		return nil
+	}
+	kw := make(Keywords, len(terms))
+	for i, t := range terms {
+		kw[i] = Keyword{Term: t, Weight: DefaultKeywordWeight}
+	}
+	return kw
+}
+
+// Terms returns the keyword terms without their weights.
+func (kw Keywords) Terms() []string {
+	terms := make([]string, len(kw))
+	for i, k := range kw {
+		terms[i] = k.Term
+	}
+	return terms
+}
+
+// Weighted reports whether any keyword has a non-default weight.
+func (kw Keywords) Weighted() bool {
+	for _, k := range kw {
+		if k.Weight != DefaultKeywordWeight {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that every term is non-blank and every weight is in
+// (0, MaxKeywordWeight].
+func (kw Keywords) Validate() error {
+	for _, k := range kw {
+		if strings.TrimSpace(k.Term) == "" {
+			return fmt.Errorf("keyword term must not be empty")
+		}
+		if k.Weight <= 0 || k.Weight > MaxKeywordWeight {
+			return fmt.Errorf("keyword %q: weight must be greater than 0 and at most %g", k.Term, MaxKeywordWeight)
+		}
+	}
+	return nil
+}
+
+// String renders the keywords for a prompt: comma-separated terms, with
+// non-default weights noted, e.g. "golang (weight 3), news".
+func (kw Keywords) String() string {
+	parts := make([]string, len(kw))
+	for i, k := range kw {
+		parts[i] = k.Term
+		if k.Weight != DefaultKeywordWeight {
+			parts[i] += " (weight " + strconv.FormatFloat(k.Weight, 'g', -1, 64) + ")"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (kw Keywords) MarshalJSON() ([]byte, error) {
+	if kw == nil {
+		return []byte("null"), nil
+	}
+	if kw.Weighted() {
+		return json.Marshal([]Keyword(kw))
+	}
+	return json.Marshal(kw.Terms())
+}
+
+func (kw *Keywords) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*kw = nil
+		return nil
+	}
+	out := make(Keywords, len(raw))
+	for i, elem := range raw {
+		if bytes.HasPrefix(bytes.TrimSpace(elem), []byte(`"`)) {
+			if err := json.Unmarshal(elem, &out[i].Term); err != nil {
+				return err
+			}
+			out[i].Weight = DefaultKeywordWeight
+			continue
+		}
+		var obj struct {
+			Term   string   `json:"term"`
+			Weight *float64 `json:"weight"`
+		}
+		if err := json.Unmarshal(elem, &obj); err != nil {
+			return fmt.Errorf("keyword %d must be a string or {term, weight} object: %w", i, err)
+		}
+		out[i] = Keyword{Term: obj.Term, Weight: DefaultKeywordWeight}
+		if obj.Weight != nil {
+			out[i].Weight = *obj.Weight
+		}
+	}
+	*kw = out
+	return nil
+}
+
+func (kw *Keywords) UnmarshalYAML(node *yaml.Node) error {
+	if node.Tag == "!!null" {
+		*kw = nil
+		return nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("line %d: keywords must be a list", node.Line)
+	}
+	out := make(Keywords, len(node.Content))
+	for i, elem := range node.Content {
+		if elem.Kind == yaml.ScalarNode {
+			out[i] = Keyword{Term: elem.Value, Weight: DefaultKeywordWeight}
+			continue
+		}
+		var obj struct {
+			Term   string   `yaml:"term"`
+			Weight *float64 `yaml:"weight"`
+		}
+		if err := elem.Decode(&obj); err != nil {
+			return err
+		}
+		out[i] = Keyword{Term: obj.Term, Weight: DefaultKeywordWeight}
+		if obj.Weight != nil {
+			out[i].Weight = *obj.Weight
+		}
+	}
+	*kw = out
+	return nil
+}
+
+func (kw Keywords) MarshalYAML() (interface{}, error) {
+	if kw.Weighted() {
+		return []Keyword(kw), nil
+	}
+	return kw.Terms(), nil
+}
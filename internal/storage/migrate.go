@@ -65,6 +65,7 @@ func MigrateStore(ctx context.Context, src, dst Store) (*MigrateStats, error) {
 		{"article_authors", func() error { return migrateArticleAuthors(ctx, srcDB, dst, articleMap) }},
 		{"article_categories", func() error { return migrateArticleCategories(ctx, srcDB, dst, articleMap) }},
 		{"article_summaries", func() error { return migrateArticleSummaries(ctx, srcDB, dst, userMap, articleMap) }},
+		{"article_notes", func() error { return migrateArticleNotes(ctx, srcDB, dst, userMap, articleMap) }},
 		{"article_groups", func() error { return migrateArticleGroups(ctx, srcDB, dst, userMap, articleMap, groupMap, stats) }},
 		{"filter_rules", func() error { return migrateFilterRules(ctx, srcDB, dst, userMap, feedMap, stats) }},
 		{"fever_credentials", func() error { return migrateFeverCredentials(ctx, srcDB, dst, userMap, stats) }},
@@ -286,7 +287,7 @@ func migrateArticles(ctx context.Context, src *tracedDB, dst Store, dstDB *trace
 		       COALESCE(content,''), COALESCE(summary,''), COALESCE(author,''),
 		       published_date, fetched_date,
 		       COALESCE(linked_url,''), COALESCE(linked_content,''),
-		       full_text_fetched, images_cached
+		       full_text_fetched, images_cached, lang
 		FROM articles ORDER BY id`)
 	if err != nil {
 		return err
@@ -305,6 +306,7 @@ func migrateArticles(ctx context.Context, src *tracedDB, dst Store, dstDB *trace
 		linkedContent    string
 		fullTextFetched  bool
 		imagesCached     bool
+		lang             string
 	}
 
 	var articles []articleRow
@@ -315,7 +317,7 @@ func migrateArticles(ctx context.Context, src *tracedDB, dst Store, dstDB *trace
 			&a.content, &a.summary, &a.author,
 			&a.publishedDate, &a.fetchedDate,
 			&a.linkedURL, &a.linkedContent,
-			&a.fullTextFetched, &a.imagesCached,
+			&a.fullTextFetched, &a.imagesCached, &a.lang,
 		); err != nil {
 			return fmt.Errorf("scan article: %w", err)
 		}
@@ -339,6 +341,7 @@ func migrateArticles(ctx context.Context, src *tracedDB, dst Store, dstDB *trace
 			Content: sanitizeStr(a.content),
 			Summary: sanitizeStr(a.summary),
 			Author:  sanitizeStr(a.author),
+			Lang:    a.lang,
 		}
 		if a.publishedDate.Valid {
 			t := a.publishedDate.Time
@@ -636,6 +639,35 @@ func migrateArticleSummaries(ctx context.Context, src *tracedDB, dst Store, user
 	return rows.Err()
 }
 
+func migrateArticleNotes(ctx context.Context, src *tracedDB, dst Store, userMap, articleMap map[int64]int64) error {
+	rows, err := src.QueryContext(ctx,
+		"SELECT user_id, article_id, note FROM article_notes ORDER BY user_id, article_id")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var srcUserID, srcArticleID int64
+		var note string
+		if err := rows.Scan(&srcUserID, &srcArticleID, &note); err != nil {
+			return err
+		}
+		dstUserID, ok := userMap[srcUserID]
+		if !ok {
+			continue
+		}
+		dstArticleID, ok := articleMap[srcArticleID]
+		if !ok {
+			continue
+		}
+		if err := dst.SetArticleNote(dstUserID, dstArticleID, note); err != nil {
+			return fmt.Errorf("SetArticleNote: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
 func migrateArticleGroups(ctx context.Context, src *tracedDB, dst Store, userMap, articleMap, groupMap map[int64]int64, stats *MigrateStats) error {
 	rows, err := src.QueryContext(ctx,
 		"SELECT id, user_id, topic, embedding, COALESCE(embedding_model, '') FROM article_groups ORDER BY id")
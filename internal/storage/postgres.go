@@ -53,12 +53,70 @@ func NewPostgresStore(dsn string) (*PostgresStore, error) {
 	pgMigrations := []string{
 		"ALTER TABLE user_feeds ADD COLUMN IF NOT EXISTS user_title TEXT",
 		"ALTER TABLE read_state ADD COLUMN IF NOT EXISTS security_reason TEXT",
+		// Audit flag for articles a user released past a failed security check.
+		"ALTER TABLE read_state ADD COLUMN IF NOT EXISTS security_overridden BOOLEAN NOT NULL DEFAULT FALSE",
 		"ALTER TABLE article_groups ADD COLUMN IF NOT EXISTS display_name TEXT",
 		"ALTER TABLE article_groups ADD COLUMN IF NOT EXISTS muted BOOLEAN NOT NULL DEFAULT FALSE",
 		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS site_url TEXT NOT NULL DEFAULT ''",
 		"ALTER TABLE group_summaries ADD COLUMN IF NOT EXISTS headline TEXT NOT NULL DEFAULT ''",
 		"ALTER TABLE article_groups ADD COLUMN IF NOT EXISTS embedding_model TEXT NOT NULL DEFAULT ''",
 		"ALTER TABLE read_state ADD COLUMN IF NOT EXISTS ai_retries INTEGER NOT NULL DEFAULT 0",
+		// Publishing host for domain filter rules, backfilled from the URL.
+		"ALTER TABLE articles ADD COLUMN IF NOT EXISTS domain TEXT NOT NULL DEFAULT ''",
+		`UPDATE articles
+		 SET domain = lower(COALESCE(substring(url from '^[A-Za-z][A-Za-z0-9+.-]*://(?:[^@/]*@)?([^:/?#]+)'), ''))
+		 WHERE domain = '' AND url LIKE '%://%'`,
+		// Widen the filter_rules axis CHECK to include domain and title.
+		"ALTER TABLE filter_rules DROP CONSTRAINT IF EXISTS filter_rules_axis_check",
+		"ALTER TABLE filter_rules ADD CONSTRAINT filter_rules_axis_check CHECK (axis IN ('author', 'category', 'tag', 'domain', 'title', 'lang'))",
+		"ALTER TABLE filter_rules ADD COLUMN IF NOT EXISTS block BOOLEAN NOT NULL DEFAULT FALSE",
+		// URL-based dedupe for feeds that regenerate GUIDs.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS dedupe_by_url BOOLEAN NOT NULL DEFAULT FALSE",
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS guid_churn_polls BIGINT NOT NULL DEFAULT 0",
+		// Detected article language, "unknown" when undetected.
+		"ALTER TABLE articles ADD COLUMN IF NOT EXISTS lang TEXT NOT NULL DEFAULT 'unknown'",
+		// Visit tracking for "new since last visit".
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ",
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS prev_seen_at TIMESTAMPTZ",
+		// Grace period before an orphaned feed is deleted.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS delete_after TIMESTAMPTZ",
+		// Muted subscriptions keep fetching but stay out of the unread stream.
+		"ALTER TABLE user_feeds ADD COLUMN IF NOT EXISTS muted BOOLEAN NOT NULL DEFAULT FALSE",
+		// Word count of the article text, computed when the article is stored.
+		"ALTER TABLE articles ADD COLUMN IF NOT EXISTS word_count INTEGER NOT NULL DEFAULT 0",
+		// One-line curation justification shown as "Surfaced because: ...".
+		"ALTER TABLE read_state ADD COLUMN IF NOT EXISTS interest_reason TEXT",
+		// Per-user folder path ("Tech/Go"), carried in from OPML outlines.
+		"ALTER TABLE user_feeds ADD COLUMN IF NOT EXISTS folder TEXT NOT NULL DEFAULT ''",
+		// When a notify_when=present article was surfaced, so it isn't repeated.
+		"ALTER TABLE read_state ADD COLUMN IF NOT EXISTS presented_at TIMESTAMPTZ",
+		// Equivalence key for feed URLs, so http/https and www variants share a row.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS normalized_url TEXT NOT NULL DEFAULT ''",
+		"CREATE INDEX IF NOT EXISTS idx_feeds_normalized_url ON feeds(normalized_url)",
+		// Per-feed cap on stored articles; 0 means unlimited.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS max_articles BIGINT NOT NULL DEFAULT 0",
+		// Stable permalink key, derived from the feed URL and GUID.
+		"ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug TEXT NOT NULL DEFAULT ''",
+		"CREATE INDEX IF NOT EXISTS idx_articles_slug ON articles(slug)",
+		// Hash of the last feed body, so an unchanged body skips storage.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS body_hash TEXT NOT NULL DEFAULT ''",
+		// Extra request headers (JSON object) for feeds that need auth.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS fetch_headers TEXT NOT NULL DEFAULT ''",
+		// Which article field (content, summary or longest) a feed's text comes from.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS content_source TEXT NOT NULL DEFAULT 'content'",
+		// Publisher-advertised polling interval in minutes (<ttl>, sy:updatePeriod); 0 = none.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS suggested_interval BIGINT NOT NULL DEFAULT 0",
+		// Feed-declared generator and language, refreshed on every fetch.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS generator TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT ''",
+		// Feed-reported modification time of an item, for detecting edits.
+		"ALTER TABLE articles ADD COLUMN IF NOT EXISTS updated_date TIMESTAMPTZ",
+		// How many times the user has opened an article.
+		"ALTER TABLE read_state ADD COLUMN IF NOT EXISTS open_count INTEGER NOT NULL DEFAULT 0",
+		// Regexps (JSON array) removed from a feed's text before AI processing.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS strip_patterns TEXT NOT NULL DEFAULT ''",
+		// Last failed favicon lookup, so retries back off instead of running every poll.
+		"ALTER TABLE feeds ADD COLUMN IF NOT EXISTS favicon_checked_at TIMESTAMPTZ",
 		// Full-text search: tsvector column with GIN index.
 		"ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector",
 		"CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING gin(search_vector)",
@@ -92,16 +150,57 @@ func NewPostgresStore(dsn string) (*PostgresStore, error) {
 			return nil, fmt.Errorf("failed to run postgres migration: %w", err)
 		}
 	}
-	return &PostgresStore{db: &tracedDB{DB: db, useRebind: true}}, nil
+	store := &PostgresStore{db: &tracedDB{DB: db, useRebind: true}}
+	if err := backfillFeedNormalizedURLs(store.db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill normalized feed URLs: %w", err)
+	}
+	if err := backfillArticleSlugs(store.db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill article slugs: %w", err)
+	}
+	if err := backfillArticleURLs(store.db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill article URLs: %w", err)
+	}
+	return store, nil
 }
 
 func (s *PostgresStore) Close() error { return s.db.Close() }
 
+func (s *PostgresStore) Ping() error {
+	var one int
+	if err := s.db.QueryRow("SELECT 1").Scan(&one); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Backup(destPath string) error {
+	return fmt.Errorf("backup is not supported for PostgreSQL; use pg_dump instead")
+}
+
+func (s *PostgresStore) IntegrityCheck() ([]string, error) {
+	return nil, fmt.Errorf("integrity check is not supported for PostgreSQL")
+}
+
+func (s *PostgresStore) Vacuum() error {
+	if _, err := s.db.Exec("VACUUM ANALYZE"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
+
 // --- Internal helpers ---
 
-// computeFeedBaseInterval queries the last 11 article publish dates for feedID
-// and returns a fetch interval based on posting recency and frequency.
+// computeFeedBaseInterval returns the feed's suggested interval when the
+// publisher advertises one; otherwise it queries the last 11 article publish
+// dates for feedID and returns a fetch interval based on posting recency and
+// frequency.
 func (s *PostgresStore) computeFeedBaseInterval(feedID int64) time.Duration {
+	if d := suggestedBaseInterval(s.db, feedID); d > 0 {
+		return d
+	}
 	rows, err := s.db.Query(
 		`SELECT published_date FROM articles
 		 WHERE feed_id = ? AND published_date IS NOT NULL
@@ -173,13 +272,34 @@ func pickFetchIntervalPG(lastPostAge, medianPostInterval time.Duration) time.Dur
 
 func (s *PostgresStore) CreateUser(name string) (int64, error) {
 	var id int64
-	err := s.db.QueryRow("INSERT INTO users (name) VALUES (?) RETURNING id", name).Scan(&id)
+	err := s.db.queryRowWrite("INSERT INTO users (name) VALUES (?) RETURNING id", name).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("create user: %w", err)
 	}
 	return id, nil
 }
 
+func (s *PostgresStore) EnsureDefaultUser() (int64, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO users (id, name) OVERRIDING SYSTEM VALUE
+		 SELECT 1, ? WHERE NOT EXISTS (SELECT 1 FROM users)
+		 ON CONFLICT DO NOTHING`,
+		DefaultUserName,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("create default user: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return 0, nil
+	}
+	// The explicit ID bypassed the identity sequence; move it past 1 so the
+	// next CreateUser doesn't collide.
+	if _, err := s.db.Exec(`SELECT setval(pg_get_serial_sequence('users', 'id'), 1)`); err != nil {
+		return 0, fmt.Errorf("create default user: %w", err)
+	}
+	return 1, nil
+}
+
 func (s *PostgresStore) GetUserByName(name string) (*User, error) {
 	var u User
 	var email sql.NullString
@@ -224,7 +344,7 @@ func (s *PostgresStore) CreateUserWithOIDC(name, email, sub string) (*User, erro
 		emailVal = &email
 	}
 	var id int64
-	err := s.db.QueryRow(
+	err := s.db.queryRowWrite(
 		"INSERT INTO users (name, oidc_sub, email) VALUES (?, ?, ?) RETURNING id",
 		name, sub, emailVal,
 	).Scan(&id)
@@ -266,6 +386,25 @@ func (s *PostgresStore) ListUsers() ([]User, error) {
 	return users, rows.Err()
 }
 
+func (s *PostgresStore) GetUserLastSeen(userID int64) (lastSeen, prevSeen time.Time, err error) {
+	var last, prev sql.NullTime
+	err = s.db.QueryRow("SELECT last_seen_at, prev_seen_at FROM users WHERE id = ?", userID).Scan(&last, &prev)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("get user last seen: %w", err)
+	}
+	return last.Time, prev.Time, nil
+}
+
+func (s *PostgresStore) SetUserLastSeen(userID int64, lastSeen, prevSeen time.Time) error {
+	prev := sql.NullTime{Time: prevSeen, Valid: !prevSeen.IsZero()}
+	_, err := s.db.Exec("UPDATE users SET last_seen_at = ?, prev_seen_at = ? WHERE id = ?",
+		lastSeen, prev, userID)
+	if err != nil {
+		return fmt.Errorf("set user last seen: %w", err)
+	}
+	return nil
+}
+
 // --- User prompts ---
 
 func (s *PostgresStore) GetUserPrompt(userID int64, promptType string) (string, error) {
@@ -411,6 +550,56 @@ func (s *PostgresStore) DeleteUserPreference(userID int64, key string) error {
 	return err
 }
 
+// --- Notification queue ---
+
+func (s *PostgresStore) EnqueueNotification(userID, articleID int64, interestScore float64) error {
+	_, err := s.db.Exec(
+		`INSERT INTO notification_queue (user_id, article_id, interest_score, queued_at)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT(user_id, article_id) DO NOTHING`,
+		userID, articleID, interestScore, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetQueuedNotifications(userID int64) ([]QueuedNotification, error) {
+	return getQueuedNotifications(s.db, userID)
+}
+
+func (s *PostgresStore) DeleteQueuedNotifications(userID, throughID int64) error {
+	return deleteQueuedNotifications(s.db, userID, throughID)
+}
+
+func (s *PostgresStore) PresentArticles(userID int64, minScore, minSecurity float64, limit int, filterThreshold *int) ([]PresentedArticle, error) {
+	filterSQL, filterArgs := filterScoreClausePG(userID, filterThreshold)
+	query := `
+		UPDATE read_state SET presented_at = NOW()
+		WHERE user_id = ? AND article_id IN (
+			SELECT a.id
+			FROM articles a
+			JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+			JOIN user_feeds uf ON uf.feed_id = a.feed_id AND uf.user_id = rs.user_id
+			WHERE rs.read = FALSE AND rs.presented_at IS NULL AND uf.muted = FALSE
+			  AND rs.interest_score >= ?
+			  AND (rs.security_score IS NULL OR rs.security_score >= ?)
+			` + filterSQL + `
+			ORDER BY rs.interest_score DESC, a.published_date DESC
+			LIMIT ?
+		)
+		RETURNING article_id, interest_score`
+	args := []interface{}{userID, userID, minScore, minSecurity}
+	args = append(args, filterArgs...)
+	args = append(args, limit)
+	rows, err := s.db.queryWrite(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to present articles: %w", err)
+	}
+	return scanPresentedArticles(rows)
+}
+
 // --- Read state ---
 
 func (s *PostgresStore) UpdateStarred(userID, articleID int64, starred bool) error {
@@ -426,6 +615,31 @@ func (s *PostgresStore) UpdateStarred(userID, articleID int64, starred bool) err
 	return nil
 }
 
+func (s *PostgresStore) SetStarredBatch(userID int64, articleIDs []int64, starred bool) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	values := strings.TrimSuffix(strings.Repeat("(?, ?, ?), ", len(articleIDs)), ", ")
+	args := make([]interface{}, 0, 3*len(articleIDs))
+	for _, id := range articleIDs {
+		args = append(args, userID, id, starred)
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO read_state (user_id, article_id, starred)
+		 VALUES `+values+`
+		 ON CONFLICT(user_id, article_id) DO UPDATE SET starred = excluded.starred`,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("update starred batch: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetInterestScores(userID int64, articleIDs []int64) (map[int64]float64, error) {
+	return queryInterestScores(s.db, userID, articleIDs)
+}
+
 func (s *PostgresStore) UpdateReadState(userID, articleID int64, read bool, interestScore, securityScore *float64, securityReason *string) error {
 	var err error
 	if interestScore != nil {
@@ -455,6 +669,17 @@ func (s *PostgresStore) UpdateReadState(userID, articleID int64, read bool, inte
 	return nil
 }
 
+func (s *PostgresStore) ToggleRead(userID, articleID int64) (bool, error) {
+	return toggleRead(s.db,
+		`INSERT INTO read_state (user_id, article_id, read, read_date)
+		 VALUES (?, ?, TRUE, NOW())
+		 ON CONFLICT(user_id, article_id) DO UPDATE SET
+		   read = NOT read_state.read,
+		   read_date = NOW()
+		 RETURNING read`,
+		userID, articleID)
+}
+
 // GetScoreStats returns AI scoring breakdown per feed for a user.
 func (s *PostgresStore) GetScoreStats(userID int64) (*ScoreStatsResult, error) {
 	rows, err := s.db.Query(`
@@ -517,6 +742,24 @@ func (s *PostgresStore) IncrementAIRetries(userID, articleID int64) error {
 	return nil
 }
 
+func (s *PostgresStore) RecordArticleOpen(userID, articleID int64) error {
+	_, err := s.db.Exec(
+		`INSERT INTO read_state (user_id, article_id, open_count)
+		 VALUES (?, ?, 1)
+		 ON CONFLICT(user_id, article_id) DO UPDATE SET
+		   open_count = read_state.open_count + 1`,
+		userID, articleID,
+	)
+	if err != nil {
+		return fmt.Errorf("record article open: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetOpenCounts(userID int64, articleIDs []int64) (map[int64]int, error) {
+	return queryOpenCounts(s.db, userID, articleIDs)
+}
+
 // ResetScores clears AI scores so articles are reprocessed by the pipeline.
 func (s *PostgresStore) ResetScores(userID int64, securityOnly bool, belowScore float64) (int64, error) {
 	var result sql.Result
@@ -544,13 +787,70 @@ func (s *PostgresStore) ResetScores(userID int64, securityOnly bool, belowScore
 	return n, nil
 }
 
+func (s *PostgresStore) ClearInterestScores(userID int64, minSecurityScore float64) (int64, error) {
+	result, err := s.db.Exec(
+		`UPDATE read_state SET interest_score = NULL
+		 WHERE user_id = ? AND read = FALSE AND ai_scored = TRUE AND security_score >= ?`,
+		userID, minSecurityScore,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("clear interest scores: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (s *PostgresStore) GetUncuratedUnreadArticles(userID int64) ([]Article, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+		WHERE rs.read = FALSE AND rs.ai_scored = TRUE AND rs.interest_score IS NULL
+		ORDER BY a.id`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get uncurated articles: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []Article
+	for rows.Next() {
+		var a Article
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
+			return nil, fmt.Errorf("scan article: %w", err)
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+func (s *PostgresStore) UpdateInterestScore(userID, articleID int64, score float64) error {
+	_, err := s.db.Exec(
+		`UPDATE read_state SET interest_score = ? WHERE user_id = ? AND article_id = ?`,
+		score, userID, articleID,
+	)
+	if err != nil {
+		return fmt.Errorf("update interest score: %w", err)
+	}
+	return nil
+}
+
 // --- Feeds ---
 
 func (s *PostgresStore) AddFeed(url, title, description string) (int64, error) {
+	existing, err := s.FindFeedByNormalizedURL(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add feed: %w", err)
+	}
+	if existing != nil {
+		return existing.ID, nil
+	}
 	var id int64
-	err := s.db.QueryRow(
-		"INSERT INTO feeds (url, title, description) VALUES (?, ?, ?) RETURNING id",
-		url, title, description,
+	err = s.db.queryRowWrite(
+		"INSERT INTO feeds (url, title, description, normalized_url) VALUES (?, ?, ?, ?) RETURNING id",
+		url, title, description, NormalizeFeedURL(url),
 	).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to add feed: %w", err)
@@ -558,10 +858,28 @@ func (s *PostgresStore) AddFeed(url, title, description string) (int64, error) {
 	return id, nil
 }
 
+func (s *PostgresStore) FindFeedByNormalizedURL(rawURL string) (*Feed, error) {
+	rows, err := s.db.Query(`
+		SELECT id, url, title, description, site_url, generator, language, last_fetched, last_error, etag, last_modified,
+		       enabled, created_at, consecutive_errors, next_fetch_at, status, body_hash
+		FROM feeds
+		WHERE normalized_url = ?
+		ORDER BY id LIMIT 1`, NormalizeFeedURL(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find feed: %w", err)
+	}
+	defer rows.Close()
+	feeds, err := scanFeeds(rows)
+	if err != nil || len(feeds) == 0 {
+		return nil, err
+	}
+	return &feeds[0], nil
+}
+
 func (s *PostgresStore) GetAllFeeds() ([]Feed, error) {
 	rows, err := s.db.Query(`
-		SELECT id, url, title, description, site_url, last_fetched, last_error, etag, last_modified,
-		       enabled, created_at, consecutive_errors, next_fetch_at, status
+		SELECT id, url, title, description, site_url, generator, language, last_fetched, last_error, etag, last_modified,
+		       enabled, created_at, consecutive_errors, next_fetch_at, status, body_hash
 		FROM feeds
 		WHERE enabled = TRUE AND status = 'active'
 		  AND (next_fetch_at IS NULL OR next_fetch_at <= NOW())`)
@@ -615,10 +933,10 @@ func (s *PostgresStore) MarkFeedFetched(feedID int64) error {
 	return nil
 }
 
-func (s *PostgresStore) UpdateFeedCacheHeaders(feedID int64, etag, lastModified string) error {
+func (s *PostgresStore) UpdateFeedCacheHeaders(feedID int64, etag, lastModified, bodyHash string) error {
 	_, err := s.db.Exec(
-		"UPDATE feeds SET etag = ?, last_modified = ? WHERE id = ?",
-		etag, lastModified, feedID,
+		"UPDATE feeds SET etag = ?, last_modified = ?, body_hash = ? WHERE id = ?",
+		etag, lastModified, bodyHash, feedID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update feed cache headers: %w", err)
@@ -661,24 +979,72 @@ func (s *PostgresStore) RenameUserFeed(userID, feedID int64, title string) error
 	return nil
 }
 
-func (s *PostgresStore) UpdateFeedSiteURL(feedID int64, siteURL string) error {
-	_, err := s.db.Exec("UPDATE feeds SET site_url = ? WHERE id = ?", siteURL, feedID)
+func (s *PostgresStore) GetUserFeedTitle(userID, feedID int64) (string, error) {
+	var title string
+	err := s.db.QueryRow(`
+		SELECT COALESCE(uf.user_title, f.title)
+		FROM feeds f
+		LEFT JOIN user_feeds uf ON uf.feed_id = f.id AND uf.user_id = ?
+		WHERE f.id = ?`, userID, feedID).Scan(&title)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return title, err
+}
+
+func (s *PostgresStore) SetFeedMuted(userID, feedID int64, muted bool) error {
+	res, err := s.db.Exec("UPDATE user_feeds SET muted = ? WHERE user_id = ? AND feed_id = ?", muted, userID, feedID)
 	if err != nil {
-		return fmt.Errorf("update feed site url: %w", err)
+		return fmt.Errorf("failed to set feed muted: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("not subscribed to feed %d", feedID)
 	}
 	return nil
 }
 
+func (s *PostgresStore) SetFeedFolder(userID, feedID int64, folder string) error {
+	res, err := s.db.Exec("UPDATE user_feeds SET folder = ? WHERE user_id = ? AND feed_id = ?", folder, userID, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed folder: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("not subscribed to feed %d", feedID)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetFeedsByFolder(userID int64) (map[string][]Feed, error) {
+	feeds, err := s.GetUserFeeds(userID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query("SELECT feed_id, folder FROM user_feeds WHERE user_id = ? AND folder <> ''", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed folders: %w", err)
+	}
+	defer rows.Close()
+	folders, err := scanFeedFolders(rows)
+	if err != nil {
+		return nil, err
+	}
+	return groupFeedsByFolder(feeds, folders), nil
+}
+
+func (s *PostgresStore) UpdateFeedMetadata(feedID int64, siteLink, generator, lang string) error {
+	return updateFeedMetadata(s.db, feedID, siteLink, generator, lang)
+}
+
 // --- Articles ---
 
-func (s *PostgresStore) FindDuplicateArticle(title string, publishedDate *time.Time) (int64, error) {
+func (s *PostgresStore) FindDuplicateArticle(feedID int64, guid, title string, publishedDate *time.Time) (int64, error) {
 	if title == "" || publishedDate == nil {
 		return 0, nil
 	}
 	var id int64
 	err := s.db.QueryRow(
-		"SELECT id FROM articles WHERE title = ? AND published_date = ? LIMIT 1",
-		title, publishedDate,
+		"SELECT id FROM articles WHERE title = ? AND published_date = ? AND NOT (feed_id = ? AND guid = ?) LIMIT 1",
+		title, publishedDate, feedID, guid,
 	).Scan(&id)
 	if err == sql.ErrNoRows {
 		return 0, nil
@@ -686,15 +1052,131 @@ func (s *PostgresStore) FindDuplicateArticle(title string, publishedDate *time.T
 	return id, err
 }
 
-func (s *PostgresStore) AddArticle(article *Article) (int64, error) {
+func (s *PostgresStore) FindArticleByURL(feedID int64, url string) (int64, string, error) {
+	if url == "" {
+		return 0, "", nil
+	}
 	var id int64
+	var guid string
 	err := s.db.QueryRow(
-		`INSERT INTO articles (feed_id, guid, title, url, content, summary, author, published_date)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		"SELECT id, guid FROM articles WHERE feed_id = ? AND url = ? ORDER BY id LIMIT 1",
+		feedID, url,
+	).Scan(&id, &guid)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	return id, guid, err
+}
+
+func (s *PostgresStore) GetFeedDedupeByURL(feedID int64) (bool, error) {
+	var enabled bool
+	err := s.db.QueryRow("SELECT dedupe_by_url FROM feeds WHERE id = ?", feedID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return enabled, err
+}
+
+func (s *PostgresStore) SetFeedDedupeByURL(feedID int64, enabled bool) error {
+	res, err := s.db.Exec("UPDATE feeds SET dedupe_by_url = ?, guid_churn_polls = 0 WHERE id = ?", enabled, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed dedupe mode: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("feed %d not found", feedID)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetFeedContentSource(feedID int64) (string, error) {
+	return getFeedContentSource(s.db, feedID)
+}
+
+func (s *PostgresStore) SetFeedContentSource(feedID int64, source string) error {
+	return setFeedContentSource(s.db, feedID, source)
+}
+
+func (s *PostgresStore) GetFeedStripPatterns(feedID int64) ([]string, error) {
+	return getFeedStripPatterns(s.db, feedID)
+}
+
+func (s *PostgresStore) SetFeedStripPatterns(feedID int64, patterns []string) error {
+	return setFeedStripPatterns(s.db, feedID, patterns)
+}
+
+func (s *PostgresStore) GetFeedSuggestedInterval(feedID int64) (time.Duration, error) {
+	return getFeedSuggestedInterval(s.db, feedID)
+}
+
+func (s *PostgresStore) SetFeedSuggestedInterval(feedID int64, interval time.Duration) error {
+	return setFeedSuggestedInterval(s.db, feedID, interval)
+}
+
+func (s *PostgresStore) GetFeedMaxArticles(feedID int64) (int, error) {
+	var n int
+	err := s.db.QueryRow("SELECT max_articles FROM feeds WHERE id = ?", feedID).Scan(&n)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return n, err
+}
+
+func (s *PostgresStore) SetFeedMaxArticles(feedID int64, n int) error {
+	if n < 0 {
+		return fmt.Errorf("max articles must not be negative")
+	}
+	res, err := s.db.Exec("UPDATE feeds SET max_articles = ? WHERE id = ?", n, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed max articles: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("feed %d not found", feedID)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetFeedFetchHeaders(feedID int64) (map[string]string, error) {
+	return getFeedFetchHeaders(s.db, feedID)
+}
+
+func (s *PostgresStore) SetFeedFetchHeaders(feedID int64, headers map[string]string) error {
+	return setFeedFetchHeaders(s.db, feedID, headers)
+}
+
+func (s *PostgresStore) TrimFeedArticles(feedID int64, keep int) (int, error) {
+	return trimFeedArticles(s.db, feedID, keep)
+}
+
+func (s *PostgresStore) RecordGUIDChurn(feedID int64, churned bool) (int, error) {
+	if !churned {
+		_, err := s.db.Exec("UPDATE feeds SET guid_churn_polls = 0 WHERE id = ? AND guid_churn_polls > 0", feedID)
+		return 0, err
+	}
+	var n int
+	err := s.db.queryRowWrite(
+		"UPDATE feeds SET guid_churn_polls = guid_churn_polls + 1 WHERE id = ? RETURNING guid_churn_polls",
+		feedID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record guid churn: %w", err)
+	}
+	return n, nil
+}
+
+func (s *PostgresStore) AddArticle(article *Article) (int64, error) {
+	slug, err := articleSlug(s.db, article)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add article: %w", err)
+	}
+	var id int64
+	err = s.db.queryRowWrite(
+		`INSERT INTO articles (feed_id, guid, title, url, domain, content, summary, author, published_date, updated_date, lang, word_count, slug)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(feed_id, guid) DO NOTHING
 		 RETURNING id`,
-		article.FeedID, article.GUID, article.Title, article.URL,
-		article.Content, article.Summary, article.Author, article.PublishedDate,
+		article.FeedID, article.GUID, article.Title, article.URL, articleDomain(article.URL),
+		article.Content, article.Summary, article.Author, article.PublishedDate, article.UpdatedDate,
+		articleLang(article.Lang), article.WordCount, slug,
 	).Scan(&id)
 	if err == sql.ErrNoRows {
 		return 0, nil // duplicate
@@ -705,14 +1187,18 @@ func (s *PostgresStore) AddArticle(article *Article) (int64, error) {
 	return id, nil
 }
 
+func (s *PostgresStore) UpsertArticle(article *Article) (int64, bool, error) {
+	return upsertArticle(s.db, article, s.AddArticle)
+}
+
 func (s *PostgresStore) GetUnreadArticles(limit int) ([]Article, error) {
 	rows, err := s.db.Query(`
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		LEFT JOIN read_state rs ON a.id = rs.article_id
 		WHERE rs.article_id IS NULL OR rs.read = FALSE
-		ORDER BY a.published_date DESC
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
 		LIMIT ?`, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get unread articles: %w", err)
@@ -725,73 +1211,99 @@ func (s *PostgresStore) GetArticle(articleID int64) (*Article, error) {
 	var a Article
 	err := s.db.QueryRow(
 		`SELECT id, feed_id, guid, title, url, content, summary,
-		        author, published_date, fetched_date,
-		        COALESCE(linked_url,''), COALESCE(linked_content,'')
+		        author, published_date, fetched_date, word_count,
+		        COALESCE(linked_url,''), COALESCE(linked_content,''), lang, slug
 		 FROM articles WHERE id = ?`, articleID,
 	).Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-		&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate,
-		&a.LinkedURL, &a.LinkedContent)
+		&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount,
+		&a.LinkedURL, &a.LinkedContent, &a.Lang, &a.Slug)
 	if err != nil {
 		return nil, fmt.Errorf("get article %d: %w", articleID, err)
 	}
 	return &a, nil
 }
 
-func (s *PostgresStore) GetArticlesByInterestScore(userID int64, threshold float64, limit, offset int, filterThreshold *int) ([]Article, []float64, error) {
+func (s *PostgresStore) GetArticleBySlug(slug string) (*Article, error) {
+	return getArticleBySlug(s.db, s, slug)
+}
+
+func (s *PostgresStore) GetArticlesByInterestScore(userID int64, threshold float64, limit, offset int, filterThreshold *int) ([]Article, []float64, []float64, error) {
 	filterSQL, filterArgs := filterScoreClausePG(userID, filterThreshold)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date,
+		       a.author, a.published_date, a.fetched_date, a.word_count,
+		       COALESCE(rs.interest_score, 0) AS raw_score,
 		       COALESCE(rs.interest_score, 0) * (1.0 / (1.0 + GREATEST(0, EXTRACT(epoch FROM (NOW() - COALESCE(a.published_date, a.fetched_date))) / 86400.0) * 0.1)) AS decayed_score
 		FROM articles a
 		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
 		WHERE rs.interest_score >= ? AND rs.read = FALSE
 		` + filterSQL + `
-		ORDER BY decayed_score DESC
+		ORDER BY decayed_score DESC, COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
 		LIMIT ? OFFSET ?`
 	args := []interface{}{userID, threshold}
 	args = append(args, filterArgs...)
 	args = append(args, limit, offset)
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get articles by interest score: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to get articles by interest score: %w", err)
 	}
 	defer rows.Close()
 
 	var articles []Article
-	var scores []float64
+	var scores, rawScores []float64
 	for rows.Next() {
 		var a Article
-		var score float64
+		var score, raw float64
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &score); err != nil {
-			return nil, nil, fmt.Errorf("failed to scan article: %w", err)
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount, &raw, &score); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 		articles = append(articles, a)
 		scores = append(scores, score)
+		rawScores = append(rawScores, raw)
 	}
-	return articles, scores, rows.Err()
+	return articles, scores, rawScores, rows.Err()
+}
+
+// unreadOrderClausePG is unreadOrderClause with Postgres date arithmetic.
+func unreadOrderClausePG(order string) (string, error) {
+	if order != OrderScore {
+		return unreadOrderClause(order)
+	}
+	return `ORDER BY COALESCE(rs.interest_score, 0) * (1.0 / (1.0 + GREATEST(0, EXTRACT(epoch FROM (NOW() - COALESCE(a.published_date, a.fetched_date))) / 86400.0) * 0.1)) DESC,
+			COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC`, nil
+}
+
+func (s *PostgresStore) GetUnreadArticlesForUser(userID int64, limit, offset int, filterThreshold *int, languages []string) ([]Article, error) {
+	return s.GetUnreadArticlesOrdered(userID, limit, offset, filterThreshold, languages, OrderNewest)
 }
 
-func (s *PostgresStore) GetUnreadArticlesForUser(userID int64, limit, offset int, filterThreshold *int) ([]Article, error) {
+func (s *PostgresStore) GetUnreadArticlesOrdered(userID int64, limit, offset int, filterThreshold *int, languages []string, order string) ([]Article, error) {
+	orderSQL, err := unreadOrderClausePG(order)
+	if err != nil {
+		return nil, err
+	}
 	filterSQL, filterArgs := filterScoreClausePG(userID, filterThreshold)
+	langSQL, langArgs := languageClause(languages)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		LEFT JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
-		WHERE uf.user_id = ? AND (rs.article_id IS NULL OR rs.read = FALSE)
+		WHERE uf.user_id = ? AND uf.muted = FALSE AND (rs.article_id IS NULL OR rs.read = FALSE)
 		AND NOT EXISTS (
 			SELECT 1 FROM article_group_members agm
 			JOIN article_groups ag ON agm.group_id = ag.id
 			WHERE agm.article_id = a.id AND ag.user_id = ?
 		)
 		` + filterSQL + `
-		ORDER BY a.published_date DESC
+		` + langSQL + `
+		` + orderSQL + `
 		LIMIT ? OFFSET ?`
 	args := []interface{}{userID, userID, userID}
 	args = append(args, filterArgs...)
+	args = append(args, langArgs...)
 	args = append(args, limit, offset)
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
@@ -805,7 +1317,7 @@ func (s *PostgresStore) GetUnreadArticlesByFeed(userID, feedID int64, limit, off
 	filterSQL, filterArgs := filterScoreClausePG(userID, filterThreshold)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		LEFT JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
@@ -816,7 +1328,7 @@ func (s *PostgresStore) GetUnreadArticlesByFeed(userID, feedID int64, limit, off
 			WHERE agm.article_id = a.id AND ag.user_id = ?
 		)
 		` + filterSQL + `
-		ORDER BY a.published_date DESC
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
 		LIMIT ? OFFSET ?`
 	args := []interface{}{userID, userID, feedID, userID}
 	args = append(args, filterArgs...)
@@ -829,17 +1341,23 @@ func (s *PostgresStore) GetUnreadArticlesByFeed(userID, feedID int64, limit, off
 	return scanArticles(rows)
 }
 
-func (s *PostgresStore) GetUnscoredArticlesForUser(userID int64, limit int) ([]Article, error) {
-	rows, err := s.db.Query(`
+func (s *PostgresStore) GetUnscoredArticlesForUser(userID int64, limit int, minAge time.Duration) ([]Article, error) {
+	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		LEFT JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
 		WHERE uf.user_id = ? AND (rs.article_id IS NULL OR rs.ai_scored = FALSE)
-		  AND COALESCE(rs.ai_retries, 0) < 3
-		ORDER BY a.published_date DESC
-		LIMIT ?`, userID, userID, limit)
+		  AND COALESCE(rs.ai_retries, 0) < 3`
+	args := []any{userID, userID}
+	if minAge > 0 {
+		query += ` AND COALESCE(a.published_date, a.fetched_date) <= ?`
+		args = append(args, time.Now().UTC().Add(-minAge))
+	}
+	query += ` ORDER BY a.published_date DESC LIMIT ?`
+	args = append(args, limit)
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get unscored articles for user: %w", err)
 	}
@@ -883,7 +1401,7 @@ func (s *PostgresStore) GetUnsummarizedArticleCount(userID int64) (int, error) {
 func (s *PostgresStore) GetArticlesNeedingFullText(limit int) ([]Article, error) {
 	rows, err := s.db.Query(`
 		SELECT id, feed_id, guid, title, url, COALESCE(content,''), COALESCE(summary,''),
-		       COALESCE(author,''), published_date, fetched_date
+		       COALESCE(author,''), published_date, fetched_date, word_count
 		FROM articles
 		WHERE full_text_fetched = FALSE
 		ORDER BY fetched_date DESC
@@ -900,6 +1418,11 @@ func (s *PostgresStore) UpdateArticleContent(articleID int64, content string) er
 	return err
 }
 
+func (s *PostgresStore) UpdateArticleWordCount(articleID int64, wordCount int) error {
+	_, err := s.db.Exec(`UPDATE articles SET word_count = ? WHERE id = ?`, wordCount, articleID)
+	return err
+}
+
 func (s *PostgresStore) UpdateArticleLinkedContent(articleID int64, linkedURL, linkedContent string) error {
 	_, err := s.db.Exec(
 		`UPDATE articles SET linked_url = ?, linked_content = ? WHERE id = ?`,
@@ -913,11 +1436,37 @@ func (s *PostgresStore) MarkArticleFullTextFetched(articleID int64) error {
 	return err
 }
 
+func (s *PostgresStore) GetArticlesSince(userID int64, since time.Time, limit int) ([]Article, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		WHERE uf.user_id = ? AND a.fetched_date > ?
+		ORDER BY a.fetched_date DESC, a.id DESC
+		LIMIT ?`, userID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles since: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []Article
+	for rows.Next() {
+		var a Article
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
 func (s *PostgresStore) GetStarredArticles(userID int64, limit, offset int, filterThreshold *int) ([]Article, error) {
 	filterSQL, filterArgs := filterScoreClausePG(userID, filterThreshold)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
@@ -936,11 +1485,122 @@ func (s *PostgresStore) GetStarredArticles(userID int64, limit, offset int, filt
 	return scanArticles(rows)
 }
 
+func (s *PostgresStore) GetArticlesByCategory(userID int64, category string, limit, offset int, filterThreshold *int) ([]Article, error) {
+	filterSQL, filterArgs := filterScoreClausePG(userID, filterThreshold)
+	query := `
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN article_categories ac ON ac.article_id = a.id
+		WHERE uf.user_id = ? AND lower(ac.category) = lower(?)
+		` + filterSQL + `
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
+		LIMIT ? OFFSET ?`
+	args := []interface{}{userID, category}
+	args = append(args, filterArgs...)
+	args = append(args, limit, offset)
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles by category: %w", err)
+	}
+	defer rows.Close()
+	return scanArticles(rows)
+}
+
+func (s *PostgresStore) GetReadingQueue(userID int64, limit, offset int, filterThreshold *int) ([]Article, error) {
+	filterSQL, filterArgs := filterScoreClausePG(userID, filterThreshold)
+	query := `
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+		WHERE uf.user_id = ? AND rs.starred = TRUE AND rs.read = FALSE
+		` + filterSQL + `
+		ORDER BY COALESCE(a.published_date, a.fetched_date) ASC, a.id ASC
+		LIMIT ? OFFSET ?`
+	args := []interface{}{userID, userID}
+	args = append(args, filterArgs...)
+	args = append(args, limit, offset)
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading queue: %w", err)
+	}
+	defer rows.Close()
+	return scanArticles(rows)
+}
+
+func (s *PostgresStore) GetReadArticles(userID int64, limit, offset int) ([]Article, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+		WHERE uf.user_id = ? AND rs.read = TRUE AND rs.read_date IS NOT NULL
+		ORDER BY rs.read_date DESC, a.id DESC
+		LIMIT ? OFFSET ?`, userID, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read articles: %w", err)
+	}
+	defer rows.Close()
+	return scanArticles(rows)
+}
+
+func (s *PostgresStore) GetUngroupedArticles(userID int64, limit, offset int) ([]Article, error) {
+	query := `
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+		WHERE uf.user_id = ? AND rs.read = FALSE
+		  AND NOT EXISTS (
+		      SELECT 1 FROM article_group_members agm
+		      JOIN article_groups ag ON agm.group_id = ag.id
+		      WHERE agm.article_id = a.id AND ag.user_id = ?
+		  )
+		ORDER BY COALESCE(rs.interest_score, 0) DESC, a.published_date DESC
+		LIMIT ? OFFSET ?`
+	rows, err := s.db.Query(query, userID, userID, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ungrouped articles: %w", err)
+	}
+	defer rows.Close()
+	return scanArticles(rows)
+}
+
+func (s *PostgresStore) IsArticleBlocked(userID, articleID int64, threshold float64) (bool, error) {
+	return isArticleBlocked(s.db, userID, articleID, threshold)
+}
+
+func (s *PostgresStore) OverrideSecurity(userID, articleID int64, threshold, interestScore float64) error {
+	return overrideSecurity(s.db, userID, articleID, threshold, interestScore)
+}
+
+func (s *PostgresStore) GetBlockedArticles(userID int64, threshold float64, limit, offset int) ([]BlockedArticle, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count,
+		       rs.security_score, COALESCE(rs.security_reason, '')
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = uf.user_id
+		WHERE uf.user_id = ? AND rs.security_score IS NOT NULL AND rs.security_score < ?
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
+		LIMIT ? OFFSET ?`, userID, threshold, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked articles: %w", err)
+	}
+	return scanBlockedArticles(rows)
+}
+
 // --- Article images ---
 
 func (s *PostgresStore) StoreArticleImage(articleID int64, originalURL string, data []byte, mimeType string, width, height int) (int64, error) {
 	var id int64
-	err := s.db.QueryRow(
+	err := s.db.queryRowWrite(
 		`INSERT INTO article_images (article_id, original_url, data, mime_type, width, height)
 		 VALUES (?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(article_id, original_url) DO UPDATE SET
@@ -996,7 +1656,7 @@ func (s *PostgresStore) GetArticleImageMap(articleID int64) (map[string]int64, e
 func (s *PostgresStore) GetArticlesNeedingImageCache(limit int) ([]Article, error) {
 	rows, err := s.db.Query(`
 		SELECT id, feed_id, guid, title, url, COALESCE(content,''), COALESCE(summary,''),
-		       COALESCE(author,''), published_date, fetched_date
+		       COALESCE(author,''), published_date, fetched_date, word_count
 		FROM articles
 		WHERE images_cached = FALSE
 		ORDER BY fetched_date DESC
@@ -1078,9 +1738,68 @@ func (s *PostgresStore) GetArticleCategories(articleID int64) ([]string, error)
 		if err := rows.Scan(&cat); err != nil {
 			return nil, fmt.Errorf("scan article category: %w", err)
 		}
-		cats = append(cats, cat)
+		cats = append(cats, cat)
+	}
+	return cats, rows.Err()
+}
+
+func (s *PostgresStore) StoreAITags(userID, articleID int64, tags []string) error {
+	if _, err := s.db.Exec("DELETE FROM ai_tags WHERE user_id = ? AND article_id = ?", userID, articleID); err != nil {
+		return fmt.Errorf("clear ai tags: %w", err)
+	}
+	for _, tag := range tags {
+		_, err := s.db.Exec(
+			"INSERT INTO ai_tags (user_id, article_id, tag) VALUES (?, ?, ?) ON CONFLICT DO NOTHING",
+			userID, articleID, tag,
+		)
+		if err != nil {
+			return fmt.Errorf("store ai tag: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetAITags(userID, articleID int64) ([]string, error) {
+	rows, err := s.db.Query(
+		"SELECT tag FROM ai_tags WHERE user_id = ? AND article_id = ? ORDER BY tag", userID, articleID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get ai tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("scan ai tag: %w", err)
+		}
+		tags = append(tags, tag)
 	}
-	return cats, rows.Err()
+	return tags, rows.Err()
+}
+
+func (s *PostgresStore) SetInterestReason(userID, articleID int64, reason string) error {
+	_, err := s.db.Exec(
+		"UPDATE read_state SET interest_reason = ? WHERE user_id = ? AND article_id = ?",
+		reason, userID, articleID,
+	)
+	return err
+}
+
+func (s *PostgresStore) GetInterestReason(userID, articleID int64) (string, error) {
+	var reason sql.NullString
+	err := s.db.QueryRow(
+		"SELECT interest_reason FROM read_state WHERE user_id = ? AND article_id = ?",
+		userID, articleID,
+	).Scan(&reason)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get interest reason: %w", err)
+	}
+	return reason.String, nil
 }
 
 // --- Feed metadata discovery ---
@@ -1133,10 +1852,10 @@ func (s *PostgresStore) GetFeedCategories(feedID int64) ([]string, error) {
 
 func (s *PostgresStore) AddFilterRule(rule *FilterRule) (int64, error) {
 	var id int64
-	err := s.db.QueryRow(
-		`INSERT INTO filter_rules (user_id, feed_id, axis, value, score)
-		 VALUES (?, ?, ?, ?, ?) RETURNING id`,
-		rule.UserID, rule.FeedID, rule.Axis, rule.Value, rule.Score,
+	err := s.db.queryRowWrite(
+		`INSERT INTO filter_rules (user_id, feed_id, axis, value, score, block)
+		 VALUES (?, ?, ?, ?, ?, ?) RETURNING id`,
+		rule.UserID, rule.FeedID, rule.Axis, rule.Value, rule.Score, rule.Block,
 	).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("add filter rule: %w", err)
@@ -1148,12 +1867,12 @@ func (s *PostgresStore) GetFilterRules(userID int64, feedID *int64) ([]FilterRul
 	var query string
 	var args []interface{}
 	if feedID != nil {
-		query = `SELECT id, user_id, feed_id, axis, value, score, created_at
+		query = `SELECT id, user_id, feed_id, axis, value, score, block, created_at
 				 FROM filter_rules WHERE user_id = ? AND (feed_id IS NULL OR feed_id = ?)
 				 ORDER BY axis, value`
 		args = []interface{}{userID, *feedID}
 	} else {
-		query = `SELECT id, user_id, feed_id, axis, value, score, created_at
+		query = `SELECT id, user_id, feed_id, axis, value, score, block, created_at
 				 FROM filter_rules WHERE user_id = ? ORDER BY axis, value`
 		args = []interface{}{userID}
 	}
@@ -1166,7 +1885,7 @@ func (s *PostgresStore) GetFilterRules(userID int64, feedID *int64) ([]FilterRul
 	var rules []FilterRule
 	for rows.Next() {
 		var r FilterRule
-		if err := rows.Scan(&r.ID, &r.UserID, &r.FeedID, &r.Axis, &r.Value, &r.Score, &r.CreatedAt); err != nil {
+		if err := rows.Scan(&r.ID, &r.UserID, &r.FeedID, &r.Axis, &r.Value, &r.Score, &r.Block, &r.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan filter rule: %w", err)
 		}
 		rules = append(rules, r)
@@ -1190,6 +1909,10 @@ func (s *PostgresStore) DeleteFilterRule(ruleID int64) error {
 	return nil
 }
 
+func (s *PostgresStore) ImportFilterRules(rules []FilterRule) (int, error) {
+	return importFilterRules(s.db, rules)
+}
+
 func (s *PostgresStore) HasFilterRules(userID int64) (bool, error) {
 	var count int
 	err := s.db.QueryRow("SELECT COUNT(*) FROM filter_rules WHERE user_id = ?", userID).Scan(&count)
@@ -1199,6 +1922,32 @@ func (s *PostgresStore) HasFilterRules(userID int64) (bool, error) {
 	return count > 0, nil
 }
 
+func (s *PostgresStore) ExplainArticleScore(userID, articleID int64) ([]FilterMatch, error) {
+	rows, err := s.db.Query(
+		`SELECT fr.id, fr.feed_id, fr.axis, fr.value, fr.score, fr.block
+		 FROM filter_rules fr
+		 JOIN articles a ON a.id = ?
+		 WHERE fr.user_id = ?
+		   AND `+filterRuleMatchPG+`
+		 ORDER BY fr.block DESC, fr.score DESC, fr.axis, fr.value`,
+		articleID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("explain article score: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []FilterMatch
+	for rows.Next() {
+		var m FilterMatch
+		if err := rows.Scan(&m.RuleID, &m.FeedID, &m.Axis, &m.Value, &m.Score, &m.Block); err != nil {
+			return nil, fmt.Errorf("scan filter match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
 // --- Article summaries ---
 
 func (s *PostgresStore) UpdateArticleAISummary(userID, articleID int64, aiSummary string) error {
@@ -1231,6 +1980,63 @@ func (s *PostgresStore) GetArticleSummary(userID, articleID int64) (*ArticleSumm
 	return &as, nil
 }
 
+func (s *PostgresStore) SetArticleNote(userID, articleID int64, note string) error {
+	var err error
+	if note == "" {
+		_, err = s.db.Exec("DELETE FROM article_notes WHERE user_id = ? AND article_id = ?", userID, articleID)
+	} else {
+		_, err = s.db.Exec(
+			`INSERT INTO article_notes (user_id, article_id, note, updated_at)
+			 VALUES (?, ?, ?, NOW())
+			 ON CONFLICT(user_id, article_id) DO UPDATE SET note = excluded.note, updated_at = NOW()`,
+			userID, articleID, note,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("set article note: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetArticleNote(userID, articleID int64) (*ArticleNote, error) {
+	var n ArticleNote
+	err := s.db.QueryRow(
+		"SELECT user_id, article_id, note, updated_at FROM article_notes WHERE user_id = ? AND article_id = ?",
+		userID, articleID,
+	).Scan(&n.UserID, &n.ArticleID, &n.Note, &n.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get article note: %w", err)
+	}
+	return &n, nil
+}
+
+func (s *PostgresStore) GetSummarizedArticleIDs(userID, feedID int64) ([]int64, error) {
+	rows, err := s.db.Query(
+		`SELECT s.article_id FROM article_summaries s
+		 JOIN articles a ON a.id = s.article_id
+		 WHERE s.user_id = ? AND a.feed_id = ?
+		 ORDER BY s.article_id`,
+		userID, feedID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get summarized articles: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan summarized article: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // --- Feed stats ---
 
 func (s *PostgresStore) GetFeedStats(userID int64) ([]FeedStats, error) {
@@ -1244,14 +2050,14 @@ func (s *PostgresStore) GetFeedStats(userID int64) ([]FeedStats, error) {
 			           WHERE agm.article_id = a.id AND ag.user_id = uf.user_id
 			         ) THEN 1 ELSE 0 END),
 			COUNT(a.id) - COUNT(asumm.article_id),
-			MAX(a.published_date)
+			MAX(a.published_date), uf.muted, uf.folder
 		FROM feeds f
 		JOIN user_feeds uf ON uf.feed_id = f.id AND uf.user_id = ?
 		JOIN articles a ON a.feed_id = f.id
 		LEFT JOIN read_state rs ON rs.article_id = a.id AND rs.user_id = ?
 		LEFT JOIN article_summaries asumm ON asumm.article_id = a.id AND asumm.user_id = ?
-		GROUP BY f.id, uf.user_title
-		ORDER BY COALESCE(uf.user_title, f.title)`,
+		GROUP BY f.id, uf.user_title, uf.muted, uf.folder
+		ORDER BY uf.folder, COALESCE(uf.user_title, f.title)`,
 		userID, userID, userID,
 	)
 	if err != nil {
@@ -1262,7 +2068,7 @@ func (s *PostgresStore) GetFeedStats(userID int64) ([]FeedStats, error) {
 	var stats []FeedStats
 	for rows.Next() {
 		var fs FeedStats
-		if err := rows.Scan(&fs.FeedID, &fs.FeedTitle, &fs.TotalArticles, &fs.UnreadArticles, &fs.UnsummarizedArticles, &fs.LastPostDate); err != nil {
+		if err := rows.Scan(&fs.FeedID, &fs.FeedTitle, &fs.TotalArticles, &fs.UnreadArticles, &fs.UnsummarizedArticles, &fs.LastPostDate, &fs.Muted, &fs.Folder); err != nil {
 			return nil, fmt.Errorf("scan feed stats: %w", err)
 		}
 		stats = append(stats, fs)
@@ -1274,7 +2080,7 @@ func (s *PostgresStore) GetFeedStats(userID int64) ([]FeedStats, error) {
 
 func (s *PostgresStore) CreateArticleGroup(userID int64, topic string) (int64, error) {
 	var id int64
-	err := s.db.QueryRow(
+	err := s.db.queryRowWrite(
 		"INSERT INTO article_groups (user_id, topic) VALUES (?, ?) RETURNING id",
 		userID, topic,
 	).Scan(&id)
@@ -1299,11 +2105,11 @@ func (s *PostgresStore) AddArticleToGroup(groupID, articleID int64) error {
 func (s *PostgresStore) GetGroupArticles(groupID int64) ([]Article, error) {
 	rows, err := s.db.Query(`
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN article_group_members agm ON a.id = agm.article_id
 		WHERE agm.group_id = ?
-		ORDER BY a.published_date DESC`, groupID)
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC`, groupID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get group articles: %w", err)
 	}
@@ -1341,6 +2147,32 @@ func (s *PostgresStore) GetGroupSummary(groupID int64) (*GroupSummary, error) {
 	return &gs, nil
 }
 
+func (s *PostgresStore) GetRecentGroups(userID int64, since time.Time) ([]ArticleGroup, error) {
+	rows, err := s.db.Query(`
+		SELECT id, user_id, topic, display_name, muted, created_at, updated_at
+		FROM article_groups
+		WHERE user_id = ? AND updated_at >= ?
+		ORDER BY updated_at DESC`, userID, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []ArticleGroup
+	for rows.Next() {
+		var g ArticleGroup
+		var displayName *string
+		if err := rows.Scan(&g.ID, &g.UserID, &g.Topic, &displayName, &g.Muted, &g.CreatedAt, &g.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		if displayName != nil {
+			g.DisplayName = *displayName
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
 func (s *PostgresStore) GetUserGroups(userID int64) ([]ArticleGroup, error) {
 	rows, err := s.db.Query(`
 		SELECT ag.id, ag.user_id, ag.topic, ag.display_name, ag.muted, ag.created_at, ag.updated_at
@@ -1407,14 +2239,14 @@ func (s *PostgresStore) GetUnreadGroupArticles(userID, groupID int64, limit, off
 	filterSQL, filterArgs := filterScoreClausePG(userID, filterThreshold)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN article_group_members agm ON a.id = agm.article_id
 		JOIN article_groups ag ON agm.group_id = ag.id
 		LEFT JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
 		WHERE agm.group_id = ? AND ag.user_id = ? AND (rs.article_id IS NULL OR rs.read = FALSE)
 		` + filterSQL + `
-		ORDER BY a.published_date DESC
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
 		LIMIT ? OFFSET ?`
 	args := []interface{}{userID, groupID, userID}
 	args = append(args, filterArgs...)
@@ -1427,6 +2259,34 @@ func (s *PostgresStore) GetUnreadGroupArticles(userID, groupID int64, limit, off
 	return scanArticles(rows)
 }
 
+func (s *PostgresStore) GetGroupUnreadCounts(userID int64) (map[int64]int, error) {
+	rows, err := s.db.Query(`
+		SELECT ag.id,
+		       SUM(CASE WHEN rs.read IS NULL OR rs.read = FALSE THEN 1 ELSE 0 END)
+		FROM article_groups ag
+		JOIN article_group_members agm ON agm.group_id = ag.id
+		LEFT JOIN read_state rs ON rs.article_id = agm.article_id AND rs.user_id = ?
+		WHERE ag.user_id = ?
+		GROUP BY ag.id`,
+		userID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get group unread counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var id int64
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, fmt.Errorf("scan group unread count: %w", err)
+		}
+		counts[id] = n
+	}
+	return counts, rows.Err()
+}
+
 func (s *PostgresStore) GetGroupStats(userID int64) ([]GroupStats, error) {
 	rows, err := s.db.Query(`
 		SELECT ag.id,
@@ -1476,11 +2336,7 @@ func (s *PostgresStore) IsGroupMuted(groupID int64) (bool, error) {
 }
 
 func (s *PostgresStore) DisbandGroup(groupID int64) error {
-	_, err := s.db.Exec("DELETE FROM article_groups WHERE id = ?", groupID)
-	if err != nil {
-		return fmt.Errorf("disband group: %w", err)
-	}
-	return nil
+	return disbandGroup(s.db, groupID)
 }
 
 func (s *PostgresStore) UpdateGroupDisplayName(groupID int64, displayName string) error {
@@ -1491,6 +2347,33 @@ func (s *PostgresStore) UpdateGroupDisplayName(groupID int64, displayName string
 	return nil
 }
 
+func (s *PostgresStore) RenameGroup(groupID int64, topic string) error {
+	_, err := s.db.Exec(
+		"UPDATE article_groups SET topic = ?, display_name = NULL, updated_at = NOW() WHERE id = ?",
+		topic, groupID,
+	)
+	if err != nil {
+		return fmt.Errorf("rename group: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) MergeGroups(srcID, dstID int64) error {
+	return mergeGroups(s.db, srcID, dstID)
+}
+
+func (s *PostgresStore) RemoveArticleFromGroup(groupID, articleID int64) error {
+	_, err := s.db.Exec(
+		"DELETE FROM article_group_members WHERE group_id = ? AND article_id = ?",
+		groupID, articleID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove article from group: %w", err)
+	}
+	_, err = s.db.Exec("UPDATE article_groups SET updated_at = NOW() WHERE id = ?", groupID)
+	return err
+}
+
 func (s *PostgresStore) UpdateGroupEmbedding(groupID int64, embedding []byte, model string) error {
 	_, err := s.db.Exec("UPDATE article_groups SET embedding = ?, embedding_model = ? WHERE id = ?", embedding, model, groupID)
 	if err != nil {
@@ -1600,15 +2483,21 @@ func (s *PostgresStore) GetAllFeedFavicons() ([]FeedFavicon, error) {
 	return favicons, rows.Err()
 }
 
+func (s *PostgresStore) MarkFaviconChecked(feedID int64) error {
+	_, err := s.db.Exec(`UPDATE feeds SET favicon_checked_at = NOW() WHERE id = ?`, feedID)
+	return err
+}
+
 func (s *PostgresStore) GetSubscribedFeedsWithoutFavicons() ([]Feed, error) {
 	rows, err := s.db.Query(`
-		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url,
+		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.generator, f.language,
 		       f.last_fetched, f.last_error, f.etag, f.last_modified,
-		       f.enabled, f.created_at, f.consecutive_errors, f.next_fetch_at, f.status
+		       f.enabled, f.created_at, f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
 		FROM feeds f
 		JOIN user_feeds uf ON f.id = uf.feed_id
 		LEFT JOIN feed_favicons ff ON f.id = ff.feed_id
 		WHERE ff.feed_id IS NULL
+		  AND (f.favicon_checked_at IS NULL OR f.favicon_checked_at < NOW() - INTERVAL '1 day')
 		ORDER BY f.id`)
 	if err != nil {
 		return nil, fmt.Errorf("get feeds without favicons: %w", err)
@@ -1620,6 +2509,9 @@ func (s *PostgresStore) GetSubscribedFeedsWithoutFavicons() ([]Feed, error) {
 // --- Subscriptions ---
 
 func (s *PostgresStore) SubscribeUserToFeed(userID, feedID int64) error {
+	if err := checkPrivateFeed(s.db, userID, feedID); err != nil {
+		return err
+	}
 	_, err := s.db.Exec(
 		"INSERT INTO user_feeds (user_id, feed_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
 		userID, feedID,
@@ -1627,14 +2519,17 @@ func (s *PostgresStore) SubscribeUserToFeed(userID, feedID int64) error {
 	if err != nil {
 		return fmt.Errorf("failed to subscribe user to feed: %w", err)
 	}
+	if _, err := s.db.Exec("UPDATE feeds SET delete_after = NULL WHERE id = ? AND delete_after IS NOT NULL", feedID); err != nil {
+		return fmt.Errorf("failed to cancel feed deletion: %w", err)
+	}
 	return nil
 }
 
 func (s *PostgresStore) GetUserFeeds(userID int64) ([]Feed, error) {
 	rows, err := s.db.Query(`
-		SELECT f.id, f.url, COALESCE(uf.user_title, f.title), f.description, f.site_url, f.last_fetched, f.last_error, f.etag,
+		SELECT f.id, f.url, COALESCE(uf.user_title, f.title), f.description, f.site_url, f.generator, f.language, f.last_fetched, f.last_error, f.etag,
 		       f.last_modified, f.enabled, f.created_at,
-		       f.consecutive_errors, f.next_fetch_at, f.status
+		       f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
 		FROM feeds f
 		JOIN user_feeds uf ON f.id = uf.feed_id
 		WHERE uf.user_id = ? AND f.enabled = TRUE
@@ -1646,11 +2541,32 @@ func (s *PostgresStore) GetUserFeeds(userID int64) ([]Feed, error) {
 	return scanFeeds(rows)
 }
 
+func (s *PostgresStore) SearchUserFeeds(userID int64, q string) ([]Feed, error) {
+	if q == "" {
+		return s.GetUserFeeds(userID)
+	}
+	rows, err := s.db.Query(`
+		SELECT f.id, f.url, COALESCE(uf.user_title, f.title), f.description, f.site_url, f.generator, f.language, f.last_fetched, f.last_error, f.etag,
+		       f.last_modified, f.enabled, f.created_at,
+		       f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
+		FROM feeds f
+		JOIN user_feeds uf ON f.id = uf.feed_id
+		WHERE uf.user_id = ? AND f.enabled = TRUE
+		  AND (strpos(lower(COALESCE(uf.user_title, f.title, '')), lower(?::text)) > 0
+		       OR strpos(lower(f.url), lower(?::text)) > 0)
+		ORDER BY COALESCE(uf.user_title, f.title)`, userID, q, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search user feeds: %w", err)
+	}
+	defer rows.Close()
+	return scanFeeds(rows)
+}
+
 func (s *PostgresStore) GetAllSubscribedFeeds() ([]Feed, error) {
 	rows, err := s.db.Query(`
-		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.last_fetched, f.last_error,
+		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.generator, f.language, f.last_fetched, f.last_error,
 		       f.etag, f.last_modified, f.enabled, f.created_at,
-		       f.consecutive_errors, f.next_fetch_at, f.status
+		       f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
 		FROM feeds f
 		JOIN user_feeds uf ON f.id = uf.feed_id
 		WHERE f.enabled = TRUE AND f.status = 'active'
@@ -1665,9 +2581,9 @@ func (s *PostgresStore) GetAllSubscribedFeeds() ([]Feed, error) {
 
 func (s *PostgresStore) GetAllActiveSubscribedFeeds() ([]Feed, error) {
 	rows, err := s.db.Query(`
-		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.last_fetched, f.last_error,
+		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.generator, f.language, f.last_fetched, f.last_error,
 		       f.etag, f.last_modified, f.enabled, f.created_at,
-		       f.consecutive_errors, f.next_fetch_at, f.status
+		       f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
 		FROM feeds f
 		JOIN user_feeds uf ON f.id = uf.feed_id
 		WHERE f.enabled = TRUE
@@ -1679,6 +2595,10 @@ func (s *PostgresStore) GetAllActiveSubscribedFeeds() ([]Feed, error) {
 	return scanFeeds(rows)
 }
 
+func (s *PostgresStore) IsSubscribed(userID, feedID int64) (bool, error) {
+	return isSubscribed(s.db, userID, feedID)
+}
+
 func (s *PostgresStore) GetFeedSubscribers(feedID int64) ([]int64, error) {
 	rows, err := s.db.Query("SELECT user_id FROM user_feeds WHERE feed_id = ?", feedID)
 	if err != nil {
@@ -1707,6 +2627,87 @@ func (s *PostgresStore) UnsubscribeUserFromFeed(userID, feedID int64) error {
 	return nil
 }
 
+func (s *PostgresStore) FeedExists(feedID int64) (bool, error) {
+	var exists bool
+	if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM feeds WHERE id = ?)", feedID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check feed: %w", err)
+	}
+	return exists, nil
+}
+
+func (s *PostgresStore) ScheduleFeedDeletion(feedID int64, at time.Time) (bool, error) {
+	res, err := s.db.Exec(
+		"UPDATE feeds SET delete_after = ? WHERE id = ? AND NOT EXISTS (SELECT 1 FROM user_feeds WHERE feed_id = ?)",
+		at.UTC(), feedID, feedID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to schedule feed deletion: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (s *PostgresStore) RecordUnsubscribe(userID, feedID int64, undoBefore time.Time) error {
+	return s.db.inTx(func(db *tracedDB) error {
+		if _, err := db.Exec("DELETE FROM feed_unsubscribes WHERE undo_before <= NOW()"); err != nil {
+			return fmt.Errorf("failed to prune unsubscribes: %w", err)
+		}
+		_, err := db.Exec(`
+			INSERT INTO feed_unsubscribes (user_id, feed_id, undo_before)
+			SELECT user_id, feed_id, ? FROM user_feeds WHERE user_id = ? AND feed_id = ?
+			ON CONFLICT(user_id, feed_id) DO UPDATE SET undo_before = excluded.undo_before`,
+			undoBefore.UTC(), userID, feedID)
+		if err != nil {
+			return fmt.Errorf("failed to record unsubscribe: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *PostgresStore) TakeUnsubscribe(userID, feedID int64, now time.Time) (bool, error) {
+	res, err := s.db.Exec(
+		"DELETE FROM feed_unsubscribes WHERE user_id = ? AND feed_id = ? AND undo_before > ?",
+		userID, feedID, now.UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to take unsubscribe: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (s *PostgresStore) GetFeedsDueForDeletion(now time.Time) ([]int64, error) {
+	rows, err := s.db.Query(`
+		SELECT id FROM feeds
+		WHERE delete_after IS NOT NULL AND delete_after <= ?
+		  AND NOT EXISTS (SELECT 1 FROM user_feeds WHERE feed_id = feeds.id)
+		ORDER BY id`, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feeds due for deletion: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan feed ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *PostgresStore) ReassignFeedArticles(fromFeedID, toFeedID int64) error {
+	return reassignFeedArticles(s.db, fromFeedID, toFeedID)
+}
+
 func (s *PostgresStore) DeleteFeedIfOrphaned(feedID int64) (bool, error) {
 	var n int
 	if err := s.db.QueryRow("SELECT COUNT(*) FROM user_feeds WHERE feed_id = ?", feedID).Scan(&n); err != nil {
@@ -1765,41 +2766,81 @@ func (s *PostgresStore) GetAllSubscribingUsers() ([]int64, error) {
 // --- Admin stats ---
 
 func (s *PostgresStore) GetDBStats() (DBStats, error) {
-	var stats DBStats
+	return getDBStats(s.db)
+}
+
+// --- Reading stats ---
+
+func (s *PostgresStore) GetReadingStats(userID int64, since time.Time) (ReadingStats, error) {
+	var stats ReadingStats
+	err := s.db.QueryRow(`
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE starred),
+			COUNT(*) FILTER (WHERE interest_score >= 8.0)
+		FROM read_state
+		WHERE user_id = ? AND read = TRUE AND read_date >= ?`,
+		userID, since.UTC(),
+	).Scan(&stats.Read, &stats.Starred, &stats.HighInterest)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get reading stats: %w", err)
+	}
 
 	rows, err := s.db.Query(`
-		SELECT
-			f.id, f.title, f.url, f.status,
-			COUNT(DISTINCT a.id)       AS articles,
-			COUNT(DISTINCT uf.user_id) AS subscribers
-		FROM feeds f
-		LEFT JOIN articles   a  ON a.feed_id  = f.id
-		LEFT JOIN user_feeds uf ON uf.feed_id = f.id
-		GROUP BY f.id
-		ORDER BY articles DESC
-	`)
+		SELECT to_char(read_date AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COUNT(*)
+		FROM read_state
+		WHERE user_id = ? AND read = TRUE AND read_date >= ?
+		GROUP BY day
+		ORDER BY day`,
+		userID, since.UTC())
 	if err != nil {
-		return stats, fmt.Errorf("failed to query feed stats: %w", err)
+		return stats, fmt.Errorf("failed to get daily reads: %w", err)
 	}
 	defer rows.Close()
-
 	for rows.Next() {
-		var fs FeedStat
-		if err := rows.Scan(&fs.ID, &fs.Title, &fs.URL, &fs.Status, &fs.Articles, &fs.Subscribers); err != nil {
-			return stats, fmt.Errorf("failed to scan feed stat: %w", err)
+		var d DayCount
+		if err := rows.Scan(&d.Day, &d.Count); err != nil {
+			return stats, fmt.Errorf("failed to scan daily reads: %w", err)
 		}
-		stats.Feeds = append(stats.Feeds, fs)
-		stats.TotalArticles += fs.Articles
-		stats.TotalFeeds++
+		stats.Daily = append(stats.Daily, d)
 	}
-	if err := rows.Err(); err != nil {
-		return stats, err
+	return stats, rows.Err()
+}
+
+// --- Poll history ---
+
+func (s *PostgresStore) RecordPollRun(run PollRun) error {
+	_, err := s.db.Exec(`
+		INSERT INTO poll_runs (started_at, duration_ms, feeds_total, feeds_downloaded, new_articles, processed, errored)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		run.StartedAt.UTC(), run.DurationMS, run.FeedsTotal, run.FeedsDownloaded,
+		run.NewArticles, run.Processed, run.Errored)
+	if err != nil {
+		return fmt.Errorf("failed to record poll run: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetPollRuns(limit int) ([]PollRun, error) {
+	rows, err := s.db.Query(`
+		SELECT id, started_at, duration_ms, feeds_total, feeds_downloaded, new_articles, processed, errored
+		FROM poll_runs
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query poll runs: %w", err)
 	}
+	defer rows.Close()
 
-	if err := s.db.QueryRow("SELECT COUNT(*) FROM users").Scan(&stats.TotalUsers); err != nil {
-		return stats, fmt.Errorf("failed to count users: %w", err)
+	var runs []PollRun
+	for rows.Next() {
+		var r PollRun
+		if err := rows.Scan(&r.ID, &r.StartedAt, &r.DurationMS, &r.FeedsTotal, &r.FeedsDownloaded,
+			&r.NewArticles, &r.Processed, &r.Errored); err != nil {
+			return nil, fmt.Errorf("failed to scan poll run: %w", err)
+		}
+		runs = append(runs, r)
 	}
-	return stats, nil
+	return runs, rows.Err()
 }
 
 // --- Fever API ---
@@ -1975,22 +3016,27 @@ func (s *PostgresStore) MarkFeedArticlesRead(userID, feedID int64, before int64)
 	return err
 }
 
-func (s *PostgresStore) MarkGroupArticlesRead(userID, groupID int64, before int64) error {
+func (s *PostgresStore) MarkGroupArticlesRead(userID, groupID int64, before int64) (int, error) {
 	var beforeCond string
-	args := []any{userID, groupID}
+	args := []any{userID, userID, groupID}
 	if before > 0 {
 		beforeCond = `AND (a.published_date IS NULL OR a.published_date <= ?)`
 		args = append(args, time.Unix(before, 0))
 	}
-	_, err := s.db.Exec(fmt.Sprintf(`
+	result, err := s.db.Exec(fmt.Sprintf(`
 		INSERT INTO read_state (user_id, article_id, read, read_date)
 		SELECT ?, a.id, TRUE, NOW()
 		FROM articles a
 		JOIN article_group_members agm ON agm.article_id = a.id
-		WHERE agm.group_id = ? %s
+		LEFT JOIN read_state rs ON rs.article_id = a.id AND rs.user_id = ?
+		WHERE agm.group_id = ? AND COALESCE(rs.read, FALSE) = FALSE %s
 		ON CONFLICT(user_id, article_id) DO UPDATE SET read = TRUE, read_date = NOW()`,
 		beforeCond), args...)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
 }
 
 func (s *PostgresStore) MarkAllArticlesRead(userID int64, before int64) error {
@@ -2131,7 +3177,7 @@ func (s *PostgresStore) GetFeedGroupMemberships(userID int64) (map[int64][]int64
 func (s *PostgresStore) SearchArticlesFTS(userID int64, query string, limit, offset int) ([]Article, error) {
 	rows, err := s.db.Query(s.db.prepare(`
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		WHERE uf.user_id = ? AND a.search_vector @@ websearch_to_tsquery('english', ?)
@@ -2156,29 +3202,8 @@ func (s *PostgresStore) StoreArticleEmbedding(articleID int64, embedding []byte,
 	return err
 }
 
-// GetArticleEmbeddings returns all article embeddings for a user's subscribed feeds,
-// filtered by the specified embedding model.
-func (s *PostgresStore) GetArticleEmbeddings(userID int64, model string) ([]ArticleEmbeddingRow, error) {
-	rows, err := s.db.Query(s.db.prepare(`
-		SELECT ae.article_id, ae.embedding
-		FROM article_embeddings ae
-		JOIN articles a ON a.id = ae.article_id
-		JOIN user_feeds uf ON a.feed_id = uf.feed_id
-		WHERE uf.user_id = ? AND ae.embedding_model = ?`),
-		userID, model)
-	if err != nil {
-		return nil, fmt.Errorf("get article embeddings: %w", err)
-	}
-	defer rows.Close()
-	var result []ArticleEmbeddingRow
-	for rows.Next() {
-		var r ArticleEmbeddingRow
-		if err := rows.Scan(&r.ArticleID, &r.Embedding); err != nil {
-			return nil, err
-		}
-		result = append(result, r)
-	}
-	return result, rows.Err()
+func (s *PostgresStore) GetArticleEmbeddings(userID int64, model string, since time.Time) ([]ArticleEmbeddingRow, error) {
+	return getArticleEmbeddings(s.db, userID, model, since)
 }
 
 // GetArticlesWithoutEmbeddings returns articles that have no embedding for the
@@ -2186,7 +3211,7 @@ func (s *PostgresStore) GetArticleEmbeddings(userID int64, model string) ([]Arti
 func (s *PostgresStore) GetArticlesWithoutEmbeddings(model string, limit int) ([]Article, error) {
 	rows, err := s.db.Query(s.db.prepare(`
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		LEFT JOIN article_embeddings ae ON a.id = ae.article_id AND ae.embedding_model = ?
 		WHERE ae.article_id IS NULL
@@ -2207,7 +3232,7 @@ func (s *PostgresStore) CreateNewsletter(n *Newsletter) (int64, error) {
 		return 0, fmt.Errorf("marshal newsletter config: %w", err)
 	}
 	var id int64
-	err = s.db.QueryRow(s.db.prepare(`
+	err = s.db.queryRowWrite(s.db.prepare(`
 		INSERT INTO newsletters (user_id, name, schedule, config_json, prompt_template, email_recipient, enabled)
 		VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id`),
 		n.UserID, n.Name, n.Schedule, string(configJSON), n.PromptTemplate, n.EmailRecipient, n.Enabled).Scan(&id)
@@ -2310,7 +3335,7 @@ func (s *PostgresStore) GetDueNewsletters(schedule string) ([]Newsletter, error)
 func (s *PostgresStore) CreateNewsletterIssue(issue *NewsletterIssue) (int64, error) {
 	articleIDsJSON, _ := json.Marshal(issue.ArticleIDs) //nolint:errcheck
 	var id int64
-	err := s.db.QueryRow(s.db.prepare(`
+	err := s.db.queryRowWrite(s.db.prepare(`
 		INSERT INTO newsletter_issues (newsletter_id, headline, content_html, content_text, article_ids_json)
 		VALUES (?, ?, ?, ?, ?) RETURNING id`),
 		issue.NewsletterID, issue.Headline, issue.ContentHTML, issue.ContentText, string(articleIDsJSON)).Scan(&id)
@@ -2406,7 +3431,7 @@ func (s *PostgresStore) GetNewsletterStats(userID int64) ([]NewsletterStats, err
 func (s *PostgresStore) GetNewsletterArticles(userID int64, config *NewsletterConfig, since *time.Time, limit int) ([]Article, []float64, error) {
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date, rs.interest_score
+		       a.author, a.published_date, a.fetched_date, a.word_count, rs.interest_score
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		JOIN read_state rs ON rs.article_id = a.id AND rs.user_id = uf.user_id
@@ -2473,7 +3498,7 @@ func (s *PostgresStore) GetNewsletterArticles(userID int64, config *NewsletterCo
 		var a Article
 		var score float64
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &score); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount, &score); err != nil {
 			return nil, nil, err
 		}
 		articles = append(articles, a)
@@ -2484,13 +3509,13 @@ func (s *PostgresStore) GetNewsletterArticles(userID int64, config *NewsletterCo
 
 // --- Internal scan helpers ---
 
-// scanArticles scans a standard 10-column article result set.
+// scanArticles scans a standard 11-column article result set.
 func scanArticles(rows *sql.Rows) ([]Article, error) {
 	var articles []Article
 	for rows.Next() {
 		var a Article
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
 			return nil, fmt.Errorf("scan article: %w", err)
 		}
 		articles = append(articles, a)
@@ -2498,19 +3523,10 @@ func scanArticles(rows *sql.Rows) ([]Article, error) {
 	return articles, rows.Err()
 }
 
-// filterScoreClausePG is identical in logic to filterScoreClause but named
-// distinctly to avoid a duplicate-declaration collision with the SQLite version.
-func filterScoreClausePG(userID int64, threshold *int) (string, []interface{}) {
-	if threshold == nil {
-		return "", nil
-	}
-	sql := `AND (
-		NOT EXISTS (SELECT 1 FROM filter_rules WHERE user_id = ?)
-		OR (
-			SELECT COALESCE(SUM(fr.score), 0)
-			FROM filter_rules fr
-			WHERE fr.user_id = ?
-			  AND (fr.feed_id IS NULL OR fr.feed_id = a.feed_id)
+// filterRuleMatchPG is filterRuleMatch with a case-insensitive domain
+// suffix match, since CITEXT equality does not extend to LIKE, and strpos in
+// place of SQLite's instr for title matches.
+const filterRuleMatchPG = `(fr.feed_id IS NULL OR fr.feed_id = a.feed_id)
 			  AND (
 				(fr.axis = 'author' AND EXISTS (
 				  SELECT 1 FROM article_authors aa
@@ -2520,8 +3536,37 @@ func filterScoreClausePG(userID int64, threshold *int) (string, []interface{}) {
 				  SELECT 1 FROM article_categories ac
 				  WHERE ac.article_id = a.id AND ac.category = fr.value
 				))
-			  )
+				OR (fr.axis = 'tag' AND EXISTS (
+				  SELECT 1 FROM ai_tags t
+				  WHERE t.user_id = fr.user_id AND t.article_id = a.id AND t.tag = fr.value
+				))
+				OR (fr.axis = 'domain' AND (
+				  a.domain = fr.value OR a.domain ILIKE '%.' || fr.value
+				))
+				OR (fr.axis = 'title' AND strpos(lower(a.title), lower(fr.value::text)) > 0)
+				OR (fr.axis = 'lang' AND a.lang = fr.value)
+			  )`
+
+// filterScoreClausePG is identical in logic to filterScoreClause but uses
+// Postgres boolean literals and filterRuleMatchPG.
+func filterScoreClausePG(userID int64, threshold *int) (string, []interface{}) {
+	sql := `AND NOT EXISTS (
+		SELECT 1 FROM filter_rules fr
+		WHERE fr.user_id = ? AND fr.block = TRUE
+		  AND ` + filterRuleMatchPG + `
+	)`
+	if threshold == nil {
+		return sql, []interface{}{userID}
+	}
+	sql += `
+	AND (
+		NOT EXISTS (SELECT 1 FROM filter_rules WHERE user_id = ? AND block = FALSE)
+		OR (
+			SELECT COALESCE(SUM(fr.score), 0)
+			FROM filter_rules fr
+			WHERE fr.user_id = ? AND fr.block = FALSE
+			  AND ` + filterRuleMatchPG + `
 		) >= ?
 	)`
-	return sql, []interface{}{userID, userID, *threshold}
+	return sql, []interface{}{userID, userID, userID, *threshold}
 }
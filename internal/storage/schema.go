@@ -15,7 +15,17 @@ CREATE TABLE IF NOT EXISTS feeds (
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     consecutive_errors INTEGER NOT NULL DEFAULT 0,
     next_fetch_at DATETIME,
-    status TEXT NOT NULL DEFAULT 'active'
+    status TEXT NOT NULL DEFAULT 'active',
+    dedupe_by_url BOOLEAN NOT NULL DEFAULT 0,
+    guid_churn_polls INTEGER NOT NULL DEFAULT 0,
+    delete_after DATETIME,
+    max_articles INTEGER NOT NULL DEFAULT 0,
+    body_hash TEXT NOT NULL DEFAULT '',
+    fetch_headers TEXT NOT NULL DEFAULT '',
+    content_source TEXT NOT NULL DEFAULT 'content',
+    suggested_interval INTEGER NOT NULL DEFAULT 0,
+    generator TEXT NOT NULL DEFAULT '',
+    language TEXT NOT NULL DEFAULT ''
 );
 CREATE TABLE IF NOT EXISTS articles (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -30,20 +40,28 @@ CREATE TABLE IF NOT EXISTS articles (
     fetched_date DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     linked_url TEXT NOT NULL DEFAULT '',
     linked_content TEXT NOT NULL DEFAULT '',
+    domain TEXT NOT NULL DEFAULT '',
     full_text_fetched BOOLEAN NOT NULL DEFAULT 0,
     images_cached BOOLEAN NOT NULL DEFAULT 0,
+    lang TEXT NOT NULL DEFAULT 'unknown',
+    word_count INTEGER NOT NULL DEFAULT 0,
+    slug TEXT NOT NULL DEFAULT '',
+    updated_date DATETIME,
     FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE,
     UNIQUE(feed_id, guid)
 );
 
 CREATE INDEX IF NOT EXISTS idx_articles_published ON articles(published_date DESC);
+CREATE INDEX IF NOT EXISTS idx_articles_feed_url ON articles(feed_id, url);
 
 CREATE TABLE IF NOT EXISTS users (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     name TEXT NOT NULL UNIQUE COLLATE NOCASE,
     oidc_sub TEXT UNIQUE,
     email TEXT,
-    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at DATETIME,
+    prev_seen_at DATETIME
 );
 
 CREATE TABLE IF NOT EXISTS read_state (
@@ -93,6 +111,16 @@ CREATE TABLE IF NOT EXISTS article_summaries (
 
 CREATE INDEX IF NOT EXISTS idx_article_summaries_article ON article_summaries(article_id);
 
+CREATE TABLE IF NOT EXISTS article_notes (
+    user_id INTEGER NOT NULL,
+    article_id INTEGER NOT NULL,
+    note TEXT NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, article_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS article_groups (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     user_id INTEGER NOT NULL DEFAULT 1,
@@ -156,13 +184,22 @@ CREATE TABLE IF NOT EXISTS article_categories (
 );
 CREATE INDEX IF NOT EXISTS idx_article_categories_category ON article_categories(category);
 
+CREATE TABLE IF NOT EXISTS ai_tags (
+    user_id INTEGER NOT NULL,
+    article_id INTEGER NOT NULL,
+    tag TEXT NOT NULL COLLATE NOCASE,
+    PRIMARY KEY (user_id, article_id, tag),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS filter_rules (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     user_id INTEGER NOT NULL,
     feed_id INTEGER,
-    axis TEXT NOT NULL CHECK(axis IN ('author', 'category', 'tag')),
+    axis TEXT NOT NULL CHECK(axis IN ('author', 'category', 'tag', 'domain', 'title', 'lang')),
     value TEXT NOT NULL COLLATE NOCASE,
     score INTEGER NOT NULL,
+    block BOOLEAN NOT NULL DEFAULT 0,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
 );
@@ -237,4 +274,42 @@ CREATE TABLE IF NOT EXISTS newsletter_issues (
 );
 CREATE INDEX IF NOT EXISTS idx_newsletter_issues_newsletter ON newsletter_issues(newsletter_id);
 CREATE INDEX IF NOT EXISTS idx_newsletter_issues_generated ON newsletter_issues(generated_at DESC);
+
+CREATE TABLE IF NOT EXISTS poll_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at DATETIME NOT NULL,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    feeds_total INTEGER NOT NULL DEFAULT 0,
+    feeds_downloaded INTEGER NOT NULL DEFAULT 0,
+    new_articles INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    errored INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_poll_runs_started ON poll_runs(started_at DESC);
+
+CREATE TABLE IF NOT EXISTS notification_queue (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    article_id INTEGER NOT NULL,
+    interest_score REAL NOT NULL DEFAULT 0,
+    queued_at DATETIME NOT NULL,
+    UNIQUE(user_id, article_id),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS trimmed_articles (
+    feed_id INTEGER NOT NULL,
+    guid TEXT NOT NULL,
+    trimmed_at DATETIME NOT NULL,
+    PRIMARY KEY (feed_id, guid),
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS feed_unsubscribes (
+    user_id INTEGER NOT NULL,
+    feed_id INTEGER NOT NULL,
+    undo_before DATETIME NOT NULL,
+    PRIMARY KEY (user_id, feed_id),
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
 `
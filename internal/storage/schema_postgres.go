@@ -19,7 +19,17 @@ CREATE TABLE IF NOT EXISTS feeds (
     created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     consecutive_errors BIGINT NOT NULL DEFAULT 0,
     next_fetch_at      TIMESTAMPTZ,
-    status             TEXT NOT NULL DEFAULT 'active'
+    status             TEXT NOT NULL DEFAULT 'active',
+    dedupe_by_url      BOOLEAN NOT NULL DEFAULT FALSE,
+    guid_churn_polls   BIGINT NOT NULL DEFAULT 0,
+    delete_after       TIMESTAMPTZ,
+    max_articles       BIGINT NOT NULL DEFAULT 0,
+    body_hash          TEXT NOT NULL DEFAULT '',
+    fetch_headers      TEXT NOT NULL DEFAULT '',
+    content_source     TEXT NOT NULL DEFAULT 'content',
+    suggested_interval BIGINT NOT NULL DEFAULT 0,
+    generator          TEXT NOT NULL DEFAULT '',
+    language           TEXT NOT NULL DEFAULT ''
 );
 
 CREATE TABLE IF NOT EXISTS articles (
@@ -35,20 +45,28 @@ CREATE TABLE IF NOT EXISTS articles (
     fetched_date      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     linked_url        TEXT NOT NULL DEFAULT '',
     linked_content    TEXT NOT NULL DEFAULT '',
+    domain            TEXT NOT NULL DEFAULT '',
     full_text_fetched BOOLEAN NOT NULL DEFAULT FALSE,
     images_cached     BOOLEAN NOT NULL DEFAULT FALSE,
+    lang              TEXT NOT NULL DEFAULT 'unknown',
+    word_count        INTEGER NOT NULL DEFAULT 0,
+    slug              TEXT NOT NULL DEFAULT '',
+    updated_date      TIMESTAMPTZ,
     FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE,
     UNIQUE(feed_id, guid)
 );
 
 CREATE INDEX IF NOT EXISTS idx_articles_published ON articles(published_date DESC);
+CREATE INDEX IF NOT EXISTS idx_articles_feed_url ON articles(feed_id, url);
 
 CREATE TABLE IF NOT EXISTS users (
     id         BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     name       CITEXT NOT NULL UNIQUE,
     oidc_sub   TEXT UNIQUE,
-    email      TEXT,
-    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    email        TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ,
+    prev_seen_at TIMESTAMPTZ
 );
 
 CREATE TABLE IF NOT EXISTS read_state (
@@ -98,6 +116,16 @@ CREATE TABLE IF NOT EXISTS article_summaries (
 
 CREATE INDEX IF NOT EXISTS idx_article_summaries_article ON article_summaries(article_id);
 
+CREATE TABLE IF NOT EXISTS article_notes (
+    user_id    BIGINT NOT NULL,
+    article_id BIGINT NOT NULL,
+    note       TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, article_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS article_groups (
     id           BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     user_id      BIGINT NOT NULL DEFAULT 1,
@@ -159,15 +187,24 @@ CREATE TABLE IF NOT EXISTS article_categories (
     PRIMARY KEY (article_id, category),
     FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
 );
+
+CREATE TABLE IF NOT EXISTS ai_tags (
+    user_id    BIGINT NOT NULL,
+    article_id BIGINT NOT NULL,
+    tag        CITEXT NOT NULL,
+    PRIMARY KEY (user_id, article_id, tag),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
 CREATE INDEX IF NOT EXISTS idx_article_categories_category ON article_categories(category);
 
 CREATE TABLE IF NOT EXISTS filter_rules (
     id         BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
     user_id    BIGINT NOT NULL,
     feed_id    BIGINT,
-    axis       TEXT NOT NULL CHECK(axis IN ('author', 'category', 'tag')),
+    axis       TEXT NOT NULL CHECK(axis IN ('author', 'category', 'tag', 'domain', 'title', 'lang')),
     value      CITEXT NOT NULL,
     score      BIGINT NOT NULL,
+    block      BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
 );
@@ -242,4 +279,42 @@ CREATE TABLE IF NOT EXISTS newsletter_issues (
 );
 CREATE INDEX IF NOT EXISTS idx_newsletter_issues_newsletter ON newsletter_issues(newsletter_id);
 CREATE INDEX IF NOT EXISTS idx_newsletter_issues_generated ON newsletter_issues(generated_at DESC);
+
+CREATE TABLE IF NOT EXISTS poll_runs (
+    id               BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    started_at       TIMESTAMPTZ NOT NULL,
+    duration_ms      BIGINT NOT NULL DEFAULT 0,
+    feeds_total      INTEGER NOT NULL DEFAULT 0,
+    feeds_downloaded INTEGER NOT NULL DEFAULT 0,
+    new_articles     INTEGER NOT NULL DEFAULT 0,
+    processed        INTEGER NOT NULL DEFAULT 0,
+    errored          INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_poll_runs_started ON poll_runs(started_at DESC);
+
+CREATE TABLE IF NOT EXISTS notification_queue (
+    id             BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id        BIGINT NOT NULL,
+    article_id     BIGINT NOT NULL,
+    interest_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    queued_at      TIMESTAMPTZ NOT NULL,
+    UNIQUE(user_id, article_id),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS trimmed_articles (
+    feed_id    BIGINT NOT NULL,
+    guid       TEXT NOT NULL,
+    trimmed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (feed_id, guid),
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS feed_unsubscribes (
+    user_id     BIGINT NOT NULL,
+    feed_id     BIGINT NOT NULL,
+    undo_before TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, feed_id),
+    FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+);
 `
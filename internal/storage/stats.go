@@ -1,6 +1,9 @@
 package storage
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // FeedStat holds per-feed statistics for the admin stats page.
 type FeedStat struct {
@@ -17,21 +20,37 @@ type DBStats struct {
 	TotalArticles int
 	TotalFeeds    int
 	TotalUsers    int
-	Feeds         []FeedStat
+	TotalUnread   int // unread articles summed over every user's subscriptions
+	FeedsErroring int // feeds whose last fetch failed
+
+	// Totals over the recorded poll history (poll_runs), so any process
+	// sharing the database can report them.
+	PollRuns    int
+	PollSeconds float64
+	PollErrors  int // feeds that failed, summed over every recorded poll
+
+	Feeds []FeedStat
 }
 
 // GetDBStats returns article counts per feed and overall DB totals.
 func (s *SQLiteStore) GetDBStats() (DBStats, error) {
+	return getDBStats(s.db)
+}
+
+// getDBStats implements GetDBStats for both stores. Subscribers are counted
+// separately so the article join isn't multiplied by user_feeds.
+func getDBStats(db *tracedDB) (DBStats, error) {
 	var stats DBStats
 
-	rows, err := s.db.Query(`
-		SELECT
-			f.id, f.title, f.url, f.status,
-			COUNT(DISTINCT a.id)      AS articles,
-			COUNT(DISTINCT uf.user_id) AS subscribers
+	subscribers, err := getFeedSubscriberCounts(db)
+	if err != nil {
+		return stats, err
+	}
+
+	rows, err := db.Query(`
+		SELECT f.id, f.title, f.url, f.status, COUNT(a.id) AS articles
 		FROM feeds f
-		LEFT JOIN articles   a  ON a.feed_id  = f.id
-		LEFT JOIN user_feeds uf ON uf.feed_id = f.id
+		LEFT JOIN articles a ON a.feed_id = f.id
 		GROUP BY f.id
 		ORDER BY articles DESC
 	`)
@@ -42,17 +61,168 @@ func (s *SQLiteStore) GetDBStats() (DBStats, error) {
 
 	for rows.Next() {
 		var fs FeedStat
-		if err := rows.Scan(&fs.ID, &fs.Title, &fs.URL, &fs.Status, &fs.Articles, &fs.Subscribers); err != nil {
+		if err := rows.Scan(&fs.ID, &fs.Title, &fs.URL, &fs.Status, &fs.Articles); err != nil {
 			return stats, fmt.Errorf("failed to scan feed stat: %w", err)
 		}
+		fs.Subscribers = subscribers[fs.ID]
 		stats.Feeds = append(stats.Feeds, fs)
 		stats.TotalArticles += fs.Articles
 		stats.TotalFeeds++
 	}
+	if err := rows.Err(); err != nil {
+		return stats, err
+	}
 
-	if err := s.db.QueryRow("SELECT COUNT(*) FROM users").Scan(&stats.TotalUsers); err != nil {
+	if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&stats.TotalUsers); err != nil {
 		return stats, fmt.Errorf("failed to count users: %w", err)
 	}
 
+	err = db.QueryRow(`
+		SELECT COUNT(*)
+		FROM user_feeds uf
+		JOIN articles a ON a.feed_id = uf.feed_id
+		LEFT JOIN read_state rs ON rs.article_id = a.id AND rs.user_id = uf.user_id
+		WHERE rs.read IS NULL OR NOT rs.read
+	`).Scan(&stats.TotalUnread)
+	if err != nil {
+		return stats, fmt.Errorf("failed to count unread articles: %w", err)
+	}
+
+	if err := db.QueryRow("SELECT COUNT(*) FROM feeds WHERE consecutive_errors > 0").Scan(&stats.FeedsErroring); err != nil {
+		return stats, fmt.Errorf("failed to count erroring feeds: %w", err)
+	}
+
+	var pollMS int64
+	err = db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(duration_ms), 0), COALESCE(SUM(errored), 0)
+		FROM poll_runs
+	`).Scan(&stats.PollRuns, &pollMS, &stats.PollErrors)
+	if err != nil {
+		return stats, fmt.Errorf("failed to total poll runs: %w", err)
+	}
+	stats.PollSeconds = time.Duration(pollMS * int64(time.Millisecond)).Seconds()
+
 	return stats, nil
 }
+
+// getFeedSubscriberCounts returns the number of users subscribed to each
+// feed. Feeds nobody subscribes to are absent from the map.
+func getFeedSubscriberCounts(db *tracedDB) (map[int64]int, error) {
+	rows, err := db.Query("SELECT feed_id, COUNT(*) FROM user_feeds GROUP BY feed_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count feed subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var feedID int64
+		var n int
+		if err := rows.Scan(&feedID, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan feed subscriber count: %w", err)
+		}
+		counts[feedID] = n
+	}
+	return counts, rows.Err()
+}
+
+// ReadingStats summarizes the articles a user marked read since a point in
+// time. Starred and HighInterest count within those read articles.
+type ReadingStats struct {
+	Read         int
+	Starred      int
+	HighInterest int        // interest score 8 or higher, the IntHigh bucket
+	Daily        []DayCount // days with reads, oldest first
+}
+
+// DayCount is the number of articles read on one UTC day.
+type DayCount struct {
+	Day   string // YYYY-MM-DD
+	Count int
+}
+
+// GetReadingStats aggregates read_state for articles the user read at or
+// after since.
+func (s *SQLiteStore) GetReadingStats(userID int64, since time.Time) (ReadingStats, error) {
+	var stats ReadingStats
+	err := s.db.QueryRow(`
+		SELECT COUNT(*),
+			COALESCE(SUM(CASE WHEN starred = 1 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN interest_score >= 8.0 THEN 1 ELSE 0 END), 0)
+		FROM read_state
+		WHERE user_id = ? AND read = 1 AND julianday(read_date) >= julianday(?)`,
+		userID, since.UTC(),
+	).Scan(&stats.Read, &stats.Starred, &stats.HighInterest)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get reading stats: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+		SELECT date(read_date), COUNT(*)
+		FROM read_state
+		WHERE user_id = ? AND read = 1 AND julianday(read_date) >= julianday(?)
+		GROUP BY date(read_date)
+		ORDER BY date(read_date)`,
+		userID, since.UTC())
+	if err != nil {
+		return stats, fmt.Errorf("failed to get daily reads: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d DayCount
+		if err := rows.Scan(&d.Day, &d.Count); err != nil {
+			return stats, fmt.Errorf("failed to scan daily reads: %w", err)
+		}
+		stats.Daily = append(stats.Daily, d)
+	}
+	return stats, rows.Err()
+}
+
+// PollRun records the outcome of one poller fetch-and-process cycle.
+type PollRun struct {
+	ID              int64
+	StartedAt       time.Time
+	DurationMS      int64
+	FeedsTotal      int
+	FeedsDownloaded int
+	NewArticles     int
+	Processed       int
+	Errored         int
+}
+
+// RecordPollRun appends a completed poll cycle to the poll history.
+func (s *SQLiteStore) RecordPollRun(run PollRun) error {
+	_, err := s.db.Exec(`
+		INSERT INTO poll_runs (started_at, duration_ms, feeds_total, feeds_downloaded, new_articles, processed, errored)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		run.StartedAt.UTC(), run.DurationMS, run.FeedsTotal, run.FeedsDownloaded,
+		run.NewArticles, run.Processed, run.Errored)
+	if err != nil {
+		return fmt.Errorf("failed to record poll run: %w", err)
+	}
+	return nil
+}
+
+// GetPollRuns returns up to limit poll runs, most recent first.
+func (s *SQLiteStore) GetPollRuns(limit int) ([]PollRun, error) {
+	rows, err := s.db.Query(`
+		SELECT id, started_at, duration_ms, feeds_total, feeds_downloaded, new_articles, processed, errored
+		FROM poll_runs
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query poll runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []PollRun
+	for rows.Next() {
+		var r PollRun
+		if err := rows.Scan(&r.ID, &r.StartedAt, &r.DurationMS, &r.FeedsTotal, &r.FeedsDownloaded,
+			&r.NewArticles, &r.Processed, &r.Errored); err != nil {
+			return nil, fmt.Errorf("failed to scan poll run: %w", err)
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
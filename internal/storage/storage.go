@@ -1,12 +1,18 @@
 package storage
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/textproto"
+	"net/url"
+	"os"
 	"sort"
 	"strings"
 	"time"
+	"unicode"
 
 	_ "modernc.org/sqlite"
 )
@@ -25,10 +31,13 @@ type Feed struct {
 	Title             string
 	Description       string
 	SiteURL           string // blog homepage URL, populated from feed metadata
+	Generator         string // software that produced the feed, from feed metadata
+	Language          string // feed-declared language, e.g. "en-us"
 	LastFetched       *time.Time
 	LastError         *string
 	ETag              string
 	LastModified      string
+	BodyHash          string // SHA-256 of the last stored feed body; see UpdateFeedCacheHeaders
 	Enabled           bool
 	CreatedAt         time.Time
 	ConsecutiveErrors int
@@ -46,9 +55,13 @@ type Article struct {
 	Summary       string
 	Author        string
 	PublishedDate *time.Time
+	UpdatedDate   *time.Time // when the feed last said the item changed; see UpsertArticle
 	FetchedDate   time.Time
 	LinkedURL     string // outbound link extracted from a link-blog post
 	LinkedContent string // readability content fetched from LinkedURL
+	Lang          string // detected ISO 639-1 language code, or "unknown"
+	WordCount     int    // words in the article text, computed at store time
+	Slug          string // stable permalink key; see ArticleSlug
 }
 
 type ArticleSummary struct {
@@ -58,6 +71,14 @@ type ArticleSummary struct {
 	GeneratedAt time.Time
 }
 
+// ArticleNote is a user's private note on an article.
+type ArticleNote struct {
+	UserID    int64
+	ArticleID int64
+	Note      string
+	UpdatedAt time.Time
+}
+
 type ReadState struct {
 	ArticleID     int64
 	Read          bool
@@ -105,6 +126,20 @@ type GroupSummary struct {
 	GeneratedAt      time.Time
 }
 
+// Stale reports whether a group now holding articleCount articles needs its
+// summary regenerated: always when it has none (g is nil), otherwise when the
+// count has moved by more than minChange since the summary was written.
+func (g *GroupSummary) Stale(articleCount, minChange int) bool {
+	if g == nil {
+		return true
+	}
+	diff := articleCount - g.ArticleCount
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff > minChange
+}
+
 // NewsletterConfig holds the filtering criteria for a newsletter definition.
 type NewsletterConfig struct {
 	MinInterestScore  float64  `json:"min_interest_score"`
@@ -171,33 +206,105 @@ type FilterRule struct {
 	ID        int64
 	UserID    int64
 	FeedID    *int64 // nil = global rule
-	Axis      string // "author", "category", "tag"
+	Axis      string // "author", "category", "tag", "domain", "title"
 	Value     string
 	Score     int
+	Block     bool // hide matching articles regardless of score
 	CreatedAt time.Time
 }
 
-// NewSQLiteStore creates a new database connection and initializes the schema.
+// FilterMatch is a filter rule that applies to a particular article.
+type FilterMatch struct {
+	RuleID int64
+	FeedID *int64
+	Axis   string
+	Value  string
+	Score  int
+	Block  bool
+}
+
+// SQLiteOptions tunes the per-connection PRAGMAs applied when opening a
+// SQLite database. Zero values select the defaults.
+type SQLiteOptions struct {
+	// BusyTimeout is how long a connection waits on a locked database before
+	// failing with SQLITE_BUSY. Default 15s.
+	BusyTimeout time.Duration
+	// JournalMode is the SQLite journal mode (WAL, DELETE, TRUNCATE, PERSIST,
+	// MEMORY or OFF). Default WAL, which lets the poller write while
+	// herald-web reads.
+	JournalMode string
+	// ReadOnly opens the database with mode=ro, skips schema setup and
+	// migrations, and makes every write fail with ErrReadOnly. The database
+	// must already exist.
+	ReadOnly bool
+	// CreateDefaultUser calls EnsureDefaultUser after opening, so tools that
+	// act as user 1 work on a fresh database. Ignored with ReadOnly.
+	CreateDefaultUser bool
+}
+
+// defaultBusyTimeout is 15s because the daemon writes aggressively during
+// feed fetches and image caching; 5s was too short for multi-process WAL
+// contention.
+const defaultBusyTimeout = 15 * time.Second
+
+// validJournalModes lists the journal modes SQLite accepts.
+var validJournalModes = map[string]bool{
+	"WAL": true, "DELETE": true, "TRUNCATE": true, "PERSIST": true, "MEMORY": true, "OFF": true,
+}
+
+// dsn builds the modernc.org/sqlite DSN for dbPath with the options applied.
+func (o SQLiteOptions) dsn(dbPath string) (string, error) {
+	timeout := o.BusyTimeout
+	if timeout <= 0 {
+		timeout = defaultBusyTimeout
+	}
+	mode := strings.ToUpper(strings.TrimSpace(o.JournalMode))
+	if mode == "" {
+		mode = "WAL"
+	}
+	if !validJournalModes[mode] {
+		return "", fmt.Errorf("invalid SQLite journal mode %q", o.JournalMode)
+	}
+	// Embedding the PRAGMAs in the DSN via _pragma applies them to every
+	// connection in the pool before it runs its first query, avoiding
+	// write-lock hangs and broken FK cascades.
+	if o.ReadOnly {
+		// Changing the journal mode is a write, so leave it as the writer
+		// set it. mode=ro is only honoured for file: URIs.
+		return fmt.Sprintf("file:%s?mode=ro&_time_format=sqlite&_pragma=busy_timeout(%d)&_pragma=foreign_keys(on)",
+			dbPath, timeout.Milliseconds()), nil
+	}
+	return fmt.Sprintf("%s?_time_format=sqlite&_pragma=busy_timeout(%d)&_pragma=journal_mode(%s)&_pragma=foreign_keys(on)",
+		dbPath, timeout.Milliseconds(), mode), nil
+}
+
+// NewSQLiteStore creates a new database connection and initializes the schema
+// using the default SQLiteOptions.
 func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
-	// busy_timeout and foreign_keys are connection-level PRAGMAs; embedding
-	// them in the DSN via _pragma ensures every connection in the pool gets
-	// them automatically, avoiding write-lock hangs and broken FK cascades.
-	// 15s timeout: the daemon writes aggressively during feed fetches and
-	// image caching; 5s was too short for multi-process WAL contention.
-	dsn := dbPath + "?_time_format=sqlite" +
-		"&_pragma=busy_timeout(15000)" +
-		"&_pragma=foreign_keys(on)"
+	return NewSQLiteStoreWithOptions(dbPath, SQLiteOptions{})
+}
+
+// NewSQLiteStoreWithOptions creates a new database connection with the given
+// PRAGMA options and initializes the schema.
+func NewSQLiteStoreWithOptions(dbPath string, opts SQLiteOptions) (*SQLiteStore, error) {
+	dsn, err := opts.dsn(dbPath)
+	if err != nil {
+		return nil, err
+	}
 	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Enable WAL mode — a persistent database-level setting, so one Exec
-	// at open time is sufficient. WAL allows concurrent reads alongside
-	// a single writer.
-	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+	// Open is lazy; ping so a bad path or PRAGMA fails here, before the
+	// schema is touched.
+	if err := db.Ping(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to set WAL mode: %w", err)
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if opts.ReadOnly {
+		return &SQLiteStore{db: &tracedDB{DB: db, readOnly: true}}, nil
 	}
 
 	// Initialize schema
@@ -251,6 +358,8 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 		"ALTER TABLE user_feeds ADD COLUMN user_title TEXT",
 		// Security check reasoning for audit/debugging.
 		"ALTER TABLE read_state ADD COLUMN security_reason TEXT",
+		// Audit flag for articles a user released past a failed security check.
+		"ALTER TABLE read_state ADD COLUMN security_overridden BOOLEAN NOT NULL DEFAULT 0",
 		// Article groups as virtual feeds: display name and mute support.
 		"ALTER TABLE article_groups ADD COLUMN display_name TEXT",
 		"ALTER TABLE article_groups ADD COLUMN muted BOOLEAN NOT NULL DEFAULT 0",
@@ -262,6 +371,55 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 		"ALTER TABLE article_groups ADD COLUMN embedding_model TEXT NOT NULL DEFAULT ''",
 		// Retry counter for AI pipeline failures (prevents infinite retry loops).
 		"ALTER TABLE read_state ADD COLUMN ai_retries INTEGER NOT NULL DEFAULT 0",
+		// Block rules hide matching articles outright instead of scoring them.
+		"ALTER TABLE filter_rules ADD COLUMN block BOOLEAN NOT NULL DEFAULT 0",
+		// URL-based dedupe for feeds that regenerate GUIDs on every publish.
+		"ALTER TABLE feeds ADD COLUMN dedupe_by_url BOOLEAN NOT NULL DEFAULT 0",
+		"ALTER TABLE feeds ADD COLUMN guid_churn_polls INTEGER NOT NULL DEFAULT 0",
+		// Detected article language (ISO 639-1), "unknown" when undetected.
+		"ALTER TABLE articles ADD COLUMN lang TEXT NOT NULL DEFAULT 'unknown'",
+		// Visit tracking for "new since last visit".
+		"ALTER TABLE users ADD COLUMN last_seen_at DATETIME",
+		"ALTER TABLE users ADD COLUMN prev_seen_at DATETIME",
+		// Grace period before an orphaned feed is deleted, so unsubscribing can be undone.
+		"ALTER TABLE feeds ADD COLUMN delete_after DATETIME",
+		// Muted subscriptions keep fetching but stay out of the unread stream.
+		"ALTER TABLE user_feeds ADD COLUMN muted BOOLEAN NOT NULL DEFAULT 0",
+		// Word count of the article text, computed when the article is stored.
+		"ALTER TABLE articles ADD COLUMN word_count INTEGER NOT NULL DEFAULT 0",
+		// One-line curation justification shown as "Surfaced because: ...".
+		"ALTER TABLE read_state ADD COLUMN interest_reason TEXT",
+		// Per-user folder path ("Tech/Go"), carried in from OPML outlines.
+		"ALTER TABLE user_feeds ADD COLUMN folder TEXT NOT NULL DEFAULT ''",
+		// When a notify_when=present article was surfaced, so it isn't repeated.
+		"ALTER TABLE read_state ADD COLUMN presented_at DATETIME",
+		// Equivalence key for feed URLs, so http/https and www variants share a row.
+		"ALTER TABLE feeds ADD COLUMN normalized_url TEXT NOT NULL DEFAULT ''",
+		"CREATE INDEX IF NOT EXISTS idx_feeds_normalized_url ON feeds(normalized_url)",
+		// Per-feed cap on stored articles; 0 means unlimited.
+		"ALTER TABLE feeds ADD COLUMN max_articles INTEGER NOT NULL DEFAULT 0",
+		// Stable permalink key, derived from the feed URL and GUID.
+		"ALTER TABLE articles ADD COLUMN slug TEXT NOT NULL DEFAULT ''",
+		"CREATE INDEX IF NOT EXISTS idx_articles_slug ON articles(slug)",
+		// Hash of the last feed body, so an unchanged body skips storage.
+		"ALTER TABLE feeds ADD COLUMN body_hash TEXT NOT NULL DEFAULT ''",
+		// Extra request headers (JSON object) for feeds that need auth.
+		"ALTER TABLE feeds ADD COLUMN fetch_headers TEXT NOT NULL DEFAULT ''",
+		// Which article field (content, summary or longest) a feed's text comes from.
+		"ALTER TABLE feeds ADD COLUMN content_source TEXT NOT NULL DEFAULT 'content'",
+		// Publisher-advertised polling interval in minutes (<ttl>, sy:updatePeriod); 0 = none.
+		"ALTER TABLE feeds ADD COLUMN suggested_interval INTEGER NOT NULL DEFAULT 0",
+		// Feed-declared generator and language, refreshed on every fetch.
+		"ALTER TABLE feeds ADD COLUMN generator TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE feeds ADD COLUMN language TEXT NOT NULL DEFAULT ''",
+		// Feed-reported modification time of an item, for detecting edits.
+		"ALTER TABLE articles ADD COLUMN updated_date DATETIME",
+		// How many times the user has opened an article.
+		"ALTER TABLE read_state ADD COLUMN open_count INTEGER NOT NULL DEFAULT 0",
+		// Regexps (JSON array) removed from a feed's text before AI processing.
+		"ALTER TABLE feeds ADD COLUMN strip_patterns TEXT NOT NULL DEFAULT ''",
+		// Last failed favicon lookup, so retries back off instead of running every poll.
+		"ALTER TABLE feeds ADD COLUMN favicon_checked_at DATETIME",
 		// FTS5 full-text search index (external-content, synced via triggers).
 		`CREATE VIRTUAL TABLE IF NOT EXISTS articles_fts USING fts5(
 			title, content, summary, linked_content,
@@ -317,7 +475,336 @@ func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
 		}
 	}
 
-	return &SQLiteStore{db: &tracedDB{DB: db}}, nil
+	// Publishing host for domain filter rules. Backfill only when the column
+	// is new; AddArticle populates it from then on.
+	if _, err := db.Exec("ALTER TABLE articles ADD COLUMN domain TEXT NOT NULL DEFAULT ''"); err == nil {
+		if err := backfillArticleDomains(db); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to backfill article domains: %w", err)
+		}
+	}
+
+	// SQLite cannot alter a CHECK constraint, so widening the filter_rules
+	// axis list means rebuilding the table.
+	if needsFilterRulesAxisMigration(db) {
+		migrationSQL := `
+			CREATE TABLE filter_rules_new (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id INTEGER NOT NULL,
+				feed_id INTEGER,
+				axis TEXT NOT NULL CHECK(axis IN ('author', 'category', 'tag', 'domain', 'title', 'lang')),
+				value TEXT NOT NULL COLLATE NOCASE,
+				score INTEGER NOT NULL,
+				block BOOLEAN NOT NULL DEFAULT 0,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (feed_id) REFERENCES feeds(id) ON DELETE CASCADE
+			);
+			INSERT INTO filter_rules_new (id, user_id, feed_id, axis, value, score, block, created_at)
+				SELECT id, user_id, feed_id, axis, value, score, block, created_at FROM filter_rules;
+			DROP TABLE filter_rules;
+			ALTER TABLE filter_rules_new RENAME TO filter_rules;
+			CREATE INDEX IF NOT EXISTS idx_filter_rules_user ON filter_rules(user_id);
+			CREATE INDEX IF NOT EXISTS idx_filter_rules_lookup ON filter_rules(user_id, axis, value);
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_filter_rules_unique
+				ON filter_rules(user_id, COALESCE(feed_id, -1), axis, value);
+		`
+		if _, err := db.Exec(migrationSQL); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate filter_rules: %w", err)
+		}
+	}
+
+	store := &SQLiteStore{db: &tracedDB{DB: db}}
+	if err := backfillFeedNormalizedURLs(store.db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill normalized feed URLs: %w", err)
+	}
+	if err := backfillArticleSlugs(store.db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill article slugs: %w", err)
+	}
+	if err := backfillArticleURLs(store.db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to backfill article URLs: %w", err)
+	}
+	if opts.CreateDefaultUser {
+		if _, err := store.EnsureDefaultUser(); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// needsFilterRulesAxisMigration reports whether the filter_rules CHECK
+// constraint predates the newest axis (lang). Bump the probe whenever an
+// axis is added.
+func needsFilterRulesAxisMigration(db *sql.DB) bool {
+	var ddl string
+	if err := db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'filter_rules'").Scan(&ddl); err != nil {
+		return false
+	}
+	return !strings.Contains(ddl, "'lang'")
+}
+
+// backfillArticleDomains fills articles.domain from each article's URL.
+func backfillArticleDomains(db *sql.DB) error {
+	rows, err := db.Query("SELECT id, url FROM articles WHERE domain = ''")
+	if err != nil {
+		return err
+	}
+	domains := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var rawURL string
+		if err := rows.Scan(&id, &rawURL); err != nil {
+			rows.Close()
+			return err
+		}
+		if d := articleDomain(rawURL); d != "" {
+			domains[id] = d
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for id, d := range domains {
+		if _, err := db.Exec("UPDATE articles SET domain = ? WHERE id = ?", d, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// articleDomain returns the lower-cased host of rawURL without any port, or
+// "" if it cannot be parsed.
+func articleDomain(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// NormalizeFeedURL returns the key under which equivalent feed URLs match:
+// the scheme is dropped so http and https agree, the host is lower-cased with
+// any "www." prefix and default port removed, and trailing slashes and the
+// fragment are stripped. Unparseable URLs are returned trimmed.
+func NormalizeFeedURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	key := host + strings.TrimRight(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+// NormalizeArticleURL trims whitespace and lowercases the scheme and host so
+// cosmetic differences don't defeat URL-based dedupe. Unlike NormalizeFeedURL
+// the result is still a usable link, and is what articles.url stores.
+// Unparseable URLs are returned trimmed.
+func NormalizeArticleURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	return u.String()
+}
+
+// backfillArticleURLs rewrites articles.url under NormalizeArticleURL for
+// rows stored before the fetcher normalized links, so FindArticleByURL
+// matches them. Only URLs with whitespace or upper-case letters can change,
+// and only the ones that do are rewritten.
+func backfillArticleURLs(db *tracedDB) error {
+	rows, err := db.Query("SELECT id, url FROM articles WHERE url <> TRIM(url) OR url <> LOWER(url)")
+	if err != nil {
+		return err
+	}
+	urls := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var rawURL string
+		if err := rows.Scan(&id, &rawURL); err != nil {
+			rows.Close()
+			return err
+		}
+		if norm := NormalizeArticleURL(rawURL); norm != rawURL {
+			urls[id] = norm
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for id, norm := range urls {
+		if _, err := db.Exec("UPDATE articles SET url = ? WHERE id = ?", norm, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillFeedNormalizedURLs fills feeds.normalized_url for rows stored
+// before the column existed. AddFeed populates it from then on.
+func backfillFeedNormalizedURLs(db *tracedDB) error {
+	rows, err := db.Query("SELECT id, url FROM feeds WHERE normalized_url = ''")
+	if err != nil {
+		return err
+	}
+	keys := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var rawURL string
+		if err := rows.Scan(&id, &rawURL); err != nil {
+			rows.Close()
+			return err
+		}
+		keys[id] = NormalizeFeedURL(rawURL)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for id, key := range keys {
+		if _, err := db.Exec("UPDATE feeds SET normalized_url = ? WHERE id = ?", key, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getArticleBySlug resolves slug to an article ID and loads it through st.
+// Slugs are 64-bit hashes, so a collision is possible but vanishingly rare;
+// the oldest article wins.
+func getArticleBySlug(db *tracedDB, st Store, slug string) (*Article, error) {
+	if slug == "" {
+		return nil, fmt.Errorf("get article by slug: %w", sql.ErrNoRows)
+	}
+	var id int64
+	if err := db.QueryRow("SELECT id FROM articles WHERE slug = ? ORDER BY id LIMIT 1", slug).Scan(&id); err != nil {
+		return nil, fmt.Errorf("get article by slug %q: %w", slug, err)
+	}
+	return st.GetArticle(id)
+}
+
+// backfillArticleSlugs fills articles.slug for rows stored before the column
+// existed. AddArticle populates it from then on.
+func backfillArticleSlugs(db *tracedDB) error {
+	rows, err := db.Query(
+		`SELECT a.id, f.url, a.guid FROM articles a
+		 JOIN feeds f ON f.id = a.feed_id
+		 WHERE a.slug = ''`)
+	if err != nil {
+		return err
+	}
+	slugs := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var feedURL, guid string
+		if err := rows.Scan(&id, &feedURL, &guid); err != nil {
+			rows.Close()
+			return err
+		}
+		slugs[id] = ArticleSlug(feedURL, guid)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for id, slug := range slugs {
+		if _, err := db.Exec("UPDATE articles SET slug = ? WHERE id = ?", slug, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Feed content sources, choosing which article field supplies its text.
+const (
+	ContentSourceContent = "content" // the feed's content, falling back to its summary
+	ContentSourceSummary = "summary" // the feed's summary, falling back to its content
+	ContentSourceLongest = "longest" // whichever of the two has more text
+)
+
+// ValidContentSource reports whether source is a known content source.
+func ValidContentSource(source string) bool {
+	switch source {
+	case ContentSourceContent, ContentSourceSummary, ContentSourceLongest:
+		return true
+	}
+	return false
+}
+
+// ArticleBody picks an article's text from content and summary according to
+// source. The preferred field falls back to the other one when it's blank;
+// an unknown source behaves like ContentSourceContent.
+func ArticleBody(source, content, summary string) string {
+	switch source {
+	case ContentSourceSummary:
+		if strings.TrimSpace(summary) != "" {
+			return summary
+		}
+		return content
+	case ContentSourceLongest:
+		if textLen(summary) > textLen(content) {
+			return summary
+		}
+		return content
+	}
+	if strings.TrimSpace(content) != "" {
+		return content
+	}
+	return summary
+}
+
+// textLen counts the non-space characters of s outside HTML tags, so
+// markup-heavy content doesn't outweigh a longer plain summary.
+func textLen(s string) int {
+	n, inTag := 0, false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag && !unicode.IsSpace(r):
+			n++
+		}
+	}
+	return n
+}
+
+// ArticleSlug returns the permalink key for the article with guid in the
+// feed at feedURL. It depends only on those two strings, so the same article
+// gets the same slug in a rebuilt database, unlike its numeric ID.
+func ArticleSlug(feedURL, guid string) string {
+	sum := sha256.Sum256([]byte(feedURL + "\n" + guid))
+	return hex.EncodeToString(sum[:8])
+}
+
+// articleSlug returns article.Slug, or derives it from the article's feed
+// URL and GUID when unset.
+func articleSlug(db *tracedDB, article *Article) (string, error) {
+	if article.Slug != "" {
+		return article.Slug, nil
+	}
+	var feedURL string
+	if err := db.QueryRow("SELECT url FROM feeds WHERE id = ?", article.FeedID).Scan(&feedURL); err != nil {
+		return "", fmt.Errorf("failed to look up feed %d: %w", article.FeedID, err)
+	}
+	return ArticleSlug(feedURL, article.GUID), nil
 }
 
 // needsReadStateMigration checks whether the read_state table uses the old
@@ -345,18 +832,19 @@ func needsReadStateMigration(db *sql.DB) bool {
 }
 
 // scanFeeds scans a *sql.Rows result set into a []Feed slice.
-// Each row must select: id, url, title, description, site_url, last_fetched,
-// last_error, etag, last_modified, enabled, created_at, consecutive_errors,
-// next_fetch_at, status.
+// Each row must select: id, url, title, description, site_url, generator,
+// language, last_fetched, last_error, etag, last_modified, enabled, created_at, consecutive_errors,
+// next_fetch_at, status, body_hash.
 func scanFeeds(rows *sql.Rows) ([]Feed, error) {
 	var feeds []Feed
 	for rows.Next() {
 		var f Feed
 		var etag, lastMod sql.NullString
 		if err := rows.Scan(
-			&f.ID, &f.URL, &f.Title, &f.Description, &f.SiteURL, &f.LastFetched, &f.LastError,
+			&f.ID, &f.URL, &f.Title, &f.Description, &f.SiteURL, &f.Generator, &f.Language,
+			&f.LastFetched, &f.LastError,
 			&etag, &lastMod, &f.Enabled, &f.CreatedAt,
-			&f.ConsecutiveErrors, &f.NextFetchAt, &f.Status,
+			&f.ConsecutiveErrors, &f.NextFetchAt, &f.Status, &f.BodyHash,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan feed: %w", err)
 		}
@@ -367,9 +855,14 @@ func scanFeeds(rows *sql.Rows) ([]Feed, error) {
 	return feeds, rows.Err()
 }
 
-// computeFeedBaseInterval queries the last 11 article publish dates for feedID
-// and returns a fetch interval based on posting recency and frequency.
+// computeFeedBaseInterval returns the feed's suggested interval when the
+// publisher advertises one; otherwise it queries the last 11 article publish
+// dates for feedID and returns a fetch interval based on posting recency and
+// frequency.
 func (s *SQLiteStore) computeFeedBaseInterval(feedID int64) time.Duration {
+	if d := suggestedBaseInterval(s.db, feedID); d > 0 {
+		return d
+	}
 	rows, err := s.db.Query(
 		`SELECT published_date FROM articles
 		 WHERE feed_id = ? AND published_date IS NOT NULL
@@ -458,6 +951,60 @@ func (s *SQLiteStore) Close() error {
 	return s.db.Close()
 }
 
+// Ping verifies the database answers a trivial query.
+func (s *SQLiteStore) Ping() error {
+	var one int
+	if err := s.db.QueryRow("SELECT 1").Scan(&one); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	return nil
+}
+
+// Backup writes a consistent snapshot of the database to destPath using
+// VACUUM INTO, which is safe while other connections are reading or writing.
+// destPath must not already exist.
+func (s *SQLiteStore) Backup(destPath string) error {
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("backup destination %s already exists", destPath)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("check backup destination: %w", err)
+	}
+	if _, err := s.db.Exec("VACUUM INTO ?", destPath); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
+
+// IntegrityCheck runs PRAGMA integrity_check and returns the problems it
+// reports. An empty result means the database is intact.
+func (s *SQLiteStore) IntegrityCheck() ([]string, error) {
+	rows, err := s.db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return nil, fmt.Errorf("failed to scan integrity check result: %w", err)
+		}
+		if msg != "ok" {
+			problems = append(problems, msg)
+		}
+	}
+	return problems, rows.Err()
+}
+
+// Vacuum rebuilds the database file to reclaim space left by deleted rows.
+func (s *SQLiteStore) Vacuum() error {
+	if _, err := s.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
+
 // User represents a registered household member.
 type User struct {
 	ID        int64
@@ -479,6 +1026,28 @@ func (s *SQLiteStore) CreateUser(name string) (int64, error) {
 	return result.LastInsertId()
 }
 
+// DefaultUserName is the name of the user EnsureDefaultUser creates.
+const DefaultUserName = "default"
+
+// EnsureDefaultUser creates user 1, named DefaultUserName, when the database
+// has no users at all, and returns its ID. It returns 0 and leaves the users
+// table alone when any user already exists, so existing data is never
+// touched and calling it on every open is safe.
+func (s *SQLiteStore) EnsureDefaultUser() (int64, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO users (id, name) SELECT 1, ? WHERE NOT EXISTS (SELECT 1 FROM users)
+		 ON CONFLICT DO NOTHING`,
+		DefaultUserName,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("create default user: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return 0, nil
+	}
+	return 1, nil
+}
+
 // GetUserByName looks up a user by name (case-insensitive).
 func (s *SQLiteStore) GetUserByName(name string) (*User, error) {
 	var u User
@@ -551,6 +1120,29 @@ func (s *SQLiteStore) ListUsers() ([]User, error) {
 	return users, rows.Err()
 }
 
+// GetUserLastSeen returns the user's two most recent visit times. Either is
+// the zero time if not yet recorded.
+func (s *SQLiteStore) GetUserLastSeen(userID int64) (lastSeen, prevSeen time.Time, err error) {
+	var last, prev sql.NullTime
+	err = s.db.QueryRow("SELECT last_seen_at, prev_seen_at FROM users WHERE id = ?", userID).Scan(&last, &prev)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("get user last seen: %w", err)
+	}
+	return last.Time, prev.Time, nil
+}
+
+// SetUserLastSeen records the user's visit times. A zero prevSeen is stored
+// as NULL.
+func (s *SQLiteStore) SetUserLastSeen(userID int64, lastSeen, prevSeen time.Time) error {
+	prev := sql.NullTime{Time: prevSeen.UTC(), Valid: !prevSeen.IsZero()}
+	_, err := s.db.Exec("UPDATE users SET last_seen_at = ?, prev_seen_at = ? WHERE id = ?",
+		lastSeen.UTC(), prev, userID)
+	if err != nil {
+		return fmt.Errorf("set user last seen: %w", err)
+	}
+	return nil
+}
+
 // User prompt management
 
 // GetUserPrompt retrieves a user's custom prompt template
@@ -712,103 +1304,324 @@ func (s *SQLiteStore) DeleteUserPreference(userID int64, key string) error {
 	return err
 }
 
-// UpdateStarred sets the starred flag on an article's read state.
-func (s *SQLiteStore) UpdateStarred(userID, articleID int64, starred bool) error {
+// QueuedNotification is a high-interest article held back for a user whose
+// notify_when preference is "queue".
+type QueuedNotification struct {
+	ID            int64
+	ArticleID     int64
+	InterestScore float64
+	QueuedAt      time.Time
+}
+
+// EnqueueNotification holds an article for the user's next digest. Queuing
+// an article that is already waiting is a no-op.
+func (s *SQLiteStore) EnqueueNotification(userID, articleID int64, interestScore float64) error {
 	_, err := s.db.Exec(
-		`INSERT INTO read_state (user_id, article_id, starred)
-		 VALUES (?, ?, ?)
-		 ON CONFLICT(user_id, article_id) DO UPDATE SET
-		   starred = excluded.starred`,
-		userID, articleID, starred,
+		`INSERT INTO notification_queue (user_id, article_id, interest_score, queued_at)
+		 VALUES (?, ?, ?, ?)
+		 ON CONFLICT(user_id, article_id) DO NOTHING`,
+		userID, articleID, interestScore, time.Now().UTC(),
 	)
 	if err != nil {
-		return fmt.Errorf("update starred: %w", err)
+		return fmt.Errorf("failed to enqueue notification: %w", err)
 	}
 	return nil
 }
 
-// AddFeed adds a new feed to the database
-func (s *SQLiteStore) AddFeed(url, title, description string) (int64, error) {
-	result, err := s.db.Exec(
-		"INSERT INTO feeds (url, title, description) VALUES (?, ?, ?)",
-		url, title, description,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("failed to add feed: %w", err)
-	}
-	return result.LastInsertId()
+// GetQueuedNotifications returns the user's queued notifications, oldest
+// first, without removing them. Pair it with DeleteQueuedNotifications to
+// clear the queue once the notifications have been delivered.
+func (s *SQLiteStore) GetQueuedNotifications(userID int64) ([]QueuedNotification, error) {
+	return getQueuedNotifications(s.db, userID)
 }
 
-// GetAllFeeds returns all active enabled feeds that are due for fetching.
-func (s *SQLiteStore) GetAllFeeds() ([]Feed, error) {
-	rows, err := s.db.Query(`
-		SELECT id, url, title, description, site_url, last_fetched, last_error, etag, last_modified,
-		       enabled, created_at, consecutive_errors, next_fetch_at, status
-		FROM feeds
-		WHERE enabled = 1 AND status = 'active'
-		  AND (next_fetch_at IS NULL OR next_fetch_at <= CURRENT_TIMESTAMP)`)
+func getQueuedNotifications(db *tracedDB, userID int64) ([]QueuedNotification, error) {
+	rows, err := db.Query(
+		`SELECT id, article_id, interest_score, queued_at FROM notification_queue
+		 WHERE user_id = ? ORDER BY id`,
+		userID,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get feeds: %w", err)
+		return nil, fmt.Errorf("failed to get queued notifications: %w", err)
 	}
-	defer rows.Close()
-	return scanFeeds(rows)
+	return scanQueuedNotifications(rows)
 }
 
-// UpdateFeedError records a fetch error, increments the consecutive error count,
-// schedules the next fetch with exponential backoff, and marks the feed as dead
-// when it has failed 5+ times without a successful fetch in the last 30 days.
-func (s *SQLiteStore) UpdateFeedError(feedID int64, errMsg string) error {
-	if _, err := s.db.Exec(
-		"UPDATE feeds SET last_error = ?, consecutive_errors = consecutive_errors + 1 WHERE id = ?",
-		errMsg, feedID,
-	); err != nil {
-		return fmt.Errorf("failed to update feed error: %w", err)
-	}
+// DeleteQueuedNotifications removes the user's queued notifications with an
+// ID up to and including throughID. Notifications queued after a
+// GetQueuedNotifications call have higher IDs and are kept.
+func (s *SQLiteStore) DeleteQueuedNotifications(userID, throughID int64) error {
+	return deleteQueuedNotifications(s.db, userID, throughID)
+}
 
-	var consecutiveErrors int
-	var lastFetched sql.NullTime
-	if err := s.db.QueryRow(
-		"SELECT consecutive_errors, last_fetched FROM feeds WHERE id = ?", feedID,
-	).Scan(&consecutiveErrors, &lastFetched); err != nil {
-		return nil // best-effort scheduling; don't fail the caller
+func deleteQueuedNotifications(db *tracedDB, userID, throughID int64) error {
+	_, err := db.Exec(`DELETE FROM notification_queue WHERE user_id = ? AND id <= ?`, userID, throughID)
+	if err != nil {
+		return fmt.Errorf("failed to delete queued notifications: %w", err)
 	}
+	return nil
+}
 
-	// Mark dead when persistently broken: 5+ errors and no success in 30+ days.
-	if consecutiveErrors >= 5 && (!lastFetched.Valid || time.Since(lastFetched.Time) > 30*24*time.Hour) {
-		s.db.Exec("UPDATE feeds SET status = 'dead' WHERE id = ?", feedID) //nolint:errcheck
-		return nil
+// scanQueuedNotifications collects queued notification rows.
+func scanQueuedNotifications(rows *sql.Rows) ([]QueuedNotification, error) {
+	defer rows.Close()
+	var queued []QueuedNotification
+	for rows.Next() {
+		var n QueuedNotification
+		if err := rows.Scan(&n.ID, &n.ArticleID, &n.InterestScore, &n.QueuedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan queued notification: %w", err)
+		}
+		queued = append(queued, n)
 	}
-
-	base := s.computeFeedBaseInterval(feedID)
-	next := time.Now().Add(applyErrorBackoff(base, consecutiveErrors))
-	s.db.Exec("UPDATE feeds SET next_fetch_at = ? WHERE id = ?", next, feedID) //nolint:errcheck
-	return nil
+	return queued, rows.Err()
 }
 
-// ClearFeedError clears the last error and schedules the next fetch.
-func (s *SQLiteStore) ClearFeedError(feedID int64) error {
-	return s.UpdateFeedLastFetched(feedID)
+// PresentedArticle is an article claimed by PresentArticles.
+type PresentedArticle struct {
+	ArticleID     int64
+	InterestScore float64
 }
 
-// MarkFeedFetched records a successful fetch and resets error state without
-// scheduling next_fetch_at. Use for initial subscriptions so the feed remains
-// immediately eligible for the next regular fetch cycle (next_fetch_at = NULL
-// means "due now").
-func (s *SQLiteStore) MarkFeedFetched(feedID int64) error {
-	_, err := s.db.Exec(
-		`UPDATE feeds SET last_fetched = CURRENT_TIMESTAMP, last_error = NULL,
-		 consecutive_errors = 0, status = 'active' WHERE id = ?`,
-		feedID,
-	)
+// PresentArticles claims up to limit unread articles from the user's unmuted
+// feeds that score at least minScore for interest (and minSecurity, when
+// scored, for security) and have not been presented before. Claimed articles
+// get presented_at set, so each is returned once. Results are highest
+// interest first.
+func (s *SQLiteStore) PresentArticles(userID int64, minScore, minSecurity float64, limit int, filterThreshold *int) ([]PresentedArticle, error) {
+	filterSQL, filterArgs := filterScoreClause(userID, filterThreshold)
+	query := `
+		UPDATE read_state SET presented_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND article_id IN (
+			SELECT a.id
+			FROM articles a
+			JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+			JOIN user_feeds uf ON uf.feed_id = a.feed_id AND uf.user_id = rs.user_id
+			WHERE rs.read = 0 AND rs.presented_at IS NULL AND uf.muted = 0
+			  AND rs.interest_score >= ?
+			  AND (rs.security_score IS NULL OR rs.security_score >= ?)
+			` + filterSQL + `
+			ORDER BY rs.interest_score DESC, a.published_date DESC
+			LIMIT ?
+		)
+		RETURNING article_id, interest_score`
+	args := []interface{}{userID, userID, minScore, minSecurity}
+	args = append(args, filterArgs...)
+	args = append(args, limit)
+	rows, err := s.db.queryWrite(query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to mark feed fetched: %w", err)
+		return nil, fmt.Errorf("failed to present articles: %w", err)
 	}
-	return nil
+	return scanPresentedArticles(rows)
 }
 
-// UpdateFeedCacheHeaders stores the HTTP cache headers from the last successful fetch.
-func (s *SQLiteStore) UpdateFeedCacheHeaders(feedID int64, etag, lastModified string) error {
-	_, err := s.db.Exec("UPDATE feeds SET etag = ?, last_modified = ? WHERE id = ?", etag, lastModified, feedID)
+// scanPresentedArticles collects claimed rows, highest interest first;
+// UPDATE ... RETURNING makes no ordering promise.
+func scanPresentedArticles(rows *sql.Rows) ([]PresentedArticle, error) {
+	defer rows.Close()
+	var presented []PresentedArticle
+	for rows.Next() {
+		var p PresentedArticle
+		if err := rows.Scan(&p.ArticleID, &p.InterestScore); err != nil {
+			return nil, fmt.Errorf("failed to scan presented article: %w", err)
+		}
+		presented = append(presented, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(presented, func(i, j int) bool { return presented[i].InterestScore > presented[j].InterestScore })
+	return presented, nil
+}
+
+// UpdateStarred sets the starred flag on an article's read state.
+func (s *SQLiteStore) UpdateStarred(userID, articleID int64, starred bool) error {
+	_, err := s.db.Exec(
+		`INSERT INTO read_state (user_id, article_id, starred)
+		 VALUES (?, ?, ?)
+		 ON CONFLICT(user_id, article_id) DO UPDATE SET
+		   starred = excluded.starred`,
+		userID, articleID, starred,
+	)
+	if err != nil {
+		return fmt.Errorf("update starred: %w", err)
+	}
+	return nil
+}
+
+// SetStarredBatch sets or clears the starred flag on several articles in a
+// single statement.
+func (s *SQLiteStore) SetStarredBatch(userID int64, articleIDs []int64, starred bool) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	values := strings.TrimSuffix(strings.Repeat("(?, ?, ?), ", len(articleIDs)), ", ")
+	args := make([]interface{}, 0, 3*len(articleIDs))
+	for _, id := range articleIDs {
+		args = append(args, userID, id, starred)
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO read_state (user_id, article_id, starred)
+		 VALUES `+values+`
+		 ON CONFLICT(user_id, article_id) DO UPDATE SET
+		   starred = excluded.starred`,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("update starred batch: %w", err)
+	}
+	return nil
+}
+
+// GetInterestScores returns the stored interest scores for articleIDs, keyed
+// by article ID. Unscored articles are absent from the map.
+func (s *SQLiteStore) GetInterestScores(userID int64, articleIDs []int64) (map[int64]float64, error) {
+	return queryInterestScores(s.db, userID, articleIDs)
+}
+
+// queryInterestScores implements GetInterestScores for both stores.
+func queryInterestScores(db *tracedDB, userID int64, articleIDs []int64) (map[int64]float64, error) {
+	scores := make(map[int64]float64, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return scores, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(articleIDs)), ", ")
+	args := make([]interface{}, 0, 1+len(articleIDs))
+	args = append(args, userID)
+	for _, id := range articleIDs {
+		args = append(args, id)
+	}
+	rows, err := db.Query(
+		`SELECT article_id, interest_score FROM read_state
+		 WHERE user_id = ? AND interest_score IS NOT NULL AND article_id IN (`+placeholders+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get interest scores: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var score float64
+		if err := rows.Scan(&id, &score); err != nil {
+			return nil, fmt.Errorf("scan interest score: %w", err)
+		}
+		scores[id] = score
+	}
+	return scores, rows.Err()
+}
+
+// AddFeed adds a new feed to the database. If a feed with an equivalent URL
+// (see NormalizeFeedURL) already exists, its ID is returned instead.
+func (s *SQLiteStore) AddFeed(url, title, description string) (int64, error) {
+	existing, err := s.FindFeedByNormalizedURL(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add feed: %w", err)
+	}
+	if existing != nil {
+		return existing.ID, nil
+	}
+	result, err := s.db.Exec(
+		"INSERT INTO feeds (url, title, description, normalized_url) VALUES (?, ?, ?, ?)",
+		url, title, description, NormalizeFeedURL(url),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add feed: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// FindFeedByNormalizedURL returns the oldest feed whose URL is equivalent to
+// rawURL under NormalizeFeedURL, or nil if there is none.
+func (s *SQLiteStore) FindFeedByNormalizedURL(rawURL string) (*Feed, error) {
+	rows, err := s.db.Query(`
+		SELECT id, url, title, description, site_url, generator, language, last_fetched, last_error, etag, last_modified,
+		       enabled, created_at, consecutive_errors, next_fetch_at, status, body_hash
+		FROM feeds
+		WHERE normalized_url = ?
+		ORDER BY id LIMIT 1`, NormalizeFeedURL(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find feed: %w", err)
+	}
+	defer rows.Close()
+	feeds, err := scanFeeds(rows)
+	if err != nil || len(feeds) == 0 {
+		return nil, err
+	}
+	return &feeds[0], nil
+}
+
+// GetAllFeeds returns all active enabled feeds that are due for fetching.
+func (s *SQLiteStore) GetAllFeeds() ([]Feed, error) {
+	rows, err := s.db.Query(`
+		SELECT id, url, title, description, site_url, generator, language, last_fetched, last_error, etag, last_modified,
+		       enabled, created_at, consecutive_errors, next_fetch_at, status, body_hash
+		FROM feeds
+		WHERE enabled = 1 AND status = 'active'
+		  AND (next_fetch_at IS NULL OR next_fetch_at <= CURRENT_TIMESTAMP)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feeds: %w", err)
+	}
+	defer rows.Close()
+	return scanFeeds(rows)
+}
+
+// UpdateFeedError records a fetch error, increments the consecutive error count,
+// schedules the next fetch with exponential backoff, and marks the feed as dead
+// when it has failed 5+ times without a successful fetch in the last 30 days.
+func (s *SQLiteStore) UpdateFeedError(feedID int64, errMsg string) error {
+	if _, err := s.db.Exec(
+		"UPDATE feeds SET last_error = ?, consecutive_errors = consecutive_errors + 1 WHERE id = ?",
+		errMsg, feedID,
+	); err != nil {
+		return fmt.Errorf("failed to update feed error: %w", err)
+	}
+
+	var consecutiveErrors int
+	var lastFetched sql.NullTime
+	if err := s.db.QueryRow(
+		"SELECT consecutive_errors, last_fetched FROM feeds WHERE id = ?", feedID,
+	).Scan(&consecutiveErrors, &lastFetched); err != nil {
+		return nil // best-effort scheduling; don't fail the caller
+	}
+
+	// Mark dead when persistently broken: 5+ errors and no success in 30+ days.
+	if consecutiveErrors >= 5 && (!lastFetched.Valid || time.Since(lastFetched.Time) > 30*24*time.Hour) {
+		s.db.Exec("UPDATE feeds SET status = 'dead' WHERE id = ?", feedID) //nolint:errcheck
+		return nil
+	}
+
+	base := s.computeFeedBaseInterval(feedID)
+	next := time.Now().Add(applyErrorBackoff(base, consecutiveErrors))
+	s.db.Exec("UPDATE feeds SET next_fetch_at = ? WHERE id = ?", next, feedID) //nolint:errcheck
+	return nil
+}
+
+// ClearFeedError clears the last error and schedules the next fetch.
+func (s *SQLiteStore) ClearFeedError(feedID int64) error {
+	return s.UpdateFeedLastFetched(feedID)
+}
+
+// MarkFeedFetched records a successful fetch and resets error state without
+// scheduling next_fetch_at. Use for initial subscriptions so the feed remains
+// immediately eligible for the next regular fetch cycle (next_fetch_at = NULL
+// means "due now").
+func (s *SQLiteStore) MarkFeedFetched(feedID int64) error {
+	_, err := s.db.Exec(
+		`UPDATE feeds SET last_fetched = CURRENT_TIMESTAMP, last_error = NULL,
+		 consecutive_errors = 0, status = 'active' WHERE id = ?`,
+		feedID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark feed fetched: %w", err)
+	}
+	return nil
+}
+
+// UpdateFeedCacheHeaders stores the HTTP cache headers and the body hash from
+// the last successful fetch. An empty bodyHash forces the next fetch to be
+// stored in full.
+func (s *SQLiteStore) UpdateFeedCacheHeaders(feedID int64, etag, lastModified, bodyHash string) error {
+	_, err := s.db.Exec("UPDATE feeds SET etag = ?, last_modified = ?, body_hash = ? WHERE id = ?", etag, lastModified, bodyHash, feedID)
 	if err != nil {
 		return fmt.Errorf("failed to update feed cache headers: %w", err)
 	}
@@ -833,15 +1646,16 @@ func (s *SQLiteStore) UpdateFeedLastFetched(feedID int64) error {
 
 // FindDuplicateArticle returns the ID of an existing article with the same title
 // and published date (used to suppress cross-posted duplicates from multiple feeds).
-// Returns 0 if no duplicate is found.
-func (s *SQLiteStore) FindDuplicateArticle(title string, publishedDate *time.Time) (int64, error) {
+// The item's own stored copy (feedID, guid) doesn't count. Returns 0 if no
+// duplicate is found.
+func (s *SQLiteStore) FindDuplicateArticle(feedID int64, guid, title string, publishedDate *time.Time) (int64, error) {
 	if title == "" || publishedDate == nil {
 		return 0, nil
 	}
 	var id int64
 	err := s.db.QueryRow(
-		"SELECT id FROM articles WHERE title = ? AND published_date = ? LIMIT 1",
-		title, publishedDate,
+		"SELECT id FROM articles WHERE title = ? AND published_date = ? AND NOT (feed_id = ? AND guid = ?) LIMIT 1",
+		title, publishedDate, feedID, guid,
 	).Scan(&id)
 	if err == sql.ErrNoRows {
 		return 0, nil
@@ -849,30 +1663,267 @@ func (s *SQLiteStore) FindDuplicateArticle(title string, publishedDate *time.Tim
 	return id, err
 }
 
+// FindArticleByURL returns the ID and GUID of the oldest article in feedID
+// with the given URL, or 0 and "" when there is none. Used to dedupe feeds
+// whose GUIDs change between polls.
+func (s *SQLiteStore) FindArticleByURL(feedID int64, url string) (int64, string, error) {
+	if url == "" {
+		return 0, "", nil
+	}
+	var id int64
+	var guid string
+	err := s.db.QueryRow(
+		"SELECT id, guid FROM articles WHERE feed_id = ? AND url = ? ORDER BY id LIMIT 1",
+		feedID, url,
+	).Scan(&id, &guid)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	return id, guid, err
+}
+
+// GetFeedDedupeByURL reports whether articles in feedID are deduplicated by
+// URL instead of GUID. Unknown feeds report false.
+func (s *SQLiteStore) GetFeedDedupeByURL(feedID int64) (bool, error) {
+	var enabled bool
+	err := s.db.QueryRow("SELECT dedupe_by_url FROM feeds WHERE id = ?", feedID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return enabled, err
+}
+
+// SetFeedDedupeByURL turns URL-based deduplication on or off for a feed and
+// resets its GUID churn counter.
+func (s *SQLiteStore) SetFeedDedupeByURL(feedID int64, enabled bool) error {
+	res, err := s.db.Exec("UPDATE feeds SET dedupe_by_url = ?, guid_churn_polls = 0 WHERE id = ?", enabled, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed dedupe mode: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("feed %d not found", feedID)
+	}
+	return nil
+}
+
+// GetFeedContentSource returns which article field the feed's text is taken
+// from (see ArticleBody). Unknown feeds report ContentSourceContent.
+func (s *SQLiteStore) GetFeedContentSource(feedID int64) (string, error) {
+	return getFeedContentSource(s.db, feedID)
+}
+
+// SetFeedContentSource sets which article field the feed's text is taken
+// from: "content", "summary" or "longest".
+func (s *SQLiteStore) SetFeedContentSource(feedID int64, source string) error {
+	return setFeedContentSource(s.db, feedID, source)
+}
+
+// GetFeedStripPatterns returns the regexps removed from the feed's text
+// before AI processing (see StripBoilerplate). Unknown feeds have none.
+func (s *SQLiteStore) GetFeedStripPatterns(feedID int64) ([]string, error) {
+	return getFeedStripPatterns(s.db, feedID)
+}
+
+// SetFeedStripPatterns replaces the feed's strip patterns after checking
+// them with ValidateStripPatterns; an empty list clears them.
+func (s *SQLiteStore) SetFeedStripPatterns(feedID int64, patterns []string) error {
+	return setFeedStripPatterns(s.db, feedID, patterns)
+}
+
+// GetFeedSuggestedInterval returns the polling interval the feed itself
+// advertises via <ttl> or sy:updatePeriod, or 0 when it has none.
+func (s *SQLiteStore) GetFeedSuggestedInterval(feedID int64) (time.Duration, error) {
+	return getFeedSuggestedInterval(s.db, feedID)
+}
+
+// SetFeedSuggestedInterval records the feed's advertised polling interval;
+// 0 clears it. When set, it replaces the posting-frequency heuristic in
+// next_fetch_at scheduling, clamped to MinSuggestedInterval and
+// MaxSuggestedInterval.
+func (s *SQLiteStore) SetFeedSuggestedInterval(feedID int64, interval time.Duration) error {
+	return setFeedSuggestedInterval(s.db, feedID, interval)
+}
+
+// GetFeedMaxArticles returns the feed's stored-article cap, 0 meaning
+// unlimited. Unknown feeds report 0.
+func (s *SQLiteStore) GetFeedMaxArticles(feedID int64) (int, error) {
+	var n int
+	err := s.db.QueryRow("SELECT max_articles FROM feeds WHERE id = ?", feedID).Scan(&n)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return n, err
+}
+
+// SetFeedMaxArticles sets how many unstarred articles a feed keeps; 0
+// removes the cap. The cap is enforced by TrimFeedArticles after each fetch.
+func (s *SQLiteStore) SetFeedMaxArticles(feedID int64, n int) error {
+	if n < 0 {
+		return fmt.Errorf("max articles must not be negative")
+	}
+	res, err := s.db.Exec("UPDATE feeds SET max_articles = ? WHERE id = ?", n, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed max articles: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("feed %d not found", feedID)
+	}
+	return nil
+}
+
+// GetFeedFetchHeaders returns the extra HTTP headers sent when fetching the
+// feed, keyed by canonical header name. Unknown feeds report none.
+func (s *SQLiteStore) GetFeedFetchHeaders(feedID int64) (map[string]string, error) {
+	return getFeedFetchHeaders(s.db, feedID)
+}
+
+// SetFeedFetchHeaders replaces the extra HTTP headers sent when fetching the
+// feed, e.g. Authorization for a private feed. An empty map clears them.
+func (s *SQLiteStore) SetFeedFetchHeaders(feedID int64, headers map[string]string) error {
+	return setFeedFetchHeaders(s.db, feedID, headers)
+}
+
+// TrimFeedArticles deletes a feed's oldest articles beyond the newest keep,
+// returning how many were removed. Articles any user has starred are never
+// deleted and don't count toward keep. keep <= 0 is a no-op.
+func (s *SQLiteStore) TrimFeedArticles(feedID int64, keep int) (int, error) {
+	return trimFeedArticles(s.db, feedID, keep)
+}
+
+// RecordGUIDChurn tracks consecutive polls in which a feed re-published
+// known URLs under new GUIDs. A churned poll increments the counter and
+// returns its new value; a clean poll resets it to zero.
+func (s *SQLiteStore) RecordGUIDChurn(feedID int64, churned bool) (int, error) {
+	if !churned {
+		_, err := s.db.Exec("UPDATE feeds SET guid_churn_polls = 0 WHERE id = ? AND guid_churn_polls > 0", feedID)
+		return 0, err
+	}
+	if _, err := s.db.Exec("UPDATE feeds SET guid_churn_polls = guid_churn_polls + 1 WHERE id = ?", feedID); err != nil {
+		return 0, fmt.Errorf("failed to record guid churn: %w", err)
+	}
+	var n int
+	if err := s.db.QueryRow("SELECT guid_churn_polls FROM feeds WHERE id = ?", feedID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to read guid churn: %w", err)
+	}
+	return n, nil
+}
+
 // AddArticle adds a new article to the database
 func (s *SQLiteStore) AddArticle(article *Article) (int64, error) {
+	slug, err := articleSlug(s.db, article)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add article: %w", err)
+	}
 	result, err := s.db.Exec(
-		`INSERT INTO articles (feed_id, guid, title, url, content, summary, author, published_date)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`INSERT INTO articles (feed_id, guid, title, url, domain, content, summary, author, published_date, updated_date, lang, word_count, slug)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(feed_id, guid) DO NOTHING`,
-		article.FeedID, article.GUID, article.Title, article.URL,
-		article.Content, article.Summary, article.Author, article.PublishedDate,
+		article.FeedID, article.GUID, article.Title, article.URL, articleDomain(article.URL),
+		article.Content, article.Summary, article.Author, article.PublishedDate, article.UpdatedDate,
+		articleLang(article.Lang), article.WordCount, slug,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to add article: %w", err)
 	}
+	// LastInsertId is stale when the insert was skipped as a duplicate.
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return 0, nil
+	}
 	return result.LastInsertId()
 }
 
+// UpsertArticle stores a feed item like AddArticle. If the item is already
+// stored and its UpdatedDate is newer than the stored copy's (its
+// updated_date, or its fetched_date when it has none), the item was edited:
+// its title, content, summary and word count are refreshed and the cached
+// AI summaries dropped so they are regenerated, and the article is queued
+// for scoring again. The read and starred flags are kept. Items trimmed by TrimFeedArticles are not stored again. It returns
+// the article ID when the item was inserted or refreshed, 0 otherwise, and
+// whether it was a refresh.
+func (s *SQLiteStore) UpsertArticle(article *Article) (int64, bool, error) {
+	return upsertArticle(s.db, article, s.AddArticle)
+}
+
+// upsertArticle implements UpsertArticle for both stores; add is the
+// store's AddArticle.
+func upsertArticle(db *tracedDB, article *Article, add func(*Article) (int64, error)) (int64, bool, error) {
+	var trimmed bool
+	err := db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM trimmed_articles WHERE feed_id = ? AND guid = ?)",
+		article.FeedID, article.GUID,
+	).Scan(&trimmed)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to check trimmed articles: %w", err)
+	}
+	if trimmed {
+		return 0, false, nil
+	}
+
+	if article.UpdatedDate == nil {
+		id, err := add(article)
+		return id, false, err
+	}
+
+	var id int64
+	var updated *time.Time
+	var fetched time.Time
+	err = db.QueryRow(
+		"SELECT id, updated_date, fetched_date FROM articles WHERE feed_id = ? AND guid = ?",
+		article.FeedID, article.GUID,
+	).Scan(&id, &updated, &fetched)
+	if err == sql.ErrNoRows {
+		id, err := add(article)
+		return id, false, err
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to look up article: %w", err)
+	}
+	stored := fetched
+	if updated != nil {
+		stored = *updated
+	}
+	if !article.UpdatedDate.After(stored) {
+		return 0, false, nil
+	}
+
+	err = db.inTx(func(tx *tracedDB) error {
+		// The feed's copy replaces any full text fetched earlier, so let the
+		// full-text pass run again.
+		_, err := tx.Exec(
+			`UPDATE articles SET title = ?, content = ?, summary = ?, word_count = ?,
+			        updated_date = ?, full_text_fetched = ?
+			 WHERE id = ?`,
+			article.Title, article.Content, article.Summary, article.WordCount,
+			article.UpdatedDate, false, id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to refresh article: %w", err)
+		}
+		if _, err := tx.Exec("DELETE FROM article_summaries WHERE article_id = ?", id); err != nil {
+			return fmt.Errorf("failed to drop stale summaries: %w", err)
+		}
+		// Queue the new text for scoring again; read and starred stay as
+		// the user left them.
+		if _, err := tx.Exec("UPDATE read_state SET ai_scored = ? WHERE article_id = ?", false, id); err != nil {
+			return fmt.Errorf("failed to reset article scoring: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
 // GetUnreadArticles returns all unread articles
 func (s *SQLiteStore) GetUnreadArticles(limit int) ([]Article, error) {
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		LEFT JOIN read_state rs ON a.id = rs.article_id
 		WHERE rs.article_id IS NULL OR rs.read = 0
-		ORDER BY a.published_date DESC
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
 		LIMIT ?
 	`
 	rows, err := s.db.Query(query, limit)
@@ -885,7 +1936,7 @@ func (s *SQLiteStore) GetUnreadArticles(limit int) ([]Article, error) {
 	for rows.Next() {
 		var a Article
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
 			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 		articles = append(articles, a)
@@ -927,6 +1978,13 @@ func (s *SQLiteStore) UpdateArticleContent(articleID int64, content string) erro
 	return err
 }
 
+// UpdateArticleWordCount replaces an article's stored word count, e.g. after
+// its content was replaced with the full text.
+func (s *SQLiteStore) UpdateArticleWordCount(articleID int64, wordCount int) error {
+	_, err := s.db.Exec(`UPDATE articles SET word_count = ? WHERE id = ?`, wordCount, articleID)
+	return err
+}
+
 // MarkArticleFullTextFetched sets full_text_fetched = 1 for the article,
 // recording that we have already processed it (whether or not we updated the content).
 func (s *SQLiteStore) MarkArticleFullTextFetched(articleID int64) error {
@@ -970,6 +2028,39 @@ func (s *SQLiteStore) UpdateReadState(userID, articleID int64, read bool, intere
 	return nil
 }
 
+// ToggleRead flips the user's read flag on an article and returns the new
+// state. An article without read state becomes read. Scores are untouched.
+func (s *SQLiteStore) ToggleRead(userID, articleID int64) (bool, error) {
+	return toggleRead(s.db,
+		`INSERT INTO read_state (user_id, article_id, read, read_date)
+		 VALUES (?, ?, 1, CURRENT_TIMESTAMP)
+		 ON CONFLICT(user_id, article_id) DO UPDATE SET
+		   read = NOT read_state.read,
+		   read_date = CURRENT_TIMESTAMP
+		 RETURNING read`,
+		userID, articleID)
+}
+
+// toggleRead runs a store's ToggleRead upsert and reads back the new state.
+func toggleRead(db *tracedDB, query string, userID, articleID int64) (bool, error) {
+	rows, err := db.queryWrite(query, userID, articleID)
+	if err != nil {
+		return false, fmt.Errorf("failed to toggle read state: %w", err)
+	}
+	defer rows.Close()
+	var read bool
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return false, fmt.Errorf("failed to toggle read state: %w", err)
+		}
+		return false, fmt.Errorf("failed to toggle read state: no row returned")
+	}
+	if err := rows.Scan(&read); err != nil {
+		return false, fmt.Errorf("failed to toggle read state: %w", err)
+	}
+	return read, rows.Close()
+}
+
 // GetScoreStats returns AI scoring breakdown per feed for a user.
 func (s *SQLiteStore) GetScoreStats(userID int64) (*ScoreStatsResult, error) {
 	rows, err := s.db.Query(`
@@ -1032,6 +2123,60 @@ func (s *SQLiteStore) IncrementAIRetries(userID, articleID int64) error {
 	return nil
 }
 
+// RecordArticleOpen counts one opening of the article by the user.
+// Creates a read_state row if one doesn't exist yet.
+func (s *SQLiteStore) RecordArticleOpen(userID, articleID int64) error {
+	_, err := s.db.Exec(
+		`INSERT INTO read_state (user_id, article_id, open_count)
+		 VALUES (?, ?, 1)
+		 ON CONFLICT(user_id, article_id) DO UPDATE SET
+		   open_count = read_state.open_count + 1`,
+		userID, articleID,
+	)
+	if err != nil {
+		return fmt.Errorf("record article open: %w", err)
+	}
+	return nil
+}
+
+// GetOpenCounts returns how many times the user has opened each of
+// articleIDs. Articles never opened are absent from the map.
+func (s *SQLiteStore) GetOpenCounts(userID int64, articleIDs []int64) (map[int64]int, error) {
+	return queryOpenCounts(s.db, userID, articleIDs)
+}
+
+// queryOpenCounts implements GetOpenCounts for both stores.
+func queryOpenCounts(db *tracedDB, userID int64, articleIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return counts, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(articleIDs)), ", ")
+	args := make([]interface{}, 0, 1+len(articleIDs))
+	args = append(args, userID)
+	for _, id := range articleIDs {
+		args = append(args, id)
+	}
+	rows, err := db.Query(
+		`SELECT article_id, open_count FROM read_state
+		 WHERE user_id = ? AND open_count > 0 AND article_id IN (`+placeholders+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get open counts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, fmt.Errorf("scan open count: %w", err)
+		}
+		counts[id] = n
+	}
+	return counts, rows.Err()
+}
+
 // ResetScores clears AI scores so articles are reprocessed by the pipeline.
 // If securityOnly is true, only articles that failed the security check are reset.
 // belowScore filters to articles with security_score < belowScore (use 10.0 to reset all).
@@ -1062,6 +2207,108 @@ func (s *SQLiteStore) ResetScores(userID int64, securityOnly bool, belowScore fl
 	return n, nil
 }
 
+// ClearInterestScores nulls the interest score of the user's unread, AI-scored
+// articles whose security score is at least minSecurityScore, so they can be
+// re-curated. Read flags, security scores and ai_scored are left untouched.
+// Returns the number of rows affected.
+func (s *SQLiteStore) ClearInterestScores(userID int64, minSecurityScore float64) (int64, error) {
+	result, err := s.db.Exec(
+		`UPDATE read_state SET interest_score = NULL
+		 WHERE user_id = ? AND read = 0 AND ai_scored = 1 AND security_score >= ?`,
+		userID, minSecurityScore,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("clear interest scores: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// GetUncuratedUnreadArticles returns the user's unread articles that passed
+// AI scoring but have no interest score, i.e. those cleared by
+// ClearInterestScores and not yet re-curated.
+func (s *SQLiteStore) GetUncuratedUnreadArticles(userID int64) ([]Article, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+		WHERE rs.read = 0 AND rs.ai_scored = 1 AND rs.interest_score IS NULL
+		ORDER BY a.id`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get uncurated articles: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []Article
+	for rows.Next() {
+		var a Article
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
+			return nil, fmt.Errorf("scan article: %w", err)
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// UpdateInterestScore sets only the interest score of an existing read_state
+// row, leaving the read flag and security verdict as they are.
+func (s *SQLiteStore) UpdateInterestScore(userID, articleID int64, score float64) error {
+	_, err := s.db.Exec(
+		`UPDATE read_state SET interest_score = ? WHERE user_id = ? AND article_id = ?`,
+		score, userID, articleID,
+	)
+	if err != nil {
+		return fmt.Errorf("update interest score: %w", err)
+	}
+	return nil
+}
+
+// OverrideSecurity releases an article the security check blocked for the
+// user: the security score is raised to threshold, the interest score is
+// set and security_overridden is flagged. The security reason is kept for
+// the record. Articles that aren't blocked are an error.
+func (s *SQLiteStore) OverrideSecurity(userID, articleID int64, threshold, interestScore float64) error {
+	return overrideSecurity(s.db, userID, articleID, threshold, interestScore)
+}
+
+// IsArticleBlocked reports whether the security check blocked the article
+// for the user: it has a security score below threshold.
+func (s *SQLiteStore) IsArticleBlocked(userID, articleID int64, threshold float64) (bool, error) {
+	return isArticleBlocked(s.db, userID, articleID, threshold)
+}
+
+// isArticleBlocked implements IsArticleBlocked for both stores.
+func isArticleBlocked(db *tracedDB, userID, articleID int64, threshold float64) (bool, error) {
+	var blocked bool
+	err := db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM read_state
+		WHERE user_id = ? AND article_id = ? AND security_score IS NOT NULL AND security_score < ?)`,
+		userID, articleID, threshold).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check security verdict: %w", err)
+	}
+	return blocked, nil
+}
+
+// overrideSecurity implements OverrideSecurity for both stores.
+func overrideSecurity(db *tracedDB, userID, articleID int64, threshold, interestScore float64) error {
+	res, err := db.Exec(`
+		UPDATE read_state
+		SET security_score = ?, interest_score = ?, security_overridden = TRUE, ai_scored = TRUE, ai_retries = 0
+		WHERE user_id = ? AND article_id = ? AND security_score IS NOT NULL AND security_score < ?`,
+		threshold, interestScore, userID, articleID, threshold)
+	if err != nil {
+		return fmt.Errorf("failed to override security verdict: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("article %d is not blocked", articleID)
+	}
+	return nil
+}
+
 // GetArticlesByInterestScore returns unread articles with interest scores above
 // threshold, ordered by a time-decayed effective score. The decay formula is:
 //
@@ -1070,19 +2317,22 @@ func (s *SQLiteStore) ResetScores(userID int64, securityOnly bool, belowScore fl
 // This causes older articles to gradually sink in priority: a 10-day-old article
 // is weighted at 50% of its raw score, 20-day at 33%, 30-day at 25%. The WHERE
 // clause still filters on the raw score so legitimately interesting articles
-// remain visible — they just sort lower as they age. Returned scores are the
-// decayed effective scores, not the raw stored values.
-func (s *SQLiteStore) GetArticlesByInterestScore(userID int64, threshold float64, limit, offset int, filterThreshold *int) ([]Article, []float64, error) {
+// remain visible — they just sort lower as they age. The first returned score
+// slice holds the decayed effective scores; the second holds the raw stored
+// values, so callers can tell an old-but-interesting article from one that was
+// never interesting.
+func (s *SQLiteStore) GetArticlesByInterestScore(userID int64, threshold float64, limit, offset int, filterThreshold *int) ([]Article, []float64, []float64, error) {
 	filterSQL, filterArgs := filterScoreClause(userID, filterThreshold)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date,
+		       a.author, a.published_date, a.fetched_date, a.word_count,
+		       COALESCE(rs.interest_score, 0) AS raw_score,
 		       COALESCE(rs.interest_score, 0) * (1.0 / (1.0 + MAX(0, julianday('now') - julianday(COALESCE(a.published_date, a.fetched_date))) * 0.1)) AS decayed_score
 		FROM articles a
 		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
 		WHERE rs.interest_score >= ? AND rs.read = 0
 		` + filterSQL + `
-		ORDER BY decayed_score DESC
+		ORDER BY decayed_score DESC, COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
 		LIMIT ? OFFSET ?
 	`
 	args := []interface{}{userID, threshold}
@@ -1090,23 +2340,24 @@ func (s *SQLiteStore) GetArticlesByInterestScore(userID int64, threshold float64
 	args = append(args, limit, offset)
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get articles by interest score: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to get articles by interest score: %w", err)
 	}
 	defer rows.Close()
 
 	var articles []Article
-	var scores []float64
+	var scores, rawScores []float64
 	for rows.Next() {
 		var a Article
-		var score float64
+		var score, raw float64
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &score); err != nil {
-			return nil, nil, fmt.Errorf("failed to scan article: %w", err)
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount, &raw, &score); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 		articles = append(articles, a)
 		scores = append(scores, score)
+		rawScores = append(rawScores, raw)
 	}
-	return articles, scores, rows.Err()
+	return articles, scores, rawScores, rows.Err()
 }
 
 // UpdateArticleAISummary stores the AI-generated summary for an article (per-user)
@@ -1141,6 +2392,68 @@ func (s *SQLiteStore) GetArticleSummary(userID, articleID int64) (*ArticleSummar
 	return &as, nil
 }
 
+// SetArticleNote stores the user's note on an article, replacing any earlier
+// one. An empty note deletes it.
+func (s *SQLiteStore) SetArticleNote(userID, articleID int64, note string) error {
+	var err error
+	if note == "" {
+		_, err = s.db.Exec("DELETE FROM article_notes WHERE user_id = ? AND article_id = ?", userID, articleID)
+	} else {
+		_, err = s.db.Exec(
+			`INSERT INTO article_notes (user_id, article_id, note, updated_at)
+			 VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+			 ON CONFLICT(user_id, article_id) DO UPDATE SET note = excluded.note, updated_at = CURRENT_TIMESTAMP`,
+			userID, articleID, note,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("set article note: %w", err)
+	}
+	return nil
+}
+
+// GetArticleNote returns the user's note on an article, or nil if there is none.
+func (s *SQLiteStore) GetArticleNote(userID, articleID int64) (*ArticleNote, error) {
+	var n ArticleNote
+	err := s.db.QueryRow(
+		"SELECT user_id, article_id, note, updated_at FROM article_notes WHERE user_id = ? AND article_id = ?",
+		userID, articleID,
+	).Scan(&n.UserID, &n.ArticleID, &n.Note, &n.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get article note: %w", err)
+	}
+	return &n, nil
+}
+
+// GetSummarizedArticleIDs returns the IDs of articles in a feed that already
+// have an AI summary for the user.
+func (s *SQLiteStore) GetSummarizedArticleIDs(userID, feedID int64) ([]int64, error) {
+	rows, err := s.db.Query(
+		`SELECT s.article_id FROM article_summaries s
+		 JOIN articles a ON a.id = s.article_id
+		 WHERE s.user_id = ? AND a.feed_id = ?
+		 ORDER BY s.article_id`,
+		userID, feedID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get summarized articles: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan summarized article: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // FeedStats holds per-feed article counts.
 type FeedStats struct {
 	FeedID               int64
@@ -1149,6 +2462,8 @@ type FeedStats struct {
 	UnreadArticles       int
 	UnsummarizedArticles int
 	LastPostDate         *time.Time
+	Muted                bool
+	Folder               string
 }
 
 // GetFeedStats returns article counts per feed for a user.
@@ -1163,14 +2478,14 @@ func (s *SQLiteStore) GetFeedStats(userID int64) ([]FeedStats, error) {
 			           WHERE agm.article_id = a.id AND ag.user_id = uf.user_id
 			         ) THEN 1 ELSE 0 END),
 			COUNT(a.id) - COUNT(asumm.article_id),
-			MAX(a.published_date)
+			MAX(a.published_date), uf.muted, uf.folder
 		FROM feeds f
 		JOIN user_feeds uf ON uf.feed_id = f.id AND uf.user_id = ?
 		JOIN articles a ON a.feed_id = f.id
 		LEFT JOIN read_state rs ON rs.article_id = a.id AND rs.user_id = ?
 		LEFT JOIN article_summaries asumm ON asumm.article_id = a.id AND asumm.user_id = ?
-		GROUP BY f.id, uf.user_title
-		ORDER BY COALESCE(uf.user_title, f.title)`,
+		GROUP BY f.id, uf.user_title, uf.muted, uf.folder
+		ORDER BY uf.folder, COALESCE(uf.user_title, f.title)`,
 		userID, userID, userID,
 	)
 	if err != nil {
@@ -1193,7 +2508,7 @@ func (s *SQLiteStore) GetFeedStats(userID int64) ([]FeedStats, error) {
 	for rows.Next() {
 		var fs FeedStats
 		var lastPost *string
-		if err := rows.Scan(&fs.FeedID, &fs.FeedTitle, &fs.TotalArticles, &fs.UnreadArticles, &fs.UnsummarizedArticles, &lastPost); err != nil {
+		if err := rows.Scan(&fs.FeedID, &fs.FeedTitle, &fs.TotalArticles, &fs.UnreadArticles, &fs.UnsummarizedArticles, &lastPost, &fs.Muted, &fs.Folder); err != nil {
 			return nil, fmt.Errorf("scan feed stats: %w", err)
 		}
 		if lastPost != nil {
@@ -1240,11 +2555,11 @@ func (s *SQLiteStore) AddArticleToGroup(groupID, articleID int64) error {
 func (s *SQLiteStore) GetGroupArticles(groupID int64) ([]Article, error) {
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN article_group_members agm ON a.id = agm.article_id
 		WHERE agm.group_id = ?
-		ORDER BY a.published_date DESC
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
 	`
 	rows, err := s.db.Query(query, groupID)
 	if err != nil {
@@ -1256,7 +2571,7 @@ func (s *SQLiteStore) GetGroupArticles(groupID int64) ([]Article, error) {
 	for rows.Next() {
 		var a Article
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
 			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 		articles = append(articles, a)
@@ -1299,6 +2614,33 @@ func (s *SQLiteStore) GetGroupSummary(groupID int64) (*GroupSummary, error) {
 // GetUserGroups returns groups for a user that contain at least 2 articles.
 // Single-article groups are excluded as they represent ungrouped articles rather
 // than genuine topic clusters.
+// GetRecentGroups returns a user's groups updated at or after since,
+// including the single-article groups GetUserGroups hides, newest first.
+func (s *SQLiteStore) GetRecentGroups(userID int64, since time.Time) ([]ArticleGroup, error) {
+	rows, err := s.db.Query(`SELECT id, user_id, topic, display_name, muted, created_at, updated_at
+		FROM article_groups
+		WHERE user_id = ? AND julianday(updated_at) >= julianday(?)
+		ORDER BY updated_at DESC`, userID, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []ArticleGroup
+	for rows.Next() {
+		var g ArticleGroup
+		var displayName *string
+		if err := rows.Scan(&g.ID, &g.UserID, &g.Topic, &displayName, &g.Muted, &g.CreatedAt, &g.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan group: %w", err)
+		}
+		if displayName != nil {
+			g.DisplayName = *displayName
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
 func (s *SQLiteStore) GetUserGroups(userID int64) ([]ArticleGroup, error) {
 	query := `SELECT ag.id, ag.user_id, ag.topic, ag.display_name, ag.muted, ag.created_at, ag.updated_at
 		FROM article_groups ag
@@ -1369,14 +2711,14 @@ func (s *SQLiteStore) GetUnreadGroupArticles(userID, groupID int64, limit, offse
 	filterSQL, filterArgs := filterScoreClause(userID, filterThreshold)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN article_group_members agm ON a.id = agm.article_id
 		JOIN article_groups ag ON agm.group_id = ag.id
 		LEFT JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
 		WHERE agm.group_id = ? AND ag.user_id = ? AND (rs.article_id IS NULL OR rs.read = 0)
 		` + filterSQL + `
-		ORDER BY a.published_date DESC
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
 		LIMIT ? OFFSET ?
 	`
 	args := []interface{}{userID, groupID, userID}
@@ -1392,12 +2734,42 @@ func (s *SQLiteStore) GetUnreadGroupArticles(userID, groupID int64, limit, offse
 	for rows.Next() {
 		var a Article
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
 			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
-		articles = append(articles, a)
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// GetGroupUnreadCounts returns the number of unread articles in each of the
+// user's groups, keyed by group ID. Groups with no members are omitted.
+func (s *SQLiteStore) GetGroupUnreadCounts(userID int64) (map[int64]int, error) {
+	rows, err := s.db.Query(`
+		SELECT ag.id,
+		       SUM(CASE WHEN rs.read IS NULL OR rs.read = 0 THEN 1 ELSE 0 END)
+		FROM article_groups ag
+		JOIN article_group_members agm ON agm.group_id = ag.id
+		LEFT JOIN read_state rs ON rs.article_id = agm.article_id AND rs.user_id = ?
+		WHERE ag.user_id = ?
+		GROUP BY ag.id`,
+		userID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get group unread counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var id int64
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, fmt.Errorf("scan group unread count: %w", err)
+		}
+		counts[id] = n
 	}
-	return articles, rows.Err()
+	return counts, rows.Err()
 }
 
 // GetGroupStats returns sidebar data for non-muted groups with 2+ articles and unread content.
@@ -1453,11 +2825,7 @@ func (s *SQLiteStore) IsGroupMuted(groupID int64) (bool, error) {
 
 // DisbandGroup deletes a group and its memberships (ON DELETE CASCADE).
 func (s *SQLiteStore) DisbandGroup(groupID int64) error {
-	_, err := s.db.Exec("DELETE FROM article_groups WHERE id = ?", groupID)
-	if err != nil {
-		return fmt.Errorf("disband group: %w", err)
-	}
-	return nil
+	return disbandGroup(s.db, groupID)
 }
 
 // UpdateGroupDisplayName sets the display name for a group.
@@ -1469,8 +2837,435 @@ func (s *SQLiteStore) UpdateGroupDisplayName(groupID int64, displayName string)
 	return nil
 }
 
-// SubscribeUserToFeed subscribes a user to a feed
+// RenameGroup sets a group's topic and clears any AI-assigned display name so
+// the new topic is what the user sees.
+func (s *SQLiteStore) RenameGroup(groupID int64, topic string) error {
+	_, err := s.db.Exec(
+		"UPDATE article_groups SET topic = ?, display_name = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		topic, groupID,
+	)
+	if err != nil {
+		return fmt.Errorf("rename group: %w", err)
+	}
+	return nil
+}
+
+// MergeGroups moves every member of srcID into dstID and deletes srcID, in
+// one transaction. Articles already in dstID are skipped.
+func (s *SQLiteStore) MergeGroups(srcID, dstID int64) error {
+	return mergeGroups(s.db, srcID, dstID)
+}
+
+// mergeGroups implements MergeGroups for both stores.
+func mergeGroups(db *tracedDB, srcID, dstID int64) error {
+	return db.inTx(func(tx *tracedDB) error {
+		_, err := tx.Exec(
+			`INSERT INTO article_group_members (group_id, article_id, added_at)
+			 SELECT ?, article_id, added_at FROM article_group_members WHERE group_id = ?
+			 ON CONFLICT DO NOTHING`,
+			dstID, srcID,
+		)
+		if err != nil {
+			return fmt.Errorf("merge group members: %w", err)
+		}
+		if err := disbandGroup(tx, srcID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE article_groups SET updated_at = CURRENT_TIMESTAMP WHERE id = ?", dstID); err != nil {
+			return fmt.Errorf("touch merged group: %w", err)
+		}
+		return nil
+	})
+}
+
+// disbandGroup implements DisbandGroup for both stores. Memberships go with
+// the group through ON DELETE CASCADE.
+func disbandGroup(db *tracedDB, groupID int64) error {
+	if _, err := db.Exec("DELETE FROM article_groups WHERE id = ?", groupID); err != nil {
+		return fmt.Errorf("disband group: %w", err)
+	}
+	return nil
+}
+
+// RemoveArticleFromGroup drops a single article from a group.
+func (s *SQLiteStore) RemoveArticleFromGroup(groupID, articleID int64) error {
+	_, err := s.db.Exec(
+		"DELETE FROM article_group_members WHERE group_id = ? AND article_id = ?",
+		groupID, articleID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove article from group: %w", err)
+	}
+	_, err = s.db.Exec("UPDATE article_groups SET updated_at = CURRENT_TIMESTAMP WHERE id = ?", groupID)
+	return err
+}
+
+// ReassignFeedArticles moves every article of fromFeedID to toFeedID in one
+// transaction, so per-article state survives a feed consolidation. When
+// both feeds carry the same GUID, the surviving feed's article is kept: the
+// duplicate's read and starred flags are OR'd into it, its other per-article
+// rows move over where the survivor has none, and the duplicate is deleted.
+func (s *SQLiteStore) ReassignFeedArticles(fromFeedID, toFeedID int64) error {
+	return reassignFeedArticles(s.db, fromFeedID, toFeedID)
+}
+
+// articleStateTables lists the tables holding per-article rows, each with
+// the columns that, together with article_id, identify a row.
+var articleStateTables = []struct {
+	table string
+	keys  []string
+}{
+	{"read_state", []string{"user_id"}},
+	{"article_summaries", []string{"user_id"}},
+	{"article_notes", []string{"user_id"}},
+	{"ai_tags", []string{"user_id", "tag"}},
+	{"article_group_members", []string{"group_id"}},
+	{"article_authors", []string{"name"}},
+	{"article_categories", []string{"category"}},
+	{"article_images", []string{"original_url"}},
+	{"article_embeddings", nil},
+	{"notification_queue", []string{"user_id"}},
+}
+
+// unstarred matches articles no user has starred.
+const unstarred = `NOT EXISTS (SELECT 1 FROM read_state rs WHERE rs.article_id = articles.id AND rs.starred)`
+
+// getArticleEmbeddings implements GetArticleEmbeddings for both stores.
+func getArticleEmbeddings(db *tracedDB, userID int64, model string, since time.Time) ([]ArticleEmbeddingRow, error) {
+	query := `
+		SELECT ae.article_id, ae.embedding
+		FROM article_embeddings ae
+		JOIN articles a ON a.id = ae.article_id
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		WHERE uf.user_id = ? AND ae.embedding_model = ?`
+	args := []any{userID, model}
+	if !since.IsZero() {
+		query += " AND a.fetched_date >= ?"
+		args = append(args, since.UTC())
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get article embeddings: %w", err)
+	}
+	defer rows.Close()
+	var result []ArticleEmbeddingRow
+	for rows.Next() {
+		var r ArticleEmbeddingRow
+		if err := rows.Scan(&r.ArticleID, &r.Embedding); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// getFeedContentSource implements GetFeedContentSource for both stores.
+func getFeedContentSource(db *tracedDB, feedID int64) (string, error) {
+	source := ContentSourceContent
+	err := db.QueryRow("SELECT content_source FROM feeds WHERE id = ?", feedID).Scan(&source)
+	if err == sql.ErrNoRows {
+		return ContentSourceContent, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get feed content source: %w", err)
+	}
+	return source, nil
+}
+
+// setFeedContentSource implements SetFeedContentSource for both stores.
+func setFeedContentSource(db *tracedDB, feedID int64, source string) error {
+	if !ValidContentSource(source) {
+		return fmt.Errorf("content source must be content, summary or longest")
+	}
+	res, err := db.Exec("UPDATE feeds SET content_source = ? WHERE id = ?", source, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed content source: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("feed %d not found", feedID)
+	}
+	return nil
+}
+
+// getFeedFetchHeaders implements GetFeedFetchHeaders for both stores.
+func getFeedFetchHeaders(db *tracedDB, feedID int64) (map[string]string, error) {
+	var raw string
+	err := db.QueryRow("SELECT fetch_headers FROM feeds WHERE id = ?", feedID).Scan(&raw)
+	if err == sql.ErrNoRows || (err == nil && raw == "") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed fetch headers: %w", err)
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(raw), &headers); err != nil {
+		return nil, fmt.Errorf("failed to decode fetch headers for feed %d: %w", feedID, err)
+	}
+	return headers, nil
+}
+
+// checkPrivateFeed returns ErrPrivateFeed if feedID has fetch headers and a
+// user other than userID subscribes to it: the headers are that user's
+// credentials, so nobody else may read the feed through them.
+func checkPrivateFeed(db *tracedDB, userID, feedID int64) error {
+	var shared bool
+	err := db.QueryRow(`SELECT EXISTS(
+		SELECT 1 FROM feeds f JOIN user_feeds uf ON uf.feed_id = f.id
+		WHERE f.id = ? AND f.fetch_headers != '' AND uf.user_id != ?)`,
+		feedID, userID,
+	).Scan(&shared)
+	if err != nil {
+		return fmt.Errorf("failed to check feed credentials: %w", err)
+	}
+	if shared {
+		return ErrPrivateFeed
+	}
+	return nil
+}
+
+// setFeedFetchHeaders implements SetFeedFetchHeaders for both stores. Names
+// are canonicalized, so "authorization" and "Authorization" are one header.
+func setFeedFetchHeaders(db *tracedDB, feedID int64, headers map[string]string) error {
+	clean := make(map[string]string, len(headers))
+	for name, value := range headers {
+		name = strings.TrimSpace(name)
+		if !validHeaderName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("header %s: value must be a single line", name)
+		}
+		clean[textproto.CanonicalMIMEHeaderKey(name)] = value
+	}
+	var raw string
+	if len(clean) > 0 {
+		b, err := json.Marshal(clean)
+		if err != nil {
+			return fmt.Errorf("failed to encode fetch headers: %w", err)
+		}
+		raw = string(b)
+	}
+	res, err := db.Exec("UPDATE feeds SET fetch_headers = ? WHERE id = ?", raw, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed fetch headers: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("feed %d not found", feedID)
+	}
+	return nil
+}
+
+// getFeedStripPatterns implements GetFeedStripPatterns for both stores.
+func getFeedStripPatterns(db *tracedDB, feedID int64) ([]string, error) {
+	var raw string
+	err := db.QueryRow("SELECT strip_patterns FROM feeds WHERE id = ?", feedID).Scan(&raw)
+	if err == sql.ErrNoRows || (err == nil && raw == "") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed strip patterns: %w", err)
+	}
+	var patterns []string
+	if err := json.Unmarshal([]byte(raw), &patterns); err != nil {
+		return nil, fmt.Errorf("failed to decode strip patterns for feed %d: %w", feedID, err)
+	}
+	return patterns, nil
+}
+
+// setFeedStripPatterns implements SetFeedStripPatterns for both stores.
+func setFeedStripPatterns(db *tracedDB, feedID int64, patterns []string) error {
+	if err := ValidateStripPatterns(patterns); err != nil {
+		return err
+	}
+	var raw string
+	if len(patterns) > 0 {
+		b, err := json.Marshal(patterns)
+		if err != nil {
+			return fmt.Errorf("failed to encode strip patterns: %w", err)
+		}
+		raw = string(b)
+	}
+	res, err := db.Exec("UPDATE feeds SET strip_patterns = ? WHERE id = ?", raw, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed strip patterns: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("feed %d not found", feedID)
+	}
+	return nil
+}
+
+// Bounds applied to a feed's suggested interval when scheduling, so a
+// publisher hint can neither hammer a server nor stall a feed for months.
+const (
+	MinSuggestedInterval = 15 * time.Minute
+	MaxSuggestedInterval = 7 * 24 * time.Hour
+)
+
+// getFeedSuggestedInterval implements GetFeedSuggestedInterval for both stores.
+func getFeedSuggestedInterval(db *tracedDB, feedID int64) (time.Duration, error) {
+	var minutes int64
+	err := db.QueryRow("SELECT suggested_interval FROM feeds WHERE id = ?", feedID).Scan(&minutes)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get feed suggested interval: %w", err)
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// setFeedSuggestedInterval implements SetFeedSuggestedInterval for both
+// stores. The interval is stored in whole minutes.
+func setFeedSuggestedInterval(db *tracedDB, feedID int64, interval time.Duration) error {
+	if interval < 0 {
+		return fmt.Errorf("suggested interval must not be negative")
+	}
+	res, err := db.Exec("UPDATE feeds SET suggested_interval = ? WHERE id = ?", int64(interval/time.Minute), feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed suggested interval: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("feed %d not found", feedID)
+	}
+	return nil
+}
+
+// suggestedBaseInterval returns the feed's suggested interval clamped to
+// [MinSuggestedInterval, MaxSuggestedInterval], or 0 when it has none.
+func suggestedBaseInterval(db *tracedDB, feedID int64) time.Duration {
+	d, err := getFeedSuggestedInterval(db, feedID)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return min(max(d, MinSuggestedInterval), MaxSuggestedInterval)
+}
+
+// validHeaderName reports whether name is a non-empty RFC 7230 token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// trimmedGUIDRetention is how long a trimmed article's GUID is remembered.
+// Feeds rarely keep listing an item for longer than this.
+const trimmedGUIDRetention = 90 * 24 * time.Hour
+
+// trimFeedArticles implements TrimFeedArticles for both stores. Articles
+// are ranked newest first by publish date, falling back to fetch date.
+// Trimmed GUIDs are remembered in trimmed_articles so that upsertArticle
+// doesn't store them again while the feed still lists them.
+func trimFeedArticles(db *tracedDB, feedID int64, keep int) (int, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+	const trimmable = `feed_id = ? AND ` + unstarred + `
+		  AND id NOT IN (
+			SELECT id FROM articles
+			WHERE feed_id = ? AND ` + unstarred + `
+			ORDER BY COALESCE(published_date, fetched_date) DESC, id DESC
+			LIMIT ?)`
+	now := time.Now().UTC()
+	var n int64
+	err := db.inTx(func(tx *tracedDB) error {
+		if _, err := tx.Exec(`
+			INSERT INTO trimmed_articles (feed_id, guid, trimmed_at)
+			SELECT feed_id, guid, ? FROM articles WHERE `+trimmable+`
+			ON CONFLICT (feed_id, guid) DO UPDATE SET trimmed_at = excluded.trimmed_at`,
+			now, feedID, feedID, keep); err != nil {
+			return err
+		}
+		res, err := tx.Exec(`DELETE FROM articles WHERE `+trimmable, feedID, feedID, keep)
+		if err != nil {
+			return err
+		}
+		n, _ = res.RowsAffected()
+		_, err = tx.Exec(`DELETE FROM trimmed_articles WHERE feed_id = ? AND trimmed_at < ?`,
+			feedID, now.Add(-trimmedGUIDRetention))
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to trim articles for feed %d: %w", feedID, err)
+	}
+	return int(n), nil
+}
+
+// guidConflicts pairs each article of the source feed (src_id) with the
+// destination feed's article sharing its GUID (dst_id). Its placeholders
+// take the source and destination feed IDs, in that order.
+const guidConflicts = `SELECT a1.id AS src_id, a2.id AS dst_id
+	FROM articles a1 JOIN articles a2 ON a2.guid = a1.guid
+	WHERE a1.feed_id = ? AND a2.feed_id = ?`
+
+// reassignFeedArticles implements ReassignFeedArticles for both stores.
+func reassignFeedArticles(db *tracedDB, fromFeedID, toFeedID int64) error {
+	if fromFeedID == toFeedID {
+		return fmt.Errorf("reassign feed articles: source and destination are both feed %d", fromFeedID)
+	}
+	return db.inTx(func(tx *tracedDB) error {
+		// Fold the duplicate's flags into read_state rows the survivor
+		// already has; rows it lacks move over below.
+		_, err := tx.Exec(`
+			UPDATE read_state SET
+				read = read OR EXISTS (
+					SELECT 1 FROM read_state dup JOIN (`+guidConflicts+`) m ON m.src_id = dup.article_id
+					WHERE m.dst_id = read_state.article_id AND dup.user_id = read_state.user_id AND dup.read = TRUE),
+				starred = starred OR EXISTS (
+					SELECT 1 FROM read_state dup JOIN (`+guidConflicts+`) m ON m.src_id = dup.article_id
+					WHERE m.dst_id = read_state.article_id AND dup.user_id = read_state.user_id AND dup.starred = TRUE)
+			WHERE article_id IN (SELECT dst_id FROM (`+guidConflicts+`) m)`,
+			fromFeedID, toFeedID, fromFeedID, toFeedID, fromFeedID, toFeedID)
+		if err != nil {
+			return fmt.Errorf("merge read state: %w", err)
+		}
+
+		for _, t := range articleStateTables {
+			match := ""
+			for _, k := range t.keys {
+				match += " AND kept." + k + " = " + t.table + "." + k
+			}
+			_, err := tx.Exec(`
+				UPDATE `+t.table+` SET article_id = (
+					SELECT m.dst_id FROM (`+guidConflicts+`) m WHERE m.src_id = `+t.table+`.article_id)
+				WHERE article_id IN (SELECT src_id FROM (`+guidConflicts+`) m)
+				  AND NOT EXISTS (
+					SELECT 1 FROM `+t.table+` kept JOIN (`+guidConflicts+`) m ON m.dst_id = kept.article_id
+					WHERE m.src_id = `+t.table+`.article_id`+match+`)`,
+				fromFeedID, toFeedID, fromFeedID, toFeedID, fromFeedID, toFeedID)
+			if err != nil {
+				return fmt.Errorf("move %s: %w", t.table, err)
+			}
+		}
+
+		// Whatever the survivor already had goes with the duplicate.
+		if _, err := tx.Exec(`DELETE FROM articles WHERE id IN (SELECT src_id FROM (`+guidConflicts+`) m)`,
+			fromFeedID, toFeedID); err != nil {
+			return fmt.Errorf("delete duplicate articles: %w", err)
+		}
+		if _, err := tx.Exec("UPDATE articles SET feed_id = ? WHERE feed_id = ?", toFeedID, fromFeedID); err != nil {
+			return fmt.Errorf("move articles: %w", err)
+		}
+		return nil
+	})
+}
+
+// SubscribeUserToFeed subscribes a user to a feed, cancelling any pending
+// orphan deletion of it. It returns ErrPrivateFeed if another user fetches
+// the feed with custom headers.
 func (s *SQLiteStore) SubscribeUserToFeed(userID, feedID int64) error {
+	if err := checkPrivateFeed(s.db, userID, feedID); err != nil {
+		return err
+	}
 	_, err := s.db.Exec(
 		"INSERT OR IGNORE INTO user_feeds (user_id, feed_id) VALUES (?, ?)",
 		userID, feedID,
@@ -1478,15 +3273,18 @@ func (s *SQLiteStore) SubscribeUserToFeed(userID, feedID int64) error {
 	if err != nil {
 		return fmt.Errorf("failed to subscribe user to feed: %w", err)
 	}
+	if _, err := s.db.Exec("UPDATE feeds SET delete_after = NULL WHERE id = ? AND delete_after IS NOT NULL", feedID); err != nil {
+		return fmt.Errorf("failed to cancel feed deletion: %w", err)
+	}
 	return nil
 }
 
 // GetUserFeeds returns all feeds a user is subscribed to.
 func (s *SQLiteStore) GetUserFeeds(userID int64) ([]Feed, error) {
 	rows, err := s.db.Query(`
-		SELECT f.id, f.url, COALESCE(uf.user_title, f.title), f.description, f.site_url, f.last_fetched, f.last_error, f.etag,
+		SELECT f.id, f.url, COALESCE(uf.user_title, f.title), f.description, f.site_url, f.generator, f.language, f.last_fetched, f.last_error, f.etag,
 		       f.last_modified, f.enabled, f.created_at,
-		       f.consecutive_errors, f.next_fetch_at, f.status
+		       f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
 		FROM feeds f
 		JOIN user_feeds uf ON f.id = uf.feed_id
 		WHERE uf.user_id = ? AND f.enabled = 1
@@ -1498,13 +3296,36 @@ func (s *SQLiteStore) GetUserFeeds(userID int64) ([]Feed, error) {
 	return scanFeeds(rows)
 }
 
+// SearchUserFeeds returns the user's feeds whose title or URL contains q,
+// case-insensitively. An empty q returns all of them.
+func (s *SQLiteStore) SearchUserFeeds(userID int64, q string) ([]Feed, error) {
+	if q == "" {
+		return s.GetUserFeeds(userID)
+	}
+	rows, err := s.db.Query(`
+		SELECT f.id, f.url, COALESCE(uf.user_title, f.title), f.description, f.site_url, f.generator, f.language, f.last_fetched, f.last_error, f.etag,
+		       f.last_modified, f.enabled, f.created_at,
+		       f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
+		FROM feeds f
+		JOIN user_feeds uf ON f.id = uf.feed_id
+		WHERE uf.user_id = ? AND f.enabled = 1
+		  AND (instr(lower(COALESCE(uf.user_title, f.title, '')), lower(?)) > 0
+		       OR instr(lower(f.url), lower(?)) > 0)
+		ORDER BY COALESCE(uf.user_title, f.title)`, userID, q, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search user feeds: %w", err)
+	}
+	defer rows.Close()
+	return scanFeeds(rows)
+}
+
 // GetAllSubscribedFeeds returns all active enabled feeds that any user is subscribed
 // to and that are due for fetching.
 func (s *SQLiteStore) GetAllSubscribedFeeds() ([]Feed, error) {
 	rows, err := s.db.Query(`
-		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.last_fetched, f.last_error,
+		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.generator, f.language, f.last_fetched, f.last_error,
 		       f.etag, f.last_modified, f.enabled, f.created_at,
-		       f.consecutive_errors, f.next_fetch_at, f.status
+		       f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
 		FROM feeds f
 		JOIN user_feeds uf ON f.id = uf.feed_id
 		WHERE f.enabled = 1 AND f.status = 'active'
@@ -1521,9 +3342,9 @@ func (s *SQLiteStore) GetAllSubscribedFeeds() ([]Feed, error) {
 // without any scheduling filter. Intended for export operations.
 func (s *SQLiteStore) GetAllActiveSubscribedFeeds() ([]Feed, error) {
 	rows, err := s.db.Query(`
-		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.last_fetched, f.last_error,
+		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.generator, f.language, f.last_fetched, f.last_error,
 		       f.etag, f.last_modified, f.enabled, f.created_at,
-		       f.consecutive_errors, f.next_fetch_at, f.status
+		       f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
 		FROM feeds f
 		JOIN user_feeds uf ON f.id = uf.feed_id
 		WHERE f.enabled = 1
@@ -1535,6 +3356,21 @@ func (s *SQLiteStore) GetAllActiveSubscribedFeeds() ([]Feed, error) {
 	return scanFeeds(rows)
 }
 
+// IsSubscribed reports whether the user subscribes to the feed.
+func (s *SQLiteStore) IsSubscribed(userID, feedID int64) (bool, error) {
+	return isSubscribed(s.db, userID, feedID)
+}
+
+// isSubscribed implements IsSubscribed for both stores.
+func isSubscribed(db *tracedDB, userID, feedID int64) (bool, error) {
+	var n int
+	err := db.QueryRow("SELECT COUNT(*) FROM user_feeds WHERE user_id = ? AND feed_id = ?", userID, feedID).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("check subscription: %w", err)
+	}
+	return n > 0, nil
+}
+
 // GetFeedSubscribers returns all user IDs subscribed to a feed
 func (s *SQLiteStore) GetFeedSubscribers(feedID int64) ([]int64, error) {
 	rows, err := s.db.Query("SELECT user_id FROM user_feeds WHERE feed_id = ?", feedID)
@@ -1566,6 +3402,94 @@ func (s *SQLiteStore) UnsubscribeUserFromFeed(userID, feedID int64) error {
 	return nil
 }
 
+// FeedExists reports whether a feed row exists.
+func (s *SQLiteStore) FeedExists(feedID int64) (bool, error) {
+	var exists bool
+	if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM feeds WHERE id = ?)", feedID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check feed: %w", err)
+	}
+	return exists, nil
+}
+
+// ScheduleFeedDeletion marks a feed for deletion at the given time if it has
+// no subscribers. Returns true if the feed was marked.
+func (s *SQLiteStore) ScheduleFeedDeletion(feedID int64, at time.Time) (bool, error) {
+	res, err := s.db.Exec(
+		"UPDATE feeds SET delete_after = ? WHERE id = ? AND NOT EXISTS (SELECT 1 FROM user_feeds WHERE feed_id = ?)",
+		at.UTC(), feedID, feedID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to schedule feed deletion: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RecordUnsubscribe notes that userID, who must currently be subscribed to
+// feedID, may undo unsubscribing from it until undoBefore. Call it before
+// UnsubscribeUserFromFeed; for a user who isn't subscribed it records
+// nothing. Expired records are pruned.
+func (s *SQLiteStore) RecordUnsubscribe(userID, feedID int64, undoBefore time.Time) error {
+	return s.db.inTx(func(db *tracedDB) error {
+		if _, err := db.Exec("DELETE FROM feed_unsubscribes WHERE julianday(undo_before) <= julianday('now')"); err != nil {
+			return fmt.Errorf("failed to prune unsubscribes: %w", err)
+		}
+		_, err := db.Exec(`
+			INSERT INTO feed_unsubscribes (user_id, feed_id, undo_before)
+			SELECT user_id, feed_id, ? FROM user_feeds WHERE user_id = ? AND feed_id = ?
+			ON CONFLICT(user_id, feed_id) DO UPDATE SET undo_before = excluded.undo_before`,
+			undoBefore.UTC(), userID, feedID)
+		if err != nil {
+			return fmt.Errorf("failed to record unsubscribe: %w", err)
+		}
+		return nil
+	})
+}
+
+// TakeUnsubscribe consumes userID's record of unsubscribing from feedID,
+// reporting whether one existed whose undo window is still open at now.
+func (s *SQLiteStore) TakeUnsubscribe(userID, feedID int64, now time.Time) (bool, error) {
+	res, err := s.db.Exec(
+		"DELETE FROM feed_unsubscribes WHERE user_id = ? AND feed_id = ? AND julianday(undo_before) > julianday(?)",
+		userID, feedID, now.UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to take unsubscribe: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetFeedsDueForDeletion returns the IDs of unsubscribed feeds whose
+// deletion grace period ended at or before now.
+func (s *SQLiteStore) GetFeedsDueForDeletion(now time.Time) ([]int64, error) {
+	rows, err := s.db.Query(`
+		SELECT id FROM feeds
+		WHERE delete_after IS NOT NULL AND julianday(delete_after) <= julianday(?)
+		  AND NOT EXISTS (SELECT 1 FROM user_feeds WHERE feed_id = feeds.id)
+		ORDER BY id`, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feeds due for deletion: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan feed ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // DeleteFeedIfOrphaned deletes a feed only if no users are subscribed to it.
 // Returns true if the feed was deleted.
 //
@@ -1625,6 +3549,73 @@ func (s *SQLiteStore) RenameFeed(feedID int64, title string) error {
 	return nil
 }
 
+// SetFeedMuted mutes or unmutes a user's subscription. A muted feed is still
+// fetched and searchable but its articles stay out of the unread stream.
+func (s *SQLiteStore) SetFeedMuted(userID, feedID int64, muted bool) error {
+	res, err := s.db.Exec("UPDATE user_feeds SET muted = ? WHERE user_id = ? AND feed_id = ?", muted, userID, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed muted: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("not subscribed to feed %d", feedID)
+	}
+	return nil
+}
+
+// SetFeedFolder files a user's subscription under a folder path, with nested
+// folders separated by "/". An empty folder leaves the feed unfiled.
+func (s *SQLiteStore) SetFeedFolder(userID, feedID int64, folder string) error {
+	res, err := s.db.Exec("UPDATE user_feeds SET folder = ? WHERE user_id = ? AND feed_id = ?", folder, userID, feedID)
+	if err != nil {
+		return fmt.Errorf("failed to set feed folder: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("not subscribed to feed %d", feedID)
+	}
+	return nil
+}
+
+// GetFeedsByFolder returns a user's feeds keyed by folder path. Unfiled feeds
+// are under the empty key.
+func (s *SQLiteStore) GetFeedsByFolder(userID int64) (map[string][]Feed, error) {
+	feeds, err := s.GetUserFeeds(userID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query("SELECT feed_id, folder FROM user_feeds WHERE user_id = ? AND folder <> ''", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed folders: %w", err)
+	}
+	defer rows.Close()
+	folders, err := scanFeedFolders(rows)
+	if err != nil {
+		return nil, err
+	}
+	return groupFeedsByFolder(feeds, folders), nil
+}
+
+func scanFeedFolders(rows *sql.Rows) (map[int64]string, error) {
+	folders := make(map[int64]string)
+	for rows.Next() {
+		var feedID int64
+		var folder string
+		if err := rows.Scan(&feedID, &folder); err != nil {
+			return nil, fmt.Errorf("failed to scan feed folder: %w", err)
+		}
+		folders[feedID] = folder
+	}
+	return folders, rows.Err()
+}
+
+func groupFeedsByFolder(feeds []Feed, folders map[int64]string) map[string][]Feed {
+	byFolder := make(map[string][]Feed)
+	for _, f := range feeds {
+		folder := folders[f.ID]
+		byFolder[folder] = append(byFolder[folder], f)
+	}
+	return byFolder
+}
+
 // RenameUserFeed sets a per-user display title for a feed subscription.
 // Passing an empty title clears the override, reverting to the feed's original title.
 func (s *SQLiteStore) RenameUserFeed(userID, feedID int64, title string) error {
@@ -1637,14 +3628,42 @@ func (s *SQLiteStore) RenameUserFeed(userID, feedID int64, title string) error {
 	if err != nil {
 		return fmt.Errorf("failed to rename user feed: %w", err)
 	}
-	return nil
+	return nil
+}
+
+// GetUserFeedTitle returns the feed's title as the user sees it: their own
+// title for the subscription if they set one, else the feed's. Unknown feeds
+// return "".
+func (s *SQLiteStore) GetUserFeedTitle(userID, feedID int64) (string, error) {
+	var title string
+	err := s.db.QueryRow(`
+		SELECT COALESCE(uf.user_title, f.title)
+		FROM feeds f
+		LEFT JOIN user_feeds uf ON uf.feed_id = f.id AND uf.user_id = ?
+		WHERE f.id = ?`, userID, feedID).Scan(&title)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return title, err
+}
+
+// UpdateFeedMetadata stores what a feed says about itself: its site
+// homepage link, generator and language. An empty siteLink keeps the stored
+// one, which favicon discovery depends on; the generator and language are
+// replaced as given.
+func (s *SQLiteStore) UpdateFeedMetadata(feedID int64, siteLink, generator, lang string) error {
+	return updateFeedMetadata(s.db, feedID, siteLink, generator, lang)
 }
 
-// UpdateFeedSiteURL stores the blog homepage URL for a feed.
-func (s *SQLiteStore) UpdateFeedSiteURL(feedID int64, siteURL string) error {
-	_, err := s.db.Exec("UPDATE feeds SET site_url = ? WHERE id = ?", siteURL, feedID)
+// updateFeedMetadata implements UpdateFeedMetadata for both stores.
+func updateFeedMetadata(db *tracedDB, feedID int64, siteLink, generator, lang string) error {
+	_, err := db.Exec(
+		`UPDATE feeds SET site_url = COALESCE(NULLIF(?, ''), site_url), generator = ?, language = ?
+		 WHERE id = ?`,
+		siteLink, generator, lang, feedID,
+	)
 	if err != nil {
-		return fmt.Errorf("update feed site url: %w", err)
+		return fmt.Errorf("update feed metadata: %w", err)
 	}
 	return nil
 }
@@ -1673,18 +3692,23 @@ func (s *SQLiteStore) GetArticle(articleID int64) (*Article, error) {
 	var a Article
 	err := s.db.QueryRow(
 		`SELECT id, feed_id, guid, title, url, content, summary,
-		        author, published_date, fetched_date,
-		        COALESCE(linked_url,''), COALESCE(linked_content,'')
+		        author, published_date, fetched_date, word_count,
+		        COALESCE(linked_url,''), COALESCE(linked_content,''), lang, slug
 		 FROM articles WHERE id = ?`, articleID,
 	).Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-		&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate,
-		&a.LinkedURL, &a.LinkedContent)
+		&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount,
+		&a.LinkedURL, &a.LinkedContent, &a.Lang, &a.Slug)
 	if err != nil {
 		return nil, fmt.Errorf("get article %d: %w", articleID, err)
 	}
 	return &a, nil
 }
 
+// GetArticleBySlug returns the article with the given permalink slug.
+func (s *SQLiteStore) GetArticleBySlug(slug string) (*Article, error) {
+	return getArticleBySlug(s.db, s, slug)
+}
+
 // UpdateArticleLinkedContent stores the outbound link URL and the readability
 // content fetched from it for a link-blog post. The original post content is
 // left unchanged; this data is displayed alongside it in the reading pane.
@@ -1735,19 +3759,25 @@ func (s *SQLiteStore) GetUnsummarizedArticleCount(userID int64) (int, error) {
 
 // GetUnscoredArticlesForUser returns articles from the user's subscribed feeds
 // that have no read_state entry (never been scored by the AI pipeline).
-func (s *SQLiteStore) GetUnscoredArticlesForUser(userID int64, limit int) ([]Article, error) {
+// A positive minAge skips articles published (or, lacking a date, fetched)
+// less than minAge ago; they are returned by a later call.
+func (s *SQLiteStore) GetUnscoredArticlesForUser(userID int64, limit int, minAge time.Duration) ([]Article, error) {
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		LEFT JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
 		WHERE uf.user_id = ? AND (rs.article_id IS NULL OR rs.ai_scored = 0)
-		  AND COALESCE(rs.ai_retries, 0) < 3
-		ORDER BY a.published_date DESC
-		LIMIT ?
-	`
-	rows, err := s.db.Query(query, userID, userID, limit)
+		  AND COALESCE(rs.ai_retries, 0) < 3`
+	args := []any{userID, userID}
+	if minAge > 0 {
+		query += ` AND datetime(COALESCE(a.published_date, a.fetched_date)) <= datetime('now', ?)`
+		args = append(args, fmt.Sprintf("-%d seconds", int64(minAge/time.Second)))
+	}
+	query += ` ORDER BY a.published_date DESC LIMIT ?`
+	args = append(args, limit)
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get unscored articles for user: %w", err)
 	}
@@ -1757,7 +3787,7 @@ func (s *SQLiteStore) GetUnscoredArticlesForUser(userID int64, limit int) ([]Art
 	for rows.Next() {
 		var a Article
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
 			return nil, fmt.Errorf("scan article: %w", err)
 		}
 		articles = append(articles, a)
@@ -1765,27 +3795,67 @@ func (s *SQLiteStore) GetUnscoredArticlesForUser(userID int64, limit int) ([]Art
 	return articles, rows.Err()
 }
 
-// GetUnreadArticlesForUser returns unread articles from feeds the user subscribes to
-func (s *SQLiteStore) GetUnreadArticlesForUser(userID int64, limit, offset int, filterThreshold *int) ([]Article, error) {
+// Orderings accepted by GetUnreadArticlesOrdered.
+const (
+	OrderNewest = "newest"
+	OrderOldest = "oldest"
+	OrderScore  = "score" // decayed interest score, as in GetArticlesByInterestScore
+)
+
+// unreadOrderClause returns the ORDER BY clause for an unread-article order.
+// Dates sort by the article's effective date, its published date or else
+// when it was fetched, with the ID breaking ties. Sorting on published_date
+// alone would put undated articles last in SQLite but first in Postgres.
+func unreadOrderClause(order string) (string, error) {
+	switch order {
+	case OrderNewest, "":
+		return "ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC", nil
+	case OrderOldest:
+		return "ORDER BY COALESCE(a.published_date, a.fetched_date) ASC, a.id ASC", nil
+	case OrderScore:
+		return `ORDER BY COALESCE(rs.interest_score, 0) * (1.0 / (1.0 + MAX(0, julianday('now') - julianday(COALESCE(a.published_date, a.fetched_date))) * 0.1)) DESC,
+			COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC`, nil
+	}
+	return "", fmt.Errorf("unknown article order %q", order)
+}
+
+// GetUnreadArticlesForUser returns unread articles from feeds the user subscribes to,
+// newest first.
+// A non-empty languages list restricts results to those languages plus
+// articles whose language is unknown.
+func (s *SQLiteStore) GetUnreadArticlesForUser(userID int64, limit, offset int, filterThreshold *int, languages []string) ([]Article, error) {
+	return s.GetUnreadArticlesOrdered(userID, limit, offset, filterThreshold, languages, OrderNewest)
+}
+
+// GetUnreadArticlesOrdered is GetUnreadArticlesForUser with a choice of
+// order: OrderNewest, OrderOldest or OrderScore.
+func (s *SQLiteStore) GetUnreadArticlesOrdered(userID int64, limit, offset int, filterThreshold *int, languages []string, order string) ([]Article, error) {
+	orderSQL, err := unreadOrderClause(order)
+	if err != nil {
+		return nil, err
+	}
 	filterSQL, filterArgs := filterScoreClause(userID, filterThreshold)
+	langSQL, langArgs := languageClause(languages)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		LEFT JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
-		WHERE uf.user_id = ? AND (rs.article_id IS NULL OR rs.read = 0)
+		WHERE uf.user_id = ? AND uf.muted = 0 AND (rs.article_id IS NULL OR rs.read = 0)
 		AND NOT EXISTS (
 			SELECT 1 FROM article_group_members agm
 			JOIN article_groups ag ON agm.group_id = ag.id
 			WHERE agm.article_id = a.id AND ag.user_id = ?
 		)
 		` + filterSQL + `
-		ORDER BY a.published_date DESC
+		` + langSQL + `
+		` + orderSQL + `
 		LIMIT ? OFFSET ?
 	`
 	args := []interface{}{userID, userID, userID}
 	args = append(args, filterArgs...)
+	args = append(args, langArgs...)
 	args = append(args, limit, offset)
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
@@ -1797,7 +3867,7 @@ func (s *SQLiteStore) GetUnreadArticlesForUser(userID int64, limit, offset int,
 	for rows.Next() {
 		var a Article
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
 			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 		articles = append(articles, a)
@@ -1805,12 +3875,13 @@ func (s *SQLiteStore) GetUnreadArticlesForUser(userID int64, limit, offset int,
 	return articles, rows.Err()
 }
 
-// GetUnreadArticlesByFeed returns unread articles for a user filtered to a specific feed.
+// GetUnreadArticlesByFeed returns unread articles for a user filtered to a
+// specific feed. Muted feeds are included; browsing a feed is explicit.
 func (s *SQLiteStore) GetUnreadArticlesByFeed(userID, feedID int64, limit, offset int, filterThreshold *int) ([]Article, error) {
 	filterSQL, filterArgs := filterScoreClause(userID, filterThreshold)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		LEFT JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
@@ -1821,7 +3892,7 @@ func (s *SQLiteStore) GetUnreadArticlesByFeed(userID, feedID int64, limit, offse
 			WHERE agm.article_id = a.id AND ag.user_id = ?
 		)
 		` + filterSQL + `
-		ORDER BY a.published_date DESC
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
 		LIMIT ? OFFSET ?
 	`
 	args := []interface{}{userID, userID, feedID, userID}
@@ -1837,7 +3908,7 @@ func (s *SQLiteStore) GetUnreadArticlesByFeed(userID, feedID int64, limit, offse
 	for rows.Next() {
 		var a Article
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
 			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 		articles = append(articles, a)
@@ -1924,7 +3995,7 @@ func (s *SQLiteStore) GetStarredArticles(userID int64, limit, offset int, filter
 	filterSQL, filterArgs := filterScoreClause(userID, filterThreshold)
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
@@ -1946,7 +4017,7 @@ func (s *SQLiteStore) GetStarredArticles(userID int64, limit, offset int, filter
 	for rows.Next() {
 		var a Article
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
 			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
 		articles = append(articles, a)
@@ -1954,22 +4025,205 @@ func (s *SQLiteStore) GetStarredArticles(userID int64, limit, offset int, filter
 	return articles, rows.Err()
 }
 
-// --- Filter scoring helper ---
+// GetArticlesByCategory returns the user's articles carrying the feed-supplied
+// category, newest first. Categories match case-insensitively.
+func (s *SQLiteStore) GetArticlesByCategory(userID int64, category string, limit, offset int, filterThreshold *int) ([]Article, error) {
+	filterSQL, filterArgs := filterScoreClause(userID, filterThreshold)
+	query := `
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN article_categories ac ON ac.article_id = a.id
+		WHERE uf.user_id = ? AND ac.category = ?
+		` + filterSQL + `
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
+		LIMIT ? OFFSET ?
+	`
+	args := []interface{}{userID, category}
+	args = append(args, filterArgs...)
+	args = append(args, limit, offset)
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles by category: %w", err)
+	}
+	defer rows.Close()
+	return scanArticles(rows)
+}
 
-// filterScoreClause returns an SQL fragment and bind args that filter articles
-// by additive filter rule scoring. Returns ("", nil) when threshold is nil
-// (no filtering). The caller's query must alias the articles table as "a".
-func filterScoreClause(userID int64, threshold *int) (string, []interface{}) {
-	if threshold == nil {
-		return "", nil
+// GetReadingQueue returns the user's starred articles that are still unread,
+// oldest first, so stars work as a read-later queue.
+func (s *SQLiteStore) GetReadingQueue(userID int64, limit, offset int, filterThreshold *int) ([]Article, error) {
+	filterSQL, filterArgs := filterScoreClause(userID, filterThreshold)
+	query := `
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+		WHERE uf.user_id = ? AND rs.starred = 1 AND rs.read = 0
+		` + filterSQL + `
+		ORDER BY COALESCE(a.published_date, a.fetched_date) ASC, a.id ASC
+		LIMIT ? OFFSET ?
+	`
+	args := []interface{}{userID, userID}
+	args = append(args, filterArgs...)
+	args = append(args, limit, offset)
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading queue: %w", err)
 	}
-	sql := `AND (
-		NOT EXISTS (SELECT 1 FROM filter_rules WHERE user_id = ?)
-		OR (
-			SELECT COALESCE(SUM(fr.score), 0)
-			FROM filter_rules fr
-			WHERE fr.user_id = ?
-			  AND (fr.feed_id IS NULL OR fr.feed_id = a.feed_id)
+	defer rows.Close()
+
+	var articles []Article
+	for rows.Next() {
+		var a Article
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// GetReadArticles returns the user's read articles from their current
+// subscriptions, most recently read first.
+func (s *SQLiteStore) GetReadArticles(userID int64, limit, offset int) ([]Article, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+		WHERE uf.user_id = ? AND rs.read = 1 AND rs.read_date IS NOT NULL
+		ORDER BY rs.read_date DESC, a.id DESC
+		LIMIT ? OFFSET ?
+	`, userID, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read articles: %w", err)
+	}
+	defer rows.Close()
+	return scanArticles(rows)
+}
+
+// GetArticlesSince returns articles from the user's subscriptions fetched
+// after since, newest first.
+func (s *SQLiteStore) GetArticlesSince(userID int64, since time.Time, limit int) ([]Article, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		WHERE uf.user_id = ? AND julianday(a.fetched_date) > julianday(?)
+		ORDER BY a.fetched_date DESC, a.id DESC
+		LIMIT ?
+	`, userID, since.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles since: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []Article
+	for rows.Next() {
+		var a Article
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// BlockedArticle is an article the security check scored below threshold,
+// with the score and the model's stated reason.
+type BlockedArticle struct {
+	Article
+	SecurityScore  float64
+	SecurityReason string
+}
+
+// GetBlockedArticles returns articles from the user's subscriptions whose
+// security score is below threshold, newest first, read or unread. These
+// skipped summarization and curation, so this is where false positives
+// can be reviewed.
+func (s *SQLiteStore) GetBlockedArticles(userID int64, threshold float64, limit, offset int) ([]BlockedArticle, error) {
+	rows, err := s.db.Query(`
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count,
+		       rs.security_score, COALESCE(rs.security_reason, '')
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = uf.user_id
+		WHERE uf.user_id = ? AND rs.security_score IS NOT NULL AND rs.security_score < ?
+		ORDER BY COALESCE(a.published_date, a.fetched_date) DESC, a.id DESC
+		LIMIT ? OFFSET ?`, userID, threshold, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked articles: %w", err)
+	}
+	return scanBlockedArticles(rows)
+}
+
+func scanBlockedArticles(rows *sql.Rows) ([]BlockedArticle, error) {
+	defer rows.Close()
+	var articles []BlockedArticle
+	for rows.Next() {
+		var b BlockedArticle
+		a := &b.Article
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount,
+			&b.SecurityScore, &b.SecurityReason); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked article: %w", err)
+		}
+		articles = append(articles, b)
+	}
+	return articles, rows.Err()
+}
+
+// GetUngroupedArticles returns unread, scored articles from the user's
+// subscriptions that do not belong to any of the user's groups, highest
+// interest first. These are the singleton stories clustering left behind.
+func (s *SQLiteStore) GetUngroupedArticles(userID int64, limit, offset int) ([]Article, error) {
+	query := `
+		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
+		       a.author, a.published_date, a.fetched_date, a.word_count
+		FROM articles a
+		JOIN user_feeds uf ON a.feed_id = uf.feed_id
+		JOIN read_state rs ON a.id = rs.article_id AND rs.user_id = ?
+		WHERE uf.user_id = ? AND rs.read = 0
+		  AND NOT EXISTS (
+		      SELECT 1 FROM article_group_members agm
+		      JOIN article_groups ag ON agm.group_id = ag.id
+		      WHERE agm.article_id = a.id AND ag.user_id = ?
+		  )
+		ORDER BY COALESCE(rs.interest_score, 0) DESC, a.published_date DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := s.db.Query(query, userID, userID, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ungrouped articles: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []Article
+	for rows.Next() {
+		var a Article
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// --- Filter scoring helper ---
+
+// filterRuleMatch is the predicate tying a filter_rules row "fr" to the
+// article aliased as "a". The tag axis matches feed categories as well as
+// the tags curation suggested for the rule's owner.
+const filterRuleMatch = `(fr.feed_id IS NULL OR fr.feed_id = a.feed_id)
 			  AND (
 				(fr.axis = 'author' AND EXISTS (
 				  SELECT 1 FROM article_authors aa
@@ -1979,10 +4233,65 @@ func filterScoreClause(userID int64, threshold *int) (string, []interface{}) {
 				  SELECT 1 FROM article_categories ac
 				  WHERE ac.article_id = a.id AND ac.category = fr.value
 				))
-			  )
+				OR (fr.axis = 'tag' AND EXISTS (
+				  SELECT 1 FROM ai_tags t
+				  WHERE t.user_id = fr.user_id AND t.article_id = a.id AND t.tag = fr.value
+				))
+				OR (fr.axis = 'domain' AND (
+				  a.domain = fr.value OR a.domain LIKE '%.' || fr.value
+				))
+				OR (fr.axis = 'title' AND instr(lower(a.title), lower(fr.value)) > 0)
+				OR (fr.axis = 'lang' AND a.lang = fr.value)
+			  )`
+
+// filterScoreClause returns an SQL fragment and bind args that filter articles
+// by the user's filter rules. Articles matching a block rule are always
+// excluded. When threshold is non-nil, articles whose additive score from the
+// remaining rules falls below it are excluded as well. The caller's query must
+// alias the articles table as "a".
+func filterScoreClause(userID int64, threshold *int) (string, []interface{}) {
+	sql := `AND NOT EXISTS (
+		SELECT 1 FROM filter_rules fr
+		WHERE fr.user_id = ? AND fr.block = 1
+		  AND ` + filterRuleMatch + `
+	)`
+	if threshold == nil {
+		return sql, []interface{}{userID}
+	}
+	sql += `
+	AND (
+		NOT EXISTS (SELECT 1 FROM filter_rules WHERE user_id = ? AND block = 0)
+		OR (
+			SELECT COALESCE(SUM(fr.score), 0)
+			FROM filter_rules fr
+			WHERE fr.user_id = ? AND fr.block = 0
+			  AND ` + filterRuleMatch + `
 		) >= ?
 	)`
-	return sql, []interface{}{userID, userID, *threshold}
+	return sql, []interface{}{userID, userID, userID, *threshold}
+}
+
+// languageClause returns an SQL fragment and bind args restricting articles
+// aliased as "a" to the given languages. Articles of unknown language always
+// pass. An empty list applies no restriction.
+func languageClause(languages []string) (string, []interface{}) {
+	if len(languages) == 0 {
+		return "", nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(languages)), ", ")
+	args := make([]interface{}, len(languages))
+	for i, l := range languages {
+		args[i] = l
+	}
+	return `AND (a.lang = 'unknown' OR a.lang IN (` + placeholders + `))`, args
+}
+
+// articleLang defaults an undetected article language to "unknown".
+func articleLang(lang string) string {
+	if lang == "" {
+		return "unknown"
+	}
+	return lang
 }
 
 // --- Article metadata methods ---
@@ -2063,6 +4372,73 @@ func (s *SQLiteStore) GetArticleCategories(articleID int64) ([]string, error) {
 	return cats, rows.Err()
 }
 
+// StoreAITags replaces the tags curation suggested for an article on the
+// user's behalf. They are matched by the "tag" filter axis.
+func (s *SQLiteStore) StoreAITags(userID, articleID int64, tags []string) error {
+	if _, err := s.db.Exec("DELETE FROM ai_tags WHERE user_id = ? AND article_id = ?", userID, articleID); err != nil {
+		return fmt.Errorf("clear ai tags: %w", err)
+	}
+	for _, tag := range tags {
+		_, err := s.db.Exec(
+			"INSERT OR IGNORE INTO ai_tags (user_id, article_id, tag) VALUES (?, ?, ?)",
+			userID, articleID, tag,
+		)
+		if err != nil {
+			return fmt.Errorf("store ai tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetAITags returns the curation-suggested tags for an article.
+func (s *SQLiteStore) GetAITags(userID, articleID int64) ([]string, error) {
+	rows, err := s.db.Query(
+		"SELECT tag FROM ai_tags WHERE user_id = ? AND article_id = ? ORDER BY tag",
+		userID, articleID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get ai tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("scan ai tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// SetInterestReason records curation's one-line justification for an
+// article's interest score. The read state row must already exist.
+func (s *SQLiteStore) SetInterestReason(userID, articleID int64, reason string) error {
+	_, err := s.db.Exec(
+		"UPDATE read_state SET interest_reason = ? WHERE user_id = ? AND article_id = ?",
+		reason, userID, articleID,
+	)
+	return err
+}
+
+// GetInterestReason returns curation's justification for an article, or ""
+// when there is none.
+func (s *SQLiteStore) GetInterestReason(userID, articleID int64) (string, error) {
+	var reason sql.NullString
+	err := s.db.QueryRow(
+		"SELECT interest_reason FROM read_state WHERE user_id = ? AND article_id = ?",
+		userID, articleID,
+	).Scan(&reason)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get interest reason: %w", err)
+	}
+	return reason.String, nil
+}
+
 // --- Feed metadata discovery ---
 
 // GetFeedAuthors returns distinct author names across all articles in a feed.
@@ -2118,9 +4494,9 @@ func (s *SQLiteStore) GetFeedCategories(feedID int64) ([]string, error) {
 // AddFilterRule inserts a new filter rule and returns its ID.
 func (s *SQLiteStore) AddFilterRule(rule *FilterRule) (int64, error) {
 	result, err := s.db.Exec(
-		`INSERT INTO filter_rules (user_id, feed_id, axis, value, score)
-		 VALUES (?, ?, ?, ?, ?)`,
-		rule.UserID, rule.FeedID, rule.Axis, rule.Value, rule.Score,
+		`INSERT INTO filter_rules (user_id, feed_id, axis, value, score, block)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		rule.UserID, rule.FeedID, rule.Axis, rule.Value, rule.Score, rule.Block,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("add filter rule: %w", err)
@@ -2135,12 +4511,12 @@ func (s *SQLiteStore) GetFilterRules(userID int64, feedID *int64) ([]FilterRule,
 	var args []interface{}
 
 	if feedID != nil {
-		query = `SELECT id, user_id, feed_id, axis, value, score, created_at
+		query = `SELECT id, user_id, feed_id, axis, value, score, block, created_at
 				 FROM filter_rules WHERE user_id = ? AND (feed_id IS NULL OR feed_id = ?)
 				 ORDER BY axis, value`
 		args = []interface{}{userID, *feedID}
 	} else {
-		query = `SELECT id, user_id, feed_id, axis, value, score, created_at
+		query = `SELECT id, user_id, feed_id, axis, value, score, block, created_at
 				 FROM filter_rules WHERE user_id = ?
 				 ORDER BY axis, value`
 		args = []interface{}{userID}
@@ -2155,7 +4531,7 @@ func (s *SQLiteStore) GetFilterRules(userID int64, feedID *int64) ([]FilterRule,
 	var rules []FilterRule
 	for rows.Next() {
 		var r FilterRule
-		if err := rows.Scan(&r.ID, &r.UserID, &r.FeedID, &r.Axis, &r.Value, &r.Score, &r.CreatedAt); err != nil {
+		if err := rows.Scan(&r.ID, &r.UserID, &r.FeedID, &r.Axis, &r.Value, &r.Score, &r.Block, &r.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan filter rule: %w", err)
 		}
 		rules = append(rules, r)
@@ -2181,6 +4557,41 @@ func (s *SQLiteStore) DeleteFilterRule(ruleID int64) error {
 	return nil
 }
 
+// ImportFilterRules adds a batch of filter rules in one transaction. A rule
+// that duplicates an existing one (same user, feed, axis and value) is
+// skipped rather than failing the batch. Returns how many rules were added.
+func (s *SQLiteStore) ImportFilterRules(rules []FilterRule) (int, error) {
+	return importFilterRules(s.db, rules)
+}
+
+// importFilterRules implements ImportFilterRules for both stores.
+func importFilterRules(db *tracedDB, rules []FilterRule) (int, error) {
+	added := 0
+	err := db.inTx(func(tx *tracedDB) error {
+		for _, rule := range rules {
+			result, err := tx.Exec(
+				`INSERT INTO filter_rules (user_id, feed_id, axis, value, score, block)
+				 VALUES (?, ?, ?, ?, ?, ?)
+				 ON CONFLICT DO NOTHING`,
+				rule.UserID, rule.FeedID, rule.Axis, rule.Value, rule.Score, rule.Block,
+			)
+			if err != nil {
+				return fmt.Errorf("import filter rule %s=%q: %w", rule.Axis, rule.Value, err)
+			}
+			n, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			added += int(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
 // HasFilterRules returns true if the user has any filter rules defined.
 func (s *SQLiteStore) HasFilterRules(userID int64) (bool, error) {
 	var count int
@@ -2191,6 +4602,35 @@ func (s *SQLiteStore) HasFilterRules(userID int64) (bool, error) {
 	return count > 0, nil
 }
 
+// ExplainArticleScore returns the user's filter rules that match an article,
+// using the same predicate as the filtering queries. Block rules sort first,
+// then scoring rules by descending score.
+func (s *SQLiteStore) ExplainArticleScore(userID, articleID int64) ([]FilterMatch, error) {
+	rows, err := s.db.Query(
+		`SELECT fr.id, fr.feed_id, fr.axis, fr.value, fr.score, fr.block
+		 FROM filter_rules fr
+		 JOIN articles a ON a.id = ?
+		 WHERE fr.user_id = ?
+		   AND `+filterRuleMatch+`
+		 ORDER BY fr.block DESC, fr.score DESC, fr.axis, fr.value`,
+		articleID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("explain article score: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []FilterMatch
+	for rows.Next() {
+		var m FilterMatch
+		if err := rows.Scan(&m.RuleID, &m.FeedID, &m.Axis, &m.Value, &m.Score, &m.Block); err != nil {
+			return nil, fmt.Errorf("scan filter match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
 // --- Feed favicons ---
 
 // FeedFavicon holds a cached favicon for a feed.
@@ -2247,17 +4687,26 @@ func (s *SQLiteStore) GetAllFeedFavicons() ([]FeedFavicon, error) {
 	return favicons, rows.Err()
 }
 
+// MarkFaviconChecked records a failed favicon lookup for a feed, so it isn't
+// retried until a day has passed.
+func (s *SQLiteStore) MarkFaviconChecked(feedID int64) error {
+	_, err := s.db.Exec(`UPDATE feeds SET favicon_checked_at = CURRENT_TIMESTAMP WHERE id = ?`, feedID)
+	return err
+}
+
 // GetSubscribedFeedsWithoutFavicons returns subscribed feeds that have no
-// cached favicon, ordered by ID. Used to drive background favicon fetching.
+// cached favicon and no failed lookup in the last day, ordered by ID. Used to
+// drive background favicon fetching.
 func (s *SQLiteStore) GetSubscribedFeedsWithoutFavicons() ([]Feed, error) {
 	const query = `
-		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url,
+		SELECT DISTINCT f.id, f.url, f.title, f.description, f.site_url, f.generator, f.language,
 		       f.last_fetched, f.last_error, f.etag, f.last_modified,
-		       f.enabled, f.created_at, f.consecutive_errors, f.next_fetch_at, f.status
+		       f.enabled, f.created_at, f.consecutive_errors, f.next_fetch_at, f.status, f.body_hash
 		FROM feeds f
 		JOIN user_feeds uf ON f.id = uf.feed_id
 		LEFT JOIN feed_favicons ff ON f.id = ff.feed_id
 		WHERE ff.feed_id IS NULL
+		  AND (f.favicon_checked_at IS NULL OR f.favicon_checked_at < datetime('now', '-1 day'))
 		ORDER BY f.id`
 	rows, err := s.db.Query(query)
 	if err != nil {
@@ -2392,7 +4841,7 @@ func (s *SQLiteStore) MarkArticleImagesCached(articleID int64) error {
 func (s *SQLiteStore) SearchArticlesFTS(userID int64, query string, limit, offset int) ([]Article, error) {
 	rows, err := s.db.Query(`
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		JOIN articles_fts fts ON fts.rowid = a.id
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
@@ -2415,29 +4864,11 @@ func (s *SQLiteStore) StoreArticleEmbedding(articleID int64, embedding []byte, m
 	return err
 }
 
-// GetArticleEmbeddings returns all article embeddings for a user's subscribed feeds,
-// filtered by the specified embedding model.
-func (s *SQLiteStore) GetArticleEmbeddings(userID int64, model string) ([]ArticleEmbeddingRow, error) {
-	rows, err := s.db.Query(`
-		SELECT ae.article_id, ae.embedding
-		FROM article_embeddings ae
-		JOIN articles a ON a.id = ae.article_id
-		JOIN user_feeds uf ON a.feed_id = uf.feed_id
-		WHERE uf.user_id = ? AND ae.embedding_model = ?`,
-		userID, model)
-	if err != nil {
-		return nil, fmt.Errorf("get article embeddings: %w", err)
-	}
-	defer rows.Close()
-	var result []ArticleEmbeddingRow
-	for rows.Next() {
-		var r ArticleEmbeddingRow
-		if err := rows.Scan(&r.ArticleID, &r.Embedding); err != nil {
-			return nil, err
-		}
-		result = append(result, r)
-	}
-	return result, rows.Err()
+// GetArticleEmbeddings returns the article embeddings for a user's subscribed
+// feeds, filtered by the specified embedding model. A non-zero since limits
+// them to articles fetched at or after it.
+func (s *SQLiteStore) GetArticleEmbeddings(userID int64, model string, since time.Time) ([]ArticleEmbeddingRow, error) {
+	return getArticleEmbeddings(s.db, userID, model, since)
 }
 
 // GetArticlesWithoutEmbeddings returns articles that have no embedding for the
@@ -2445,7 +4876,7 @@ func (s *SQLiteStore) GetArticleEmbeddings(userID int64, model string) ([]Articl
 func (s *SQLiteStore) GetArticlesWithoutEmbeddings(model string, limit int) ([]Article, error) {
 	rows, err := s.db.Query(`
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date
+		       a.author, a.published_date, a.fetched_date, a.word_count
 		FROM articles a
 		LEFT JOIN article_embeddings ae ON a.id = ae.article_id AND ae.embedding_model = ?
 		WHERE ae.article_id IS NULL
@@ -2669,7 +5100,7 @@ func (s *SQLiteStore) GetNewsletterStats(userID int64) ([]NewsletterStats, error
 func (s *SQLiteStore) GetNewsletterArticles(userID int64, config *NewsletterConfig, since *time.Time, limit int) ([]Article, []float64, error) {
 	query := `
 		SELECT a.id, a.feed_id, a.guid, a.title, a.url, a.content, a.summary,
-		       a.author, a.published_date, a.fetched_date, rs.interest_score
+		       a.author, a.published_date, a.fetched_date, a.word_count, rs.interest_score
 		FROM articles a
 		JOIN user_feeds uf ON a.feed_id = uf.feed_id
 		JOIN read_state rs ON rs.article_id = a.id AND rs.user_id = uf.user_id
@@ -2736,7 +5167,7 @@ func (s *SQLiteStore) GetNewsletterArticles(userID int64, config *NewsletterConf
 		var a Article
 		var score float64
 		if err := rows.Scan(&a.ID, &a.FeedID, &a.GUID, &a.Title, &a.URL,
-			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &score); err != nil {
+			&a.Content, &a.Summary, &a.Author, &a.PublishedDate, &a.FetchedDate, &a.WordCount, &score); err != nil {
 			return nil, nil, err
 		}
 		articles = append(articles, a)
@@ -2749,8 +5180,25 @@ func (s *SQLiteStore) GetNewsletterArticles(userID int64, config *NewsletterConf
 // A DSN beginning with "postgres://" or "postgresql://" selects PostgreSQL;
 // all other values are treated as a SQLite file path.
 func NewStore(dsn string) (Store, error) {
+	return NewStoreWithOptions(dsn, SQLiteOptions{})
+}
+
+// NewStoreWithOptions is NewStore with explicit SQLite options. For
+// PostgreSQL DSNs only ReadOnly and CreateDefaultUser apply.
+func NewStoreWithOptions(dsn string, opts SQLiteOptions) (Store, error) {
 	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
-		return NewPostgresStore(dsn)
+		s, err := NewPostgresStore(dsn)
+		if err != nil {
+			return nil, err
+		}
+		s.db.readOnly = opts.ReadOnly
+		if opts.CreateDefaultUser && !opts.ReadOnly {
+			if _, err := s.EnsureDefaultUser(); err != nil {
+				s.Close()
+				return nil, err
+			}
+		}
+		return s, nil
 	}
-	return NewSQLiteStore(dsn)
+	return NewSQLiteStoreWithOptions(dsn, opts)
 }
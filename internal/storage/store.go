@@ -1,6 +1,9 @@
 package storage
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 // FeedScoreStats holds AI scoring breakdown for a single feed (storage-internal type).
 type FeedScoreStats struct {
@@ -21,17 +24,32 @@ type ScoreStatsResult struct {
 	Total FeedScoreStats
 }
 
+// ErrReadOnly is returned by write operations on a store opened read-only.
+var ErrReadOnly = errors.New("database is opened read-only")
+
+// ErrPrivateFeed is returned when subscribing to a feed another user fetches
+// with their own credentials.
+var ErrPrivateFeed = errors.New("feed is fetched with another user's credentials")
+
 // Store defines the storage interface for herald's data layer.
 type Store interface {
 	Close() error
+	Ping() error
+	Backup(destPath string) error
+	IntegrityCheck() ([]string, error)
+	Vacuum() error
+	WithTx(fn func(*Tx) error) error
 
 	// Users
 	CreateUser(name string) (int64, error)
+	EnsureDefaultUser() (int64, error)
 	GetUserByName(name string) (*User, error)
 	GetUserByOIDCSub(sub string) (*User, error)
 	CreateUserWithOIDC(name, email, sub string) (*User, error)
 	UpdateUserOIDCEmail(id int64, email string) error
 	ListUsers() ([]User, error)
+	GetUserLastSeen(userID int64) (lastSeen, prevSeen time.Time, err error)
+	SetUserLastSeen(userID int64, lastSeen, prevSeen time.Time) error
 
 	// User prompts
 	GetUserPrompt(userID int64, promptType string) (string, error)
@@ -47,38 +65,76 @@ type Store interface {
 	GetAllUserPreferences(userID int64) (map[string]string, error)
 	DeleteUserPreference(userID int64, key string) error
 
+	// Notification queue
+	EnqueueNotification(userID, articleID int64, interestScore float64) error
+	GetQueuedNotifications(userID int64) ([]QueuedNotification, error)
+	DeleteQueuedNotifications(userID, throughID int64) error
+	PresentArticles(userID int64, minScore, minSecurity float64, limit int, filterThreshold *int) ([]PresentedArticle, error)
+
 	// Read state
 	UpdateStarred(userID, articleID int64, starred bool) error
+	SetStarredBatch(userID int64, articleIDs []int64, starred bool) error
 	UpdateReadState(userID, articleID int64, read bool, interestScore, securityScore *float64, securityReason *string) error
+	ToggleRead(userID, articleID int64) (bool, error)
 	IncrementAIRetries(userID, articleID int64) error
+	RecordArticleOpen(userID, articleID int64) error
+	GetOpenCounts(userID int64, articleIDs []int64) (map[int64]int, error)
 	ResetScores(userID int64, securityOnly bool, belowScore float64) (int64, error)
+	ClearInterestScores(userID int64, minSecurityScore float64) (int64, error)
+	GetUncuratedUnreadArticles(userID int64) ([]Article, error)
+	UpdateInterestScore(userID, articleID int64, score float64) error
+	GetInterestScores(userID int64, articleIDs []int64) (map[int64]float64, error)
 	GetScoreStats(userID int64) (*ScoreStatsResult, error)
 
 	// Feeds
 	AddFeed(url, title, description string) (int64, error)
+	FindFeedByNormalizedURL(rawURL string) (*Feed, error)
 	GetAllFeeds() ([]Feed, error)
 	UpdateFeedError(feedID int64, errMsg string) error
 	ClearFeedError(feedID int64) error
 	MarkFeedFetched(feedID int64) error
-	UpdateFeedCacheHeaders(feedID int64, etag, lastModified string) error
+	UpdateFeedCacheHeaders(feedID int64, etag, lastModified, bodyHash string) error
 	UpdateFeedLastFetched(feedID int64) error
 	RenameFeed(feedID int64, title string) error
 	RenameUserFeed(userID, feedID int64, title string) error
-	UpdateFeedSiteURL(feedID int64, siteURL string) error
+	GetUserFeedTitle(userID, feedID int64) (string, error)
+	SetFeedMuted(userID, feedID int64, muted bool) error
+	SetFeedFolder(userID, feedID int64, folder string) error
+	GetFeedsByFolder(userID int64) (map[string][]Feed, error)
+	UpdateFeedMetadata(feedID int64, siteLink, generator, lang string) error
+	GetFeedDedupeByURL(feedID int64) (bool, error)
+	SetFeedDedupeByURL(feedID int64, enabled bool) error
+	GetFeedMaxArticles(feedID int64) (int, error)
+	SetFeedMaxArticles(feedID int64, n int) error
+	GetFeedFetchHeaders(feedID int64) (map[string]string, error)
+	SetFeedFetchHeaders(feedID int64, headers map[string]string) error
+	GetFeedContentSource(feedID int64) (string, error)
+	SetFeedContentSource(feedID int64, source string) error
+	GetFeedStripPatterns(feedID int64) ([]string, error)
+	SetFeedStripPatterns(feedID int64, patterns []string) error
+	GetFeedSuggestedInterval(feedID int64) (time.Duration, error)
+	SetFeedSuggestedInterval(feedID int64, interval time.Duration) error
+	TrimFeedArticles(feedID int64, keep int) (int, error)
+	RecordGUIDChurn(feedID int64, churned bool) (int, error)
 
 	// Articles
 	AddArticle(article *Article) (int64, error)
-	FindDuplicateArticle(title string, publishedDate *time.Time) (int64, error)
+	UpsertArticle(article *Article) (int64, bool, error)
+	FindDuplicateArticle(feedID int64, guid, title string, publishedDate *time.Time) (int64, error)
+	FindArticleByURL(feedID int64, url string) (int64, string, error)
 	GetUnreadArticles(limit int) ([]Article, error)
 	GetArticle(articleID int64) (*Article, error)
-	GetArticlesByInterestScore(userID int64, threshold float64, limit, offset int, filterThreshold *int) ([]Article, []float64, error)
-	GetUnreadArticlesForUser(userID int64, limit, offset int, filterThreshold *int) ([]Article, error)
+	GetArticleBySlug(slug string) (*Article, error)
+	GetArticlesByInterestScore(userID int64, threshold float64, limit, offset int, filterThreshold *int) ([]Article, []float64, []float64, error)
+	GetUnreadArticlesForUser(userID int64, limit, offset int, filterThreshold *int, languages []string) ([]Article, error)
+	GetUnreadArticlesOrdered(userID int64, limit, offset int, filterThreshold *int, languages []string, order string) ([]Article, error)
 	GetUnreadArticlesByFeed(userID, feedID int64, limit, offset int, filterThreshold *int) ([]Article, error)
-	GetUnscoredArticlesForUser(userID int64, limit int) ([]Article, error)
+	GetUnscoredArticlesForUser(userID int64, limit int, minAge time.Duration) ([]Article, error)
 	GetUnscoredArticleCount(userID int64) (int, error)
 	GetUnsummarizedArticleCount(userID int64) (int, error)
 	GetArticlesNeedingFullText(limit int) ([]Article, error)
 	UpdateArticleContent(articleID int64, content string) error
+	UpdateArticleWordCount(articleID int64, wordCount int) error
 	UpdateArticleLinkedContent(articleID int64, linkedURL, linkedContent string) error
 	MarkArticleFullTextFetched(articleID int64) error
 
@@ -90,12 +146,24 @@ type Store interface {
 	MarkArticleImagesCached(articleID int64) error
 
 	GetStarredArticles(userID int64, limit, offset int, filterThreshold *int) ([]Article, error)
+	GetReadingQueue(userID int64, limit, offset int, filterThreshold *int) ([]Article, error)
+	GetReadArticles(userID int64, limit, offset int) ([]Article, error)
+	GetArticlesByCategory(userID int64, category string, limit, offset int, filterThreshold *int) ([]Article, error)
+	GetUngroupedArticles(userID int64, limit, offset int) ([]Article, error)
+	GetBlockedArticles(userID int64, threshold float64, limit, offset int) ([]BlockedArticle, error)
+	IsArticleBlocked(userID, articleID int64, threshold float64) (bool, error)
+	OverrideSecurity(userID, articleID int64, threshold, interestScore float64) error
+	GetArticlesSince(userID int64, since time.Time, limit int) ([]Article, error)
 
 	// Article metadata
 	StoreArticleAuthors(articleID int64, authors []ArticleAuthor) error
 	StoreArticleCategories(articleID int64, categories []string) error
 	GetArticleAuthors(articleID int64) ([]ArticleAuthor, error)
 	GetArticleCategories(articleID int64) ([]string, error)
+	StoreAITags(userID, articleID int64, tags []string) error
+	GetAITags(userID, articleID int64) ([]string, error)
+	SetInterestReason(userID, articleID int64, reason string) error
+	GetInterestReason(userID, articleID int64) (string, error)
 
 	// Feed metadata discovery
 	GetFeedAuthors(feedID int64) ([]string, error)
@@ -107,10 +175,17 @@ type Store interface {
 	UpdateFilterRuleScore(ruleID int64, score int) error
 	DeleteFilterRule(ruleID int64) error
 	HasFilterRules(userID int64) (bool, error)
+	ImportFilterRules(rules []FilterRule) (int, error)
+	ExplainArticleScore(userID, articleID int64) ([]FilterMatch, error)
 
 	// Article summaries
 	UpdateArticleAISummary(userID, articleID int64, aiSummary string) error
 	GetArticleSummary(userID, articleID int64) (*ArticleSummary, error)
+	GetSummarizedArticleIDs(userID, feedID int64) ([]int64, error)
+
+	// Article notes
+	SetArticleNote(userID, articleID int64, note string) error
+	GetArticleNote(userID, articleID int64) (*ArticleNote, error)
 
 	// Feed stats
 	GetFeedStats(userID int64) ([]FeedStats, error)
@@ -122,21 +197,26 @@ type Store interface {
 	UpdateGroupSummary(groupID int64, headline, summary string, articleCount int, maxInterestScore *float64) error
 	GetGroupSummary(groupID int64) (*GroupSummary, error)
 	GetUserGroups(userID int64) ([]ArticleGroup, error)
+	GetRecentGroups(userID int64, since time.Time) ([]ArticleGroup, error)
 	GetGroup(groupID int64) (*ArticleGroup, error)
 	FindArticleGroup(articleID, userID int64) (*int64, error)
 
 	// Group virtual feed operations
 	GetUnreadGroupArticles(userID, groupID int64, limit, offset int, filterThreshold *int) ([]Article, error)
 	GetGroupStats(userID int64) ([]GroupStats, error)
+	GetGroupUnreadCounts(userID int64) (map[int64]int, error)
 	SetGroupMuted(groupID int64, muted bool) error
 	IsGroupMuted(groupID int64) (bool, error)
 	DisbandGroup(groupID int64) error
 	UpdateGroupDisplayName(groupID int64, displayName string) error
+	RenameGroup(groupID int64, topic string) error
+	MergeGroups(srcID, dstID int64) error
+	RemoveArticleFromGroup(groupID, articleID int64) error
 
 	// Search
 	SearchArticlesFTS(userID int64, query string, limit, offset int) ([]Article, error)
 	StoreArticleEmbedding(articleID int64, embedding []byte, model string) error
-	GetArticleEmbeddings(userID int64, model string) ([]ArticleEmbeddingRow, error)
+	GetArticleEmbeddings(userID int64, model string, since time.Time) ([]ArticleEmbeddingRow, error)
 	GetArticlesWithoutEmbeddings(model string, limit int) ([]Article, error)
 
 	// Newsletters
@@ -169,6 +249,13 @@ type Store interface {
 	// Admin stats
 	GetDBStats() (DBStats, error)
 
+	// Reading stats
+	GetReadingStats(userID int64, since time.Time) (ReadingStats, error)
+
+	// Poll history
+	RecordPollRun(run PollRun) error
+	GetPollRuns(limit int) ([]PollRun, error)
+
 	// Fever API
 	SetFeverCredential(userID int64, apiKey string) error
 	GetUserByFeverAPIKey(apiKey string) (*User, error)
@@ -179,7 +266,7 @@ type Store interface {
 	GetUnreadArticleIDsForUser(userID int64) ([]int64, error)
 	GetStarredArticleIDsForUser(userID int64) ([]int64, error)
 	MarkFeedArticlesRead(userID, feedID int64, before int64) error
-	MarkGroupArticlesRead(userID, groupID int64, before int64) error
+	MarkGroupArticlesRead(userID, groupID int64, before int64) (int, error)
 	MarkAllArticlesRead(userID int64, before int64) error
 	GetFeedGroupMemberships(userID int64) (map[int64][]int64, error)
 	GetFeverLinks(userID int64) ([]FeverLink, error)
@@ -189,14 +276,23 @@ type Store interface {
 	GetFeedFavicon(feedID int64) (*FeedFavicon, error)
 	GetAllFeedFavicons() ([]FeedFavicon, error)
 	GetSubscribedFeedsWithoutFavicons() ([]Feed, error)
+	MarkFaviconChecked(feedID int64) error
 
 	// Subscriptions
 	SubscribeUserToFeed(userID, feedID int64) error
 	GetUserFeeds(userID int64) ([]Feed, error)
+	SearchUserFeeds(userID int64, q string) ([]Feed, error)
 	GetAllSubscribedFeeds() ([]Feed, error)
 	GetAllActiveSubscribedFeeds() ([]Feed, error)
 	GetFeedSubscribers(feedID int64) ([]int64, error)
+	IsSubscribed(userID, feedID int64) (bool, error)
 	UnsubscribeUserFromFeed(userID, feedID int64) error
 	DeleteFeedIfOrphaned(feedID int64) (bool, error)
+	ReassignFeedArticles(fromFeedID, toFeedID int64) error
+	FeedExists(feedID int64) (bool, error)
+	ScheduleFeedDeletion(feedID int64, at time.Time) (bool, error)
+	RecordUnsubscribe(userID, feedID int64, undoBefore time.Time) error
+	TakeUnsubscribe(userID, feedID int64, now time.Time) (bool, error)
+	GetFeedsDueForDeletion(now time.Time) ([]int64, error)
 	GetAllSubscribingUsers() ([]int64, error)
 }
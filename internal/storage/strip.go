@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Limits on a feed's strip patterns. Go regexps run in linear time, so no
+// pattern can backtrack catastrophically; these bound the remaining cost of
+// compiling and applying them to every article the feed delivers.
+const (
+	MaxStripPatterns      = 20
+	MaxStripPatternLength = 500
+)
+
+// maxCachedStripPatterns bounds stripRegexps. Patterns that were validated
+// but never stored, or were since replaced, would otherwise stay cached for
+// the life of the process.
+const maxCachedStripPatterns = 1000
+
+// stripRegexps caches compiled strip patterns by source text, so each one is
+// compiled once however many articles it is applied to. It is emptied when
+// it reaches maxCachedStripPatterns; patterns still in use are recompiled on
+// their next use.
+var stripRegexps struct {
+	sync.Mutex
+	m map[string]*regexp.Regexp
+}
+
+// compileStripPattern returns the compiled form of pattern, from the cache
+// when it has been seen before.
+func compileStripPattern(pattern string) (*regexp.Regexp, error) {
+	stripRegexps.Lock()
+	re, ok := stripRegexps.m[pattern]
+	stripRegexps.Unlock()
+	if ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	stripRegexps.Lock()
+	if stripRegexps.m == nil || len(stripRegexps.m) >= maxCachedStripPatterns {
+		stripRegexps.m = make(map[string]*regexp.Regexp)
+	}
+	stripRegexps.m[pattern] = re
+	stripRegexps.Unlock()
+	return re, nil
+}
+
+// ValidateStripPatterns checks a feed's strip patterns against the count and
+// length limits and reports the first that isn't a valid regexp. Blank
+// patterns are rejected because they would match everywhere.
+func ValidateStripPatterns(patterns []string) error {
+	if len(patterns) > MaxStripPatterns {
+		return fmt.Errorf("at most %d strip patterns per feed", MaxStripPatterns)
+	}
+	for _, p := range patterns {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("strip pattern must not be blank")
+		}
+		if len(p) > MaxStripPatternLength {
+			return fmt.Errorf("strip pattern longer than %d characters: %.40q...", MaxStripPatternLength, p)
+		}
+		if _, err := compileStripPattern(p); err != nil {
+			return fmt.Errorf("invalid strip pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// StripBoilerplate removes every match of patterns from text and trims the
+// result. Patterns that fail to compile are skipped; SetFeedStripPatterns
+// refuses to store them, so that only happens to hand-edited rows.
+func StripBoilerplate(patterns []string, text string) string {
+	if len(patterns) == 0 {
+		return text
+	}
+	for _, p := range patterns {
+		re, err := compileStripPattern(p)
+		if err != nil {
+			continue
+		}
+		text = re.ReplaceAllString(text, "")
+	}
+	return strings.TrimSpace(text)
+}
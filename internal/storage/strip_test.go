@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStripPatternCacheBounded(t *testing.T) {
+	for i := range maxCachedStripPatterns + 10 {
+		if _, err := compileStripPattern(fmt.Sprintf("pattern-%d", i)); err != nil {
+			t.Fatalf("compileStripPattern: %v", err)
+		}
+	}
+	stripRegexps.Lock()
+	n := len(stripRegexps.m)
+	stripRegexps.Unlock()
+	if n > maxCachedStripPatterns {
+		t.Errorf("cache holds %d patterns, want at most %d", n, maxCachedStripPatterns)
+	}
+
+	if got := StripBoilerplate([]string{`(?s)Read more.*`}, "Body text. Read more at example.com"); got != "Body text." {
+		t.Errorf("StripBoilerplate = %q", got)
+	}
+}
@@ -3,7 +3,8 @@ package storage
 import (
 	"context"
 	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
 	"strconv"
 	"time"
 )
@@ -31,9 +32,34 @@ func reindexParams(query string) string {
 // It shadows Query, QueryRow, Exec, and their Context variants so all call
 // sites on SQLiteStore and PostgresStore are covered without modification.
 // When useRebind is true (PostgreSQL), ? placeholders are converted to $N.
+// When readOnly is true, Exec, ExecContext, queryWrite and queryRowWrite
+// fail with ErrReadOnly; every write in both stores goes through one of
+// them, so RETURNING writes must use queryWrite or queryRowWrite rather than
+// Query or QueryRow. When tx is set (see inTx), statements run on that
+// transaction instead of the pool.
 type tracedDB struct {
 	*sql.DB
+	tx        *sql.Tx
 	useRebind bool
+	readOnly  bool
+}
+
+// sqlExecutor is the statement API shared by *sql.DB and *sql.Tx.
+type sqlExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// executor returns the transaction when t is bound to one, else the pool.
+func (t *tracedDB) executor() sqlExecutor {
+	if t.tx != nil {
+		return t.tx
+	}
+	return t.DB
 }
 
 func (t *tracedDB) prepare(query string) string {
@@ -48,25 +74,34 @@ func (t *tracedDB) logIfSlow(start time.Time, query string, err error) {
 	if d < slowQueryThreshold {
 		return
 	}
+	if len(query) > 500 {
+		query = query[:500]
+	}
 	if err != nil {
-		log.Printf("SLOW QUERY (%dms, err=%v): %.500s", d.Milliseconds(), err, query)
+		slog.Warn("slow query", "event", "slow_query", "duration_ms", d.Milliseconds(), "query", query, "err", err)
 	} else {
-		log.Printf("SLOW QUERY (%dms): %.500s", d.Milliseconds(), query)
+		slog.Warn("slow query", "event", "slow_query", "duration_ms", d.Milliseconds(), "query", query)
 	}
 }
 
 func (t *tracedDB) Exec(query string, args ...any) (sql.Result, error) {
+	if t.readOnly {
+		return nil, ErrReadOnly
+	}
 	query = t.prepare(query)
 	start := time.Now()
-	res, err := t.DB.Exec(query, args...)
+	res, err := t.executor().Exec(query, args...)
 	t.logIfSlow(start, query, err)
 	return res, err
 }
 
 func (t *tracedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if t.readOnly {
+		return nil, ErrReadOnly
+	}
 	query = t.prepare(query)
 	start := time.Now()
-	res, err := t.DB.ExecContext(ctx, query, args...)
+	res, err := t.executor().ExecContext(ctx, query, args...)
 	t.logIfSlow(start, query, err)
 	return res, err
 }
@@ -74,15 +109,44 @@ func (t *tracedDB) ExecContext(ctx context.Context, query string, args ...any) (
 func (t *tracedDB) Query(query string, args ...any) (*sql.Rows, error) {
 	query = t.prepare(query)
 	start := time.Now()
-	rows, err := t.DB.Query(query, args...)
+	rows, err := t.executor().Query(query, args...)
 	t.logIfSlow(start, query, err)
 	return rows, err
 }
 
+// queryWrite is Query for statements that write and return rows, such as
+// DELETE ... RETURNING. Unlike Query it honours readOnly.
+func (t *tracedDB) queryWrite(query string, args ...any) (*sql.Rows, error) {
+	if t.readOnly {
+		return nil, ErrReadOnly
+	}
+	return t.Query(query, args...)
+}
+
+// rowScanner is the Scan half of *sql.Row, returned by queryRowWrite.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// errRow is a rowScanner whose Scan fails with err.
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+// queryRowWrite is QueryRow for statements that write and return a row,
+// such as INSERT ... RETURNING id. Unlike QueryRow it honours readOnly: the
+// returned row's Scan fails with ErrReadOnly.
+func (t *tracedDB) queryRowWrite(query string, args ...any) rowScanner {
+	if t.readOnly {
+		return errRow{ErrReadOnly}
+	}
+	return t.QueryRow(query, args...)
+}
+
 func (t *tracedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
 	query = t.prepare(query)
 	start := time.Now()
-	rows, err := t.DB.QueryContext(ctx, query, args...)
+	rows, err := t.executor().QueryContext(ctx, query, args...)
 	t.logIfSlow(start, query, err)
 	return rows, err
 }
@@ -92,7 +156,7 @@ func (t *tracedDB) QueryContext(ctx context.Context, query string, args ...any)
 func (t *tracedDB) QueryRow(query string, args ...any) *sql.Row {
 	query = t.prepare(query)
 	start := time.Now()
-	row := t.DB.QueryRow(query, args...)
+	row := t.executor().QueryRow(query, args...)
 	t.logIfSlow(start, query, nil)
 	return row
 }
@@ -100,7 +164,32 @@ func (t *tracedDB) QueryRow(query string, args ...any) *sql.Row {
 func (t *tracedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
 	query = t.prepare(query)
 	start := time.Now()
-	row := t.DB.QueryRowContext(ctx, query, args...)
+	row := t.executor().QueryRowContext(ctx, query, args...)
 	t.logIfSlow(start, query, nil)
 	return row
 }
+
+// inTx runs fn against a copy of t bound to a new transaction, committing
+// when fn returns nil and rolling back otherwise. On a copy already bound to
+// a transaction, fn joins it. It fails with ErrReadOnly on a read-only
+// database.
+func (t *tracedDB) inTx(fn func(tx *tracedDB) error) error {
+	if t.readOnly {
+		return ErrReadOnly
+	}
+	if t.tx != nil {
+		return fn(t)
+	}
+	sqlTx, err := t.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	if err := fn(&tracedDB{DB: t.DB, tx: sqlTx, useRebind: t.useRebind}); err != nil {
+		sqlTx.Rollback() //nolint:errcheck
+		return err
+	}
+	if err := sqlTx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package storage
+
+import "time"
+
+// Tx is a transaction-scoped store handed to WithTx callbacks. Its methods
+// behave like their Store counterparts but run on the transaction, so they
+// commit or roll back together.
+type Tx struct {
+	txStore
+}
+
+// txStore lists the Store methods available inside a transaction: the
+// feed and article writes that make up a subscription, the per-user
+// settings a config import writes, plus the lookups they depend on.
+type txStore interface {
+	AddFeed(url, title, description string) (int64, error)
+	UpdateFeedMetadata(feedID int64, siteLink, generator, lang string) error
+	UpdateFeedCacheHeaders(feedID int64, etag, lastModified, bodyHash string) error
+	MarkFeedFetched(feedID int64) error
+	GetFeedDedupeByURL(feedID int64) (bool, error)
+	SetFeedDedupeByURL(feedID int64, enabled bool) error
+	GetFeedMaxArticles(feedID int64) (int, error)
+	SetFeedSuggestedInterval(feedID int64, interval time.Duration) error
+	TrimFeedArticles(feedID int64, keep int) (int, error)
+	RecordGUIDChurn(feedID int64, churned bool) (int, error)
+	SubscribeUserToFeed(userID, feedID int64) error
+	FindFeedByNormalizedURL(rawURL string) (*Feed, error)
+	RenameUserFeed(userID, feedID int64, title string) error
+	SetFeedFolder(userID, feedID int64, folder string) error
+	SetFeedMuted(userID, feedID int64, muted bool) error
+
+	SetUserPreference(userID int64, key, value string) error
+	SetUserPrompt(userID int64, promptType, promptTemplate string, temperature *float64, model *string) error
+	ImportFilterRules(rules []FilterRule) (int, error)
+
+	AddArticle(article *Article) (int64, error)
+	UpsertArticle(article *Article) (int64, bool, error)
+	FindDuplicateArticle(feedID int64, guid, title string, publishedDate *time.Time) (int64, error)
+	FindArticleByURL(feedID int64, url string) (int64, string, error)
+	StoreArticleAuthors(articleID int64, authors []ArticleAuthor) error
+	StoreArticleCategories(articleID int64, categories []string) error
+}
+
+// WithTx runs fn in a transaction, committing if it returns nil and rolling
+// back every write made through the Tx otherwise.
+func (s *SQLiteStore) WithTx(fn func(*Tx) error) error {
+	return s.db.inTx(func(db *tracedDB) error {
+		return fn(&Tx{&SQLiteStore{db: db}})
+	})
+}
+
+func (s *PostgresStore) WithTx(fn func(*Tx) error) error {
+	return s.db.inTx(func(db *tracedDB) error {
+		return fn(&Tx{&PostgresStore{db: db}})
+	})
+}
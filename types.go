@@ -1,19 +1,42 @@
 package herald
 
-import "time"
+import (
+	"log/slog"
+	"time"
+
+	"github.com/matthewjhunter/herald/internal/storage"
+)
 
 // EngineConfig configures the Herald content engine.
 type EngineConfig struct {
-	DBPath            string
-	OllamaBaseURL     string
-	SecurityModel     string
-	CurationModel     string
-	InterestThreshold float64
-	SecurityThreshold float64
-	Keywords          []string // user interest keywords for curation scoring
-	UserID            int64    // primary user ID; DB preferences override CLI flags
-	ReadOnly          bool     // when true, skip AI processor and fetcher creation
-	MaxParallel       int      // max concurrent AI pipeline workers; 0 or 1 = serial
+	DBPath              string
+	OllamaBaseURL       string
+	SecurityModel       string
+	CurationModel       string
+	CurationModels      []string // ordered curation fallback chain; when set, replaces CurationModel
+	InterestThreshold   float64
+	SecurityThreshold   float64
+	Keywords            []string      // user interest keywords for curation scoring
+	AvoidKeywords       []string      // topics that lower the curation score
+	UserID              int64         // primary user ID; DB preferences override CLI flags
+	ReadOnly            bool          // open the database read-only and skip the AI processor; writes fail with ErrReadOnly
+	MaxParallel         int           // max concurrent AI pipeline workers; 0 or 1 = serial
+	ProcessDelay        time.Duration // minimum published age before an article is scored; 0 = immediately
+	BusyTimeout         time.Duration // SQLite lock wait; 0 = default (15s)
+	JournalMode         string        // SQLite journal mode; "" = WAL
+	CreateDefaultUser   bool          // create user 1 ("default") when the database has no users
+	FetchTimeout        time.Duration // per-attempt feed fetch timeout; 0 = default (30s)
+	FetchConcurrency    int           // feeds fetched in parallel; 0 or 1 = serial
+	FetchRetries        int           // extra attempts after a failed feed fetch; 0 = none
+	FetchProxy          string        // proxy URL for feed fetches; "" = HTTP_PROXY/HTTPS_PROXY/NO_PROXY
+	DetectLanguage      bool          // tag newly fetched articles with their detected language
+	GroupTitleThreshold float64       // title word overlap (0-1) for AI-free grouping; 0 = default (0.6), negative disables
+	MinGroupSize        int           // related articles needed to create a group; 0 = default (1, every article)
+	SummaryMinChange    int           // member-count change needed before a poll regenerates a group summary; 0 = any change
+	SecurityMaxContent  int           // article characters sent to the security model; 0 = default (3000), minimum 1000
+	CurationMaxContent  int           // article characters sent to the curation model; 0 = default (3000), capped at SecurityMaxContent
+	PromptDir           string        // directory of <type>.txt prompt templates replacing the embedded defaults; "" = embedded only
+	Logger              *slog.Logger  // engine event log; nil = slog.Default()
 }
 
 // User represents a registered household member.
@@ -34,23 +57,38 @@ type Article struct {
 	Summary       string     `json:"summary"`
 	AISummary     string     `json:"ai_summary,omitempty"`
 	Author        string     `json:"author"`
-	PublishedDate *time.Time `json:"published_date,omitempty"`
-	FetchedDate   time.Time  `json:"fetched_date"`
+	PublishedDate *time.Time `json:"published_date"` // as declared by the feed; null when it gave none
+	FetchedDate   time.Time  `json:"fetched_date"`   // when herald first stored the article
+	EffectiveDate time.Time  `json:"effective_date"` // PublishedDate, else FetchedDate; what article lists sort on
 	LinkedURL     string     `json:"linked_url,omitempty"`
 	LinkedContent string     `json:"linked_content,omitempty"`
+	Lang          string     `json:"lang,omitempty"` // detected ISO 639-1 code or "unknown"; set on single-article reads
+	Note          string     `json:"note,omitempty"` // the requesting user's private note; set by GetArticleForUser
+	// InterestReason and Tags are curation's one-line justification and
+	// suggested topic tags for the requesting user; set by GetArticleForUser.
+	InterestReason string   `json:"interest_reason,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	WordCount      int      `json:"word_count,omitempty"`
+	ReadingTime    int      `json:"reading_minutes,omitempty"` // estimated minutes at readingWPM, rounded up
+	Slug           string   `json:"slug,omitempty"`            // stable permalink key; survives a database rebuild, unlike ID
+	OpenCount      int      `json:"open_count,omitempty"`      // times the user opened the article; set by GetReadHistory
 }
 
 // Feed represents an RSS/Atom feed subscription.
 type Feed struct {
-	ID          int64      `json:"id"`
-	URL         string     `json:"url"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	SiteURL     string     `json:"site_url,omitempty"`
-	LastFetched *time.Time `json:"last_fetched,omitempty"`
-	LastError   *string    `json:"last_error,omitempty"`
-	Enabled     bool       `json:"enabled"`
-	CreatedAt   time.Time  `json:"created_at"`
+	ID                int64      `json:"id"`
+	URL               string     `json:"url"`
+	Title             string     `json:"title"`
+	Description       string     `json:"description"`
+	SiteURL           string     `json:"site_url,omitempty"`
+	Generator         string     `json:"generator,omitempty"` // software that produced the feed
+	Language          string     `json:"language,omitempty"`  // feed-declared language, e.g. "en-us"
+	LastFetched       *time.Time `json:"last_fetched,omitempty"`
+	LastError         *string    `json:"last_error,omitempty"`
+	Enabled           bool       `json:"enabled"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ConsecutiveErrors int        `json:"consecutive_errors"` // fetch failures since the last success
+	Status            string     `json:"status"`             // "active", or "dead" once polling has given up
 }
 
 // SearchResult holds a single search hit with match metadata.
@@ -68,6 +106,25 @@ type ScoredArticle struct {
 	Safe          bool    `json:"safe"`
 }
 
+// AIStatus reports whether AI processing is configured and whether the
+// endpoint serves its models.
+type AIStatus struct {
+	Configured    bool   `json:"configured"`
+	SecurityModel string `json:"security_model,omitempty"`
+	CurationModel string `json:"curation_model,omitempty"`
+	Ready         bool   `json:"ready"`           // both models are available
+	Error         string `json:"error,omitempty"` // why the models aren't ready
+}
+
+// BlockedArticle is an article the security check scored below the
+// security threshold. It was kept out of summarization and curation;
+// SecurityReason is the check's stated rationale, when one was recorded.
+type BlockedArticle struct {
+	Article
+	SecurityScore  float64 `json:"security_score"`
+	SecurityReason string  `json:"security_reason,omitempty"`
+}
+
 // Newsletter represents a user-defined newsletter/digest configuration.
 type Newsletter struct {
 	ID              int64            `json:"id"`
@@ -126,6 +183,7 @@ type ArticleGroup struct {
 	Scores      []float64 `json:"scores,omitempty"`
 	MaxScore    float64   `json:"max_score,omitempty"`
 	Count       int       `json:"count"`
+	Unread      int       `json:"unread"` // unread articles for the owning user; set by GetUserGroups
 }
 
 // GroupStats holds sidebar display data for an article group.
@@ -143,14 +201,39 @@ type FeedStats struct {
 	UnreadArticles       int        `json:"unread_articles"`
 	UnsummarizedArticles int        `json:"unsummarized_articles"`
 	LastPostDate         *time.Time `json:"last_post_date,omitempty"`
+	Muted                bool       `json:"muted,omitempty"`  // kept out of the unread stream and the total unread count
+	Folder               string     `json:"folder,omitempty"` // "/"-separated path, from OPML import
 }
 
+// Orderings for GetUnreadArticlesOrdered.
+const (
+	OrderNewest = storage.OrderNewest
+	OrderOldest = storage.OrderOldest
+	OrderScore  = storage.OrderScore // time-decayed interest score, highest first
+)
+
 // FeedStatsResult contains per-feed stats and an aggregate total.
 type FeedStatsResult struct {
 	Feeds []FeedStats `json:"feeds"`
 	Total FeedStats   `json:"total"`
 }
 
+// ReadingStats summarizes the articles a user read since a point in time.
+// Starred and HighInterest (interest score 8+) count within the read articles.
+type ReadingStats struct {
+	Since        time.Time   `json:"since"`
+	Read         int         `json:"read"`
+	Starred      int         `json:"starred"`
+	HighInterest int         `json:"high_interest"`
+	Daily        []DailyRead `json:"daily"` // one entry per UTC day from Since through today
+}
+
+// DailyRead is the number of articles read on one UTC day.
+type DailyRead struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int    `json:"count"`
+}
+
 // FeedScoreStats holds AI scoring breakdown for a single feed.
 // Security buckets: Pass (>=7), Borderline (>=4,<7), Fail (<4).
 // Interest buckets count only security-passed articles: High (>=8), Medium (>=5,<8), Low (<5).
@@ -211,13 +294,32 @@ type ScoreStatsResult struct {
 	Total FeedScoreStats
 }
 
+// Keyword is a curation interest term with its relative weight.
+type Keyword = storage.Keyword
+
+// Keywords is a weighted interest list. It accepts and, when unweighted,
+// produces a plain JSON string array.
+type Keywords = storage.Keywords
+
+// DefaultNotifyMinScore is the notify_min_score used when a user hasn't set one.
+const DefaultNotifyMinScore = 7.0
+
 // UserPreferences holds all user-configurable preference values.
 type UserPreferences struct {
-	Keywords          []string `json:"keywords"`
+	Keywords          Keywords `json:"keywords"`       // bare terms or {term, weight} objects
+	AvoidKeywords     Keywords `json:"avoid_keywords"` // topics to down-rank; same shapes as keywords
 	InterestThreshold float64  `json:"interest_threshold"`
 	FilterThreshold   int      `json:"filter_threshold"`
 	NotifyWhen        string   `json:"notify_when"` // "present", "always", "queue"
 	NotifyMinScore    float64  `json:"notify_min_score"`
+	NotifyWebhookURL  string   `json:"notify_webhook_url"` // POSTed each safe high-interest article; "" = off
+	AutostarMinScore  float64  `json:"autostar_min_score"` // newly scored articles at or above this are starred; 0 = off
+	SummaryMaxWords   int      `json:"summary_max_words"`  // 0 = no word limit
+	SummaryStyle      string   `json:"summary_style"`      // "", "terse", "detailed", "bullets"
+	Languages         []string `json:"languages"`          // ISO 639-1 allow-list for unread listings; empty = all
+	Timezone          string   `json:"timezone"`           // IANA zone for displayed dates; "" = server local time
+	DateFormat        string   `json:"date_format"`        // "relative" (default) or "absolute"
+	SanitizePolicy    string   `json:"sanitize_policy"`    // article HTML policy: "ugc" (default), "strict" (no images or embeds) or "relaxed"
 }
 
 // FilterRule represents a user-defined scoring rule for article filtering.
@@ -228,6 +330,7 @@ type FilterRule struct {
 	Axis      string    `json:"axis"`
 	Value     string    `json:"value"`
 	Score     int       `json:"score"`
+	Block     bool      `json:"block,omitempty"` // hide matching articles outright; Score must be 0
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -238,6 +341,27 @@ type DiscoveredFeed struct {
 	Type  string `json:"type"` // "rss", "atom", or "json"
 }
 
+// FilterMatch is a filter rule that fired for a particular article.
+type FilterMatch struct {
+	RuleID int64  `json:"rule_id"`
+	FeedID *int64 `json:"feed_id,omitempty"`
+	Axis   string `json:"axis"`
+	Value  string `json:"value"`
+	Score  int    `json:"score"`
+	Block  bool   `json:"block,omitempty"`
+}
+
+// ScoreExplanation breaks down how the user's filter rules score an article.
+type ScoreExplanation struct {
+	ArticleID int64         `json:"article_id"`
+	Title     string        `json:"title"`
+	Matches   []FilterMatch `json:"matches"`
+	Total     int           `json:"total"`     // sum of matching non-block rule scores
+	Blocked   bool          `json:"blocked"`   // a block rule matched
+	Threshold int           `json:"threshold"` // user's filter threshold; 0 = disabled
+	Visible   bool          `json:"visible"`   // whether filtering lets the article through
+}
+
 // FeedMetadata holds discoverable metadata for a feed's articles.
 type FeedMetadata struct {
 	FeedID     int64    `json:"feed_id"`
@@ -272,3 +396,90 @@ type FetchResult struct {
 	HighInterest     int      `json:"high_interest_count"`
 	Errors           []string `json:"errors,omitempty"`
 }
+
+// OPMLImportResult summarizes an OPML import. Feeds the user already had,
+// or that the document lists twice, are skipped rather than failed.
+type OPMLImportResult struct {
+	Added   int      `json:"added"`
+	Skipped int      `json:"skipped"`
+	Failed  []string `json:"failed,omitempty"` // feed URLs that failed validation or could not be added
+}
+
+// UserConfigExportVersion is the UserConfigExport format written by
+// ExportUserConfig and accepted by ImportUserConfig.
+const UserConfigExportVersion = 1
+
+// UserConfigExport is a user's personalization in portable form: their
+// subscriptions, preferences, custom prompts and filter rules, but no
+// articles or read state. Feeds are identified by URL, so it can be applied
+// to another database.
+type UserConfigExport struct {
+	Version       int                    `json:"version"`
+	ExportedAt    time.Time              `json:"exported_at"`
+	Subscriptions []ExportedSubscription `json:"subscriptions"`
+	Preferences   map[string]string      `json:"preferences"` // raw values, as SetPreference takes them
+	Prompts       []ExportedPrompt       `json:"prompts"`
+	FilterRules   []ExportedFilterRule   `json:"filter_rules"`
+}
+
+// ExportedSubscription is one feed subscription in a UserConfigExport.
+type ExportedSubscription struct {
+	URL    string `json:"url"`
+	Title  string `json:"title,omitempty"`  // the user's title for the feed
+	Folder string `json:"folder,omitempty"` // "/"-separated path
+	Muted  bool   `json:"muted,omitempty"`
+}
+
+// ExportedPrompt is a customized prompt in a UserConfigExport.
+type ExportedPrompt struct {
+	Type        string   `json:"type"`
+	Template    string   `json:"template"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	Model       string   `json:"model,omitempty"`
+}
+
+// ExportedFilterRule is a filter rule in a UserConfigExport. Feed-scoped
+// rules name their feed by URL rather than ID.
+type ExportedFilterRule struct {
+	FeedURL string `json:"feed_url,omitempty"`
+	Axis    string `json:"axis"`
+	Value   string `json:"value"`
+	Score   int    `json:"score"`
+	Block   bool   `json:"block,omitempty"`
+}
+
+// UserConfigImportResult summarizes an ImportUserConfig call.
+type UserConfigImportResult struct {
+	FeedsAdded   int `json:"feeds_added"`
+	FeedsSkipped int `json:"feeds_skipped"` // already subscribed
+	Preferences  int `json:"preferences"`
+	Prompts      int `json:"prompts"`
+	RulesAdded   int `json:"rules_added"`
+	RulesSkipped int `json:"rules_skipped"` // duplicates of existing rules
+}
+
+// QueuedNotification is a high-interest article held for the next briefing
+// because the user's notify_when preference is "queue".
+type QueuedNotification struct {
+	Article
+	InterestScore float64   `json:"interest_score"`
+	QueuedAt      time.Time `json:"queued_at"`
+}
+
+// PresentedArticle is a high-interest article surfaced by
+// PresentNotifications because the user's notify_when preference is "present".
+type PresentedArticle struct {
+	Article
+	InterestScore float64 `json:"interest_score"`
+}
+
+// PollRun is one recorded poll cycle from the poll history.
+type PollRun struct {
+	StartedAt       time.Time `json:"started_at"`
+	DurationMS      int64     `json:"duration_ms"`
+	FeedsTotal      int       `json:"feeds_total"`
+	FeedsDownloaded int       `json:"feeds_downloaded"`
+	NewArticles     int       `json:"new_articles"`
+	Processed       int       `json:"processed"`
+	Errored         int       `json:"errored"`
+}